@@ -17,6 +17,8 @@ type ICamera interface {
 	ProjMatrix(*math32.Matrix4)
 	Project(*math32.Vector3) *math32.Vector3
 	Unproject(*math32.Vector3) *math32.Vector3
+	ProjectScreen(pos *math32.Vector3, width, height int) (x, y, depth float32)
+	UnprojectScreen(x, y, depth float32, width, height int) math32.Vector3
 	SetRaycaster(rc *core.Raycaster, x, y float32)
 }
 
@@ -109,6 +111,64 @@ func (cam *Camera) ViewMatrix(m *math32.Matrix4) {
 	*m = cam.viewMatrix
 }
 
+// fitBox returns the center of the specified box, the camera direction and
+// the coordinates of the box corners relative to its center along the
+// camera right, up and backward axes, used to fit the box in the camera view.
+// The current camera direction is kept. A box with zero size is fitted as a
+// box with size 1 around its center. Returns false if the box is empty.
+func (cam *Camera) fitBox(box *math32.Box3) (math32.Vector3, math32.Vector3, [8]math32.Vector3, bool) {
+
+	var center, dir math32.Vector3
+	var corners [8]math32.Vector3
+	if box.Empty() {
+		return center, dir, corners, false
+	}
+	fbox := *box
+	box.Center(&center)
+	var size math32.Vector3
+	if box.Size(&size).Length() < 1e-6 {
+		fbox.ExpandByScalar(0.5)
+	}
+
+	// Calculates the camera axes from its direction and up vector
+	var wpos math32.Vector3
+	cam.WorldPosition(&wpos)
+	dir.SubVectors(&cam.target, &wpos)
+	if dir.Length() < 1e-6 {
+		dir.Set(0, 0, -1)
+	}
+	dir.Normalize()
+	back := dir
+	back.Negate()
+	var right, up math32.Vector3
+	right.CrossVectors(&cam.up, &back)
+	if right.Length() < 1e-6 {
+		right.CrossVectors(&math32.Vector3{0, 0, 1}, &back)
+		if right.Length() < 1e-6 {
+			right.CrossVectors(&math32.Vector3{1, 0, 0}, &back)
+		}
+	}
+	right.Normalize()
+	up.CrossVectors(&back, &right)
+
+	// Transforms the box corners to the camera axes
+	for i := range corners {
+		corner := fbox.Min
+		if i&1 != 0 {
+			corner.X = fbox.Max.X
+		}
+		if i&2 != 0 {
+			corner.Y = fbox.Max.Y
+		}
+		if i&4 != 0 {
+			corner.Z = fbox.Max.Z
+		}
+		corner.Sub(&center)
+		corners[i].Set(corner.Dot(&right), corner.Dot(&up), corner.Dot(&back))
+	}
+	return center, dir, corners, true
+}
+
 // updateQuaternion must be called when the camera position or target
 // is changed to update its quaternion.
 // This is important if the camera has children, such as an audio listener
@@ -121,6 +181,20 @@ func (cam *Camera) updateQuaternion() {
 	cam.SetQuaternionQuat(&q)
 }
 
+// ProjectScreen satisfies the ICamera interface and must
+// be implemented for specific camera types.
+func (cam *Camera) ProjectScreen(pos *math32.Vector3, width, height int) (x, y, depth float32) {
+
+	panic("Not implemented")
+}
+
+// UnprojectScreen satisfies the ICamera interface and must
+// be implemented for specific camera types.
+func (cam *Camera) UnprojectScreen(x, y, depth float32, width, height int) math32.Vector3 {
+
+	panic("Not implemented")
+}
+
 // Project satisfies the ICamera interface and must
 // be implemented for specific camera types.
 func (cam *Camera) Project(v *math32.Vector3) *math32.Vector3 {
@@ -141,3 +215,62 @@ func (cam *Camera) SetRaycaster(rc *core.Raycaster, x, y float32) {
 
 	panic("Not implemented")
 }
+
+// projectToScreen transforms the specified position in world coordinates
+// using the current view and projection matrices of the specified camera
+// and returns its coordinates in pixels in a viewport with the specified
+// size and its depth from 0 at the near plane to 1 at the far plane.
+func projectToScreen(icam ICamera, pos *math32.Vector3, width, height int) (x, y, depth float32) {
+
+	var view, proj, mvp math32.Matrix4
+	icam.ViewMatrix(&view)
+	icam.ProjMatrix(&proj)
+	mvp.MultiplyMatrices(&proj, &view)
+	ndc := *pos
+	ndc.ApplyProjection(&mvp)
+	x = (ndc.X + 1) * 0.5 * float32(width)
+	y = (1 - ndc.Y) * 0.5 * float32(height)
+	depth = (ndc.Z + 1) * 0.5
+	return x, y, depth
+}
+
+// unprojectFromScreen is the inverse of projectToScreen and returns the
+// position in world coordinates of the point with the specified coordinates
+// in pixels in a viewport with the specified size and depth from 0 at the
+// near plane to 1 at the far plane of the specified camera.
+func unprojectFromScreen(icam ICamera, x, y, depth float32, width, height int) math32.Vector3 {
+
+	var view, proj, mvp, inv math32.Matrix4
+	icam.ViewMatrix(&view)
+	icam.ProjMatrix(&proj)
+	mvp.MultiplyMatrices(&proj, &view)
+	inv.GetInverse(&mvp, false)
+	pos := math32.Vector3{
+		2*x/float32(width) - 1,
+		1 - 2*y/float32(height),
+		2*depth - 1,
+	}
+	pos.ApplyProjection(&inv)
+	return pos
+}
+
+// SetRaycasterFromScreen sets the specified raycaster with the ray of the
+// specified camera through the point with the specified coordinates in pixels
+// in a viewport with the specified size, with the origin at the top left corner
+// as the window cursor coordinates. For example, to select the mesh under the
+// cursor when the mouse is clicked:
+//
+//	rc := core.NewRaycaster(&math32.Vector3{}, &math32.Vector3{})
+//	win.Subscribe(window.OnMouseDown, func(evname string, ev interface{}) {
+//		mev := ev.(*window.MouseEvent)
+//		width, height := win.GetSize()
+//		camera.SetRaycasterFromScreen(cam, rc, mev.Xpos, mev.Ypos, width, height)
+//		intersects := rc.IntersectObject(scene, true)
+//		if len(intersects) > 0 {
+//			selected = intersects[0].Object
+//		}
+//	})
+func SetRaycasterFromScreen(icam ICamera, rc *core.Raycaster, x, y float32, width, height int) {
+
+	icam.SetRaycaster(rc, 2*x/float32(width)-1, 1-2*y/float32(height))
+}
@@ -0,0 +1,65 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package camera
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestCameraProjectScreen(t *testing.T) {
+
+	persp := NewPerspective(60, 800.0/600.0, 0.1, 100)
+	persp.SetPosition(3, 2, 10)
+	persp.LookAt(&math32.Vector3{0, 1, 0})
+	ortho := NewOrthographic(-4, 4, 3, -3, 0.1, 100)
+	ortho.SetPosition(-2, 5, 8)
+	ortho.LookAt(&math32.Vector3{0, 0, 0})
+	points := []math32.Vector3{
+		{0, 1, 0},
+		{0, 0, 0},
+		{1, -1, 2},
+		{-2, 2, -3},
+		{0.5, 1.5, 5},
+	}
+	for name, icam := range map[string]ICamera{"perspective": persp, "orthographic": ortho} {
+		for _, p := range points {
+			x, y, depth := icam.ProjectScreen(&p, 800, 600)
+			if depth <= 0 || depth >= 1 {
+				t.Errorf("%s: depth of %v = %v, want between 0 and 1", name, p, depth)
+			}
+			back := icam.UnprojectScreen(x, y, depth, 800, 600)
+			if math32.Abs(back.X-p.X) > 1e-3 || math32.Abs(back.Y-p.Y) > 1e-3 || math32.Abs(back.Z-p.Z) > 1e-3 {
+				t.Errorf("%s: UnprojectScreen(ProjectScreen(%v)) = %v", name, p, back)
+			}
+		}
+	}
+
+	// The target is projected to the center of the viewport
+	target := persp.Target()
+	x, y, _ := persp.ProjectScreen(&target, 800, 600)
+	if !near(x, 400) || !near(y, 300) {
+		t.Errorf("target projected to (%v,%v), want (400,300)", x, y)
+	}
+	ndc := persp.Target()
+	persp.Project(&ndc)
+	if !near(ndc.X, 0) || !near(ndc.Y, 0) {
+		t.Errorf("target projected to NDC %v, want (0,0)", ndc)
+	}
+
+	// The top left corner of the near plane is at the viewport origin
+	cam := NewPerspective(90, 1, 1, 100)
+	cam.LookAt(&math32.Vector3{0, 0, -1})
+	corner := math32.Vector3{-1, 1, -1}
+	x, y, depth := cam.ProjectScreen(&corner, 100, 100)
+	if !near(x, 0) || !near(y, 0) || !near(depth, 0) {
+		t.Errorf("near corner projected to (%v,%v,%v), want (0,0,0)", x, y, depth)
+	}
+	far := cam.UnprojectScreen(100, 100, 1, 100, 100)
+	if want := (math32.Vector3{100, -100, -100}); math32.Abs(far.X-want.X) > 1e-2 || math32.Abs(far.Y-want.Y) > 1e-2 || math32.Abs(far.Z-want.Z) > 1e-2 {
+		t.Errorf("far corner unprojected to %v, want %v", far, want)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package control_test
+
+import (
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/camera/control"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
+	"github.com/g3n/engine/window"
+)
+
+// This example walks through a field of boxes with the W, A, S, D keys and
+// the mouse, sprinting with a shift key. The F key switches between walking
+// at the eyes height and flying, and the Escape key releases the mouse.
+func ExampleNewFlyControl() {
+
+	win, err := window.New("glfw", 800, 600, "Fly control", false)
+	if err != nil {
+		panic(err)
+	}
+	gs, err := gls.New()
+	if err != nil {
+		panic(err)
+	}
+	rend := renderer.NewRenderer(gs)
+	err = rend.AddDefaultShaders()
+	if err != nil {
+		panic(err)
+	}
+
+	scene := core.NewNode()
+	scene.Add(light.NewAmbient(math32.NewColor(1, 1, 1), 0.5))
+	sun := light.NewDirectional(math32.NewColor(1, 1, 1), 1)
+	sun.SetPosition(1, 2, 1)
+	scene.Add(sun)
+	ground := graphic.NewMesh(geometry.NewPlane(100, 100, 1, 1), material.NewStandard(math32.NewColor(0.3, 0.5, 0.3)))
+	ground.SetRotationX(-math32.Pi / 2)
+	scene.Add(ground)
+	box := geometry.NewBox(1, 2, 1, 1, 1, 1)
+	boxMat := material.NewStandard(math32.NewColor(0.8, 0.6, 0.4))
+	for x := -20; x <= 20; x += 4 {
+		for z := -20; z <= 20; z += 4 {
+			mesh := graphic.NewMesh(box, boxMat)
+			mesh.SetPosition(float32(x), 1, float32(z))
+			scene.Add(mesh)
+		}
+	}
+
+	cam := camera.NewPerspective(60, 800.0/600.0, 0.1, 200)
+	cam.SetPosition(0, 1.7, 22)
+	cam.LookAt(&math32.Vector3{Y: 1.7})
+	fc := control.NewFlyControl(cam, win)
+	fc.Flight = false
+	fc.GroundHeight = 1.7
+	win.Subscribe(window.OnKeyDown, func(evname string, ev interface{}) {
+		switch ev.(*window.KeyEvent).Keycode {
+		case window.KeyEscape:
+			fc.SetEnabled(!fc.Enabled())
+		case window.KeyF:
+			fc.Flight = !fc.Flight
+		}
+	})
+
+	for !win.ShouldClose() {
+		win.Dispatch(window.OnFrame, nil)
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
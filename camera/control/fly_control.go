@@ -0,0 +1,253 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package control
+
+import (
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+// FlyControl moves a camera with the keyboard and rotates it with the mouse
+// as in first person games. The W, A, S, D or arrow keys move the camera
+// forward, left, back and right, the E or space keys move it up and the Q or
+// C keys move it down in flight mode, and holding a shift key multiplies the
+// speed by the sprint factor. While the control is enabled the cursor is
+// hidden and captured by the window so the mouse movements rotate the camera
+// without limits. In ground mode the camera moves only in the horizontal plane
+// at the ground height. The movement is integrated on each window OnFrame event
+// using the time elapsed since the previous frame, so the application should
+// dispatch this event once per frame. For example, to walk through a scene
+// and release the mouse for the GUI with the Escape key:
+//
+//	fc := control.NewFlyControl(cam, win)
+//	fc.Flight = false
+//	fc.GroundHeight = 1.7
+//	win.Subscribe(window.OnKeyDown, func(evname string, ev interface{}) {
+//		if ev.(*window.KeyEvent).Keycode == window.KeyEscape {
+//			fc.SetEnabled(!fc.Enabled())
+//		}
+//	})
+//	for !win.ShouldClose() {
+//		win.Dispatch(window.OnFrame, nil)
+//		...
+//	}
+type FlyControl struct {
+	MoveSpeed        float32 // Movement speed in units per second. Default is 5
+	SprintFactor     float32 // Speed factor when a shift key is pressed. Default is 3
+	MouseSensitivity float32 // Rotation in radians per pixel of mouse movement. Default is 0.002
+	MaxPitch         float32 // Maximum angle to look up or down in radians. Default is 89 degrees
+	Flight           bool    // Flight mode if true or ground mode if false. Default is true
+	GroundHeight     float32 // Height of the camera in ground mode. Default is 0
+	// Internal
+	cam        *camera.Camera
+	win        window.IWindow
+	enabled    bool                // Control enabled state
+	yaw        float32             // rotation around the Y axis from the -Z axis
+	pitch      float32             // rotation up or down from the horizontal plane
+	keys       map[window.Key]bool // keys currently pressed
+	cursorInit bool                // cursor event received after enabling
+	lastTime   float64             // time of the last frame in seconds
+	cursorMode int                 // cursor mode before the control was enabled
+	subsEvents int                 // Address of this field is used as events subscription id
+}
+
+// NewFlyControl creates and returns a pointer to a new enabled fly control
+// for the specified camera and window. The initial rotation is taken from
+// the current camera direction.
+func NewFlyControl(icam camera.ICamera, win window.IWindow) *FlyControl {
+
+	fc := new(FlyControl)
+	fc.cam = icam.GetCamera()
+	fc.win = win
+	fc.keys = make(map[window.Key]bool)
+
+	// Set defaults
+	fc.MoveSpeed = 5.0
+	fc.SprintFactor = 3.0
+	fc.MouseSensitivity = 0.002
+	fc.MaxPitch = math32.DegToRad(89)
+	fc.Flight = true
+
+	// Calculates initial angles from the current camera direction
+	var dir math32.Vector3
+	fc.cam.WorldDirection(&dir)
+	fc.pitch = math32.Asin(math32.Clamp(dir.Y, -1, 1))
+	fc.yaw = math32.Atan2(-dir.X, -dir.Z)
+
+	// Subscribe to events
+	fc.win.SubscribeID(window.OnKeyDown, &fc.subsEvents, fc.onKey)
+	fc.win.SubscribeID(window.OnKeyUp, &fc.subsEvents, fc.onKey)
+	fc.win.SubscribeID(window.OnCursor, &fc.subsEvents, fc.onCursor)
+	fc.win.SubscribeID(window.OnFrame, &fc.subsEvents, fc.onFrame)
+	fc.SetEnabled(true)
+	return fc
+}
+
+// Dispose disables this control and unsubscribes it from the window events
+func (fc *FlyControl) Dispose() {
+
+	fc.SetEnabled(false)
+	fc.win.UnsubscribeID(window.OnKeyDown, &fc.subsEvents)
+	fc.win.UnsubscribeID(window.OnKeyUp, &fc.subsEvents)
+	fc.win.UnsubscribeID(window.OnCursor, &fc.subsEvents)
+	fc.win.UnsubscribeID(window.OnFrame, &fc.subsEvents)
+}
+
+// SetEnabled enables or disables this control. When enabled the cursor
+// is captured by the window and when disabled the previous cursor mode
+// is restored, so the control can be disabled while the GUI has the focus.
+func (fc *FlyControl) SetEnabled(state bool) {
+
+	if state == fc.enabled {
+		return
+	}
+	fc.enabled = state
+	fc.keys = make(map[window.Key]bool)
+	fc.cursorInit = false
+	fc.lastTime = 0
+	if state {
+		fc.cursorMode = fc.win.CursorMode()
+		fc.win.SetCursorMode(window.CursorDisabled)
+	} else {
+		fc.win.SetCursorMode(fc.cursorMode)
+	}
+}
+
+// Enabled returns the current enabled state of this control
+func (fc *FlyControl) Enabled() bool {
+
+	return fc.enabled
+}
+
+// Update moves the camera by the keys currently pressed for the specified
+// time in seconds. It is called on each window OnFrame event and can also be
+// called directly by applications which do not dispatch this event.
+func (fc *FlyControl) Update(delta float32) {
+
+	if !fc.enabled {
+		return
+	}
+
+	// Horizontal directions of the camera
+	forward := math32.Vector3{-math32.Sin(fc.yaw), 0, -math32.Cos(fc.yaw)}
+	right := math32.Vector3{math32.Cos(fc.yaw), 0, -math32.Sin(fc.yaw)}
+	if fc.Flight {
+		forward = fc.direction()
+	}
+
+	// Sums the movements of the pressed keys
+	var move math32.Vector3
+	if fc.pressed(window.KeyW, window.KeyUp) {
+		move.Add(&forward)
+	}
+	if fc.pressed(window.KeyS, window.KeyDown) {
+		move.Sub(&forward)
+	}
+	if fc.pressed(window.KeyD, window.KeyRight) {
+		move.Add(&right)
+	}
+	if fc.pressed(window.KeyA, window.KeyLeft) {
+		move.Sub(&right)
+	}
+	if fc.Flight {
+		if fc.pressed(window.KeyE, window.KeySpace) {
+			move.Y++
+		}
+		if fc.pressed(window.KeyQ, window.KeyC) {
+			move.Y--
+		}
+	}
+
+	speed := fc.MoveSpeed
+	if fc.pressed(window.KeyLeftShift, window.KeyRightShift) {
+		speed *= fc.SprintFactor
+	}
+	if move.Length() > 0 {
+		move.Normalize().MultiplyScalar(speed * delta)
+	}
+
+	position := fc.cam.Position()
+	position.Add(&move)
+	if !fc.Flight {
+		position.Y = fc.GroundHeight
+	}
+	fc.cam.SetPositionVec(&position)
+	fc.updateTarget()
+}
+
+// direction returns the current view direction from the yaw and pitch angles
+func (fc *FlyControl) direction() math32.Vector3 {
+
+	cosPitch := math32.Cos(fc.pitch)
+	return math32.Vector3{
+		-math32.Sin(fc.yaw) * cosPitch,
+		math32.Sin(fc.pitch),
+		-math32.Cos(fc.yaw) * cosPitch,
+	}
+}
+
+// updateTarget sets the camera target in front of its position
+// from the yaw and pitch angles
+func (fc *FlyControl) updateTarget() {
+
+	target := fc.cam.Position()
+	dir := fc.direction()
+	target.Add(&dir)
+	fc.cam.LookAt(&target)
+}
+
+// pressed returns if any of the specified keys is pressed
+func (fc *FlyControl) pressed(keys ...window.Key) bool {
+
+	for _, key := range keys {
+		if fc.keys[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// Called when the window frame event is received
+func (fc *FlyControl) onFrame(evname string, ev interface{}) {
+
+	now := fc.win.GetTime()
+	delta := float32(0)
+	if fc.lastTime > 0 {
+		// Limits the time step to avoid jumps after pauses
+		delta = math32.Min(float32(now-fc.lastTime), 0.1)
+	}
+	fc.lastTime = now
+	fc.Update(delta)
+}
+
+// Called when key is pressed or released
+func (fc *FlyControl) onKey(evname string, ev interface{}) {
+
+	if !fc.enabled {
+		return
+	}
+	kev := ev.(*window.KeyEvent)
+	fc.keys[kev.Keycode] = kev.Action != window.Release
+}
+
+// Called when cursor position event is received
+func (fc *FlyControl) onCursor(evname string, ev interface{}) {
+
+	if !fc.enabled {
+		return
+	}
+	// Ignores the first movement after the cursor is captured
+	// which may be a jump to the window center
+	if !fc.cursorInit {
+		fc.cursorInit = true
+		return
+	}
+	cev := ev.(*window.CursorEvent)
+	fc.yaw -= cev.Xdelta * fc.MouseSensitivity
+	fc.pitch -= cev.Ydelta * fc.MouseSensitivity
+	fc.pitch = math32.Clamp(fc.pitch, -fc.MaxPitch, fc.MaxPitch)
+	fc.updateTarget()
+}
@@ -12,12 +12,34 @@ import (
 	"math"
 )
 
+// OrbitControl rotates, zooms and pans a camera around a target point
+// using the mouse and the keyboard events of a window.
+// Dragging with the left mouse button rotates the camera around the target,
+// dragging with the middle button or scrolling the wheel zooms and dragging
+// with the right button pans the camera and the target.
+// When damping is enabled the movements are smoothed and continue with
+// inertia after the mouse is released, at the same speed for any frame rate,
+// which requires the application to dispatch the window OnFrame event once
+// per frame. For example:
+//
+//	oc := control.NewOrbitControl(cam, win)
+//	oc.SetTarget(&math32.Vector3{0, 1, 0})
+//	oc.EnableDamping = true
+//	oc.MaxPolarAngle = math32.Pi / 2
+//	oc.MaxDistance = 50
+//	...
+//	for !win.ShouldClose() {
+//		win.Dispatch(window.OnFrame, nil)
+//		...
+//	}
 type OrbitControl struct {
 	Enabled         bool    // Control enabled state
 	EnableRotate    bool    // Rotate enabled state
 	EnableZoom      bool    // Zoom enabled state
 	EnablePan       bool    // Pan enabled state
 	EnableKeys      bool    // Enable keys state
+	EnableDamping   bool    // Enable inertial damping of rotation, zoom and pan. Default is false
+	DampingFactor   float32 // Fraction of the remaining movement applied each 1/60 second when damping. Default is 0.1
 	ZoomSpeed       float32 // Zoom speed factor. Default is 0.1
 	RotateSpeed     float32 // Rotate speed factor. Default is 1.0
 	MinDistance     float32 // Minimum distance from target. Default is 0.01
@@ -49,8 +71,9 @@ type OrbitControl struct {
 	zoomStart   float32
 	zoomEnd     float32
 	zoomDelta   float32
-	subsEvents  int // Address of this field is used as events subscription id
-	subsPos     int // Address of this field is used as cursor pos events subscription id
+	lastTime    float64 // time of the last frame in seconds
+	subsEvents  int     // Address of this field is used as events subscription id
+	subsPos     int     // Address of this field is used as cursor pos events subscription id
 }
 
 const (
@@ -96,6 +119,7 @@ func NewOrbitControl(icam camera.ICamera, win window.IWindow) *OrbitControl {
 	oc.MaxAzimuthAngle = float32(math.Inf(1))
 	oc.KeyPanSpeed = 5.0
 	oc.KeyRotateSpeed = 0.02
+	oc.DampingFactor = 0.1
 
 	// Saves initial camera parameters
 	oc.position0 = oc.cam.Position()
@@ -106,9 +130,11 @@ func NewOrbitControl(icam camera.ICamera, win window.IWindow) *OrbitControl {
 	oc.win.SubscribeID(window.OnMouseDown, &oc.subsEvents, oc.onMouse)
 	oc.win.SubscribeID(window.OnScroll, &oc.subsEvents, oc.onScroll)
 	oc.win.SubscribeID(window.OnKeyDown, &oc.subsEvents, oc.onKey)
+	oc.win.SubscribeID(window.OnFrame, &oc.subsEvents, oc.onFrame)
 	return oc
 }
 
+// Dispose unsubscribes this control from the window events
 func (oc *OrbitControl) Dispose() {
 
 	// Unsubscribe to event handlers
@@ -116,48 +142,108 @@ func (oc *OrbitControl) Dispose() {
 	oc.win.UnsubscribeID(window.OnMouseDown, &oc.subsEvents)
 	oc.win.UnsubscribeID(window.OnScroll, &oc.subsEvents)
 	oc.win.UnsubscribeID(window.OnKeyDown, &oc.subsEvents)
+	oc.win.UnsubscribeID(window.OnFrame, &oc.subsEvents)
 	oc.win.UnsubscribeID(window.OnCursor, &oc.subsPos)
 }
 
-// Reset to initial camera position
+// Reset to initial camera position and target
+// stopping any movement in progress
 func (oc *OrbitControl) Reset() {
 
 	oc.state = stateNone
+	oc.thetaDelta = 0
+	oc.phiDelta = 0
+	oc.panOffset.Set(0, 0)
+	oc.zoomDelta = 0
+	oc.win.UnsubscribeID(window.OnCursor, &oc.subsPos)
 	oc.cam.SetPositionVec(&oc.position0)
 	oc.cam.LookAt(&oc.target0)
 }
 
+// SetTarget sets the point in world coordinates which the camera
+// orbits around and looks at, keeping the camera position
+func (oc *OrbitControl) SetTarget(target *math32.Vector3) {
+
+	oc.cam.LookAt(target)
+}
+
+// Target returns the current point which the camera orbits around
+func (oc *OrbitControl) Target() math32.Vector3 {
+
+	return oc.cam.Target()
+}
+
+// Update applies the part of the pending rotation, zoom and pan movements
+// corresponding to the specified time in seconds when damping is enabled.
+// It is called on each window OnFrame event with the time elapsed since the
+// previous frame and can also be called directly by applications which do
+// not dispatch this event.
+func (oc *OrbitControl) Update(delta float32) {
+
+	const EPS = 1e-4
+
+	if !oc.EnableDamping {
+		return
+	}
+	fraction := oc.dampingFraction(delta)
+	if math32.Abs(oc.thetaDelta) > EPS || math32.Abs(oc.phiDelta) > EPS {
+		oc.updateRotate(fraction)
+	} else {
+		oc.thetaDelta = 0
+		oc.phiDelta = 0
+	}
+	if math32.Abs(oc.zoomDelta) > EPS {
+		oc.updateZoom(fraction)
+	} else {
+		oc.zoomDelta = 0
+	}
+	if math32.Abs(oc.panOffset.X) > EPS || math32.Abs(oc.panOffset.Y) > EPS {
+		oc.updatePan(fraction)
+	} else {
+		oc.panOffset.Set(0, 0)
+	}
+}
+
 // Pan the camera and target by the specified deltas
 func (oc *OrbitControl) Pan(deltaX, deltaY float32) {
 
 	width, height := oc.win.GetSize()
 	oc.pan(deltaX, deltaY, width, height)
-	oc.updatePan()
+	if !oc.EnableDamping {
+		oc.updatePan(1)
+	}
 }
 
-// Zoom in or out
+// Zoom in or out by the specified delta, which is
+// applied gradually if damping is enabled
 func (oc *OrbitControl) Zoom(delta float32) {
 
-	oc.zoomDelta = delta
-	oc.updateZoom()
+	oc.zoomDelta += delta
+	if !oc.EnableDamping {
+		oc.updateZoom(1)
+	}
 }
 
 // Rotate camera left by specified angle
 func (oc *OrbitControl) RotateLeft(angle float32) {
 
 	oc.thetaDelta -= angle
-	oc.updateRotate()
+	if !oc.EnableDamping {
+		oc.updateRotate(1)
+	}
 }
 
 // Rotate camera up by specified angle
 func (oc *OrbitControl) RotateUp(angle float32) {
 
 	oc.phiDelta -= angle
-	oc.updateRotate()
+	if !oc.EnableDamping {
+		oc.updateRotate(1)
+	}
 }
 
-// Updates camera rotation from tethaDelta and phiDelta
-func (oc *OrbitControl) updateRotate() {
+// Updates camera rotation from the specified fraction of tethaDelta and phiDelta
+func (oc *OrbitControl) updateRotate(fraction float32) {
 
 	const EPS = 0.01
 
@@ -182,9 +268,10 @@ func (oc *OrbitControl) updateRotate() {
 	theta := math32.Atan2(vdir.X, vdir.Z)
 	phi := math32.Acos(vdir.Y / radius)
 
-	// Add deltas to the angles
-	theta += oc.thetaDelta
-	phi += oc.phiDelta
+	// Add deltas to the angles.
+	// When damping only a fraction of the deltas is applied in each frame
+	theta += oc.thetaDelta * fraction
+	phi += oc.phiDelta * fraction
 
 	// Restrict phi (elevation) to be between desired limits
 	phi = math32.Max(oc.MinPolarAngle, math32.Min(oc.MaxPolarAngle, phi))
@@ -204,9 +291,9 @@ func (oc *OrbitControl) updateRotate() {
 	position.Add(&vdir)
 	oc.cam.SetPositionVec(&position)
 
-	// Reset deltas
-	oc.thetaDelta = 0
-	oc.phiDelta = 0
+	// Reduces deltas by the applied fraction
+	oc.thetaDelta *= 1 - fraction
+	oc.phiDelta *= 1 - fraction
 }
 
 // Updates camera rotation from tethaDelta and phiDelta
@@ -255,8 +342,8 @@ func (oc *OrbitControl) updateRotate2() {
 	oc.phiDelta = 0
 }
 
-// Updates camera pan from panOffset
-func (oc *OrbitControl) updatePan() {
+// Updates camera pan from the specified fraction of panOffset
+func (oc *OrbitControl) updatePan(fraction float32) {
 
 	// Get camera parameters
 	position := oc.cam.Position()
@@ -278,9 +365,10 @@ func (oc *OrbitControl) updatePan() {
 	vpany.CrossVectors(&vdir, &vpanx)
 	vpany.Normalize()
 
-	// Adds pan offsets
-	vpanx.MultiplyScalar(oc.panOffset.X)
-	vpany.MultiplyScalar(oc.panOffset.Y)
+	// Adds pan offsets.
+	// When damping only a fraction of the offsets is applied in each frame
+	vpanx.MultiplyScalar(oc.panOffset.X * fraction)
+	vpany.MultiplyScalar(oc.panOffset.Y * fraction)
 	var vpan math32.Vector3
 	vpan.AddVectors(&vpanx, &vpany)
 
@@ -292,18 +380,29 @@ func (oc *OrbitControl) updatePan() {
 	oc.cam.SetPositionVec(&position)
 	oc.cam.LookAt(&target)
 
-	// Reset deltas
-	oc.panOffset.Set(0, 0)
+	// Reduces offsets by the applied fraction
+	oc.panOffset.MultiplyScalar(1 - fraction)
 }
 
-// Updates camera zoom from zoomDelta
-func (oc *OrbitControl) updateZoom() {
+// Returns the fraction of the pending movements to apply for the specified
+// time in seconds, so the movements are damped at the same speed for any
+// frame rate
+func (oc *OrbitControl) dampingFraction(delta float32) float32 {
+
+	factor := math32.Clamp(oc.DampingFactor, 0.01, 1)
+	return 1 - math32.Pow(1-factor, delta*60)
+}
+
+// Updates camera zoom from the specified fraction of zoomDelta
+func (oc *OrbitControl) updateZoom(fraction float32) {
+
+	zoomDelta := oc.zoomDelta * fraction
+	// Reduces delta by the applied fraction
+	oc.zoomDelta -= zoomDelta
 
 	if oc.camOrtho != nil {
-		zoom := oc.camOrtho.Zoom() - 0.01*oc.zoomDelta
+		zoom := oc.camOrtho.Zoom() - 0.01*zoomDelta
 		oc.camOrtho.SetZoom(zoom)
-		// Reset delta
-		oc.zoomDelta = 0
 		return
 	}
 
@@ -316,16 +415,29 @@ func (oc *OrbitControl) updateZoom() {
 	vdir.Sub(&target)
 
 	// Calculates new distance from target and applies limits
-	dist := vdir.Length() * (1.0 + oc.zoomDelta*oc.ZoomSpeed/10.0)
+	dist := vdir.Length() * (1.0 + zoomDelta*oc.ZoomSpeed/10.0)
 	dist = math32.Max(oc.MinDistance, math32.Min(oc.MaxDistance, dist))
 	vdir.SetLength(dist)
 
 	// Adds new distance to target to get new camera position
 	target.Add(&vdir)
 	oc.cam.SetPositionVec(&target)
+}
 
-	// Reset delta
-	oc.zoomDelta = 0
+// Called when the window frame event is received
+func (oc *OrbitControl) onFrame(evname string, ev interface{}) {
+
+	now := oc.win.GetTime()
+	delta := float32(0)
+	if oc.lastTime > 0 {
+		// Limits the time step to avoid jumps after pauses
+		delta = math32.Min(float32(now-oc.lastTime), 0.1)
+	}
+	oc.lastTime = now
+	if !oc.Enabled {
+		return
+	}
+	oc.Update(delta)
 }
 
 // Called when mouse button event is received
@@ -411,9 +523,9 @@ func (oc *OrbitControl) onCursorPos(evname string, ev interface{}) {
 	// Zooming
 	if oc.state == stateZoom {
 		oc.zoomEnd = float32(mev.Ypos)
-		oc.zoomDelta = oc.zoomEnd - oc.zoomStart
+		delta := oc.zoomEnd - oc.zoomStart
 		oc.zoomStart = oc.zoomEnd
-		oc.Zoom(oc.zoomDelta)
+		oc.Zoom(delta)
 	}
 }
 
@@ -0,0 +1,34 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package control
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestOrbitControlDampingFraction(t *testing.T) {
+
+	oc := &OrbitControl{DampingFactor: 0.1}
+	if f := oc.dampingFraction(1.0 / 60); math32.Abs(f-0.1) > 1e-5 {
+		t.Errorf("fraction at 60Hz = %v, want 0.1", f)
+	}
+	if f := oc.dampingFraction(0); f != 0 {
+		t.Errorf("fraction without time = %v, want 0", f)
+	}
+
+	// The movement remaining after one second is the same for any frame rate
+	for _, fps := range []int{30, 60, 144} {
+		remaining := float32(1)
+		for i := 0; i < fps; i++ {
+			remaining *= 1 - oc.dampingFraction(1/float32(fps))
+		}
+		want := math32.Pow(0.9, 60)
+		if math32.Abs(remaining-want) > 1e-4 {
+			t.Errorf("%d fps: remaining = %v, want %v", fps, remaining, want)
+		}
+	}
+}
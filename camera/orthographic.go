@@ -5,6 +5,7 @@
 package camera
 
 import (
+	"github.com/g3n/engine/core"
 	"github.com/g3n/engine/math32"
 )
 
@@ -57,6 +58,49 @@ func (cam *Orthographic) Planes() (left, right, top, bottom, near, far float32)
 	return cam.left, cam.right, cam.top, cam.bottom, cam.near, cam.far
 }
 
+// FitToBox moves the camera along its current direction, points it at
+// the center of the specified box in world coordinates and sets the zoom
+// so the box fills the specified fraction of the viewport height or width,
+// whichever is reached first. A fill ratio of 1 makes the box touch the
+// viewport borders and values less or equal to 0 are considered to be 1.
+// A box with zero size is framed as a box with size 1 around its point.
+// The camera is placed so the box is just beyond the near plane.
+func (cam *Orthographic) FitToBox(box *math32.Box3, fillRatio float32) {
+
+	center, dir, corners, ok := cam.fitBox(box)
+	if !ok {
+		return
+	}
+	if fillRatio <= 0 {
+		fillRatio = 1
+	}
+
+	// Finds the extents of the box in the view and its depth
+	var extX, extY, extZ float32
+	for _, c := range corners {
+		extX = math32.Max(extX, math32.Abs(c.X))
+		extY = math32.Max(extY, math32.Abs(c.Y))
+		extZ = math32.Max(extZ, c.Z)
+	}
+	if extX < 1e-6 && extY < 1e-6 {
+		extX = 0.5
+		extY = 0.5
+	}
+
+	// The zoom divides the planes of the camera
+	zoom := math32.Min(
+		fillRatio*(cam.right-cam.left)/(2*extX),
+		fillRatio*(cam.top-cam.bottom)/(2*extY),
+	)
+	cam.SetZoom(zoom)
+
+	dist := math32.Max(cam.near, 0) + extZ
+	position := dir
+	position.MultiplyScalar(-dist).Add(&center)
+	cam.SetPositionVec(&position)
+	cam.LookAt(&center)
+}
+
 // ProjMatrix satisfies the ICamera interface
 func (cam *Orthographic) ProjMatrix(m *math32.Matrix4) {
 
@@ -66,3 +110,60 @@ func (cam *Orthographic) ProjMatrix(m *math32.Matrix4) {
 	}
 	*m = cam.projMatrix
 }
+
+// ProjectScreen transforms the specified position in world coordinates using the
+// current view and projection matrices of this camera and returns its
+// coordinates in pixels in a viewport with the specified size, with the origin
+// at the top left corner as the window cursor coordinates, and its depth from
+// 0 at the near plane to 1 at the far plane. Unlike Project, which returns
+// normalized device coordinates from -1 to 1, the coordinates are those of
+// the viewport.
+func (cam *Orthographic) ProjectScreen(pos *math32.Vector3, width, height int) (x, y, depth float32) {
+
+	return projectToScreen(cam, pos, width, height)
+}
+
+// UnprojectScreen is the inverse of ProjectScreen and returns the position in world
+// coordinates of the point with the specified coordinates in pixels in a
+// viewport with the specified size and depth from 0 at the near plane to 1
+// at the far plane.
+func (cam *Orthographic) UnprojectScreen(x, y, depth float32, width, height int) math32.Vector3 {
+
+	return unprojectFromScreen(cam, x, y, depth, width, height)
+}
+
+// Project transforms the specified position from world coordinates to this camera projected coordinates.
+func (cam *Orthographic) Project(v *math32.Vector3) *math32.Vector3 {
+
+	var view, proj, matrix math32.Matrix4
+	cam.ViewMatrix(&view)
+	cam.ProjMatrix(&proj)
+	matrix.MultiplyMatrices(&proj, &view)
+	v.ApplyProjection(&matrix)
+	return v
+}
+
+// Unproject transforms the specified position from camera projected coordinates to world coordinates.
+func (cam *Orthographic) Unproject(v *math32.Vector3) *math32.Vector3 {
+
+	var view, proj, matrix math32.Matrix4
+	cam.ViewMatrix(&view)
+	cam.ProjMatrix(&proj)
+	matrix.MultiplyMatrices(&proj, &view)
+	matrix.GetInverse(&matrix, true)
+	v.ApplyProjection(&matrix)
+	return v
+}
+
+// SetRaycaster sets the specified raycaster with the specified coordinates
+// unprojected on the near plane of this camera pointing to the camera direction.
+func (cam *Orthographic) SetRaycaster(rc *core.Raycaster, sx, sy float32) {
+
+	var origin, direction math32.Vector3
+	origin.Set(sx, sy, -1)
+	cam.Unproject(&origin)
+	cam.WorldDirection(&direction)
+	rc.Set(&origin, &direction)
+	// Updates the view matrix of the raycaster
+	cam.ViewMatrix(&rc.ViewMatrix)
+}
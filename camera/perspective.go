@@ -9,16 +9,29 @@ import (
 	"github.com/g3n/engine/math32"
 )
 
+// Perspective is a camera with a perspective projection defined by its
+// vertical field of view, which can also be switched to an orthographic
+// projection, keeping its position and orientation, with SetProjection.
 type Perspective struct {
 	Camera                     // Embedded camera
 	fov         float32        // field of view in degrees
 	aspect      float32        // aspect ratio (width/height)
 	near        float32        // near plane z coordinate
 	far         float32        // far plane z coordinate
+	projection  Projection     // current projection type
 	projChanged bool           // camera projection parameters changed (needs to recalculates projection matrix)
 	projMatrix  math32.Matrix4 // last calculated projection matrix
 }
 
+// Projection is the type of projection used by a perspective camera
+type Projection int
+
+// Projection types
+const (
+	ProjectionPerspective  = Projection(iota) // Perspective projection (default)
+	ProjectionOrthographic                    // Orthographic projection
+)
+
 // NewPerspective creates and returns a pointer to a new perspective camera with the
 // specified parameters.
 func NewPerspective(fov, aspect, near, far float32) *Perspective {
@@ -47,6 +60,30 @@ func (cam *Perspective) SetAspect(aspect float32) {
 	cam.projChanged = true
 }
 
+// SetProjection sets the type of projection of this camera, keeping its
+// position and orientation. In orthographic projection the size of the view
+// volume is the size of the perspective view at the distance from the camera
+// to its target, so the objects at the target distance keep their apparent
+// size when switching and moving the camera towards the target zooms in.
+// For example, to toggle the projection of a CAD viewer with a key:
+//
+//	if cam.Projection() == camera.ProjectionPerspective {
+//		cam.SetProjection(camera.ProjectionOrthographic)
+//	} else {
+//		cam.SetProjection(camera.ProjectionPerspective)
+//	}
+func (cam *Perspective) SetProjection(proj Projection) {
+
+	cam.projection = proj
+	cam.projChanged = true
+}
+
+// Projection returns the current type of projection of this camera
+func (cam *Perspective) Projection() Projection {
+
+	return cam.projection
+}
+
 // Fov returns the current camera FOV (field of view) in degrees
 func (cam *Perspective) Fov() float32 {
 
@@ -71,6 +108,48 @@ func (cam *Perspective) Far() float32 {
 	return cam.far
 }
 
+// FitToBox moves the camera along its current direction and points it
+// at the center of the specified box in world coordinates, so the box
+// fills the specified fraction of the viewport height or width, whichever
+// is reached first, for the current field of view and aspect ratio.
+// A fill ratio of 1 makes the box touch the viewport borders and values
+// less or equal to 0 are considered to be 1. A box with zero size is framed
+// as a box with size 1 around its point. The near and far planes are not
+// changed. For example, to view a loaded model with a margin around it:
+//
+//	var box math32.Box3
+//	box.Set(nil, nil)
+//	... expand the box with the bounding boxes of the model geometries
+//	cam.FitToBox(&box, 0.8)
+func (cam *Perspective) FitToBox(box *math32.Box3, fillRatio float32) {
+
+	center, dir, corners, ok := cam.fitBox(box)
+	if !ok {
+		return
+	}
+	if fillRatio <= 0 {
+		fillRatio = 1
+	}
+
+	// Tangents of the half angles of the view reduced by the fill ratio
+	tanY := math32.Tan(cam.fov*math32.Pi/360) * fillRatio
+	tanX := tanY * cam.aspect
+
+	// Finds the minimum distance from the box center where all the
+	// corners are inside the reduced view and in front of the near plane
+	dist := float32(0)
+	for _, c := range corners {
+		dist = math32.Max(dist, math32.Abs(c.X)/tanX+c.Z)
+		dist = math32.Max(dist, math32.Abs(c.Y)/tanY+c.Z)
+		dist = math32.Max(dist, cam.near+c.Z)
+	}
+
+	position := dir
+	position.MultiplyScalar(-dist).Add(&center)
+	cam.SetPositionVec(&position)
+	cam.LookAt(&center)
+}
+
 // ProjMatrix satisfies the ICamera interface
 func (cam *Perspective) ProjMatrix(m *math32.Matrix4) {
 
@@ -78,6 +157,37 @@ func (cam *Perspective) ProjMatrix(m *math32.Matrix4) {
 	*m = cam.projMatrix
 }
 
+// ProjectScreen transforms the specified position in world coordinates using the
+// current view and projection matrices of this camera and returns its
+// coordinates in pixels in a viewport with the specified size, with the origin
+// at the top left corner as the window cursor coordinates, and its depth from
+// 0 at the near plane to 1 at the far plane. Points with depth outside this
+// range are not visible. Unlike Project, which returns normalized device
+// coordinates from -1 to 1, the coordinates are those of the viewport. For example, to place a label over a 3D point:
+//
+//	width, height := win.GetSize()
+//	x, y, depth := cam.ProjectScreen(&point, width, height)
+//	if depth >= 0 && depth <= 1 {
+//		label.SetPosition(x, y)
+//	}
+func (cam *Perspective) ProjectScreen(pos *math32.Vector3, width, height int) (x, y, depth float32) {
+
+	return projectToScreen(cam, pos, width, height)
+}
+
+// UnprojectScreen is the inverse of ProjectScreen and returns the position in world
+// coordinates of the point with the specified coordinates in pixels in a
+// viewport with the specified size and depth from 0 at the near plane to 1
+// at the far plane. For example, to find the point under the cursor on the
+// near plane:
+//
+//	width, height := win.GetSize()
+//	point := cam.UnprojectScreen(mev.Xpos, mev.Ypos, 0, width, height)
+func (cam *Perspective) UnprojectScreen(x, y, depth float32, width, height int) math32.Vector3 {
+
+	return unprojectFromScreen(cam, x, y, depth, width, height)
+}
+
 // Project transforms the specified position from world coordinates to this camera projected coordinates.
 func (cam *Perspective) Project(v *math32.Vector3) *math32.Vector3 {
 
@@ -114,9 +224,19 @@ func (cam *Perspective) Unproject(v *math32.Vector3) *math32.Vector3 {
 
 // SetRaycaster sets the specified raycaster with this camera position in world coordinates
 // pointing to the direction defined by the specified coordinates unprojected using this camera.
+// In orthographic projection the ray starts at the specified coordinates unprojected on
+// the near plane and points to the camera direction.
 func (cam *Perspective) SetRaycaster(rc *core.Raycaster, sx, sy float32) {
 
 	var origin, direction math32.Vector3
+	if cam.projection == ProjectionOrthographic {
+		origin.Set(sx, sy, -1)
+		cam.Unproject(&origin)
+		cam.WorldDirection(&direction)
+		rc.Set(&origin, &direction)
+		cam.ViewMatrix(&rc.ViewMatrix)
+		return
+	}
 	matrixWorld := cam.MatrixWorld()
 	origin.SetFromMatrixPosition(&matrixWorld)
 	direction.Set(sx, sy, 0.5)
@@ -129,6 +249,21 @@ func (cam *Perspective) SetRaycaster(rc *core.Raycaster, sx, sy float32) {
 // updateProjMatrix updates this camera projection matrix if necessary
 func (cam *Perspective) updateProjMatrix() {
 
+	// The orthographic view volume depends on the current distance
+	// to the target, so it is always recalculated
+	if cam.projection == ProjectionOrthographic {
+		var wpos math32.Vector3
+		cam.WorldPosition(&wpos)
+		dist := wpos.DistanceTo(&cam.target)
+		if dist < 1e-6 {
+			dist = 1
+		}
+		height := dist * math32.Tan(cam.fov*math32.Pi/360)
+		width := height * cam.aspect
+		cam.projMatrix.MakeOrthographic(-width, width, height, -height, cam.near, cam.far)
+		cam.projChanged = false
+		return
+	}
 	if cam.projChanged {
 		cam.projMatrix.MakePerspective(cam.fov, cam.aspect, cam.near, cam.far)
 		cam.projChanged = false
@@ -0,0 +1,167 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package camera
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+const testEpsilon = 1e-4
+
+func near(a, b float32) bool {
+
+	return math32.Abs(a-b) <= testEpsilon
+}
+
+func nearVector3(a, b *math32.Vector3) bool {
+
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Z, b.Z)
+}
+
+// maxProjected returns the maximum absolute normalized device coordinate
+// in X and Y of the corners of the specified box projected by the camera
+func maxProjected(icam ICamera, box *math32.Box3) float32 {
+
+	var view, proj, mvp math32.Matrix4
+	icam.ViewMatrix(&view)
+	icam.ProjMatrix(&proj)
+	mvp.MultiplyMatrices(&proj, &view)
+	max := float32(0)
+	for i := 0; i < 8; i++ {
+		corner := box.Min
+		if i&1 != 0 {
+			corner.X = box.Max.X
+		}
+		if i&2 != 0 {
+			corner.Y = box.Max.Y
+		}
+		if i&4 != 0 {
+			corner.Z = box.Max.Z
+		}
+		corner.ApplyProjection(&mvp)
+		max = math32.Max(max, math32.Max(math32.Abs(corner.X), math32.Abs(corner.Y)))
+	}
+	return max
+}
+
+func TestPerspectiveFitToBox(t *testing.T) {
+
+	unitCube := math32.NewBox3(&math32.Vector3{-0.5, -0.5, -0.5}, &math32.Vector3{0.5, 0.5, 0.5})
+	movedCube := math32.NewBox3(&math32.Vector3{9.5, -0.5, -0.5}, &math32.Vector3{10.5, 0.5, 0.5})
+	point := math32.NewBox3(&math32.Vector3{1, 2, 3}, &math32.Vector3{1, 2, 3})
+	cases := []struct {
+		name       string
+		fov        float32
+		aspect     float32
+		box        *math32.Box3
+		fill       float32
+		wantPos    math32.Vector3
+		wantTarget math32.Vector3
+	}{
+		// The nearest face at 0.5 from the center fills the view at distance 0.5 from it
+		{"unit cube", 90, 1, unitCube, 1, math32.Vector3{0, 0, 1}, math32.Vector3{}},
+		{"unit cube half", 90, 1, unitCube, 0.5, math32.Vector3{0, 0, 1.5}, math32.Vector3{}},
+		{"unit cube invalid fill", 90, 1, unitCube, 0, math32.Vector3{0, 0, 1}, math32.Vector3{}},
+		// A wide viewport is limited by its height
+		{"unit cube wide", 90, 2, unitCube, 1, math32.Vector3{0, 0, 1}, math32.Vector3{}},
+		// A narrow viewport is limited by its width
+		{"unit cube narrow", 90, 0.5, unitCube, 1, math32.Vector3{0, 0, 1.5}, math32.Vector3{}},
+		{"unit cube fov 60", 60, 1, unitCube, 1, math32.Vector3{0, 0, 0.5 + 0.5*math32.Sqrt(3)}, math32.Vector3{}},
+		{"moved cube", 90, 1, movedCube, 1, math32.Vector3{10, 0, 1}, math32.Vector3{10, 0, 0}},
+		// A point is framed as a box with size 1
+		{"point", 90, 1, point, 1, math32.Vector3{1, 2, 4}, math32.Vector3{1, 2, 3}},
+	}
+	for _, c := range cases {
+		cam := NewPerspective(c.fov, c.aspect, 0.1, 100)
+		cam.SetPosition(0, 0, 5)
+		cam.FitToBox(c.box, c.fill)
+		pos := cam.Position()
+		if !nearVector3(&pos, &c.wantPos) {
+			t.Errorf("%s: position = %v, want %v", c.name, pos, c.wantPos)
+		}
+		target := cam.Target()
+		if !nearVector3(&target, &c.wantTarget) {
+			t.Errorf("%s: target = %v, want %v", c.name, target, c.wantTarget)
+		}
+		if c.box == point {
+			continue
+		}
+		fill := c.fill
+		if fill <= 0 {
+			fill = 1
+		}
+		if max := maxProjected(cam, c.box); !near(max, fill) {
+			t.Errorf("%s: projected size = %v, want %v", c.name, max, fill)
+		}
+	}
+}
+
+func TestPerspectiveFitToBoxDirection(t *testing.T) {
+
+	// The camera direction is kept
+	cam := NewPerspective(90, 1, 0.1, 100)
+	cam.SetPosition(5, 5, 5)
+	box := math32.NewBox3(&math32.Vector3{-1, -1, -1}, &math32.Vector3{1, 1, 1})
+	cam.FitToBox(box, 0.8)
+	var dir math32.Vector3
+	cam.WorldDirection(&dir)
+	want := math32.Vector3{-1, -1, -1}
+	want.Normalize()
+	if !nearVector3(&dir, &want) {
+		t.Errorf("direction = %v, want %v", dir, want)
+	}
+	if max := maxProjected(cam, box); max > 0.8+testEpsilon {
+		t.Errorf("projected size = %v, want at most 0.8", max)
+	}
+}
+
+func TestPerspectiveFitToBoxEmpty(t *testing.T) {
+
+	cam := NewPerspective(90, 1, 0.1, 100)
+	cam.SetPosition(0, 0, 5)
+	var box math32.Box3
+	box.MakeEmpty()
+	cam.FitToBox(&box, 1)
+	pos := cam.Position()
+	if want := (math32.Vector3{0, 0, 5}); !nearVector3(&pos, &want) {
+		t.Errorf("position = %v, want %v", pos, want)
+	}
+}
+
+func TestOrthographicFitToBox(t *testing.T) {
+
+	unitCube := math32.NewBox3(&math32.Vector3{-0.5, -0.5, -0.5}, &math32.Vector3{0.5, 0.5, 0.5})
+	cases := []struct {
+		name     string
+		box      *math32.Box3
+		fill     float32
+		wantZoom float32
+		wantPos  math32.Vector3
+	}{
+		{"unit cube", unitCube, 1, 2, math32.Vector3{0, 0, 0.6}},
+		{"unit cube half", unitCube, 0.5, 1, math32.Vector3{0, 0, 0.6}},
+		{"point", math32.NewBox3(&math32.Vector3{1, 2, 3}, &math32.Vector3{1, 2, 3}), 1, 2, math32.Vector3{1, 2, 3.6}},
+	}
+	for _, c := range cases {
+		cam := NewOrthographic(-1, 1, 1, -1, 0.1, 100)
+		cam.SetPosition(0, 0, 5)
+		cam.FitToBox(c.box, c.fill)
+		if !near(cam.Zoom(), c.wantZoom) {
+			t.Errorf("%s: zoom = %v, want %v", c.name, cam.Zoom(), c.wantZoom)
+		}
+		pos := cam.Position()
+		if !nearVector3(&pos, &c.wantPos) {
+			t.Errorf("%s: position = %v, want %v", c.name, pos, c.wantPos)
+		}
+	}
+	cam := NewOrthographic(-1, 1, 1, -1, 0.1, 100)
+	cam.SetPosition(0, 0, 5)
+	cam.FitToBox(unitCube, 0.5)
+	if max := maxProjected(cam, unitCube); !near(max, 0.5) {
+		t.Errorf("projected size = %v, want 0.5", max)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+// Layers is a bitmask of the 32 layers numbered from 0 to 31 which a node
+// belongs to or which a camera or raycaster processes. A node is drawn by a
+// camera or checked by a raycaster if they have at least one layer in common.
+// Layer numbers out of this range are ignored and logged as warnings.
+// For example, to draw a gizmo only with the editor camera:
+//
+//	gizmo.SetLayer(1)
+//	editorCam.EnableLayer(1)
+type Layers uint32
+
+// LayersAll contains all the layers
+const LayersAll Layers = 0xFFFFFFFF
+
+// Set sets these layers to contain only the specified layer
+func (l *Layers) Set(layer int) {
+
+	if !validLayer(layer) {
+		return
+	}
+	*l = 1 << uint(layer)
+}
+
+// Enable adds the specified layer to these layers
+func (l *Layers) Enable(layer int) {
+
+	if !validLayer(layer) {
+		return
+	}
+	*l |= 1 << uint(layer)
+}
+
+// Disable removes the specified layer from these layers
+func (l *Layers) Disable(layer int) {
+
+	if !validLayer(layer) {
+		return
+	}
+	*l &^= 1 << uint(layer)
+}
+
+// Toggle adds the specified layer to these layers if not
+// contained or removes it otherwise
+func (l *Layers) Toggle(layer int) {
+
+	if !validLayer(layer) {
+		return
+	}
+	*l ^= 1 << uint(layer)
+}
+
+// IsEnabled returns if these layers contain the specified layer
+func (l Layers) IsEnabled(layer int) bool {
+
+	if !validLayer(layer) {
+		return false
+	}
+	return l&(1<<uint(layer)) != 0
+}
+
+// Test returns if these layers have at least one layer in common
+// with the specified layers
+func (l Layers) Test(other Layers) bool {
+
+	return l&other != 0
+}
+
+// validLayer returns if the specified layer number is valid
+// and logs a warning if not
+func validLayer(layer int) bool {
+
+	if layer < 0 || layer > 31 {
+		log.Warn("Invalid layer:%d", layer)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestLayers(t *testing.T) {
+
+	cases := []struct {
+		name string
+		op   func(l *Layers)
+		init Layers
+		want Layers
+	}{
+		{"set", func(l *Layers) { l.Set(3) }, LayersAll, 0x8},
+		{"set last", func(l *Layers) { l.Set(31) }, 0, 0x80000000},
+		{"enable", func(l *Layers) { l.Enable(2) }, 0x1, 0x5},
+		{"enable enabled", func(l *Layers) { l.Enable(0) }, 0x1, 0x1},
+		{"disable", func(l *Layers) { l.Disable(0) }, 0x3, 0x2},
+		{"disable disabled", func(l *Layers) { l.Disable(5) }, 0x3, 0x3},
+		{"toggle on", func(l *Layers) { l.Toggle(4) }, 0x1, 0x11},
+		{"toggle off", func(l *Layers) { l.Toggle(0) }, 0x1, 0x0},
+		// Out of range layers are ignored
+		{"set 32", func(l *Layers) { l.Set(32) }, 0x1, 0x1},
+		{"set negative", func(l *Layers) { l.Set(-1) }, 0x1, 0x1},
+		{"enable 32", func(l *Layers) { l.Enable(32) }, 0x1, 0x1},
+		{"disable 40", func(l *Layers) { l.Disable(40) }, LayersAll, LayersAll},
+		{"toggle 32", func(l *Layers) { l.Toggle(32) }, 0x1, 0x1},
+	}
+	for _, c := range cases {
+		l := c.init
+		c.op(&l)
+		if l != c.want {
+			t.Errorf("%s: layers = %#x, want %#x", c.name, l, c.want)
+		}
+	}
+}
+
+func TestLayersIsEnabled(t *testing.T) {
+
+	l := Layers(0x80000005)
+	for layer, want := range map[int]bool{0: true, 1: false, 2: true, 31: true, 32: false, -1: false} {
+		if got := l.IsEnabled(layer); got != want {
+			t.Errorf("IsEnabled(%d) = %v, want %v", layer, got, want)
+		}
+	}
+}
+
+func TestLayersTest(t *testing.T) {
+
+	cases := []struct {
+		name string
+		a, b Layers
+		want bool
+	}{
+		{"same", 0x1, 0x1, true},
+		{"disjoint", 0x1, 0x2, false},
+		{"overlap", 0x6, 0xC, true},
+		{"all", LayersAll, 0x80000000, true},
+		{"empty", 0, LayersAll, false},
+		{"both empty", 0, 0, false},
+	}
+	for _, c := range cases {
+		if got := c.a.Test(c.b); got != c.want {
+			t.Errorf("%s: %#x.Test(%#x) = %v, want %v", c.name, c.a, c.b, got, c.want)
+		}
+		if got := c.b.Test(c.a); got != c.want {
+			t.Errorf("%s: %#x.Test(%#x) = %v, want %v", c.name, c.b, c.a, got, c.want)
+		}
+	}
+}
+
+func TestNodeLayersRaycast(t *testing.T) {
+
+	// New nodes belong to layer 0 and raycasters check all layers
+	n := NewNode()
+	if n.Layers() != 0x1 {
+		t.Errorf("default node layers = %#x, want 0x1", n.Layers())
+	}
+	rc := NewRaycaster(&math32.Vector3{}, &math32.Vector3{0, 0, -1})
+	if !n.Layers().Test(rc.Layers) {
+		t.Errorf("default node not tested by the default raycaster")
+	}
+	n.SetLayer(1)
+	rc.Layers.Set(0)
+	if n.Layers().Test(rc.Layers) {
+		t.Errorf("node in layer 1 tested by raycaster of layer 0")
+	}
+	n.EnableLayer(0)
+	if !n.Layers().Test(rc.Layers) {
+		t.Errorf("node in layers 0 and 1 not tested by raycaster of layer 0")
+	}
+}
@@ -18,6 +18,24 @@ type INode interface {
 	Dispose()
 }
 
+// Node events
+const (
+	OnAdded   = "core.OnAdded"   // Node entered a scene, dispatched to the node and its descendants
+	OnRemoved = "core.OnRemoved" // Node left a scene, dispatched to the node and its descendants
+)
+
+// NodeEvent is the event of the OnAdded and OnRemoved events. It is
+// dispatched to the node which entered or left a scene and to all its
+// descendants, so they can subscribe or unsubscribe to other events
+// and create or release resources when they enter or leave a scene.
+// A node is in a scene if it or one of its ancestors was set as
+// a scene root with SetScene.
+type NodeEvent struct {
+	Node   INode // Node which entered or left the scene
+	Parent INode // Parent node of the node which entered or left the scene
+	Scene  INode // Root of the scene entered or left
+}
+
 type Node struct {
 	Dispatcher                    // Embedded event dispatcher
 	loaderID    string            // ID used by loader
@@ -30,6 +48,8 @@ type Node struct {
 	matrix      math32.Matrix4    // Transform matrix relative to this node parent.
 	matrixWorld math32.Matrix4    // Transform world matrix
 	visible     bool              // Visible flag
+	layers      Layers            // Layers of this node
+	scene       bool              // Scene root flag
 	parent      INode             // Parent node
 	children    []INode           // Array with node children
 	userData    interface{}       // Generic user data
@@ -57,6 +77,7 @@ func (n *Node) Init() {
 	n.matrixWorld.Identity()
 	n.children = make([]INode, 0)
 	n.visible = true
+	n.layers = 1
 }
 
 // GetNode satisfies the INode interface and returns
@@ -115,6 +136,74 @@ func (n *Node) FindLoaderID(id string) INode {
 	return finder(n, id)
 }
 
+// FindByName looks in the children of this node and all their descendants
+// for a node with the specified name and if found returns it.
+// If more than one node has the name, returns the first one found
+// in depth first order. Returns nil if not found.
+// For example, to find the wheel of a loaded car model:
+//
+//	wheel := car.FindByName("front_wheel")
+//	if wheel != nil {
+//		wheel.GetNode().SetRotationX(angle)
+//	}
+func (n *Node) FindByName(name string) INode {
+
+	var found INode
+	n.Traverse(func(inode INode) bool {
+		if inode.GetNode().name == name {
+			found = inode
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// FindByUserData looks in the children of this node and all their
+// descendants for a node with user data equal to the specified data
+// and if found returns it. The data must be of a comparable type, such as a
+// pointer or a string. If more than one node has the data, returns the first
+// one found in depth first order. Returns nil if not found.
+func (n *Node) FindByUserData(data interface{}) INode {
+
+	var found INode
+	n.Traverse(func(inode INode) bool {
+		if inode.GetNode().userData == data {
+			found = inode
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Traverse calls the specified function for each of the children of this
+// node and all their descendants in depth first order, visiting each node
+// before its children. The traversal stops as soon as the function returns
+// false. Returns false if the traversal was stopped or true otherwise.
+// The function must not add or remove nodes of the traversed hierarchy.
+// For example, to count the meshes of a scene:
+//
+//	count := 0
+//	scene.Traverse(func(inode core.INode) bool {
+//		if _, ok := inode.(*graphic.Mesh); ok {
+//			count++
+//		}
+//		return true
+//	})
+func (n *Node) Traverse(visit func(INode) bool) bool {
+
+	for _, child := range n.children {
+		if !visit(child) {
+			return false
+		}
+		if !child.GetNode().Traverse(visit) {
+			return false
+		}
+	}
+	return true
+}
+
 // SetName set an option name for the node.
 // This name can be used for debugging or other purposes.
 func (n *Node) SetName(name string) {
@@ -328,6 +417,40 @@ func (n *Node) Visible() bool {
 	return n.visible
 }
 
+// SetLayers sets the layers this node belongs to. Cameras only draw and
+// raycasters only check intersections with the nodes which have a layer in
+// common with them, so helpers and other nodes which should not be drawn or
+// picked can be placed in other layers. For cameras these are the layers
+// which are drawn. The default is layer 0 only.
+func (n *Node) SetLayers(layers Layers) {
+
+	n.layers = layers
+}
+
+// Layers returns the layers this node belongs to
+func (n *Node) Layers() Layers {
+
+	return n.layers
+}
+
+// SetLayer sets this node to belong only to the specified layer from 0 to 31
+func (n *Node) SetLayer(layer int) {
+
+	n.layers.Set(layer)
+}
+
+// EnableLayer adds the specified layer to the layers of this node
+func (n *Node) EnableLayer(layer int) {
+
+	n.layers.Enable(layer)
+}
+
+// DisableLayer removes the specified layer from the layers of this node
+func (n *Node) DisableLayer(layer int) {
+
+	n.layers.Disable(layer)
+}
+
 // WorldPosition updates this node world matrix and gets
 // the current world position vector.
 func (n *Node) WorldPosition(result *math32.Vector3) {
@@ -422,6 +545,40 @@ func (n *Node) Children() []INode {
 	return n.children
 }
 
+// SetScene sets if this node is the root of a scene. The nodes in the tree
+// of a scene root receive the OnAdded event when they enter the scene, because
+// they are added to a node in the scene or the root is set as a scene, and the
+// OnRemoved event when they leave it, because they are removed or disposed or
+// the root is no longer a scene. Moving a node inside the same scene dispatches
+// no events. For example, to start the animations of the nodes of a scene:
+//
+//	scene := core.NewNode()
+//	scene.SetScene(true)
+//	model.Subscribe(core.OnAdded, func(evname string, ev interface{}) {
+//		anim.Play()
+//	})
+//	scene.Add(model)
+func (n *Node) SetScene(state bool) {
+
+	before := n.sceneRoot()
+	n.scene = state
+	after := n.sceneRoot()
+	dispatchSceneChange(n, n.parent, before, after)
+}
+
+// IsScene returns if this node is the root of a scene
+func (n *Node) IsScene() bool {
+
+	return n.scene
+}
+
+// Scene returns the root of the scene which contains this node,
+// which may be this node, or nil if it is not in a scene
+func (n *Node) Scene() INode {
+
+	return n.sceneRoot()
+}
+
 // Add adds the specified INode to this node list of children
 func (n *Node) Add(ichild INode) *Node {
 
@@ -432,11 +589,13 @@ func (n *Node) Add(ichild INode) *Node {
 	}
 	// If this child already has a parent,
 	// removes it from this parent children list
+	before := child.sceneRoot()
 	if child.parent != nil {
-		child.parent.GetNode().Remove(ichild)
+		child.parent.GetNode().detach(ichild)
 	}
 	child.parent = n
 	n.children = append(n.children, ichild)
+	dispatchSceneChange(ichild, n, before, child.sceneRoot())
 	return n
 }
 
@@ -444,24 +603,22 @@ func (n *Node) Add(ichild INode) *Node {
 // Returns true if found or false otherwise
 func (n *Node) Remove(ichild INode) bool {
 
-	for pos, current := range n.children {
-		if current == ichild {
-			copy(n.children[pos:], n.children[pos+1:])
-			n.children[len(n.children)-1] = nil
-			n.children = n.children[:len(n.children)-1]
-			ichild.GetNode().parent = nil
-			return true
-		}
+	before := ichild.GetNode().sceneRoot()
+	if !n.detach(ichild) {
+		return false
 	}
-	return false
+	dispatchSceneChange(ichild, n, before, nil)
+	return true
 }
 
 // RemoveAll removes all children from this node
 func (n *Node) RemoveAll(recurs bool) {
 
+	scene := n.sceneRoot()
 	for pos, ichild := range n.children {
 		n.children[pos] = nil
 		ichild.GetNode().parent = nil
+		dispatchSceneChange(ichild, n, scene, nil)
 		if recurs {
 			ichild.GetNode().RemoveAll(recurs)
 		}
@@ -471,11 +628,14 @@ func (n *Node) RemoveAll(recurs bool) {
 
 // DisposeChildren removes and disposes all children of this
 // node and if 'recurs' is true for each of its children recursively.
+// The OnRemoved events are dispatched before the nodes are disposed.
 func (n *Node) DisposeChildren(recurs bool) {
 
+	scene := n.sceneRoot()
 	for pos, ichild := range n.children {
 		n.children[pos] = nil
 		ichild.GetNode().parent = nil
+		dispatchSceneChange(ichild, n, scene, nil)
 		if recurs {
 			ichild.GetNode().DisposeChildren(true)
 		}
@@ -484,6 +644,111 @@ func (n *Node) DisposeChildren(recurs bool) {
 	n.children = n.children[0:0]
 }
 
+// DisposeTree removes the specified node from its parent and disposes it and
+// all its descendants, releasing their OpenGL resources. If the node was in
+// a scene, the OnRemoved event is dispatched to the node and its descendants
+// before they are disposed. Geometries, materials and textures are reference
+// counted: each graphic releases one reference to its geometry and materials
+// and each material one reference to its textures, and the resources are
+// deleted when the last reference is released. Objects shared by several
+// graphics or materials must have their reference count incremented with
+// Incref for each additional owner, so they are not deleted while still in
+// use. For example, to remove a level with its resources:
+//
+//	geom := geometry.NewBox(1, 1, 1, 1, 1, 1)
+//	for i := 0; i < 10; i++ {
+//		if i > 0 {
+//			geom.Incref()
+//		}
+//		level.Add(graphic.NewMesh(geom, material.NewStandard(math32.NewColor(1, 0, 0))))
+//	}
+//	...
+//	core.DisposeTree(level)
+func DisposeTree(inode INode) {
+
+	node := inode.GetNode()
+	parent := node.parent
+	before := node.sceneRoot()
+	if parent != nil {
+		parent.GetNode().detach(inode)
+	}
+	node.scene = false
+	dispatchSceneChange(inode, parent, before, nil)
+	disposeTree(inode)
+}
+
+// disposeTree disposes the specified node and all its descendants
+// without dispatching events
+func disposeTree(inode INode) {
+
+	node := inode.GetNode()
+	for pos, ichild := range node.children {
+		node.children[pos] = nil
+		ichild.GetNode().parent = nil
+		disposeTree(ichild)
+	}
+	node.children = node.children[0:0]
+	inode.Dispose()
+}
+
+// detach removes the specified child from this node list
+// of children without dispatching events.
+// Returns true if found or false otherwise
+func (n *Node) detach(ichild INode) bool {
+
+	for pos, current := range n.children {
+		if current == ichild {
+			copy(n.children[pos:], n.children[pos+1:])
+			n.children[len(n.children)-1] = nil
+			n.children = n.children[:len(n.children)-1]
+			ichild.GetNode().parent = nil
+			return true
+		}
+	}
+	return false
+}
+
+// sceneRoot returns the root of the scene which contains this node or nil
+func (n *Node) sceneRoot() INode {
+
+	var inode INode = n
+	for inode != nil {
+		node := inode.GetNode()
+		if node.scene {
+			return inode
+		}
+		inode = node.parent
+	}
+	return nil
+}
+
+// dispatchSceneChange dispatches the OnRemoved and OnAdded events to the
+// specified node and its descendants when the scene which contains them
+// changed from the specified scene before to the specified scene after
+func dispatchSceneChange(inode, parent, before, after INode) {
+
+	if before == after {
+		return
+	}
+	if before != nil {
+		dispatchTree(inode, OnRemoved, &NodeEvent{Node: inode, Parent: parent, Scene: before})
+	}
+	if after != nil {
+		dispatchTree(inode, OnAdded, &NodeEvent{Node: inode, Parent: parent, Scene: after})
+	}
+}
+
+// dispatchTree dispatches the specified event to the specified
+// node and all its descendants
+func dispatchTree(inode INode, evname string, ev interface{}) {
+
+	node := inode.GetNode()
+	node.Dispatch(evname, ev)
+	for _, ichild := range node.children {
+		dispatchTree(ichild, evname, ev)
+	}
+}
+
 // SetUserData sets this node associated generic user data
 func (n *Node) SetUserData(data interface{}) {
 
@@ -0,0 +1,272 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// eventLog records the scene events of nodes as "name:event" entries
+type eventLog []string
+
+// watch creates a node with the specified name which records its scene events
+func (el *eventLog) watch(name string) *Node {
+
+	n := NewNode()
+	n.SetName(name)
+	n.Subscribe(OnAdded, func(evname string, ev interface{}) {
+		*el = append(*el, name+":added")
+	})
+	n.Subscribe(OnRemoved, func(evname string, ev interface{}) {
+		*el = append(*el, name+":removed")
+	})
+	return n
+}
+
+// take returns the recorded events and clears them
+func (el *eventLog) take() string {
+
+	s := strings.Join(*el, " ")
+	*el = nil
+	return s
+}
+
+func TestNodeSceneEvents(t *testing.T) {
+
+	var el eventLog
+	scene := NewNode()
+	scene.SetScene(true)
+	other := NewNode()
+	group := el.watch("group")
+	child := el.watch("child")
+	group.Add(child)
+
+	// Adding to a node outside a scene dispatches no events
+	if got := el.take(); got != "" {
+		t.Errorf("added outside a scene: events %q, want none", got)
+	}
+
+	steps := []struct {
+		name string
+		op   func()
+		want string
+	}{
+		{"enter", func() { scene.Add(group) }, "group:added child:added"},
+		{"move inside", func() { scene.Add(child) }, ""},
+		{"move back", func() { group.Add(child) }, ""},
+		{"leave by reparenting", func() { other.Add(group) }, "group:removed child:removed"},
+		{"enter by reparenting", func() { scene.Add(group) }, "group:added child:added"},
+		{"remove", func() { scene.Remove(group) }, "group:removed child:removed"},
+		{"remove again", func() { scene.Remove(group) }, ""},
+		{"root becomes scene", func() { other.Add(group); other.SetScene(true) }, "group:added child:added"},
+		{"root stops being scene", func() { other.SetScene(false) }, "group:removed child:removed"},
+		{"remove all", func() { scene.Add(group); el.take(); scene.RemoveAll(true) }, "group:removed child:removed"},
+	}
+	for _, s := range steps {
+		s.op()
+		if got := el.take(); got != s.want {
+			t.Errorf("%s: events %q, want %q", s.name, got, s.want)
+		}
+	}
+	if group.Parent() != nil || len(group.Children()) != 0 {
+		t.Errorf("remove all recursive: group parent %v with %d children, want none", group.Parent(), len(group.Children()))
+	}
+}
+
+func TestNodeSceneEventFields(t *testing.T) {
+
+	scene := NewNode()
+	scene.SetScene(true)
+	parent := NewNode()
+	scene.Add(parent)
+	child := NewNode()
+	grandchild := NewNode()
+	child.Add(grandchild)
+
+	var events []*NodeEvent
+	grandchild.Subscribe(OnAdded, func(evname string, ev interface{}) {
+		events = append(events, ev.(*NodeEvent))
+	})
+	parent.Add(child)
+	if len(events) != 1 {
+		t.Fatalf("%d events, want 1", len(events))
+	}
+	ev := events[0]
+	if ev.Node != child || ev.Parent != parent || ev.Scene != scene {
+		t.Errorf("event node %p parent %p scene %p, want %p %p %p", ev.Node, ev.Parent, ev.Scene, child, parent, scene)
+	}
+	if grandchild.Scene() != scene || NewNode().Scene() != nil {
+		t.Errorf("Scene() of nodes in and out of the scene is wrong")
+	}
+}
+
+// disposeNode is a node which records when it is disposed
+type disposeNode struct {
+	Node
+	el *eventLog
+}
+
+func (dn *disposeNode) Dispose() {
+
+	*dn.el = append(*dn.el, dn.Name()+":disposed")
+}
+
+// watchDispose creates a node with the specified name which records
+// its scene events and when it is disposed
+func (el *eventLog) watchDispose(name string) *disposeNode {
+
+	dn := &disposeNode{el: el}
+	dn.Init()
+	dn.SetName(name)
+	dn.Subscribe(OnRemoved, func(evname string, ev interface{}) {
+		*el = append(*el, name+":removed")
+	})
+	return dn
+}
+
+func TestNodeDisposeEvents(t *testing.T) {
+
+	var el eventLog
+	build := func() (*Node, *disposeNode) {
+		scene := NewNode()
+		scene.SetScene(true)
+		group := el.watchDispose("group")
+		group.Add(el.watchDispose("child"))
+		scene.Add(group)
+		return scene, group
+	}
+
+	// The events are dispatched to the whole tree before it is disposed
+	scene, _ := build()
+	scene.DisposeChildren(true)
+	if got, want := el.take(), "group:removed child:removed child:disposed group:disposed"; got != want {
+		t.Errorf("DisposeChildren: events %q, want %q", got, want)
+	}
+
+	scene, group := build()
+	DisposeTree(group)
+	if got, want := el.take(), "group:removed child:removed child:disposed group:disposed"; got != want {
+		t.Errorf("DisposeTree: events %q, want %q", got, want)
+	}
+	if len(scene.Children()) != 0 {
+		t.Errorf("DisposeTree: scene has %d children, want 0", len(scene.Children()))
+	}
+
+	// Nodes outside a scene are disposed without events
+	group = el.watchDispose("group")
+	group.Add(el.watchDispose("child"))
+	DisposeTree(group)
+	if got, want := el.take(), "child:disposed group:disposed"; got != want {
+		t.Errorf("DisposeTree outside a scene: events %q, want %q", got, want)
+	}
+}
+
+// newTree creates the hierarchy below with nodes named by their letters
+// and returns its root and its nodes by name
+//
+//	root
+//	├── a
+//	│   ├── b
+//	│   │   └── dup (1)
+//	│   └── dup (2)
+//	└── dup (3)
+//	    └── c
+func newTree() (*Node, map[string]*Node) {
+
+	nodes := make(map[string]*Node)
+	add := func(parent *Node, name, key string) *Node {
+		n := NewNode()
+		n.SetName(name)
+		parent.Add(n)
+		nodes[key] = n
+		return n
+	}
+	root := NewNode()
+	root.SetName("root")
+	a := add(root, "a", "a")
+	b := add(a, "b", "b")
+	add(b, "dup", "dup1")
+	add(a, "dup", "dup2")
+	dup3 := add(root, "dup", "dup3")
+	add(dup3, "c", "c")
+	return root, nodes
+}
+
+func TestNodeFindByName(t *testing.T) {
+
+	root, nodes := newTree()
+	cases := []struct {
+		name string
+		want INode
+	}{
+		{"a", nodes["a"]},
+		{"c", nodes["c"]},
+		// Non unique names return the first node in depth first order
+		{"dup", nodes["dup1"]},
+		// The node itself is not searched
+		{"root", nil},
+		{"none", nil},
+	}
+	for _, c := range cases {
+		if got := root.FindByName(c.name); got != c.want {
+			t.Errorf("FindByName(%q) = %v, want %v", c.name, got, c.want)
+		}
+	}
+	if got := nodes["dup3"].FindByName("dup"); got != nil {
+		t.Errorf("FindByName in subtree = %v, want nil", got)
+	}
+}
+
+func TestNodeFindByUserData(t *testing.T) {
+
+	root, nodes := newTree()
+	key := &struct{ id int }{1}
+	nodes["b"].SetUserData("door")
+	nodes["dup2"].SetUserData(key)
+	nodes["c"].SetUserData(key)
+	cases := []struct {
+		name string
+		data interface{}
+		want INode
+	}{
+		{"string", "door", nodes["b"]},
+		{"first pointer", key, nodes["dup2"]},
+		{"other pointer", &struct{ id int }{1}, nil},
+		{"other type", 1, nil},
+	}
+	for _, c := range cases {
+		if got := root.FindByUserData(c.data); got != c.want {
+			t.Errorf("%s: FindByUserData = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNodeTraverse(t *testing.T) {
+
+	root, _ := newTree()
+	cases := []struct {
+		name string
+		stop string
+		want string
+		ret  bool
+	}{
+		{"all", "", "a b dup dup dup c", true},
+		{"stop at leaf", "b", "a b", false},
+		{"stop before children", "a", "a", false},
+		{"stop last", "c", "a b dup dup dup c", false},
+	}
+	for _, c := range cases {
+		var visited []string
+		ret := root.Traverse(func(inode INode) bool {
+			name := inode.GetNode().Name()
+			visited = append(visited, name)
+			return name != c.stop
+		})
+		if got := strings.Join(visited, " "); got != c.want || ret != c.ret {
+			t.Errorf("%s: visited %q returned %v, want %q %v", c.name, got, ret, c.want, c.ret)
+		}
+	}
+}
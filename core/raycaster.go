@@ -30,6 +30,10 @@ type Raycaster struct {
 	// when checking for sprite intersections.
 	// It is set automatically when using camera.SetRaycaster
 	ViewMatrix math32.Matrix4
+	// Layers of the nodes checked for intersections.
+	// The children of nodes in other layers are still checked.
+	// The default value checks all the layers.
+	Layers Layers
 	// Embedded ray
 	math32.Ray
 }
@@ -61,6 +65,7 @@ func NewRaycaster(origin, direction *math32.Vector3) *Raycaster {
 	rc.Far = math32.Inf(1)
 	rc.LinePrecision = 0.1
 	rc.PointPrecision = 0.1
+	rc.Layers = LayersAll
 	return rc
 }
 
@@ -68,6 +73,10 @@ func NewRaycaster(origin, direction *math32.Vector3) *Raycaster {
 // and the specified node. If recursive is true, it also checks
 // the intersection with the node's children.
 // Intersections are returned sorted by distance, closest first.
+// For example, to pick only the nodes in the second layer:
+//
+//	rc.Layers.Set(1)
+//	intersects := rc.IntersectObject(scene, true)
 func (rc *Raycaster) IntersectObject(inode INode, recursive bool) []Intersect {
 
 	intersects := []Intersect{}
@@ -96,7 +105,9 @@ func (rc *Raycaster) intersectObject(inode INode, intersects *[]Intersect, recur
 	if !node.Visible() {
 		return
 	}
-	inode.Raycast(rc, intersects)
+	if node.Layers().Test(rc.Layers) {
+		inode.Raycast(rc, intersects)
+	}
 	if recursive {
 		for _, child := range node.Children() {
 			rc.intersectObject(child, intersects, true)
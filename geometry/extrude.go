@@ -0,0 +1,231 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+	"math"
+)
+
+// BevelOptions describes the bevel of the front and back edges of an Extrude geometry
+type BevelOptions struct {
+	Thickness float64 // depth of each bevel along the Z axis
+	Size      float64 // distance the side walls are extended outward from the shape outline
+	Segments  int     // number of segments of each bevel (minimum = 1)
+}
+
+type Extrude struct {
+	Geometry
+	Depth float64
+	Bevel BevelOptions
+}
+
+// extrudeLayer is a cross section of the side walls of an Extrude geometry
+type extrudeLayer struct {
+	z      float32 // z coordinate of the layer
+	offset float32 // distance of the layer outline from the shape outline
+	nxy    float32 // component of the wall normal along the outline normal
+	nz     float32 // component of the wall normal along the Z axis
+}
+
+// NewExtrude creates and returns a pointer to a new Extrude geometry object.
+// The geometry is the 2D shape defined by the outer contour and the holes
+// extruded along the Z axis from z = 0 to z = depth.
+// The outer contour must be in counter clockwise order and the holes in
+// clockwise order when seen from the positive Z axis; contours in the
+// opposite order are reversed. The contours should not repeat their first
+// point at the end.
+// If bevel is not nil the edges between the side walls and the caps are
+// rounded: the caps keep the shape outline and the walls are extended by
+// the bevel size. The bevel thickness is limited to half of the depth.
+// The caps are the group with material index 0 and the side walls the group
+// with material index 1.
+func NewExtrude(contour []math32.Vector2, holes [][]math32.Vector2, depth float64, bevel *BevelOptions) *Extrude {
+
+	e := new(Extrude)
+	e.Geometry.Init()
+	e.Depth = depth
+
+	// Builds the layers of the side walls from the back to the front.
+	// The bevels follow a quarter of an ellipse.
+	var layers []extrudeLayer
+	if bevel != nil && bevel.Thickness > 0 && bevel.Size > 0 && bevel.Segments > 0 {
+		e.Bevel = *bevel
+		thick := math.Min(bevel.Thickness, depth/2)
+		var front []extrudeLayer
+		for b := 0; b <= bevel.Segments; b++ {
+			a := float64(b) / float64(bevel.Segments) * math.Pi / 2
+			n := math32.NewVector2(float32(thick*math.Sin(a)), float32(bevel.Size*math.Cos(a))).Normalize()
+			z := float32(thick * (1 - math.Cos(a)))
+			offset := float32(bevel.Size * math.Sin(a))
+			layers = append(layers, extrudeLayer{z, offset, n.X, -n.Y})
+			front = append([]extrudeLayer{{float32(depth) - z, offset, n.X, n.Y}}, front...)
+		}
+		// Skips the straight wall if the bevels fill the depth
+		if thick == depth/2 {
+			front = front[1:]
+		}
+		layers = append(layers, front...)
+	} else {
+		layers = []extrudeLayer{{0, 0, 1, 0}, {float32(depth), 0, 1, 0}}
+	}
+
+	// Sets the winding order of the contours
+	contour = orientContour(contour, true)
+	oholes := make([][]math32.Vector2, len(holes))
+	for i, hole := range holes {
+		oholes[i] = orientContour(hole, false)
+	}
+
+	// Create buffers
+	positions := math32.NewArrayF32(0, 16)
+	normals := math32.NewArrayF32(0, 16)
+	uvs := math32.NewArrayF32(0, 16)
+	indices := math32.NewArrayU32(0, 16)
+
+	// Generates the caps with texture coordinates from the shape bounding box
+	points, tris := triangulate(contour, oholes)
+	var min, max math32.Vector2
+	if len(points) > 0 {
+		min = points[0]
+		max = points[0]
+	}
+	for i := range points {
+		min.Min(&points[i])
+		max.Max(&points[i])
+	}
+	size := max
+	size.Sub(&min)
+	if size.X == 0 {
+		size.X = 1
+	}
+	if size.Y == 0 {
+		size.Y = 1
+	}
+	gstart := indices.Size()
+	for _, c := range []struct{ z, nz float32 }{{0, -1}, {float32(depth), 1}} {
+		offset := uint32(positions.Len() / 3)
+		for i := range points {
+			positions.Append(points[i].X, points[i].Y, c.z)
+			normals.Append(0, 0, c.nz)
+			uvs.Append((points[i].X-min.X)/size.X, (points[i].Y-min.Y)/size.Y)
+		}
+		for i := 0; i < len(tris); i += 3 {
+			if c.nz > 0 {
+				indices.Append(offset+uint32(tris[i]), offset+uint32(tris[i+1]), offset+uint32(tris[i+2]))
+			} else {
+				indices.Append(offset+uint32(tris[i]), offset+uint32(tris[i+2]), offset+uint32(tris[i+1]))
+			}
+		}
+	}
+	e.AddGroup(gstart, indices.Size()-gstart, 0)
+
+	// Generates the side walls of each contour with separate vertices for each
+	// edge, so the corners of the shape are sharp and the bevels are smooth.
+	gstart = indices.Size()
+	for _, ring := range append([][]math32.Vector2{contour}, oholes...) {
+		e.buildWalls(ring, layers, &positions, &normals, &uvs, &indices)
+	}
+	e.AddGroup(gstart, indices.Size()-gstart, 1)
+
+	e.SetIndices(indices)
+	e.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(positions))
+	e.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(normals))
+	e.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 2).SetBuffer(uvs))
+
+	return e
+}
+
+// buildWalls appends the side walls of the specified contour for all the layers.
+// The u texture coordinate is the distance along the contour relative to its
+// length and the v texture coordinate is the relative depth.
+func (e *Extrude) buildWalls(ring []math32.Vector2, layers []extrudeLayer, positions, normals, uvs *math32.ArrayF32, indices *math32.ArrayU32) {
+
+	count := len(ring)
+	if count < 3 {
+		return
+	}
+
+	// Calculates the outward normal of each edge and the length of the contour
+	edgeNormals := make([]math32.Vector2, count)
+	var length float32
+	for i := 0; i < count; i++ {
+		p1 := &ring[i]
+		p2 := &ring[(i+1)%count]
+		edgeNormals[i].Set(p2.Y-p1.Y, p1.X-p2.X).Normalize()
+		length += p1.DistanceTo(p2)
+	}
+	if length == 0 {
+		length = 1
+	}
+
+	// Calculates the offset direction of each vertex which moves
+	// both adjacent edges by the offset distance
+	offsets := make([]math32.Vector2, count)
+	for i := 0; i < count; i++ {
+		n1 := &edgeNormals[(i+count-1)%count]
+		n2 := &edgeNormals[i]
+		offsets[i].AddVectors(n1, n2)
+		if offsets[i].LengthSq() < 1e-8 {
+			offsets[i] = *n2
+			continue
+		}
+		offsets[i].Normalize()
+		// Limits the miter length at sharp corners
+		scale := 1 / math32.Max(offsets[i].Dot(n2), 0.25)
+		offsets[i].MultiplyScalar(scale)
+	}
+
+	depth := float32(e.Depth)
+	if depth == 0 {
+		depth = 1
+	}
+	var dist float32
+	for i := 0; i < count; i++ {
+		j := (i + 1) % count
+		p1 := &ring[i]
+		p2 := &ring[j]
+		n := &edgeNormals[i]
+		u1 := dist / length
+		dist += p1.DistanceTo(p2)
+		u2 := dist / length
+		base := uint32(positions.Len() / 3)
+		for _, l := range layers {
+			positions.Append(p1.X+offsets[i].X*l.offset, p1.Y+offsets[i].Y*l.offset, l.z)
+			positions.Append(p2.X+offsets[j].X*l.offset, p2.Y+offsets[j].Y*l.offset, l.z)
+			normals.Append(n.X*l.nxy, n.Y*l.nxy, l.nz)
+			normals.Append(n.X*l.nxy, n.Y*l.nxy, l.nz)
+			uvs.Append(u1, l.z/depth, u2, l.z/depth)
+		}
+		for k := 0; k < len(layers)-1; k++ {
+			a := base + uint32(2*k)
+			b := a + 1
+			c := a + 3
+			d := a + 2
+			indices.Append(a, b, d, b, c, d)
+		}
+	}
+}
+
+// orientContour returns the specified contour in counter clockwise order
+// if ccw is true or in clockwise order otherwise
+func orientContour(contour []math32.Vector2, ccw bool) []math32.Vector2 {
+
+	var area float32
+	for i := range contour {
+		p1 := &contour[i]
+		p2 := &contour[(i+1)%len(contour)]
+		area += p1.X*p2.Y - p2.X*p1.Y
+	}
+	if (area > 0) == ccw || area == 0 {
+		return contour
+	}
+	reversed := make([]math32.Vector2, len(contour))
+	for i := range contour {
+		reversed[len(contour)-1-i] = contour[i]
+	}
+	return reversed
+}
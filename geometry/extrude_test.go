@@ -0,0 +1,90 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+// reversed returns a copy of the specified contour in the opposite order
+func reversed(contour []math32.Vector2) []math32.Vector2 {
+
+	r := make([]math32.Vector2, len(contour))
+	for i := range contour {
+		r[len(contour)-1-i] = contour[i]
+	}
+	return r
+}
+
+func TestExtrude(t *testing.T) {
+
+	square := []math32.Vector2{{-2, -2}, {2, -2}, {2, 2}, {-2, 2}}
+	hole := []math32.Vector2{{-1, -1}, {-1, 1}, {1, 1}, {1, -1}}
+	cases := []struct {
+		name      string
+		contour   []math32.Vector2
+		holes     [][]math32.Vector2
+		bevel     *BevelOptions
+		capTris   int     // triangles of each cap
+		capArea   float32 // area of each cap
+		outlineXY float32 // maximum absolute x and y of the walls
+	}{
+		{"square", square, nil, nil, 2, 16, 2},
+		{"clockwise square", reversed(square), nil, nil, 2, 16, 2},
+		{"square with hole", square, [][]math32.Vector2{hole}, nil, 8, 12, 2},
+		{"counter clockwise hole", square, [][]math32.Vector2{reversed(hole)}, nil, 8, 12, 2},
+		{"beveled", square, [][]math32.Vector2{hole}, &BevelOptions{Thickness: 0.2, Size: 0.1, Segments: 3}, 8, 12, 2.1},
+	}
+	for _, c := range cases {
+		e := NewExtrude(c.contour, c.holes, 1, c.bevel)
+		g := &e.Geometry
+		checkTriangles(t, c.name, g)
+		if g.GroupCount() != 2 {
+			t.Errorf("%s: %d groups, want 2", c.name, g.GroupCount())
+			continue
+		}
+
+		// Each cap covers the shape area
+		caps := g.GroupAt(0)
+		if caps.Count != 2*3*c.capTris {
+			t.Errorf("%s: %d cap indices, want %d", c.name, caps.Count, 2*3*c.capTris)
+		}
+		positions := vectors(g, "VertexPosition")
+		normals := vectors(g, "VertexNormal")
+		indices := g.Indices()
+		var front, back float32
+		for i := caps.Start; i < caps.Start+caps.Count; i += 3 {
+			a, b, cc := &positions[indices[i]], &positions[indices[i+1]], &positions[indices[i+2]]
+			var e1, e2, n math32.Vector3
+			e1.SubVectors(b, a)
+			e2.SubVectors(cc, a)
+			n.CrossVectors(&e1, &e2)
+			if a.Z == 0 {
+				back -= n.Z / 2
+			} else {
+				front += n.Z / 2
+			}
+		}
+		if !near(front, c.capArea) || !near(back, c.capArea) {
+			t.Errorf("%s: front cap area %v and back cap area %v, want %v", c.name, front, back, c.capArea)
+		}
+
+		// The cap normals are along Z
+		for i := caps.Start; i < caps.Start+caps.Count; i++ {
+			n := normals[indices[i]]
+			if n.X != 0 || n.Y != 0 || math32.Abs(n.Z) != 1 {
+				t.Errorf("%s: cap normal %v", c.name, n)
+				break
+			}
+		}
+		bbox := g.BoundingBox()
+		want := math32.Box3{Min: math32.Vector3{-c.outlineXY, -c.outlineXY, 0}, Max: math32.Vector3{c.outlineXY, c.outlineXY, 1}}
+		if !nearVector3(&bbox.Min, &want.Min) || !nearVector3(&bbox.Max, &want.Max) {
+			t.Errorf("%s: bounding box %v, want %v", c.name, bbox, want)
+		}
+	}
+}
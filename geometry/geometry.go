@@ -17,18 +17,19 @@ type IGeometry interface {
 }
 
 type Geometry struct {
-	refcount            int             // Current number of references
-	vbos                []*gls.VBO      // Array of VBOs
-	groups              []Group         // Array geometry groups
-	indices             math32.ArrayU32 // Buffer with indices
-	gs                  *gls.GLS        // Pointer to gl context. Valid after first render setup
-	handleVAO           uint32          // Handle to OpenGL VAO
-	handleIndices       uint32          // Handle to OpenGL buffer for indices
-	updateIndices       bool            // Flag to indicate that indices must be transferred
-	boundingBox         math32.Box3     // Last calculated bounding box
-	boundingBoxValid    bool            // Indicates if last calculated bounding box is valid
-	boundingSphere      math32.Sphere   // Last calculated bounding sphere
-	boundingSphereValid bool            // Indicates if last calculated bounding sphere is valid
+	refcount            int                 // Current number of references
+	vbos                []*gls.VBO          // Array of VBOs
+	groups              []Group             // Array geometry groups
+	indices             math32.ArrayU32     // Buffer with indices
+	gs                  *gls.GLS            // Pointer to gl context. Valid after first render setup
+	vaos                map[*gls.GLS]uint32 // Handles to OpenGL VAOs of each context
+	handleIndices       uint32              // Handle to OpenGL buffer for indices
+	updateIndices       bool                // Flag to indicate that indices must be transferred
+	boundingBox         math32.Box3         // Last calculated bounding box
+	boundingBoxValid    bool                // Indicates if last calculated bounding box is valid
+	boundingSphere      math32.Sphere       // Last calculated bounding sphere
+	boundingSphereValid bool                // Indicates if last calculated bounding sphere is valid
+	boundsVersion       uint32              // Version of the positions VBO for the bounding box and sphere
 }
 
 // Geometry group object
@@ -53,7 +54,7 @@ func (g *Geometry) Init() {
 	g.vbos = make([]*gls.VBO, 0)
 	g.groups = make([]Group, 0)
 	g.gs = nil
-	g.handleVAO = 0
+	g.vaos = make(map[*gls.GLS]uint32)
 	g.handleIndices = 0
 	g.updateIndices = true
 }
@@ -78,9 +79,11 @@ func (g *Geometry) Dispose() {
 		return
 	}
 
-	// Delete VAO and indices buffer
+	// Delete VAOs and indices buffer
+	for gs, vao := range g.vaos {
+		gs.DeleteVertexArrays(vao)
+	}
 	if g.gs != nil {
-		g.gs.DeleteVertexArrays(g.handleVAO)
 		g.gs.DeleteBuffers(g.handleIndices)
 	}
 	// Delete this geometry VBO buffers
@@ -122,12 +125,12 @@ func (g *Geometry) GroupAt(idx int) *Group {
 	return &g.groups[idx]
 }
 
-// SetIndices sets the indices array for this geometry
+// SetIndices sets the indices array for this geometry.
+// The bounds do not depend on the indices and are not invalidated.
 func (g *Geometry) SetIndices(indices math32.ArrayU32) {
 
 	g.indices = indices
-	g.boundingBoxValid = false
-	g.boundingSphereValid = false
+	g.updateIndices = true
 }
 
 // Indices returns this geometry indices array
@@ -140,6 +143,9 @@ func (g *Geometry) Indices() math32.ArrayU32 {
 func (g *Geometry) AddVBO(vbo *gls.VBO) {
 
 	g.vbos = append(g.vbos, vbo)
+	if vbo.Attrib("VertexPosition") != nil {
+		g.InvalidateBounds()
+	}
 }
 
 // VBO returns a pointer to this geometry VBO for the specified attribute.
@@ -170,10 +176,12 @@ func (g *Geometry) Items() int {
 }
 
 // BoundingBox computes the bounding box of the geometry if necessary
-// and returns is value
+// and returns is value. The bounding box is cached until the buffer of the
+// positions VBO is set or updated or InvalidateBounds is called.
 func (g *Geometry) BoundingBox() math32.Box3 {
 
 	// If valid, returns its value
+	g.checkBounds()
 	if g.boundingBoxValid {
 		return g.boundingBox
 	}
@@ -187,21 +195,26 @@ func (g *Geometry) BoundingBox() math32.Box3 {
 
 	// Calculates bounding box
 	var vertex math32.Vector3
-	g.boundingBox.Min.Set(0, 0, 0)
-	g.boundingBox.Max.Set(0, 0, 0)
+	g.boundingBox.MakeEmpty()
 	for i := 0; i < positions.Size(); i += 3 {
 		positions.GetVector3(i, &vertex)
 		g.boundingBox.ExpandByPoint(&vertex)
 	}
+	if positions.Size() < 3 {
+		g.boundingBox.Min.Set(0, 0, 0)
+		g.boundingBox.Max.Set(0, 0, 0)
+	}
 	g.boundingBoxValid = true
 	return g.boundingBox
 }
 
 // BoundingSphere computes the bounding sphere of this geometry
-// if necessary and returns its value.
+// if necessary and returns its value. The bounding sphere is cached until the
+// buffer of the positions VBO is set or updated or InvalidateBounds is called.
 func (g *Geometry) BoundingSphere() math32.Sphere {
 
 	// if valid, returns its value
+	g.checkBounds()
 	if g.boundingSphereValid {
 		return g.boundingSphere
 	}
@@ -236,6 +249,48 @@ func (g *Geometry) BoundingSphere() math32.Sphere {
 	return g.boundingSphere
 }
 
+// SetBoundingBox sets the bounding box of this geometry which is used
+// instead of the one computed from the vertex positions until the buffer
+// of the positions VBO is set or updated or InvalidateBounds is called.
+func (g *Geometry) SetBoundingBox(box math32.Box3) {
+
+	g.checkBounds()
+	g.boundingBox = box
+	g.boundingBoxValid = true
+}
+
+// SetBoundingSphere sets the bounding sphere of this geometry which is used
+// instead of the one computed from the vertex positions until the buffer of
+// the positions VBO is set or updated or InvalidateBounds is called. It is
+// useful when the vertex positions do not correspond to the rendered shape,
+// as for skinned meshes which are deformed by the shader.
+func (g *Geometry) SetBoundingSphere(sphere math32.Sphere) {
+
+	g.checkBounds()
+	g.boundingSphere = sphere
+	g.boundingSphereValid = true
+}
+
+// InvalidateBounds forces the recalculation of the bounding box and sphere
+// of this geometry the next time they are requested. It is not needed after
+// the buffer of the positions VBO is set or its Update method is called.
+func (g *Geometry) InvalidateBounds() {
+
+	g.boundingBoxValid = false
+	g.boundingSphereValid = false
+}
+
+// checkBounds invalidates the bounding box and sphere if the buffer
+// of the positions VBO was set or updated after they were calculated or set
+func (g *Geometry) checkBounds() {
+
+	vbo := g.VBO("VertexPosition")
+	if vbo != nil && vbo.Version() != g.boundsVersion {
+		g.InvalidateBounds()
+		g.boundsVersion = vbo.Version()
+	}
+}
+
 // ApplyMatrix multiplies each of the geometry position vertices
 // by the specified matrix and apply the correspondent normal
 // transform matrix to the geometry normal vectors.
@@ -256,6 +311,7 @@ func (g *Geometry) ApplyMatrix(m *math32.Matrix4) {
 		positions.SetVector3(i, &vertex)
 	}
 	vboPos.Update()
+	g.InvalidateBounds()
 
 	// Get normals buffer
 	vboNormals := g.VBO("VertexNormal")
@@ -275,22 +331,183 @@ func (g *Geometry) ApplyMatrix(m *math32.Matrix4) {
 	vboNormals.Update()
 }
 
-// RenderSetup is called by the renderer before drawing the geometry
+// ComputeTangents calculates the tangents of the geometry vertices from their
+// positions, normals and texture coordinates and stores them in the
+// "VertexTangent" attribute for normal mapping. Each tangent has 4 components:
+// the unit tangent orthogonal to the vertex normal in the direction of the
+// increasing u texture coordinate and the handedness (1 or -1) which
+// multiplies the cross product of the normal and the tangent to obtain the
+// bitangent. The tangents of the vertices shared by several triangles are
+// averaged. Does nothing if the geometry has no positions, normals or
+// texture coordinates.
+func (g *Geometry) ComputeTangents() {
+
+	vboPos := g.VBO("VertexPosition")
+	vboNormals := g.VBO("VertexNormal")
+	vboUvs := g.VBO("VertexTexcoord")
+	if vboPos == nil || vboNormals == nil || vboUvs == nil {
+		return
+	}
+	positions := vboPos.Buffer()
+	normals := vboNormals.Buffer()
+	uvs := vboUvs.Buffer()
+	count := positions.Size() / 3
+
+	// Accumulates the u and v directions of the triangles of each vertex
+	tan1 := make([]math32.Vector3, count)
+	tan2 := make([]math32.Vector3, count)
+	triangles := g.indices.Size() / 3
+	if g.indices.Size() == 0 {
+		triangles = count / 3
+	}
+	var p0, p1, p2, e1, e2, sdir, tdir math32.Vector3
+	var uv0, uv1, uv2 math32.Vector2
+	for t := 0; t < triangles; t++ {
+		i0, i1, i2 := 3*t, 3*t+1, 3*t+2
+		if g.indices.Size() > 0 {
+			i0, i1, i2 = int(g.indices[i0]), int(g.indices[i1]), int(g.indices[i2])
+		}
+		positions.GetVector3(i0*3, &p0)
+		positions.GetVector3(i1*3, &p1)
+		positions.GetVector3(i2*3, &p2)
+		uvs.GetVector2(i0*2, &uv0)
+		uvs.GetVector2(i1*2, &uv1)
+		uvs.GetVector2(i2*2, &uv2)
+		e1.SubVectors(&p1, &p0)
+		e2.SubVectors(&p2, &p0)
+		du1, dv1 := uv1.X-uv0.X, uv1.Y-uv0.Y
+		du2, dv2 := uv2.X-uv0.X, uv2.Y-uv0.Y
+		r := du1*dv2 - du2*dv1
+		// Ignores triangles with degenerate texture coordinates
+		if math32.Abs(r) < 1e-12 {
+			continue
+		}
+		r = 1 / r
+		sdir.Set((e1.X*dv2-e2.X*dv1)*r, (e1.Y*dv2-e2.Y*dv1)*r, (e1.Z*dv2-e2.Z*dv1)*r)
+		tdir.Set((e2.X*du1-e1.X*du2)*r, (e2.Y*du1-e1.Y*du2)*r, (e2.Z*du1-e1.Z*du2)*r)
+		for _, i := range [3]int{i0, i1, i2} {
+			tan1[i].Add(&sdir)
+			tan2[i].Add(&tdir)
+		}
+	}
+
+	// Orthonormalizes the tangents against the normals (Gram-Schmidt)
+	// and calculates the handedness from the v directions
+	tangents := math32.NewArrayF32(0, count*4)
+	var n, tangent, tmp math32.Vector3
+	for i := 0; i < count; i++ {
+		normals.GetVector3(i*3, &n)
+		tangent = tan1[i]
+		tmp = n
+		tangent.Sub(tmp.MultiplyScalar(n.Dot(&tangent)))
+		if tangent.LengthSq() < 1e-12 {
+			// Uses any direction orthogonal to the normal
+			if math32.Abs(n.X) < 0.9 {
+				tmp.Set(1, 0, 0)
+			} else {
+				tmp.Set(0, 1, 0)
+			}
+			tangent.CrossVectors(&tmp, &n)
+		}
+		tangent.Normalize()
+		w := float32(1)
+		if tmp.CrossVectors(&n, &tangent).Dot(&tan2[i]) < 0 {
+			w = -1
+		}
+		tangents.Append(tangent.X, tangent.Y, tangent.Z, w)
+	}
+
+	// Sets the tangents VBO
+	vboTangents := g.VBO("VertexTangent")
+	if vboTangents == nil {
+		g.AddVBO(gls.NewVBO().AddAttrib("VertexTangent", 4).SetBuffer(tangents))
+		return
+	}
+	vboTangents.SetBuffer(tangents)
+}
+
+// ComputeFlatNormals sets the normals of the geometry vertices to the normals
+// of their triangles for faceted shading. The vertices shared by several
+// triangles are duplicated, so each triangle has its own vertices and the
+// indices become sequential, keeping the order of the triangles and the groups.
+// The "VertexNormal" attribute is added if the geometry has no normals.
+func (g *Geometry) ComputeFlatNormals() {
+
+	vboPos := g.VBO("VertexPosition")
+	if vboPos == nil {
+		return
+	}
+
+	// Duplicates the vertices of all VBOs in the order of the indices
+	if g.indices.Size() > 0 {
+		for _, vbo := range g.vbos {
+			floats := vbo.Stride() / 4
+			src := *vbo.Buffer()
+			dst := math32.NewArrayF32(0, g.indices.Size()*floats)
+			for _, idx := range g.indices {
+				dst = append(dst, src[int(idx)*floats:(int(idx)+1)*floats]...)
+			}
+			vbo.SetBuffer(dst)
+		}
+		indices := math32.NewArrayU32(g.indices.Size(), g.indices.Size())
+		for i := range indices {
+			indices[i] = uint32(i)
+		}
+		g.SetIndices(indices)
+	}
+
+	// Calculates the normal of each triangle
+	positions := vboPos.Buffer()
+	count := positions.Size() / 3
+	normals := math32.NewArrayF32(count*3, count*3)
+	var p0, p1, p2, e1, e2, normal math32.Vector3
+	for i := 0; i+2 < count; i += 3 {
+		positions.GetVector3(i*3, &p0)
+		positions.GetVector3((i+1)*3, &p1)
+		positions.GetVector3((i+2)*3, &p2)
+		e1.SubVectors(&p1, &p0)
+		e2.SubVectors(&p2, &p0)
+		normal.CrossVectors(&e1, &e2).Normalize()
+		normals.SetVector3(i*3, &normal)
+		normals.SetVector3((i+1)*3, &normal)
+		normals.SetVector3((i+2)*3, &normal)
+	}
+
+	// Sets the normals VBO
+	vboNormals := g.VBO("VertexNormal")
+	if vboNormals == nil {
+		g.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(normals))
+		return
+	}
+	vboNormals.SetBuffer(normals)
+}
+
+// RenderSetup is called by the renderer before drawing the geometry.
+// The buffers of the geometry are shared by the windows but vertex array
+// objects are not, so one is created in the context of each renderer which
+// draws the geometry. It may also be called before any program is used to
+// transfer the vertex buffers before the first draw.
 func (g *Geometry) RenderSetup(gs *gls.GLS) {
 
 	// First time initialization
 	if g.gs == nil {
-		// Generates VAO and binds it
-		g.handleVAO = gs.GenVertexArray()
-		gs.BindVertexArray(g.handleVAO)
 		// Generates VBO for indices
 		g.handleIndices = gs.GenBuffer()
 		// Saves pointer to gl indicating initialization was done.
 		g.gs = gs
 	}
 
+	// Generates the VAO of this context and binds the shared indices buffer to it
+	vao, ok := g.vaos[gs]
+	if !ok {
+		vao = gs.GenVertexArray()
+		g.vaos[gs] = vao
+		gs.BindVertexArray(vao)
+		gs.BindBuffer(gls.ELEMENT_ARRAY_BUFFER, g.handleIndices)
+	}
+
 	// Update VBOs
-	gs.BindVertexArray(g.handleVAO)
+	gs.BindVertexArray(vao)
 	for _, vbo := range g.vbos {
 		vbo.Transfer(gs)
 	}
@@ -0,0 +1,226 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+const testEpsilon = 1e-4
+
+// near returns if the specified values are equal within testEpsilon
+func near(a, b float32) bool {
+
+	return math32.Abs(a-b) <= testEpsilon
+}
+
+// nearVector3 returns if the specified vectors are equal within testEpsilon
+func nearVector3(a, b *math32.Vector3) bool {
+
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Z, b.Z)
+}
+
+// vectors returns the 3D vectors of the specified attribute of the geometry
+func vectors(g *Geometry, attrib string) []math32.Vector3 {
+
+	vbo := g.VBO(attrib)
+	if vbo == nil {
+		return nil
+	}
+	buf := *vbo.Buffer()
+	vs := make([]math32.Vector3, buf.Size()/3)
+	for i := range vs {
+		buf.GetVector3(3*i, &vs[i])
+	}
+	return vs
+}
+
+// checkTriangles checks that the indices of the geometry reference its
+// vertices in complete triangles and, if the geometry has normals, that the
+// triangles are counter clockwise when seen from the side of their normals.
+func checkTriangles(t *testing.T, name string, g *Geometry) {
+
+	positions := vectors(g, "VertexPosition")
+	normals := vectors(g, "VertexNormal")
+	indices := g.Indices()
+	if indices.Size()%3 != 0 {
+		t.Errorf("%s: %d indices is not a multiple of 3", name, indices.Size())
+		return
+	}
+	for i := 0; i < indices.Size(); i += 3 {
+		a, b, c := indices[i], indices[i+1], indices[i+2]
+		if int(a) >= len(positions) || int(b) >= len(positions) || int(c) >= len(positions) {
+			t.Errorf("%s: triangle %d with indices %d %d %d out of %d vertices", name, i/3, a, b, c, len(positions))
+			return
+		}
+		if normals == nil {
+			continue
+		}
+		var e1, e2, face, vn math32.Vector3
+		e1.SubVectors(&positions[b], &positions[a])
+		e2.SubVectors(&positions[c], &positions[a])
+		face.CrossVectors(&e1, &e2)
+		if face.Length() < 1e-9 {
+			continue
+		}
+		vn.Copy(&normals[a]).Add(&normals[b]).Add(&normals[c])
+		if face.Dot(&vn) <= 0 {
+			t.Errorf("%s: triangle %d is clockwise seen from its normals", name, i/3)
+			return
+		}
+	}
+}
+
+func TestComputeTangents(t *testing.T) {
+
+	var rot math32.Matrix4
+	rot.MakeRotationFromQuaternion(new(math32.Quaternion).SetFromEuler(&math32.Vector3{0.5, -0.3, 1.2}))
+	cases := []struct {
+		name    string
+		mirror  bool
+		matrix  *math32.Matrix4
+		tangent math32.Vector3
+		w       float32
+	}{
+		{"quad", false, nil, math32.Vector3{1, 0, 0}, 1},
+		{"mirrored quad", true, nil, math32.Vector3{-1, 0, 0}, -1},
+		{"rotated quad", false, &rot, *new(math32.Vector3).Set(1, 0, 0).ApplyMatrix4(&rot), 1},
+	}
+	for _, c := range cases {
+		plane := NewPlane(2, 2, 2, 2)
+		g := &plane.Geometry
+		if c.mirror {
+			uvs := *g.VBO("VertexTexcoord").Buffer()
+			for i := 0; i < len(uvs); i += 2 {
+				uvs[i] = 1 - uvs[i]
+			}
+		}
+		if c.matrix != nil {
+			g.ApplyMatrix(c.matrix)
+		}
+		g.ComputeTangents()
+		vbo := g.VBO("VertexTangent")
+		if vbo == nil {
+			t.Fatalf("%s: no VertexTangent VBO", c.name)
+		}
+		tangents := *vbo.Buffer()
+		normals := vectors(g, "VertexNormal")
+		if len(tangents) != 4*len(normals) {
+			t.Fatalf("%s: %d tangent floats for %d vertices", c.name, len(tangents), len(normals))
+		}
+		for i := range normals {
+			tangent := math32.Vector3{tangents[4*i], tangents[4*i+1], tangents[4*i+2]}
+			w := tangents[4*i+3]
+			if !near(tangent.Length(), 1) || !near(tangent.Dot(&normals[i]), 0) {
+				t.Errorf("%s: tangent %d = %v not orthonormal to %v", c.name, i, tangent, normals[i])
+			}
+			if !nearVector3(&tangent, &c.tangent) || w != c.w {
+				t.Errorf("%s: tangent %d = %v %v, want %v %v", c.name, i, tangent, w, c.tangent, c.w)
+			}
+		}
+	}
+}
+
+func TestComputeTangentsWithoutTexcoords(t *testing.T) {
+
+	g := NewGeometry()
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(math32.ArrayF32{0, 0, 0, 1, 0, 0, 0, 1, 0}))
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(math32.ArrayF32{0, 0, 1, 0, 0, 1, 0, 0, 1}))
+	g.ComputeTangents()
+	if g.VBO("VertexTangent") != nil {
+		t.Errorf("ComputeTangents added tangents to a geometry without texture coordinates")
+	}
+}
+
+func TestComputeFlatNormals(t *testing.T) {
+
+	// Two triangles folded along their shared edge from (0,0,0) to (0,1,0)
+	g := NewGeometry()
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(math32.ArrayF32{
+		0, 0, 0, 0, 1, 0, -1, 0, 1, 1, 0, 1,
+	}))
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 2).SetBuffer(math32.ArrayF32{0.5, 0, 0.5, 1, 0, 0, 1, 0}))
+	g.SetIndices(math32.ArrayU32{0, 2, 1, 0, 1, 3})
+	g.AddGroup(3, 3, 1)
+	g.ComputeFlatNormals()
+
+	positions := vectors(g, "VertexPosition")
+	normals := vectors(g, "VertexNormal")
+	indices := g.Indices()
+	if len(positions) != 6 || len(normals) != 6 || len(indices) != 6 {
+		t.Fatalf("%d positions, %d normals and %d indices, want 6", len(positions), len(normals), len(indices))
+	}
+	for i, idx := range indices {
+		if idx != uint32(i) {
+			t.Fatalf("indices = %v, want sequential", indices)
+		}
+	}
+	if uvs := *g.VBO("VertexTexcoord").Buffer(); uvs[2*3] != 0.5 || uvs[2*5] != 1 {
+		t.Errorf("texture coordinates not duplicated with the vertices: %v", uvs)
+	}
+	if grp := g.GroupAt(0); grp.Start != 3 || grp.Count != 3 {
+		t.Errorf("group = %+v, want start 3 count 3", *grp)
+	}
+	checkTriangles(t, "flat", g)
+
+	// Each triangle has its own constant normal
+	want := []math32.Vector3{*math32.NewVector3(-1, 0, -1).Normalize(), *math32.NewVector3(1, 0, -1).Normalize()}
+	for tri := 0; tri < 2; tri++ {
+		for k := 0; k < 3; k++ {
+			if n := normals[3*tri+k]; !nearVector3(&n, &want[tri]) {
+				t.Errorf("normal %d of triangle %d = %v, want %v", k, tri, n, want[tri])
+			}
+		}
+	}
+}
+
+func TestGeometryBoundsInvalidation(t *testing.T) {
+
+	g := triangle()
+	vbo := g.VBO("VertexPosition")
+	checkBox := func(step string, min, max math32.Vector3) {
+		box := g.BoundingBox()
+		if !nearVector3(&box.Min, &min) || !nearVector3(&box.Max, &max) {
+			t.Errorf("%s: bounding box %v, want %v %v", step, box, min, max)
+		}
+	}
+	checkSphere := func(step string, center math32.Vector3, radius float32) {
+		sphere := g.BoundingSphere()
+		if !nearVector3(&sphere.Center, &center) || !near(sphere.Radius, radius) {
+			t.Errorf("%s: bounding sphere %v, want %v %v", step, sphere, center, radius)
+		}
+	}
+	checkBox("initial", math32.Vector3{0, 0, 0}, math32.Vector3{1, 1, 0})
+
+	// Setting the buffer invalidates the cached bounds
+	vbo.SetBuffer(math32.ArrayF32{0, 0, 0, 4, 0, 0, 0, 2, 0})
+	checkBox("SetBuffer", math32.Vector3{0, 0, 0}, math32.Vector3{4, 2, 0})
+	checkSphere("SetBuffer", math32.Vector3{2, 1, 0}, math32.Sqrt(5))
+
+	// Changing the buffer and updating the VBO invalidates the cached bounds
+	(*vbo.Buffer())[0] = -2
+	vbo.Update()
+	checkBox("Update", math32.Vector3{-2, 0, 0}, math32.Vector3{4, 2, 0})
+
+	// A bounding sphere set by the user is kept until the positions change
+	user := math32.Sphere{Center: math32.Vector3{0, 0, 0}, Radius: 10}
+	g.SetBoundingSphere(user)
+	g.SetIndices(math32.ArrayU32{0, 1, 2})
+	checkSphere("SetIndices", user.Center, user.Radius)
+	g.VBO("VertexNormal").Update()
+	checkSphere("normals Update", user.Center, user.Radius)
+	vbo.Update()
+	checkSphere("positions Update", math32.Vector3{1, 1, 0}, math32.Sqrt(10))
+
+	// A bounding box set by the user is kept until the positions change
+	userBox := math32.Box3{Min: math32.Vector3{-5, -5, -5}, Max: math32.Vector3{5, 5, 5}}
+	g.SetBoundingBox(userBox)
+	checkBox("SetBoundingBox", userBox.Min, userBox.Max)
+	g.ApplyMatrix(new(math32.Matrix4).MakeTranslation(0, 0, 1))
+	checkBox("ApplyMatrix", math32.Vector3{-2, 0, 1}, math32.Vector3{4, 2, 1})
+}
@@ -0,0 +1,101 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+	"math"
+)
+
+type Lathe struct {
+	Geometry
+	Points    []math32.Vector2
+	Segments  int
+	PhiStart  float64
+	PhiLength float64
+}
+
+// NewLathe creates and returns a pointer to a new Lathe geometry object.
+// The geometry is the revolution around the Y axis of the profile defined
+// by the specified points, where X is the distance from the axis and Y the
+// height, from the phiStart angle by phiLength radians divided in the
+// specified number of segments. The normals point to the left of the profile
+// when going from the first to the last point, which is outward for a profile
+// going up. For a full revolution the first and last vertices of each point
+// ring have the same positions and normals, so the seam is smooth.
+func NewLathe(points []math32.Vector2, segments int, phiStart, phiLength float64) *Lathe {
+
+	l := new(Lathe)
+	l.Geometry.Init()
+
+	if segments < 1 {
+		segments = 1
+	}
+	l.Points = points
+	l.Segments = segments
+	l.PhiStart = phiStart
+	l.PhiLength = phiLength
+
+	count := len(points)
+	vertexCount := (segments + 1) * count
+
+	// Create buffers
+	positions := math32.NewArrayF32(0, vertexCount*3)
+	normals := math32.NewArrayF32(0, vertexCount*3)
+	uvs := math32.NewArrayF32(0, vertexCount*2)
+	indices := math32.NewArrayU32(0, segments*count*6)
+
+	// Calculates the profile normals averaging the normals of the adjacent segments
+	pnormals := make([]math32.Vector2, count)
+	for j := 0; j < count-1; j++ {
+		dx := points[j+1].X - points[j].X
+		dy := points[j+1].Y - points[j].Y
+		n := math32.Vector2{X: dy, Y: -dx}
+		n.Normalize()
+		pnormals[j].Add(&n)
+		pnormals[j+1].Add(&n)
+	}
+	for j := range pnormals {
+		pnormals[j].Normalize()
+	}
+
+	// Generates the vertices, normals and uv coordinates
+	full := math.Abs(phiLength) >= 2*math.Pi
+	for i := 0; i <= segments; i++ {
+		phi := phiStart + float64(i)/float64(segments)*phiLength
+		if full && i == segments {
+			phi = phiStart
+		}
+		sin := float32(math.Sin(phi))
+		cos := float32(math.Cos(phi))
+		for j := 0; j < count; j++ {
+			positions.Append(points[j].X*sin, points[j].Y, points[j].X*cos)
+			var normal math32.Vector3
+			normal.Set(pnormals[j].X*sin, pnormals[j].Y, pnormals[j].X*cos).Normalize()
+			normals.AppendVector3(&normal)
+			uvs.Append(float32(i)/float32(segments), float32(j)/float32(math32.Max(float32(count-1), 1)))
+		}
+	}
+
+	// Generates the indices
+	for i := 0; i < segments; i++ {
+		for j := 0; j < count-1; j++ {
+			base := j + i*count
+			a := base
+			b := base + count
+			c := base + count + 1
+			d := base + 1
+			indices.Append(uint32(a), uint32(b), uint32(d), uint32(c), uint32(d), uint32(b))
+		}
+	}
+
+	l.SetIndices(indices)
+	l.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(positions))
+	l.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(normals))
+	l.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 2).SetBuffer(uvs))
+
+	return l
+}
@@ -0,0 +1,61 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestLathe(t *testing.T) {
+
+	// Vase profile going up
+	profile := []math32.Vector2{{0.5, 0}, {1, 0.5}, {0.8, 1.5}, {0.3, 2}, {0.4, 2.5}}
+	cases := []struct {
+		name      string
+		segments  int
+		phiStart  float64
+		phiLength float64
+		full      bool
+	}{
+		{"full", 16, 0, 2 * math.Pi, true},
+		{"full with offset", 7, 1, 2 * math.Pi, true},
+		{"half", 8, 0, math.Pi, false},
+	}
+	for _, c := range cases {
+		lathe := NewLathe(profile, c.segments, c.phiStart, c.phiLength)
+		g := &lathe.Geometry
+		positions := vectors(g, "VertexPosition")
+		normals := vectors(g, "VertexNormal")
+		count := len(profile)
+		if want := (c.segments + 1) * count; len(positions) != want {
+			t.Errorf("%s: %d vertices, want %d", c.name, len(positions), want)
+		}
+		if want := c.segments * (count - 1) * 6; len(g.Indices()) != want {
+			t.Errorf("%s: %d indices, want %d", c.name, len(g.Indices()), want)
+		}
+		checkTriangles(t, c.name, g)
+
+		// The vertices are at the profile distance from the Y axis and height
+		for i, p := range positions {
+			pp := profile[i%count]
+			if !near(math32.Sqrt(p.X*p.X+p.Z*p.Z), pp.X) || !near(p.Y, pp.Y) {
+				t.Errorf("%s: vertex %d = %v not on the profile point %v", c.name, i, p, pp)
+				break
+			}
+		}
+
+		// The first and last rings coincide for a full revolution
+		last := c.segments * count
+		for j := 0; j < count; j++ {
+			same := nearVector3(&positions[j], &positions[last+j]) && nearVector3(&normals[j], &normals[last+j])
+			if same != c.full {
+				t.Errorf("%s: first and last vertex %d: %v %v, want coincident %v", c.name, j, positions[j], positions[last+j], c.full)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// Merge creates and returns a pointer to a new geometry with the vertices,
+// indices and groups of all the specified geometries, so they can be rendered
+// with a single draw call for each material.
+// If matrices is not nil it must have one matrix for each geometry (or nil for
+// the identity) which is applied to its positions, normals and tangents.
+// All the geometries must have the same VBOs with the same attributes in the
+// same order, otherwise an error is returned. If some of the geometries have
+// indices, sequential indices are generated for the ones which have not.
+// The index and group offsets are adjusted for the position of each geometry
+// in the merged buffers.
+func Merge(geoms []*Geometry, matrices []*math32.Matrix4) (*Geometry, error) {
+
+	if len(geoms) == 0 {
+		return nil, fmt.Errorf("no geometries to merge")
+	}
+	if matrices != nil && len(matrices) != len(geoms) {
+		return nil, fmt.Errorf("number of matrices:%d different from number of geometries:%d", len(matrices), len(geoms))
+	}
+
+	// Checks that all geometries have the same vertex attributes
+	first := geoms[0]
+	indexed := false
+	for gi, g := range geoms {
+		if len(g.vbos) != len(first.vbos) {
+			return nil, fmt.Errorf("geometry:%d has %d VBOs instead of %d", gi, len(g.vbos), len(first.vbos))
+		}
+		for vi, vbo := range g.vbos {
+			fvbo := first.vbos[vi]
+			if vbo.AttribCount() != fvbo.AttribCount() {
+				return nil, fmt.Errorf("geometry:%d VBO:%d has %d attributes instead of %d", gi, vi, vbo.AttribCount(), fvbo.AttribCount())
+			}
+			for ai := 0; ai < vbo.AttribCount(); ai++ {
+				if *vbo.AttribAt(ai) != *fvbo.AttribAt(ai) {
+					return nil, fmt.Errorf("geometry:%d VBO:%d attribute:%s different from %s", gi, vi, vbo.AttribAt(ai).Name, fvbo.AttribAt(ai).Name)
+				}
+			}
+		}
+		if g.indices.Size() > 0 {
+			indexed = true
+		}
+	}
+
+	// Concatenates the buffers, indices and groups
+	buffers := make([]math32.ArrayF32, len(first.vbos))
+	indices := math32.NewArrayU32(0, 0)
+	groups := make([]Group, 0)
+	var vertexOffset int
+	for gi, g := range geoms {
+		count := g.Items()
+		for vi, vbo := range g.vbos {
+			start := len(buffers[vi])
+			buffers[vi] = append(buffers[vi], *vbo.Buffer()...)
+			if matrices != nil && matrices[gi] != nil {
+				transformBuffer(vbo, buffers[vi][start:], matrices[gi])
+			}
+		}
+		elemOffset := vertexOffset
+		if indexed {
+			elemOffset = indices.Size()
+			if g.indices.Size() > 0 {
+				for _, idx := range g.indices {
+					indices.Append(idx + uint32(vertexOffset))
+				}
+			} else {
+				for i := 0; i < count; i++ {
+					indices.Append(uint32(vertexOffset + i))
+				}
+			}
+		}
+		for _, grp := range g.groups {
+			grp.Start += elemOffset
+			groups = append(groups, grp)
+		}
+		vertexOffset += count
+	}
+
+	merged := NewGeometry()
+	for vi, fvbo := range first.vbos {
+		vbo := gls.NewVBO()
+		for ai := 0; ai < fvbo.AttribCount(); ai++ {
+			attrib := fvbo.AttribAt(ai)
+			vbo.AddAttrib(attrib.Name, attrib.ItemSize)
+		}
+		merged.AddVBO(vbo.SetBuffer(buffers[vi]))
+	}
+	if indexed {
+		merged.SetIndices(indices)
+	}
+	merged.AddGroupList(groups)
+	return merged, nil
+}
+
+// transformBuffer applies the specified matrix to the positions, normals and
+// tangents in the specified buffer with the attributes of the specified VBO
+func transformBuffer(vbo *gls.VBO, buffer math32.ArrayF32, m *math32.Matrix4) {
+
+	var normalMatrix math32.Matrix3
+	normalMatrix.GetNormalMatrix(m)
+	mirror := m.Determinant() < 0
+
+	// Calculates the number of floats of all the attributes of one vertex
+	stride := 0
+	for ai := 0; ai < vbo.AttribCount(); ai++ {
+		stride += int(vbo.AttribAt(ai).ItemSize)
+	}
+	offset := 0
+	for ai := 0; ai < vbo.AttribCount(); ai++ {
+		attrib := vbo.AttribAt(ai)
+		var v math32.Vector3
+		for i := offset; i+2 < len(buffer); i += stride {
+			switch attrib.Name {
+			case "VertexPosition":
+				buffer.GetVector3(i, &v)
+				buffer.SetVector3(i, v.ApplyMatrix4(m))
+			case "VertexNormal":
+				buffer.GetVector3(i, &v)
+				buffer.SetVector3(i, v.ApplyMatrix3(&normalMatrix).Normalize())
+			case "VertexTangent":
+				buffer.GetVector3(i, &v)
+				buffer.SetVector3(i, v.TransformDirection(m))
+				// Mirroring transforms change the handedness of the tangent space
+				if mirror && attrib.ItemSize == 4 {
+					buffer[i+3] = -buffer[i+3]
+				}
+			}
+		}
+		offset += int(attrib.ItemSize)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// triangle returns a geometry with one triangle without indices
+func triangle() *Geometry {
+
+	g := NewGeometry()
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(math32.ArrayF32{0, 0, 0, 1, 0, 0, 0, 1, 0}))
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(math32.ArrayF32{0, 0, 1, 0, 0, 1, 0, 0, 1}))
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 2).SetBuffer(math32.ArrayF32{0, 0, 1, 0, 0, 1}))
+	return g
+}
+
+func TestMerge(t *testing.T) {
+
+	var m1, m2 math32.Matrix4
+	m1.MakeTranslation(10, 0, 0)
+	m2.MakeRotationFromQuaternion(new(math32.Quaternion).SetFromEuler(&math32.Vector3{0, 1, 0})).SetPosition(&math32.Vector3{0, -5, 2})
+	geoms := []*Geometry{&NewPlane(2, 2, 2, 1).Geometry, &NewBox(1, 2, 3, 1, 1, 1).Geometry, triangle()}
+	matrices := []*math32.Matrix4{nil, &m1, &m2}
+
+	// Expected triangles of the merged geometry
+	var want [][3]math32.Vector3
+	wantGroups := 0
+	for gi, g := range geoms {
+		positions := vectors(g, "VertexPosition")
+		indices := g.Indices()
+		if len(indices) == 0 {
+			for i := range positions {
+				indices = append(indices, uint32(i))
+			}
+		}
+		for i := 0; i < len(indices); i += 3 {
+			var tri [3]math32.Vector3
+			for k := 0; k < 3; k++ {
+				tri[k] = positions[indices[i+k]]
+				if matrices[gi] != nil {
+					tri[k].ApplyMatrix4(matrices[gi])
+				}
+			}
+			want = append(want, tri)
+		}
+		wantGroups += g.GroupCount()
+	}
+
+	merged, err := Merge(geoms, matrices)
+	if err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	positions := vectors(merged, "VertexPosition")
+	indices := merged.Indices()
+	if len(indices) != 3*len(want) {
+		t.Fatalf("merged geometry with %d indices, want %d", len(indices), 3*len(want))
+	}
+	for i, tri := range want {
+		for k := 0; k < 3; k++ {
+			p := positions[indices[3*i+k]]
+			if !nearVector3(&p, &tri[k]) {
+				t.Errorf("vertex %d of triangle %d = %v, want %v", k, i, p, tri[k])
+			}
+		}
+	}
+	checkTriangles(t, "merged", merged)
+
+	// The groups reference the triangles of their geometries
+	if merged.GroupCount() != wantGroups {
+		t.Errorf("merged geometry with %d groups, want %d", merged.GroupCount(), wantGroups)
+	}
+	boxGroup := merged.GroupAt(geoms[0].GroupCount())
+	if boxGroup.Start != len(geoms[0].Indices()) || boxGroup.Count != geoms[1].GroupAt(0).Count {
+		t.Errorf("first box group = %+v, want start %d count %d", *boxGroup, len(geoms[0].Indices()), geoms[1].GroupAt(0).Count)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+
+	noUVs := NewGeometry()
+	noUVs.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(math32.ArrayF32{0, 0, 0, 1, 0, 0, 0, 1, 0}))
+	noUVs.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(math32.ArrayF32{0, 0, 1, 0, 0, 1, 0, 0, 1}))
+	otherUVs := NewGeometry()
+	otherUVs.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(math32.ArrayF32{0, 0, 0, 1, 0, 0, 0, 1, 0}))
+	otherUVs.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(math32.ArrayF32{0, 0, 1, 0, 0, 1, 0, 0, 1}))
+	otherUVs.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 3).SetBuffer(math32.ArrayF32{0, 0, 0, 1, 0, 0, 0, 1, 0}))
+	cases := []struct {
+		name     string
+		geoms    []*Geometry
+		matrices []*math32.Matrix4
+	}{
+		{"no geometries", nil, nil},
+		{"missing VBO", []*Geometry{triangle(), noUVs}, nil},
+		{"different attribute", []*Geometry{triangle(), otherUVs}, nil},
+		{"matrices count", []*Geometry{triangle(), triangle()}, []*math32.Matrix4{nil}},
+	}
+	for _, c := range cases {
+		if _, err := Merge(c.geoms, c.matrices); err == nil {
+			t.Errorf("%s: Merge did not return an error", c.name)
+		}
+	}
+}
+
+func BenchmarkMerge(b *testing.B) {
+
+	geoms := make([]*Geometry, 500)
+	matrices := make([]*math32.Matrix4, len(geoms))
+	for i := range geoms {
+		geoms[i] = &NewBox(1, 1, 1, 2, 2, 2).Geometry
+		matrices[i] = new(math32.Matrix4).MakeTranslation(float32(i), 0, 0)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		Merge(geoms, matrices)
+	}
+}
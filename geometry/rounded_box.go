@@ -0,0 +1,172 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+	"math"
+)
+
+type RoundedBox struct {
+	Geometry
+	Width    float64
+	Height   float64
+	Depth    float64
+	Segments int
+	Radius   float64
+}
+
+// roundedCoord is a grid coordinate of a rounded box face along one axis.
+// The inner coordinate is on the box reduced by the radius and the offset
+// is the distance from it to the unrounded box surface.
+type roundedCoord struct {
+	inner  float64
+	offset float64
+}
+
+// NewRoundedBox creates and returns a pointer to a new RoundedBox geometry object.
+// The geometry is defined by its width, height, depth, the radius of its rounded
+// edges and corners and the number of segments of each rounded edge (minimum = 1).
+// The radius is limited to half of the smallest dimension.
+// As in Box, each face is a group with material index from 0 to 5 in the
+// order: +X, -X, +Y, -Y, +Z, -Z.
+func NewRoundedBox(width, height, depth float64, segments int, radius float64) *RoundedBox {
+
+	box := new(RoundedBox)
+	box.Geometry.Init()
+
+	if segments < 1 {
+		segments = 1
+	}
+	radius = math.Max(0, math.Min(radius, math.Min(width, math.Min(height, depth))/2))
+	box.Width = width
+	box.Height = height
+	box.Depth = depth
+	box.Segments = segments
+	box.Radius = radius
+
+	// Create buffers
+	positions := math32.NewArrayF32(0, 16)
+	normals := math32.NewArrayF32(0, 16)
+	uvs := math32.NewArrayF32(0, 16)
+	indices := math32.NewArrayU32(0, 16)
+
+	half := [3]float64{width / 2, height / 2, depth / 2}
+	inner := [3]float64{half[0] - radius, half[1] - radius, half[2] - radius}
+
+	// Internal function to build the grid coordinates along an axis from the
+	// negative to the positive side. The rounded parts are divided in equal
+	// angles and the flat part between them is a single segment.
+	edgeSegs := segments
+	if radius == 0 {
+		edgeSegs = 0
+	}
+	axisCoords := func(axis int) []roundedCoord {
+
+		coords := make([]roundedCoord, 0, 2*edgeSegs+2)
+		for k := edgeSegs; k >= 0; k-- {
+			coords = append(coords, roundedCoord{-inner[axis], -radius * edgeOffset(k, edgeSegs)})
+		}
+		// Skips the repeated middle coordinate if there is no flat part
+		start := 0
+		if inner[axis] == 0 {
+			start = 1
+		}
+		for k := start; k <= edgeSegs; k++ {
+			coords = append(coords, roundedCoord{inner[axis], radius * edgeOffset(k, edgeSegs)})
+		}
+		return coords
+	}
+	coords := [3][]roundedCoord{axisCoords(0), axisCoords(1), axisCoords(2)}
+
+	// Internal function to build each box face where the face normal is along
+	// the w axis with the specified sign and the grid columns and rows
+	// advance along the u and v axes in the specified directions.
+	buildFace := func(u, v, w int, udir, vdir, sign float64, materialIndex int) {
+
+		ucoords := coords[u]
+		vcoords := coords[v]
+		gridX := len(ucoords) - 1
+		gridY := len(vcoords) - 1
+		offset := positions.Len() / 3
+
+		// Generates the face vertices, normals and uv coordinates.
+		for iy := 0; iy <= gridY; iy++ {
+			vc := vcoords[iy]
+			for ix := 0; ix <= gridX; ix++ {
+				uc := ucoords[ix]
+				// The normal is the direction from the inner box to the unrounded surface
+				var d [3]float64
+				d[u] = uc.offset * udir
+				d[v] = vc.offset * vdir
+				d[w] = sign * radius
+				if radius == 0 {
+					d[w] = sign
+				}
+				var normal math32.Vector3
+				normal.Set(float32(d[0]), float32(d[1]), float32(d[2])).Normalize()
+				var p [3]float64
+				p[u] = uc.inner * udir
+				p[v] = vc.inner * vdir
+				p[w] = sign * inner[w]
+				var vector math32.Vector3
+				vector.Set(
+					float32(p[0])+normal.X*float32(radius),
+					float32(p[1])+normal.Y*float32(radius),
+					float32(p[2])+normal.Z*float32(radius),
+				)
+				positions.AppendVector3(&vector)
+				normals.AppendVector3(&normal)
+				// Texture coordinates from the position on the unrounded face
+				uvs.Append(
+					float32((uc.inner+uc.offset+half[u])/(2*half[u])),
+					float32(1-(vc.inner+vc.offset+half[v])/(2*half[v])),
+				)
+			}
+		}
+
+		gstart := indices.Size()
+		gridX1 := gridX + 1
+		// Generates the indices for the vertices, normals and uvs
+		for iy := 0; iy < gridY; iy++ {
+			for ix := 0; ix < gridX; ix++ {
+				a := ix + gridX1*iy
+				b := ix + gridX1*(iy+1)
+				c := (ix + 1) + gridX1*(iy+1)
+				d := (ix + 1) + gridX1*iy
+				indices.Append(uint32(a+offset), uint32(b+offset), uint32(d+offset), uint32(b+offset), uint32(c+offset), uint32(d+offset))
+			}
+		}
+		gcount := indices.Size() - gstart
+		box.AddGroup(gstart, gcount, materialIndex)
+	}
+
+	buildFace(2, 1, 0, -1, -1, 1, 0)  // px
+	buildFace(2, 1, 0, 1, -1, -1, 1)  // nx
+	buildFace(0, 2, 1, 1, 1, 1, 2)    // py
+	buildFace(0, 2, 1, 1, -1, -1, 3)  // ny
+	buildFace(0, 1, 2, 1, -1, 1, 4)   // pz
+	buildFace(0, 1, 2, -1, -1, -1, 5) // nz
+
+	box.SetIndices(indices)
+	box.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(positions))
+	box.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(normals))
+	box.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 2).SetBuffer(uvs))
+
+	return box
+}
+
+// edgeOffset returns the offset, relative to the radius, from the inner box to
+// the unrounded surface of the k-th of n grid coordinates of a rounded edge.
+// Each face covers half of the quarter circle of the edge, so the coordinates
+// are spaced by equal angles from 0 to 45 degrees.
+func edgeOffset(k, n int) float64 {
+
+	if n == 0 {
+		return 0
+	}
+	return math.Tan(math.Pi / 4 * float64(k) / float64(n))
+}
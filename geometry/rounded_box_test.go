@@ -0,0 +1,67 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestRoundedBox(t *testing.T) {
+
+	cases := []struct {
+		name                  string
+		width, height, depth  float64
+		segments              int
+		radius                float64
+		vertices, faceIndices int
+	}{
+		// Each face is a grid of 2*segments+1 rows and columns
+		{"rounded", 2, 3, 4, 3, 0.5, 6 * 8 * 8, 6 * 7 * 7 * 6},
+		{"one segment", 1, 1, 1, 1, 0.2, 6 * 4 * 4, 6 * 3 * 3 * 6},
+		{"square", 2, 2, 2, 2, 0, 6 * 2 * 2, 6 * 1 * 1 * 6},
+		// Without flat part the middle row and column are not repeated
+		{"sphere", 2, 2, 2, 2, 1, 6 * 5 * 5, 6 * 4 * 4 * 6},
+	}
+	for _, c := range cases {
+		box := NewRoundedBox(c.width, c.height, c.depth, c.segments, c.radius)
+		g := &box.Geometry
+		if n := len(vectors(g, "VertexPosition")); n != c.vertices {
+			t.Errorf("%s: %d vertices, want %d", c.name, n, c.vertices)
+		}
+		if n := len(g.Indices()); n != c.faceIndices {
+			t.Errorf("%s: %d indices, want %d", c.name, n, c.faceIndices)
+		}
+		if g.GroupCount() != 6 {
+			t.Errorf("%s: %d groups, want 6", c.name, g.GroupCount())
+		}
+		checkTriangles(t, c.name, g)
+
+		bbox := g.BoundingBox()
+		max := math32.Vector3{float32(c.width / 2), float32(c.height / 2), float32(c.depth / 2)}
+		min := max
+		min.Negate()
+		if !nearVector3(&bbox.Min, &min) || !nearVector3(&bbox.Max, &max) {
+			t.Errorf("%s: bounding box %v, want %v %v", c.name, bbox, min, max)
+		}
+
+		// The normals are unit vectors
+		for i, n := range vectors(g, "VertexNormal") {
+			if !near(n.Length(), 1) {
+				t.Errorf("%s: normal %d = %v is not a unit vector", c.name, i, n)
+				break
+			}
+		}
+	}
+}
+
+func TestRoundedBoxRadiusLimit(t *testing.T) {
+
+	box := NewRoundedBox(1, 4, 4, 2, 3)
+	if box.Radius != 0.5 {
+		t.Errorf("Radius = %v, want 0.5", box.Radius)
+	}
+}
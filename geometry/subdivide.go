@@ -0,0 +1,260 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// SubdivideMaxIterations is the maximum number of iterations of Subdivide
+const SubdivideMaxIterations = 4
+
+// subdivMesh is the triangle mesh being subdivided
+type subdivMesh struct {
+	positions []math32.Vector3
+	uvs       []math32.Vector2 // nil if the geometry has no texture coordinates
+	indices   []uint32
+}
+
+// subdivEdge is an edge between two welded vertices
+type subdivEdge struct {
+	v1, v2 int
+}
+
+// Subdivide creates and returns a pointer to a new geometry with the triangles
+// of the specified geometry smoothed by the specified number of iterations of
+// Loop subdivision, limited to SubdivideMaxIterations. Each iteration splits
+// each triangle in 4, so the number of triangles is multiplied by 4^iterations
+// and the number of vertices grows by about the same factor.
+// The vertices with the same position are joined to subdivide the surface,
+// so the result is watertight even if the geometry has duplicated vertices
+// with different texture coordinates, which are kept and interpolated.
+// The new geometry has positions, smooth normals, texture coordinates if the
+// original geometry has them and the groups of the original geometry.
+func Subdivide(geom *Geometry, iterations int) *Geometry {
+
+	iterations = math32.ClampInt(iterations, 0, SubdivideMaxIterations)
+
+	// Gets the mesh from the geometry
+	var mesh subdivMesh
+	if vbo := geom.VBO("VertexPosition"); vbo != nil {
+		buf := vbo.Buffer()
+		mesh.positions = make([]math32.Vector3, buf.Size()/3)
+		for i := range mesh.positions {
+			buf.GetVector3(i*3, &mesh.positions[i])
+		}
+	}
+	if vbo := geom.VBO("VertexTexcoord"); vbo != nil {
+		buf := vbo.Buffer()
+		mesh.uvs = make([]math32.Vector2, buf.Size()/2)
+		for i := range mesh.uvs {
+			buf.GetVector2(i*2, &mesh.uvs[i])
+		}
+	}
+	if geom.indices.Size() > 0 {
+		mesh.indices = append([]uint32(nil), geom.indices...)
+	} else {
+		mesh.indices = make([]uint32, len(mesh.positions)/3*3)
+		for i := range mesh.indices {
+			mesh.indices[i] = uint32(i)
+		}
+	}
+
+	for it := 0; it < iterations; it++ {
+		mesh.subdivide()
+	}
+
+	// Creates the new geometry
+	g := NewGeometry()
+	positions := math32.NewArrayF32(0, len(mesh.positions)*3)
+	for i := range mesh.positions {
+		positions.AppendVector3(&mesh.positions[i])
+	}
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(positions))
+	g.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(mesh.normals()))
+	if mesh.uvs != nil {
+		uvs := math32.NewArrayF32(0, len(mesh.uvs)*2)
+		for i := range mesh.uvs {
+			uvs.AppendVector2(&mesh.uvs[i])
+		}
+		g.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 2).SetBuffer(uvs))
+	}
+	g.SetIndices(math32.ArrayU32(mesh.indices))
+
+	// Each triangle was replaced by 4^iterations consecutive triangles
+	scale := 1 << uint(2*iterations)
+	for _, grp := range geom.groups {
+		grp.Start *= scale
+		grp.Count *= scale
+		g.groups = append(g.groups, grp)
+	}
+	return g
+}
+
+// weld returns the welded vertex of each vertex of this mesh
+// and the positions of the welded vertices
+func (m *subdivMesh) weld() ([]int, []math32.Vector3) {
+
+	ids := make(map[math32.Vector3]int)
+	vid := make([]int, len(m.positions))
+	welded := make([]math32.Vector3, 0, len(m.positions))
+	for i, p := range m.positions {
+		id, ok := ids[p]
+		if !ok {
+			id = len(welded)
+			ids[p] = id
+			welded = append(welded, p)
+		}
+		vid[i] = id
+	}
+	return vid, welded
+}
+
+// subdivide executes one iteration of Loop subdivision of this mesh
+func (m *subdivMesh) subdivide() {
+
+	vid, welded := m.weld()
+
+	// Finds the opposite vertices of each edge and the neighbours of each vertex
+	opposite := make(map[subdivEdge][]int)
+	edgeKey := func(v1, v2 int) subdivEdge {
+		if v1 > v2 {
+			v1, v2 = v2, v1
+		}
+		return subdivEdge{v1, v2}
+	}
+	for t := 0; t+2 < len(m.indices); t += 3 {
+		for k := 0; k < 3; k++ {
+			a := vid[m.indices[t+k]]
+			b := vid[m.indices[t+(k+1)%3]]
+			c := vid[m.indices[t+(k+2)%3]]
+			key := edgeKey(a, b)
+			opposite[key] = append(opposite[key], c)
+		}
+	}
+	neighbours := make([][]int, len(welded))
+	boundary := make([][]int, len(welded))
+	for e, opp := range opposite {
+		neighbours[e.v1] = append(neighbours[e.v1], e.v2)
+		neighbours[e.v2] = append(neighbours[e.v2], e.v1)
+		if len(opp) == 1 {
+			boundary[e.v1] = append(boundary[e.v1], e.v2)
+			boundary[e.v2] = append(boundary[e.v2], e.v1)
+		}
+	}
+
+	// Calculates the new positions of the original vertices
+	vertexPos := make([]math32.Vector3, len(welded))
+	for v := range welded {
+		p := &vertexPos[v]
+		switch {
+		case len(boundary[v]) == 2:
+			// Boundary vertex: 3/4 of the vertex and 1/8 of each boundary neighbour
+			p.Copy(&welded[v]).MultiplyScalar(0.75)
+			for _, n := range boundary[v] {
+				p.Add(welded[n].Clone().MultiplyScalar(0.125))
+			}
+		case len(boundary[v]) > 0 || len(neighbours[v]) < 3:
+			// Corner or non manifold vertex is kept
+			p.Copy(&welded[v])
+		default:
+			// Interior vertex with Loop weights
+			n := float32(len(neighbours[v]))
+			beta := 3 / (8 * n)
+			if n == 3 {
+				beta = 3.0 / 16
+			}
+			p.Copy(&welded[v]).MultiplyScalar(1 - n*beta)
+			for _, nb := range neighbours[v] {
+				p.Add(welded[nb].Clone().MultiplyScalar(beta))
+			}
+		}
+	}
+
+	// Calculates the positions of the new edge vertices
+	edgePos := make(map[subdivEdge]math32.Vector3, len(opposite))
+	for e, opp := range opposite {
+		var p math32.Vector3
+		p.AddVectors(&welded[e.v1], &welded[e.v2])
+		if len(opp) == 2 {
+			// Interior edge: 3/8 of each edge vertex and 1/8 of each opposite vertex
+			p.MultiplyScalar(0.375)
+			p.Add(welded[opp[0]].Clone().Add(&welded[opp[1]]).MultiplyScalar(0.125))
+		} else {
+			// Boundary or non manifold edge: mid point
+			p.MultiplyScalar(0.5)
+		}
+		edgePos[e] = p
+	}
+
+	// Builds the new vertices keeping the original vertices
+	// and adding a vertex for each edge of the original vertices
+	positions := make([]math32.Vector3, len(m.positions), len(m.positions)*4)
+	for i := range m.positions {
+		positions[i] = vertexPos[vid[i]]
+	}
+	var uvs []math32.Vector2
+	if m.uvs != nil {
+		uvs = make([]math32.Vector2, len(m.uvs), len(m.uvs)*4)
+		copy(uvs, m.uvs)
+	}
+	mids := make(map[subdivEdge]uint32)
+	midVertex := func(i1, i2 uint32) uint32 {
+		key := edgeKey(int(i1), int(i2))
+		if idx, ok := mids[key]; ok {
+			return idx
+		}
+		idx := uint32(len(positions))
+		positions = append(positions, edgePos[edgeKey(vid[i1], vid[i2])])
+		if uvs != nil {
+			var uv math32.Vector2
+			uv.AddVectors(&m.uvs[i1], &m.uvs[i2]).MultiplyScalar(0.5)
+			uvs = append(uvs, uv)
+		}
+		mids[key] = idx
+		return idx
+	}
+
+	// Splits each triangle in 4 keeping the winding order
+	indices := make([]uint32, 0, len(m.indices)*4)
+	for t := 0; t+2 < len(m.indices); t += 3 {
+		i0, i1, i2 := m.indices[t], m.indices[t+1], m.indices[t+2]
+		m01 := midVertex(i0, i1)
+		m12 := midVertex(i1, i2)
+		m20 := midVertex(i2, i0)
+		indices = append(indices, i0, m01, m20, m01, i1, m12, m20, m12, i2, m01, m12, m20)
+	}
+	m.positions = positions
+	m.uvs = uvs
+	m.indices = indices
+}
+
+// normals returns the smooth normals of the vertices of this mesh
+// averaging the normals of the triangles of the vertices with the same position
+func (m *subdivMesh) normals() math32.ArrayF32 {
+
+	vid, welded := m.weld()
+	accum := make([]math32.Vector3, len(welded))
+	var e1, e2, n math32.Vector3
+	for t := 0; t+2 < len(m.indices); t += 3 {
+		p0 := &m.positions[m.indices[t]]
+		p1 := &m.positions[m.indices[t+1]]
+		p2 := &m.positions[m.indices[t+2]]
+		e1.SubVectors(p1, p0)
+		e2.SubVectors(p2, p0)
+		// The cross product length is proportional to the triangle area
+		n.CrossVectors(&e1, &e2)
+		for k := 0; k < 3; k++ {
+			accum[vid[m.indices[t+k]]].Add(&n)
+		}
+	}
+	normals := math32.NewArrayF32(0, len(m.positions)*3)
+	for i := range m.positions {
+		n = accum[vid[i]]
+		normals.AppendVector3(n.Normalize())
+	}
+	return normals
+}
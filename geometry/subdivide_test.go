@@ -0,0 +1,103 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+// openEdges returns the number of edges of the geometry which are not shared
+// by exactly two triangles, identifying the vertices by their positions
+func openEdges(g *Geometry) int {
+
+	positions := vectors(g, "VertexPosition")
+	ids := make(map[math32.Vector3]int)
+	id := func(i uint32) int {
+		p := positions[i]
+		// Rounds the coordinates to join the vertices at the same position
+		key := math32.Vector3{math32.Round(p.X * 1e4), math32.Round(p.Y * 1e4), math32.Round(p.Z * 1e4)}
+		if v, ok := ids[key]; ok {
+			return v
+		}
+		ids[key] = len(ids)
+		return ids[key]
+	}
+	edges := make(map[[2]int]int)
+	indices := g.Indices()
+	for i := 0; i < len(indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			a, b := id(indices[i+k]), id(indices[i+(k+1)%3])
+			if a > b {
+				a, b = b, a
+			}
+			edges[[2]int{a, b}]++
+		}
+	}
+	open := 0
+	for _, count := range edges {
+		if count != 2 {
+			open++
+		}
+	}
+	return open
+}
+
+// roundness returns the difference between the maximum and minimum
+// distances of the vertices from the center relative to the maximum
+func roundness(g *Geometry) float32 {
+
+	min := math32.Inf(1)
+	var max float32
+	for _, p := range vectors(g, "VertexPosition") {
+		d := p.Length()
+		min = math32.Min(min, d)
+		max = math32.Max(max, d)
+	}
+	return (max - min) / max
+}
+
+func TestSubdivideCube(t *testing.T) {
+
+	// The faces are divided so the vertices are not all at the same distance
+	cube := &NewBox(2, 2, 2, 2, 2, 2).Geometry
+	last := roundness(cube)
+	for it := 1; it <= 3; it++ {
+		g := Subdivide(cube, it)
+		name := "iteration " + string(rune('0'+it))
+		triangles := len(cube.Indices()) / 3 << uint(2*it)
+		if n := len(g.Indices()) / 3; n != triangles {
+			t.Errorf("%s: %d triangles, want %d", name, n, triangles)
+		}
+		if n := openEdges(g); n != 0 {
+			t.Errorf("%s: %d edges not shared by two triangles", name, n)
+		}
+		if g.GroupCount() != cube.GroupCount() {
+			t.Errorf("%s: %d groups, want %d", name, g.GroupCount(), cube.GroupCount())
+		}
+		if g.VBO("VertexNormal") == nil || g.VBO("VertexTexcoord") == nil {
+			t.Errorf("%s: normals or texture coordinates missing", name)
+		}
+		checkTriangles(t, name, g)
+		r := roundness(g)
+		if r >= last {
+			t.Errorf("%s: roundness %v not smaller than %v", name, r, last)
+		}
+		last = r
+	}
+}
+
+func TestSubdivideIterationsLimit(t *testing.T) {
+
+	cube := &NewBox(2, 2, 2, 1, 1, 1).Geometry
+	if n := len(Subdivide(cube, 0).Indices()); n != len(cube.Indices()) {
+		t.Errorf("Subdivide(0) with %d indices, want %d", n, len(cube.Indices()))
+	}
+	max := len(Subdivide(cube, SubdivideMaxIterations).Indices())
+	if n := len(Subdivide(cube, SubdivideMaxIterations+2).Indices()); n != max {
+		t.Errorf("Subdivide(%d) with %d indices, want %d", SubdivideMaxIterations+2, n, max)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"sort"
+
+	"github.com/g3n/engine/math32"
+)
+
+// triangulate triangulates the polygon with the specified outer contour and holes
+// by ear clipping. The outer contour must be counter clockwise and the holes
+// clockwise. Returns the points of the contour followed by the points of the
+// holes and the indices of the counter clockwise triangles into these points.
+func triangulate(contour []math32.Vector2, holes [][]math32.Vector2) ([]math32.Vector2, []int) {
+
+	points := make([]math32.Vector2, 0, len(contour))
+	points = append(points, contour...)
+	poly := make([]int, len(contour))
+	for i := range poly {
+		poly[i] = i
+	}
+
+	// Appends the holes points and sorts the holes from right to left
+	hidx := make([][]int, 0, len(holes))
+	for _, hole := range holes {
+		if len(hole) < 3 {
+			continue
+		}
+		h := make([]int, len(hole))
+		for i := range hole {
+			h[i] = len(points)
+			points = append(points, hole[i])
+		}
+		hidx = append(hidx, h)
+	}
+	maxX := func(h []int) int {
+		m := 0
+		for i := range h {
+			if points[h[i]].X > points[h[m]].X {
+				m = i
+			}
+		}
+		return m
+	}
+	sort.Stable(holesByMaxX{points, hidx, maxX})
+
+	// Joins each hole to the polygon by a bridge from its rightmost
+	// point to the nearest polygon point which is visible from it
+	for hi, h := range hidx {
+		m := maxX(h)
+		pm := points[h[m]]
+		bridge := -1
+		var bestDist float32
+		for i, pi := range poly {
+			d := pm.DistanceToSquared(&points[pi])
+			if bridge >= 0 && d >= bestDist {
+				continue
+			}
+			if !triVisible(points, poly, hidx[hi:], h[m], pi) {
+				continue
+			}
+			bridge = i
+			bestDist = d
+		}
+		if bridge < 0 {
+			continue
+		}
+		merged := make([]int, 0, len(poly)+len(h)+2)
+		merged = append(merged, poly[:bridge+1]...)
+		merged = append(merged, h[m:]...)
+		merged = append(merged, h[:m+1]...)
+		merged = append(merged, poly[bridge:]...)
+		poly = merged
+	}
+
+	// Clips the ears of the polygon
+	tris := make([]int, 0, 3*len(poly))
+	for len(poly) > 3 {
+		clipped := false
+		for i := range poly {
+			i0 := poly[(i+len(poly)-1)%len(poly)]
+			i1 := poly[i]
+			i2 := poly[(i+1)%len(poly)]
+			area := triArea(&points[i0], &points[i1], &points[i2])
+			// Removes degenerate vertices without generating a triangle
+			if area == 0 {
+				poly = append(poly[:i], poly[i+1:]...)
+				clipped = true
+				break
+			}
+			// Ignores reflex vertices
+			if area < 0 {
+				continue
+			}
+			if !triIsEar(points, poly, i0, i1, i2) {
+				continue
+			}
+			tris = append(tris, i0, i1, i2)
+			poly = append(poly[:i], poly[i+1:]...)
+			clipped = true
+			break
+		}
+		// The polygon is invalid (self intersecting, for example)
+		if !clipped {
+			break
+		}
+	}
+	if len(poly) == 3 && triArea(&points[poly[0]], &points[poly[1]], &points[poly[2]]) > 0 {
+		tris = append(tris, poly...)
+	}
+	return points, tris
+}
+
+// holesByMaxX sorts holes by decreasing maximum x coordinate
+type holesByMaxX struct {
+	points []math32.Vector2
+	holes  [][]int
+	maxX   func([]int) int
+}
+
+func (hs holesByMaxX) Len() int      { return len(hs.holes) }
+func (hs holesByMaxX) Swap(i, j int) { hs.holes[i], hs.holes[j] = hs.holes[j], hs.holes[i] }
+func (hs holesByMaxX) Less(i, j int) bool {
+	hi := hs.holes[i]
+	hj := hs.holes[j]
+	return hs.points[hi[hs.maxX(hi)]].X > hs.points[hj[hs.maxX(hj)]].X
+}
+
+// triArea returns twice the signed area of the triangle a, b, c
+// which is positive if the triangle is counter clockwise
+func triArea(a, b, c *math32.Vector2) float32 {
+
+	return (b.X-a.X)*(c.Y-a.Y) - (c.X-a.X)*(b.Y-a.Y)
+}
+
+// triIsEar returns if no other vertex of the polygon is inside
+// the counter clockwise triangle i0, i1, i2
+func triIsEar(points []math32.Vector2, poly []int, i0, i1, i2 int) bool {
+
+	a, b, c := &points[i0], &points[i1], &points[i2]
+	for _, pi := range poly {
+		p := &points[pi]
+		// Ignores the triangle vertices and the bridge points with the same position
+		if p.Equals(a) || p.Equals(b) || p.Equals(c) {
+			continue
+		}
+		if triArea(a, b, p) >= 0 && triArea(b, c, p) >= 0 && triArea(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// triVisible returns if the segment from point i1 to point i2 does not cross
+// any edge of the polygon or of the specified holes not yet bridged
+func triVisible(points []math32.Vector2, poly []int, holes [][]int, i1, i2 int) bool {
+
+	crosses := func(ring []int) bool {
+		for i := range ring {
+			e1 := ring[i]
+			e2 := ring[(i+1)%len(ring)]
+			if e1 == i1 || e2 == i1 || e1 == i2 || e2 == i2 {
+				continue
+			}
+			if segmentsIntersect(&points[i1], &points[i2], &points[e1], &points[e2]) {
+				return true
+			}
+		}
+		return false
+	}
+	if crosses(poly) {
+		return false
+	}
+	for _, h := range holes {
+		if crosses(h) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentsIntersect returns if the segment p1-p2 intersects or touches the segment q1-q2
+func segmentsIntersect(p1, p2, q1, q2 *math32.Vector2) bool {
+
+	d1 := triArea(q1, q2, p1)
+	d2 := triArea(q1, q2, p2)
+	d3 := triArea(p1, p2, q1)
+	d4 := triArea(p1, p2, q2)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	onSegment := func(a, b, p *math32.Vector2) bool {
+		return math32.Min(a.X, b.X) <= p.X && p.X <= math32.Max(a.X, b.X) &&
+			math32.Min(a.Y, b.Y) <= p.Y && p.Y <= math32.Max(a.Y, b.Y)
+	}
+	return (d1 == 0 && onSegment(q1, q2, p1)) || (d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) || (d4 == 0 && onSegment(p1, p2, q2))
+}
@@ -0,0 +1,152 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+	"math"
+)
+
+type Tube struct {
+	Geometry
+	Path            math32.Curve
+	TubularSegments int
+	RadialSegments  int
+	Radius          float64
+	Closed          bool
+	Tangents        []math32.Vector3 // tangents of the path at each tubular segment
+	Normals         []math32.Vector3 // normals of the path at each tubular segment
+	Binormals       []math32.Vector3 // binormals of the path at each tubular segment
+}
+
+// NewTube creates and returns a pointer to a new Tube geometry object.
+// The geometry is a circular cross section of the specified radius swept
+// along the path curve. The tube has (tubularSegments+1)*(radialSegments+1)
+// vertices, where the first and last vertices of each ring and of each
+// radial line are at the same positions with different texture coordinates.
+// Its orientation along the path is calculated by parallel transport to avoid
+// twisting. If closed is true, the path should be a closed curve and the
+// orientation is adjusted so the tube end is stitched to its start.
+func NewTube(path math32.Curve, tubularSegments, radialSegments int, radius float64, closed bool) *Tube {
+
+	t := new(Tube)
+	t.Geometry.Init()
+
+	t.Path = path
+	t.TubularSegments = tubularSegments
+	t.RadialSegments = radialSegments
+	t.Radius = radius
+	t.Closed = closed
+	t.computeFrames()
+
+	vertexCount := (tubularSegments + 1) * (radialSegments + 1)
+
+	// Create buffers
+	positions := math32.NewArrayF32(0, vertexCount*3)
+	normals := math32.NewArrayF32(0, vertexCount*3)
+	uvs := math32.NewArrayF32(0, vertexCount*2)
+	indices := math32.NewArrayU32(0, tubularSegments*radialSegments*6)
+
+	// Generates the vertices, normals and uv coordinates of each ring.
+	// The last ring of a closed tube uses the first ring frame.
+	var point, normal, vertex math32.Vector3
+	for i := 0; i <= tubularSegments; i++ {
+		frame := i
+		if closed && i == tubularSegments {
+			frame = 0
+		}
+		path.Point(float32(frame)/float32(tubularSegments), &point)
+		N := &t.Normals[frame]
+		B := &t.Binormals[frame]
+		for j := 0; j <= radialSegments; j++ {
+			v := float64(j) / float64(radialSegments) * 2 * math.Pi
+			sin := float32(math.Sin(v))
+			cos := float32(-math.Cos(v))
+			normal.Set(cos*N.X+sin*B.X, cos*N.Y+sin*B.Y, cos*N.Z+sin*B.Z).Normalize()
+			vertex.Copy(&normal).MultiplyScalar(float32(radius)).Add(&point)
+			positions.AppendVector3(&vertex)
+			normals.AppendVector3(&normal)
+			uvs.Append(float32(i)/float32(tubularSegments), float32(j)/float32(radialSegments))
+		}
+	}
+
+	// Generates the indices
+	for j := 1; j <= tubularSegments; j++ {
+		for i := 1; i <= radialSegments; i++ {
+			a := (radialSegments+1)*(j-1) + (i - 1)
+			b := (radialSegments+1)*j + (i - 1)
+			c := (radialSegments+1)*j + i
+			d := (radialSegments+1)*(j-1) + i
+			indices.Append(uint32(a), uint32(b), uint32(d), uint32(b), uint32(c), uint32(d))
+		}
+	}
+
+	t.SetIndices(indices)
+	t.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(positions))
+	t.AddVBO(gls.NewVBO().AddAttrib("VertexNormal", 3).SetBuffer(normals))
+	t.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord", 2).SetBuffer(uvs))
+
+	return t
+}
+
+// computeFrames calculates the tangents, normals and binormals of the
+// path at each tubular segment transporting the initial normal along the
+// path with minimum rotation.
+func (t *Tube) computeFrames() {
+
+	segments := t.TubularSegments
+	t.Tangents = make([]math32.Vector3, segments+1)
+	t.Normals = make([]math32.Vector3, segments+1)
+	t.Binormals = make([]math32.Vector3, segments+1)
+	for i := 0; i <= segments; i++ {
+		math32.CurveTangent(t.Path, float32(i)/float32(segments), &t.Tangents[i])
+	}
+
+	// The initial normal is perpendicular to the tangent
+	// and to the axis where the tangent component is smallest
+	T0 := &t.Tangents[0]
+	var axis math32.Vector3
+	tx := math32.Abs(T0.X)
+	ty := math32.Abs(T0.Y)
+	tz := math32.Abs(T0.Z)
+	if tx <= ty && tx <= tz {
+		axis.Set(1, 0, 0)
+	} else if ty <= tz {
+		axis.Set(0, 1, 0)
+	} else {
+		axis.Set(0, 0, 1)
+	}
+	var vec math32.Vector3
+	vec.CrossVectors(T0, &axis).Normalize()
+	t.Normals[0].CrossVectors(T0, &vec)
+	t.Binormals[0].CrossVectors(T0, &t.Normals[0])
+
+	// Rotates the previous normal by the rotation between the previous and current tangents
+	for i := 1; i <= segments; i++ {
+		t.Normals[i] = t.Normals[i-1]
+		vec.CrossVectors(&t.Tangents[i-1], &t.Tangents[i])
+		if vec.Length() > 1e-6 {
+			vec.Normalize()
+			theta := math32.Acos(math32.Clamp(t.Tangents[i-1].Dot(&t.Tangents[i]), -1, 1))
+			t.Normals[i].ApplyAxisAngle(&vec, theta)
+		}
+		t.Binormals[i].CrossVectors(&t.Tangents[i], &t.Normals[i])
+	}
+
+	// For closed paths distributes the twist between the
+	// last and first normals along the path
+	if t.Closed {
+		theta := math32.Acos(math32.Clamp(t.Normals[0].Dot(&t.Normals[segments]), -1, 1)) / float32(segments)
+		vec.CrossVectors(&t.Normals[0], &t.Normals[segments])
+		if t.Tangents[0].Dot(&vec) > 0 {
+			theta = -theta
+		}
+		for i := 1; i <= segments; i++ {
+			t.Normals[i].ApplyAxisAngle(&t.Tangents[i], theta*float32(i))
+			t.Binormals[i].CrossVectors(&t.Tangents[i], &t.Normals[i])
+		}
+	}
+}
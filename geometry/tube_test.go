@@ -0,0 +1,84 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+// lineCurve is a straight segment curve
+type lineCurve struct {
+	start, end math32.Vector3
+}
+
+func (c *lineCurve) Point(t float32, optionalTarget *math32.Vector3) *math32.Vector3 {
+
+	if optionalTarget == nil {
+		optionalTarget = new(math32.Vector3)
+	}
+	return optionalTarget.Copy(&c.end).Sub(&c.start).MultiplyScalar(t).Add(&c.start)
+}
+
+func TestTube(t *testing.T) {
+
+	loop := math32.NewCatmullRom([]math32.Vector3{{0, 0, 0}, {4, 0, 1}, {4, 3, 0}, {0, 3, -1}}, true)
+	cases := []struct {
+		name     string
+		path     math32.Curve
+		tubular  int
+		radial   int
+		closed   bool
+		straight bool
+	}{
+		{"line", &lineCurve{math32.Vector3{0, 0, 0}, math32.Vector3{0, 5, 5}}, 4, 6, false, true},
+		{"open curve", math32.NewCatmullRom([]math32.Vector3{{0, 0, 0}, {1, 2, 0}, {3, 2, 1}}, false), 20, 8, false, false},
+		{"closed loop", loop, 32, 12, true, false},
+	}
+	for _, c := range cases {
+		tube := NewTube(c.path, c.tubular, c.radial, 0.25, c.closed)
+		g := &tube.Geometry
+		positions := vectors(g, "VertexPosition")
+		if want := (c.tubular + 1) * (c.radial + 1); len(positions) != want {
+			t.Errorf("%s: %d vertices, want %d", c.name, len(positions), want)
+		}
+		if want := c.tubular * c.radial * 6; len(g.Indices()) != want {
+			t.Errorf("%s: %d indices, want %d", c.name, len(g.Indices()), want)
+		}
+		checkTriangles(t, c.name, g)
+
+		// The vertices of each ring are at the radius from the path
+		var point math32.Vector3
+		for i := 0; i <= c.tubular; i++ {
+			c.path.Point(float32(i)/float32(c.tubular), &point)
+			for j := 0; j <= c.radial; j++ {
+				v := &positions[i*(c.radial+1)+j]
+				if d := v.DistanceTo(&point); !near(d, 0.25) {
+					t.Errorf("%s: vertex %d of ring %d at %v from the path", c.name, j, i, d)
+				}
+			}
+		}
+
+		// The ends of a closed tube are stitched
+		if c.closed {
+			last := c.tubular * (c.radial + 1)
+			for j := 0; j <= c.radial; j++ {
+				if !nearVector3(&positions[j], &positions[last+j]) {
+					t.Errorf("%s: vertex %d of the last ring %v, want %v", c.name, j, positions[last+j], positions[j])
+				}
+			}
+		}
+
+		// Straight paths are not twisted
+		if c.straight {
+			for i := range tube.Normals {
+				if !nearVector3(&tube.Normals[i], &tube.Normals[0]) {
+					t.Errorf("%s: normal %d = %v twisted from %v", c.name, i, tube.Normals[i], tube.Normals[0])
+				}
+			}
+		}
+	}
+}
@@ -23,6 +23,7 @@ type GLS struct {
 	programs            map[*Program]bool // shader programs cache
 	checkErrors         bool              // check openGL API errors flag
 	activeTexture       uint32            // cached last set active texture unit
+	framebuffer         uint32            // cached last bound framebuffer
 	viewportX           int32             // cached last set viewport x
 	viewportY           int32             // cached last set viewport y
 	viewportWidth       int32             // cached last set viewport width
@@ -46,6 +47,16 @@ type GLS struct {
 	polygonModeMode     uint32            // cached last set polygon mode mode
 	polygonOffsetFactor float32           // cached last set polygon offset factor
 	polygonOffsetUnits  float32           // cached last set polygon offset units
+	stencilFunc         uint32            // cached last set stencil function
+	stencilRef          int32             // cached last set stencil reference value
+	stencilFuncMask     uint32            // cached last set stencil function mask
+	stencilFail         uint32            // cached last set stencil fail operation
+	stencilZfail        uint32            // cached last set stencil depth fail operation
+	stencilZpass        uint32            // cached last set stencil depth pass operation
+	stencilMask         uint32            // cached last set stencil write mask
+	stencilMaskSet      bool              // stencil write mask was set
+	extensions          map[string]bool   // cached supported extensions
+	maxAnisotropy       float32           // cached maximum texture anisotropy
 	gobuf               []byte            // conversion buffer with GO memory
 	cbuf                []byte            // conversion buffer with C memory
 }
@@ -62,6 +73,9 @@ type Stats struct {
 	UnilocMiss uint64 // Cummulative number of uniform location cache misses
 	Unisets    uint64 // Cummulative number of uniform sets
 	Drawcalls  uint64 // Cummulative number of draw calls
+	Triangles  uint64 // Cummulative number of triangles drawn
+	Programs   uint64 // Cummulative number of shader program activations
+	Texbinds   uint64 // Cummulative number of texture binds
 }
 
 // Polygon side view.
@@ -71,6 +85,13 @@ const (
 	DoubleSide
 )
 
+// Constants of the EXT_texture_filter_anisotropic extension
+// which are not defined in the core profile header
+const (
+	TEXTURE_MAX_ANISOTROPY     = 0x84FE
+	MAX_TEXTURE_MAX_ANISOTROPY = 0x84FF
+)
+
 const (
 	capUndef    = 0
 	capDisabled = 1
@@ -137,6 +158,7 @@ func (gs *GLS) reset() {
 	gs.prog = nil
 
 	gs.activeTexture = uintUndef
+	gs.framebuffer = 0
 	gs.blendEquation = uintUndef
 	gs.blendSrc = uintUndef
 	gs.blendDst = uintUndef
@@ -150,6 +172,16 @@ func (gs *GLS) reset() {
 	gs.polygonModeMode = 0
 	gs.polygonOffsetFactor = -1
 	gs.polygonOffsetUnits = -1
+	gs.stencilFunc = uintUndef
+	gs.stencilRef = 0
+	gs.stencilFuncMask = 0
+	gs.stencilFail = uintUndef
+	gs.stencilZfail = uintUndef
+	gs.stencilZpass = uintUndef
+	gs.stencilMask = 0
+	gs.stencilMaskSet = false
+	gs.extensions = nil
+	gs.maxAnisotropy = -1
 }
 
 // setDefaultState is used internally to set the initial state of OpenGL
@@ -202,9 +234,26 @@ func (gs *GLS) BindBuffer(target int, vbo uint32) {
 	C.glBindBuffer(C.GLenum(target), C.GLuint(vbo))
 }
 
+// BindFramebuffer binds the specified framebuffer to the specified target.
+// The framebuffer 0 is the default framebuffer of the window.
+func (gs *GLS) BindFramebuffer(target uint32, fb uint32) {
+
+	C.glBindFramebuffer(C.GLenum(target), C.GLuint(fb))
+	if target == FRAMEBUFFER || target == DRAW_FRAMEBUFFER {
+		gs.framebuffer = fb
+	}
+}
+
+// Framebuffer returns the last framebuffer bound for drawing
+func (gs *GLS) Framebuffer() uint32 {
+
+	return gs.framebuffer
+}
+
 func (gs *GLS) BindTexture(target int, tex uint32) {
 
 	C.glBindTexture(C.GLenum(target), C.GLuint(tex))
+	gs.stats.Texbinds++
 }
 
 func (gs *GLS) BindVertexArray(vao uint32) {
@@ -259,6 +308,13 @@ func (gs *GLS) BufferData(target uint32, size int, data interface{}, usage uint3
 	C.glBufferData(C.GLenum(target), C.GLsizeiptr(size), ptr(data), C.GLenum(usage))
 }
 
+// CheckFramebufferStatus returns the completeness status of the
+// framebuffer bound to the specified target
+func (gs *GLS) CheckFramebufferStatus(target uint32) uint32 {
+
+	return uint32(C.glCheckFramebufferStatus(C.GLenum(target)))
+}
+
 func (gs *GLS) ClearColor(r, g, b, a float32) {
 
 	C.glClearColor(C.GLfloat(r), C.GLfloat(g), C.GLfloat(b), C.GLfloat(a))
@@ -269,6 +325,12 @@ func (gs *GLS) Clear(mask uint) {
 	C.glClear(C.GLbitfield(mask))
 }
 
+// ClearStencil sets the value used to clear the stencil buffer
+func (gs *GLS) ClearStencil(s int32) {
+
+	C.glClearStencil(C.GLint(s))
+}
+
 func (gs *GLS) CompileShader(shader uint32) {
 
 	C.glCompileShader(C.GLuint(shader))
@@ -292,6 +354,12 @@ func (gs *GLS) DeleteBuffers(bufs ...uint32) {
 	gs.stats.Buffers -= len(bufs)
 }
 
+// DeleteFramebuffers deletes the specified framebuffers
+func (gs *GLS) DeleteFramebuffers(fbs ...uint32) {
+
+	C.glDeleteFramebuffers(C.GLsizei(len(fbs)), (*C.GLuint)(&fbs[0]))
+}
+
 func (gs *GLS) DeleteShader(shader uint32) {
 
 	C.glDeleteShader(C.GLuint(shader))
@@ -343,12 +411,52 @@ func (gs *GLS) DrawArrays(mode uint32, first int32, count int32) {
 
 	C.glDrawArrays(C.GLenum(mode), C.GLint(first), C.GLsizei(count))
 	gs.stats.Drawcalls++
+	gs.countTriangles(mode, count, 1)
+}
+
+// DrawArraysInstanced draws the specified number of instances
+// of the primitives from the array data with a single draw call.
+func (gs *GLS) DrawArraysInstanced(mode uint32, first int32, count int32, instances int32) {
+
+	C.glDrawArraysInstanced(C.GLenum(mode), C.GLint(first), C.GLsizei(count), C.GLsizei(instances))
+	gs.stats.Drawcalls++
+	gs.countTriangles(mode, count, instances)
+}
+
+// DrawBuffer specifies the color buffer to be drawn into
+func (gs *GLS) DrawBuffer(mode uint32) {
+
+	C.glDrawBuffer(C.GLenum(mode))
 }
 
 func (gs *GLS) DrawElements(mode uint32, count int32, itype uint32, start uint32) {
 
 	C.glDrawElements(C.GLenum(mode), C.GLsizei(count), C.GLenum(itype), unsafe.Pointer(uintptr(start)))
 	gs.stats.Drawcalls++
+	gs.countTriangles(mode, count, 1)
+}
+
+// DrawElementsInstanced draws the specified number of instances
+// of the indexed primitives with a single draw call.
+func (gs *GLS) DrawElementsInstanced(mode uint32, count int32, itype uint32, start uint32, instances int32) {
+
+	C.glDrawElementsInstanced(C.GLenum(mode), C.GLsizei(count), C.GLenum(itype), unsafe.Pointer(uintptr(start)), C.GLsizei(instances))
+	gs.stats.Drawcalls++
+	gs.countTriangles(mode, count, instances)
+}
+
+// countTriangles adds to the statistics the number of triangles drawn
+// with the specified primitive, number of vertices and instances
+func (gs *GLS) countTriangles(mode uint32, count int32, instances int32) {
+
+	switch mode {
+	case TRIANGLES:
+		gs.stats.Triangles += uint64(count/3) * uint64(instances)
+	case TRIANGLE_STRIP, TRIANGLE_FAN:
+		if count > 2 {
+			gs.stats.Triangles += uint64(count-2) * uint64(instances)
+		}
+	}
 }
 
 func (gs *GLS) Enable(cap int) {
@@ -381,6 +489,13 @@ func (gs *GLS) CullFace(mode uint32) {
 	C.glCullFace(C.GLenum(mode))
 }
 
+// FramebufferTexture2D attaches the specified level of a texture
+// to the framebuffer bound to the specified target
+func (gs *GLS) FramebufferTexture2D(target, attachment, textarget uint32, texture uint32, level int32) {
+
+	C.glFramebufferTexture2D(C.GLenum(target), C.GLenum(attachment), C.GLenum(textarget), C.GLuint(texture), C.GLint(level))
+}
+
 func (gs *GLS) FrontFace(mode uint32) {
 
 	if gs.frontFace == mode {
@@ -398,6 +513,14 @@ func (gs *GLS) GenBuffer() uint32 {
 	return buf
 }
 
+// GenFramebuffer generates and returns a framebuffer name
+func (gs *GLS) GenFramebuffer() uint32 {
+
+	var fb uint32
+	C.glGenFramebuffers(1, (*C.GLuint)(&fb))
+	return fb
+}
+
 func (gs *GLS) GenerateMipmap(target uint32) {
 
 	C.glGenerateMipmap(C.GLenum(target))
@@ -454,12 +577,59 @@ func (gs *GLS) GetShaderInfoLog(shader uint32) string {
 	return string(gs.gobuf[:length])
 }
 
+// GetFloatv returns the value of the specified float parameter
+func (gs *GLS) GetFloatv(pname uint32, data *float32) {
+
+	C.glGetFloatv(C.GLenum(pname), (*C.GLfloat)(data))
+}
+
+// GetIntegerv returns the value of the specified integer parameter
+func (gs *GLS) GetIntegerv(pname uint32, data *int32) {
+
+	C.glGetIntegerv(C.GLenum(pname), (*C.GLint)(data))
+}
+
 func (gs *GLS) GetString(name uint32) string {
 
 	cs := C.glGetString(C.GLenum(name))
 	return C.GoString((*C.char)(unsafe.Pointer(cs)))
 }
 
+// GetStringi returns the string with the specified index of the specified indexed string parameter
+func (gs *GLS) GetStringi(name uint32, index uint32) string {
+
+	cs := C.glGetStringi(C.GLenum(name), C.GLuint(index))
+	return C.GoString((*C.char)(unsafe.Pointer(cs)))
+}
+
+// HasExtension returns if the specified OpenGL extension, such as
+// "GL_EXT_texture_filter_anisotropic", is supported by this context.
+func (gs *GLS) HasExtension(name string) bool {
+
+	if gs.extensions == nil {
+		gs.extensions = make(map[string]bool)
+		var count int32
+		gs.GetIntegerv(NUM_EXTENSIONS, &count)
+		for i := 0; i < int(count); i++ {
+			gs.extensions[gs.GetStringi(EXTENSIONS, uint32(i))] = true
+		}
+	}
+	return gs.extensions[name]
+}
+
+// MaxTextureAnisotropy returns the maximum texture anisotropy supported by
+// this context or 1 if anisotropic filtering is not supported.
+func (gs *GLS) MaxTextureAnisotropy() float32 {
+
+	if gs.maxAnisotropy < 0 {
+		gs.maxAnisotropy = 1
+		if gs.HasExtension("GL_EXT_texture_filter_anisotropic") || gs.HasExtension("GL_ARB_texture_filter_anisotropic") {
+			gs.GetFloatv(MAX_TEXTURE_MAX_ANISOTROPY, &gs.maxAnisotropy)
+		}
+	}
+	return gs.maxAnisotropy
+}
+
 // GetUniformLocation returns the location of a uniform variable for the specified program.
 func (gs *GLS) GetUniformLocation(program uint32, name string) int32 {
 
@@ -491,6 +661,12 @@ func (gs *GLS) GetShaderiv(shader, pname uint32, params *int32) {
 	C.glGetShaderiv(C.GLuint(shader), C.GLenum(pname), (*C.GLint)(params))
 }
 
+// ReadBuffer specifies the color buffer source for reading pixels
+func (gs *GLS) ReadBuffer(mode uint32) {
+
+	C.glReadBuffer(C.GLenum(mode))
+}
+
 func (gs *GLS) ShaderSource(shader uint32, src string) {
 
 	csource := gs.cbufStr(src)
@@ -510,6 +686,57 @@ func (gs *GLS) TexImage2D(target uint32, level int32, iformat int32, width int32
 		ptr(data))
 }
 
+// TexSubImage2D specifies a region of a two-dimensional texture image
+func (gs *GLS) TexSubImage2D(target uint32, level int32, xoffset, yoffset int32, width, height int32, format uint32, itype uint32, data interface{}) {
+
+	C.glTexSubImage2D(C.GLenum(target),
+		C.GLint(level),
+		C.GLint(xoffset),
+		C.GLint(yoffset),
+		C.GLsizei(width),
+		C.GLsizei(height),
+		C.GLenum(format),
+		C.GLenum(itype),
+		ptr(data))
+}
+
+// TexImage3D specifies a three-dimensional or two-dimensional array texture image
+func (gs *GLS) TexImage3D(target uint32, level int32, iformat int32, width int32, height int32, depth int32, border int32, format uint32, itype uint32, data interface{}) {
+
+	C.glTexImage3D(C.GLenum(target),
+		C.GLint(level),
+		C.GLint(iformat),
+		C.GLsizei(width),
+		C.GLsizei(height),
+		C.GLsizei(depth),
+		C.GLint(border),
+		C.GLenum(format),
+		C.GLenum(itype),
+		ptr(data))
+}
+
+// TexSubImage3D specifies a region of a three-dimensional or two-dimensional array texture image
+func (gs *GLS) TexSubImage3D(target uint32, level int32, xoffset, yoffset, zoffset int32, width, height, depth int32, format uint32, itype uint32, data interface{}) {
+
+	C.glTexSubImage3D(C.GLenum(target),
+		C.GLint(level),
+		C.GLint(xoffset),
+		C.GLint(yoffset),
+		C.GLint(zoffset),
+		C.GLsizei(width),
+		C.GLsizei(height),
+		C.GLsizei(depth),
+		C.GLenum(format),
+		C.GLenum(itype),
+		ptr(data))
+}
+
+// TexParameterf sets the specified float parameter of the texture bound to the specified target
+func (gs *GLS) TexParameterf(target uint32, pname uint32, param float32) {
+
+	C.glTexParameterf(C.GLenum(target), C.GLenum(pname), C.GLfloat(param))
+}
+
 func (gs *GLS) TexParameteri(target uint32, pname uint32, param int32) {
 
 	C.glTexParameteri(C.GLenum(target), C.GLenum(pname), C.GLint(param))
@@ -535,6 +762,42 @@ func (gs *GLS) PolygonOffset(factor float32, units float32) {
 	gs.polygonOffsetUnits = units
 }
 
+// StencilFunc sets the function, reference value and mask for the stencil test
+func (gs *GLS) StencilFunc(fn uint32, ref int32, mask uint32) {
+
+	if gs.stencilFunc == fn && gs.stencilRef == ref && gs.stencilFuncMask == mask {
+		return
+	}
+	C.glStencilFunc(C.GLenum(fn), C.GLint(ref), C.GLuint(mask))
+	gs.stencilFunc = fn
+	gs.stencilRef = ref
+	gs.stencilFuncMask = mask
+}
+
+// StencilOp sets the actions to take when the stencil test fails, when the
+// stencil test passes and the depth test fails and when both tests pass
+func (gs *GLS) StencilOp(fail, zfail, zpass uint32) {
+
+	if gs.stencilFail == fail && gs.stencilZfail == zfail && gs.stencilZpass == zpass {
+		return
+	}
+	C.glStencilOp(C.GLenum(fail), C.GLenum(zfail), C.GLenum(zpass))
+	gs.stencilFail = fail
+	gs.stencilZfail = zfail
+	gs.stencilZpass = zpass
+}
+
+// StencilMask sets the mask of the stencil bits which can be written
+func (gs *GLS) StencilMask(mask uint32) {
+
+	if gs.stencilMaskSet && gs.stencilMask == mask {
+		return
+	}
+	C.glStencilMask(C.GLuint(mask))
+	gs.stencilMask = mask
+	gs.stencilMaskSet = true
+}
+
 func (gs *GLS) Uniform1i(location int32, v0 int32) {
 
 	C.glUniform1i(C.GLint(location), C.GLint(v0))
@@ -601,6 +864,13 @@ func (gs *GLS) Uniform4fv(location int32, count int32, v []float32) {
 	gs.stats.Unisets++
 }
 
+// VertexAttribDivisor sets the number of instances drawn with each value of
+// the specified vertex attribute, or 0 to advance the attribute per vertex.
+func (gs *GLS) VertexAttribDivisor(index uint32, divisor uint32) {
+
+	C.glVertexAttribDivisor(C.GLuint(index), C.GLuint(divisor))
+}
+
 func (gs *GLS) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, offset uint32) {
 
 	C.glVertexAttribPointer(C.GLuint(index), C.GLint(size), C.GLenum(xtype), bool2c(normalized), C.GLsizei(stride), unsafe.Pointer(uintptr(offset)))
@@ -623,6 +893,7 @@ func (gs *GLS) UseProgram(prog *Program) {
 	}
 	C.glUseProgram(C.GLuint(prog.handle))
 	gs.prog = prog
+	gs.stats.Programs++
 
 	// Inserts program in cache if not already there.
 	if !gs.programs[prog] {
@@ -15,8 +15,11 @@ type VBO struct {
 	handle  uint32          // OpenGL handle for this VBO
 	usage   uint32          // Expected usage patter of the buffer
 	update  bool            // Update flag
+	version uint32          // Incremented each time the buffer is set or updated
 	buffer  math32.ArrayF32 // Data buffer
 	attribs []VBOattrib     // List of attributes
+	divisor uint32          // Number of instances per attribute value or 0 for per vertex
+	prog    *Program        // Program used to set the attributes locations
 }
 
 // VBOattrib describes one attribute of an OpenGL Vertex Buffer Object
@@ -86,6 +89,7 @@ func (vbo *VBO) Dispose() {
 		vbo.gs.DeleteBuffers(vbo.handle)
 	}
 	vbo.gs = nil
+	vbo.prog = nil
 }
 
 // Sets the VBO buffer
@@ -93,6 +97,7 @@ func (vbo *VBO) SetBuffer(buffer math32.ArrayF32) *VBO {
 
 	vbo.buffer = buffer
 	vbo.update = true
+	vbo.version++
 	return vbo
 }
 
@@ -103,6 +108,21 @@ func (vbo *VBO) SetUsage(usage uint32) {
 	vbo.usage = usage
 }
 
+// SetDivisor sets the number of instances drawn with each group of the
+// attributes of this VBO in instanced draw calls. The default value 0 advances
+// the attributes for each vertex and 1 advances them for each instance.
+func (vbo *VBO) SetDivisor(divisor uint32) {
+
+	vbo.divisor = divisor
+	vbo.prog = nil
+}
+
+// Divisor returns the current number of instances per group of attributes
+func (vbo *VBO) Divisor() uint32 {
+
+	return vbo.divisor
+}
+
 // Buffer returns pointer to the VBO buffer
 func (vbo *VBO) Buffer() *math32.ArrayF32 {
 
@@ -113,6 +133,14 @@ func (vbo *VBO) Buffer() *math32.ArrayF32 {
 func (vbo *VBO) Update() {
 
 	vbo.update = true
+	vbo.version++
+}
+
+// Version returns a number which changes each time the buffer
+// of this VBO is set or updated
+func (vbo *VBO) Version() uint32 {
+
+	return vbo.version
 }
 
 // Stride returns the stride of this VBO which is the number of bytes
@@ -140,6 +168,14 @@ func (vbo *VBO) Transfer(gs *GLS) {
 	// First time initialization
 	if vbo.gs == nil {
 		vbo.handle = gs.GenBuffer()
+		vbo.gs = gs // this indicates that the vbo was initialized
+	}
+
+	// Sets the attributes found in the current program. The attributes are
+	// set again when the program changes, as attributes not used by the
+	// previous program were not found. Without a program only the data
+	// is transferred.
+	if gs.prog != nil && vbo.prog != gs.prog {
 		gs.BindBuffer(ARRAY_BUFFER, vbo.handle)
 		// Calculates stride
 		stride := vbo.Stride()
@@ -150,16 +186,24 @@ func (vbo *VBO) Transfer(gs *GLS) {
 		for _, attrib := range vbo.attribs {
 			// Get attribute location in the current program
 			loc := gs.prog.GetAttribLocation(attrib.Name)
-			if loc < 0 {
-				continue
+			if loc >= 0 {
+				// Enables attribute and sets its stride and offset in the buffer.
+				// Matrix attributes use one location for each column of 4 elements.
+				for col := int32(0); col*4 < attrib.ItemSize; col++ {
+					size := attrib.ItemSize - col*4
+					if size > 4 {
+						size = 4
+					}
+					colLoc := uint32(loc + col)
+					gs.EnableVertexAttribArray(colLoc)
+					gs.VertexAttribPointer(colLoc, size, FLOAT, false, int32(stride), offset+uint32(elsize*col*4))
+					gs.VertexAttribDivisor(colLoc, vbo.divisor)
+				}
 			}
-			// Enables attribute and sets its stride and offset in the buffer
-			gs.EnableVertexAttribArray(uint32(loc))
-			gs.VertexAttribPointer(uint32(loc), attrib.ItemSize, FLOAT, false, int32(stride), offset)
 			items += uint32(attrib.ItemSize)
 			offset = uint32(elsize) * items
 		}
-		vbo.gs = gs // this indicates that the vbo was initialized
+		vbo.prog = gs.prog
 	}
 	if !vbo.update {
 		return
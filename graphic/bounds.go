@@ -0,0 +1,52 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphic
+
+import (
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/math32"
+)
+
+// ExpandByObject expands the specified box to contain the bounding boxes
+// of the geometries of the graphics in the tree of the specified node,
+// transformed to world coordinates, and returns the box. The bounding box
+// of an instanced mesh contains all its instances. The world matrices of
+// the node and its descendants are updated from the world matrix of its
+// parent. For example, to get the world bounds of a loaded model:
+//
+//	var box math32.Box3
+//	box.MakeEmpty()
+//	graphic.ExpandByObject(&box, model)
+func ExpandByObject(box *math32.Box3, inode core.INode) *math32.Box3 {
+
+	inode.UpdateMatrixWorld()
+	expandByObject(box, inode)
+	return box
+}
+
+// expandByObject expands the specified box by the graphics
+// of the tree of the specified node with updated world matrices
+func expandByObject(box *math32.Box3, inode core.INode) {
+
+	if igr, ok := inode.(IGraphic); ok {
+		geom := igr.GetGeometry()
+		matrixWorld := igr.GetNode().MatrixWorld()
+		if m, ok := inode.(*InstancedMesh); ok {
+			for i := 0; i < m.InstanceCount(); i++ {
+				var matrix math32.Matrix4
+				instance := m.InstanceMatrix(i)
+				matrix.MultiplyMatrices(&matrixWorld, &instance)
+				bbox := geom.BoundingBox()
+				box.Union(bbox.ApplyMatrix4(&matrix))
+			}
+		} else {
+			bbox := geom.BoundingBox()
+			box.Union(bbox.ApplyMatrix4(&matrixWorld))
+		}
+	}
+	for _, ichild := range inode.GetNode().Children() {
+		expandByObject(box, ichild)
+	}
+}
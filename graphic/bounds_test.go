@@ -0,0 +1,81 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphic
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+func TestExpandByObject(t *testing.T) {
+
+	mat := material.NewStandard(math32.NewColor(1, 1, 1))
+	newTree := func() (*core.Node, *core.Node) {
+		// A box 2 long in X rotated 90 degrees around Y, so it is 2 long in Z,
+		// in a translated and scaled parent with an empty node
+		root := core.NewNode()
+		root.SetPosition(10, 0, 0)
+		root.SetScale(2, 2, 2)
+		parent := core.NewNode()
+		child := NewMesh(geometry.NewBox(2, 1, 1, 1, 1, 1), mat)
+		child.SetPosition(1, 0, 0)
+		child.SetRotationY(math32.Pi / 2)
+		parent.Add(child)
+		parent.Add(core.NewNode())
+		root.Add(parent)
+		return root, parent
+	}
+	root, parent := newTree()
+
+	// An instanced mesh with cubes at the origin and 5 units along Y
+	instanced := NewInstancedMesh(geometry.NewBox(1, 1, 1, 1, 1, 1), mat, 2)
+	var m math32.Matrix4
+	m.MakeTranslation(0, 5, 0)
+	instanced.SetInstanceMatrix(1, &m)
+
+	cases := []struct {
+		name  string
+		inode core.INode
+		box   math32.Box3
+		want  math32.Box3
+	}{
+		{"rotated child", root, *math32.NewBox3(nil, nil),
+			math32.Box3{Min: math32.Vector3{X: 11, Y: -1, Z: -2}, Max: math32.Vector3{X: 13, Y: 1, Z: 2}}},
+		{"expanded box", root, math32.Box3{Min: math32.Vector3{X: -1, Y: -1, Z: -1}, Max: math32.Vector3{X: 1, Y: 1, Z: 1}},
+			math32.Box3{Min: math32.Vector3{X: -1, Y: -1, Z: -2}, Max: math32.Vector3{X: 13, Y: 1, Z: 2}}},
+		{"no graphics", core.NewNode(), *math32.NewBox3(nil, nil), *math32.NewBox3(nil, nil)},
+		{"instanced mesh", instanced, *math32.NewBox3(nil, nil),
+			math32.Box3{Min: math32.Vector3{X: -0.5, Y: -0.5, Z: -0.5}, Max: math32.Vector3{X: 0.5, Y: 5.5, Z: 0.5}}},
+	}
+	for _, c := range cases {
+		box := c.box
+		ExpandByObject(&box, c.inode)
+		if !nearBox3(&box, &c.want) {
+			t.Errorf("%s: ExpandByObject = %v, want %v", c.name, box, c.want)
+		}
+	}
+
+	// The world matrices of a subtree are updated from its parent
+	root, parent = newTree()
+	root.UpdateMatrixWorld()
+	var box math32.Box3
+	box.MakeEmpty()
+	ExpandByObject(&box, parent)
+	if want := cases[0].want; !nearBox3(&box, &want) {
+		t.Errorf("subtree: ExpandByObject = %v, want %v", box, want)
+	}
+}
+
+func nearBox3(a, b *math32.Box3) bool {
+
+	if a.Empty() || b.Empty() {
+		return a.Empty() == b.Empty()
+	}
+	return a.Min.DistanceTo(&b.Min) < 1e-4 && a.Max.DistanceTo(&b.Max) < 1e-4
+}
@@ -0,0 +1,69 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphic_test
+
+import (
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
+	"github.com/g3n/engine/texture"
+	"github.com/g3n/engine/window"
+)
+
+// This example renders a skybox from an equirectangular HDR image and
+// a reflective sphere using the same cube map as its environment map.
+// The camera orbits the sphere, so the skybox rotates with the view.
+func ExampleNewSkyboxCubemap() {
+
+	win, err := window.New("glfw", 800, 600, "Skybox", false)
+	if err != nil {
+		panic(err)
+	}
+	gs, err := gls.New()
+	if err != nil {
+		panic(err)
+	}
+	rend := renderer.NewRenderer(gs)
+	err = rend.AddDefaultShaders()
+	if err != nil {
+		panic(err)
+	}
+	cam := camera.NewPerspective(60, 800.0/600.0, 0.1, 100)
+
+	// The faces could also be loaded from six images with:
+	// texture.NewCubemap("px.png", "nx.png", "py.png", "ny.png", "pz.png", "nz.png")
+	env, err := texture.NewCubemapFromEquirect("environment.hdr", 512)
+	if err != nil {
+		panic(err)
+	}
+	scene := core.NewNode()
+	scene.Add(graphic.NewSkyboxCubemap(env))
+
+	chrome := material.NewPhysical()
+	chrome.SetMetallicFactor(1)
+	chrome.SetRoughnessFactor(0.1)
+	chrome.SetEnvCubemap(env)
+	sphere := graphic.NewMesh(geometry.NewSphere(1, 64, 32, 0, 2*math32.Pi, 0, math32.Pi), chrome)
+	scene.Add(sphere)
+
+	var angle float32
+	for !win.ShouldClose() {
+		angle += 0.005
+		cam.SetPosition(4*math32.Sin(angle), 1, 4*math32.Cos(angle))
+		cam.LookAt(&math32.Vector3{})
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
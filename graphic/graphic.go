@@ -21,6 +21,10 @@ type Graphic struct {
 	materials  []GraphicMaterial  // Materials
 	mode       uint32             // OpenGL primitive
 	renderable bool               // Renderable flag
+	castShadow bool               // Cast shadows flag
+	culled     bool               // Frustum culling flag
+	instanced  bool               // Drawn with instanced draw calls
+	instances  int                // Number of instances drawn
 }
 
 // GraphicMaterial specifies the material to be used for
@@ -61,6 +65,7 @@ func (gr *Graphic) Init(igeom geometry.IGeometry, mode uint32) *Graphic {
 	gr.mode = mode
 	gr.materials = make([]GraphicMaterial, 0)
 	gr.renderable = true
+	gr.culled = true
 	return gr
 }
 
@@ -78,12 +83,26 @@ func (gr *Graphic) GetGeometry() *geometry.Geometry {
 	return gr.igeom.GetGeometry()
 }
 
-// Dispose overrides the embedded Node Dispose method
+// Dispose overrides the embedded Node Dispose method and releases
+// the references of this graphic to its geometry and materials,
+// which are disposed when they are not shared with other graphics.
+// A material used by several graphic materials of this graphic
+// is released only once.
 func (gr *Graphic) Dispose() {
 
 	gr.igeom.Dispose()
 	for i := 0; i < len(gr.materials); i++ {
-		gr.materials[i].imat.Dispose()
+		imat := gr.materials[i].imat
+		shared := false
+		for j := 0; j < i; j++ {
+			if gr.materials[j].imat == imat {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			imat.Dispose()
+		}
 	}
 }
 
@@ -101,6 +120,41 @@ func (gr *Graphic) Renderable() bool {
 	return gr.renderable
 }
 
+// SetCastShadow sets if this graphic casts shadows from the lights
+// which have cast shadow enabled (default = false)
+func (gr *Graphic) SetCastShadow(state bool) {
+
+	gr.castShadow = state
+}
+
+// CastShadow returns if this graphic casts shadows
+func (gr *Graphic) CastShadow() bool {
+
+	return gr.castShadow
+}
+
+// SetFrustumCulled sets if this graphic is skipped by the renderer when its
+// bounding sphere is outside the view volume of the camera (default = true).
+// It should be disabled for graphics whose vertices are moved by the shader
+// or which are not positioned by the camera, as their bounds are not meaningful.
+func (gr *Graphic) SetFrustumCulled(state bool) {
+
+	gr.culled = state
+}
+
+// FrustumCulled returns if this graphic is skipped when outside the camera view volume
+func (gr *Graphic) FrustumCulled() bool {
+
+	return gr.culled
+}
+
+// Instanced returns if this graphic is drawn with instanced draw calls.
+// The renderer then selects the instanced variant of the material shader.
+func (gr *Graphic) Instanced() bool {
+
+	return gr.instanced
+}
+
 // Add material for the specified subset of vertices.
 // If the material applies to all vertices, start and count must be 0.
 func (gr *Graphic) AddMaterial(igr IGraphic, imat material.IMaterial, start, count int) {
@@ -114,6 +168,44 @@ func (gr *Graphic) AddMaterial(igr IGraphic, imat material.IMaterial, start, cou
 	gr.materials = append(gr.materials, gmat)
 }
 
+// InsertMaterial inserts a material for the specified subset of vertices
+// at the specified position of this graphic materials list.
+// Materials are rendered in the order of this list.
+// If the material applies to all vertices, start and count must be 0.
+func (gr *Graphic) InsertMaterial(pos int, igr IGraphic, imat material.IMaterial, start, count int) {
+
+	if pos < 0 || pos > len(gr.materials) {
+		panic("Invalid material position")
+	}
+	gmat := GraphicMaterial{
+		imat:     imat,
+		start:    start,
+		count:    count,
+		igraphic: igr,
+	}
+	gr.materials = append(gr.materials, GraphicMaterial{})
+	copy(gr.materials[pos+1:], gr.materials[pos:])
+	gr.materials[pos] = gmat
+}
+
+// RemoveMaterial removes all the graphic materials which use the
+// specified material and returns true if any was found.
+// The removed material is not disposed.
+func (gr *Graphic) RemoveMaterial(imat material.IMaterial) bool {
+
+	found := false
+	materials := gr.materials[:0]
+	for _, gmat := range gr.materials {
+		if gmat.imat == imat {
+			found = true
+			continue
+		}
+		materials = append(materials, gmat)
+	}
+	gr.materials = materials
+	return found
+}
+
 // Add group material
 func (gr *Graphic) AddGroupMaterial(igr IGraphic, imat material.IMaterial, gindex int) {
 
@@ -139,7 +231,7 @@ func (gr *Graphic) GetMaterial(vpos int) material.IMaterial {
 		if gmat.count == 0 {
 			return gmat.imat
 		}
-		if gmat.start >= vpos && gmat.start+gmat.count <= vpos {
+		if vpos >= gmat.start && vpos < gmat.start+gmat.count {
 			return gmat.imat
 		}
 	}
@@ -151,14 +243,33 @@ func (grmat *GraphicMaterial) GetMaterial() material.IMaterial {
 	return grmat.imat
 }
 
+// GetGraphic returns the graphic which contains this graphic material
+func (grmat *GraphicMaterial) GetGraphic() IGraphic {
+
+	return grmat.igraphic
+}
+
 // Render is called by the renderer to render this graphic material
 func (grmat *GraphicMaterial) Render(gs *gls.GLS, rinfo *core.RenderInfo) {
 
 	// Setup the associated material (set states and transfer material uniforms and textures)
 	grmat.imat.RenderSetup(gs)
 
-	// Setup the associated geometry (set VAO and transfer VBOS)
+	grmat.RenderGeometry(gs, rinfo)
+}
+
+// RenderGeometry draws the vertices of this graphic material using the
+// current shader program and states without setting up the material.
+// It is used to render the depth of the graphics into shadow maps.
+func (grmat *GraphicMaterial) RenderGeometry(gs *gls.GLS, rinfo *core.RenderInfo) {
+
+	// Nothing to draw for instanced graphics without instances
 	gr := grmat.igraphic.GetGraphic()
+	if gr.instanced && gr.instances == 0 {
+		return
+	}
+
+	// Setup the associated geometry (set VAO and transfer VBOS)
 	gr.igeom.RenderSetup(gs)
 
 	// Setup current graphic (transfer matrices)
@@ -174,12 +285,20 @@ func (grmat *GraphicMaterial) Render(gs *gls.GLS, rinfo *core.RenderInfo) {
 		if count == 0 {
 			count = indices.Size()
 		}
-		gs.DrawElements(gr.mode, int32(count), gls.UNSIGNED_INT, 4*uint32(grmat.start))
+		if gr.instanced {
+			gs.DrawElementsInstanced(gr.mode, int32(count), gls.UNSIGNED_INT, 4*uint32(grmat.start), int32(gr.instances))
+		} else {
+			gs.DrawElements(gr.mode, int32(count), gls.UNSIGNED_INT, 4*uint32(grmat.start))
+		}
 		// Non indexed geometry
 	} else {
 		if count == 0 {
 			count = geom.Items()
 		}
-		gs.DrawArrays(gr.mode, int32(grmat.start), int32(count))
+		if gr.instanced {
+			gs.DrawArraysInstanced(gr.mode, int32(grmat.start), int32(count), int32(gr.instances))
+		} else {
+			gs.DrawArrays(gr.mode, int32(grmat.start), int32(count))
+		}
 	}
 }
@@ -0,0 +1,145 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphic
+
+import (
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+// InstancedMesh is a mesh which draws many copies of its geometry with a
+// single draw call for each material, with a transform and a color for each
+// instance. The transforms are applied in the mesh model coordinates, before
+// the mesh world transform, and the colors multiply the material colors.
+// Drawing thousands of repeated objects such as trees or rocks as instances
+// avoids the cost of one draw call and one set of uniforms for each object.
+// The instance attributes are added to the geometry, which must not be shared
+// with other meshes. Instancing is supported by the standard material and by
+// the shadow maps. Frustum culling is disabled by default as the geometry
+// bounds do not include the instances transforms. For example, to draw a forest of 1000 trees:
+//
+//	trees := graphic.NewInstancedMesh(treeGeom, treeMat, 1000)
+//	for i := 0; i < trees.InstanceCount(); i++ {
+//		pos := math32.Vector3{rand.Float32()*100 - 50, 0, rand.Float32()*100 - 50}
+//		rot := math32.NewQuaternion(0, 0, 0, 1)
+//		rot.SetFromAxisAngle(&math32.Vector3{0, 1, 0}, rand.Float32()*2*math32.Pi)
+//		scale := 0.8 + rand.Float32()*0.4
+//		trees.SetInstanceTransform(i, &pos, rot, &math32.Vector3{scale, scale, scale})
+//	}
+//	scene.Add(trees)
+type InstancedMesh struct {
+	Mesh                   // Embedded mesh
+	vbo    *gls.VBO        // VBO with the instances attributes
+	buffer math32.ArrayF32 // Instances attributes buffer
+}
+
+// Number of floats of the attributes of each instance:
+// the 4x4 transform matrix followed by the RGB color
+const instanceSize = 16 + 3
+
+// NewInstancedMesh creates and returns a pointer to a new instanced mesh with
+// the specified geometry and material and number of instances, which are
+// initialized with the identity transform and the white color.
+func NewInstancedMesh(igeom geometry.IGeometry, imat material.IMaterial, count int) *InstancedMesh {
+
+	m := new(InstancedMesh)
+	m.Mesh.Init(igeom, imat)
+	m.instanced = true
+	m.SetFrustumCulled(false)
+	m.vbo = gls.NewVBO().
+		AddAttrib("InstanceMatrix", 16).
+		AddAttrib("InstanceColor", 3)
+	m.vbo.SetUsage(gls.DYNAMIC_DRAW)
+	m.vbo.SetDivisor(1)
+	m.GetGeometry().AddVBO(m.vbo)
+	m.SetInstanceCount(count)
+	return m
+}
+
+// SetInstanceCount sets the number of instances drawn. The existing instances
+// are kept and the new ones are initialized with the identity transform and
+// the white color.
+func (m *InstancedMesh) SetInstanceCount(count int) {
+
+	if count < 0 {
+		count = 0
+	}
+	var identity math32.Matrix4
+	identity.Identity()
+	for i := len(m.buffer) / instanceSize; i < count; i++ {
+		m.buffer.Append(identity[:]...)
+		m.buffer.Append(1, 1, 1)
+	}
+	m.buffer = m.buffer[:count*instanceSize]
+	m.instances = count
+	m.vbo.SetBuffer(m.buffer)
+}
+
+// InstanceCount returns the current number of instances
+func (m *InstancedMesh) InstanceCount() int {
+
+	return m.instances
+}
+
+// SetInstanceMatrix sets the transform matrix of the instance
+// with the specified index
+func (m *InstancedMesh) SetInstanceMatrix(i int, mat *math32.Matrix4) {
+
+	m.buffer.Set(i*instanceSize, mat[:]...)
+	m.vbo.Update()
+}
+
+// InstanceMatrix returns the transform matrix of the instance
+// with the specified index
+func (m *InstancedMesh) InstanceMatrix(i int) math32.Matrix4 {
+
+	var mat math32.Matrix4
+	copy(mat[:], m.buffer[i*instanceSize:])
+	return mat
+}
+
+// SetInstanceTransform sets the transform matrix of the instance with the
+// specified index from the specified position, rotation and scale
+func (m *InstancedMesh) SetInstanceTransform(i int, position *math32.Vector3, quaternion *math32.Quaternion, scale *math32.Vector3) {
+
+	var mat math32.Matrix4
+	mat.Compose(position, quaternion, scale)
+	m.SetInstanceMatrix(i, &mat)
+}
+
+// SetInstanceColor sets the color of the instance with the specified index,
+// which multiplies the material colors
+func (m *InstancedMesh) SetInstanceColor(i int, color *math32.Color) {
+
+	m.buffer.SetColor(i*instanceSize+16, color)
+	m.vbo.Update()
+}
+
+// InstanceColor returns the color of the instance with the specified index
+func (m *InstancedMesh) InstanceColor(i int) math32.Color {
+
+	var color math32.Color
+	m.buffer.GetColor(i*instanceSize+16, &color)
+	return color
+}
+
+// Raycast checks intersections between the instances of this mesh and the
+// specified raycaster and if any found appends them to the specified
+// intersects array with this mesh as the intersected object.
+func (m *InstancedMesh) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
+
+	matrixWorld := m.MatrixWorld()
+	for i := 0; i < m.instances; i++ {
+		mat := m.InstanceMatrix(i)
+		if mat.Determinant() == 0 {
+			continue
+		}
+		mat.MultiplyMatrices(&matrixWorld, &mat)
+		m.raycast(rc, &mat, m, intersects)
+	}
+}
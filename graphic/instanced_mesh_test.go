@@ -0,0 +1,160 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphic
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+func TestInstancedMeshCount(t *testing.T) {
+
+	m := NewInstancedMesh(geometry.NewBox(1, 1, 1, 1, 1, 1), material.NewStandard(math32.NewColor(1, 1, 1)), 2)
+	if m.InstanceCount() != 2 {
+		t.Fatalf("InstanceCount = %d, want 2", m.InstanceCount())
+	}
+	var mat math32.Matrix4
+	mat.MakeTranslation(1, 2, 3)
+	m.SetInstanceMatrix(1, &mat)
+	m.SetInstanceColor(1, math32.NewColor(1, 0, 0))
+
+	// Growing keeps the existing instances and initializes the new ones
+	m.SetInstanceCount(4)
+	if m.InstanceCount() != 4 || len(m.buffer) != 4*instanceSize {
+		t.Fatalf("grown: InstanceCount = %d, buffer = %d, want 4, %d", m.InstanceCount(), len(m.buffer), 4*instanceSize)
+	}
+	var identity math32.Matrix4
+	identity.Identity()
+	for i, want := range []math32.Matrix4{identity, mat, identity, identity} {
+		if got := m.InstanceMatrix(i); got != want {
+			t.Errorf("grown: InstanceMatrix(%d) = %v, want %v", i, got, want)
+		}
+	}
+	for i, want := range []math32.Color{{1, 1, 1}, {1, 0, 0}, {1, 1, 1}, {1, 1, 1}} {
+		if got := m.InstanceColor(i); got != want {
+			t.Errorf("grown: InstanceColor(%d) = %v, want %v", i, got, want)
+		}
+	}
+
+	// Shrinking keeps the first instances
+	m.SetInstanceCount(1)
+	m.SetInstanceCount(2)
+	if got := m.InstanceMatrix(1); got != identity {
+		t.Errorf("shrunk: InstanceMatrix(1) = %v, want identity", got)
+	}
+	m.SetInstanceCount(-1)
+	if m.InstanceCount() != 0 || m.vbo.Buffer().Len() != 0 {
+		t.Errorf("negative: InstanceCount = %d, buffer = %d, want 0, 0", m.InstanceCount(), m.vbo.Buffer().Len())
+	}
+}
+
+func TestInstancedMeshTransform(t *testing.T) {
+
+	m := NewInstancedMesh(geometry.NewBox(1, 1, 1, 1, 1, 1), material.NewStandard(math32.NewColor(1, 1, 1)), 1)
+	version := m.vbo.Version()
+	pos := math32.Vector3{1, 2, 3}
+	rot := math32.NewQuaternion(0, 0, 0, 1)
+	rot.SetFromAxisAngle(&math32.Vector3{0, 1, 0}, math32.Pi/2)
+	scale := math32.Vector3{2, 2, 2}
+	m.SetInstanceTransform(0, &pos, rot, &scale)
+	if m.vbo.Version() == version {
+		t.Errorf("VBO version not changed by SetInstanceTransform")
+	}
+
+	// The instance transform scales, rotates and then translates
+	mat := m.InstanceMatrix(0)
+	v := math32.Vector3{1, 0, 0}
+	v.ApplyMatrix4(&mat)
+	want := math32.Vector3{1, 2, 1}
+	if math32.Abs(v.X-want.X) > 1e-5 || math32.Abs(v.Y-want.Y) > 1e-5 || math32.Abs(v.Z-want.Z) > 1e-5 {
+		t.Errorf("transformed point = %v, want %v", v, want)
+	}
+}
+
+func TestInstancedMeshRaycast(t *testing.T) {
+
+	m := NewInstancedMesh(geometry.NewBox(1, 1, 1, 1, 1, 1), material.NewStandard(math32.NewColor(1, 1, 1)), 3)
+	for i, x := range []float32{-3, 0, 3} {
+		var mat math32.Matrix4
+		mat.MakeTranslation(x, 0, 0)
+		m.SetInstanceMatrix(i, &mat)
+	}
+	// The instances are moved with the mesh
+	m.SetPosition(0, 10, 0)
+	m.UpdateMatrixWorld()
+
+	cases := []struct {
+		name   string
+		origin math32.Vector3
+		want   []math32.Vector3
+	}{
+		// Only the front faces are intersected
+		{"left", math32.Vector3{-3.2, 10.1, 5}, []math32.Vector3{{-3.2, 10.1, 0.5}}},
+		{"right", math32.Vector3{3.2, 10.1, 5}, []math32.Vector3{{3.2, 10.1, 0.5}}},
+		{"between", math32.Vector3{1.5, 10, 5}, nil},
+		{"mesh position only", math32.Vector3{-3, 0, 5}, nil},
+	}
+	for _, c := range cases {
+		rc := core.NewRaycaster(&c.origin, &math32.Vector3{0, 0, -1})
+		intersects := rc.IntersectObject(m, false)
+		if len(intersects) != len(c.want) {
+			t.Errorf("%s: %d intersects, want %d", c.name, len(intersects), len(c.want))
+			continue
+		}
+		for i, is := range intersects {
+			if is.Object != m {
+				t.Errorf("%s: intersected object %v, want the instanced mesh", c.name, is.Object)
+			}
+			p := c.want[i]
+			if math32.Abs(is.Point.X-p.X) > 1e-4 || math32.Abs(is.Point.Y-p.Y) > 1e-4 || math32.Abs(is.Point.Z-p.Z) > 1e-4 {
+				t.Errorf("%s: intersect %d point = %v, want %v", c.name, i, is.Point, p)
+			}
+		}
+	}
+}
+
+// The benchmarks compare the CPU time per frame of updating the transforms of
+// 10000 objects as the instances of one mesh and as individual meshes, without
+// rendering them. The renderer package benchmarks the rendering of both with
+// the -gl flag.
+const benchmarkObjects = 10000
+
+func BenchmarkInstancedMeshUpdate(b *testing.B) {
+
+	m := NewInstancedMesh(geometry.NewPlane(0.1, 1, 1, 1), material.NewStandard(math32.NewColor(0, 1, 0)), benchmarkObjects)
+	rot := math32.NewQuaternion(0, 0, 0, 1)
+	scale := math32.Vector3{1, 1, 1}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchmarkObjects; j++ {
+			pos := math32.Vector3{float32(j % 100), 0, float32(j/100) + float32(i%2)*0.1}
+			m.SetInstanceTransform(j, &pos, rot, &scale)
+		}
+		m.UpdateMatrixWorld()
+	}
+}
+
+func BenchmarkMeshesUpdate(b *testing.B) {
+
+	geom := geometry.NewPlane(0.1, 1, 1, 1)
+	mat := material.NewStandard(math32.NewColor(0, 1, 0))
+	scene := core.NewNode()
+	meshes := make([]*Mesh, benchmarkObjects)
+	for j := range meshes {
+		meshes[j] = NewMesh(geom, mat)
+		scene.Add(meshes[j])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, mesh := range meshes {
+			mesh.SetPosition(float32(j%100), 0, float32(j/100)+float32(i%2)*0.1)
+		}
+		scene.UpdateMatrixWorld()
+	}
+}
@@ -84,12 +84,20 @@ func (m *Mesh) RenderSetup(gs *gls.GLS, rinfo *core.RenderInfo) {
 // and if any found appends it to the specified intersects array.
 func (m *Mesh) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
 
+	matrixWorld := m.MatrixWorld()
+	m.raycast(rc, &matrixWorld, m, intersects)
+}
+
+// raycast checks intersections between this geometry transformed by the
+// specified world matrix and the specified raycaster and if any found
+// appends them to the specified intersects array with the specified object.
+func (m *Mesh) raycast(rc *core.Raycaster, matrixWorld *math32.Matrix4, object core.INode, intersects *[]core.Intersect) {
+
 	// Transform this mesh geometry bounding sphere from model
 	// to world coordinates and checks intersection with raycaster
 	geom := m.GetGeometry()
 	sphere := geom.BoundingSphere()
-	matrixWorld := m.MatrixWorld()
-	sphere.ApplyMatrix4(&matrixWorld)
+	sphere.ApplyMatrix4(matrixWorld)
 	if !rc.IsIntersectionSphere(&sphere) {
 		return
 	}
@@ -99,7 +107,7 @@ func (m *Mesh) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
 	// the geometry, as is much less expensive to transform the
 	// ray to model coordinates than the geometry to world coordinates.
 	var inverseMatrix math32.Matrix4
-	inverseMatrix.GetInverse(&matrixWorld, true)
+	inverseMatrix.GetInverse(matrixWorld, true)
 	var ray math32.Ray
 	ray.Copy(&rc.Ray).ApplyMatrix4(&inverseMatrix)
 	bbox := geom.BoundingBox()
@@ -109,24 +117,25 @@ func (m *Mesh) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
 
 	// Local function to check the intersection of the ray from the raycaster with
 	// the specified face defined by three poins.
-	checkIntersection := func(mat *material.Material, pA, pB, pC, point *math32.Vector3) *core.Intersect {
+	checkIntersection := func(mat *material.Material, pA, pB, pC *math32.Vector3) *core.Intersect {
 
+		var point math32.Vector3
 		var intersect bool
 		switch mat.Side() {
 		case material.SideBack:
-			intersect = ray.IntersectTriangle(pC, pB, pA, true, point)
+			point, intersect = ray.IntersectTriangle(pC, pB, pA, true)
 		case material.SideFront:
-			intersect = ray.IntersectTriangle(pA, pB, pC, true, point)
+			point, intersect = ray.IntersectTriangle(pA, pB, pC, true)
 		case material.SideDouble:
-			intersect = ray.IntersectTriangle(pA, pB, pC, false, point)
+			point, intersect = ray.IntersectTriangle(pA, pB, pC, false)
 		}
 		if !intersect {
 			return nil
 		}
 
 		// Transform intersection point from model to world coordinates
-		var intersectionPointWorld = *point
-		intersectionPointWorld.ApplyMatrix4(&matrixWorld)
+		var intersectionPointWorld = point
+		intersectionPointWorld.ApplyMatrix4(matrixWorld)
 
 		// Calculates the distance from the ray origin to intersection point
 		origin := rc.Ray.Origin()
@@ -140,7 +149,7 @@ func (m *Mesh) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
 		return &core.Intersect{
 			Distance: distance,
 			Point:    intersectionPointWorld,
-			Object:   m,
+			Object:   object,
 		}
 	}
 
@@ -168,9 +177,11 @@ func (m *Mesh) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
 			positions.GetVector3(int(3*b), &vB)
 			positions.GetVector3(int(3*c), &vC)
 			// Checks intersection of the ray with this face
-			mat := m.GetMaterial(i).GetMaterial()
-			var point math32.Vector3
-			intersect := checkIntersection(mat, &vA, &vB, &vC, &point)
+			imat := m.GetMaterial(i)
+			if imat == nil {
+				continue
+			}
+			intersect := checkIntersection(imat.GetMaterial(), &vA, &vB, &vC)
 			if intersect != nil {
 				intersect.Index = uint32(i)
 				*intersects = append(*intersects, *intersect)
@@ -188,9 +199,11 @@ func (m *Mesh) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
 			positions.GetVector3(int(3*b), &vB)
 			positions.GetVector3(int(3*c), &vC)
 			// Checks intersection of the ray with this face
-			mat := m.GetMaterial(i).GetMaterial()
-			var point math32.Vector3
-			intersect := checkIntersection(mat, &vA, &vB, &vC, &point)
+			imat := m.GetMaterial(a)
+			if imat == nil {
+				continue
+			}
+			intersect := checkIntersection(imat.GetMaterial(), &vA, &vB, &vC)
 			if intersect != nil {
 				intersect.Index = uint32(a)
 				*intersects = append(*intersects, *intersect)
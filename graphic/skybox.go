@@ -33,6 +33,8 @@ func NewSkybox(data SkyboxData) (*Skybox, error) {
 
 	geom := geometry.NewBox(50, 50, 50, 1, 1, 1)
 	skybox.Graphic.Init(geom, gls.TRIANGLES)
+	// The skybox follows the camera
+	skybox.SetFrustumCulled(false)
 
 	for i := 0; i < 6; i++ {
 		tex, err := texture.NewTexture2DFromImage(data.DirAndPrefix + data.Suffixes[i] + "." + data.Extension)
@@ -54,6 +56,27 @@ func NewSkybox(data SkyboxData) (*Skybox, error) {
 	return skybox, nil
 }
 
+// NewSkyboxCubemap creates and returns a pointer to a skybox rendering the
+// specified cube map with the view rotation of the camera.
+// The cube map may also be used as the environment map of reflective materials.
+func NewSkyboxCubemap(cubemap *texture.Cubemap) *Skybox {
+
+	skybox := new(Skybox)
+
+	geom := geometry.NewBox(2, 2, 2, 1, 1, 1)
+	skybox.Graphic.Init(geom, gls.TRIANGLES)
+	// The skybox follows the camera
+	skybox.SetFrustumCulled(false)
+	skybox.AddMaterial(skybox, material.NewSkybox(cubemap), 0, 0)
+
+	// Creates uniforms
+	skybox.mvm.Init("ModelViewMatrix")
+	skybox.mvpm.Init("MVP")
+	skybox.nm.Init("NormalMatrix")
+
+	return skybox
+}
+
 // RenderSetup is called by the engine before drawing the skybox geometry
 // It is responsible to updating the current shader uniforms with
 // the model matrices.
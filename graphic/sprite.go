@@ -135,8 +135,8 @@ func (s *Sprite) Raycast(rc *core.Raycaster, intersects *[]core.Intersect) {
 		pos = indices[i+2]
 		buffer.GetVector3(int(pos*5), &v3)
 		v3.ApplyMatrix4(&mv)
-		if ray.IntersectTriangle(&v1, &v2, &v3, false, &point) {
-			intersect = true
+		point, intersect = ray.IntersectTriangle(&v1, &v2, &v3, false)
+		if intersect {
 			break
 		}
 	}
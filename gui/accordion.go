@@ -0,0 +1,407 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+/***************************************
+
+ Accordion
+ +--------------------------------+
+ | v header 0                     |
+ +--------------------------------+
+ |                                |
+ |  body 0                        |
+ |                                |
+ +--------------------------------+
+ | > header 1                     |
+ +--------------------------------+
+ | > header 2                     |
+ +--------------------------------+
+
+**/
+
+// Accordion is a vertical stack of sections where each section has a
+// header which can be clicked to expand or collapse the section body.
+// In the exclusive mode only one section can be expanded at a time.
+// OnExpand and OnCollapse are dispatched with the index of the section.
+// The accordion height changes when sections are expanded or collapsed
+// and the layout of its parent is recalculated.
+type Accordion struct {
+	Panel                         // Embedded panel
+	sections  []*accordionSection // sections in display order
+	styles    *AccordionStyles    // pointer to current styles
+	exclusive bool                // only one section can be expanded
+	animated  bool                // animates the expansion and collapse of sections
+	animID    int                 // id of the animation timer (0 = none)
+	animTime  time.Time           // time of the last animation step
+}
+
+// accordionSection is a section of an Accordion
+type accordionSection struct {
+	header   Panel   // header panel
+	icon     Label   // expanded/collapsed icon
+	label    Label   // header title
+	clip     Panel   // container which clips the body during the animation
+	body     IPanel  // body panel
+	expanded bool    // section is expanded
+	frac     float32 // visible fraction of the body height (0 to 1)
+	over     bool    // cursor is over the header
+}
+
+// AccordionStyle contains the styling of the Accordion section headers
+type AccordionStyle struct {
+	Border      BorderSizes
+	Paddings    BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color4
+	FgColor     math32.Color
+	Icons       [2]int // icons for the collapsed and expanded states
+}
+
+// AccordionStyles contains all the Accordion section headers styles
+type AccordionStyles struct {
+	Normal   AccordionStyle
+	Over     AccordionStyle
+	Disabled AccordionStyle
+}
+
+const (
+	accordionAnimTime = 150 * time.Millisecond // duration of the expand or collapse animation
+)
+
+// NewAccordion creates and returns a pointer to a new accordion
+// with the specified width and no sections
+func NewAccordion(width float32) *Accordion {
+
+	a := new(Accordion)
+	a.styles = &StyleDefault.Accordion
+	a.animated = true
+	a.Panel.Initialize(width, 0)
+	a.Panel.Subscribe(OnResize, func(evname string, ev interface{}) { a.recalc() })
+	a.Panel.Subscribe(OnEnable, func(evname string, ev interface{}) { a.update() })
+	return a
+}
+
+// AddSection appends a new collapsed section with the specified header
+// title and body panel and returns its index
+func (a *Accordion) AddSection(title string, body IPanel) int {
+
+	s := new(accordionSection)
+	s.header.Initialize(0, 0)
+	s.icon.initialize("", StyleDefault.FontIcon)
+	s.icon.SetFontSize(StyleDefault.Font.Size() * 1.3)
+	s.header.Add(&s.icon)
+	s.label.initialize(title, StyleDefault.Font)
+	s.header.Add(&s.label)
+	s.header.Subscribe(OnMouseDown, func(evname string, ev interface{}) { a.onMouse(s, ev) })
+	s.header.Subscribe(OnCursorEnter, func(evname string, ev interface{}) { a.onCursor(s, evname) })
+	s.header.Subscribe(OnCursorLeave, func(evname string, ev interface{}) { a.onCursor(s, evname) })
+	a.Panel.Add(&s.header)
+
+	s.clip.Initialize(0, 0)
+	s.clip.SetVisible(false)
+	s.body = body
+	s.clip.Add(body)
+	body.GetPanel().Subscribe(OnResize, func(evname string, ev interface{}) { a.recalc() })
+	a.Panel.Add(&s.clip)
+
+	a.sections = append(a.sections, s)
+	a.update()
+	a.recalc()
+	return len(a.sections) - 1
+}
+
+// RemoveSection removes the section with the specified index
+// and returns its body panel or nil if the index is invalid
+func (a *Accordion) RemoveSection(idx int) IPanel {
+
+	if idx < 0 || idx >= len(a.sections) {
+		log.Warn("Invalid Accordion section index")
+		return nil
+	}
+	s := a.sections[idx]
+	copy(a.sections[idx:], a.sections[idx+1:])
+	a.sections[len(a.sections)-1] = nil
+	a.sections = a.sections[:len(a.sections)-1]
+	s.clip.Remove(s.body)
+	a.Panel.Remove(&s.header)
+	a.Panel.Remove(&s.clip)
+	a.recalc()
+	return s.body
+}
+
+// SectionCount returns the number of sections of this accordion
+func (a *Accordion) SectionCount() int {
+
+	return len(a.sections)
+}
+
+// Body returns the body panel of the section with the specified index
+// or nil if the index is invalid
+func (a *Accordion) Body(idx int) IPanel {
+
+	if idx < 0 || idx >= len(a.sections) {
+		return nil
+	}
+	return a.sections[idx].body
+}
+
+// SetTitle sets the header title of the section with the specified index
+func (a *Accordion) SetTitle(idx int, title string) {
+
+	if idx < 0 || idx >= len(a.sections) {
+		log.Warn("Invalid Accordion section index")
+		return
+	}
+	a.sections[idx].label.SetText(title)
+	a.recalc()
+}
+
+// SetExpanded expands or collapses the section with the specified index.
+// In the exclusive mode expanding a section collapses the others.
+func (a *Accordion) SetExpanded(idx int, state bool) {
+
+	if idx < 0 || idx >= len(a.sections) {
+		log.Warn("Invalid Accordion section index")
+		return
+	}
+	a.setExpanded(idx, state)
+}
+
+// Expanded returns if the section with the specified index is expanded
+func (a *Accordion) Expanded(idx int) bool {
+
+	if idx < 0 || idx >= len(a.sections) {
+		return false
+	}
+	return a.sections[idx].expanded
+}
+
+// SetExclusive sets the exclusive mode in which only one section can be
+// expanded at a time. When set, all the expanded sections except the
+// first one are collapsed.
+func (a *Accordion) SetExclusive(state bool) {
+
+	a.exclusive = state
+	if !state {
+		return
+	}
+	first := true
+	for idx, s := range a.sections {
+		if !s.expanded {
+			continue
+		}
+		if first {
+			first = false
+			continue
+		}
+		a.setExpanded(idx, false)
+	}
+}
+
+// Exclusive returns if the accordion is in the exclusive mode
+func (a *Accordion) Exclusive() bool {
+
+	return a.exclusive
+}
+
+// SetAnimated sets if the expansion and collapse of the sections are
+// animated. The default is true.
+func (a *Accordion) SetAnimated(state bool) {
+
+	a.animated = state
+}
+
+// Animated returns if the expansion and collapse of the sections are animated
+func (a *Accordion) Animated() bool {
+
+	return a.animated
+}
+
+// SetStyles sets the accordion styles overriding the default style
+func (a *Accordion) SetStyles(as *AccordionStyles) {
+
+	a.styles = as
+	a.update()
+	a.recalc()
+}
+
+// Dispose overrides the embedded panel Dispose method
+// and stops the animation if necessary
+func (a *Accordion) Dispose() {
+
+	a.stopAnim()
+	a.Panel.Dispose()
+}
+
+// setExpanded expands or collapses the section with the specified index
+// and dispatches OnExpand or OnCollapse with the index if its state changed
+func (a *Accordion) setExpanded(idx int, state bool) {
+
+	s := a.sections[idx]
+	if s.expanded == state {
+		return
+	}
+	if state && a.exclusive {
+		for i, other := range a.sections {
+			if i != idx && other.expanded {
+				a.setExpanded(i, false)
+			}
+		}
+	}
+	s.expanded = state
+	a.update()
+	a.startAnim()
+	if state {
+		a.Dispatch(OnExpand, idx)
+	} else {
+		a.Dispatch(OnCollapse, idx)
+	}
+}
+
+// onMouse process subscribed mouse events over a section header
+func (a *Accordion) onMouse(s *accordionSection, ev interface{}) {
+
+	mev := ev.(*window.MouseEvent)
+	if mev.Button != window.MouseButtonLeft || !a.Enabled() {
+		return
+	}
+	for idx, item := range a.sections {
+		if item == s {
+			a.setExpanded(idx, !s.expanded)
+			break
+		}
+	}
+	a.root.StopPropagation(Stop3D)
+}
+
+// onCursor process subscribed cursor events over a section header
+func (a *Accordion) onCursor(s *accordionSection, evname string) {
+
+	s.over = evname == OnCursorEnter
+	a.update()
+}
+
+// startAnim starts the animation timer if the accordion is animated
+// and was already added to a gui root. Otherwise the sections are
+// expanded or collapsed immediately.
+func (a *Accordion) startAnim() {
+
+	if !a.animated || a.root == nil {
+		for _, s := range a.sections {
+			s.frac = 0
+			if s.expanded {
+				s.frac = 1
+			}
+		}
+		a.recalc()
+		return
+	}
+	if a.animID != 0 {
+		return
+	}
+	a.animTime = time.Now()
+	a.animID = a.root.SetInterval(time.Millisecond, nil, a.onAnim)
+}
+
+// stopAnim stops the animation timer
+func (a *Accordion) stopAnim() {
+
+	if a.animID != 0 {
+		a.root.ClearTimeout(a.animID)
+		a.animID = 0
+	}
+}
+
+// onAnim is called at each frame during the animation and changes the
+// visible fraction of the bodies of the sections being expanded or collapsed
+func (a *Accordion) onAnim(arg interface{}) {
+
+	now := time.Now()
+	delta := float32(now.Sub(a.animTime).Seconds() / accordionAnimTime.Seconds())
+	a.animTime = now
+	done := true
+	for _, s := range a.sections {
+		if s.expanded {
+			s.frac = math32.Min(s.frac+delta, 1)
+			done = done && s.frac == 1
+		} else {
+			s.frac = math32.Max(s.frac-delta, 0)
+			done = done && s.frac == 0
+		}
+	}
+	if done {
+		a.stopAnim()
+	}
+	a.recalc()
+}
+
+// update updates the visual state of the section headers
+func (a *Accordion) update() {
+
+	for _, s := range a.sections {
+		st := &a.styles.Normal
+		if !a.Enabled() {
+			st = &a.styles.Disabled
+		} else if s.over {
+			st = &a.styles.Over
+		}
+		s.header.SetBordersFrom(&st.Border)
+		s.header.SetBordersColor4(&st.BorderColor)
+		s.header.SetPaddingsFrom(&st.Paddings)
+		s.header.SetColor4(&st.BgColor)
+		icode := st.Icons[0]
+		if s.expanded {
+			icode = st.Icons[1]
+		}
+		s.icon.SetText(string(rune(icode)))
+		s.icon.SetColor(&st.FgColor)
+		s.label.SetColor(&st.FgColor)
+	}
+}
+
+// recalc recalculates the positions and sizes of the sections and
+// the height of the accordion
+func (a *Accordion) recalc() {
+
+	width := a.ContentWidth()
+	var py float32
+	for _, s := range a.sections {
+		// Header
+		s.header.SetPosition(0, py)
+		s.header.SetWidth(width)
+		s.header.SetContentHeight(math32.Max(s.label.Height(), s.icon.Height()))
+		s.icon.SetPosition(0, (s.header.ContentHeight()-s.icon.Height())/2)
+		s.label.SetPosition(s.icon.Width()+4, (s.header.ContentHeight()-s.label.Height())/2)
+		py += s.header.Height()
+		// Body clipped by its container
+		body := s.body.GetPanel()
+		if body.Width() != width {
+			body.SetWidth(width)
+		}
+		body.SetPosition(0, 0)
+		height := math32.Floor(body.Height() * s.frac)
+		s.clip.SetVisible(height > 0)
+		s.clip.SetPosition(0, py)
+		s.clip.SetSize(width, height)
+		py += height
+	}
+
+	// Sets the accordion height and recalculates the layout of the parent if changed
+	if a.ContentHeight() == py {
+		return
+	}
+	a.SetContentHeight(py)
+	par, ok := a.Parent().(*Panel)
+	if ok && par.layout != nil {
+		par.layout.Recalc(par)
+	}
+}
@@ -5,6 +5,8 @@
 package gui
 
 import (
+	"time"
+
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/window"
 )
@@ -30,6 +32,20 @@ type Button struct {
 	styles    *ButtonStyles // pointer to current button styles
 	mouseOver bool          // true if mouse is over button
 	pressed   bool          // true if button is pressed
+	repDelay  time.Duration // delay before long press and auto repeat (0 = disabled)
+	repPeriod time.Duration // auto repeat interval (0 = no repeat)
+	repID     int           // id of current long press/repeat timer (0 = none)
+	align     Align         // horizontal alignment of image/icon and label
+	lcolor    math32.Color4 // label own color used when style has no foreground color
+	toggle    bool          // true if button is in toggle mode
+	toggled   bool          // current latched state in toggle mode
+	imgFit    ImageFit      // image fit mode
+	imgWidth  float32       // width of the box the image is fitted in
+	imgHeight float32       // height of the box the image is fitted in
+	spacing   float32       // spacing between image/icon and label
+	onPress   bool          // true if OnClick is dispatched on mouse down (legacy)
+	repeated  bool          // true if the current press already dispatched a long press
+	focus     bool          // true if button has the key focus
 }
 
 // Button style
@@ -52,10 +68,13 @@ type ButtonStyles struct {
 
 // NewButton creates and returns a pointer to a new button widget
 // with the specified text for the button label.
+// The text may contain line breaks (\n) for multi-line labels.
 func NewButton(text string) *Button {
 
 	b := new(Button)
 	b.styles = &StyleDefault.Button
+	b.align = AlignCenter
+	b.spacing = 4
 
 	// Initializes the button panel
 	b.Panel = NewPanel(0, 0)
@@ -68,11 +87,17 @@ func NewButton(text string) *Button {
 	b.Panel.Subscribe(OnCursor, b.onCursor)
 	b.Panel.Subscribe(OnCursorEnter, b.onCursor)
 	b.Panel.Subscribe(OnCursorLeave, b.onCursor)
-	b.Panel.Subscribe(OnEnable, func(name string, ev interface{}) { b.update() })
+	b.Panel.Subscribe(OnEnable, b.onEnable)
+	b.Panel.Subscribe(OnFocus, func(name string, ev interface{}) {
+		b.focus = true
+		b.update()
+	})
+	b.Panel.SetFocusable(true)
 	b.Panel.Subscribe(OnResize, func(name string, ev interface{}) { b.recalc() })
 
 	// Creates label
 	b.Label = NewLabel(text)
+	b.lcolor = b.Label.Color()
 	b.Label.Subscribe(OnResize, func(name string, ev interface{}) { b.recalc() })
 	b.Panel.Add(b.Label)
 
@@ -85,7 +110,7 @@ func NewButton(text string) *Button {
 // If there is currently a selected image, it is removed
 func (b *Button) SetIcon(icode int) {
 
-	ico := NewIconLabel(string(icode))
+	ico := NewIconLabel(string(rune(icode)))
 	if b.image != nil {
 		b.Panel.Remove(b.image)
 		b.image = nil
@@ -113,11 +138,28 @@ func (b *Button) SetImage(imgfile string) error {
 		b.Panel.Remove(b.image)
 	}
 	b.image = img
+	b.image.Fit(b.imgWidth, b.imgHeight, b.imgFit)
 	b.Panel.Add(b.image)
 	b.recalc()
 	return nil
 }
 
+// SetImageFit sets the size of the box inside the button content area
+// where the button image is fitted and the fit mode to use.
+// With ImageFitContain the image aspect ratio is preserved and the image
+// is centered inside the box. With ImageFitNone (the default)
+// the image is shown with its native size.
+func (b *Button) SetImageFit(width, height float32, fit ImageFit) {
+
+	b.imgWidth = width
+	b.imgHeight = height
+	b.imgFit = fit
+	if b.image != nil {
+		b.image.Fit(width, height, fit)
+		b.recalc()
+	}
+}
+
 // SetStyles set the button styles overriding the default style
 func (b *Button) SetStyles(bs *ButtonStyles) {
 
@@ -125,6 +167,117 @@ func (b *Button) SetStyles(bs *ButtonStyles) {
 	b.update()
 }
 
+// SetLabelAlignment sets the horizontal alignment of the button image/icon
+// and label inside the button content area.
+// Valid values are AlignLeft, AlignCenter (the default) and AlignRight.
+func (b *Button) SetLabelAlignment(align Align) {
+
+	switch align {
+	case AlignLeft, AlignCenter, AlignRight:
+		b.align = align
+	default:
+		log.Warn("Invalid button label alignment:%v", align)
+		return
+	}
+	b.recalc()
+}
+
+// LabelAlignment returns the current horizontal alignment of the button label
+func (b *Button) LabelAlignment() Align {
+
+	return b.align
+}
+
+// SetToggle sets the toggle mode of this button.
+// In toggle mode each click flips the button latched state and
+// the button keeps the pressed style while latched.
+// An OnChange event is dispatched with the new state.
+func (b *Button) SetToggle(toggle bool) {
+
+	b.toggle = toggle
+	if !toggle {
+		b.toggled = false
+	}
+	b.update()
+}
+
+// Toggle returns the current toggle mode of this button
+func (b *Button) Toggle() bool {
+
+	return b.toggle
+}
+
+// SetToggled sets the latched state of this button in toggle mode
+// and dispatches OnChange if the state changed.
+func (b *Button) SetToggled(state bool) {
+
+	if !b.toggle || state == b.toggled {
+		return
+	}
+	b.toggled = state
+	b.update()
+	b.Dispatch(OnChange, b.toggled)
+}
+
+// Toggled returns the current latched state of this button in toggle mode
+func (b *Button) Toggled() bool {
+
+	return b.toggled
+}
+
+// SetIconSpacing sets the spacing in pixels between
+// the button image/icon and the label (default 4)
+func (b *Button) SetIconSpacing(spacing float32) {
+
+	b.spacing = spacing
+	b.recalc()
+}
+
+// IconSpacing returns the spacing in pixels between the button image/icon and the label
+func (b *Button) IconSpacing() float32 {
+
+	return b.spacing
+}
+
+// SetClickOnPress sets if OnClick is dispatched when the mouse button is pressed
+// (legacy behavior) instead of when it is released over the button (the default).
+func (b *Button) SetClickOnPress(state bool) {
+
+	b.onPress = state
+}
+
+// SetRepeat sets the long press delay and the auto repeat interval of this button.
+// After the button is kept pressed for the specified delay, an OnLongPress event
+// is dispatched and, if the interval is not zero, OnClick events are dispatched
+// periodically at the specified interval while the button remains pressed.
+// Passing a zero delay disables the long press and auto repeat (the default).
+func (b *Button) SetRepeat(delay, interval time.Duration) {
+
+	b.repDelay = delay
+	b.repPeriod = interval
+	if delay == 0 {
+		b.stopRepeat()
+	}
+}
+
+// LostKeyFocus satisfies the IPanel interface and is called by gui root
+// container when the panel loses the key focus
+func (b *Button) LostKeyFocus() {
+
+	b.focus = false
+	b.update()
+}
+
+// onEnable process subscribed enable events
+func (b *Button) onEnable(evname string, ev interface{}) {
+
+	if !b.Enabled() {
+		b.pressed = false
+		b.stopRepeat()
+	}
+	b.update()
+}
+
 // onCursor process subscribed cursor events
 func (b *Button) onCursor(evname string, ev interface{}) {
 
@@ -135,6 +288,7 @@ func (b *Button) onCursor(evname string, ev interface{}) {
 	case OnCursorLeave:
 		b.pressed = false
 		b.mouseOver = false
+		b.stopRepeat()
 		b.update()
 	}
 	b.root.StopPropagation(StopAll)
@@ -147,11 +301,22 @@ func (b *Button) onMouse(evname string, ev interface{}) {
 	case OnMouseDown:
 		b.root.SetKeyFocus(b)
 		b.pressed = true
+		b.mouseOver = true
 		b.update()
-		b.Dispatch(OnClick, nil)
+		if b.onPress {
+			b.click()
+		}
+		b.startRepeat()
 	case OnMouseUp:
+		// The click is cancelled if the cursor left the button
+		// while pressed or if a long press was already dispatched.
+		click := !b.onPress && b.pressed && b.mouseOver && !b.repeated
 		b.pressed = false
+		b.stopRepeat()
 		b.update()
+		if click {
+			b.click()
+		}
 	default:
 		return
 	}
@@ -165,12 +330,14 @@ func (b *Button) onKey(evname string, ev interface{}) {
 	if evname == OnKeyDown && kev.Keycode == window.KeyEnter {
 		b.pressed = true
 		b.update()
-		b.Dispatch(OnClick, nil)
+		b.click()
+		b.startRepeat()
 		b.root.StopPropagation(Stop3D)
 		return
 	}
 	if evname == OnKeyUp && kev.Keycode == window.KeyEnter {
 		b.pressed = false
+		b.stopRepeat()
 		b.update()
 		b.root.StopPropagation(Stop3D)
 		return
@@ -178,6 +345,60 @@ func (b *Button) onKey(evname string, ev interface{}) {
 	return
 }
 
+// click flips the latched state if in toggle mode and dispatches OnClick
+func (b *Button) click() {
+
+	if b.toggle {
+		b.SetToggled(!b.toggled)
+	}
+	b.Dispatch(OnClick, nil)
+}
+
+// startRepeat starts the long press timer if enabled
+// Auto repeat is not used in toggle mode.
+func (b *Button) startRepeat() {
+
+	b.stopRepeat()
+	b.repeated = false
+	if b.repDelay == 0 || b.toggle || b.root == nil {
+		return
+	}
+	b.repID = b.root.SetTimeout(b.repDelay, nil, func(arg interface{}) {
+		b.repID = 0
+		if !b.pressed {
+			return
+		}
+		b.repeated = true
+		b.Dispatch(OnLongPress, nil)
+		if b.repPeriod == 0 {
+			return
+		}
+		b.Dispatch(OnClick, nil)
+		b.repID = b.root.SetInterval(b.repPeriod, nil, func(arg interface{}) {
+			b.Dispatch(OnClick, nil)
+		})
+	})
+}
+
+// stopRepeat cancels the current long press or auto repeat timer if any
+func (b *Button) stopRepeat() {
+
+	if b.repID == 0 {
+		return
+	}
+	b.root.ClearTimeout(b.repID)
+	b.repID = 0
+}
+
+// imageBox returns the width and height of the box occupied by the button image
+func (b *Button) imageBox() (float32, float32) {
+
+	if b.imgFit == ImageFitNone || b.imgWidth <= 0 || b.imgHeight <= 0 {
+		return b.image.Width(), b.image.Height()
+	}
+	return b.imgWidth, b.imgHeight
+}
+
 // update updates the button visual state
 func (b *Button) update() {
 
@@ -185,7 +406,7 @@ func (b *Button) update() {
 		b.applyStyle(&b.styles.Disabled)
 		return
 	}
-	if b.pressed {
+	if b.pressed || b.toggled {
 		b.applyStyle(&b.styles.Pressed)
 		return
 	}
@@ -193,6 +414,10 @@ func (b *Button) update() {
 		b.applyStyle(&b.styles.Over)
 		return
 	}
+	if b.focus {
+		b.applyStyle(&b.styles.Focus)
+		return
+	}
 	b.applyStyle(&b.styles.Normal)
 }
 
@@ -206,7 +431,17 @@ func (b *Button) applyStyle(bs *ButtonStyle) {
 	if b.icon != nil {
 		b.icon.SetColor(&bs.FgColor)
 	}
-	//b.Label.SetColor(&bs.FgColor)
+	// If the style has no foreground color uses the label own color
+	var color math32.Color4
+	if bs.FgColor == (math32.Color{}) {
+		color = b.lcolor
+	} else {
+		color.FromColor(&bs.FgColor, 1.0)
+	}
+	// Only redraws the label text if the color changed
+	if b.Label.Color() != color {
+		b.Label.SetColor4(&color)
+	}
 }
 
 // recalc recalculates all dimensions and position from inside out
@@ -216,18 +451,21 @@ func (b *Button) recalc() {
 	width := b.Panel.ContentWidth()
 	height := b.Panel.ContentHeight()
 
-	// Image or icon width
+	// Image or icon width and height
 	imgWidth := float32(0)
+	imgHeight := float32(0)
 	if b.image != nil {
-		imgWidth = b.image.Width()
+		imgWidth, imgHeight = b.imageBox()
 	} else if b.icon != nil {
 		imgWidth = b.icon.Width()
+		imgHeight = b.icon.Height()
 	}
 
-	// Sets new content width and height if necessary
-	spacing := float32(4)
+	// Sets new content width and height if necessary.
+	// The label height considers all its text lines.
+	spacing := b.spacing
 	minWidth := imgWidth + spacing + b.Label.Width()
-	minHeight := b.Label.Height()
+	minHeight := math32.Max(b.Label.Height(), imgHeight)
 	resize := false
 	if width < minWidth {
 		width = minWidth
@@ -241,18 +479,29 @@ func (b *Button) recalc() {
 		b.SetContentSize(width, height)
 	}
 
-	// Centralize horizontally
-	px := (width - minWidth) / 2
+	// Horizontal position of the image/icon and label set
+	var px float32
+	switch b.align {
+	case AlignLeft:
+		px = 0
+	case AlignRight:
+		px = width - minWidth
+	default:
+		px = (width - minWidth) / 2
+	}
 
 	// Set label position
 	ly := (height - b.Label.Height()) / 2
 	b.Label.SetPosition(px+imgWidth+spacing, ly)
 
-	// Image/icon position
+	// Image/icon position centered vertically against the label box
+	iy := (height - imgHeight) / 2
 	if b.image != nil {
-		iy := (height - b.image.height) / 2
-		b.image.SetPosition(px, iy)
+		// Letterbox the image inside its box
+		bx := (imgWidth - b.image.Width()) / 2
+		by := (imgHeight - b.image.Height()) / 2
+		b.image.SetPosition(px+bx, iy+by)
 	} else if b.icon != nil {
-		b.icon.SetPosition(px, ly)
+		b.icon.SetPosition(px, iy)
 	}
 }
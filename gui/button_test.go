@@ -0,0 +1,58 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/gui/assets"
+	"github.com/g3n/engine/math32"
+)
+
+func TestButtonLabelColorFollowsStyle(t *testing.T) {
+
+	styles := StyleDefault.Button
+	styles.Normal.FgColor = math32.Color{}
+	styles.Disabled.FgColor = math32.Color{0.5, 0.5, 0.5}
+	b := NewButton("OK")
+	b.SetStyles(&styles)
+	own := b.Label.Color()
+
+	b.SetEnabled(false)
+	if got := b.Label.Color(); got != (math32.Color4{0.5, 0.5, 0.5, 1}) {
+		t.Errorf("disabled label color = %v, want the disabled style color", got)
+	}
+	b.SetEnabled(true)
+	if got := b.Label.Color(); got != own {
+		t.Errorf("enabled label color = %v, want the label own color %v", got, own)
+	}
+}
+
+func TestButtonIconSpacing(t *testing.T) {
+
+	cases := []struct {
+		align   Align
+		spacing float32
+		delta   float32
+	}{
+		{AlignLeft, 10, 6},
+		{AlignLeft, 4, 0},
+		{AlignCenter, 12, 8},
+		{AlignRight, 20, 16},
+	}
+	for _, c := range cases {
+		b := NewButton("OK")
+		b.SetIcon(assets.Check)
+		b.SetLabelAlignment(c.align)
+		x := b.Label.Position().X
+		b.SetIconSpacing(c.spacing)
+		if b.IconSpacing() != c.spacing {
+			t.Errorf("align %v: spacing = %v, want %v", c.align, b.IconSpacing(), c.spacing)
+		}
+		if got := b.Label.Position().X - x; got != c.delta {
+			t.Errorf("align %v spacing %v: label moved %v, want %v", c.align, c.spacing, got, c.delta)
+		}
+	}
+}
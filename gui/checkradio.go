@@ -11,10 +11,20 @@ import (
 )
 
 const (
-	checkON  = string(assets.CheckBox)
-	checkOFF = string(assets.CheckBoxOutlineBlank)
-	radioON  = string(assets.RadioButtonChecked)
-	radioOFF = string(assets.RadioButtonUnchecked)
+	checkON    = string(rune(assets.CheckBox))
+	checkOFF   = string(rune(assets.CheckBoxOutlineBlank))
+	checkMIXED = string(rune(assets.IndeterminateCheckBox))
+	radioON    = string(rune(assets.RadioButtonChecked))
+	radioOFF   = string(rune(assets.RadioButtonUnchecked))
+)
+
+// CheckState is the state of a tri-state CheckBox
+type CheckState int
+
+const (
+	Unchecked = CheckState(iota) // CheckBox is not checked
+	Checked                      // CheckBox is checked
+	Mixed                        // CheckBox is in the mixed (indeterminate) state
 )
 
 type CheckRadio struct {
@@ -23,9 +33,12 @@ type CheckRadio struct {
 	icon       *Label
 	styles     *CheckRadioStyles
 	check      bool
-	group      string // current group name
+	group      string      // current group name
+	radioGroup *RadioGroup // radio group which contains this button (may be nil)
 	cursorOver bool
+	focus      bool // key focus flag
 	state      bool
+	mixed      bool // indicates mixed state for CheckBox
 	codeON     string
 	codeOFF    string
 	subroot    bool // indicates root subcription
@@ -87,6 +100,11 @@ func newCheckRadio(check bool, text string) *CheckRadio {
 	cb.Panel.Subscribe(OnCursorLeave, cb.onCursor)
 	cb.Panel.Subscribe(OnMouseDown, cb.onMouse)
 	cb.Panel.Subscribe(OnEnable, func(evname string, ev interface{}) { cb.update() })
+	cb.Panel.Subscribe(OnFocus, func(evname string, ev interface{}) {
+		cb.focus = true
+		cb.update()
+	})
+	cb.Panel.SetFocusable(true)
 
 	// Creates label
 	cb.Label = NewLabel(text)
@@ -103,20 +121,66 @@ func newCheckRadio(check bool, text string) *CheckRadio {
 }
 
 // Value returns the current state of the checkbox
+// The mixed state of a CheckBox is reported as false.
 func (cb *CheckRadio) Value() bool {
 
-	return cb.state
+	return cb.state && !cb.mixed
 }
 
 // SetValue sets the current state of the checkbox
+// and clears the mixed state if set.
 func (cb *CheckRadio) SetValue(state bool) *CheckRadio {
 
-	if state == cb.state {
+	if state {
+		return cb.SetState(Checked)
+	}
+	return cb.SetState(Unchecked)
+}
+
+// State returns the current tri-state of the checkbox
+func (cb *CheckRadio) State() CheckState {
+
+	if cb.mixed {
+		return Mixed
+	}
+	if cb.state {
+		return Checked
+	}
+	return Unchecked
+}
+
+// SetState sets the current tri-state of the checkbox and dispatches OnChange
+// with the new state if it changed.
+// The Mixed state is only valid for CheckBox widgets and is normally used
+// to indicate that only some of a group of child items are checked.
+func (cb *CheckRadio) SetState(state CheckState) *CheckRadio {
+
+	if state == cb.State() {
+		return cb
+	}
+	switch state {
+	case Unchecked:
+		cb.state = false
+		cb.mixed = false
+	case Checked:
+		cb.state = true
+		cb.mixed = false
+	case Mixed:
+		if !cb.check {
+			log.Warn("Mixed state is only valid for CheckBox")
+			return cb
+		}
+		cb.state = false
+		cb.mixed = true
+	default:
+		log.Warn("Invalid CheckBox state:%v", state)
 		return cb
 	}
-	cb.state = state
 	cb.update()
-	cb.Dispatch(OnChange, nil)
+	cb.Dispatch(OnChange, state)
+	if cb.radioGroup != nil {
+		cb.radioGroup.onState(cb)
+	}
 	return cb
 }
 
@@ -132,6 +196,12 @@ func (cb *CheckRadio) SetGroup(group string) {
 	cb.group = group
 }
 
+// RadioGroup returns the radio group which contains this button or nil
+func (cb *CheckRadio) RadioGroup() *RadioGroup {
+
+	return cb.radioGroup
+}
+
 // SetStyles set the button styles overriding the default style
 func (cb *CheckRadio) SetStyles(bs *CheckRadioStyles) {
 
@@ -139,6 +209,14 @@ func (cb *CheckRadio) SetStyles(bs *CheckRadioStyles) {
 	cb.update()
 }
 
+// LostKeyFocus satisfies the IPanel interface and is called by gui root
+// container when the panel loses the key focus
+func (cb *CheckRadio) LostKeyFocus() {
+
+	cb.focus = false
+	cb.update()
+}
+
 // toggleState toggles the current state of the checkbox/radiobutton
 func (cb *CheckRadio) toggleState() {
 
@@ -151,8 +229,16 @@ func (cb *CheckRadio) toggleState() {
 		cb.subroot = true
 	}
 
+	// Clicking a radio button of a RadioGroup always checks it
+	if cb.radioGroup != nil {
+		cb.SetValue(true)
+		return
+	}
+
 	if cb.check {
-		cb.state = !cb.state
+		// From the mixed state always goes to checked
+		cb.state = !cb.state || cb.mixed
+		cb.mixed = false
 	} else {
 		if len(cb.group) == 0 {
 			cb.state = !cb.state
@@ -164,7 +250,7 @@ func (cb *CheckRadio) toggleState() {
 		}
 	}
 	cb.update()
-	cb.Dispatch(OnChange, nil)
+	cb.Dispatch(OnChange, cb.State())
 	if !cb.check && len(cb.group) > 0 {
 		cb.root.Dispatch(OnRadioGroup, cb)
 	}
@@ -174,6 +260,7 @@ func (cb *CheckRadio) toggleState() {
 func (cb *CheckRadio) onMouse(evname string, ev interface{}) {
 
 	cb.root.SetKeyFocus(cb)
+	cb.focus = true
 	cb.root.StopPropagation(Stop3D)
 	cb.toggleState()
 	// Dispatch OnClick for left mouse button down
@@ -202,13 +289,34 @@ func (cb *CheckRadio) onCursor(evname string, ev interface{}) {
 func (cb *CheckRadio) onKey(evname string, ev interface{}) {
 
 	kev := ev.(*window.KeyEvent)
-	if evname == OnKeyDown && kev.Keycode == window.KeyEnter {
+	if evname == OnKeyDown && (kev.Keycode == window.KeyEnter || kev.Keycode == window.KeySpace) {
 		cb.toggleState()
 		cb.update()
 		cb.Dispatch(OnClick, nil)
 		cb.root.StopPropagation(Stop3D)
 		return
 	}
+	// Arrow keys move the selection among the members of the radio group
+	if evname == OnKeyDown && cb.radioGroup != nil {
+		delta := 0
+		switch kev.Keycode {
+		case window.KeyUp, window.KeyLeft:
+			delta = -1
+		case window.KeyDown, window.KeyRight:
+			delta = 1
+		default:
+			return
+		}
+		next := cb.radioGroup.next(cb, delta)
+		if next == nil {
+			return
+		}
+		cb.root.SetKeyFocus(next)
+		next.focus = true
+		next.SetValue(true)
+		next.update()
+		cb.root.StopPropagation(Stop3D)
+	}
 	return
 }
 
@@ -230,7 +338,9 @@ func (cb *CheckRadio) onRadioGroup(other *CheckRadio) {
 // update updates the visual appearance of the checkbox
 func (cb *CheckRadio) update() {
 
-	if cb.state {
+	if cb.mixed {
+		cb.icon.SetText(checkMIXED)
+	} else if cb.state {
 		cb.icon.SetText(cb.codeON)
 	} else {
 		cb.icon.SetText(cb.codeOFF)
@@ -244,6 +354,10 @@ func (cb *CheckRadio) update() {
 		cb.applyStyle(&cb.styles.Over)
 		return
 	}
+	if cb.focus {
+		cb.applyStyle(&cb.styles.Focus)
+		return
+	}
 	cb.applyStyle(&cb.styles.Normal)
 }
 
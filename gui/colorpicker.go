@@ -0,0 +1,530 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+/***************************************
+
+ ColorPicker
+ +-------------------------------------+
+ | +---------------+ +--+ +--+         |
+ | |               | |  | |  |         |
+ | |  saturation   | |h | |a |         |
+ | |  and value    | |u | |l |         |
+ | |               | |e | |p |         |
+ | |               | |  | |h |         |
+ | +---------------+ +--+ +--+         |
+ | # [hex     ]                        |
+ | R [   ] G [   ] B [   ] A [   ]     |
+ +-------------------------------------+
+
+**/
+
+// ColorPicker is a widget to choose a color using a saturation/value
+// square, a hue strip, an optional alpha strip and numeric entry fields.
+// OnChange is dispatched with a *ColorPickerEvent continuously while
+// the mouse is dragged and with Final set when the mouse is released
+// or the color is changed by the entry fields.
+type ColorPicker struct {
+	Panel                        // Embedded panel
+	styles     *ColorPickerStyle // pointer to current style
+	hue        float32           // current hue from 0 to 1
+	sat        float32           // current saturation from 0 to 1
+	val        float32           // current value from 0 to 1
+	alpha      float32           // current alpha from 0 to 1
+	showAlpha  bool              // alpha strip and field are visible
+	square     *Image            // saturation/value square
+	hueStrip   *Image            // hue strip
+	alphaStrip *Image            // alpha strip
+	sqMarker   Panel             // marker of the saturation/value square
+	hueMarker  Panel             // marker of the hue strip
+	alphMarker Panel             // marker of the alpha strip
+	hexLabel   *Label            // label of the hex field
+	hexEdit    *Edit             // hex field
+	rgbaLabels [4]*Label         // labels of the R, G, B and A fields
+	rgbaEdits  [4]*Edit          // R, G, B and A fields
+	sqImage    *image.RGBA       // image of the saturation/value square
+	alphaImage *image.RGBA       // image of the alpha strip
+	dragging   *Image            // picker area being dragged (nil = none)
+	updating   bool              // entry fields being updated by the picker
+}
+
+// ColorPickerStyle contains the styling of a ColorPicker
+type ColorPickerStyle struct {
+	Border      BorderSizes
+	Paddings    BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color4
+	FgColor     math32.Color  // color of the field labels
+	MarkerColor math32.Color4 // color of the markers borders
+}
+
+// ColorPickerEvent is the event dispatched with OnChange by the ColorPicker
+type ColorPickerEvent struct {
+	Color math32.Color4 // current color
+	Final bool          // false while the mouse is being dragged
+}
+
+const (
+	colorPickerSize    = 128 // size in pixels of the saturation/value square
+	colorPickerStrip   = 16  // width in pixels of the hue and alpha strips
+	colorPickerSpacing = 6   // spacing in pixels between the internal panels
+	colorPickerMarker  = 7   // size in pixels of the square marker
+	colorPickerChecker = 4   // size in pixels of the alpha strip checkerboard cells
+)
+
+// NewColorPicker creates and returns a pointer to a new color picker
+// with the alpha strip hidden and the color opaque white.
+func NewColorPicker() *ColorPicker {
+
+	cp := new(ColorPicker)
+	cp.styles = &StyleDefault.ColorPicker
+	cp.alpha = 1
+	cp.Panel.Initialize(0, 0)
+
+	// Creates the saturation/value square
+	cp.sqImage = image.NewRGBA(image.Rect(0, 0, colorPickerSize, colorPickerSize))
+	cp.square = NewImageFromRGBA(cp.sqImage)
+	cp.square.Subscribe(OnMouseDown, cp.onMouse)
+	cp.square.Subscribe(OnMouseUp, cp.onMouse)
+	cp.square.Subscribe(OnCursor, cp.onCursor)
+	cp.Panel.Add(cp.square)
+
+	// Creates the hue strip
+	hueImage := image.NewRGBA(image.Rect(0, 0, colorPickerStrip, colorPickerSize))
+	for y := 0; y < colorPickerSize; y++ {
+		r, g, b := hsvToRGB(float32(y)/float32(colorPickerSize-1), 1, 1)
+		c := color.RGBA{uint8(r * 255), uint8(g * 255), uint8(b * 255), 255}
+		for x := 0; x < colorPickerStrip; x++ {
+			hueImage.SetRGBA(x, y, c)
+		}
+	}
+	cp.hueStrip = NewImageFromRGBA(hueImage)
+	cp.hueStrip.Subscribe(OnMouseDown, cp.onMouse)
+	cp.hueStrip.Subscribe(OnMouseUp, cp.onMouse)
+	cp.hueStrip.Subscribe(OnCursor, cp.onCursor)
+	cp.Panel.Add(cp.hueStrip)
+
+	// Creates the alpha strip
+	cp.alphaImage = image.NewRGBA(image.Rect(0, 0, colorPickerStrip, colorPickerSize))
+	cp.alphaStrip = NewImageFromRGBA(cp.alphaImage)
+	cp.alphaStrip.Subscribe(OnMouseDown, cp.onMouse)
+	cp.alphaStrip.Subscribe(OnMouseUp, cp.onMouse)
+	cp.alphaStrip.Subscribe(OnCursor, cp.onCursor)
+	cp.alphaStrip.SetVisible(false)
+	cp.Panel.Add(cp.alphaStrip)
+
+	// Creates the markers which do not receive mouse events
+	cp.sqMarker.Initialize(colorPickerMarker, colorPickerMarker)
+	cp.square.Add(&cp.sqMarker)
+	cp.hueMarker.Initialize(colorPickerStrip, 3)
+	cp.hueStrip.Add(&cp.hueMarker)
+	cp.alphMarker.Initialize(colorPickerStrip, 3)
+	cp.alphaStrip.Add(&cp.alphMarker)
+	for _, m := range []*Panel{&cp.sqMarker, &cp.hueMarker, &cp.alphMarker} {
+		m.SetBorders(1, 1, 1, 1)
+		m.SetColor4(&math32.Color4{0, 0, 0, 0})
+		m.SetEnabled(false)
+	}
+
+	// Creates the entry fields
+	cp.hexLabel = NewLabel("#")
+	cp.Panel.Add(cp.hexLabel)
+	cp.hexEdit = NewEdit(80, "rrggbb")
+	cp.hexEdit.MaxLength = 8
+	cp.hexEdit.Subscribe(OnChange, cp.onHexEdit)
+	cp.Panel.Add(cp.hexEdit)
+	for i, name := range []string{"R", "G", "B", "A"} {
+		cp.rgbaLabels[i] = NewLabel(name)
+		cp.Panel.Add(cp.rgbaLabels[i])
+		edit := NewEdit(36, "0")
+		edit.MaxLength = 3
+		edit.SetValidator(func(r rune, current string) bool { return r >= '0' && r <= '9' })
+		edit.Subscribe(OnChange, cp.onRGBAEdit)
+		cp.rgbaEdits[i] = edit
+		cp.Panel.Add(edit)
+	}
+	cp.rgbaLabels[3].SetVisible(false)
+	cp.rgbaEdits[3].SetVisible(false)
+
+	cp.sat = 0
+	cp.val = 1
+	cp.update()
+	cp.recalc()
+	cp.updatePicker()
+	cp.updateFields()
+	return cp
+}
+
+// SetColor sets the current color of the color picker without dispatching events
+func (cp *ColorPicker) SetColor(c math32.Color4) {
+
+	cp.setRGB(c.R, c.G, c.B)
+	cp.alpha = math32.Clamp(c.A, 0, 1)
+	cp.updatePicker()
+	cp.updateFields()
+}
+
+// Color returns the current color of the color picker
+func (cp *ColorPicker) Color() math32.Color4 {
+
+	r, g, b := hsvToRGB(cp.hue, cp.sat, cp.val)
+	return math32.Color4{r, g, b, cp.alpha}
+}
+
+// SetAlphaVisible sets the visibility of the alpha strip and field.
+// When the alpha strip is hidden the alpha of the color is not changed
+// by the picker.
+func (cp *ColorPicker) SetAlphaVisible(visible bool) {
+
+	cp.showAlpha = visible
+	cp.alphaStrip.SetVisible(visible)
+	cp.rgbaLabels[3].SetVisible(visible)
+	cp.rgbaEdits[3].SetVisible(visible)
+	cp.hexEdit.SetPlaceholder("rrggbb")
+	if visible {
+		cp.hexEdit.SetPlaceholder("rrggbbaa")
+	}
+	cp.recalc()
+	cp.updatePicker()
+	cp.updateFields()
+}
+
+// AlphaVisible returns the visibility of the alpha strip
+func (cp *ColorPicker) AlphaVisible() bool {
+
+	return cp.showAlpha
+}
+
+// SetStyles sets the color picker style overriding the default style
+func (cp *ColorPicker) SetStyles(cs *ColorPickerStyle) {
+
+	cp.styles = cs
+	cp.update()
+	cp.recalc()
+}
+
+// setRGB sets the current hue, saturation and value from the specified
+// color components keeping the current hue for colors without saturation
+func (cp *ColorPicker) setRGB(r, g, b float32) {
+
+	h, s, v := rgbToHSV(math32.Clamp(r, 0, 1), math32.Clamp(g, 0, 1), math32.Clamp(b, 0, 1))
+	if s > 0 {
+		cp.hue = h
+	}
+	cp.sat = s
+	cp.val = v
+}
+
+// dispatch dispatches OnChange with the current color
+func (cp *ColorPicker) dispatch(final bool) {
+
+	cp.Dispatch(OnChange, &ColorPickerEvent{Color: cp.Color(), Final: final})
+}
+
+// onMouse process subscribed mouse events over the picker areas
+func (cp *ColorPicker) onMouse(evname string, ev interface{}) {
+
+	mev := ev.(*window.MouseEvent)
+	switch evname {
+	case OnMouseDown:
+		if mev.Button != window.MouseButtonLeft {
+			return
+		}
+		switch {
+		case cp.square.InsideBorders(mev.Xpos, mev.Ypos):
+			cp.dragging = cp.square
+		case cp.hueStrip.InsideBorders(mev.Xpos, mev.Ypos):
+			cp.dragging = cp.hueStrip
+		case cp.alphaStrip.Visible() && cp.alphaStrip.InsideBorders(mev.Xpos, mev.Ypos):
+			cp.dragging = cp.alphaStrip
+		default:
+			return
+		}
+		cp.root.SetMouseFocus(cp.dragging)
+		cp.pick(mev.Xpos, mev.Ypos)
+	case OnMouseUp:
+		if cp.dragging == nil {
+			return
+		}
+		cp.dragging = nil
+		cp.root.SetMouseFocus(nil)
+		cp.dispatch(true)
+	default:
+		return
+	}
+	cp.root.StopPropagation(StopAll)
+}
+
+// onCursor process subscribed cursor events over the picker areas
+func (cp *ColorPicker) onCursor(evname string, ev interface{}) {
+
+	if cp.dragging == nil {
+		return
+	}
+	cev := ev.(*window.CursorEvent)
+	cp.pick(cev.Xpos, cev.Ypos)
+	cp.root.StopPropagation(StopAll)
+}
+
+// pick changes the color from the specified screen position
+// over the picker area being dragged
+func (cp *ColorPicker) pick(x, y float32) {
+
+	cx, cy := cp.dragging.ContentCoords(x, y)
+	fx := math32.Clamp(cx/float32(colorPickerSize-1), 0, 1)
+	fy := math32.Clamp(cy/float32(colorPickerSize-1), 0, 1)
+	switch cp.dragging {
+	case cp.square:
+		cp.sat = fx
+		cp.val = 1 - fy
+	case cp.hueStrip:
+		cp.hue = fy
+	case cp.alphaStrip:
+		cp.alpha = 1 - fy
+	}
+	cp.updatePicker()
+	cp.updateFields()
+	cp.dispatch(false)
+}
+
+// onHexEdit process OnChange events from the hex field
+func (cp *ColorPicker) onHexEdit(evname string, ev interface{}) {
+
+	if cp.updating {
+		return
+	}
+	hex := strings.TrimPrefix(strings.TrimSpace(cp.hexEdit.Text()), "#")
+	if len(hex) != 6 && !(len(hex) == 8 && cp.showAlpha) {
+		return
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return
+	}
+	if len(hex) == 8 {
+		cp.alpha = float32(v&255) / 255
+		v >>= 8
+	}
+	cp.setRGB(float32(v>>16&255)/255, float32(v>>8&255)/255, float32(v&255)/255)
+	cp.updatePicker()
+	cp.updateFields()
+	cp.dispatch(true)
+}
+
+// onRGBAEdit process OnChange events from the R, G, B and A fields
+func (cp *ColorPicker) onRGBAEdit(evname string, ev interface{}) {
+
+	if cp.updating {
+		return
+	}
+	var comps [4]float32
+	for i, edit := range cp.rgbaEdits {
+		v, err := strconv.Atoi(strings.TrimSpace(edit.Text()))
+		if err != nil || v < 0 || v > 255 {
+			return
+		}
+		comps[i] = float32(v) / 255
+	}
+	cp.setRGB(comps[0], comps[1], comps[2])
+	if cp.showAlpha {
+		cp.alpha = comps[3]
+	}
+	cp.updatePicker()
+	cp.updateFields()
+	cp.dispatch(true)
+}
+
+// updatePicker updates the images and markers of the picker areas
+func (cp *ColorPicker) updatePicker() {
+
+	// Saturation/value square for the current hue
+	last := float32(colorPickerSize - 1)
+	for y := 0; y < colorPickerSize; y++ {
+		for x := 0; x < colorPickerSize; x++ {
+			r, g, b := hsvToRGB(cp.hue, float32(x)/last, 1-float32(y)/last)
+			cp.sqImage.SetRGBA(x, y, color.RGBA{uint8(r * 255), uint8(g * 255), uint8(b * 255), 255})
+		}
+	}
+	cp.square.tex.SetFromRGBA(cp.sqImage)
+
+	// Alpha strip for the current color over a checkerboard
+	if cp.showAlpha {
+		r, g, b := hsvToRGB(cp.hue, cp.sat, cp.val)
+		for y := 0; y < colorPickerSize; y++ {
+			a := 1 - float32(y)/last
+			for x := 0; x < colorPickerStrip; x++ {
+				bg := float32(1)
+				if (x/colorPickerChecker+y/colorPickerChecker)%2 == 1 {
+					bg = 0.6
+				}
+				cp.alphaImage.SetRGBA(x, y, color.RGBA{
+					uint8((r*a + bg*(1-a)) * 255),
+					uint8((g*a + bg*(1-a)) * 255),
+					uint8((b*a + bg*(1-a)) * 255),
+					255,
+				})
+			}
+		}
+		cp.alphaStrip.tex.SetFromRGBA(cp.alphaImage)
+	}
+
+	// Markers
+	cp.sqMarker.SetPosition(cp.sat*last-colorPickerMarker/2, (1-cp.val)*last-colorPickerMarker/2)
+	cp.hueMarker.SetPosition(0, cp.hue*last-1)
+	cp.alphMarker.SetPosition(0, (1-cp.alpha)*last-1)
+}
+
+// updateFields updates the entry fields from the current color
+// without changing the field being edited
+func (cp *ColorPicker) updateFields() {
+
+	cp.updating = true
+	defer func() { cp.updating = false }()
+
+	c := cp.Color()
+	comps := [4]int{
+		int(c.R*255 + 0.5),
+		int(c.G*255 + 0.5),
+		int(c.B*255 + 0.5),
+		int(c.A*255 + 0.5),
+	}
+	hex := fmt.Sprintf("%02x%02x%02x", comps[0], comps[1], comps[2])
+	if cp.showAlpha {
+		hex += fmt.Sprintf("%02x", comps[3])
+	}
+	if cp.root == nil || !cp.root.HasKeyFocus(cp.hexEdit) {
+		cp.hexEdit.SetText(hex)
+	}
+	for i, edit := range cp.rgbaEdits {
+		if cp.root == nil || !cp.root.HasKeyFocus(edit) {
+			edit.SetText(strconv.Itoa(comps[i]))
+		}
+	}
+}
+
+// update updates the visual state of the color picker
+func (cp *ColorPicker) update() {
+
+	s := cp.styles
+	cp.SetBordersColor4(&s.BorderColor)
+	cp.SetBordersFrom(&s.Border)
+	cp.SetPaddingsFrom(&s.Paddings)
+	cp.Panel.SetColor4(&s.BgColor)
+	cp.hexLabel.SetColor(&s.FgColor)
+	for _, l := range cp.rgbaLabels {
+		l.SetColor(&s.FgColor)
+	}
+	for _, m := range []*Panel{&cp.sqMarker, &cp.hueMarker, &cp.alphMarker} {
+		m.SetBordersColor4(&s.MarkerColor)
+	}
+}
+
+// recalc recalculates the positions of the internal panels
+// and the size of the color picker content area
+func (cp *ColorPicker) recalc() {
+
+	// Picker areas
+	cp.square.SetPosition(0, 0)
+	px := float32(colorPickerSize + colorPickerSpacing)
+	cp.hueStrip.SetPosition(px, 0)
+	px += colorPickerStrip + colorPickerSpacing
+	if cp.showAlpha {
+		cp.alphaStrip.SetPosition(px, 0)
+		px += colorPickerStrip + colorPickerSpacing
+	}
+	width := px - colorPickerSpacing
+
+	// Hex field row
+	py := float32(colorPickerSize + colorPickerSpacing)
+	cp.hexLabel.SetPosition(0, py+(cp.hexEdit.Height()-cp.hexLabel.Height())/2)
+	cp.hexEdit.SetPosition(cp.hexLabel.Width()+colorPickerSpacing/2, py)
+	py += cp.hexEdit.Height() + colorPickerSpacing/2
+
+	// R, G, B and A fields row
+	px = 0
+	for i := range cp.rgbaEdits {
+		if !cp.rgbaEdits[i].Visible() {
+			continue
+		}
+		label := cp.rgbaLabels[i]
+		edit := cp.rgbaEdits[i]
+		label.SetPosition(px, py+(edit.Height()-label.Height())/2)
+		px += label.Width() + colorPickerSpacing/2
+		edit.SetPosition(px, py)
+		px += edit.Width() + colorPickerSpacing
+	}
+	if px-colorPickerSpacing > width {
+		width = px - colorPickerSpacing
+	}
+	py += cp.rgbaEdits[0].Height()
+	cp.SetContentSize(width, py)
+}
+
+// rgbToHSV converts the specified RGB color components
+// to hue, saturation and value from 0 to 1
+func rgbToHSV(r, g, b float32) (h, s, v float32) {
+
+	max := math32.Max(r, math32.Max(g, b))
+	min := math32.Min(r, math32.Min(g, b))
+	v = max
+	delta := max - min
+	if max <= 0 || delta <= 0 {
+		return 0, 0, v
+	}
+	s = delta / max
+	switch max {
+	case r:
+		h = (g - b) / delta
+	case g:
+		h = 2 + (b-r)/delta
+	default:
+		h = 4 + (r-g)/delta
+	}
+	h /= 6
+	if h < 0 {
+		h += 1
+	}
+	return h, s, v
+}
+
+// hsvToRGB converts the specified hue, saturation and value from 0 to 1
+// to RGB color components
+func hsvToRGB(h, s, v float32) (r, g, b float32) {
+
+	if s <= 0 {
+		return v, v, v
+	}
+	h = (h - math32.Floor(h)) * 6
+	i := int(h)
+	f := h - float32(i)
+	p := v * (1 - s)
+	q := v * (1 - s*f)
+	t := v * (1 - s*(1-f))
+	switch i % 6 {
+	case 0:
+		return v, t, p
+	case 1:
+		return q, v, p
+	case 2:
+		return p, v, t
+	case 3:
+		return p, q, v
+	case 4:
+		return t, p, v
+	default:
+		return v, p, q
+	}
+}
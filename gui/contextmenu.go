@@ -0,0 +1,113 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"github.com/g3n/engine/window"
+)
+
+// ContextMenu is a popup Menu which is shown at the cursor position
+// when the right mouse button is pressed over its target panels.
+// It is closed when one of its options is selected, when the mouse
+// is clicked outside of it or when the Escape key is pressed.
+// Menu items dispatch OnClick events with a pointer to the MenuItem
+// which can be identified by its Id().
+type ContextMenu struct {
+	Menu             // embedded menu
+	prevFocus IPanel // panel with key focus before the menu was shown
+}
+
+// NewContextMenu creates and returns a pointer to a new empty context menu
+func NewContextMenu() *ContextMenu {
+
+	cm := new(ContextMenu)
+	cm.Menu.initialize()
+	cm.Menu.popup = cm
+	cm.Panel.SetBounded(false)
+	cm.Panel.SetVisible(false)
+	cm.Panel.Subscribe(OnMouseOut, cm.onMouseOut)
+	return cm
+}
+
+// SetTarget sets a panel which shows this context menu
+// when the right mouse button is pressed over it.
+func (cm *ContextMenu) SetTarget(ipan IPanel) {
+
+	ipan.GetPanel().Subscribe(OnMouseDown, func(evname string, ev interface{}) {
+		mev := ev.(*window.MouseEvent)
+		if mev.Button != window.MouseButtonRight {
+			return
+		}
+		cm.Show(ipan.GetPanel().Root(), mev.Xpos, mev.Ypos)
+		ipan.GetPanel().Root().StopPropagation(StopAll)
+	})
+}
+
+// Show shows this context menu in the specified gui root panel
+// at the specified screen position in pixels.
+// The menu is moved if necessary to stay inside the root panel.
+func (cm *ContextMenu) Show(root *Root, x, y float32) {
+
+	if root == nil {
+		return
+	}
+	if cm.Parent() == nil {
+		root.Add(cm)
+	} else {
+		root.SetTopChild(cm)
+	}
+	// Keeps the menu inside the root panel or the window if the root is not sized
+	width, height := root.Size()
+	if width == 0 || height == 0 {
+		w, h := root.win.GetSize()
+		width = float32(w)
+		height = float32(h)
+	}
+	if x+cm.Width() > width {
+		x = width - cm.Width()
+	}
+	if y+cm.Height() > height {
+		y = height - cm.Height()
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	cm.SetPosition(x, y)
+	if !cm.Visible() {
+		cm.prevFocus = root.keyFocus
+	}
+	cm.setSelectedPos(-1)
+	cm.SetVisible(true)
+	root.SetKeyFocus(cm)
+}
+
+// Close closes this context menu and restores the key focus
+// to the panel which had it before the menu was shown.
+func (cm *ContextMenu) Close() {
+
+	if !cm.Visible() {
+		return
+	}
+	cm.setSelectedPos(-1)
+	cm.SetVisible(false)
+	if cm.root != nil && cm.root.keyFocus != nil && cm.root.keyFocus.GetPanel() == &cm.Panel {
+		cm.root.SetKeyFocus(cm.prevFocus)
+	}
+	cm.prevFocus = nil
+}
+
+// onMouseOut process OnMouseOut events closing the menu
+// if the mouse was not clicked over one of its open sub menus
+func (cm *ContextMenu) onMouseOut(evname string, ev interface{}) {
+
+	mev := ev.(*window.MouseEvent)
+	if cm.Menu.insideSubmenu(mev.Xpos, mev.Ypos) {
+		return
+	}
+	cm.Close()
+}
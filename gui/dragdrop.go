@@ -0,0 +1,278 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+// DragEvent is the event dispatched with OnDragStart, OnDragOver and OnDrop
+type DragEvent struct {
+	Source   IPanel      // panel being dragged
+	Target   IPanel      // drop target panel under the cursor (may be nil)
+	Payload  interface{} // payload of the dragged panel
+	Xpos     float32     // cursor x position in pixels
+	Ypos     float32     // cursor y position in pixels
+	Accepted bool        // payload was accepted by the drop target (OnDrop only)
+}
+
+// DragImageStyle contains the styling of the default drag image
+type DragImageStyle struct {
+	Border      BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color4
+}
+
+// panelDrag keeps the drag and drop state of a panel
+type panelDrag struct {
+	draggable bool                           // panel can be dragged
+	payload   interface{}                    // payload carried when dragged
+	image     IPanel                         // custom drag image (may be nil)
+	handler   func(payload interface{}) bool // drop handler of a drop target (may be nil)
+}
+
+// rootDrag keeps the state of the current drag operation of a gui root
+type rootDrag struct {
+	source  IPanel  // panel being dragged
+	startX  float32 // cursor x position when the mouse button was pressed
+	startY  float32 // cursor y position when the mouse button was pressed
+	offsetX float32 // horizontal offset of the cursor from the drag image
+	offsetY float32 // vertical offset of the cursor from the drag image
+	started bool    // cursor moved enough to start the drag
+	image   IPanel  // drag image following the cursor
+	custom  bool    // drag image was set by the source panel
+	target  IPanel  // drop target currently under the cursor (may be nil)
+}
+
+const (
+	dragThreshold = 4 // distance in pixels the cursor must move to start a drag
+)
+
+// SetDraggable sets if this panel can be dragged with the left mouse button.
+// The drag starts when the cursor moves after the button is pressed over
+// the panel and OnDragStart is dispatched to it. When the button is released
+// the payload is offered to the drop target under the cursor (if any) and
+// OnDrop is dispatched to this panel. Pressing Escape cancels the drag.
+func (p *Panel) SetDraggable(state bool) {
+
+	if p.drag == nil {
+		p.drag = new(panelDrag)
+	}
+	p.drag.draggable = state
+}
+
+// Draggable returns if this panel can be dragged
+func (p *Panel) Draggable() bool {
+
+	return p.drag != nil && p.drag.draggable
+}
+
+// SetDragPayload sets the payload carried when this panel is dragged
+func (p *Panel) SetDragPayload(payload interface{}) {
+
+	if p.drag == nil {
+		p.drag = new(panelDrag)
+	}
+	p.drag.payload = payload
+}
+
+// DragPayload returns the payload carried when this panel is dragged
+func (p *Panel) DragPayload() interface{} {
+
+	if p.drag == nil {
+		return nil
+	}
+	return p.drag.payload
+}
+
+// SetDragImage sets the panel shown under the cursor when this panel is
+// dragged. If nil (the default), a translucent panel with the size of this
+// panel is shown. The image is removed from the gui root but not disposed
+// when the drag finishes.
+func (p *Panel) SetDragImage(ipan IPanel) {
+
+	if p.drag == nil {
+		p.drag = new(panelDrag)
+	}
+	p.drag.image = ipan
+}
+
+// SetDropHandler sets the function called when a payload is dropped over
+// this panel which becomes a drop target. The function must return true
+// if the payload was accepted. Passing nil removes the drop handler.
+func (p *Panel) SetDropHandler(handler func(payload interface{}) bool) {
+
+	if p.drag == nil {
+		p.drag = new(panelDrag)
+	}
+	p.drag.handler = handler
+}
+
+// Dragging returns if a panel is currently being dragged
+func (r *Root) Dragging() bool {
+
+	return r.drag != nil && r.drag.started
+}
+
+// CancelDrag cancels the current drag operation if any.
+// OnDrop is dispatched to the dragged panel with Accepted false.
+func (r *Root) CancelDrag() {
+
+	if r.drag == nil {
+		return
+	}
+	d := r.drag
+	r.endDrag()
+	if d.started {
+		d.source.GetPanel().Dispatch(OnDrop, &DragEvent{Source: d.source, Payload: d.source.GetPanel().DragPayload()})
+	}
+}
+
+// dragMouse process mouse button events for drag and drop
+// and returns true if the event was used by the current drag operation
+func (r *Root) dragMouse(evname string, mev *window.MouseEvent) bool {
+
+	if mev.Button != window.MouseButtonLeft {
+		return false
+	}
+	switch evname {
+	case OnMouseDown:
+		// Checks for a draggable panel under the cursor unless some
+		// panel took the mouse focus when receiving this event.
+		r.drag = nil
+		if r.mouseFocus != nil {
+			return false
+		}
+		for _, ipan := range r.targets {
+			if ipan.GetPanel().Draggable() {
+				r.drag = &rootDrag{source: ipan, startX: mev.Xpos, startY: mev.Ypos}
+				break
+			}
+		}
+		return false
+	case OnMouseUp:
+		if r.drag == nil {
+			return false
+		}
+		if !r.drag.started {
+			r.drag = nil
+			return false
+		}
+		r.drop(mev.Xpos, mev.Ypos)
+		return true
+	}
+	return false
+}
+
+// dragCursor process cursor events for drag and drop after the event
+// was sent to the panels under the cursor
+func (r *Root) dragCursor(cev *window.CursorEvent) {
+
+	if r.drag == nil {
+		return
+	}
+	d := r.drag
+	if !d.started {
+		if math32.Abs(cev.Xpos-d.startX) < dragThreshold && math32.Abs(cev.Ypos-d.startY) < dragThreshold {
+			return
+		}
+		r.startDrag(cev.Xpos, cev.Ypos)
+	}
+	d.image.GetPanel().SetPosition(cev.Xpos-d.offsetX, cev.Ypos-d.offsetY)
+
+	// Finds the most foreground drop target under the cursor
+	d.target = nil
+	for _, ipan := range r.targets {
+		pan := ipan.GetPanel()
+		if pan.drag != nil && pan.drag.handler != nil {
+			d.target = ipan
+			break
+		}
+	}
+	if d.target != nil {
+		d.target.GetPanel().Dispatch(OnDragOver, r.dragEvent(cev.Xpos, cev.Ypos))
+	}
+	r.StopPropagation(Stop3D)
+}
+
+// startDrag starts the drag of the pending source panel, dispatches
+// OnDragStart to it and shows the drag image
+func (r *Root) startDrag(x, y float32) {
+
+	d := r.drag
+	d.started = true
+	src := d.source.GetPanel()
+	src.Dispatch(OnDragStart, r.dragEvent(x, y))
+
+	// Creates the drag image which does not receive mouse events
+	d.image = src.drag.image
+	d.custom = d.image != nil
+	if d.custom {
+		d.offsetX = -8
+		d.offsetY = -8
+	} else {
+		s := &StyleDefault.DragImage
+		img := NewPanel(src.Width(), src.Height())
+		img.SetBordersFrom(&s.Border)
+		img.SetBordersColor4(&s.BorderColor)
+		img.SetColor4(&s.BgColor)
+		d.image = img
+		d.offsetX = d.startX - src.pospix.X
+		d.offsetY = d.startY - src.pospix.Y
+	}
+	pan := d.image.GetPanel()
+	pan.SetBounded(false)
+	pan.SetEnabled(false)
+	r.Add(d.image)
+	r.SetTopChild(d.image)
+	r.SetCursorDrag()
+}
+
+// drop offers the payload to the drop target under the cursor (if any),
+// finishes the drag and dispatches OnDrop to the target if the payload
+// was accepted and to the dragged panel.
+func (r *Root) drop(x, y float32) {
+
+	d := r.drag
+	ev := r.dragEvent(x, y)
+	r.endDrag()
+	if d.target != nil {
+		handler := d.target.GetPanel().drag.handler
+		ev.Accepted = handler != nil && handler(ev.Payload)
+		if ev.Accepted {
+			d.target.GetPanel().Dispatch(OnDrop, ev)
+		}
+	}
+	d.source.GetPanel().Dispatch(OnDrop, ev)
+}
+
+// endDrag removes the drag image and clears the drag state
+func (r *Root) endDrag() {
+
+	d := r.drag
+	r.drag = nil
+	if d.image == nil {
+		return
+	}
+	r.Remove(d.image)
+	if !d.custom {
+		d.image.GetPanel().Dispose()
+	}
+	r.SetCursorNormal()
+}
+
+// dragEvent returns a new drag event for the current drag operation
+func (r *Root) dragEvent(x, y float32) *DragEvent {
+
+	d := r.drag
+	return &DragEvent{
+		Source:  d.source,
+		Target:  d.target,
+		Payload: d.source.GetPanel().DragPayload(),
+		Xpos:    x,
+		Ypos:    y,
+	}
+}
@@ -5,8 +5,11 @@
 package gui
 
 import (
+	"strings"
+
 	"github.com/g3n/engine/gui/assets"
 	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/text"
 	"github.com/g3n/engine/window"
 )
 
@@ -21,6 +24,11 @@ type DropDown struct {
 	overList     bool
 	focus        bool
 	clickOut     bool
+	filterable   bool                          // type-ahead filtering enabled
+	filterFunc   func(item, query string) bool // optional custom filter match function
+	query        string                        // current filter query
+	litemText    string                        // drop box item text before filtering
+	items        []*ImageLabel                 // all the items of the dropdown (used for filtering)
 }
 
 // DropDown list style
@@ -60,7 +68,7 @@ func NewDropDown(width float32, item *ImageLabel) *DropDown {
 	// Create icon
 	dd.icon = NewIconLabel(" ")
 	dd.icon.SetFontSize(StyleDefault.Font.Size() * 1.3)
-	dd.icon.SetText(string(assets.ArrowDropDown))
+	dd.icon.SetText(string(rune(assets.ArrowDropDown)))
 	dd.Panel.Add(dd.icon)
 
 	/// Create list
@@ -72,6 +80,9 @@ func NewDropDown(width float32, item *ImageLabel) *DropDown {
 	dd.list.Subscribe(OnMouseDown, dd.onListMouse)
 	dd.list.Subscribe(OnMouseOut, dd.onListMouse)
 	dd.list.Subscribe(OnChange, dd.onListChangeEvent)
+	dd.list.Subscribe(OnChar, dd.onListChar)
+	dd.list.Subscribe(OnKeyDown, dd.onListKey)
+	dd.list.Subscribe(OnKeyRepeat, dd.onListKey)
 	dd.Panel.Add(dd.list)
 
 	dd.update()
@@ -83,21 +94,31 @@ func NewDropDown(width float32, item *ImageLabel) *DropDown {
 // Add add a list item at the end of the list
 func (dd *DropDown) Add(item *ImageLabel) {
 
+	dd.setQuery("")
 	dd.list.Add(item)
+	dd.items = append(dd.items, item)
 }
 
 // InsertAt inserts a list item at the specified position
 // Returs true if the item was successfuly inserted
 func (dd *DropDown) InsertAt(pos int, item *ImageLabel) {
 
+	dd.setQuery("")
 	dd.list.InsertAt(pos, item)
+	dd.items = append(dd.items, nil)
+	copy(dd.items[pos+1:], dd.items[pos:])
+	dd.items[pos] = item
 }
 
 // RemoveAt removes the list item from the specified position
 // Returs true if the item was successfuly removed
 func (dd *DropDown) RemoveAt(pos int) {
 
+	dd.setQuery("")
 	dd.list.RemoveAt(pos)
+	copy(dd.items[pos:], dd.items[pos+1:])
+	dd.items[len(dd.items)-1] = nil
+	dd.items = dd.items[:len(dd.items)-1]
 }
 
 // ItemAt returns the list item at the specified position
@@ -125,6 +146,32 @@ func (dd *DropDown) SelectPos(pos int) {
 	dd.list.SelectPos(pos, true)
 }
 
+// SetFilterable sets the type-ahead filtering state of this dropdown.
+// When filtering is enabled, typing while the list is open narrows the
+// visible items to the ones matching the typed text.
+// Enter selects the first matching item and Escape restores the full list.
+func (dd *DropDown) SetFilterable(state bool) {
+
+	dd.filterable = state
+	if !state {
+		dd.setQuery("")
+	}
+}
+
+// Filterable returns the current type-ahead filtering state of this dropdown
+func (dd *DropDown) Filterable() bool {
+
+	return dd.filterable
+}
+
+// SetFilterFunc sets the function used to check if an item text matches
+// the current filter query. If not set or set to nil, the default
+// case insensitive substring match is used.
+func (dd *DropDown) SetFilterFunc(f func(item, query string) bool) {
+
+	dd.filterFunc = f
+}
+
 // onKeyEvent is called when key event is received when this dropdown has the key focus.
 func (dd *DropDown) onKeyEvent(evname string, ev interface{}) {
 
@@ -183,6 +230,7 @@ func (dd *DropDown) onListMouse(evname string, ev interface{}) {
 		}
 		// Otherwise, closes the list
 		dd.list.SetVisible(false)
+		dd.setQuery("")
 		//dd.copySelected()
 		dd.overList = false
 		dd.update()
@@ -192,6 +240,7 @@ func (dd *DropDown) onListMouse(evname string, ev interface{}) {
 	if evname == OnMouseOut {
 		if dd.list.Visible() {
 			dd.list.SetVisible(false)
+			dd.setQuery("")
 		}
 		// If list clickout occurred inside the dropdown, set 'clickOut' to
 		// indicate that the list was already closed
@@ -217,6 +266,104 @@ func (dd *DropDown) onListCursor(evname string, ev interface{}) {
 
 }
 
+// onListChar receives subscribed char events for the list
+func (dd *DropDown) onListChar(evname string, ev interface{}) {
+
+	if !dd.filterable || !dd.list.Visible() {
+		return
+	}
+	cev := ev.(*window.CharEvent)
+	dd.setQuery(dd.query + string(cev.Char))
+	dd.root.StopPropagation(Stop3D)
+}
+
+// onListKey receives subscribed key events for the list
+func (dd *DropDown) onListKey(evname string, ev interface{}) {
+
+	if !dd.filterable || len(dd.query) == 0 {
+		return
+	}
+	kev := ev.(*window.KeyEvent)
+	switch kev.Keycode {
+	case window.KeyBackspace:
+		dd.setQuery(text.StrRemove(dd.query, text.StrCount(dd.query)-1))
+	case window.KeyEscape:
+		dd.setQuery("")
+	case window.KeyEnter:
+		// Selects the currently highlighted matching item if any.
+		// The list was already closed by its own key event handler.
+		sel := dd.list.Selected()
+		var item *ImageLabel
+		if len(sel) > 0 {
+			item = sel[0].(*ImageLabel)
+		}
+		dd.list.SetVisible(false)
+		dd.setQuery("")
+		if item != nil {
+			pos := dd.list.ItemPosition(item)
+			dd.list.setSelection(dd.list.items[pos].(*ListItem), true, true, true)
+		}
+	default:
+		return
+	}
+	dd.root.StopPropagation(Stop3D)
+}
+
+// setQuery sets the current filter query and shows in the list
+// only the items which matches the query.
+// An empty query restores all the items.
+func (dd *DropDown) setQuery(query string) {
+
+	if query == dd.query {
+		return
+	}
+	// Saves the drop box item text when filtering starts
+	if len(dd.query) == 0 {
+		dd.litemText = dd.litem.Text()
+	}
+	dd.query = query
+
+	// Removes all the items from the list and inserts
+	// only the items which match the query
+	for dd.list.Len() > 0 {
+		dd.list.RemoveAt(0)
+	}
+	for _, item := range dd.items {
+		if len(query) == 0 || dd.match(item.Text(), query) {
+			dd.list.Add(item)
+		}
+	}
+
+	// Restores the drop box item and the selected item when filtering ends
+	if len(query) == 0 {
+		dd.litem.SetText(dd.litemText)
+		if dd.selItem != nil {
+			pos := dd.list.ItemPosition(dd.selItem)
+			if pos >= 0 {
+				dd.list.setSelection(dd.list.items[pos].(*ListItem), true, true, false)
+			}
+		}
+		dd.recalc()
+		return
+	}
+
+	// Shows the query in the drop box and highlights the first match
+	dd.litem.SetText(query)
+	if dd.list.Len() > 0 {
+		dd.list.setSelection(dd.list.items[0].(*ListItem), true, true, false)
+	}
+	dd.recalc()
+}
+
+// match checks if the specified item text matches the filter query
+func (dd *DropDown) match(item, query string) bool {
+
+	if dd.filterFunc != nil {
+		return dd.filterFunc(item, query)
+	}
+	return strings.Contains(strings.ToLower(item), strings.ToLower(query))
+}
+
 // copySelected copy to the dropdown panel the selected item
 // from the list.
 func (dd *DropDown) copySelected() {
@@ -10,6 +10,7 @@ import (
 	"github.com/g3n/engine/window"
 	"strings"
 	"time"
+	"unicode"
 )
 
 type Edit struct {
@@ -24,6 +25,8 @@ type Edit struct {
 	blinkID     int
 	caretOn     bool
 	styles      *EditStyles
+	mask        []rune                            // optional input mask
+	validator   func(r rune, current string) bool // optional input validator
 }
 
 type EditStyle struct {
@@ -48,6 +51,14 @@ const (
 	blinkTime   = 1000
 )
 
+// Input mask placeholder characters.
+// All other characters of a mask are literal separators.
+const (
+	MaskDigit    = '#' // accepts a digit
+	MaskLetter   = 'A' // accepts a letter
+	MaskAlphaNum = '*' // accepts a letter or a digit
+)
+
 // NewEdit creates and returns a pointer to a new edit widget
 func NewEdit(width int, placeHolder string) *Edit {
 
@@ -69,31 +80,107 @@ func NewEdit(width int, placeHolder string) *Edit {
 	ed.Label.Subscribe(OnCursorEnter, ed.onCursor)
 	ed.Label.Subscribe(OnCursorLeave, ed.onCursor)
 	ed.Label.Subscribe(OnEnable, func(evname string, ev interface{}) { ed.update() })
+	ed.Label.Subscribe(OnFocus, ed.onFocus)
+	ed.Label.SetFocusable(true)
 
 	ed.update()
 	return ed
 }
 
 // SetText sets this edit text
-func (ed *Edit) SetText(text string) *Edit {
+func (ed *Edit) SetText(newText string) *Edit {
 
 	// Remove new lines from text
-	ed.text = strings.Replace(text, "\n", "", -1)
+	ed.text = strings.Replace(newText, "\n", "", -1)
+	if ed.mask != nil {
+		ed.text = ed.maskFormat(ed.maskFilter(ed.text))
+	}
+	if ed.col > text.StrCount(ed.text) {
+		ed.col = text.StrCount(ed.text)
+	}
 	ed.update()
 	return ed
 }
 
 // Text returns the current edited text
+// which never includes the place holder text
 func (ed *Edit) Text() string {
 
 	return ed.text
 }
 
+// SetPlaceholder sets the hint text which is shown with the style
+// HolderColor when the edit is empty and does not have the key focus
+func (ed *Edit) SetPlaceholder(text string) *Edit {
+
+	ed.placeHolder = strings.Replace(text, "\n", "", -1)
+	ed.update()
+	return ed
+}
+
+// Placeholder returns the current place holder text
+func (ed *Edit) Placeholder() string {
+
+	return ed.placeHolder
+}
+
+// SetInputMask sets the input mask for this edit. In the mask pattern
+// the characters MaskDigit, MaskLetter and MaskAlphaNum are replaced by
+// the accepted input characters and all other characters are separators
+// which are automatically inserted. For example: "(###) ###-####".
+// An empty pattern removes the mask.
+// The current text is filtered and formatted with the new mask.
+func (ed *Edit) SetInputMask(pattern string) *Edit {
+
+	if pattern == "" {
+		ed.mask = nil
+		ed.text = ed.RawValue()
+	} else {
+		raw := ed.RawValue()
+		ed.mask = []rune(pattern)
+		ed.text = ed.maskFormat(ed.maskFilter(raw))
+	}
+	ed.col = text.StrCount(ed.text)
+	ed.update()
+	return ed
+}
+
+// SetValidator sets an optional function to validate each character
+// inserted in this edit. It receives the character and the current
+// raw value and must return true to accept the character.
+func (ed *Edit) SetValidator(f func(r rune, current string) bool) *Edit {
+
+	ed.validator = f
+	return ed
+}
+
+// MaskedValue returns the current text formatted with the input mask
+func (ed *Edit) MaskedValue() string {
+
+	return ed.text
+}
+
+// RawValue returns the current text without the input mask separators
+func (ed *Edit) RawValue() string {
+
+	if ed.mask == nil {
+		return ed.text
+	}
+	// The formatted text characters are aligned with the mask characters
+	raw := make([]rune, 0)
+	for pos, r := range []rune(ed.text) {
+		if pos < len(ed.mask) && maskSlot(ed.mask[pos]) {
+			raw = append(raw, r)
+		}
+	}
+	return string(raw)
+}
+
 // SetFontSize sets label font size (overrides Label.SetFontSize)
 func (ed *Edit) SetFontSize(size float64) *Edit {
 
 	ed.Label.fontSize = size
-	ed.redraw(ed.focus)
+	ed.update()
 	return ed
 }
 
@@ -144,6 +231,19 @@ func (ed *Edit) CursorRight() {
 // CursorBack deletes the character at left of the cursor if possible
 func (ed *Edit) CursorBack() {
 
+	if ed.mask != nil {
+		ri := ed.maskRawIndex(ed.col)
+		if ri == 0 {
+			return
+		}
+		raw := []rune(ed.RawValue())
+		raw = append(raw[:ri-1], raw[ri:]...)
+		ed.text = ed.maskFormat(raw)
+		ed.col = ed.maskCol(ri - 1)
+		ed.redraw(ed.focus)
+		ed.Dispatch(OnChange, nil)
+		return
+	}
 	if ed.col > 0 {
 		ed.col--
 		ed.text = text.StrRemove(ed.text, ed.col)
@@ -169,6 +269,19 @@ func (ed *Edit) CursorEnd() {
 // CursorDelete deletes the character at the right of the cursor if possible
 func (ed *Edit) CursorDelete() {
 
+	if ed.mask != nil {
+		ri := ed.maskRawIndex(ed.col)
+		raw := []rune(ed.RawValue())
+		if ri >= len(raw) {
+			return
+		}
+		raw = append(raw[:ri], raw[ri+1:]...)
+		ed.text = ed.maskFormat(raw)
+		ed.col = ed.maskCol(ri)
+		ed.redraw(ed.focus)
+		ed.Dispatch(OnChange, nil)
+		return
+	}
 	if ed.col < text.StrCount(ed.text) {
 		ed.text = text.StrRemove(ed.text, ed.col)
 		ed.redraw(ed.focus)
@@ -176,10 +289,32 @@ func (ed *Edit) CursorDelete() {
 	}
 }
 
-// CursorInput inserts the specified string at the current cursor position
+// CursorInput inserts the specified string at the current cursor position.
+// The string is checked by the validator and the input mask, if set,
+// and is truncated at the first character which is not accepted
+// or which would exceed MaxLength.
 func (ed *Edit) CursorInput(s string) {
 
-	if text.StrCount(ed.text) >= ed.MaxLength {
+	count := text.StrCount(ed.text)
+	if count >= ed.MaxLength {
+		return
+	}
+	if ed.mask != nil {
+		ed.maskInput(s)
+		return
+	}
+
+	// Keeps only the accepted prefix of the input
+	if ed.validator != nil {
+		for pos, r := range s {
+			if !ed.validator(r, ed.text) {
+				s = s[:pos]
+				break
+			}
+		}
+	}
+	s = text.StrPrefix(s, ed.MaxLength-count)
+	if s == "" {
 		return
 	}
 
@@ -198,12 +333,187 @@ func (ed *Edit) CursorInput(s string) {
 	}
 
 	ed.text = newText
-	ed.col++
+	ed.col += text.StrCount(s)
 
 	ed.Dispatch(OnChange, nil)
 	ed.redraw(ed.focus)
 }
 
+// maskInput inserts the accepted prefix of the specified string
+// in the raw value at the current cursor position and formats the
+// new text using the input mask
+func (ed *Edit) maskInput(s string) {
+
+	ri := ed.maskRawIndex(ed.col)
+	raw := []rune(ed.RawValue())
+	slots := ed.maskSlots()
+	inserted := 0
+	for _, r := range s {
+		if len(raw) >= slots {
+			break
+		}
+		if ed.validator != nil && !ed.validator(r, string(raw)) {
+			break
+		}
+		// The characters after the cursor are shifted and must fit their new slots
+		newRaw := make([]rune, 0, len(raw)+1)
+		newRaw = append(newRaw, raw[:ri+inserted]...)
+		newRaw = append(newRaw, r)
+		newRaw = append(newRaw, raw[ri+inserted:]...)
+		if !ed.maskFits(newRaw) {
+			break
+		}
+		raw = newRaw
+		inserted++
+	}
+	if inserted == 0 {
+		return
+	}
+	newText := ed.maskFormat(raw)
+
+	// Checks if new text exceeds edit width
+	width, _ := ed.Label.font.MeasureText(newText)
+	if float32(width)+editMarginX+float32(1) >= ed.Label.ContentWidth() {
+		return
+	}
+	ed.text = newText
+	ed.col = ed.maskCol(ri + inserted)
+	ed.Dispatch(OnChange, nil)
+	ed.redraw(ed.focus)
+}
+
+// maskSlots returns the number of input characters accepted by the mask
+func (ed *Edit) maskSlots() int {
+
+	count := 0
+	for _, m := range ed.mask {
+		if maskSlot(m) {
+			count++
+		}
+	}
+	return count
+}
+
+// maskFits returns if all the characters of the specified raw value
+// are accepted by their mask slots
+func (ed *Edit) maskFits(raw []rune) bool {
+
+	ri := 0
+	for _, m := range ed.mask {
+		if ri >= len(raw) {
+			return true
+		}
+		if !maskSlot(m) {
+			continue
+		}
+		if !maskAccept(m, raw[ri]) {
+			return false
+		}
+		ri++
+	}
+	return ri >= len(raw)
+}
+
+// maskFilter returns the characters of the specified string which
+// are accepted in sequence by the mask slots ignoring all the others
+func (ed *Edit) maskFilter(s string) []rune {
+
+	raw := make([]rune, 0)
+	mi := 0
+	for _, r := range s {
+		for mi < len(ed.mask) && !maskSlot(ed.mask[mi]) {
+			mi++
+		}
+		if mi >= len(ed.mask) {
+			break
+		}
+		if !maskAccept(ed.mask[mi], r) {
+			continue
+		}
+		if ed.validator != nil && !ed.validator(r, string(raw)) {
+			continue
+		}
+		raw = append(raw, r)
+		mi++
+	}
+	return raw
+}
+
+// maskFormat returns the text formatted with the input mask from the
+// specified raw value. Separators are only inserted before raw characters.
+func (ed *Edit) maskFormat(raw []rune) string {
+
+	out := make([]rune, 0, len(ed.mask))
+	ri := 0
+	for _, m := range ed.mask {
+		if ri >= len(raw) {
+			break
+		}
+		if maskSlot(m) {
+			out = append(out, raw[ri])
+			ri++
+		} else {
+			out = append(out, m)
+		}
+	}
+	return string(out)
+}
+
+// maskRawIndex returns the index in the raw value of the character
+// at the specified column of the formatted text
+func (ed *Edit) maskRawIndex(col int) int {
+
+	ri := 0
+	for mi, m := range ed.mask {
+		if mi >= col {
+			break
+		}
+		if maskSlot(m) {
+			ri++
+		}
+	}
+	return ri
+}
+
+// maskCol returns the column of the formatted text after
+// the specified number of raw characters
+func (ed *Edit) maskCol(count int) int {
+
+	if count == 0 {
+		return 0
+	}
+	ri := 0
+	for mi, m := range ed.mask {
+		if maskSlot(m) {
+			ri++
+			if ri == count {
+				return mi + 1
+			}
+		}
+	}
+	return text.StrCount(ed.text)
+}
+
+// maskSlot returns if the specified mask character is an input placeholder
+func maskSlot(m rune) bool {
+
+	return m == MaskDigit || m == MaskLetter || m == MaskAlphaNum
+}
+
+// maskAccept returns if the specified mask placeholder accepts the specified character
+func maskAccept(m, r rune) bool {
+
+	switch m {
+	case MaskDigit:
+		return unicode.IsDigit(r)
+	case MaskLetter:
+		return unicode.IsLetter(r)
+	case MaskAlphaNum:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
 // redraw redraws the text showing the caret if specified
 func (ed *Edit) redraw(caret bool) {
 
@@ -231,6 +541,18 @@ func (ed *Edit) onKey(evname string, ev interface{}) {
 		ed.CursorBack()
 	case window.KeyDelete:
 		ed.CursorDelete()
+	case window.KeyC:
+		if kev.Mods&window.ModControl == 0 {
+			return
+		}
+		ed.root.win.SetClipboardString(ed.text)
+	case window.KeyV:
+		if kev.Mods&window.ModControl == 0 {
+			return
+		}
+		if s, err := ed.root.win.GetClipboardString(); err == nil {
+			ed.CursorInput(strings.Replace(strings.Replace(s, "\r", "", -1), "\n", " ", -1))
+		}
 	default:
 		return
 	}
@@ -272,6 +594,18 @@ func (ed *Edit) onMouse(evname string, ev interface{}) {
 	ed.root.StopPropagation(Stop3D)
 }
 
+// onFocus receives subscribed focus events and starts the caret blinking
+func (ed *Edit) onFocus(evname string, ev interface{}) {
+
+	if ed.focus {
+		return
+	}
+	ed.focus = true
+	ed.blinkID = ed.root.SetInterval(750*time.Millisecond, nil, ed.blink)
+	ed.CursorEnd()
+	ed.update()
+}
+
 // onCursor receives subscribed cursor events
 func (ed *Edit) onCursor(evname string, ev interface{}) {
 
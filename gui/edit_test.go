@@ -0,0 +1,35 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"testing"
+)
+
+func TestEditCursorInputMaxLength(t *testing.T) {
+
+	cases := []struct {
+		text  string
+		col   int
+		input string
+		want  string
+	}{
+		{"ab", 2, "cdefgh", "abcde"},
+		{"ab", 0, "xyzw", "xyzab"},
+		{"ab", 1, "ção", "açãob"},
+		{"abcde", 5, "f", "abcde"},
+		{"", 0, "12345678", "12345"},
+	}
+	for _, c := range cases {
+		ed := NewEdit(400, "")
+		ed.MaxLength = 5
+		ed.SetText(c.text)
+		ed.CursorPos(c.col)
+		ed.CursorInput(c.input)
+		if got := ed.Text(); got != c.want {
+			t.Errorf("%q at %d: CursorInput(%q) = %q, want %q", c.text, c.col, c.input, got, c.want)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 // Consolidate window events plus GUI events
 const (
 	OnClick       = "gui.OnClick"       // Widget clicked by mouse or key
+	OnLongPress   = "gui.OnLongPress"   // Widget kept pressed by mouse or key
 	OnCursor      = window.OnCursor     // cursor (mouse) position events
 	OnCursorEnter = "gui.OnCursorEnter" // cursor enters the panel area
 	OnCursorLeave = "gui.OnCursorLeave" // cursor leaves the panel area
@@ -27,4 +28,11 @@ const (
 	OnScroll      = window.OnScroll     // scroll event
 	OnChild       = "gui.OnChild"       // child added to or removed from panel
 	OnRadioGroup  = "gui.OnRadioGroup"  // radio button from a group changed state
+	OnExpand      = "gui.OnExpand"      // tree node expanded
+	OnCollapse    = "gui.OnCollapse"    // tree node collapsed
+	OnFocus       = "gui.OnFocus"       // panel received the key focus (no parameters)
+	OnDragStart   = "gui.OnDragStart"   // panel started being dragged
+	OnDragOver    = "gui.OnDragOver"    // dragged panel moved over a drop target
+	OnDrop        = "gui.OnDrop"        // dragged panel dropped or drag cancelled
+	OnClose       = "gui.OnClose"       // modal panel closed
 )
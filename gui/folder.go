@@ -158,7 +158,7 @@ func (f *Folder) applyStyle(s *FolderStyle) {
 	if f.contentPanel.GetPanel().Visible() {
 		icode = 1
 	}
-	f.icon.SetText(string(s.Icons[icode]))
+	f.icon.SetText(string(rune(s.Icons[icode])))
 	f.icon.SetColor(&s.FgColor)
 	f.label.SetBgColor(&s.BgColor)
 	f.label.SetColor(&s.FgColor)
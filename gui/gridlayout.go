@@ -5,13 +5,18 @@
 package gui
 
 type GridLayout struct {
-	columns int
+	pan        IPanel          // last panel laid out
+	columns    int             // number of columns for items without layout params (0 = ignore them)
+	colGap     float32         // horizontal gap between columns in pixels
+	rowGap     float32         // vertical gap between rows in pixels
+	colWeights map[int]float32 // stretch weights of the columns
 }
 
 type GridLayoutParams struct {
 	Row     int   // grid layout row number from 0
 	Col     int   // grid layout column number from 0
 	ColSpan int   // number of additional columns to ocuppy to the right
+	RowSpan int   // number of additional rows to ocuppy to the bottom
 	AlignH  Align // vertical alignment
 	AlignV  Align // horizontal alignment
 }
@@ -20,18 +25,83 @@ type GridLayoutParams struct {
 func NewGridLayout() *GridLayout {
 
 	g := new(GridLayout)
+	g.colWeights = make(map[int]float32)
 	return g
 }
 
+// SetColumns sets the number of columns of the grid used to place the
+// children without layout params in order, from left to right and top to
+// bottom, after the rows used by the children with layout params.
+// The default is zero and the children without layout params are ignored.
+func (g *GridLayout) SetColumns(columns int) {
+
+	g.columns = columns
+	g.Recalc(g.pan)
+}
+
+// Columns returns the number of columns of the grid used to place
+// the children without layout params
+func (g *GridLayout) Columns() int {
+
+	return g.columns
+}
+
+// SetGaps sets the horizontal gap between columns and the vertical gap
+// between rows in pixels and updates the layout if possible
+func (g *GridLayout) SetGaps(colGap, rowGap float32) {
+
+	g.colGap = colGap
+	g.rowGap = rowGap
+	g.Recalc(g.pan)
+}
+
+// Gaps returns the horizontal gap between columns and the vertical gap
+// between rows in pixels
+func (g *GridLayout) Gaps() (float32, float32) {
+
+	return g.colGap, g.rowGap
+}
+
+// SetColWeight sets the stretch weight of the specified column and
+// updates the layout if possible. When the parent panel is wider than
+// the grid, the extra width is distributed among the columns
+// proportionally to their weights. The default weight is zero.
+func (g *GridLayout) SetColWeight(col int, weight float32) {
+
+	if g.colWeights == nil {
+		g.colWeights = make(map[int]float32)
+	}
+	if weight <= 0 {
+		delete(g.colWeights, col)
+	} else {
+		g.colWeights[col] = weight
+	}
+	g.Recalc(g.pan)
+}
+
+// ColWeight returns the stretch weight of the specified column
+func (g *GridLayout) ColWeight(col int) float32 {
+
+	return g.colWeights[col]
+}
+
+// Recalc recalculates and sets the position and sizes of all children
 func (g *GridLayout) Recalc(ipan IPanel) {
 
 	type element struct {
 		panel  *Panel
 		params *GridLayoutParams
 	}
+
+	// Saves the received panel
+	g.pan = ipan
+	if g.pan == nil {
+		return
+	}
 	rows := 0
 	cols := 0
 	items := []element{}
+	auto := []*Panel{}
 
 	pan := ipan.GetPanel()
 	for _, obj := range pan.Children() {
@@ -41,8 +111,11 @@ func (g *GridLayout) Recalc(ipan IPanel) {
 		if !child.Visible() {
 			continue
 		}
-		// Ignore if no layout params
+		// Children without layout params are placed later if the number of columns was set
 		if child.layoutParams == nil {
+			if g.columns > 0 {
+				auto = append(auto, child)
+			}
 			continue
 		}
 		// Checks layout params
@@ -50,14 +123,27 @@ func (g *GridLayout) Recalc(ipan IPanel) {
 		if !ok {
 			panic("layoutParams is not GridLayoutParams")
 		}
-		if params.Row >= rows {
-			rows = params.Row + 1
+		if params.Row+params.RowSpan >= rows {
+			rows = params.Row + params.RowSpan + 1
 		}
-		if params.Col >= cols {
-			cols = params.Col + 1
+		if params.Col+params.ColSpan >= cols {
+			cols = params.Col + params.ColSpan + 1
 		}
 		items = append(items, element{child, params})
 	}
+
+	// Places the children without layout params after the other rows
+	for i, child := range auto {
+		params := &GridLayoutParams{Row: rows + i/g.columns, Col: i % g.columns}
+		items = append(items, element{child, params})
+	}
+	if len(auto) > 0 {
+		rows += (len(auto) + g.columns - 1) / g.columns
+		if g.columns > cols {
+			cols = g.columns
+		}
+	}
+
 	// Check limits
 	if rows > 100 {
 		panic("Element row outsize limits")
@@ -66,55 +152,80 @@ func (g *GridLayout) Recalc(ipan IPanel) {
 		panic("Element column outsize limits")
 	}
 
-	// Determine row and column maximum sizes
-	colSizes := make([]int, cols)
-	rowSizes := make([]int, rows)
+	// Determine row and column maximum sizes of the elements
+	// which occupy only one cell
+	colSizes := make([]float32, cols)
+	rowSizes := make([]float32, rows)
+	for _, el := range items {
+		width := float32(int(el.panel.Width()))
+		height := float32(int(el.panel.Height()))
+		if el.params.ColSpan == 0 && width > colSizes[el.params.Col] {
+			colSizes[el.params.Col] = width
+		}
+		if el.params.RowSpan == 0 && height > rowSizes[el.params.Row] {
+			rowSizes[el.params.Row] = height
+		}
+	}
+
+	// Enlarges the spanned columns and rows of the elements which
+	// occupy more than one cell and are larger than their cells
 	for _, el := range items {
-		width := el.panel.Width()
-		height := el.panel.Height()
-		if int(width) > colSizes[el.params.Col] {
-			colSizes[el.params.Col] = int(width)
+		if el.params.ColSpan > 0 {
+			width := float32(int(el.panel.Width()))
+			g.spanSizes(colSizes[el.params.Col:el.params.Col+el.params.ColSpan+1], width, g.colGap)
 		}
-		if int(height) > rowSizes[el.params.Row] {
-			rowSizes[el.params.Row] = int(height)
+		if el.params.RowSpan > 0 {
+			height := float32(int(el.panel.Height()))
+			g.spanSizes(rowSizes[el.params.Row:el.params.Row+el.params.RowSpan+1], height, g.rowGap)
+		}
+	}
+
+	// Distributes the extra width of the parent among the weighted columns
+	var twidth float32
+	var tweight float32
+	for c, size := range colSizes {
+		twidth += size
+		if c > 0 {
+			twidth += g.colGap
+		}
+		tweight += g.colWeights[c]
+	}
+	if extra := pan.ContentWidth() - twidth; extra > 0 && tweight > 0 {
+		for c := range colSizes {
+			colSizes[c] += extra * g.colWeights[c] / tweight
 		}
 	}
 
 	// Determine row and column starting positions
-	colStart := make([]int, cols)
-	rowStart := make([]int, rows)
+	colStart := make([]float32, cols)
+	rowStart := make([]float32, rows)
 	for i := 1; i < len(colSizes); i++ {
-		colStart[i] = colStart[i-1] + colSizes[i-1]
+		colStart[i] = colStart[i-1] + colSizes[i-1] + g.colGap
 	}
 	for i := 1; i < len(rowSizes); i++ {
-		rowStart[i] = rowStart[i-1] + rowSizes[i-1]
+		rowStart[i] = rowStart[i-1] + rowSizes[i-1] + g.rowGap
 	}
 
 	// Position the elements
 	for _, el := range items {
 		row := el.params.Row
 		col := el.params.Col
-		cellHeight := rowSizes[row]
-		// Current cell width
-		cellWidth := 0
-		for c := 0; c <= el.params.ColSpan; c++ {
-			pos := col + c
-			if pos >= len(colSizes) {
-				break
-			}
-			cellWidth += colSizes[pos]
-		}
-		rstart := float32(rowStart[row])
-		cstart := float32(colStart[col])
+		// Current cell width and height including the spanned cells
+		cellWidth := colStart[col+el.params.ColSpan] + colSizes[col+el.params.ColSpan] - colStart[col]
+		cellHeight := rowStart[row+el.params.RowSpan] + rowSizes[row+el.params.RowSpan] - rowStart[row]
+		rstart := rowStart[row]
+		cstart := colStart[col]
 		// Horizontal alignment
 		var dx float32 = 0
 		switch el.params.AlignH {
 		case AlignNone:
 		case AlignLeft:
 		case AlignRight:
-			dx = float32(cellWidth) - el.panel.width
+			dx = cellWidth - el.panel.width
 		case AlignCenter:
-			dx = (float32(cellWidth) - el.panel.width) / 2
+			dx = (cellWidth - el.panel.width) / 2
+		case AlignWidth:
+			el.panel.SetWidth(cellWidth)
 		default:
 			panic("Invalid horizontal alignment")
 		}
@@ -124,12 +235,31 @@ func (g *GridLayout) Recalc(ipan IPanel) {
 		case AlignNone:
 		case AlignTop:
 		case AlignBottom:
-			dy = float32(cellHeight) - el.panel.height
+			dy = cellHeight - el.panel.height
 		case AlignCenter:
-			dy = (float32(cellHeight) - el.panel.height) / 2
+			dy = (cellHeight - el.panel.height) / 2
+		case AlignHeight:
+			el.panel.SetHeight(cellHeight)
 		default:
 			panic("Invalid vertical alignment")
 		}
 		el.panel.SetPosition(cstart+dx, rstart+dy)
 	}
 }
+
+// spanSizes enlarges equally the specified column or row sizes if the
+// specified element size is larger than the sum of the sizes and gaps
+func (g *GridLayout) spanSizes(sizes []float32, size, gap float32) {
+
+	total := gap * float32(len(sizes)-1)
+	for _, s := range sizes {
+		total += s
+	}
+	if size <= total {
+		return
+	}
+	extra := (size - total) / float32(len(sizes))
+	for i := range sizes {
+		sizes[i] += extra
+	}
+}
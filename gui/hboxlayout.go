@@ -4,16 +4,32 @@
 
 package gui
 
+// HBoxLayout arranges the visible children of a panel in a row from left
+// to right. The extra width of the panel is shared among the children with
+// an expand factor. If no children expand, the whole block of children is
+// aligned horizontally. The children are aligned vertically by their own
+// parameters or by the default vertical alignment of the layout.
 type HBoxLayout struct {
 	pan     IPanel
-	spacing float32 // horizontal spacing between the children in pixels.
-	alignH  Align   // horizontal alignment of the whole block of children
+	spacing float32            // horizontal spacing between the children in pixels.
+	alignH  Align              // horizontal alignment of the whole block of children
+	alignV  Align              // default vertical alignment of the children
+	padding BorderSizes        // space around the block of children in pixels
+	sizes   map[*Panel]boxSize // sizes of the expanded children
 }
 
 // Parameters for individual children
 type HBoxLayoutParams struct {
 	Expand float32 // item expand horizontally factor (0 - no expand)
-	AlignV Align   // item vertical alignment
+	AlignV Align   // item vertical alignment (AlignNone - use the layout alignment)
+}
+
+// boxSize keeps the natural size of an expanded child of a box layout
+// and the size assigned to it by the layout. If the child size is changed
+// elsewhere, it becomes its new natural size.
+type boxSize struct {
+	natural  float32 // size of the child before being expanded
+	assigned float32 // size assigned by the layout
 }
 
 // NewHBoxLayout creates and returns a pointer to a new horizontal box layout
@@ -22,6 +38,8 @@ func NewHBoxLayout() *HBoxLayout {
 	bl := new(HBoxLayout)
 	bl.spacing = 0
 	bl.alignH = AlignLeft
+	bl.alignV = AlignTop
+	bl.sizes = make(map[*Panel]boxSize)
 	return bl
 }
 
@@ -33,6 +51,12 @@ func (bl *HBoxLayout) SetSpacing(spacing float32) {
 	bl.Recalc(bl.pan)
 }
 
+// Spacing returns the horizontal spacing between the items in pixels
+func (bl *HBoxLayout) Spacing() float32 {
+
+	return bl.spacing
+}
+
 // SetAlignH sets the horizontal alignment of the whole group of items
 // inside the parent panel and updates the layout if possible.
 // This only has any effect if there are no expanded items.
@@ -42,126 +66,157 @@ func (bl *HBoxLayout) SetAlignH(align Align) {
 	bl.Recalc(bl.pan)
 }
 
+// AlignH returns the horizontal alignment of the whole group of items
+func (bl *HBoxLayout) AlignH() Align {
+
+	return bl.alignH
+}
+
+// SetAlignV sets the vertical alignment of the items without
+// layout parameters or with AlignV set to AlignNone and updates the
+// layout if possible. The default is AlignTop.
+func (bl *HBoxLayout) SetAlignV(align Align) {
+
+	bl.alignV = align
+	bl.Recalc(bl.pan)
+}
+
+// AlignV returns the default vertical alignment of the items
+func (bl *HBoxLayout) AlignV() Align {
+
+	return bl.alignV
+}
+
+// SetPadding sets the space in pixels between the borders of the
+// parent panel content area and the items and updates the layout if possible
+func (bl *HBoxLayout) SetPadding(top, right, bottom, left float32) {
+
+	bl.padding = BorderSizes{top, right, bottom, left}
+	bl.Recalc(bl.pan)
+}
+
+// Padding returns the space in pixels around the items
+func (bl *HBoxLayout) Padding() BorderSizes {
+
+	return bl.padding
+}
+
 // Recalc recalculates and sets the position and sizes of all children
 func (bl *HBoxLayout) Recalc(ipan IPanel) {
 
+	type element struct {
+		panel  *Panel
+		params HBoxLayoutParams
+		width  float32
+	}
+
 	// Saves the received panel
 	bl.pan = ipan
 	if bl.pan == nil {
 		return
 	}
 	parent := ipan.GetPanel()
-	if len(parent.Children()) == 0 {
-		return
-	}
 
-	// Calculates the total width, expanded width, fixed width and
-	// the sum of the expand factor for all items.
+	// Gets the visible items with their layout parameters and natural
+	// widths and calculates the total width, fixed width and the sum
+	// of the expand factor for all items.
 	var twidth float32 = 0
-	var ewidth float32 = 0
 	var fwidth float32 = 0
 	var texpand float32 = 0
-	ecount := 0
-	paramsDef := HBoxLayoutParams{Expand: 0, AlignV: AlignTop}
-	for pos, obj := range parent.Children() {
+	items := []element{}
+	for _, obj := range parent.Children() {
 		pan := obj.(IPanel).GetPanel()
+		if !pan.Visible() {
+			continue
+		}
 		// Get item layout parameters or use default
-		params := paramsDef
+		params := HBoxLayoutParams{}
 		if pan.layoutParams != nil {
 			params = *pan.layoutParams.(*HBoxLayoutParams)
 		}
-		// Calculate total width
-		twidth += pan.Width()
-		if pos > 0 {
-			twidth += bl.spacing
+		if params.AlignV == AlignNone {
+			params.AlignV = bl.alignV
 		}
-		// Calculate width of expanded items
+		width := pan.Width()
 		if params.Expand > 0 {
 			texpand += params.Expand
-			ewidth += pan.Width()
-			if pos > 0 {
-				ewidth += bl.spacing
+			if size, ok := bl.sizes[pan]; ok && size.assigned == width {
+				width = size.natural
 			}
-			ecount++
-			// Calculate width of fixed items
 		} else {
-			fwidth += pan.Width()
-			if pos > 0 {
-				fwidth += bl.spacing
-			}
+			fwidth += width
 		}
+		twidth += width
+		items = append(items, element{pan, params, width})
 	}
+	if len(items) == 0 {
+		return
+	}
+	spacings := bl.spacing * float32(len(items)-1)
+	twidth += spacings
+
+	// Available area for the items
+	width := parent.ContentWidth() - bl.padding.Left - bl.padding.Right
+	height := parent.ContentHeight() - bl.padding.Top - bl.padding.Bottom
 
 	// If there is at least on expanded item, all free space will be occupied
 	spaceMiddle := bl.spacing
 	var posX float32 = 0
+	sizes := make(map[*Panel]boxSize)
 	if texpand > 0 {
-		// If there is free space, distribute space between expanded items
-		totalSpace := parent.ContentWidth() - twidth
-		if totalSpace > 0 {
-			for _, obj := range parent.Children() {
-				pan := obj.(IPanel).GetPanel()
-				// Get item layout parameters or use default
-				params := paramsDef
-				if pan.layoutParams != nil {
-					params = *pan.layoutParams.(*HBoxLayoutParams)
-				}
-				if params.Expand > 0 {
-					iwidth := totalSpace * params.Expand / texpand
-					pan.SetWidth(pan.Width() + iwidth)
-				}
+		totalSpace := width - twidth
+		for i := range items {
+			el := &items[i]
+			if el.params.Expand <= 0 {
+				continue
 			}
-			// No free space: distribute expanded items widths
-		} else {
-			for _, obj := range parent.Children() {
-				pan := obj.(IPanel).GetPanel()
-				// Get item layout parameters or use default
-				params := paramsDef
-				if pan.layoutParams != nil {
-					params = *pan.layoutParams.(*HBoxLayoutParams)
-				}
-				if params.Expand > 0 {
-					spacing := bl.spacing * (float32(ecount) - 1)
-					iwidth := (parent.ContentWidth() - spacing - fwidth - bl.spacing) * params.Expand / texpand
-					pan.SetWidth(iwidth)
+			// If there is free space, distribute space between expanded items
+			// otherwise distribute the width not used by the fixed items
+			var iwidth float32
+			if totalSpace > 0 {
+				iwidth = el.width + totalSpace*el.params.Expand/texpand
+			} else {
+				iwidth = (width - spacings - fwidth) * el.params.Expand / texpand
+				if iwidth < 0 {
+					iwidth = 0
 				}
 			}
+			if el.panel.Width() != iwidth {
+				el.panel.SetWidth(iwidth)
+			}
+			sizes[el.panel] = boxSize{el.width, el.panel.Width()}
 		}
 		// No expanded items: checks block horizontal alignment
 	} else {
 		// Calculates initial x position which depends
 		// on the current horizontal alignment.
 		switch bl.alignH {
-		case AlignLeft:
+		case AlignNone, AlignLeft:
 			posX = 0
 		case AlignCenter:
-			posX = (parent.ContentWidth() - twidth) / 2
+			posX = (width - twidth) / 2
 		case AlignRight:
-			posX = parent.ContentWidth() - twidth
+			posX = width - twidth
 		case AlignWidth:
-			space := parent.ContentWidth() - twidth + bl.spacing*float32(len(parent.Children())-1)
+			space := width - twidth + spacings
 			if space < 0 {
-				space = bl.spacing * float32(len(parent.Children())-1)
+				space = spacings
 			}
-			spaceMiddle = space / float32(len(parent.Children())+1)
+			spaceMiddle = space / float32(len(items)+1)
 			posX = spaceMiddle
 		default:
 			log.Fatal("HBoxLayout: invalid global horizontal alignment")
 		}
 	}
+	bl.sizes = sizes
 
 	// Calculates the Y position of each item considering its vertical alignment
 	var posY float32
-	height := parent.ContentHeight()
-	for pos, obj := range parent.Children() {
-		pan := obj.(IPanel).GetPanel()
-		// Get item layout parameters or use default
-		params := paramsDef
-		if pan.layoutParams != nil {
-			params = *pan.layoutParams.(*HBoxLayoutParams)
-		}
+	posX += bl.padding.Left
+	for _, el := range items {
+		pan := el.panel
 		cheight := pan.Height()
-		switch params.AlignV {
+		switch el.params.AlignV {
 		case AlignTop:
 			posY = 0
 		case AlignCenter:
@@ -170,16 +225,14 @@ func (bl *HBoxLayout) Recalc(ipan IPanel) {
 			posY = height - cheight
 		case AlignHeight:
 			posY = 0
-			pan.SetHeight(height)
+			if cheight != height {
+				pan.SetHeight(height)
+			}
 		default:
 			log.Fatal("HBoxLayout: invalid item vertical alignment")
 		}
-		// Sets the child position
-		pan.SetPosition(posX, posY)
-		// Calculates next position
-		posX += pan.Width()
-		if pos < len(parent.Children())-1 {
-			posX += spaceMiddle
-		}
+		// Sets the child position and calculates next position
+		pan.SetPosition(posX, bl.padding.Top+posY)
+		posX += pan.Width() + spaceMiddle
 	}
 }
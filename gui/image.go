@@ -5,6 +5,7 @@
 package gui
 
 import (
+	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/texture"
 	"image"
 )
@@ -14,6 +15,15 @@ type Image struct {
 	tex   *texture.Texture2D // pointer to image texture
 }
 
+// ImageFit specifies how an image is fitted inside a target box
+type ImageFit int
+
+const (
+	ImageFitNone    = ImageFit(iota) // Image keeps its native size
+	ImageFitContain                  // Image is scaled to fit inside the box keeping its aspect ratio
+	ImageFitFill                     // Image is scaled to fill the box ignoring its aspect ratio
+)
+
 // NewImage creates and returns an image panel with the image
 // from the specified image used as a texture.
 // Initially the size of the panel content area is the exact size of the image.
@@ -57,6 +67,25 @@ func (i *Image) SetTexture(tex *texture.Texture2D) *texture.Texture2D {
 	return prevtex
 }
 
+// Fit sets the size of this image content area to fit inside a box
+// with the specified dimensions using the specified fit mode.
+// For ImageFitNone the native size of the image texture is used.
+func (i *Image) Fit(width, height float32, fit ImageFit) {
+
+	tw := float32(i.tex.Width())
+	th := float32(i.tex.Height())
+	if fit == ImageFitNone || width <= 0 || height <= 0 || tw == 0 || th == 0 {
+		i.Panel.SetContentSize(tw, th)
+		return
+	}
+	if fit == ImageFitFill {
+		i.Panel.SetContentSize(width, height)
+		return
+	}
+	scale := math32.Min(width/tw, height/th)
+	i.Panel.SetContentSize(tw*scale, th*scale)
+}
+
 //func (i *Image) Clone() *Image {
 //
 //	return NewImageFromTex(i.tex.Clone())
@@ -11,14 +11,19 @@ import (
 )
 
 type ImageButton struct {
-	*Panel                            // Embedded Panel
-	label       *Label                // Label panel
-	iconLabel   bool                  // True if icon
-	image       *Image                // pointer to button image (may be nil)
-	styles      *ImageButtonStyles    // pointer to current button styles
-	mouseOver   bool                  // true if mouse is over button
-	pressed     bool                  // true if button is pressed
-	stateImages [ButtonDisabled+1]*texture.Texture2D // array of images for each button state
+	*Panel                                           // Embedded Panel
+	label       *Label                               // Label panel
+	iconLabel   bool                                 // True if icon
+	image       *Image                               // pointer to button image (may be nil)
+	styles      *ImageButtonStyles                   // pointer to current button styles
+	mouseOver   bool                                 // true if mouse is over button
+	pressed     bool                                 // true if button is pressed
+	toggle      bool                                 // true if toggle button
+	active      bool                                 // active state of toggle button
+	fixedWidth  float32                              // fixed content width (0 = image width)
+	fixedHeight float32                              // fixed content height (0 = image height)
+	stateImages [ButtonActive + 1]*texture.Texture2D // array of images for each button state
+	focus       bool                                 // true if button has the key focus
 }
 
 type ButtonState int
@@ -29,6 +34,7 @@ const (
 	//ButtonFocus
 	ButtonPressed
 	ButtonDisabled
+	ButtonActive // active state of toggle button
 )
 
 // ImageButton style
@@ -38,6 +44,7 @@ type ImageButtonStyle struct {
 	BorderColor math32.Color4
 	BgColor     math32.Color4
 	FgColor     math32.Color
+	ImageTint   math32.Color4 // color multiplied by the image colors (zero value = no tint)
 }
 
 // All ImageButton styles
@@ -47,6 +54,7 @@ type ImageButtonStyles struct {
 	Focus    ImageButtonStyle
 	Pressed  ImageButtonStyle
 	Disabled ImageButtonStyle
+	Active   ImageButtonStyle
 }
 
 // NewImageButton creates and returns a pointer to a new ImageButton widget
@@ -79,6 +87,11 @@ func NewImageButton(normalImgPath string) (*ImageButton, error) {
 	b.Panel.Subscribe(OnCursorLeave, b.onCursor)
 	b.Panel.Subscribe(OnEnable, func(name string, ev interface{}) { b.update() })
 	b.Panel.Subscribe(OnResize, func(name string, ev interface{}) { b.recalc() })
+	b.Panel.Subscribe(OnFocus, func(name string, ev interface{}) {
+		b.focus = true
+		b.update()
+	})
+	b.Panel.SetFocusable(true)
 
 	b.recalc()
 	b.update()
@@ -117,10 +130,10 @@ func (b *ImageButton) SetIcon(icode int) {
 	b.iconLabel = true
 	if b.label == nil {
 		// Create icon
-		b.label = NewIconLabel(string(icode))
+		b.label = NewIconLabel(string(rune(icode)))
 		b.Panel.Add(b.label)
 	} else {
-		b.label.SetText(string(icode))
+		b.label.SetText(string(rune(icode)))
 	}
 	b.recalc()
 }
@@ -134,8 +147,9 @@ func (b *ImageButton) SetFontSize(size float64) {
 	}
 }
 
-// SetImage sets the button left image from the specified filename
-// If there is currently a selected icon, it is removed
+// SetImage sets the button image for the specified state from the specified filename.
+// States without an image use the ButtonActive image if the toggle button is
+// active or the ButtonNormal image otherwise.
 func (b *ImageButton) SetImage(state ButtonState, imgfile string) error {
 
 	tex, err := texture.NewTexture2DFromImage(imgfile)
@@ -152,6 +166,58 @@ func (b *ImageButton) SetImage(state ButtonState, imgfile string) error {
 	return nil
 }
 
+// SetFixedSize sets a fixed size for the button content area and the
+// image is scaled to fit inside it keeping its aspect ratio.
+// If zero sizes are specified the content area has the size of the image.
+func (b *ImageButton) SetFixedSize(width, height float32) {
+
+	if width <= 0 || height <= 0 {
+		width = 0
+		height = 0
+	}
+	b.fixedWidth = width
+	b.fixedHeight = height
+	b.update()
+}
+
+// SetToggle sets if this button is a toggle button which changes
+// its active state when clicked.
+func (b *ImageButton) SetToggle(toggle bool) {
+
+	b.toggle = toggle
+	if !toggle {
+		b.SetActive(false)
+	}
+}
+
+// Toggle returns if this button is a toggle button
+func (b *ImageButton) Toggle() bool {
+
+	return b.toggle
+}
+
+// SetActive sets the active state of this toggle button and
+// dispatches OnChange with the new state if it changed.
+func (b *ImageButton) SetActive(active bool) {
+
+	if active == b.active {
+		return
+	}
+	if active && !b.toggle {
+		log.Warn("Active state is only valid for toggle ImageButton")
+		return
+	}
+	b.active = active
+	b.update()
+	b.Dispatch(OnChange, active)
+}
+
+// Active returns the active state of this toggle button
+func (b *ImageButton) Active() bool {
+
+	return b.active
+}
+
 // Dispose releases resources used by this widget
 func (b *ImageButton) Dispose() {
 	b.Panel.Dispose()
@@ -191,8 +257,7 @@ func (b *ImageButton) onMouse(evname string, ev interface{}) {
 	case OnMouseDown:
 		b.root.SetKeyFocus(b)
 		b.pressed = true
-		b.update()
-		b.Dispatch(OnClick, nil)
+		b.click()
 	case OnMouseUp:
 		b.pressed = false
 		b.update()
@@ -202,14 +267,21 @@ func (b *ImageButton) onMouse(evname string, ev interface{}) {
 	b.root.StopPropagation(StopAll)
 }
 
+// LostKeyFocus satisfies the IPanel interface and is called by gui root
+// container when the panel loses the key focus
+func (b *ImageButton) LostKeyFocus() {
+
+	b.focus = false
+	b.update()
+}
+
 // onKey processes subscribed key events
 func (b *ImageButton) onKey(evname string, ev interface{}) {
 
 	kev := ev.(*window.KeyEvent)
 	if evname == OnKeyDown && kev.Keycode == window.KeyEnter {
 		b.pressed = true
-		b.update()
-		b.Dispatch(OnClick, nil)
+		b.click()
 		b.root.StopPropagation(Stop3D)
 		return
 	}
@@ -222,32 +294,57 @@ func (b *ImageButton) onKey(evname string, ev interface{}) {
 	return
 }
 
+// click toggles the active state of a toggle button and dispatches OnClick
+func (b *ImageButton) click() {
+
+	if b.toggle {
+		b.SetActive(!b.active)
+	}
+	b.update()
+	b.Dispatch(OnClick, nil)
+}
+
 // update updates the button visual state
 func (b *ImageButton) update() {
 
+	state := ButtonNormal
+	style := &b.styles.Normal
 	if !b.Enabled() {
-		if b.stateImages[ButtonDisabled] != nil {
-			b.image.SetTexture(b.stateImages[ButtonDisabled])
-		}
-		b.applyStyle(&b.styles.Disabled)
-		return
+		state = ButtonDisabled
+		style = &b.styles.Disabled
+	} else if b.pressed {
+		state = ButtonPressed
+		style = &b.styles.Pressed
+	} else if b.active {
+		state = ButtonActive
+		style = &b.styles.Active
+	} else if b.mouseOver {
+		state = ButtonOver
+		style = &b.styles.Over
+	} else if b.focus {
+		style = &b.styles.Focus
 	}
-	if b.pressed {
-		if b.stateImages[ButtonPressed] != nil {
-			b.image.SetTexture(b.stateImages[ButtonPressed])
-		}
-		b.applyStyle(&b.styles.Pressed)
-		return
+
+	// Sets the image of the current state (if any)
+	tex := b.stateImages[state]
+	if tex == nil && b.active {
+		tex = b.stateImages[ButtonActive]
 	}
-	if b.mouseOver {
-		if b.stateImages[ButtonOver] != nil {
-			b.image.SetTexture(b.stateImages[ButtonOver])
-		}
-		b.applyStyle(&b.styles.Over)
-		return
+	if tex == nil {
+		tex = b.stateImages[ButtonNormal]
+	}
+	if tex != b.image.tex {
+		b.image.SetTexture(tex)
+	}
+
+	// Sets the content size from the image or the fixed size
+	if b.fixedWidth > 0 {
+		b.image.Fit(b.fixedWidth, b.fixedHeight, ImageFitContain)
+		b.Panel.SetContentSize(b.fixedWidth, b.fixedHeight)
+	} else {
+		b.Panel.SetContentSize(b.image.Width(), b.image.Height())
 	}
-	b.image.SetTexture(b.stateImages[ButtonNormal])
-	b.applyStyle(&b.styles.Normal)
+	b.applyStyle(style)
 }
 
 // applyStyle applies the specified button style
@@ -260,16 +357,24 @@ func (b *ImageButton) applyStyle(bs *ImageButtonStyle) {
 	if b.label != nil {
 		b.label.SetColor(&bs.FgColor)
 	}
+	tint := bs.ImageTint
+	if tint == (math32.Color4{}) {
+		tint = math32.Color4{1, 1, 1, 1}
+	}
+	b.image.SetTextureTint(&tint)
 }
 
 // recalc recalculates all dimensions and position from inside out
 func (b *ImageButton) recalc() {
 
+	width := b.Panel.ContentWidth()
+	height := b.Panel.ContentHeight()
+
+	// Centers the image inside the content area
+	b.image.SetPosition((width-b.image.Width())/2, (height-b.image.Height())/2)
+
 	// Only need to recal if there's a label preset
 	if b.label != nil {
-		width := b.Panel.ContentWidth()
-		height := b.Panel.ContentHeight()
-
 		x := (width - b.label.Width()) / 2
 		y := (height - b.label.Height()) / 2
 
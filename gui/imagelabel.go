@@ -78,11 +78,11 @@ func (il *ImageLabel) SetIcon(icode int) {
 		il.image = nil
 	}
 	if il.icon == nil {
-		il.icon = NewIconLabel(string(icode))
+		il.icon = NewIconLabel(string(rune(icode)))
 		il.icon.SetFontSize(il.label.FontSize() * 1.4)
 		il.Panel.Add(il.icon)
 	}
-	il.icon.SetText(string(icode))
+	il.icon.SetText(string(rune(icode)))
 	il.icode = icode
 	il.recalc()
 }
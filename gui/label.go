@@ -5,6 +5,8 @@
 package gui
 
 import (
+	"strings"
+
 	"github.com/g3n/engine/gls"
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/text"
@@ -21,6 +23,9 @@ type Label struct {
 	font        *text.Font
 	tex         *texture.Texture2D // Pointer to texture with drawed text
 	currentText string
+	runs        []markupRun // styled text runs if the text was set by SetMarkup
+	maxWidth    float32     // maximum content width in pixels (0 = unlimited)
+	wrap        bool        // wrap text lines at the maximum width
 }
 
 // NewLabel creates and returns a label panel with the specified text
@@ -58,6 +63,23 @@ func (l *Label) initialize(msg string, font *text.Font) {
 // SetText draws the label text using the current font
 func (l *Label) SetText(msg string) {
 
+	l.runs = nil
+	l.drawText(msg)
+}
+
+// redraw draws the current label text or markup
+func (l *Label) redraw() {
+
+	if l.runs != nil {
+		l.drawRuns()
+		return
+	}
+	l.drawText(l.currentText)
+}
+
+// drawText draws the specified plain text using the current font
+func (l *Label) drawText(msg string) {
+
 	// Do not allow empty labels
 	str := msg
 	if len(msg) == 0 {
@@ -71,12 +93,111 @@ func (l *Label) SetText(msg string) {
 	l.font.SetBgColor4(&l.bgColor)
 	l.font.SetFgColor4(&l.fgColor)
 
+	// Wraps the text lines at the maximum width
+	lines := str
+	if l.wrap && l.maxWidth > 0 {
+		lines = strings.Join(wrapText(str, int(l.maxWidth), func(s string) int {
+			w, _ := l.font.MeasureText(s)
+			return w
+		}), "\n")
+	}
+
 	// Measure text
-	width, height := l.font.MeasureText(str)
+	width, height := l.font.MeasureText(lines)
+	if l.maxWidth > 0 && width > int(l.maxWidth) {
+		width = int(l.maxWidth)
+	}
 	// Create image canvas with the exact size of the texture
 	// and draw the text.
 	canvas := text.NewCanvas(width, height, &l.bgColor)
-	canvas.DrawText(0, 0, str, l.font)
+	canvas.DrawText(0, 0, lines, l.font)
+	l.setCanvas(canvas)
+	l.currentText = str
+}
+
+// SetMaxWidth sets the maximum width in pixels of the label content area.
+// If wrapping is enabled the text lines are wrapped at this width,
+// otherwise the text is clipped. Zero removes the limit.
+func (l *Label) SetMaxWidth(width float32) *Label {
+
+	if width < 0 {
+		width = 0
+	}
+	l.maxWidth = width
+	l.redraw()
+	return l
+}
+
+// MaxWidth returns the maximum width in pixels of the label content area
+func (l *Label) MaxWidth() float32 {
+
+	return l.maxWidth
+}
+
+// SetWrap sets if the text lines are wrapped at word boundaries to fit
+// inside the maximum width. The label height grows with the number of lines.
+// Words larger than the maximum width are broken.
+func (l *Label) SetWrap(wrap bool) *Label {
+
+	l.wrap = wrap
+	l.redraw()
+	return l
+}
+
+// Wrap returns if the text lines are wrapped at the maximum width
+func (l *Label) Wrap() bool {
+
+	return l.wrap
+}
+
+// wrapText splits the specified text in lines not wider than the specified
+// maximum width using the specified function to measure the width of a text.
+// Lines are broken at spaces and words larger than the width are broken
+// at the last character which fits.
+func wrapText(str string, maxWidth int, measure func(string) int) []string {
+
+	lines := []string{}
+	for _, line := range strings.Split(str, "\n") {
+		curr := ""
+		for i, word := range strings.Split(line, " ") {
+			cand := word
+			if i > 0 {
+				cand = curr + " " + word
+			}
+			if measure(cand) <= maxWidth {
+				curr = cand
+				continue
+			}
+			if i > 0 && curr != "" {
+				lines = append(lines, curr)
+			}
+			curr = word
+			for len(curr) > 0 && measure(curr) > maxWidth {
+				n := fitRunes(curr, maxWidth, measure)
+				lines = append(lines, string([]rune(curr)[:n]))
+				curr = string([]rune(curr)[n:])
+			}
+		}
+		lines = append(lines, curr)
+	}
+	return lines
+}
+
+// fitRunes returns the number of runes of the start of the specified text
+// which fits inside the specified width (at least 1)
+func fitRunes(str string, maxWidth int, measure func(string) int) int {
+
+	runes := []rune(str)
+	n := 1
+	for n < len(runes) && measure(string(runes[:n+1])) <= maxWidth {
+		n++
+	}
+	return n
+}
+
+// setCanvas sets the label texture from the specified canvas image
+// and the label content size from the canvas size
+func (l *Label) setCanvas(canvas *text.Canvas) {
 
 	// Creates texture if if doesnt exist.
 	if l.tex == nil {
@@ -90,11 +211,33 @@ func (l *Label) SetText(msg string) {
 	}
 
 	// Updates label panel dimensions
-	l.Panel.SetContentSize(float32(width), float32(height))
-	l.currentText = str
+	size := canvas.RGBA.Bounds().Size()
+	width := l.Panel.ContentWidth()
+	height := l.Panel.ContentHeight()
+	l.Panel.SetContentSize(float32(size.X), float32(size.Y))
+
+	// If the label size changed, recalculates the layout of its parent
+	if width == l.Panel.ContentWidth() && height == l.Panel.ContentHeight() {
+		return
+	}
+	par, ok := l.Parent().(*Panel)
+	if ok && par.layout != nil {
+		par.layout.Recalc(par)
+	}
+}
+
+// measureText returns the width and height in pixels of the specified
+// text drawn with this label current font properties
+func (l *Label) measureText(str string) (int, int) {
+
+	l.font.SetSize(l.fontSize)
+	l.font.SetDPI(l.fontDPI)
+	l.font.SetLineSpacing(l.lineSpacing)
+	return l.font.MeasureText(str)
 }
 
-// Text returns the current label text
+// Text returns the current label text.
+// For text set by SetMarkup the markup tags are not included.
 func (l *Label) Text() string {
 
 	return l.currentText
@@ -105,7 +248,7 @@ func (l *Label) Text() string {
 func (l *Label) SetColor(color *math32.Color) *Label {
 
 	l.fgColor.FromColor(color, 1.0)
-	l.SetText(l.currentText)
+	l.redraw()
 	return l
 }
 
@@ -113,7 +256,7 @@ func (l *Label) SetColor(color *math32.Color) *Label {
 func (l *Label) SetColor4(color4 *math32.Color4) *Label {
 
 	l.fgColor = *color4
-	l.SetText(l.currentText)
+	l.redraw()
 	return l
 }
 
@@ -129,7 +272,7 @@ func (l *Label) SetBgColor(color *math32.Color) *Label {
 
 	l.bgColor.FromColor(color, 1.0)
 	l.Panel.SetColor4(&l.bgColor)
-	l.SetText(l.currentText)
+	l.redraw()
 	return l
 }
 
@@ -138,7 +281,7 @@ func (l *Label) SetBgColor4(color *math32.Color4) *Label {
 
 	l.bgColor = *color
 	l.Panel.SetColor4(&l.bgColor)
-	l.SetText(l.currentText)
+	l.redraw()
 	return l
 }
 
@@ -152,7 +295,7 @@ func (l *Label) BgColor() math32.Color4 {
 func (l *Label) SetFontSize(size float64) *Label {
 
 	l.fontSize = size
-	l.SetText(l.currentText)
+	l.redraw()
 	return l
 }
 
@@ -88,6 +88,7 @@ func (li *List) initialize(vert bool, width, height float32) {
 	li.Scroller.Subscribe(OnMouseDown, li.onMouseEvent)
 	li.Scroller.Subscribe(OnKeyDown, li.onKeyEvent)
 	li.Scroller.Subscribe(OnKeyRepeat, li.onKeyEvent)
+	li.Scroller.SetFocusable(true)
 
 	if vert {
 		li.keyNext = window.KeyDown
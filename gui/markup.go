@@ -0,0 +1,362 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/text"
+)
+
+// markupRun is a run of text of a label markup with the same style
+type markupRun struct {
+	text   string         // run text (without line breaks)
+	bold   bool           // bold font
+	italic bool           // italic (slanted) text
+	color  *math32.Color4 // text color (nil = label color)
+	size   float64        // font size (0 = label font size)
+	brk    bool           // line break after this run
+}
+
+// markupSlant is the horizontal displacement per pixel of height of italic text
+const markupSlant = 0.2
+
+// SetMarkup sets the label text from the specified markup and draws it.
+// The markup is text with the optional tags <b>bold</b>, <i>italic</i>,
+// <color=#ff0000>red</color> and <size=20>larger</size>.
+// Colors can also be specified as #rgb, #rrggbbaa or an HTML color name.
+// Tags can be nested. The entities &lt; &gt; and &amp; can be used for
+// the characters '<', '>' and '&'. Invalid tags are drawn as text.
+// The Text method returns the text without the markup tags.
+func (l *Label) SetMarkup(markup string) {
+
+	l.runs, l.currentText = parseMarkup(markup)
+	l.drawRuns()
+}
+
+// parseMarkup parses the specified markup returning the styled
+// text runs and the text without markup tags
+func parseMarkup(markup string) ([]markupRun, string) {
+
+	runs := []markupRun{}
+	plain := ""
+	stack := []markupRun{{}}
+	var buf []rune
+
+	// Appends the current text buffer as a run with the current style
+	flush := func(brk bool) {
+		run := stack[len(stack)-1]
+		run.text = string(buf)
+		run.brk = brk
+		if run.text != "" || brk {
+			runs = append(runs, run)
+		}
+		plain += run.text
+		if brk {
+			plain += "\n"
+		}
+		buf = buf[:0]
+	}
+
+	rs := []rune(markup)
+	for i := 0; i < len(rs); i++ {
+		c := rs[i]
+		switch c {
+		case '\n':
+			flush(true)
+			continue
+		case '&':
+			rest := string(rs[i:])
+			found := false
+			for ent, ch := range map[string]rune{"&lt;": '<', "&gt;": '>', "&amp;": '&'} {
+				if strings.HasPrefix(rest, ent) {
+					buf = append(buf, ch)
+					i += len(ent) - 1
+					found = true
+					break
+				}
+			}
+			if !found {
+				buf = append(buf, c)
+			}
+			continue
+		case '<':
+			end := -1
+			for j := i + 1; j < len(rs); j++ {
+				if rs[j] == '>' {
+					end = j
+					break
+				}
+			}
+			if end < 0 {
+				break
+			}
+			tag := string(rs[i+1 : end])
+			// Closing tag: pops the style if it matches the current one
+			if strings.HasPrefix(tag, "/") {
+				if len(stack) > 1 && markupTagName(tag[1:]) == stack[len(stack)-1].text {
+					flush(false)
+					stack = stack[:len(stack)-1]
+					i = end
+					continue
+				}
+				break
+			}
+			// Opening tag: pushes the new style
+			style, ok := parseMarkupTag(stack[len(stack)-1], tag)
+			if !ok {
+				break
+			}
+			flush(false)
+			stack = append(stack, style)
+			i = end
+			continue
+		}
+		buf = append(buf, c)
+	}
+	flush(false)
+	return runs, plain
+}
+
+// markupTagName returns the name of the specified tag without its value
+func markupTagName(tag string) string {
+
+	if pos := strings.Index(tag, "="); pos >= 0 {
+		return strings.TrimSpace(tag[:pos])
+	}
+	return strings.TrimSpace(tag)
+}
+
+// parseMarkupTag returns the style of the specified opening tag
+// derived from the specified parent style.
+// The name of the tag is saved in the text field of the style
+// to check the closing tag.
+func parseMarkupTag(parent markupRun, tag string) (markupRun, bool) {
+
+	style := parent
+	style.text = markupTagName(tag)
+	value := ""
+	if pos := strings.Index(tag, "="); pos >= 0 {
+		value = strings.Trim(strings.TrimSpace(tag[pos+1:]), "\"'")
+	}
+	switch style.text {
+	case "b":
+		style.bold = true
+	case "i":
+		style.italic = true
+	case "color":
+		color, ok := parseMarkupColor(value)
+		if !ok {
+			return style, false
+		}
+		style.color = color
+	case "size":
+		size, err := strconv.ParseFloat(value, 64)
+		if err != nil || size <= 0 {
+			return style, false
+		}
+		style.size = size
+	default:
+		return style, false
+	}
+	return style, true
+}
+
+// parseMarkupColor parses a color in the formats #rgb, #rrggbb, #rrggbbaa
+// or an HTML color name
+func parseMarkupColor(value string) (*math32.Color4, bool) {
+
+	if value == "" {
+		return nil, false
+	}
+	color := math32.NewColor4(0, 0, 0, 1)
+	if !strings.HasPrefix(value, "#") {
+		c, ok := math32.IsColorName(strings.ToLower(value))
+		if !ok {
+			return nil, false
+		}
+		color.FromColor(&c, 1)
+		return color, true
+	}
+	hex := value[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 && len(hex) != 8 {
+		return nil, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	if len(hex) == 8 {
+		color.A = float32(v&255) / 255
+		v >>= 8
+	}
+	color.SetHex(uint(v))
+	return color, true
+}
+
+// runFont sets the properties of the font used to draw the specified
+// run and returns it
+func (l *Label) runFont(run *markupRun) *text.Font {
+
+	f := l.font
+	if run.bold && l.font == StyleDefault.Font && StyleDefault.FontBold != nil {
+		f = StyleDefault.FontBold
+	}
+	size := l.fontSize
+	if run.size > 0 {
+		size = run.size
+	}
+	f.SetSize(size)
+	f.SetDPI(l.fontDPI)
+	f.SetLineSpacing(l.lineSpacing)
+	if run.color != nil {
+		f.SetFgColor4(run.color)
+	} else {
+		f.SetFgColor4(&l.fgColor)
+	}
+	return f
+}
+
+// drawRuns draws the label styled text runs
+func (l *Label) drawRuns() {
+
+	type piece struct {
+		run    *markupRun
+		text   string
+		x      int // horizontal position in pixels
+		width  int // width in pixels
+		height int // height in pixels
+		ascent int // ascent in pixels
+	}
+	type line struct {
+		pieces []piece
+		width  int
+		height int
+		ascent int
+	}
+
+	// Measures the width of a text of a run
+	measure := func(run *markupRun, str string) (int, int) {
+		f := l.runFont(run)
+		w, h := f.MeasureText(str)
+		if run.italic {
+			w += int(math.Ceil(float64(h) * markupSlant))
+		}
+		return w, h
+	}
+
+	// Appends a piece of text of a run to the current line
+	lines := []line{{}}
+	add := func(run *markupRun, str string) {
+		curr := &lines[len(lines)-1]
+		w, h := measure(run, str)
+		ascent := int(math.Ceil(l.runFont(run).Size() * l.fontDPI / 72))
+		curr.pieces = append(curr.pieces, piece{run, str, curr.width, w, h, ascent})
+		curr.width += w
+		if h > curr.height {
+			curr.height = h
+		}
+		if ascent > curr.ascent {
+			curr.ascent = ascent
+		}
+	}
+
+	// Splits the runs in lines wrapping the words at the maximum width if requested
+	wrap := l.wrap && l.maxWidth > 0
+	maxWidth := int(l.maxWidth)
+	for i := range l.runs {
+		run := &l.runs[i]
+		if run.text != "" && !wrap {
+			add(run, run.text)
+		} else if run.text != "" {
+			for k, word := range strings.Split(run.text, " ") {
+				if k > 0 {
+					word = " " + word
+				}
+				curr := &lines[len(lines)-1]
+				w, _ := measure(run, word)
+				if curr.width+w <= maxWidth {
+					add(run, word)
+					continue
+				}
+				// Starts a new line without the leading space
+				if len(curr.pieces) > 0 {
+					lines = append(lines, line{})
+				}
+				word = strings.TrimPrefix(word, " ")
+				// Breaks words larger than the maximum width
+				for len(word) > 0 {
+					if w, _ = measure(run, word); w <= maxWidth {
+						add(run, word)
+						break
+					}
+					n := fitRunes(word, maxWidth, func(str string) int {
+						w, _ := measure(run, str)
+						return w
+					})
+					add(run, string([]rune(word)[:n]))
+					lines = append(lines, line{})
+					word = string([]rune(word)[n:])
+				}
+			}
+		}
+		if run.brk {
+			lines = append(lines, line{})
+		}
+	}
+
+	// Empty lines have the height of the label font
+	width := 0
+	height := 0
+	for i := range lines {
+		if lines[i].height == 0 {
+			_, lines[i].height = l.measureText(" ")
+			lines[i].ascent = int(math.Ceil(l.fontSize * l.fontDPI / 72))
+		}
+		if lines[i].width > width {
+			width = lines[i].width
+		}
+		height += lines[i].height
+	}
+	if width == 0 {
+		width, _ = l.measureText(" ")
+	}
+	if l.maxWidth > 0 && width > maxWidth {
+		width = maxWidth
+	}
+
+	// Draws the pieces of each line aligned by their base lines
+	canvas := text.NewCanvas(width, height, &l.bgColor)
+	py := 0
+	for _, ln := range lines {
+		for _, p := range ln.pieces {
+			f := l.runFont(p.run)
+			y := py + ln.ascent - p.ascent
+			if !p.run.italic {
+				canvas.DrawText(p.x, y, p.text, f)
+				continue
+			}
+			// Italic text is drawn in a separate canvas which is
+			// copied row by row with increasing displacement to the top
+			tmp := text.NewCanvas(p.width, p.height, &math32.Color4{0, 0, 0, 0})
+			tmp.DrawText(0, 0, p.text, f)
+			for row := 0; row < p.height; row++ {
+				dx := int(float64(p.height-row) * markupSlant)
+				dst := image.Rect(p.x+dx, y+row, p.x+dx+p.width, y+row+1)
+				draw.Draw(canvas.RGBA, dst, tmp.RGBA, image.Pt(0, row), draw.Over)
+			}
+		}
+		py += ln.height
+	}
+	l.setCanvas(canvas)
+}
@@ -0,0 +1,39 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestParseMarkupColor(t *testing.T) {
+
+	cases := []struct {
+		value string
+		color math32.Color4
+		ok    bool
+	}{
+		{"#ff0000", math32.Color4{1, 0, 0, 1}, true},
+		{"#0f0", math32.Color4{0, 1, 0, 1}, true},
+		{"#0000ff80", math32.Color4{0, 0, 1, 128.0 / 255}, true},
+		{"Blue", math32.Color4{0, 0, 1, 1}, true},
+		{"black", math32.Color4{0, 0, 0, 1}, true},
+		{"notacolor", math32.Color4{}, false},
+		{"#12345", math32.Color4{}, false},
+		{"", math32.Color4{}, false},
+	}
+	for _, c := range cases {
+		color, ok := parseMarkupColor(c.value)
+		if ok != c.ok {
+			t.Errorf("parseMarkupColor(%q) ok = %v, want %v", c.value, ok, c.ok)
+			continue
+		}
+		if ok && *color != c.color {
+			t.Errorf("parseMarkupColor(%q) = %v, want %v", c.value, *color, c.color)
+		}
+	}
+}
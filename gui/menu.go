@@ -9,15 +9,20 @@ import (
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/window"
 	"time"
+	"unicode"
 )
 
 type Menu struct {
-	Panel                // embedded panel
-	styles   *MenuStyles // pointer to current styles
-	bar      bool        // true for menu bar
-	items    []*MenuItem // menu items
-	autoOpen bool        // open sub menus when mouse over if true
-	mitem    *MenuItem   // parent menu item for sub menu
+	Panel                   // embedded panel
+	styles     *MenuStyles  // pointer to current styles
+	bar        bool         // true for menu bar
+	items      []*MenuItem  // menu items
+	autoOpen   bool         // open sub menus when mouse over if true
+	mitem      *MenuItem    // parent menu item for sub menu
+	popup      *ContextMenu // pointer to context menu if this is a popup menu
+	prevFocus  IPanel       // panel which had the key focus before the menu got it
+	altPressed bool         // Alt key pressed without other keys (menu bar)
+	subroot    *Root        // root panel subscribed for key events (menu bar)
 }
 
 // MenuBodyStyle describes the style of the menu body
@@ -50,6 +55,9 @@ type MenuItem struct {
 	menu     *Menu              // pointer to parent menu
 	licon    *Label             // optional left icon label
 	label    *Label             // optional text label (nil for separators)
+	mnemonic rune               // optional mnemonic character (upper case)
+	mpos     int                // position of the mnemonic character in the label text
+	mline    *Panel             // optional mnemonic underline
 	shortcut *Label             // optional shorcut text label
 	ricon    *Label             // optional right internal icon label for submenu
 	id       string             // optional text id
@@ -157,6 +165,14 @@ func NewMenuBar() *Menu {
 func NewMenu() *Menu {
 
 	m := new(Menu)
+	m.initialize()
+	return m
+}
+
+// initialize initializes this menu and is normally used by other
+// types which embed a menu.
+func (m *Menu) initialize() {
+
 	m.Panel.Initialize(0, 0)
 	m.styles = &StyleDefault.Menu
 	m.items = make([]*MenuItem, 0)
@@ -165,11 +181,34 @@ func NewMenu() *Menu {
 	m.Panel.Subscribe(OnKeyDown, m.onKey)
 	m.Panel.Subscribe(OnResize, m.onResize)
 	m.update()
-	return m
+}
+
+// SetRoot satisfies the IPanel interface.
+// The menu bar subscribes to the root panel key events to open
+// with the Alt or F10 keys or with the Alt+mnemonic keys and
+// unsubscribes from the previous root panel.
+func (m *Menu) SetRoot(root *Root) {
+
+	m.Panel.SetRoot(root)
+	if !m.bar || m.subroot == root {
+		return
+	}
+	if m.subroot != nil {
+		m.subroot.UnsubscribeID(OnKeyDown, m)
+		m.subroot.UnsubscribeID(OnKeyUp, m)
+	}
+	m.subroot = root
+	if root != nil {
+		root.SubscribeID(OnKeyDown, m, m.onRootKey)
+		root.SubscribeID(OnKeyUp, m, m.onRootKey)
+	}
 }
 
 // AddOption creates and adds a new menu item to this menu with the
 // specified text and returns the pointer to the created menu item.
+// A character of the text preceded by '&' is the mnemonic of the item
+// which is underlined and selects the item when its key is pressed.
+// Use "&&" to insert a literal '&'.
 func (m *Menu) AddOption(text string) *MenuItem {
 
 	mi := newMenuItem(text, m.styles.Item)
@@ -206,7 +245,7 @@ func (m *Menu) AddMenu(text string, subm *Menu) *MenuItem {
 	mi.submenu.autoOpen = true
 	mi.menu = m
 	if !m.bar {
-		mi.ricon = NewIconLabel(string(assets.PlayArrow))
+		mi.ricon = NewIconLabel(string(rune(assets.PlayArrow)))
 		mi.Panel.Add(mi.ricon)
 	}
 	mi.Panel.Add(mi.submenu)
@@ -225,11 +264,167 @@ func (m *Menu) onCursor(evname string, ev interface{}) {
 
 	switch evname {
 	case OnCursorEnter:
+		m.rootMenu().saveFocus()
 		m.root.SetKeyFocus(m)
 	}
 	m.root.StopPropagation(StopAll)
 }
 
+// onRootKey process key events subscribed from the root panel by the menu bar
+func (m *Menu) onRootKey(evname string, ev interface{}) {
+
+	if !m.Visible() || !m.Enabled() {
+		return
+	}
+	kev := ev.(*window.KeyEvent)
+	isAlt := kev.Keycode == window.KeyLeftAlt || kev.Keycode == window.KeyRightAlt
+	// Alt pressed and released alone toggles the menu bar
+	if evname == OnKeyUp {
+		if isAlt && m.altPressed {
+			m.altPressed = false
+			m.toggleBar()
+			m.root.StopPropagation(StopAll)
+		}
+		return
+	}
+	if isAlt {
+		m.altPressed = true
+		return
+	}
+	m.altPressed = false
+	// F10 toggles the menu bar
+	if kev.Keycode == window.KeyF10 && kev.Mods == 0 {
+		m.toggleBar()
+		m.root.StopPropagation(StopAll)
+		return
+	}
+	// Alt+mnemonic opens the corresponding menu bar item
+	if kev.Mods == window.ModAlt {
+		pos := m.mnemonicPos(kev.Keycode)
+		if pos < 0 {
+			return
+		}
+		m.openBar(pos, true)
+		m.root.StopPropagation(StopAll)
+	}
+}
+
+// toggleBar activates this menu bar for keyboard navigation
+// or closes it if it is already active
+func (m *Menu) toggleBar() {
+
+	if m.hasFocus() {
+		m.close()
+		return
+	}
+	m.openBar(m.nextItem(-1), false)
+}
+
+// openBar gets the key focus for this menu bar and selects the item at the
+// specified position. If open is true also opens the item sub menu.
+func (m *Menu) openBar(pos int, open bool) {
+
+	if pos < 0 || pos >= len(m.items) {
+		return
+	}
+	m.saveFocus()
+	m.root.SetKeyFocus(m)
+	mi := m.items[pos]
+	if !open || mi.submenu == nil {
+		m.setSelectedPos(pos)
+		return
+	}
+	m.autoOpen = true
+	m.setSelectedPos(pos)
+	m.root.SetKeyFocus(mi.submenu)
+	mi.submenu.setSelectedPos(mi.submenu.nextItem(-1))
+}
+
+// hasFocus returns indication if the key focus is on this menu
+// or one of its sub menus
+func (m *Menu) hasFocus() bool {
+
+	if m.root == nil {
+		return false
+	}
+	fm, ok := m.root.keyFocus.(*Menu)
+	if !ok {
+		return false
+	}
+	return fm.rootMenu() == m
+}
+
+// saveFocus saves the panel with the key focus before this root menu
+// gets the focus, so it can be restored when the menu is closed
+func (m *Menu) saveFocus() {
+
+	if m.popup != nil || m.root == nil || m.hasFocus() {
+		return
+	}
+	m.prevFocus = m.root.keyFocus
+}
+
+// restoreFocus restores the key focus to the panel which had it before
+// this root menu got the focus, if the menu still has the focus.
+func (m *Menu) restoreFocus() {
+
+	prev := m.prevFocus
+	m.prevFocus = nil
+	if !m.hasFocus() {
+		return
+	}
+	if prev != nil {
+		m.root.SetKeyFocus(prev)
+	} else {
+		m.root.SetKeyFocus(m)
+	}
+}
+
+// close closes all the open sub menus of the root menu of this menu,
+// clears the selection and restores the key focus
+func (m *Menu) close() {
+
+	rm := m.rootMenu()
+	if rm.popup != nil {
+		rm.popup.Close()
+		return
+	}
+	if rm.bar {
+		rm.autoOpen = false
+	}
+	rm.setSelectedPos(-1)
+	rm.restoreFocus()
+}
+
+// openSubmenu opens the sub menu of the specified item of this menu
+// and moves the key focus to it
+func (m *Menu) openSubmenu(mi *MenuItem) {
+
+	m.autoOpen = true
+	m.setSelectedItem(mi)
+	m.root.SetKeyFocus(mi.submenu)
+	mi.submenu.setSelectedPos(mi.submenu.nextItem(-1))
+}
+
+// mnemonicPos returns the position of the enabled menu item with the
+// mnemonic of the specified key or -1 if not found
+func (m *Menu) mnemonicPos(key window.Key) int {
+
+	text := mapKeyText[key]
+	if text == "" {
+		return -1
+	}
+	for pos, mi := range m.items {
+		if mi.mnemonic == 0 || mi.disabled {
+			continue
+		}
+		if string(mi.mnemonic) == text {
+			return pos
+		}
+	}
+	return -1
+}
+
 // onKey process subscribed key events
 func (m *Menu) onKey(evname string, ev interface{}) {
 
@@ -249,10 +444,7 @@ func (m *Menu) onKey(evname string, ev interface{}) {
 				return
 			}
 			// Sets autoOpen and selects sub menu
-			m.autoOpen = true
-			mi.update()
-			m.root.SetKeyFocus(mi.submenu)
-			mi.submenu.setSelectedPos(0)
+			m.openSubmenu(mi)
 			return
 		}
 		// Select next enabled menu item for vertical menu
@@ -281,14 +473,22 @@ func (m *Menu) onKey(evname string, ev interface{}) {
 		}
 		// If menu has parent menu item
 		if m.mitem != nil {
-			if m.mitem.menu.bar {
-				sel := m.mitem.menu.selectedPos()
-				prev := m.mitem.menu.prevItem(sel)
-				m.mitem.menu.setSelectedPos(prev)
+			pm := m.mitem.menu
+			if pm.bar {
+				// Opens the sub menu of the previous menu bar item
+				sel := pm.selectedPos()
+				prev := pm.prevItem(sel)
+				pm.setSelectedPos(prev)
+				m.root.SetKeyFocus(pm)
+				if pm.items[prev].submenu != nil {
+					pm.openSubmenu(pm.items[prev])
+				}
 			} else {
-				m.mitem.menu.setSelectedItem(m.mitem)
+				// Closes this sub menu
+				pm.setSelectedItem(m.mitem)
+				m.SetVisible(false)
+				m.root.SetKeyFocus(pm)
 			}
-			m.root.SetKeyFocus(m.mitem.menu)
 			return
 		}
 
@@ -306,23 +506,34 @@ func (m *Menu) onKey(evname string, ev interface{}) {
 		}
 		// Enter into sub menu
 		if mi.submenu != nil {
-			m.root.SetKeyFocus(mi.submenu)
-			mi.submenu.setSelectedPos(0)
+			m.openSubmenu(mi)
 			return
 		}
-		// If parent menu of this menu item is bar menu
+		// If parent menu of this menu item is bar menu,
+		// opens the sub menu of the next menu bar item
 		if m.mitem != nil && m.mitem.menu.bar {
-			sel := m.mitem.menu.selectedPos()
-			next := m.mitem.menu.nextItem(sel)
-			m.mitem.menu.setSelectedPos(next)
-			m.root.SetKeyFocus(m.mitem.menu)
+			pm := m.mitem.menu
+			sel := pm.selectedPos()
+			next := pm.nextItem(sel)
+			pm.setSelectedPos(next)
+			m.root.SetKeyFocus(pm)
+			if pm.items[next].submenu != nil {
+				pm.openSubmenu(pm.items[next])
+			}
 		}
-	// Enter -> Select menu option
-	case window.KeyEnter:
+	// Escape -> Closes all the menus of this menu chain
+	case window.KeyEscape:
+		m.close()
+	// Enter -> Select menu option or opens sub menu
+	case window.KeyEnter, window.KeyKPEnter:
 		if sel < 0 {
 			return
 		}
 		mi := m.items[sel]
+		if mi.submenu != nil {
+			m.openSubmenu(mi)
+			return
+		}
 		mi.activate()
 	// Check for menu items shortcuts
 	default:
@@ -334,6 +545,17 @@ func (m *Menu) onKey(evname string, ev interface{}) {
 			root = mi.rootMenu()
 		}
 		found := root.checkKey(kev)
+		// Checks for mnemonics of this menu items
+		if found == nil && kev.Mods == 0 {
+			pos := m.mnemonicPos(kev.Keycode)
+			if pos >= 0 {
+				found = m.items[pos]
+				if found.submenu != nil {
+					m.openSubmenu(found)
+					return
+				}
+			}
+		}
 		if found == nil {
 			return
 		}
@@ -348,6 +570,31 @@ func (m *Menu) onKey(evname string, ev interface{}) {
 	}
 }
 
+// rootMenu returns the root menu of this menu
+func (m *Menu) rootMenu() *Menu {
+
+	root := m
+	for root.mitem != nil {
+		root = root.mitem.menu
+	}
+	return root
+}
+
+// insideSubmenu returns indication if the specified screen position
+// is inside any of the visible sub menus of this menu
+func (m *Menu) insideSubmenu(x, y float32) bool {
+
+	for _, mi := range m.items {
+		if mi.submenu == nil || !mi.submenu.Visible() {
+			continue
+		}
+		if mi.submenu.InsideBorders(x, y) || mi.submenu.insideSubmenu(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
 // onMouse process subscribed mouse events for the menu
 func (m *Menu) onMouse(evname string, ev interface{}) {
 
@@ -356,6 +603,7 @@ func (m *Menu) onMouse(evname string, ev interface{}) {
 	m.Root().SetTimeout(1*time.Millisecond, nil, func(arg interface{}) {
 		m.autoOpen = false
 		m.setSelectedPos(-1)
+		m.restoreFocus()
 	})
 }
 
@@ -584,8 +832,9 @@ func newMenuItem(text string, styles *MenuItemStyles) *MenuItem {
 	mi.Panel.Initialize(0, 0)
 	mi.styles = styles
 	if text != "" {
-		mi.label = NewLabel(text)
+		mi.label = NewLabel(" ")
 		mi.Panel.Add(mi.label)
+		mi.setText(text)
 		mi.Panel.Subscribe(OnCursorEnter, mi.onCursor)
 		mi.Panel.Subscribe(OnCursor, mi.onCursor)
 		mi.Panel.Subscribe(OnMouseDown, mi.onMouse)
@@ -605,7 +854,7 @@ func (mi *MenuItem) SetIcon(icode int) *MenuItem {
 		mi.licon = nil
 	}
 	// Sets the new icon
-	mi.licon = NewIconLabel(string(icode))
+	mi.licon = NewIconLabel(string(rune(icode)))
 	mi.Panel.Add(mi.licon)
 	mi.update()
 	return mi
@@ -623,12 +872,73 @@ func (mi *MenuItem) SetText(text string) *MenuItem {
 	if mi.label == nil {
 		return mi
 	}
-	mi.label.SetText(text)
+	mi.setText(text)
 	mi.update()
 	mi.menu.recalc()
 	return mi
 }
 
+// Mnemonic returns the mnemonic character of this menu item or 0 if none
+func (mi *MenuItem) Mnemonic() rune {
+
+	return mi.mnemonic
+}
+
+// setText sets the text of this menu item label extracting the
+// optional mnemonic character preceded by '&'
+func (mi *MenuItem) setText(text string) {
+
+	runes := []rune(text)
+	label := make([]rune, 0, len(runes))
+	mi.mnemonic = 0
+	mpos := -1
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '&' || i == len(runes)-1 {
+			label = append(label, runes[i])
+			continue
+		}
+		i++
+		if runes[i] != '&' && mpos < 0 {
+			mpos = len(label)
+			mi.mnemonic = unicode.ToUpper(runes[i])
+		}
+		label = append(label, runes[i])
+	}
+	mi.label.SetText(string(label))
+
+	// Creates or removes the mnemonic underline
+	mi.mpos = mpos
+	if mpos < 0 {
+		if mi.mline != nil {
+			mi.Panel.Remove(mi.mline)
+			mi.mline.Dispose()
+			mi.mline = nil
+		}
+		return
+	}
+	if mi.mline == nil {
+		mi.mline = NewPanel(0, 0)
+		mi.mline.SetEnabled(false)
+		mi.Panel.Add(mi.mline)
+	}
+	mi.recalcMnemonic()
+}
+
+// recalcMnemonic recalculates the position and size of the mnemonic
+// underline from the current position of the label
+func (mi *MenuItem) recalcMnemonic() {
+
+	if mi.mline == nil {
+		return
+	}
+	runes := []rune(mi.label.Text())
+	px, _ := mi.label.measureText(string(runes[:mi.mpos]))
+	pw, _ := mi.label.measureText(string(runes[:mi.mpos+1]))
+	lpos := mi.label.Position()
+	mi.mline.SetPosition(lpos.X+float32(px), lpos.Y+mi.label.height-2)
+	mi.mline.SetSize(float32(pw-px), 1)
+}
+
 // SetShortcut sets the keyboard shortcut of this menu item
 func (mi *MenuItem) SetShortcut(mods window.ModifierKey, key window.Key) *MenuItem {
 
@@ -760,18 +1070,18 @@ func (mi *MenuItem) activate() {
 		rm.autoOpen = false
 	}
 	rm.setSelectedPos(-1)
-	mi.root.SetKeyFocus(rm)
+	if rm.popup != nil {
+		rm.popup.Close()
+	} else {
+		rm.restoreFocus()
+	}
 	mi.dispatchAll(OnClick, mi)
 }
 
 // rootMenu returns the root menu for this menu item
 func (mi *MenuItem) rootMenu() *Menu {
 
-	root := mi.menu
-	for root.mitem != nil {
-		root = root.mitem.menu
-	}
-	return root
+	return mi.menu.rootMenu()
 }
 
 // dispatchAll dispatch the specified event for this menu item
@@ -837,6 +1147,9 @@ func (mi *MenuItem) applyStyle(mis *MenuItemStyle) {
 	if mi.label != nil {
 		mi.label.SetColor(&mis.FgColor)
 	}
+	if mi.mline != nil {
+		mi.mline.SetColor(&mis.FgColor)
+	}
 	if mi.shortcut != nil {
 		mi.shortcut.SetPaddingsFrom(&mis.ShortcutPaddings)
 	}
@@ -857,6 +1170,7 @@ func (mi *MenuItem) recalc(iconWidth, labelWidth, shortcutWidth float32) {
 		mi.licon.SetPosition(0, py)
 	}
 	mi.label.SetPosition(iconWidth, 0)
+	mi.recalcMnemonic()
 	if mi.shortcut != nil {
 		mi.shortcut.SetPosition(iconWidth+labelWidth, 0)
 	}
@@ -0,0 +1,186 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+// ModalStyle contains the styling of the modal backdrop
+type ModalStyle struct {
+	BackdropColor math32.Color4
+}
+
+// rootModal keeps the state of a modal panel shown by a gui root
+type rootModal struct {
+	panel    IPanel // modal panel
+	backdrop *Panel // backdrop panel covering the window under the modal panel
+	focus    IPanel // panel with the key focus when the modal panel was shown
+	added    bool   // modal panel was added to the root by ShowModal
+}
+
+// ShowModal shows the specified panel as a modal panel over a translucent
+// backdrop which covers the whole window. While shown, all the input events
+// are sent only to the modal panel and its children and the panels under
+// the backdrop are blocked. Panels added to the root or moved to the top
+// after the modal panel is shown, such as context menus and tooltips, also
+// receive input. The panel is shown at its current position and is added to
+// the root if it has no parent. Modal panels can be stacked and only the
+// last one shown receives input.
+func (r *Root) ShowModal(ipan IPanel) {
+
+	if r.modal(ipan) >= 0 {
+		return
+	}
+	if r.drag != nil {
+		r.CancelDrag()
+	}
+	r.cursorLeave(r)
+	m := &rootModal{panel: ipan, focus: r.keyFocus}
+
+	// Creates the backdrop with the size of the window
+	m.backdrop = NewPanel(0, 0)
+	m.backdrop.SetColor4(&StyleDefault.Modal.BackdropColor)
+	m.backdrop.SetBounded(false)
+	m.backdrop.Subscribe(OnMouseDown, r.onBackdrop)
+	width, height := r.win.GetSize()
+	m.backdrop.SetSize(float32(width), float32(height))
+	r.Add(m.backdrop)
+	r.SetTopChild(m.backdrop)
+	if ipan.GetPanel().Parent() == nil {
+		r.Add(ipan)
+		m.added = true
+	}
+	r.SetTopChild(ipan)
+	r.modals = append(r.modals, m)
+
+	// Removes the focus of the panels under the backdrop
+	r.mouseFocus = nil
+	r.scrollFocus = nil
+	r.SetKeyFocus(nil)
+}
+
+// CloseModal closes the last modal panel shown, removing its backdrop and the
+// panel, if it was added by ShowModal, from this root, and restores the key focus to the panel which had it when
+// the modal panel was shown. OnClose is dispatched to the modal panel.
+func (r *Root) CloseModal() {
+
+	if len(r.modals) == 0 {
+		return
+	}
+	m := r.modals[len(r.modals)-1]
+	r.modals[len(r.modals)-1] = nil
+	r.modals = r.modals[:len(r.modals)-1]
+	r.cursorLeave(m.panel)
+	r.Remove(m.backdrop)
+	m.backdrop.Dispose()
+	if m.added {
+		r.Remove(m.panel)
+	}
+	r.mouseFocus = nil
+	r.scrollFocus = nil
+	r.SetKeyFocus(m.focus)
+	m.panel.GetPanel().Dispatch(OnClose, nil)
+}
+
+// Modal returns the last modal panel shown or nil if none
+func (r *Root) Modal() IPanel {
+
+	if len(r.modals) == 0 {
+		return nil
+	}
+	return r.modals[len(r.modals)-1].panel
+}
+
+// SetDismissOnBackdrop sets if clicking on the backdrop of a modal panel
+// closes it. The default is false.
+func (r *Root) SetDismissOnBackdrop(state bool) {
+
+	r.modalDismiss = state
+}
+
+// DismissOnBackdrop returns if clicking on the backdrop of a modal panel closes it
+func (r *Root) DismissOnBackdrop() bool {
+
+	return r.modalDismiss
+}
+
+// modal returns the index of the specified panel in the stack of modal panels or -1
+func (r *Root) modal(ipan IPanel) int {
+
+	for i, m := range r.modals {
+		if m.panel.GetPanel() == ipan.GetPanel() {
+			return i
+		}
+	}
+	return -1
+}
+
+// inModal returns if the specified panel can receive input events,
+// which is always true if no modal panel is shown. Otherwise the panel
+// or one of its ancestors must be in the modal layer of the root.
+func (r *Root) inModal(ipan IPanel) bool {
+
+	if len(r.modals) == 0 {
+		return true
+	}
+	if ipan == nil {
+		return false
+	}
+	layer := r.modalLayer()
+	var node core.INode = ipan
+	for node != nil {
+		for _, child := range layer {
+			if child.GetNode() == node.GetNode() {
+				return true
+			}
+		}
+		node = node.GetNode().Parent()
+	}
+	return false
+}
+
+// modalLayer returns the children of the root which receive input while the
+// last modal panel is shown: its backdrop, the modal panel and the panels
+// added or moved to the top after them.
+func (r *Root) modalLayer() []core.INode {
+
+	m := r.modals[len(r.modals)-1]
+	children := r.Children()
+	for i, child := range children {
+		if child.GetNode() == m.backdrop.GetNode() {
+			return children[i:]
+		}
+	}
+	return nil
+}
+
+// onBackdrop process mouse button events over the backdrop of the last modal panel
+func (r *Root) onBackdrop(evname string, ev interface{}) {
+
+	mev := ev.(*window.MouseEvent)
+	if mev.Button == window.MouseButtonLeft && r.modalDismiss {
+		r.CloseModal()
+	}
+	r.StopPropagation(StopAll)
+}
+
+// cursorLeave dispatches OnCursorLeave to the specified panel
+// and its descendants which have the cursor over them
+func (r *Root) cursorLeave(ipan IPanel) {
+
+	pan := ipan.GetPanel()
+	if pan.cursorEnter {
+		pan.Dispatch(OnCursorLeave, nil)
+		pan.cursorEnter = false
+	}
+	for _, child := range pan.Children() {
+		if ichild, ok := child.(IPanel); ok {
+			r.cursorLeave(ichild)
+		}
+	}
+}
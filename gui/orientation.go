@@ -0,0 +1,12 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+type Orientation int
+
+const (
+	Horizontal = Orientation(iota) // Horizontal orientation
+	Vertical                       // Vertical orientation
+)
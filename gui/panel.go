@@ -75,6 +75,11 @@ type Panel struct {
 	cursorEnter      bool                // mouse enter dispatched
 	layout           ILayout             // current layout for children
 	layoutParams     interface{}         // current layout parameters used by container panel
+	tooltip          *panelTooltip       // tooltip state (may be nil)
+	shadow           *panelShadow        // drop shadow (may be nil)
+	focusable        bool                // panel can receive the key focus by Tab navigation
+	tabIndex         int                 // order of the panel in the Tab navigation
+	drag             *panelDrag          // drag and drop state (may be nil)
 }
 
 const (
@@ -88,6 +93,10 @@ const (
 	idxPaddingColor = 5              // index of uniform array for padding color
 	idxContentColor = 6              // index of uniform array for content color
 	posTextureValid = 7 * 4          // position of uniform array for texture valid
+	idxRadius       = 8              // index of uniform array for corner radii in pixels
+	idxSize         = 9              // index of uniform array for panel size in pixels
+	idxTextureTint  = 10             // index of uniform array for texture tint color
+	panUniCount     = 11             // number of elements of uniform array
 )
 
 // NewPanel creates and returns a pointer to a new panel with the
@@ -134,13 +143,16 @@ func (p *Panel) Initialize(width, height float32) {
 	// Initialize graphic
 	p.Graphic = graphic.NewGraphic(geom, gls.TRIANGLES)
 	p.AddMaterial(p, p.mat, 0, 0)
+	// Panels are positioned in the screen independently of the camera
+	p.SetFrustumCulled(false)
 
 	// Initialize uniforms
 	p.modelMatrixUni.Init("ModelMatrix")
-	p.panUni.Init("Panel", 8)
+	p.panUni.Init("Panel", panUniCount)
 
 	// Set defaults
 	p.panUni.Set(idxBorderColor, 0, 0, 0, 1)
+	p.panUni.Set(idxTextureTint, 1, 1, 1, 1)
 	p.bounded = true
 	p.enabled = true
 	p.resize(width, height)
@@ -150,15 +162,17 @@ func (p *Panel) Initialize(width, height float32) {
 func (p *Panel) InitializeGraphic(width, height float32, gr *graphic.Graphic) {
 
 	p.Graphic = gr
+	p.SetFrustumCulled(false)
 	p.width = width
 	p.height = height
 
 	// Initializes uniforms
 	p.modelMatrixUni.Init("ModelMatrix")
-	p.panUni.Init("Panel", 8)
+	p.panUni.Init("Panel", panUniCount)
 
 	// Set defaults
 	p.panUni.Set(idxBorderColor, 0, 0, 0, 1)
+	p.panUni.Set(idxTextureTint, 1, 1, 1, 1)
 	p.bounded = true
 	p.enabled = true
 	p.resize(width, height)
@@ -171,14 +185,25 @@ func (pan *Panel) GetPanel() *Panel {
 	return pan
 }
 
+// Dispose overrides the embedded graphic Dispose method
+// and also releases the resources used by the panel shadow.
+func (p *Panel) Dispose() {
+
+	if p.shadow != nil {
+		p.Graphic.RemoveMaterial(p.shadow.mat)
+		p.shadow.dispose()
+		p.shadow = nil
+	}
+	p.Graphic.Dispose()
+}
+
 // SetRoot satisfies the IPanel interface
 // Sets the pointer to the root panel for this panel and all its children
 func (p *Panel) SetRoot(root *Root) {
 
 	p.root = root
 	for i := 0; i < len(p.Children()); i++ {
-		cpan := p.Children()[i].(IPanel).GetPanel()
-		cpan.SetRoot(root)
+		p.Children()[i].(IPanel).SetRoot(root)
 	}
 }
 
@@ -369,6 +394,45 @@ func (p *Panel) Paddings() BorderSizes {
 	return p.paddingSizes
 }
 
+// SetBorderRadius sets the radius in pixels of all the corners
+// of this panel borders and background.
+// The rounded corners are only drawn and do not change the
+// rectangular area used to check mouse events over the panel.
+func (p *Panel) SetBorderRadius(radius float32) {
+
+	p.SetBorderRadius4(radius, radius, radius, radius)
+}
+
+// SetBorderRadius4 sets the radius in pixels of each corner of this
+// panel borders and background: top left, top right, bottom right
+// and bottom left.
+func (p *Panel) SetBorderRadius4(tl, tr, br, bl float32) {
+
+	p.panUni.Set(idxRadius, tl, tr, br, bl)
+}
+
+// BorderRadius4 returns the radius in pixels of each corner of this
+// panel borders: top left, top right, bottom right and bottom left.
+func (p *Panel) BorderRadius4() (tl, tr, br, bl float32) {
+
+	r := p.panUni.GetColor4(idxRadius)
+	return r.R, r.G, r.B, r.A
+}
+
+// SetTextureTint sets the color which multiplies the color of this
+// panel content texture. The default tint is opaque white which
+// does not change the texture colors.
+func (p *Panel) SetTextureTint(color *math32.Color4) {
+
+	p.panUni.SetColor4(idxTextureTint, color)
+}
+
+// TextureTint returns the current tint color of this panel content texture
+func (p *Panel) TextureTint() math32.Color4 {
+
+	return p.panUni.GetColor4(idxTextureTint)
+}
+
 // SetBordersColor sets the color of this panel borders
 // The borders opacity is set to 1.0 (full opaque)
 func (p *Panel) SetBordersColor(color *math32.Color) {
@@ -571,6 +635,35 @@ func (p *Panel) Enabled() bool {
 	return p.enabled
 }
 
+// SetFocusable sets if this panel can receive the key focus
+// by the Tab key navigation of the gui root.
+func (p *Panel) SetFocusable(state bool) {
+
+	p.focusable = state
+}
+
+// Focusable returns if this panel can receive the key focus
+// by the Tab key navigation of the gui root.
+func (p *Panel) Focusable() bool {
+
+	return p.focusable
+}
+
+// SetTabIndex sets the order of this panel in the Tab key navigation.
+// Panels with positive indices are visited first in increasing order
+// followed by the panels with index zero (the default) in the order they
+// were added to the gui tree. Panels with negative indices are skipped.
+func (p *Panel) SetTabIndex(index int) {
+
+	p.tabIndex = index
+}
+
+// TabIndex returns the order of this panel in the Tab key navigation
+func (p *Panel) TabIndex() int {
+
+	return p.tabIndex
+}
+
 // SetLayout sets the layout to use to position the children of this panel
 // To remove the layout, call this function passing nil as parameter.
 func (p *Panel) SetLayout(ilayout ILayout) {
@@ -688,22 +781,7 @@ func (p *Panel) updateBounds(par *Panel) {
 	p.ymax = p.pospix.Y + p.height
 	if p.bounded {
 		// Get the parent content area minimum and maximum absolute coordinates in pixels
-		pxmin := par.pospix.X + par.marginSizes.Left + par.borderSizes.Left + par.paddingSizes.Left
-		if pxmin < par.xmin {
-			pxmin = par.xmin
-		}
-		pymin := par.pospix.Y + par.marginSizes.Top + par.borderSizes.Top + par.paddingSizes.Top
-		if pymin < par.ymin {
-			pymin = par.ymin
-		}
-		pxmax := par.pospix.X + par.width - (par.marginSizes.Right + par.borderSizes.Right + par.paddingSizes.Right)
-		if pxmax > par.xmax {
-			pxmax = par.xmax
-		}
-		pymax := par.pospix.Y + par.height - (par.marginSizes.Bottom + par.borderSizes.Bottom + par.paddingSizes.Bottom)
-		if pymax > par.ymax {
-			pymax = par.ymax
-		}
+		pxmin, pymin, pxmax, pymax := par.contentBounds()
 		// Update this panel minimum x and y coordinates.
 		if p.xmin < pxmin {
 			p.xmin = pxmin
@@ -745,6 +823,29 @@ func (p *Panel) updateBounds(par *Panel) {
 	p.panUni.Set(idxBounds, xmintex, ymintex, xmaxtex, ymaxtex)
 }
 
+// contentBounds returns the minimum and maximum absolute coordinates in pixels
+// of this panel content area which can be used by its bounded children.
+func (p *Panel) contentBounds() (xmin, ymin, xmax, ymax float32) {
+
+	xmin = p.pospix.X + p.marginSizes.Left + p.borderSizes.Left + p.paddingSizes.Left
+	if xmin < p.xmin {
+		xmin = p.xmin
+	}
+	ymin = p.pospix.Y + p.marginSizes.Top + p.borderSizes.Top + p.paddingSizes.Top
+	if ymin < p.ymin {
+		ymin = p.ymin
+	}
+	xmax = p.pospix.X + p.width - (p.marginSizes.Right + p.borderSizes.Right + p.paddingSizes.Right)
+	if xmax > p.xmax {
+		xmax = p.xmax
+	}
+	ymax = p.pospix.Y + p.height - (p.marginSizes.Bottom + p.borderSizes.Bottom + p.paddingSizes.Bottom)
+	if ymax > p.ymax {
+		ymax = p.ymax
+	}
+	return
+}
+
 // calcWidth calculates the panel external width in pixels
 func (p *Panel) calcWidth() float32 {
 
@@ -835,6 +936,8 @@ func (p *Panel) resize(width, height float32) {
 		float32(p.content.Width)/float32(p.width),
 		float32(p.content.Height)/float32(p.height),
 	)
+	// Updates panel size used for the rounded corners
+	p.panUni.Set(idxSize, p.width, p.height, 0, 0)
 	// Update layout and dispatch event
 	if p.layout != nil {
 		p.layout.Recalc(p)
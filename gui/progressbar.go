@@ -0,0 +1,229 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"time"
+
+	"github.com/g3n/engine/math32"
+)
+
+/***************************************
+
+ ProgressBar
+ +--------------------------------+
+ |  +--------------------------+  |
+ |  +-----------+              |  |
+ |  |           |              |  |
+ |  |   bar     |   track      |  |
+ |  +-----------+              |  |
+ |  +--------------------------+  |
+ +--------------------------------+
+
+**/
+
+// ProgressBar is a horizontal bar which shows the progress of an operation.
+// In the determinate mode the bar shows the current value from 0.0 to 1.0.
+// In the indeterminate mode the value is ignored and a stripe moves
+// continuously along the track.
+type ProgressBar struct {
+	Panel                            // Embedded panel (track)
+	bar           Panel              // bar panel
+	styles        *ProgressBarStyles // pointer to current styles
+	value         float32            // current value from 0.0 to 1.0
+	indeterminate bool               // indeterminate mode
+	animID        int                // id of the animation timer (0 = none)
+	animTime      time.Time          // time of the last animation step
+	animPos       float32            // current position of the stripe in track lengths
+}
+
+// ProgressBarStyle contains the styling of a ProgressBar
+type ProgressBarStyle struct {
+	Border      BorderSizes   // track border sizes
+	BorderColor math32.Color4 // track border color
+	Paddings    BorderSizes   // track padding sizes
+	BgColor     math32.Color4 // track color
+	FgColor     math32.Color4 // bar color
+	StripeColor math32.Color4 // indeterminate stripe color
+	StripeWidth float32       // indeterminate stripe width as a fraction of the track length
+	StripeSpeed float32       // indeterminate stripe speed in track lengths per second
+}
+
+// ProgressBarStyles contains all the ProgressBar styles
+type ProgressBarStyles struct {
+	Normal   ProgressBarStyle
+	Disabled ProgressBarStyle
+}
+
+// NewProgressBar creates and returns a pointer to a new progress bar
+// with the specified initial dimensions
+func NewProgressBar(width, height float32) *ProgressBar {
+
+	pb := new(ProgressBar)
+	pb.styles = &StyleDefault.ProgressBar
+
+	// Initialize track panel
+	pb.Panel.Initialize(width, height)
+	pb.Panel.Subscribe(OnResize, func(evname string, ev interface{}) { pb.recalc() })
+	pb.Panel.Subscribe(OnEnable, func(evname string, ev interface{}) { pb.update() })
+
+	// Initialize bar panel
+	pb.bar.Initialize(0, 0)
+	pb.Panel.Add(&pb.bar)
+
+	pb.update()
+	pb.recalc()
+	return pb
+}
+
+// SetRoot satisfies the IPanel interface and starts
+// the animation of the indeterminate mode if necessary
+func (pb *ProgressBar) SetRoot(root *Root) {
+
+	pb.Panel.SetRoot(root)
+	pb.startAnim()
+}
+
+// SetValue sets the current value of the progress bar from 0.0 to 1.0
+// and switches the progress bar to the determinate mode.
+func (pb *ProgressBar) SetValue(value float32) {
+
+	pb.value = math32.Clamp(value, 0, 1)
+	pb.setIndeterminate(false)
+	pb.recalc()
+}
+
+// Value returns the current value of the progress bar
+func (pb *ProgressBar) Value() float32 {
+
+	return pb.value
+}
+
+// SetIndeterminate sets the indeterminate mode of the progress bar.
+// In this mode the value is ignored and a stripe is animated along the track.
+func (pb *ProgressBar) SetIndeterminate(state bool) {
+
+	pb.setIndeterminate(state)
+	pb.recalc()
+}
+
+// Indeterminate returns if the progress bar is in the indeterminate mode
+func (pb *ProgressBar) Indeterminate() bool {
+
+	return pb.indeterminate
+}
+
+// SetStyles sets the progress bar styles overriding the default style
+func (pb *ProgressBar) SetStyles(ps *ProgressBarStyles) {
+
+	pb.styles = ps
+	pb.update()
+	pb.recalc()
+}
+
+// Dispose overrides the embedded panel Dispose method
+// and stops the animation if necessary
+func (pb *ProgressBar) Dispose() {
+
+	pb.stopAnim()
+	pb.Panel.Dispose()
+}
+
+// setIndeterminate sets the indeterminate state and starts or stops the animation
+func (pb *ProgressBar) setIndeterminate(state bool) {
+
+	if state == pb.indeterminate {
+		return
+	}
+	pb.indeterminate = state
+	pb.animPos = 0
+	if state {
+		pb.startAnim()
+	} else {
+		pb.stopAnim()
+	}
+	pb.update()
+}
+
+// startAnim starts the animation timer if in indeterminate mode and
+// the progress bar was already added to a gui root.
+func (pb *ProgressBar) startAnim() {
+
+	if !pb.indeterminate || pb.animID != 0 || pb.root == nil {
+		return
+	}
+	pb.animTime = time.Now()
+	pb.animID = pb.root.SetInterval(time.Millisecond, nil, pb.onAnim)
+}
+
+// stopAnim stops the animation timer
+func (pb *ProgressBar) stopAnim() {
+
+	if pb.animID != 0 {
+		pb.root.ClearTimeout(pb.animID)
+		pb.animID = 0
+	}
+}
+
+// onAnim is called at each frame in the indeterminate mode
+// and moves the stripe along the track
+func (pb *ProgressBar) onAnim(arg interface{}) {
+
+	now := time.Now()
+	dt := float32(now.Sub(pb.animTime).Seconds())
+	pb.animTime = now
+	ps := pb.style()
+	pb.animPos += ps.StripeSpeed * dt
+	// The stripe restarts after it completely leaves the track
+	if pb.animPos > 1+ps.StripeWidth {
+		pb.animPos = 0
+	}
+	pb.recalc()
+}
+
+// style returns the current style for the progress bar state
+func (pb *ProgressBar) style() *ProgressBarStyle {
+
+	if !pb.Enabled() {
+		return &pb.styles.Disabled
+	}
+	return &pb.styles.Normal
+}
+
+// update updates the progress bar visual state
+func (pb *ProgressBar) update() {
+
+	pb.applyStyle(pb.style())
+}
+
+// applyStyle applies the specified progress bar style
+func (pb *ProgressBar) applyStyle(ps *ProgressBarStyle) {
+
+	pb.SetBordersColor4(&ps.BorderColor)
+	pb.SetBordersFrom(&ps.Border)
+	pb.SetPaddingsFrom(&ps.Paddings)
+	pb.Panel.SetColor4(&ps.BgColor)
+	if pb.indeterminate {
+		pb.bar.SetColor4(&ps.StripeColor)
+	} else {
+		pb.bar.SetColor4(&ps.FgColor)
+	}
+}
+
+// recalc recalculates the position and size of the bar panel.
+// The stripe of the indeterminate mode is clipped by the track content area.
+func (pb *ProgressBar) recalc() {
+
+	width := pb.ContentWidth()
+	height := pb.ContentHeight()
+	if !pb.indeterminate {
+		pb.bar.SetPosition(0, 0)
+		pb.bar.SetSize(width*pb.value, height)
+		return
+	}
+	ps := pb.style()
+	pb.bar.SetPosition(width*(pb.animPos-ps.StripeWidth), 0)
+	pb.bar.SetSize(width*ps.StripeWidth, height)
+}
@@ -0,0 +1,155 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"github.com/g3n/engine/core"
+)
+
+// RadioGroup manages a set of radio buttons of which only one can be checked.
+// Checking one member unchecks the others and dispatches a single
+// OnChange event on the group with the checked member (or nil).
+// Arrow keys move the selection among the group members when one is focused.
+type RadioGroup struct {
+	core.Dispatcher               // Embedded event dispatcher
+	items           []*CheckRadio // group members in the order they were added
+	selected        *CheckRadio   // currently checked member (may be nil)
+}
+
+// NewRadioGroup creates and returns a pointer to a new radio group
+// with the specified optional radio buttons
+func NewRadioGroup(buttons ...*CheckRadio) *RadioGroup {
+
+	g := new(RadioGroup)
+	g.Dispatcher.Initialize()
+	for _, cb := range buttons {
+		g.Add(cb)
+	}
+	return g
+}
+
+// Add adds the specified radio button to this group.
+// If the button is checked and the group already has a checked
+// member, the button is unchecked.
+func (g *RadioGroup) Add(cb *CheckRadio) {
+
+	if cb.check {
+		log.Warn("CheckBox cannot be added to a RadioGroup")
+		return
+	}
+	if cb.radioGroup == g {
+		return
+	}
+	if cb.radioGroup != nil {
+		cb.radioGroup.Remove(cb)
+	}
+	g.items = append(g.items, cb)
+	cb.radioGroup = g
+	if cb.Value() {
+		if g.selected == nil {
+			g.selected = cb
+		} else {
+			cb.SetValue(false)
+		}
+	}
+}
+
+// Remove removes the specified radio button from this group
+// and returns true if it was found.
+// The button keeps its current state.
+func (g *RadioGroup) Remove(cb *CheckRadio) bool {
+
+	for pos, item := range g.items {
+		if item == cb {
+			copy(g.items[pos:], g.items[pos+1:])
+			g.items[len(g.items)-1] = nil
+			g.items = g.items[:len(g.items)-1]
+			cb.radioGroup = nil
+			if g.selected == cb {
+				g.selected = nil
+				g.Dispatch(OnChange, nil)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Items returns the radio buttons of this group
+func (g *RadioGroup) Items() []*CheckRadio {
+
+	return g.items
+}
+
+// Selected returns the checked radio button of this group or nil if none
+func (g *RadioGroup) Selected() *CheckRadio {
+
+	return g.selected
+}
+
+// SetSelected checks the specified radio button of this group and unchecks
+// the others. If nil is specified all the group members are unchecked.
+func (g *RadioGroup) SetSelected(cb *CheckRadio) {
+
+	if cb == nil {
+		if g.selected != nil {
+			g.selected.SetValue(false)
+		}
+		return
+	}
+	if cb.radioGroup != g {
+		log.Warn("RadioButton is not a member of this RadioGroup")
+		return
+	}
+	cb.SetValue(true)
+}
+
+// onState is called by a member when its checked state changes
+func (g *RadioGroup) onState(cb *CheckRadio) {
+
+	if !cb.Value() {
+		if g.selected == cb {
+			g.selected = nil
+			g.Dispatch(OnChange, nil)
+		}
+		return
+	}
+	if g.selected == cb {
+		return
+	}
+	// The selected member is updated before unchecking the previous one
+	// so only one OnChange event is dispatched by the group
+	prev := g.selected
+	g.selected = cb
+	if prev != nil {
+		prev.SetValue(false)
+	}
+	g.Dispatch(OnChange, cb)
+}
+
+// next returns the enabled and visible member of this group which is the
+// specified number of positions from the specified member, wrapping around
+// at the ends of the group, or nil if none found.
+func (g *RadioGroup) next(cb *CheckRadio, delta int) *CheckRadio {
+
+	pos := -1
+	for i, item := range g.items {
+		if item == cb {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return nil
+	}
+	count := len(g.items)
+	for i := 1; i < count; i++ {
+		item := g.items[(pos+i*delta+count*count)%count]
+		if item.Enabled() && item.Visible() {
+			return item
+		}
+	}
+	return nil
+}
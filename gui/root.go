@@ -21,6 +21,10 @@ type Root struct {
 	mouseFocus        IPanel         // current child panel with mouse focus
 	scrollFocus       IPanel         // current child panel with scroll focus
 	targets           listPanelZ     // preallocated list of target panels
+	tooltip           *Tooltip       // shared tooltip panel (created on demand)
+	drag              *rootDrag      // current drag and drop operation (may be nil)
+	modals            []*rootModal   // stack of modal panels shown
+	modalDismiss      bool           // clicking on the modal backdrop closes the modal panel
 }
 
 const (
@@ -75,19 +79,107 @@ func (r *Root) Add(ipan IPanel) {
 }
 
 // SetKeyFocus sets the panel which will receive all keyboard events
+// and dispatches OnFocus to it. Nothing is done if the panel
+// already has the key focus.
 // Passing nil will remove the focus (if any)
 func (r *Root) SetKeyFocus(ipan IPanel) {
 
+	// If this panel is already in focus, nothing to do
+	if ipan == nil && r.keyFocus == nil {
+		return
+	}
+	if ipan != nil && r.HasKeyFocus(ipan) {
+		// A widget which embeds the focused panel, such as an edit,
+		// replaces it to receive LostKeyFocus
+		r.keyFocus = ipan
+		return
+	}
 	if r.keyFocus != nil {
-		// If this panel is already in focus, nothing to do
-		if ipan != nil {
-			if r.keyFocus.GetPanel() == ipan.GetPanel() {
-				return
-			}
-		}
 		r.keyFocus.LostKeyFocus()
 	}
 	r.keyFocus = ipan
+	if ipan != nil {
+		ipan.GetPanel().Dispatch(OnFocus, nil)
+	}
+}
+
+// FocusNext moves the key focus to the next focusable panel
+// in the Tab key navigation order
+func (r *Root) FocusNext() {
+
+	r.moveFocus(1)
+}
+
+// FocusPrev moves the key focus to the previous focusable panel
+// in the Tab key navigation order
+func (r *Root) FocusPrev() {
+
+	r.moveFocus(-1)
+}
+
+// moveFocus moves the key focus to the focusable panel at the specified
+// offset from the panel with the current key focus, wrapping around
+// at the ends. If no panel has the key focus, the first or the last
+// focusable panel receives it.
+func (r *Root) moveFocus(delta int) {
+
+	list := r.focusables()
+	if len(list) == 0 {
+		return
+	}
+	pos := -1
+	if r.keyFocus != nil {
+		for i, ipan := range list {
+			if ipan.GetPanel() == r.keyFocus.GetPanel() {
+				pos = i
+				break
+			}
+		}
+	}
+	if pos < 0 {
+		if delta > 0 {
+			pos = len(list) - 1
+		} else {
+			pos = 0
+		}
+	}
+	count := len(list)
+	r.SetKeyFocus(list[(pos+delta%count+count)%count])
+}
+
+// focusables returns the visible and enabled focusable panels of this
+// root, or of the last modal panel shown, in the Tab key navigation order.
+// The children of hidden or disabled panels are skipped.
+func (r *Root) focusables() []IPanel {
+
+	list := []IPanel{}
+	var add func(ipan IPanel)
+	add = func(ipan IPanel) {
+		pan := ipan.GetPanel()
+		if !pan.Visible() || !pan.Enabled() {
+			return
+		}
+		if pan.focusable && pan.tabIndex >= 0 {
+			list = append(list, ipan)
+		}
+		for _, child := range pan.Children() {
+			if ichild, ok := child.(IPanel); ok {
+				add(ichild)
+			}
+		}
+	}
+	children := r.Children()
+	if len(r.modals) > 0 {
+		children = r.modalLayer()
+	}
+	for _, child := range children {
+		if ichild, ok := child.(IPanel); ok {
+			add(ichild)
+		}
+	}
+	// Panels with positive indices first, keeping the tree order for equal indices
+	sort.Stable(listPanelTab(list))
+	return list
 }
 
 // ClearKeyFocus clears the key focus panel (if any) without
@@ -171,15 +263,38 @@ func (r *Root) SetCursorVResize() {
 }
 
 // onKey is called when key events are received
+// The event is first dispatched to the subscribers of the root panel
+// which can handle global keys (such as the menu bar) and stop its
+// propagation to the panel with the key focus.
 func (r *Root) onKey(evname string, ev interface{}) {
 
-	// If no panel has the key focus, nothing to do
-	if r.keyFocus == nil {
+	// The Escape key cancels the current drag operation
+	kev := ev.(*window.KeyEvent)
+	if evname == OnKeyDown && kev.Keycode == window.KeyEscape && r.Dragging() {
+		r.CancelDrag()
+		r.win.CancelDispatch()
 		return
 	}
-	// Dispatch window.KeyEvent to focused panel subscribers
 	r.stopPropagation = 0
-	r.keyFocus.GetPanel().Dispatch(evname, ev)
+	// The subscribers of the root panel are not called while a modal panel is shown
+	if len(r.modals) == 0 {
+		r.Dispatch(evname, ev)
+	}
+	// If no panel has the key focus or propagation stopped, nothing more to do.
+	// The root panel subscribers already received the event.
+	if r.keyFocus != nil && r.keyFocus.GetPanel() != &r.Panel && r.inModal(r.keyFocus) && (r.stopPropagation&StopGUI) == 0 {
+		// Dispatch window.KeyEvent to focused panel subscribers
+		r.keyFocus.GetPanel().Dispatch(evname, ev)
+	}
+	// The Tab key moves the key focus if not used by the previous subscribers
+	if evname != OnKeyUp && kev.Keycode == window.KeyTab && (r.stopPropagation&StopGUI) == 0 {
+		if kev.Mods&window.ModShift != 0 {
+			r.FocusPrev()
+		} else {
+			r.FocusNext()
+		}
+		r.stopPropagation |= Stop3D
+	}
 	// If requested, stop propagation of event outside the root gui
 	if (r.stopPropagation & Stop3D) != 0 {
 		r.win.CancelDispatch()
@@ -190,7 +305,7 @@ func (r *Root) onKey(evname string, ev interface{}) {
 func (r *Root) onChar(evname string, ev interface{}) {
 
 	// If no panel has the key focus, nothing to do
-	if r.keyFocus == nil {
+	if r.keyFocus == nil || !r.inModal(r.keyFocus) {
 		return
 	}
 	// Dispatch window.CharEvent to focused panel subscribers
@@ -206,7 +321,15 @@ func (r *Root) onChar(evname string, ev interface{}) {
 func (r *Root) onMouse(evname string, ev interface{}) {
 
 	mev := ev.(*window.MouseEvent)
+	// The mouse button release which finishes a drag is not sent to the panels
+	if evname == OnMouseUp && r.dragMouse(evname, mev) {
+		r.win.CancelDispatch()
+		return
+	}
 	r.sendPanels(mev.Xpos, mev.Ypos, evname, ev)
+	if evname == OnMouseDown {
+		r.dragMouse(evname, mev)
+	}
 }
 
 // onCursor is called when (mouse) cursor events are received
@@ -214,6 +337,7 @@ func (r *Root) onCursor(evname string, ev interface{}) {
 
 	cev := ev.(*window.CursorEvent)
 	r.sendPanels(cev.Xpos, cev.Ypos, evname, ev)
+	r.dragCursor(cev)
 }
 
 // sendPanel sends mouse or cursor event to focused panel or panels
@@ -221,7 +345,7 @@ func (r *Root) onCursor(evname string, ev interface{}) {
 func (r *Root) sendPanels(x, y float32, evname string, ev interface{}) {
 
 	// If there is panel with MouseFocus send only to this panel
-	if r.mouseFocus != nil {
+	if r.mouseFocus != nil && r.inModal(r.mouseFocus) {
 		r.mouseFocus.GetPanel().Dispatch(evname, ev)
 		if (r.stopPropagation & Stop3D) != 0 {
 			r.win.CancelDispatch()
@@ -265,8 +389,13 @@ func (r *Root) sendPanels(x, y float32, evname string, ev interface{}) {
 		}
 	}
 
-	// Checks all children of this root node
-	for _, iobj := range r.Node.Children() {
+	// Checks all children of this root node or, if a modal panel
+	// is shown, only the children in the modal layer
+	children := r.Node.Children()
+	if len(r.modals) > 0 {
+		children = r.modalLayer()
+	}
+	for _, iobj := range children {
 		ipan, ok := iobj.(IPanel)
 		if !ok {
 			continue
@@ -318,7 +447,7 @@ func (r *Root) sendPanels(x, y float32, evname string, ev interface{}) {
 func (r *Root) onScroll(evname string, ev interface{}) {
 
 	// If no panel with the scroll focus, nothing to do
-	if r.scrollFocus == nil {
+	if r.scrollFocus == nil || !r.inModal(r.scrollFocus) {
 		return
 	}
 	// Dispatch event to panel with scroll focus
@@ -333,6 +462,11 @@ func (r *Root) onScroll(evname string, ev interface{}) {
 // onSize is called when window size events are received
 func (r *Root) onWindowSize(evname string, ev interface{}) {
 
+	// Resizes the backdrops of the modal panels
+	width, height := r.win.GetSize()
+	for _, m := range r.modals {
+		m.backdrop.SetSize(float32(width), float32(height))
+	}
 	// Sends event only to immediate children
 	for _, ipan := range r.Children() {
 		ipan.(IPanel).GetPanel().Dispatch(evname, ev)
@@ -356,3 +490,18 @@ func (p listPanelZ) Less(i, j int) bool {
 	jz := p[j].GetPanel().Position().Z
 	return iz < jz
 }
+
+// For sorting panels by Tab key navigation order
+type listPanelTab []IPanel
+
+func (p listPanelTab) Len() int      { return len(p) }
+func (p listPanelTab) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p listPanelTab) Less(i, j int) bool {
+
+	ti := p[i].GetPanel().tabIndex
+	tj := p[j].GetPanel().tabIndex
+	if ti == 0 || tj == 0 {
+		return ti != 0 && tj == 0
+	}
+	return ti < tj
+}
@@ -5,6 +5,7 @@
 package gui
 
 import (
+	"github.com/g3n/engine/gui/assets"
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/window"
 )
@@ -13,20 +14,40 @@ import (
 
  ScrollBar Panel
  +--------------------------------+
+ |  optional arrow button         |
+ +--------------------------------+
  |         scroll button          |
  |        +--------------+        |
  |        |              |        |
  |        |              |        |
  |        +--------------+        |
  +--------------------------------+
+ |  optional arrow button         |
+ +--------------------------------+
 
 **/
 
+// ScrollBar is a vertical or horizontal bar with a button (thumb) which can
+// be dragged to change the current value from 0.0 to 1.0.
+// If a range is set, the button size is proportional to the visible
+// fraction of the total range. Clicks in the bar outside the button scroll
+// one page and the optional arrow buttons scroll one step.
+// When the scroll bar is focusable, the arrow keys, PageUp, PageDown,
+// Home and End keys also change the value.
+// OnChange is dispatched with the new float64 value when the value is
+// changed by the user.
 type ScrollBar struct {
 	Panel                    // Embedded panel
 	style    *ScrollBarStyle // pointer to current style
 	vertical bool            // type of scrollbar
 	button   scrollBarButton // scrollbar button
+	value    float32         // current value from 0.0 to 1.0
+	total    float32         // total size of the scrolled range (0 = fixed button size)
+	visible  float32         // visible size of the scrolled range
+	step     float32         // step of the arrow buttons and keys (0 = automatic)
+	arrows   bool            // arrow buttons are shown
+	arrowDec scrollBarArrow  // left/top arrow button
+	arrowInc scrollBarArrow  // right/bottom arrow button
 }
 
 type scrollBarButton struct {
@@ -37,6 +58,14 @@ type scrollBarButton struct {
 	mouseY  float32    // last mouse click y position
 }
 
+// scrollBarArrow is an arrow button of the scroll bar
+type scrollBarArrow struct {
+	Panel            // Embedded panel
+	icon  Label      // arrow icon
+	sb    *ScrollBar // pointer to parent scroll bar
+	steps float32    // number of steps scrolled when clicked
+}
+
 type ScrollBarStyle struct {
 	Paddings     BorderSizes
 	Borders      BorderSizes
@@ -50,6 +79,8 @@ type ScrollBarButtonStyle struct {
 	BordersColor math32.Color4
 	Color        math32.Color
 	Size         float32
+	MinSize      float32 // minimum size of a proportional button
+	IconColor    math32.Color
 }
 
 // NewVScrollBar creates and returns a pointer to a new vertical scroll bar
@@ -82,6 +113,9 @@ func (sb *ScrollBar) initialize(width, height float32, vertical bool) {
 	sb.vertical = vertical
 	sb.Panel.Initialize(width, height)
 	sb.Panel.Subscribe(OnMouseDown, sb.onMouse)
+	sb.Panel.Subscribe(OnKeyDown, sb.onKey)
+	sb.Panel.Subscribe(OnKeyRepeat, sb.onKey)
+	sb.Panel.Subscribe(OnResize, func(evname string, ev interface{}) { sb.recalc() })
 
 	// Initialize scrollbar button
 	sb.button.Panel.Initialize(0, 0)
@@ -92,63 +126,235 @@ func (sb *ScrollBar) initialize(width, height float32, vertical bool) {
 	sb.button.sb = sb
 	sb.Add(&sb.button)
 
+	// Initialize arrow buttons which are hidden by default
+	sb.arrowDec.initialize(sb, -1)
+	sb.arrowInc.initialize(sb, 1)
+
+	sb.update()
+	sb.recalc()
+}
+
+// SetOrientation sets the orientation of this scroll bar
+func (sb *ScrollBar) SetOrientation(orientation Orientation) {
+
+	sb.vertical = orientation == Vertical
+	sb.update()
+	sb.recalc()
+}
+
+// Orientation returns the current orientation of this scroll bar
+func (sb *ScrollBar) Orientation() Orientation {
+
+	if sb.vertical {
+		return Vertical
+	}
+	return Horizontal
+}
+
+// SetRange sets the total size of the scrolled range and the size of its
+// visible part. The size of the button is proportional to the visible
+// fraction of the total. If the total is zero (the default)
+// the button has the fixed size of the current style.
+func (sb *ScrollBar) SetRange(total, visible float32) {
+
+	sb.total = math32.Max(total, 0)
+	sb.visible = math32.Clamp(visible, 0, sb.total)
+	sb.recalc()
+}
+
+// Range returns the total size of the scrolled range and the size of its visible part
+func (sb *ScrollBar) Range() (float32, float32) {
+
+	return sb.total, sb.visible
+}
+
+// SetStep sets the value change from 0.0 to 1.0 caused by the arrow
+// buttons and keys. If zero (the default) the step is a tenth of a page.
+func (sb *ScrollBar) SetStep(step float32) {
+
+	sb.step = math32.Max(step, 0)
+}
+
+// Step returns the value change caused by the arrow buttons and keys
+func (sb *ScrollBar) Step() float32 {
+
+	if sb.step > 0 {
+		return sb.step
+	}
+	return sb.page() / 10
+}
+
+// SetArrows sets the visibility of the arrow buttons at the ends of this scroll bar
+func (sb *ScrollBar) SetArrows(state bool) {
+
+	sb.arrows = state
+	sb.arrowDec.SetVisible(state)
+	sb.arrowInc.SetVisible(state)
 	sb.update()
 	sb.recalc()
 }
 
+// Arrows returns the visibility of the arrow buttons
+func (sb *ScrollBar) Arrows() bool {
+
+	return sb.arrows
+}
+
 // Value returns the current position of the button in the scrollbar
 // The returned value is between 0.0 and 1.0
 func (sb *ScrollBar) Value() float64 {
 
-	if sb.vertical {
-		return float64(sb.button.Position().Y) / (float64(sb.content.Height) - float64(sb.button.height))
-	} else {
-		return float64(sb.button.Position().X) / (float64(sb.content.Width) - float64(sb.button.width))
-	}
+	return float64(sb.value)
 }
 
 // SetValue sets the position of the button of the scrollbar
 // from 0.0 (minimum) to 1.0 (maximum).
 func (sb *ScrollBar) SetValue(v float32) {
 
+	sb.value = math32.Clamp(v, 0.0, 1.0)
+	sb.setButtonPos()
+}
+
+// setValue sets the current value and dispatches OnChange if it changed
+func (sb *ScrollBar) setValue(v float32) {
+
 	v = math32.Clamp(v, 0.0, 1.0)
+	if v == sb.value {
+		return
+	}
+	sb.value = v
+	sb.setButtonPos()
+	sb.Dispatch(OnChange, sb.Value())
+}
+
+// track returns the start position and the length of the area
+// where the button can move
+func (sb *ScrollBar) track() (float32, float32) {
+
+	var start, length float32
+	if sb.vertical {
+		length = sb.content.Height
+		if sb.arrows {
+			start = sb.content.Width
+		}
+	} else {
+		length = sb.content.Width
+		if sb.arrows {
+			start = sb.content.Height
+		}
+	}
+	return start, math32.Max(length-2*start, 0)
+}
+
+// buttonLength returns the length of the button along the scroll bar
+func (sb *ScrollBar) buttonLength() float32 {
+
+	if sb.vertical {
+		return sb.button.height
+	}
+	return sb.button.width
+}
+
+// page returns the value change for one page which corresponds
+// to the length of the button in relation to its free track length
+func (sb *ScrollBar) page() float32 {
+
+	_, length := sb.track()
+	free := length - sb.buttonLength()
+	if free <= 0 {
+		return 1
+	}
+	return sb.buttonLength() / free
+}
+
+// setButtonPos sets the position of the button from the current value
+func (sb *ScrollBar) setButtonPos() {
+
+	start, length := sb.track()
+	pos := start + sb.value*math32.Max(length-sb.buttonLength(), 0)
 	if sb.vertical {
-		pos := v * (float32(sb.content.Height) - float32(sb.button.height))
 		sb.button.SetPositionY(pos)
 	} else {
-		pos := v * (float32(sb.content.Width) - float32(sb.button.width))
 		sb.button.SetPositionX(pos)
 	}
 }
 
 // onMouse receives subscribed mouse events over the scrollbar outer panel
+// and scrolls one page in the direction of the click
 func (sb *ScrollBar) onMouse(evname string, ev interface{}) {
 
 	e := ev.(*window.MouseEvent)
 	if e.Button != window.MouseButtonLeft {
 		return
 	}
+	if sb.Focusable() {
+		sb.root.SetKeyFocus(sb)
+	}
+	cx, cy := sb.ContentCoords(e.Xpos, e.Ypos)
+	click := cx
+	pos := sb.button.Position().X
 	if sb.vertical {
-		posy := e.Ypos - sb.pospix.Y
-		newY := math32.Clamp(posy-(sb.button.height/2), 0, sb.content.Height-sb.button.height)
-		sb.button.SetPositionY(newY)
-	} else {
-		posx := e.Xpos - sb.pospix.X
-		newX := math32.Clamp(posx-(sb.button.width/2), 0, sb.content.Width-sb.button.width)
-		sb.button.SetPositionX(newX)
+		click = cy
+		pos = sb.button.Position().Y
+	}
+	if click < pos {
+		sb.setValue(sb.value - sb.page())
+	} else if click > pos+sb.buttonLength() {
+		sb.setValue(sb.value + sb.page())
 	}
 	sb.root.StopPropagation(StopAll)
-	sb.Dispatch(OnChange, nil)
+}
+
+// onKey receives subscribed key events when this scroll bar has the key focus
+func (sb *ScrollBar) onKey(evname string, ev interface{}) {
+
+	kev := ev.(*window.KeyEvent)
+	switch kev.Keycode {
+	case window.KeyUp, window.KeyLeft:
+		sb.setValue(sb.value - sb.Step())
+	case window.KeyDown, window.KeyRight:
+		sb.setValue(sb.value + sb.Step())
+	case window.KeyPageUp:
+		sb.setValue(sb.value - sb.page())
+	case window.KeyPageDown:
+		sb.setValue(sb.value + sb.page())
+	case window.KeyHome:
+		sb.setValue(0)
+	case window.KeyEnd:
+		sb.setValue(1)
+	default:
+		return
+	}
+	sb.root.StopPropagation(Stop3D)
 }
 
 // recalc recalculates sizes and positions
 func (sb *ScrollBar) recalc() {
 
+	start, length := sb.track()
+	size := sb.style.Button.Size
+	if sb.total > 0 {
+		size = math32.Max(length*sb.visible/sb.total, sb.style.Button.MinSize)
+	}
+	size = math32.Min(size, length)
 	if sb.vertical {
-		sb.button.SetSize(sb.content.Width, sb.style.Button.Size)
+		sb.button.SetSize(sb.content.Width, size)
+		sb.button.SetPositionX(0)
+		sb.arrowDec.SetPosition(0, 0)
+		sb.arrowDec.SetSize(sb.content.Width, start)
+		sb.arrowInc.SetPosition(0, start+length)
+		sb.arrowInc.SetSize(sb.content.Width, start)
 	} else {
-		sb.button.SetSize(sb.style.Button.Size, sb.content.Height)
+		sb.button.SetSize(size, sb.content.Height)
+		sb.button.SetPositionY(0)
+		sb.arrowDec.SetPosition(0, 0)
+		sb.arrowDec.SetSize(start, sb.content.Height)
+		sb.arrowInc.SetPosition(start+length, 0)
+		sb.arrowInc.SetSize(start, sb.content.Height)
 	}
+	sb.arrowDec.recalc()
+	sb.arrowInc.recalc()
+	sb.setButtonPos()
 }
 
 // update updates border sizes and colors
@@ -162,6 +368,9 @@ func (sb *ScrollBar) update() {
 	sb.button.SetBordersFrom(&sb.style.Button.Borders)
 	sb.button.SetBordersColor4(&sb.style.Button.BordersColor)
 	sb.button.SetColor(&sb.style.Button.Color)
+
+	sb.arrowDec.update()
+	sb.arrowInc.update()
 }
 
 // onMouse receives subscribed mouse events for the scroll bar button
@@ -177,6 +386,9 @@ func (button *scrollBarButton) onMouse(evname string, ev interface{}) {
 		button.mouseX = e.Xpos
 		button.mouseY = e.Ypos
 		button.sb.root.SetMouseFocus(button)
+		if button.sb.Focusable() {
+			button.sb.root.SetKeyFocus(button.sb)
+		}
 	case OnMouseUp:
 		button.pressed = false
 		button.sb.root.SetMouseFocus(nil)
@@ -193,17 +405,73 @@ func (button *scrollBarButton) onCursor(evname string, ev interface{}) {
 	if !button.pressed {
 		return
 	}
-	if button.sb.vertical {
-		dy := button.mouseY - e.Ypos
-		py := button.Position().Y
-		button.SetPositionY(math32.Clamp(py-dy, 0, button.sb.content.Height-button.sb.style.Button.Size))
-	} else {
-		dx := button.mouseX - e.Xpos
-		px := button.Position().X
-		button.SetPositionX(math32.Clamp(px-dx, 0, button.sb.content.Width-button.sb.style.Button.Size))
+	sb := button.sb
+	start, length := sb.track()
+	free := length - sb.buttonLength()
+	if free > 0 {
+		var pos float32
+		if sb.vertical {
+			pos = button.Position().Y + e.Ypos - button.mouseY
+		} else {
+			pos = button.Position().X + e.Xpos - button.mouseX
+		}
+		sb.setValue((pos - start) / free)
 	}
 	button.mouseX = e.Xpos
 	button.mouseY = e.Ypos
-	button.sb.Dispatch(OnChange, nil)
-	button.sb.root.StopPropagation(StopAll)
+	sb.root.StopPropagation(StopAll)
+}
+
+// initialize initializes this arrow button which scrolls
+// the specified number of steps when clicked
+func (a *scrollBarArrow) initialize(sb *ScrollBar, steps float32) {
+
+	a.Panel.Initialize(0, 0)
+	a.sb = sb
+	a.steps = steps
+	a.icon.initialize("", StyleDefault.FontIcon)
+	a.Panel.Add(&a.icon)
+	a.Panel.Subscribe(OnMouseDown, a.onMouse)
+	a.Panel.SetVisible(false)
+	sb.Add(a)
+}
+
+// onMouse receives subscribed mouse events for the arrow button
+func (a *scrollBarArrow) onMouse(evname string, ev interface{}) {
+
+	e := ev.(*window.MouseEvent)
+	if e.Button != window.MouseButtonLeft {
+		return
+	}
+	if a.sb.Focusable() {
+		a.sb.root.SetKeyFocus(a.sb)
+	}
+	a.sb.setValue(a.sb.value + a.steps*a.sb.Step())
+	a.sb.root.StopPropagation(StopAll)
+}
+
+// update updates the visual state of the arrow button
+func (a *scrollBarArrow) update() {
+
+	s := &a.sb.style.Button
+	a.SetBordersFrom(&s.Borders)
+	a.SetBordersColor4(&s.BordersColor)
+	a.SetColor(&a.sb.style.Color)
+	icode := assets.KeyboardArrowLeft
+	switch {
+	case a.sb.vertical && a.steps < 0:
+		icode = assets.KeyboardArrowUp
+	case a.sb.vertical:
+		icode = assets.KeyboardArrowDown
+	case a.steps > 0:
+		icode = assets.KeyboardArrowRight
+	}
+	a.icon.SetText(string(rune(icode)))
+	a.icon.SetColor(&s.IconColor)
+}
+
+// recalc centers the icon in the arrow button
+func (a *scrollBarArrow) recalc() {
+
+	a.icon.SetPosition((a.ContentWidth()-a.icon.Width())/2, (a.ContentHeight()-a.icon.Height())/2)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/window"
 	"math"
+	"time"
 )
 
 type Scroller struct {
@@ -24,7 +25,31 @@ type Scroller struct {
 	focus          bool            // has keyboard focus
 	cursorOver     bool            // mouse is over the list
 	scrollBarEvent bool
-}
+	inertia        bool      // inertial scrolling enabled flag
+	friction       float32   // inertial scrolling velocity decay rate per second
+	velocity       float32   // current inertial scrolling velocity in items per second
+	spos           float32   // current fractional scroll position in items
+	shift          float32   // current scroll shift of the items in pixels
+	animID         int       // inertial scrolling timer id
+	animTime       time.Time // time of the last inertial scrolling step
+	pressed        bool      // mouse button pressed over the scroller
+	dragging       bool      // content being dragged by the mouse
+	dragStart      float32   // cursor coordinate when the mouse button was pressed
+	dragPos        float32   // last cursor coordinate while dragging
+	dragTime       time.Time // time of the last cursor event while dragging
+}
+
+const (
+	scrollerFriction      = 4     // default inertial scrolling friction
+	scrollerWheelSpeed    = 12    // velocity added by each mouse wheel step in items per second
+	scrollerMinSpeed      = 0.05  // velocity to stop the inertial scrolling in items per second
+	scrollerSnapSpeed     = 1     // velocity to start snapping to the nearest item in items per second
+	scrollerSpring        = 120   // bounce and snap spring constant
+	scrollerDamping       = 22    // bounce and snap spring damping
+	scrollerMaxBounce     = 0.5   // maximum bounce beyond the content bounds in items
+	scrollerMaxStep       = 0.033 // maximum inertial scrolling time step in seconds
+	scrollerDragThreshold = 4     // cursor displacement in pixels to start dragging the content
+)
 
 type ScrollerStyle struct {
 	Border      BorderSizes
@@ -173,6 +198,41 @@ func (s *Scroller) ItemPosition(item IPanel) int {
 	return -1
 }
 
+// SetInertia sets the state of the inertial scrolling mode.
+// In this mode the mouse wheel and the release of the content dragged
+// by the mouse start a scrolling which continues with the last velocity
+// decaying exponentially according to the scroller friction.
+func (s *Scroller) SetInertia(state bool) {
+
+	s.inertia = state
+	if !state {
+		s.stopInertia()
+		s.recalc()
+	}
+}
+
+// Inertia returns the state of the inertial scrolling mode
+func (s *Scroller) Inertia() bool {
+
+	return s.inertia
+}
+
+// SetFriction sets the exponential decay rate per second of the
+// inertial scrolling velocity. Greater values stop the scrolling faster.
+func (s *Scroller) SetFriction(friction float32) {
+
+	if friction < 0 {
+		friction = 0
+	}
+	s.friction = friction
+}
+
+// Friction returns the current inertial scrolling friction
+func (s *Scroller) Friction() float32 {
+
+	return s.friction
+}
+
 // First returns the position of the first visible item
 func (s *Scroller) First() int {
 
@@ -183,6 +243,7 @@ func (s *Scroller) First() int {
 func (s *Scroller) SetFirst(pos int) {
 
 	if pos >= 0 && pos <= s.maxFirst() {
+		s.stopInertia()
 		s.first = pos
 		s.recalc()
 	}
@@ -195,6 +256,7 @@ func (s *Scroller) ScrollDown() {
 	if s.first >= max {
 		return
 	}
+	s.stopInertia()
 	s.first++
 	s.recalc()
 }
@@ -205,6 +267,7 @@ func (s *Scroller) ScrollUp() {
 	if s.first == 0 {
 		return
 	}
+	s.stopInertia()
 	s.first--
 	s.recalc()
 }
@@ -285,9 +348,13 @@ func (s *Scroller) initialize(vert bool, width, height float32) {
 	s.vert = vert
 	s.Panel.Initialize(width, height)
 	s.styles = &StyleDefault.Scroller
+	s.friction = scrollerFriction
 
 	s.Panel.Subscribe(OnCursorEnter, s.onCursor)
 	s.Panel.Subscribe(OnCursorLeave, s.onCursor)
+	s.Panel.Subscribe(OnCursor, s.onCursorPos)
+	s.Panel.Subscribe(OnMouseDown, s.onMouse)
+	s.Panel.Subscribe(OnMouseUp, s.onMouse)
 	s.Panel.Subscribe(OnScroll, s.onScroll)
 	s.Panel.Subscribe(OnResize, s.onResize)
 
@@ -316,7 +383,10 @@ func (s *Scroller) onCursor(evname string, ev interface{}) {
 func (s *Scroller) onScroll(evname string, ev interface{}) {
 
 	sev := ev.(*window.ScrollEvent)
-	if sev.Yoffset > 0 {
+	if s.inertia {
+		s.velocity -= float32(sev.Yoffset) * scrollerWheelSpeed
+		s.startInertia()
+	} else if sev.Yoffset > 0 {
 		s.ScrollUp()
 	} else if sev.Yoffset < 0 {
 		s.ScrollDown()
@@ -324,6 +394,228 @@ func (s *Scroller) onScroll(evname string, ev interface{}) {
 	s.root.StopPropagation(Stop3D)
 }
 
+// onMouse receives subscribed mouse button events over the scroller
+// and starts or ends dragging the content in the inertial scrolling mode
+func (s *Scroller) onMouse(evname string, ev interface{}) {
+
+	if !s.inertia {
+		return
+	}
+	mev := ev.(*window.MouseEvent)
+	if mev.Button != window.MouseButtonLeft {
+		return
+	}
+	switch evname {
+	case OnMouseDown:
+		// Ignores clicks over the scroll bars
+		if s.vscroll != nil && s.vscroll.Visible() && s.vscroll.InsideBorders(mev.Xpos, mev.Ypos) {
+			return
+		}
+		if s.hscroll != nil && s.hscroll.Visible() && s.hscroll.InsideBorders(mev.Xpos, mev.Ypos) {
+			return
+		}
+		// Grabbing the content stops the current scrolling
+		s.pauseInertia()
+		s.pressed = true
+		s.dragging = false
+		s.dragStart = s.axisCoord(mev.Xpos, mev.Ypos)
+		s.dragPos = s.dragStart
+		s.dragTime = time.Now()
+		s.velocity = 0
+	case OnMouseUp:
+		s.pressed = false
+		if !s.dragging {
+			return
+		}
+		s.dragging = false
+		s.root.SetMouseFocus(nil)
+		// Ignores the drag velocity if the cursor was stopped before the release
+		if time.Since(s.dragTime) > 100*time.Millisecond {
+			s.velocity = 0
+		}
+		s.startInertia()
+	}
+}
+
+// onCursorPos receives subscribed cursor position events
+// and drags the content in the inertial scrolling mode
+func (s *Scroller) onCursorPos(evname string, ev interface{}) {
+
+	if !s.pressed {
+		return
+	}
+	cev := ev.(*window.CursorEvent)
+	coord := s.axisCoord(cev.Xpos, cev.Ypos)
+	if !s.dragging {
+		if math32.Abs(coord-s.dragStart) < scrollerDragThreshold {
+			return
+		}
+		s.dragging = true
+		s.root.SetMouseFocus(s)
+	}
+	now := time.Now()
+	dt := float32(now.Sub(s.dragTime).Seconds())
+	s.dragTime = now
+	delta := -(coord - s.dragPos) / s.itemSize(s.first)
+	s.dragPos = coord
+	// Offers resistance when dragged beyond the content bounds
+	if s.spos < 0 || s.spos > float32(s.maxFirst()) {
+		delta /= 2
+	}
+	s.spos += delta
+	if dt > 0 {
+		s.velocity = 0.8*(delta/dt) + 0.2*s.velocity
+	}
+	s.setScrollPos(s.spos)
+	s.root.StopPropagation(Stop3D)
+}
+
+// axisCoord returns the coordinate of the specified position
+// in the scroller orientation axis
+func (s *Scroller) axisCoord(x, y float32) float32 {
+
+	if s.vert {
+		return y
+	}
+	return x
+}
+
+// itemSize returns the size in pixels of the item at the specified position
+// in the scroller orientation axis
+func (s *Scroller) itemSize(pos int) float32 {
+
+	if pos < 0 || pos >= len(s.items) {
+		return 1
+	}
+	var size float32
+	if s.vert {
+		size = s.items[pos].TotalHeight()
+	} else {
+		size = s.items[pos].GetPanel().Width()
+	}
+	if size <= 0 {
+		return 1
+	}
+	return size
+}
+
+// startInertia starts the inertial scrolling animation if not already started
+func (s *Scroller) startInertia() {
+
+	if s.animID != 0 {
+		return
+	}
+	if s.shift == 0 {
+		s.spos = float32(s.first)
+	}
+	s.animTime = time.Now()
+	s.animID = s.root.SetInterval(time.Millisecond, nil, s.onInertia)
+}
+
+// pauseInertia stops the inertial scrolling animation
+// keeping the current scroll position
+func (s *Scroller) pauseInertia() {
+
+	if s.animID != 0 {
+		s.root.ClearTimeout(s.animID)
+		s.animID = 0
+	}
+	if s.shift == 0 {
+		s.spos = float32(s.first)
+	}
+}
+
+// stopInertia stops the inertial scrolling animation and drag
+// and clears the items shift
+func (s *Scroller) stopInertia() {
+
+	if s.animID != 0 {
+		s.root.ClearTimeout(s.animID)
+		s.animID = 0
+	}
+	if s.dragging {
+		s.root.SetMouseFocus(nil)
+	}
+	s.pressed = false
+	s.dragging = false
+	s.velocity = 0
+	s.shift = 0
+}
+
+// onInertia is called at each frame during the inertial scrolling animation
+// and updates the scroll position from the current velocity.
+// Beyond the content bounds and when the velocity is low, a damped spring
+// moves the content to the bound or to the nearest item.
+func (s *Scroller) onInertia(arg interface{}) {
+
+	now := time.Now()
+	dt := float32(now.Sub(s.animTime).Seconds())
+	s.animTime = now
+	if dt <= 0 {
+		return
+	}
+	if dt > scrollerMaxStep {
+		dt = scrollerMaxStep
+	}
+	max := float32(s.maxFirst())
+	s.spos += s.velocity * dt
+	s.velocity *= float32(math.Exp(float64(-s.friction * dt)))
+
+	// Checks for bounce or snap target
+	target := s.spos
+	spring := true
+	if s.spos < 0 {
+		target = 0
+	} else if s.spos > max {
+		target = max
+	} else if math32.Abs(s.velocity) < scrollerSnapSpeed {
+		target = float32(math.Floor(float64(s.spos) + 0.5))
+	} else {
+		spring = false
+	}
+	if spring {
+		s.velocity += ((target-s.spos)*scrollerSpring - s.velocity*scrollerDamping) * dt
+	}
+
+	// Limits the bounce
+	if s.spos < -scrollerMaxBounce {
+		s.spos = -scrollerMaxBounce
+		if s.velocity < 0 {
+			s.velocity = 0
+		}
+	} else if s.spos > max+scrollerMaxBounce {
+		s.spos = max + scrollerMaxBounce
+		if s.velocity > 0 {
+			s.velocity = 0
+		}
+	}
+
+	// Stops when resting at the target
+	if spring && math32.Abs(s.velocity) < scrollerMinSpeed && math32.Abs(target-s.spos) < 0.01 {
+		s.stopInertia()
+		s.first = int(target)
+		s.recalc()
+		return
+	}
+	s.setScrollPos(s.spos)
+}
+
+// setScrollPos sets the first visible item and the items shift
+// from the specified fractional scroll position
+func (s *Scroller) setScrollPos(pos float32) {
+
+	max := s.maxFirst()
+	first := int(math.Floor(float64(pos)))
+	if first < 0 {
+		first = 0
+	} else if first > max {
+		first = max
+	}
+	s.first = first
+	s.shift = (pos - float32(first)) * s.itemSize(first)
+	s.recalc()
+}
+
 // onScroll receives resize events
 func (s *Scroller) onResize(evname string, ev interface{}) {
 
@@ -395,7 +687,7 @@ func (s *Scroller) vRecalc() {
 		width -= s.vscroll.Width()
 	}
 
-	var posY float32 = 0
+	var posY float32 = -s.shift
 	// Sets positions of all items
 	for pos, ipan := range s.items {
 		item := ipan.GetPanel()
@@ -448,7 +740,7 @@ func (s *Scroller) hRecalc() {
 		height -= s.hscroll.Height()
 	}
 
-	var posX float32 = 0
+	var posX float32 = -s.shift
 	// Sets positions of all items
 	for pos, ipan := range s.items {
 		item := ipan.GetPanel()
@@ -586,6 +878,7 @@ func (s *Scroller) onScrollBarEvent(evname string, ev interface{}) {
 	if first == s.first {
 		return
 	}
+	s.stopInertia()
 	s.scrollBarEvent = true
 	s.first = first
 	s.recalc()
@@ -0,0 +1,169 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+// panelShadow is the graphic which renders the drop shadow of a panel.
+// It is not a node of the scene: its material is inserted before the
+// panel material so it is rendered behind the panel and is not
+// considered by the panel layouts.
+type panelShadow struct {
+	*graphic.Graphic                     // Embedded graphic with the shadow quad
+	panel            *Panel              // panel which owns this shadow
+	mat              *material.Material  // shadow material
+	offsetX          float32             // horizontal offset in pixels from the panel borders
+	offsetY          float32             // vertical offset in pixels from the panel borders
+	blur             float32             // blur size in pixels
+	color            math32.Color4       // shadow color
+	modelMatrixUni   gls.UniformMatrix4f // model matrix uniform
+	shadowUni        gls.Uniform4fv      // uniform array with shadow dimensions and color
+}
+
+const (
+	idxShadowRect   = 0 // index of uniform array for shadow rectangle
+	idxShadowBounds = 1 // index of uniform array for clipping bounds
+	idxShadowColor  = 2 // index of uniform array for shadow color
+	idxShadowRadius = 3 // index of uniform array for corner radii
+	idxShadowBlur   = 4 // index of uniform array for blur size
+)
+
+// SetShadow sets the offset in pixels, the blur size in pixels and the color
+// of the drop shadow rendered behind this panel borders.
+// The shadow does not change the panel size or the layout of its siblings.
+// A shadow with a fully transparent color removes the shadow.
+func (p *Panel) SetShadow(offsetX, offsetY, blur float32, color math32.Color4) {
+
+	if color.A <= 0 {
+		if p.shadow != nil {
+			p.Graphic.RemoveMaterial(p.shadow.mat)
+			p.shadow.dispose()
+			p.shadow = nil
+		}
+		return
+	}
+	if p.shadow == nil {
+		p.shadow = newPanelShadow(p)
+		p.Graphic.InsertMaterial(0, p.shadow, p.shadow.mat, 0, 0)
+	}
+	if blur < 0 {
+		blur = 0
+	}
+	p.shadow.offsetX = offsetX
+	p.shadow.offsetY = offsetY
+	p.shadow.blur = blur
+	p.shadow.color = color
+}
+
+// Shadow returns the offset, blur size and color of this panel drop shadow.
+// If the panel has no shadow the returned color is fully transparent.
+func (p *Panel) Shadow() (offsetX, offsetY, blur float32, color math32.Color4) {
+
+	if p.shadow == nil {
+		return 0, 0, 0, math32.Color4{}
+	}
+	return p.shadow.offsetX, p.shadow.offsetY, p.shadow.blur, p.shadow.color
+}
+
+// newPanelShadow creates and returns a pointer to a new shadow for the specified panel
+func newPanelShadow(p *Panel) *panelShadow {
+
+	s := new(panelShadow)
+	s.panel = p
+
+	// Builds the same unit quad used by the panel geometry
+	positions := math32.NewArrayF32(0, 20)
+	positions.Append(
+		0, 0, 0, 0, 1,
+		0, -1, 0, 0, 0,
+		1, -1, 0, 1, 0,
+		1, 0, 0, 1, 1,
+	)
+	indices := math32.NewArrayU32(0, 6)
+	indices.Append(0, 1, 2, 0, 2, 3)
+	geom := geometry.NewGeometry()
+	geom.SetIndices(indices)
+	geom.AddVBO(gls.NewVBO().
+		AddAttrib("VertexPosition", 3).
+		AddAttrib("VertexTexcoord", 2).
+		SetBuffer(positions),
+	)
+	s.Graphic = graphic.NewGraphic(geom, gls.TRIANGLES)
+
+	// The shadow must not hide panels rendered after it
+	s.mat = material.NewMaterial()
+	s.mat.SetShader("shaderPanelShadow")
+	s.mat.SetShaderUnique(true)
+	s.mat.SetDepthMask(false)
+
+	s.modelMatrixUni.Init("ModelMatrix")
+	s.shadowUni.Init("Shadow", 5)
+	return s
+}
+
+// dispose releases the resources used by this shadow
+func (s *panelShadow) dispose() {
+
+	s.Graphic.Dispose()
+	s.mat.Dispose()
+}
+
+// RenderSetup is called by the renderer before drawing the shadow
+func (s *panelShadow) RenderSetup(gl *gls.GLS, rinfo *core.RenderInfo) {
+
+	p := s.panel
+	_, _, width, height := gl.GetViewport()
+	fwidth := float32(width)
+	fheight := float32(height)
+
+	// Shadow rectangle is the panel borders area displaced by the offset
+	x := p.pospix.X + p.marginSizes.Left + s.offsetX
+	y := p.pospix.Y + p.marginSizes.Top + s.offsetY
+	w := p.width - p.marginSizes.Left - p.marginSizes.Right
+	h := p.height - p.marginSizes.Top - p.marginSizes.Bottom
+
+	// The quad is enlarged by the blur size on all sides and is placed
+	// just behind the panel and in front of the previous panels.
+	var scale math32.Vector3
+	scale.Set(2*(w+2*s.blur)/fwidth, 2*(h+2*s.blur)/fheight, 1)
+	var pos math32.Vector3
+	pos.X = (x - s.blur - fwidth/2) / (fwidth / 2)
+	pos.Y = -(y - s.blur - fheight/2) / (fheight / 2)
+	pos.Z = p.Position().Z - deltaZ/2
+	var quat math32.Quaternion
+	quat.SetIdentity()
+	var mm math32.Matrix4
+	mm.Compose(&pos, &quat, &scale)
+	s.modelMatrixUni.SetMatrix4(&mm)
+
+	// Clips the shadow to the parent content area of bounded panels
+	xmin := float32(0)
+	ymin := float32(0)
+	xmax := fwidth
+	ymax := fheight
+	par, ok := p.Parent().(*Panel)
+	if ok && p.bounded {
+		xmin, ymin, xmax, ymax = par.contentBounds()
+	}
+
+	// Sets uniforms in window coordinates which have the y axis pointing up
+	s.shadowUni.Set(idxShadowRect, x, fheight-y-h, w, h)
+	s.shadowUni.Set(idxShadowBounds, xmin, fheight-ymax, xmax, fheight-ymin)
+	s.shadowUni.SetColor4(idxShadowColor, &s.color)
+	r := p.panUni.GetColor4(idxRadius)
+	s.shadowUni.SetColor4(idxShadowRadius, &r)
+	s.shadowUni.Set(idxShadowBlur, s.blur, 0, 0, 0)
+
+	// Transfer uniforms
+	s.shadowUni.Transfer(gl)
+	s.modelMatrixUni.Transfer(gl)
+}
@@ -34,6 +34,7 @@ type Slider struct {
 	pressed     bool          // mouse button is pressed and dragging
 	cursorOver  bool          // mouse is over slider
 	scaleFactor float32       // scale factor (default = 1.0)
+	focus       bool          // slider has the key focus
 }
 
 // SliderStyle
@@ -88,6 +89,11 @@ func newSlider(horiz bool, width, height float32) *Slider {
 	s.Panel.Subscribe(OnKeyRepeat, s.onKey)
 	s.Panel.Subscribe(OnResize, s.onResize)
 	s.Panel.Subscribe(OnEnable, func(evname string, ev interface{}) { s.update() })
+	s.Panel.Subscribe(OnFocus, func(evname string, ev interface{}) {
+		s.focus = true
+		s.update()
+	})
+	s.Panel.SetFocusable(true)
 
 	// Initialize slider panel
 	s.slider.Initialize(0, 0)
@@ -118,6 +124,35 @@ func (s *Slider) SetText(text string) {
 	s.recalc()
 }
 
+// SetOrientation sets the orientation of the slider.
+// For vertical sliders the value increases from the bottom to the top.
+func (s *Slider) SetOrientation(orientation Orientation) {
+
+	horiz := orientation == Horizontal
+	if horiz == s.horiz {
+		return
+	}
+	s.horiz = horiz
+	s.slider.SetPosition(0, 0)
+	if s.cursorOver {
+		if s.horiz {
+			s.root.SetCursorHResize()
+		} else {
+			s.root.SetCursorVResize()
+		}
+	}
+	s.recalc()
+}
+
+// Orientation returns the current orientation of the slider
+func (s *Slider) Orientation() Orientation {
+
+	if s.horiz {
+		return Horizontal
+	}
+	return Vertical
+}
+
 // SetValue sets the value of the slider considering the current scale factor
 // and updates its visual appearance.
 func (s *Slider) SetValue(value float32) {
@@ -146,6 +181,7 @@ func (s *Slider) ScaleFactor() float32 {
 
 // setPos sets the slider position from 0.0 to 1.0
 // and updates its visual appearance.
+// OnChange is dispatched with the normalized position.
 func (s *Slider) setPos(pos float32) {
 
 	const eps = 0.01
@@ -159,7 +195,7 @@ func (s *Slider) setPos(pos float32) {
 	}
 	s.pos = pos
 	s.recalc()
-	s.Dispatch(OnChange, nil)
+	s.Dispatch(OnChange, s.pos)
 }
 
 // onMouse process subscribed mouse events over the outer panel
@@ -227,6 +263,14 @@ func (s *Slider) onCursor(evname string, ev interface{}) {
 	s.root.StopPropagation(Stop3D)
 }
 
+// LostKeyFocus satisfies the IPanel interface and is called by gui root
+// container when the panel loses the key focus
+func (s *Slider) LostKeyFocus() {
+
+	s.focus = false
+	s.update()
+}
+
 // onScroll process subscribed scroll events
 func (s *Slider) onScroll(evname string, ev interface{}) {
 
@@ -283,6 +327,10 @@ func (s *Slider) update() {
 		s.applyStyle(&s.styles.Over)
 		return
 	}
+	if s.focus {
+		s.applyStyle(&s.styles.Focus)
+		return
+	}
 	s.applyStyle(&s.styles.Normal)
 }
 
@@ -0,0 +1,468 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/g3n/engine/gui/assets"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+/***************************************
+
+ Spinner
+ +-------------------------+---+
+ |                         | ^ |
+ |  edit                   +---+
+ |                         | v |
+ +-------------------------+---+
+
+**/
+
+// Spinner is a widget for bounded numeric entry composed of an edit field
+// and up/down arrow buttons. The arrow buttons, the up/down keys and the
+// mouse wheel increment or decrement the value by the current step clamped
+// to the current range. Typed values are validated and clamped when the
+// Enter key is pressed or the edit loses the key focus.
+// OnChange is dispatched with the new float64 value when the value is
+// changed by the user.
+type Spinner struct {
+	Panel                   // Embedded panel
+	edit     *spinnerEdit   // edit field
+	up       spinnerButton  // increment button
+	down     spinnerButton  // decrement button
+	styles   *SpinnerStyles // pointer to current styles
+	value    float64        // current value
+	min      float64        // minimum value
+	max      float64        // maximum value
+	step     float64        // increment and decrement step
+	decimals int            // number of decimals displayed
+	pressed  *spinnerButton // button currently pressed (nil = none)
+	repID    int            // id of the auto repeat timer (0 = none)
+}
+
+// SpinnerStyle contains the styling of the Spinner arrow buttons
+type SpinnerStyle struct {
+	Border      BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color4
+	FgColor     math32.Color
+}
+
+// SpinnerStyles contains all the Spinner arrow buttons styles
+type SpinnerStyles struct {
+	Normal   SpinnerStyle
+	Over     SpinnerStyle
+	Pressed  SpinnerStyle
+	Disabled SpinnerStyle
+}
+
+// spinnerEdit is the edit field of the spinner
+type spinnerEdit struct {
+	*Edit          // embedded edit
+	sp    *Spinner // parent spinner
+}
+
+// spinnerButton is an arrow button of the spinner
+type spinnerButton struct {
+	Panel           // embedded panel
+	icon    *Label  // arrow icon
+	delta   float64 // number of steps added when clicked
+	over    bool    // cursor is over the button
+	pressed bool    // button is pressed
+}
+
+const (
+	spinnerButtonWidth = 16                     // width of the arrow buttons in pixels
+	spinnerDelay       = 500 * time.Millisecond // delay before the auto repeat starts
+	spinnerInterval    = 80 * time.Millisecond  // auto repeat interval
+)
+
+// NewSpinner creates and returns a pointer to a new spinner with the
+// specified width in pixels of the edit field. The initial range is from
+// 0 to 100 with step 1, no decimals and value 0.
+func NewSpinner(width int) *Spinner {
+
+	sp := new(Spinner)
+	sp.styles = &StyleDefault.Spinner
+	sp.max = 100
+	sp.step = 1
+	sp.Panel.Initialize(0, 0)
+	sp.Panel.Subscribe(OnCursorEnter, sp.onCursor)
+	sp.Panel.Subscribe(OnCursorLeave, sp.onCursor)
+	sp.Panel.Subscribe(OnScroll, sp.onScroll)
+	sp.Panel.Subscribe(OnEnable, func(evname string, ev interface{}) {
+		sp.edit.SetEnabled(sp.Enabled())
+		sp.update()
+	})
+
+	// Creates the edit field
+	sp.edit = &spinnerEdit{Edit: NewEdit(width, ""), sp: sp}
+	sp.edit.SetValidator(sp.validate)
+	sp.edit.Subscribe(OnKeyDown, sp.edit.onKey)
+	sp.edit.Subscribe(OnKeyRepeat, sp.edit.onKey)
+	sp.edit.Subscribe(OnMouseDown, sp.edit.onMouse)
+	sp.Panel.Add(sp.edit)
+
+	// Creates the arrow buttons
+	sp.up.initialize(sp, assets.ArrowDropUp, 1)
+	sp.down.initialize(sp, assets.ArrowDropDown, -1)
+
+	sp.refresh()
+	sp.update()
+	sp.recalc()
+	return sp
+}
+
+// SetValue sets the current value of the spinner clamped to the current range
+// without dispatching OnChange
+func (sp *Spinner) SetValue(v float64) *Spinner {
+
+	sp.value = sp.clamp(v)
+	sp.refresh()
+	return sp
+}
+
+// Value returns the current value of the spinner
+func (sp *Spinner) Value() float64 {
+
+	return sp.value
+}
+
+// SetRange sets the minimum and maximum values of the spinner
+// and clamps the current value to the new range
+func (sp *Spinner) SetRange(min, max float64) *Spinner {
+
+	if min > max {
+		log.Warn("Spinner minimum value greater than maximum value")
+		return sp
+	}
+	sp.min = min
+	sp.max = max
+	sp.value = sp.clamp(sp.value)
+	sp.refresh()
+	return sp
+}
+
+// Range returns the minimum and maximum values of the spinner
+func (sp *Spinner) Range() (float64, float64) {
+
+	return sp.min, sp.max
+}
+
+// SetStep sets the increment and decrement step of the spinner
+func (sp *Spinner) SetStep(step float64) *Spinner {
+
+	if step <= 0 {
+		log.Warn("Spinner step must be positive")
+		return sp
+	}
+	sp.step = step
+	return sp
+}
+
+// Step returns the increment and decrement step of the spinner
+func (sp *Spinner) Step() float64 {
+
+	return sp.step
+}
+
+// SetDecimals sets the number of decimals used to display the value.
+// The value is rounded to this number of decimals.
+func (sp *Spinner) SetDecimals(decimals int) *Spinner {
+
+	if decimals < 0 {
+		decimals = 0
+	}
+	sp.decimals = decimals
+	sp.value = sp.clamp(sp.value)
+	sp.refresh()
+	return sp
+}
+
+// Decimals returns the number of decimals used to display the value
+func (sp *Spinner) Decimals() int {
+
+	return sp.decimals
+}
+
+// SetStyles sets the spinner arrow buttons styles overriding the default style
+func (sp *Spinner) SetStyles(ss *SpinnerStyles) {
+
+	sp.styles = ss
+	sp.update()
+}
+
+// Dispose overrides the embedded panel Dispose method
+// and stops the auto repeat timer if necessary
+func (sp *Spinner) Dispose() {
+
+	sp.stopRepeat()
+	sp.Panel.Dispose()
+}
+
+// clamp returns the specified value rounded to the number
+// of decimals and clamped to the current range
+func (sp *Spinner) clamp(v float64) float64 {
+
+	scale := math.Pow(10, float64(sp.decimals))
+	v = math.Floor(v*scale+0.5) / scale
+	return math.Max(sp.min, math.Min(sp.max, v))
+}
+
+// change changes the current value to the specified value clamped to the
+// current range and dispatches OnChange if the value was changed
+func (sp *Spinner) change(v float64) {
+
+	v = sp.clamp(v)
+	if v == sp.value {
+		sp.refresh()
+		return
+	}
+	sp.value = v
+	sp.refresh()
+	sp.Dispatch(OnChange, sp.value)
+}
+
+// increment adds the specified number of steps to the current value.
+// Text being typed in the edit field is committed first.
+func (sp *Spinner) increment(delta float64) {
+
+	if !sp.Enabled() {
+		return
+	}
+	sp.commit()
+	sp.change(sp.value + delta*sp.step)
+}
+
+// commit validates the text of the edit field and changes the current value.
+// Invalid text restores the current value.
+func (sp *Spinner) commit() {
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(sp.edit.Text()), 64)
+	if err != nil {
+		sp.refresh()
+		return
+	}
+	sp.change(v)
+}
+
+// refresh sets the text of the edit field from the current value
+func (sp *Spinner) refresh() {
+
+	text := strconv.FormatFloat(sp.value, 'f', sp.decimals, 64)
+	if text != sp.edit.Text() {
+		sp.edit.SetText(text)
+	}
+}
+
+// validate accepts only the characters of a number in the edit field.
+// The minus sign is only accepted at the start for negative ranges.
+func (sp *Spinner) validate(r rune, current string) bool {
+
+	// Nothing is inserted before the minus sign
+	if sp.edit.col == 0 && strings.HasPrefix(current, "-") {
+		return false
+	}
+	switch {
+	case r >= '0' && r <= '9':
+		return true
+	case r == '-':
+		return sp.min < 0 && sp.edit.col == 0
+	case r == '.':
+		return sp.decimals > 0 && !strings.Contains(current, ".")
+	}
+	return false
+}
+
+// onCursor process subscribed cursor events over the spinner
+func (sp *Spinner) onCursor(evname string, ev interface{}) {
+
+	switch evname {
+	case OnCursorEnter:
+		sp.root.SetScrollFocus(sp)
+	case OnCursorLeave:
+		sp.root.SetScrollFocus(nil)
+	}
+}
+
+// onScroll process subscribed scroll events
+func (sp *Spinner) onScroll(evname string, ev interface{}) {
+
+	sev := ev.(*window.ScrollEvent)
+	if sev.Yoffset > 0 {
+		sp.increment(1)
+	} else if sev.Yoffset < 0 {
+		sp.increment(-1)
+	}
+	sp.root.StopPropagation(Stop3D)
+}
+
+// startRepeat starts the auto repeat timer of the pressed button
+func (sp *Spinner) startRepeat() {
+
+	sp.stopRepeat()
+	if sp.root == nil {
+		return
+	}
+	sp.repID = sp.root.SetTimeout(spinnerDelay, nil, func(arg interface{}) {
+		sp.repID = 0
+		if sp.pressed == nil {
+			return
+		}
+		sp.repID = sp.root.SetInterval(spinnerInterval, nil, func(arg interface{}) {
+			if sp.pressed != nil {
+				sp.increment(sp.pressed.delta)
+			}
+		})
+	})
+}
+
+// stopRepeat cancels the auto repeat timer if any
+func (sp *Spinner) stopRepeat() {
+
+	if sp.repID == 0 {
+		return
+	}
+	sp.root.ClearTimeout(sp.repID)
+	sp.repID = 0
+}
+
+// update updates the visual state of the arrow buttons
+func (sp *Spinner) update() {
+
+	sp.up.update(sp)
+	sp.down.update(sp)
+}
+
+// recalc recalculates the positions and sizes of the edit field
+// and the arrow buttons
+func (sp *Spinner) recalc() {
+
+	width := sp.edit.Width()
+	height := sp.edit.Height()
+	sp.edit.SetPosition(0, 0)
+	sp.up.SetPosition(width, 0)
+	sp.up.SetSize(spinnerButtonWidth, math32.Ceil(height/2))
+	sp.down.SetPosition(width, sp.up.Height())
+	sp.down.SetSize(spinnerButtonWidth, height-sp.up.Height())
+	sp.up.recalc()
+	sp.down.recalc()
+	sp.SetContentSize(width+spinnerButtonWidth, height)
+}
+
+// LostKeyFocus is called by the gui root panel when the
+// edit field loses the key focus and commits the typed value
+func (se *spinnerEdit) LostKeyFocus() {
+
+	se.Edit.LostKeyFocus()
+	se.sp.commit()
+}
+
+// onKey receives subscribed key events for the edit field
+func (se *spinnerEdit) onKey(evname string, ev interface{}) {
+
+	kev := ev.(*window.KeyEvent)
+	switch kev.Keycode {
+	case window.KeyEnter, window.KeyKPEnter:
+		se.sp.commit()
+	case window.KeyUp:
+		se.sp.increment(1)
+	case window.KeyDown:
+		se.sp.increment(-1)
+	case window.KeyPageUp:
+		se.sp.increment(10)
+	case window.KeyPageDown:
+		se.sp.increment(-10)
+	default:
+		return
+	}
+	se.sp.root.StopPropagation(Stop3D)
+}
+
+// onMouse receives subscribed mouse events for the edit field
+// and keeps the key focus with this field instead of the embedded edit
+func (se *spinnerEdit) onMouse(evname string, ev interface{}) {
+
+	se.sp.root.SetKeyFocus(se)
+}
+
+// initialize initializes this arrow button with the specified icon
+// and number of steps and adds it to the specified spinner
+func (sb *spinnerButton) initialize(sp *Spinner, icode int, delta float64) {
+
+	sb.Panel.Initialize(0, 0)
+	sb.delta = delta
+	sb.icon = NewIconLabel(string(rune(icode)))
+	sb.Panel.Add(sb.icon)
+	sb.Panel.Subscribe(OnMouseDown, func(evname string, ev interface{}) { sb.onMouse(sp, evname, ev) })
+	sb.Panel.Subscribe(OnMouseUp, func(evname string, ev interface{}) { sb.onMouse(sp, evname, ev) })
+	sb.Panel.Subscribe(OnCursorEnter, func(evname string, ev interface{}) { sb.onCursor(sp, evname) })
+	sb.Panel.Subscribe(OnCursorLeave, func(evname string, ev interface{}) { sb.onCursor(sp, evname) })
+	sp.Panel.Add(sb)
+}
+
+// onMouse process subscribed mouse events for this arrow button
+func (sb *spinnerButton) onMouse(sp *Spinner, evname string, ev interface{}) {
+
+	mev := ev.(*window.MouseEvent)
+	if mev.Button != window.MouseButtonLeft || !sp.Enabled() {
+		return
+	}
+	switch evname {
+	case OnMouseDown:
+		sb.pressed = true
+		sp.pressed = sb
+		sp.root.SetMouseFocus(sb)
+		sp.increment(sb.delta)
+		sp.startRepeat()
+	case OnMouseUp:
+		sb.pressed = false
+		sp.pressed = nil
+		sp.root.SetMouseFocus(nil)
+		sp.stopRepeat()
+	}
+	sb.update(sp)
+	sp.root.StopPropagation(Stop3D)
+}
+
+// onCursor process subscribed cursor events for this arrow button
+func (sb *spinnerButton) onCursor(sp *Spinner, evname string) {
+
+	sb.over = evname == OnCursorEnter
+	sb.update(sp)
+}
+
+// update updates the visual state of this arrow button
+func (sb *spinnerButton) update(sp *Spinner) {
+
+	s := &sp.styles.Normal
+	switch {
+	case !sp.Enabled():
+		s = &sp.styles.Disabled
+	case sb.pressed:
+		s = &sp.styles.Pressed
+	case sb.over:
+		s = &sp.styles.Over
+	}
+	sb.SetBordersFrom(&s.Border)
+	sb.SetBordersColor4(&s.BorderColor)
+	sb.SetColor4(&s.BgColor)
+	sb.icon.SetColor(&s.FgColor)
+	sb.recalc()
+}
+
+// recalc centers the arrow icon in this button
+func (sb *spinnerButton) recalc() {
+
+	px := (sb.ContentWidth() - sb.icon.Width()) / 2
+	py := (sb.ContentHeight() - sb.icon.Height()) / 2
+	sb.icon.SetPosition(px, py)
+}
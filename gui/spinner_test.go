@@ -0,0 +1,41 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"testing"
+)
+
+func TestSpinnerValidate(t *testing.T) {
+
+	cases := []struct {
+		min  float64
+		text string
+		col  int
+		r    rune
+		ok   bool
+	}{
+		{-10, "", 0, '-', true},
+		{-10, "5", 0, '-', true},
+		{-10, "5", 1, '-', false},
+		{-10, "-5", 0, '-', false},
+		{-10, "-5", 0, '3', false},
+		{-10, "-5", 1, '3', true},
+		{0, "5", 0, '-', false},
+		{0, "5", 1, '.', true},
+		{0, "5.2", 3, '.', false},
+		{0, "5", 1, 'x', false},
+	}
+	for _, c := range cases {
+		sp := NewSpinner(80)
+		sp.SetRange(c.min, 10)
+		sp.SetDecimals(1)
+		sp.edit.SetText(c.text)
+		sp.edit.CursorPos(c.col)
+		if ok := sp.validate(c.r, c.text); ok != c.ok {
+			t.Errorf("min %v text %q col %d: validate(%q) = %v, want %v", c.min, c.text, c.col, c.r, ok, c.ok)
+		}
+	}
+}
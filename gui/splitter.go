@@ -5,10 +5,17 @@
 package gui
 
 import (
+	"time"
+
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/window"
 )
 
+// Splitter is a panel which contains two child panels separated by a
+// spacer panel which can be dragged to resize them.
+// OnChange is dispatched with the new float32 split position when the
+// user drags the spacer. Double clicking the spacer collapses the panel
+// nearest to it or restores the previous split position.
 type Splitter struct {
 	Panel                     // Embedded panel
 	P0        Panel           // Left/Top panel
@@ -20,6 +27,11 @@ type Splitter struct {
 	posLast   float32         // last position in pixels of the mouse cursor when dragging
 	pressed   bool            // mouse button is pressed and dragging
 	mouseOver bool            // mouse is over the spacer panel
+	min0      float32         // minimum size in pixels of the left/top panel
+	min1      float32         // minimum size in pixels of the right/bottom panel
+	collapsed bool            // one of the panels is collapsed
+	restore   float32         // split position restored after a collapse
+	clickTime time.Time       // time of the last mouse click over the spacer panel
 }
 
 type SplitterStyle struct {
@@ -34,6 +46,10 @@ type SplitterStyles struct {
 	Drag   SplitterStyle
 }
 
+const (
+	splitterDblClickTime = 500 * time.Millisecond // maximum interval between the clicks of a double click
+)
+
 // NewHSplitter creates and returns a pointer to a new horizontal splitter
 // widget with the specified initial dimensions
 func NewHSplitter(width, height float32) *Splitter {
@@ -89,9 +105,11 @@ func newSplitter(horiz bool, width, height float32) *Splitter {
 }
 
 // SetSplit sets the position of the splitter bar.
-// It accepts a value from 0.0 to 1.0
+// It accepts a value from 0.0 to 1.0 which is limited
+// by the minimum sizes of the panels.
 func (s *Splitter) SetSplit(pos float32) {
 
+	s.collapsed = false
 	s.setSplit(pos)
 	s.recalc()
 }
@@ -103,6 +121,59 @@ func (s *Splitter) Split() float32 {
 	return s.pos
 }
 
+// SetOrientation sets the orientation of the splitter.
+// A Horizontal splitter has its panels side by side.
+func (s *Splitter) SetOrientation(orientation Orientation) {
+
+	horiz := orientation == Horizontal
+	if horiz == s.horiz {
+		return
+	}
+	s.horiz = horiz
+	if horiz {
+		s.spacer.SetBorders(0, 1, 0, 1)
+	} else {
+		s.spacer.SetBorders(1, 0, 1, 0)
+	}
+	s.update()
+	s.recalc()
+}
+
+// Orientation returns the current orientation of the splitter
+func (s *Splitter) Orientation() Orientation {
+
+	if s.horiz {
+		return Horizontal
+	}
+	return Vertical
+}
+
+// SetMinSizes sets the minimum sizes in pixels of the left/top panel
+// and of the right/bottom panel. The default minimum sizes are zero.
+func (s *Splitter) SetMinSizes(min0, min1 float32) {
+
+	s.min0 = math32.Max(min0, 0)
+	s.min1 = math32.Max(min1, 0)
+	if !s.collapsed {
+		s.setSplit(s.pos)
+	}
+	s.recalc()
+}
+
+// MinSizes returns the minimum sizes in pixels of the left/top panel
+// and of the right/bottom panel.
+func (s *Splitter) MinSizes() (float32, float32) {
+
+	return s.min0, s.min1
+}
+
+// Collapsed returns if one of the panels is collapsed
+// by a double click over the spacer panel
+func (s *Splitter) Collapsed() bool {
+
+	return s.collapsed
+}
+
 // onResize receives subscribed resize events for the whole splitter panel
 func (s *Splitter) onResize(evname string, ev interface{}) {
 
@@ -115,6 +186,14 @@ func (s *Splitter) onMouse(evname string, ev interface{}) {
 	mev := ev.(*window.MouseEvent)
 	switch evname {
 	case OnMouseDown:
+		// Checks for double click to collapse or restore a panel
+		now := time.Now()
+		if now.Sub(s.clickTime) < splitterDblClickTime {
+			s.clickTime = time.Time{}
+			s.toggleCollapse()
+			break
+		}
+		s.clickTime = now
 		s.pressed = true
 		if s.horiz {
 			s.posLast = mev.Xpos
@@ -162,24 +241,97 @@ func (s *Splitter) onCursor(evname string, ev interface{}) {
 			s.posLast = cev.Ypos
 			pos += delta / s.ContentHeight()
 		}
+		prev := s.pos
+		s.collapsed = false
 		s.setSplit(pos)
 		s.recalc()
+		if s.pos != prev {
+			s.Dispatch(OnChange, s.pos)
+		}
 	}
 	s.root.StopPropagation(Stop3D)
 }
 
+// toggleCollapse collapses the panel nearest to the spacer panel
+// or restores the split position before the last collapse
+// and dispatches OnChange with the new split position.
+func (s *Splitter) toggleCollapse() {
+
+	if s.collapsed {
+		s.collapsed = false
+		s.setSplit(s.restore)
+	} else {
+		s.collapsed = true
+		s.restore = s.pos
+		if s.pos < 0.5 {
+			s.pos = 0
+		} else {
+			s.pos = 1
+		}
+	}
+	s.recalc()
+	s.Dispatch(OnChange, s.pos)
+}
+
 // setSplit sets the validated and clamped split position from the received value.
+// The position is also limited by the minimum sizes of the panels.
 func (s *Splitter) setSplit(pos float32) {
 
-	if pos < 0 {
-		s.pos = 0
-	} else if pos > 1 {
-		s.pos = 1
+	lo, hi := s.limits()
+	if pos < lo {
+		s.pos = lo
+	} else if pos > hi {
+		s.pos = hi
 	} else {
 		s.pos = pos
 	}
 }
 
+// limits returns the minimum and maximum split positions
+// allowed by the minimum sizes of the panels
+func (s *Splitter) limits() (float32, float32) {
+
+	size := s.ContentHeight()
+	spacer := s.spacer.Height()
+	if s.horiz {
+		size = s.ContentWidth()
+		spacer = s.spacer.Width()
+	}
+	if size <= 0 {
+		return 0, 1
+	}
+	lo := float32(0)
+	if s.min0 > 0 {
+		lo = math32.Clamp((s.min0+spacer/2)/size, 0, 1)
+	}
+	hi := float32(1)
+	if s.min1 > 0 {
+		hi = math32.Clamp((size-spacer/2-s.min1)/size, 0, 1)
+	}
+	// The minimum size of the left/top panel has priority
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// spacerPos returns the position in pixels of the spacer panel
+// clamped by the minimum sizes of the panels if not collapsed
+func (s *Splitter) spacerPos(size, spacer float32) float32 {
+
+	pos := size*s.pos - spacer/2
+	if !s.collapsed {
+		pos = math32.Min(pos, size-spacer-s.min1)
+		pos = math32.Max(pos, s.min0)
+	}
+	if pos < 0 {
+		pos = 0
+	} else if pos > size-spacer {
+		pos = size - spacer
+	}
+	return pos
+}
+
 // update updates the splitter visual state
 func (s *Splitter) update() {
 
@@ -213,12 +365,7 @@ func (s *Splitter) recalc() {
 	height := s.ContentHeight()
 	if s.horiz {
 		// Calculate x position for spacer panel
-		spx := width*s.pos - s.spacer.Width()/2
-		if spx < 0 {
-			spx = 0
-		} else if spx > width-s.spacer.Width() {
-			spx = width - s.spacer.Width()
-		}
+		spx := s.spacerPos(width, s.spacer.Width())
 		// Left panel
 		s.P0.SetPosition(0, 0)
 		s.P0.SetSize(spx, height)
@@ -230,12 +377,7 @@ func (s *Splitter) recalc() {
 		s.P1.SetSize(width-spx-s.spacer.Width(), height)
 	} else {
 		// Calculate y position for spacer panel
-		spy := height*s.pos - s.spacer.Height()/2
-		if spy < 0 {
-			spy = 0
-		} else if spy > height-s.spacer.Height() {
-			spy = height - s.spacer.Height()
-		}
+		spy := s.spacerPos(height, s.spacer.Height())
 		// Top panel
 		s.P0.SetPosition(0, 0)
 		s.P0.SetSize(width, spy)
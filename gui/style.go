@@ -5,6 +5,8 @@
 package gui
 
 import (
+	"time"
+
 	"github.com/g3n/engine/gui/assets"
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/text"
@@ -21,6 +23,7 @@ var StyleDefault *Style
 // All styles
 type Style struct {
 	Font          *text.Font
+	FontBold      *text.Font
 	FontIcon      *text.Font
 	Button        ButtonStyles
 	CheckRadio    CheckRadioStyles
@@ -38,6 +41,15 @@ type Style struct {
 	Menu          MenuStyles
 	Table         TableStyles
 	ImageButton   ImageButtonStyles
+	ProgressBar   ProgressBarStyles
+	ColorPicker   ColorPickerStyle
+	Spinner       SpinnerStyles
+	Accordion     AccordionStyles
+	TabBar        TabBarStyles
+	TextArea      TextAreaStyles
+	Tooltip       TooltipStyle
+	DragImage     DragImageStyle
+	Modal         ModalStyle
 }
 
 const (
@@ -72,6 +84,19 @@ func setupDefaultStyle() {
 	font.SetBgColor4(&math32.Color4{1, 1, 1, 0})
 	StyleDefault.Font = font
 
+	// Creates Bold Font
+	fontBoldData := assets.MustAsset(defaultFontBold)
+	fontBold, err := text.NewFontFromData(fontBoldData)
+	if err != nil {
+		panic(err)
+	}
+	fontBold.SetLineSpacing(1.0)
+	fontBold.SetSize(14)
+	fontBold.SetDPI(72)
+	fontBold.SetFgColor4(&math32.Color4{0, 0, 0, 1})
+	fontBold.SetBgColor4(&math32.Color4{1, 1, 1, 0})
+	StyleDefault.FontBold = fontBold
+
 	// Creates Icon Font
 	fontIconData := assets.MustAsset(defaultFontIcon)
 	fontIcon, err := text.NewFontFromData(fontIconData)
@@ -88,6 +113,7 @@ func setupDefaultStyle() {
 	borderSizes := BorderSizes{1, 1, 1, 1}
 	borderColor := math32.Color4{0, 0, 0, 1}
 	borderColorDis := math32.Color4{0.4, 0.4, 0.4, 1}
+	borderColorFocus := math32.Color4{0.2, 0.4, 0.9, 1}
 
 	bgColor := math32.Color{0.85, 0.85, 0.85}
 	bgColor4 := math32.Color4{0, 0, 0, 0}
@@ -118,7 +144,7 @@ func setupDefaultStyle() {
 		Focus: ButtonStyle{
 			Border:      borderSizes,
 			Paddings:    BorderSizes{2, 4, 2, 4},
-			BorderColor: borderColor,
+			BorderColor: borderColorFocus,
 			BgColor:     bgColorOver,
 			FgColor:     fgColor,
 		},
@@ -193,7 +219,7 @@ func setupDefaultStyle() {
 		Focus: EditStyle{
 			Border:      BorderSizes{1, 1, 1, 1},
 			Paddings:    BorderSizes{0, 0, 0, 0},
-			BorderColor: borderColor,
+			BorderColor: borderColorFocus,
 			BgColor:     bgColorOver,
 			BgAlpha:     1.0,
 			FgColor:     fgColor,
@@ -221,6 +247,44 @@ func setupDefaultStyle() {
 			BordersColor: borderColor,
 			Color:        math32.Color{0.5, 0.5, 0.5},
 			Size:         30,
+			MinSize:      10,
+			IconColor:    math32.Color{0.2, 0.2, 0.2},
+		},
+	}
+
+	// TextArea styles
+	StyleDefault.TextArea = TextAreaStyles{
+		Normal: TextAreaStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 0, 2, 0},
+			BorderColor: borderColor,
+			BgColor:     bgColor,
+			FgColor:     fgColor,
+			SelColor:    math32.Color4{0.6, 0.75, 1, 1},
+		},
+		Over: TextAreaStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 0, 2, 0},
+			BorderColor: borderColor,
+			BgColor:     bgColorOver,
+			FgColor:     fgColor,
+			SelColor:    math32.Color4{0.6, 0.75, 1, 1},
+		},
+		Focus: TextAreaStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 0, 2, 0},
+			BorderColor: borderColor,
+			BgColor:     bgColorOver,
+			FgColor:     fgColor,
+			SelColor:    math32.Color4{0.6, 0.75, 1, 1},
+		},
+		Disabled: TextAreaStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 0, 2, 0},
+			BorderColor: borderColorDis,
+			BgColor:     bgColor,
+			FgColor:     fgColorDis,
+			SelColor:    math32.Color4{0.6, 0.75, 1, 1},
 		},
 	}
 
@@ -242,7 +306,7 @@ func setupDefaultStyle() {
 		},
 		Focus: SliderStyle{
 			Border:      borderSizes,
-			BorderColor: borderColor,
+			BorderColor: borderColorFocus,
 			Paddings:    BorderSizes{0, 0, 0, 0},
 			BgColor:     math32.Color4{1, 1, 1, 1},
 			FgColor:     math32.Color4{0, 1, 0, 1},
@@ -256,6 +320,96 @@ func setupDefaultStyle() {
 		},
 	}
 
+	// ProgressBar styles
+	StyleDefault.ProgressBar = ProgressBarStyles{
+		Normal: ProgressBarStyle{
+			Border:      borderSizes,
+			BorderColor: borderColor,
+			Paddings:    BorderSizes{0, 0, 0, 0},
+			BgColor:     math32.Color4{0.8, 0.8, 0.8, 1},
+			FgColor:     math32.Color4{0, 0.8, 0, 1},
+			StripeColor: math32.Color4{0, 0.8, 0, 1},
+			StripeWidth: 0.25,
+			StripeSpeed: 0.8,
+		},
+		Disabled: ProgressBarStyle{
+			Border:      borderSizes,
+			BorderColor: borderColorDis,
+			Paddings:    BorderSizes{0, 0, 0, 0},
+			BgColor:     math32.Color4{0.8, 0.8, 0.8, 1},
+			FgColor:     math32.Color4{0.6, 0.6, 0.6, 1},
+			StripeColor: math32.Color4{0.6, 0.6, 0.6, 1},
+			StripeWidth: 0.25,
+			StripeSpeed: 0.8,
+		},
+	}
+
+	// Spinner styles
+	StyleDefault.Spinner = SpinnerStyles{
+		Normal: SpinnerStyle{
+			Border:      borderSizes,
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{0.85, 0.85, 0.85, 1},
+			FgColor:     fgColor,
+		},
+		Over: SpinnerStyle{
+			Border:      borderSizes,
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{0.9, 0.9, 0.9, 1},
+			FgColor:     fgColor,
+		},
+		Pressed: SpinnerStyle{
+			Border:      borderSizes,
+			BorderColor: borderColor,
+			BgColor:     bgColor4Sel,
+			FgColor:     fgColor,
+		},
+		Disabled: SpinnerStyle{
+			Border:      borderSizes,
+			BorderColor: borderColorDis,
+			BgColor:     math32.Color4{0.85, 0.85, 0.85, 1},
+			FgColor:     fgColorDis,
+		},
+	}
+
+	// Accordion styles
+	StyleDefault.Accordion = AccordionStyles{
+		Normal: AccordionStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 2, 2, 2},
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{0.85, 0.85, 0.85, 1},
+			FgColor:     fgColor,
+			Icons:       [2]int{assets.ChevronRight, assets.ExpandMore},
+		},
+		Over: AccordionStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 2, 2, 2},
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{0.9, 0.9, 0.9, 1},
+			FgColor:     fgColor,
+			Icons:       [2]int{assets.ChevronRight, assets.ExpandMore},
+		},
+		Disabled: AccordionStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 2, 2, 2},
+			BorderColor: borderColorDis,
+			BgColor:     math32.Color4{0.85, 0.85, 0.85, 1},
+			FgColor:     fgColorDis,
+			Icons:       [2]int{assets.ChevronRight, assets.ExpandMore},
+		},
+	}
+
+	// ColorPicker style
+	StyleDefault.ColorPicker = ColorPickerStyle{
+		Border:      borderSizes,
+		Paddings:    BorderSizes{6, 6, 6, 6},
+		BorderColor: borderColor,
+		BgColor:     math32.Color4{0.85, 0.85, 0.85, 1},
+		FgColor:     fgColor,
+		MarkerColor: math32.Color4{1, 1, 1, 1},
+	}
+
 	// Splitter styles
 	StyleDefault.Splitter = SplitterStyles{
 		Normal: SplitterStyle{
@@ -746,6 +900,10 @@ func setupDefaultStyle() {
 	}
 
 	// Table styles
+	editError := StyleDefault.Edit
+	editError.Normal.BorderColor = math32.Color4{1, 0, 0, 1}
+	editError.Over.BorderColor = math32.Color4{1, 0, 0, 1}
+	editError.Focus.BorderColor = math32.Color4{1, 0, 0, 1}
 	StyleDefault.Table = TableStyles{
 		Header: &TableHeaderStyle{
 			Border:      BorderSizes{0, 1, 1, 0},
@@ -795,6 +953,8 @@ func setupDefaultStyle() {
 			BorderColor: borderColor,
 			BgColor:     math32.Color4{0.4, 0.4, 0.4, 0.6},
 		},
+		Edit:      &StyleDefault.Edit,
+		EditError: &editError,
 	}
 
 	// Button styles
@@ -816,7 +976,7 @@ func setupDefaultStyle() {
 		Focus: ImageButtonStyle{
 			Border:      borderSizes,
 			Paddings:    BorderSizes{0, 0, 0, 0},
-			BorderColor: borderColor,
+			BorderColor: borderColorFocus,
 			BgColor:     bgColor4Over,
 			FgColor:     fgColor,
 		},
@@ -833,7 +993,72 @@ func setupDefaultStyle() {
 			BorderColor: borderColorDis,
 			BgColor:     bgColor4,
 			FgColor:     fgColorDis,
+			ImageTint:   math32.Color4{1, 1, 1, 0.5},
+		},
+		Active: ImageButtonStyle{
+			Border:      BorderSizes{2, 2, 2, 2},
+			Paddings:    BorderSizes{0, 0, 0, 0},
+			BorderColor: borderColor,
+			BgColor:     bgColor4Sel,
+			FgColor:     fgColorSel,
 		},
 	}
 
+	// Tooltip style
+	StyleDefault.Tooltip = TooltipStyle{
+		Border:      borderSizes,
+		Paddings:    BorderSizes{2, 4, 2, 4},
+		BorderColor: borderColor,
+		BgColor:     math32.Color4{1, 1, 0.88, 1},
+		FgColor:     fgColor,
+		Delay:       700 * time.Millisecond,
+		OffsetX:     12,
+		OffsetY:     18,
+	}
+
+	// Drag image style
+	StyleDefault.DragImage = DragImageStyle{
+		Border:      borderSizes,
+		BorderColor: math32.Color4{0.2, 0.2, 0.2, 0.6},
+		BgColor:     math32.Color4{0.6, 0.6, 0.6, 0.4},
+	}
+
+	// Modal style
+	StyleDefault.Modal = ModalStyle{
+		BackdropColor: math32.Color4{0, 0, 0, 0.4},
+	}
+
+	// TabBar styles
+	StyleDefault.TabBar = TabBarStyles{
+		Bar: &TabBarStyle{
+			Border:      borderSizes,
+			Paddings:    BorderSizes{2, 2, 2, 2},
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{0.85, 0.85, 0.85, 1},
+		},
+		Normal: &TabStyle{
+			Margins:     BorderSizes{0, 2, 0, 0},
+			Border:      BorderSizes{1, 1, 0, 1},
+			Paddings:    BorderSizes{2, 6, 2, 6},
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{0.75, 0.75, 0.75, 1},
+			FgColor:     fgColor,
+		},
+		Over: &TabStyle{
+			Margins:     BorderSizes{0, 2, 0, 0},
+			Border:      BorderSizes{1, 1, 0, 1},
+			Paddings:    BorderSizes{2, 6, 2, 6},
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{0.9, 0.9, 0.9, 1},
+			FgColor:     fgColor,
+		},
+		Selected: &TabStyle{
+			Margins:     BorderSizes{0, 2, 0, 0},
+			Border:      BorderSizes{1, 1, 0, 1},
+			Paddings:    BorderSizes{2, 6, 2, 6},
+			BorderColor: borderColor,
+			BgColor:     math32.Color4{1, 1, 1, 1},
+			FgColor:     fgColorSel,
+		},
+	}
 }
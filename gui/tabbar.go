@@ -0,0 +1,541 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+// TabBar is a panel which shows a horizontal row of tab headers
+// and the content panel of the currently selected tab below them.
+// Tabs can be reordered by dragging their headers with the mouse.
+type TabBar struct {
+	Panel                    // Embedded panel
+	styles     *TabBarStyles // Pointer to current styles
+	tabs       []*Tab        // Array of tabs
+	selected   int           // Position of the selected tab or -1
+	dragTab    *Tab          // Tab being pressed or dragged
+	dragging   bool          // True if the pressed tab header is being dragged
+	dragFrom   int           // Original position of the dragged tab
+	dragStartX float32       // Cursor x content coordinate when the tab was pressed
+	dragOffset float32       // Offset from the cursor to the left of the dragged header
+}
+
+// Tab describes a single tab of a TabBar
+type Tab struct {
+	tb         *TabBar // Pointer to parent tab bar
+	header     Panel   // Tab header panel
+	label      *Label  // Tab header label
+	content    IPanel  // Optional content panel
+	cursorOver bool    // True if the cursor is over the tab header
+	posX       float32 // Header slot x position in the tab bar
+}
+
+// TabMoveEvent is the event dispatched by the TabBar with OnChange
+// when a tab is moved from one position to another.
+type TabMoveEvent struct {
+	From int // Old tab position
+	To   int // New tab position
+}
+
+// TabSelectEvent is the event dispatched by the TabBar with OnChange
+// when the selected tab is changed by SetSelected or by a click.
+type TabSelectEvent struct {
+	From int // Position of the previously selected tab or -1
+	To   int // Position of the selected tab
+}
+
+// TabBarStyle describes the style of the tab bar panel
+type TabBarStyle struct {
+	Border      BorderSizes
+	Paddings    BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color4
+}
+
+// TabStyle describes the style of a tab header
+type TabStyle struct {
+	Margins     BorderSizes
+	Border      BorderSizes
+	Paddings    BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color4
+	FgColor     math32.Color
+}
+
+// TabBarStyles describes all the tab bar styles
+type TabBarStyles struct {
+	Bar      *TabBarStyle
+	Normal   *TabStyle
+	Over     *TabStyle
+	Selected *TabStyle
+}
+
+// tabDragThreshold is the minimum cursor displacement in pixels
+// to start dragging a pressed tab header
+const tabDragThreshold = 4
+
+// NewTabBar creates and returns a pointer to a new empty tab bar
+// with the specified width and height
+func NewTabBar(width, height float32) *TabBar {
+
+	tb := new(TabBar)
+	tb.Panel.Initialize(width, height)
+	tb.styles = &StyleDefault.TabBar
+	tb.selected = -1
+	tb.Panel.Subscribe(OnResize, tb.onResize)
+	tb.Panel.Subscribe(OnKeyDown, tb.onKey)
+	tb.update()
+	return tb
+}
+
+// SetStyles set the tab bar styles overriding the default style
+func (tb *TabBar) SetStyles(tbs *TabBarStyles) {
+
+	tb.styles = tbs
+	tb.update()
+	tb.recalc()
+}
+
+// AddTab creates and adds a new tab with the specified text
+// at the end of the tab bar and returns its pointer
+func (tb *TabBar) AddTab(text string) *Tab {
+
+	return tb.InsertTab(text, len(tb.tabs))
+}
+
+// InsertTab creates and inserts a new tab with the specified text
+// at the specified position and returns its pointer.
+// Returns nil if the position is invalid.
+func (tb *TabBar) InsertTab(text string, pos int) *Tab {
+
+	if pos < 0 || pos > len(tb.tabs) {
+		return nil
+	}
+	tab := newTab(tb, text)
+	tb.tabs = append(tb.tabs, nil)
+	copy(tb.tabs[pos+1:], tb.tabs[pos:])
+	tb.tabs[pos] = tab
+	tb.Panel.Add(&tab.header)
+	if tb.selected >= pos {
+		tb.selected++
+	}
+	if tb.selected < 0 {
+		tb.setSelected(pos)
+	}
+	tb.update()
+	tb.recalc()
+	return tab
+}
+
+// RemoveTab removes the tab at the specified position
+func (tb *TabBar) RemoveTab(pos int) error {
+
+	if pos < 0 || pos >= len(tb.tabs) {
+		return fmt.Errorf("Invalid tab position:%d", pos)
+	}
+	tab := tb.tabs[pos]
+	if tab == tb.dragTab {
+		tb.endDrag()
+	}
+	copy(tb.tabs[pos:], tb.tabs[pos+1:])
+	tb.tabs[len(tb.tabs)-1] = nil
+	tb.tabs = tb.tabs[:len(tb.tabs)-1]
+	tb.Panel.Remove(&tab.header)
+	if tab.content != nil {
+		tb.Panel.Remove(tab.content)
+	}
+
+	// Adjusts the selected tab
+	if tb.selected > pos {
+		tb.selected--
+	} else if tb.selected == pos {
+		tb.selected = -1
+		if len(tb.tabs) > 0 {
+			if pos >= len(tb.tabs) {
+				pos = len(tb.tabs) - 1
+			}
+			tb.setSelected(pos)
+		}
+	}
+	tb.update()
+	tb.recalc()
+	return nil
+}
+
+// MoveTab moves the tab at the specified source position to the
+// specified destination position keeping the selected tab.
+// Dispatches OnChange with a *TabMoveEvent if the tab was moved.
+func (tb *TabBar) MoveTab(src, dst int) error {
+
+	if src < 0 || src >= len(tb.tabs) {
+		return fmt.Errorf("Invalid tab source position:%d", src)
+	}
+	if dst < 0 || dst >= len(tb.tabs) {
+		return fmt.Errorf("Invalid tab destination position:%d", dst)
+	}
+	if src == dst {
+		return nil
+	}
+	var sel *Tab
+	if tb.selected >= 0 {
+		sel = tb.tabs[tb.selected]
+	}
+	tab := tb.tabs[src]
+	if src < dst {
+		copy(tb.tabs[src:dst], tb.tabs[src+1:dst+1])
+	} else {
+		copy(tb.tabs[dst+1:src+1], tb.tabs[dst:src])
+	}
+	tb.tabs[dst] = tab
+	if sel != nil {
+		tb.selected = tb.TabPosition(sel)
+	}
+	tb.recalc()
+	tb.Dispatch(OnChange, &TabMoveEvent{From: src, To: dst})
+	return nil
+}
+
+// TabCount returns the current number of tabs
+func (tb *TabBar) TabCount() int {
+
+	return len(tb.tabs)
+}
+
+// TabAt returns the pointer to the tab at the specified position
+// or nil if the position is invalid
+func (tb *TabBar) TabAt(pos int) *Tab {
+
+	if pos < 0 || pos >= len(tb.tabs) {
+		return nil
+	}
+	return tb.tabs[pos]
+}
+
+// TabPosition returns the position of the specified tab or -1 if not found
+func (tb *TabBar) TabPosition(tab *Tab) int {
+
+	for pos, t := range tb.tabs {
+		if t == tab {
+			return pos
+		}
+	}
+	return -1
+}
+
+// SetSelected selects the tab at the specified position
+// and returns its pointer or nil if the position is invalid.
+// Dispatches OnChange with a *TabSelectEvent if the selection changed.
+func (tb *TabBar) SetSelected(pos int) *Tab {
+
+	if pos < 0 || pos >= len(tb.tabs) {
+		return nil
+	}
+	tb.selectTab(pos)
+	return tb.tabs[pos]
+}
+
+// Selected returns the position of the selected tab or -1 if none
+func (tb *TabBar) Selected() int {
+
+	return tb.selected
+}
+
+// setSelected sets the selected tab position showing its content
+func (tb *TabBar) setSelected(pos int) {
+
+	for i, tab := range tb.tabs {
+		if tab.content != nil {
+			tab.content.GetPanel().SetVisible(i == pos)
+		}
+	}
+	tb.selected = pos
+}
+
+// selectTab selects the tab at the specified position, updates the
+// tab bar and dispatches OnChange if the selected tab changed
+func (tb *TabBar) selectTab(pos int) {
+
+	from := tb.selected
+	tb.setSelected(pos)
+	tb.update()
+	tb.recalc()
+	if pos != from {
+		tb.Dispatch(OnChange, &TabSelectEvent{From: from, To: pos})
+	}
+}
+
+// onResize process OnResize events for the tab bar
+func (tb *TabBar) onResize(evname string, ev interface{}) {
+
+	tb.recalc()
+}
+
+// onKey process OnKeyDown events cancelling the current drag on Escape
+func (tb *TabBar) onKey(evname string, ev interface{}) {
+
+	kev := ev.(*window.KeyEvent)
+	if kev.Keycode != window.KeyEscape || tb.dragTab == nil {
+		return
+	}
+	tb.endDrag()
+	tb.recalc()
+	tb.root.StopPropagation(Stop3D)
+}
+
+// onHeaderMouse process mouse button events over a tab header
+func (tb *TabBar) onHeaderMouse(tab *Tab, evname string, ev interface{}) {
+
+	mev := ev.(*window.MouseEvent)
+	switch evname {
+	case OnMouseDown:
+		if mev.Button != window.MouseButtonLeft {
+			return
+		}
+		pos := tb.TabPosition(tab)
+		if pos != tb.selected {
+			tb.selectTab(pos)
+		}
+		cx, _ := tb.ContentCoords(mev.Xpos, mev.Ypos)
+		tb.dragTab = tab
+		tb.dragging = false
+		tb.dragFrom = pos
+		tb.dragStartX = cx
+		tb.dragOffset = cx - tab.posX
+		tb.root.SetKeyFocus(tb)
+		tb.root.SetMouseFocus(&tab.header)
+	case OnMouseUp:
+		if tb.dragTab != tab {
+			return
+		}
+		if tb.dragging {
+			cx, _ := tb.ContentCoords(mev.Xpos, mev.Ypos)
+			dst := tb.dropPosition(tab, cx-tb.dragOffset)
+			tb.endDrag()
+			tb.MoveTab(tb.dragFrom, dst)
+		} else {
+			tb.endDrag()
+		}
+		tb.recalc()
+	default:
+		return
+	}
+	tb.root.StopPropagation(Stop3D)
+}
+
+// onHeaderCursor process cursor events over a tab header
+func (tb *TabBar) onHeaderCursor(tab *Tab, evname string, ev interface{}) {
+
+	switch evname {
+	case OnCursorEnter:
+		tab.cursorOver = true
+		tab.update()
+	case OnCursorLeave:
+		tab.cursorOver = false
+		tab.update()
+	case OnCursor:
+		if tb.dragTab != tab {
+			return
+		}
+		cev := ev.(*window.CursorEvent)
+		cx, _ := tb.ContentCoords(cev.Xpos, cev.Ypos)
+		if !tb.dragging {
+			if math32.Abs(cx-tb.dragStartX) < tabDragThreshold {
+				return
+			}
+			tb.dragging = true
+			tb.root.SetCursorDrag()
+			tb.SetTopChild(&tab.header)
+		}
+		// Moves the dragged header following the cursor inside the bar
+		x := cx - tb.dragOffset
+		maxX := tb.ContentWidth() - tab.header.Width()
+		if x > maxX {
+			x = maxX
+		}
+		if x < 0 {
+			x = 0
+		}
+		tab.header.SetPosition(x, 0)
+	default:
+		return
+	}
+	tb.root.StopPropagation(Stop3D)
+}
+
+// dropPosition returns the position where the specified dragged tab
+// would be inserted if its header was dropped at the specified x position
+func (tb *TabBar) dropPosition(tab *Tab, x float32) int {
+
+	center := x + tab.header.Width()/2
+	pos := 0
+	for _, t := range tb.tabs {
+		if t == tab {
+			continue
+		}
+		if t.posX+t.header.Width()/2 < center {
+			pos++
+		}
+	}
+	return pos
+}
+
+// endDrag terminates the current tab press or drag
+func (tb *TabBar) endDrag() {
+
+	if tb.dragTab == nil {
+		return
+	}
+	if tb.dragging {
+		tb.root.SetCursorNormal()
+	}
+	tb.root.SetMouseFocus(nil)
+	tb.dragTab = nil
+	tb.dragging = false
+}
+
+// update updates the tab bar and tab headers visual state
+func (tb *TabBar) update() {
+
+	s := tb.styles.Bar
+	tb.SetBordersFrom(&s.Border)
+	tb.SetBordersColor4(&s.BorderColor)
+	tb.SetPaddingsFrom(&s.Paddings)
+	tb.SetColor4(&s.BgColor)
+	for _, tab := range tb.tabs {
+		tab.update()
+	}
+}
+
+// recalc recalculates the positions of the tab headers and contents
+func (tb *TabBar) recalc() {
+
+	// Sets the header slot positions
+	var px, height float32
+	for _, tab := range tb.tabs {
+		tab.posX = px
+		if tab != tb.dragTab || !tb.dragging {
+			tab.header.SetPosition(px, 0)
+		}
+		px += tab.header.Width()
+		if tab.header.Height() > height {
+			height = tab.header.Height()
+		}
+	}
+
+	// Sets the position and size of the selected tab content
+	if tb.selected < 0 || tb.tabs[tb.selected].content == nil {
+		return
+	}
+	cheight := tb.ContentHeight() - height
+	if cheight < 0 {
+		cheight = 0
+	}
+	cont := tb.tabs[tb.selected].content.GetPanel()
+	cont.SetPosition(0, height)
+	cont.SetSize(tb.ContentWidth(), cheight)
+}
+
+// newTab creates and returns a pointer to a new tab for the specified tab bar
+func newTab(tb *TabBar, text string) *Tab {
+
+	tab := new(Tab)
+	tab.tb = tb
+	tab.header.Initialize(0, 0)
+	tab.label = NewLabel(text)
+	tab.header.Add(tab.label)
+	tab.header.Subscribe(OnMouseDown, func(evname string, ev interface{}) {
+		tb.onHeaderMouse(tab, evname, ev)
+	})
+	tab.header.Subscribe(OnMouseUp, func(evname string, ev interface{}) {
+		tb.onHeaderMouse(tab, evname, ev)
+	})
+	tab.header.Subscribe(OnCursor, func(evname string, ev interface{}) {
+		tb.onHeaderCursor(tab, evname, ev)
+	})
+	tab.header.Subscribe(OnCursorEnter, func(evname string, ev interface{}) {
+		tb.onHeaderCursor(tab, evname, ev)
+	})
+	tab.header.Subscribe(OnCursorLeave, func(evname string, ev interface{}) {
+		tb.onHeaderCursor(tab, evname, ev)
+	})
+	return tab
+}
+
+// SetText sets the text of this tab header
+func (tab *Tab) SetText(text string) {
+
+	tab.label.SetText(text)
+	tab.recalc()
+	tab.tb.recalc()
+}
+
+// Text returns the text of this tab header
+func (tab *Tab) Text() string {
+
+	return tab.label.Text()
+}
+
+// SetContent sets the panel which is shown when this tab is selected
+func (tab *Tab) SetContent(ipan IPanel) {
+
+	if tab.content != nil {
+		tab.tb.Panel.Remove(tab.content)
+	}
+	tab.content = ipan
+	if ipan != nil {
+		tab.tb.Panel.Add(ipan)
+		ipan.GetPanel().SetVisible(tab.tb.TabPosition(tab) == tab.tb.selected)
+	}
+	tab.tb.recalc()
+}
+
+// Content returns the content panel of this tab
+func (tab *Tab) Content() IPanel {
+
+	return tab.content
+}
+
+// Header returns a pointer to the header panel of this tab
+func (tab *Tab) Header() *Panel {
+
+	return &tab.header
+}
+
+// update updates the tab header visual state
+func (tab *Tab) update() {
+
+	if tab.tb.selected >= 0 && tab.tb.tabs[tab.tb.selected] == tab {
+		tab.applyStyle(tab.tb.styles.Selected)
+		return
+	}
+	if tab.cursorOver {
+		tab.applyStyle(tab.tb.styles.Over)
+		return
+	}
+	tab.applyStyle(tab.tb.styles.Normal)
+}
+
+// applyStyle applies the specified style to the tab header
+func (tab *Tab) applyStyle(s *TabStyle) {
+
+	tab.header.SetMarginsFrom(&s.Margins)
+	tab.header.SetBordersFrom(&s.Border)
+	tab.header.SetBordersColor4(&s.BorderColor)
+	tab.header.SetPaddingsFrom(&s.Paddings)
+	tab.header.SetColor4(&s.BgColor)
+	tab.label.SetColor(&s.FgColor)
+	tab.recalc()
+}
+
+// recalc recalculates the size of the tab header from its label
+func (tab *Tab) recalc() {
+
+	tab.label.SetPosition(0, 0)
+	tab.header.SetContentSize(tab.label.Width(), tab.label.Height())
+}
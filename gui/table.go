@@ -9,6 +9,7 @@ import (
 	"math"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/g3n/engine/gui/assets"
 	"github.com/g3n/engine/math32"
@@ -32,6 +33,45 @@ const (
 	TableSortNumber
 )
 
+// TableSortDir is the type used to specify the current sort direction of a table column
+type TableSortDir int
+
+const (
+	// Column not sorted
+	TableSortDirNone TableSortDir = iota
+	// Column sorted in ascending order
+	TableSortDirAsc
+	// Column sorted in descending order
+	TableSortDirDesc
+)
+
+// TableSortFunc is the type for column sort comparison functions.
+// It must return a negative number if a < b, zero if a == b
+// and a positive number if a > b.
+type TableSortFunc func(a, b interface{}) int
+
+// TableSortEvent describes the table sort state change event
+// which is dispatched with OnChange when the user clicks on
+// the header of a sortable column.
+type TableSortEvent struct {
+	Col string       // Id of the sorted column (empty if none)
+	Dir TableSortDir // Current sort direction
+}
+
+// TableValidateFunc is the type for cell edit validation functions.
+// It receives the edited cell with its current value and the new text
+// and must return true if the new text is valid.
+type TableValidateFunc func(cell TableCell, text string) bool
+
+// TableEditEvent describes the cell edit event which is dispatched
+// with OnChange when the user commits a new value for an editable cell.
+type TableEditEvent struct {
+	Row      int         // Index of the edited row
+	Col      string      // Id of the edited column
+	OldValue interface{} // Cell value before editing
+	NewValue interface{} // Cell value after editing
+}
+
 // TableSelType is the type used to specify the table row selection
 type TableSelType int
 
@@ -46,36 +86,40 @@ const (
 	tableSortedNoneIcon = assets.SwapVert
 	tableSortedAscIcon  = assets.ArrowDownward
 	tableSortedDescIcon = assets.ArrowUpward
-	tableSortedNone     = 0
-	tableSortedAsc      = 1
-	tableSortedDesc     = 2
 	tableResizerPix     = 4
+	tableDblClickTime   = 500 * time.Millisecond
 	tableColMinWidth    = 16
 	tableErrInvRow      = "Invalid row index"
 	tableErrInvCol      = "Invalid column id"
 )
 
-//
 // Table implements a panel which can contains child panels
 // organized in rows and columns.
-//
 type Table struct {
-	Panel                       // Embedded panel
-	styles         *TableStyles // pointer to current styles
-	header         tableHeader  // table headers
-	rows           []*tableRow  // array of table rows
-	rowCursor      int          // index of row cursor
-	firstRow       int          // index of the first visible row
-	lastRow        int          // index of the last visible row
-	vscroll        *ScrollBar   // vertical scroll bar
-	statusPanel    Panel        // optional bottom status panel
-	statusLabel    *Label       // status label
-	scrollBarEvent bool         // do not update the scrollbar value in recalc() if true
-	resizerPanel   Panel        // resizer panel
-	resizeCol      int          // column being resized
-	resizerX       float32      // initial resizer x coordinate
-	resizing       bool         // dragging the column resizer
-	selType        TableSelType // table selection type
+	Panel                          // Embedded panel
+	styles         *TableStyles    // pointer to current styles
+	header         tableHeader     // table headers
+	rows           []*tableRow     // array of table rows
+	rowCursor      int             // index of row cursor
+	firstRow       int             // index of the first visible row
+	lastRow        int             // index of the last visible row
+	vscroll        *ScrollBar      // vertical scroll bar
+	statusPanel    Panel           // optional bottom status panel
+	statusLabel    *Label          // status label
+	scrollBarEvent bool            // do not update the scrollbar value in recalc() if true
+	resizerPanel   Panel           // resizer panel
+	resizeCol      int             // column being resized
+	resizerX       float32         // initial resizer x coordinate
+	resizing       bool            // dragging the column resizer
+	selType        TableSelType    // table selection type
+	sortCol        *tableColHeader // column currently sorted by header clicks or nil
+	nextSeq        int             // next row sequence number
+	clickTime      time.Time       // time of the last row click
+	clickRow       *tableRow       // row of the last row click
+	clickCol       string          // column of the last row click
+	editor         *tableEdit      // cell editor
+	editRow        *tableRow       // row of the cell being edited
+	editCol        *tableColHeader // column of the cell being edited
 }
 
 // TableColumn describes a table column
@@ -155,6 +199,8 @@ type TableStyles struct {
 	RowSel    *TableRowStyle
 	Status    *TableStatusStyle
 	Resizer   *TableResizerStyle
+	Edit      *EditStyles // cell editor styles
+	EditError *EditStyles // cell editor styles for invalid values
 }
 
 // TableClickEvent describes a mouse click event over a table
@@ -179,31 +225,41 @@ type tableHeader struct {
 
 // tableColHeader is panel for a column header
 type tableColHeader struct {
-	Panel                      // header panel
-	label      *Label          // header label
-	ricon      *Label          // header right icon (sort direction)
-	id         string          // column id
-	width      float32         // initial column width
-	minWidth   float32         // minimum width
-	format     string          // column format string
-	formatFunc TableFormatFunc // column format function
-	align      Align           // column alignment
-	expand     float32         // column expand factor
-	sort       TableSortType   // column sort type
-	resize     bool            // column can be resized by user
-	order      int             // row columns order
-	sorted     int             // current sorted status
-	xl         float32         // left border coordinate in pixels
-	xr         float32         // right border coordinate in pixels
+	Panel                        // header panel
+	label      *Label            // header label
+	ricon      *Label            // header right icon (sort direction)
+	id         string            // column id
+	width      float32           // initial column width
+	minWidth   float32           // minimum width
+	format     string            // column format string
+	formatFunc TableFormatFunc   // column format function
+	align      Align             // column alignment
+	expand     float32           // column expand factor
+	sort       TableSortType     // column sort type
+	sorter     TableSortFunc     // optional column sort comparison function
+	resize     bool              // column can be resized by user
+	editable   bool              // column cells can be edited by user
+	validate   TableValidateFunc // optional cell edit validation function
+	order      int               // row columns order
+	sorted     TableSortDir      // current sorted status
+	xl         float32           // left border coordinate in pixels
+	xr         float32           // right border coordinate in pixels
 }
 
 // tableRow is panel which contains an entire table row of cells
 type tableRow struct {
 	Panel                 // embedded panel
 	selected bool         // row selected flag
+	seq      int          // row sequence number in the unsorted order
 	cells    []*tableCell // array of row cells
 }
 
+// tableEdit is the edit widget used to edit table cells
+type tableEdit struct {
+	*Edit        // embedded edit
+	t     *Table // parent table
+}
+
 // tableCell is a panel which contains one cell (a label)
 type tableCell struct {
 	Panel             // embedded panel
@@ -259,11 +315,8 @@ func NewTable(width, height float32, cols []TableColumn) (*Table, error) {
 		c.resize = cdesc.Resize
 		// Adds optional sort icon
 		if c.sort != TableSortNone {
-			c.ricon = NewIconLabel(string(tableSortedNoneIcon))
+			c.ricon = NewIconLabel(string(rune(tableSortedNoneIcon)))
 			c.Add(c.ricon)
-			c.ricon.Subscribe(OnMouseDown, func(evname string, ev interface{}) {
-				t.onRicon(evname, c)
-			})
 		}
 		// Sets default format and order
 		if c.format == "" {
@@ -558,6 +611,7 @@ func (t *Table) RemoveRow(row int) {
 // Clear removes all rows from the table
 func (t *Table) Clear() {
 
+	t.endEdit()
 	for ri := 0; ri < len(t.rows); ri++ {
 		trow := t.rows[ri]
 		t.Panel.Remove(trow)
@@ -675,12 +729,392 @@ func (t *Table) SortColumn(col string, asString bool, asc bool) {
 	if len(t.rows) < 2 {
 		return
 	}
+	var cmp TableSortFunc = TableCompareNumber
 	if asString {
-		ts := tableSortString{rows: t.rows, col: c.order, asc: asc, format: c.format}
-		sort.Sort(ts)
+		format := c.format
+		cmp = func(a, b interface{}) int {
+			return TableCompareString(fmt.Sprintf(format, a), fmt.Sprintf(format, b))
+		}
+	}
+	t.sortRows(c.order, cmp, asc)
+}
+
+// SetColumnSortable sets if the specified column can be sorted by the user
+// clicking on its header. Clicks on the header cycle the column sort direction
+// between ascending, descending and none (original rows order).
+// If the column has no sort type, its values are sorted as strings.
+// As in the other column methods, the column is identified by its id
+// and not by its position, which may be changed by SetColOrder.
+func (t *Table) SetColumnSortable(colid string, sortable bool) {
+
+	c := t.header.cmap[colid]
+	if c == nil {
+		panic(tableErrInvCol)
+	}
+	if sortable {
+		if c.sort == TableSortNone {
+			c.sort = TableSortString
+		}
+		if c.ricon == nil {
+			c.ricon = NewIconLabel(string(rune(tableSortedNoneIcon)))
+			c.Add(c.ricon)
+		}
 	} else {
-		ts := tableSortNumber{rows: t.rows, col: c.order, asc: asc}
-		sort.Sort(ts)
+		if t.sortCol == c {
+			t.sortCol = nil
+		}
+		c.sort = TableSortNone
+		c.sorted = TableSortDirNone
+		if c.ricon != nil {
+			c.Remove(c.ricon)
+			c.ricon = nil
+		}
+	}
+	t.recalc()
+}
+
+// ColumnSortable returns if the specified column can be sorted by the user
+func (t *Table) ColumnSortable(colid string) bool {
+
+	c := t.header.cmap[colid]
+	if c == nil {
+		panic(tableErrInvCol)
+	}
+	return c.sort != TableSortNone
+}
+
+// SetColumnSorter sets the comparison function used to sort the specified column
+// overriding the default string or number comparison of its sort type.
+// A nil function restores the default comparison.
+func (t *Table) SetColumnSorter(colid string, f TableSortFunc) {
+
+	c := t.header.cmap[colid]
+	if c == nil {
+		panic(tableErrInvCol)
+	}
+	c.sorter = f
+	if t.sortCol == c {
+		t.sortRows(c.order, t.colSorter(c), c.sorted == TableSortDirAsc)
+	}
+}
+
+// ColumnSortDir returns the current column id sorted by header clicks
+// and its sort direction. Returns an empty id if no column is sorted.
+func (t *Table) ColumnSortDir() (string, TableSortDir) {
+
+	if t.sortCol == nil {
+		return "", TableSortDirNone
+	}
+	return t.sortCol.id, t.sortCol.sorted
+}
+
+// SetCellEditable sets if the cells of the specified column can be edited
+// by the user double clicking on them.
+func (t *Table) SetCellEditable(colid string, editable bool) {
+
+	c := t.header.cmap[colid]
+	if c == nil {
+		panic(tableErrInvCol)
+	}
+	c.editable = editable
+	if !editable && t.editCol == c {
+		t.endEdit()
+	}
+}
+
+// SetCellValidator sets an optional function to validate the new values
+// of the edited cells of the specified column.
+// If the function returns false, the cell editor is kept open and flagged,
+// or the edit is cancelled if the editor lost the key focus.
+func (t *Table) SetCellValidator(colid string, f TableValidateFunc) {
+
+	c := t.header.cmap[colid]
+	if c == nil {
+		panic(tableErrInvCol)
+	}
+	c.validate = f
+}
+
+// EditCell starts editing the cell at the specified row and column
+// if the column is editable.
+func (t *Table) EditCell(row int, colid string) {
+
+	if row < 0 || row >= len(t.rows) {
+		panic(tableErrInvRow)
+	}
+	c := t.header.cmap[colid]
+	if c == nil {
+		panic(tableErrInvCol)
+	}
+	if !c.editable || !c.Visible() {
+		return
+	}
+	t.endEdit()
+
+	// Creates the cell editor on first use
+	if t.editor == nil {
+		te := &tableEdit{Edit: NewEdit(0, ""), t: t}
+		te.SetBounded(false)
+		te.Subscribe(OnKeyDown, te.onKey)
+		te.Subscribe(OnMouseDown, te.onMouse)
+		t.editor = te
+	}
+	te := t.editor
+	trow := t.rows[row]
+	cell := trow.cells[c.order]
+	text := ""
+	if cell.value != nil {
+		text = fmt.Sprint(cell.value)
+	}
+	te.width = int(cell.ContentWidth())
+	te.SetStyles(t.styles.Edit)
+	te.SetText(text)
+	te.CursorEnd()
+	te.SetPosition(0, 0)
+	cell.label.SetVisible(false)
+	cell.Add(te)
+	t.editRow = trow
+	t.editCol = c
+	t.root.SetKeyFocus(te)
+	if !te.focus {
+		te.focus = true
+		te.blinkID = t.root.SetInterval(750*time.Millisecond, nil, te.blink)
+		te.update()
+	}
+}
+
+// commitEdit validates and sets the new value of the cell being edited
+// and dispatches OnChange with a TableEditEvent. The edited text is converted
+// to the type of the previous cell value. If the new value is invalid the
+// editor is kept open and flagged, or the edit is cancelled if the editor
+// is losing the key focus (blur is true).
+func (t *Table) commitEdit(blur bool) {
+
+	if t.editRow == nil {
+		return
+	}
+	row := t.rowIndex(t.editRow)
+	c := t.editCol
+	old := t.editRow.cells[c.order].value
+	text := t.editor.Text()
+	value, ok := tableEditValue(old, text)
+	if ok && c.validate != nil {
+		ok = c.validate(TableCell{t, row, c.id, old}, text)
+	}
+	if !ok {
+		if blur {
+			t.closeEdit()
+			return
+		}
+		t.editor.SetStyles(t.styles.EditError)
+		return
+	}
+	if blur {
+		t.closeEdit()
+	} else {
+		t.endEdit()
+	}
+	t.setCell(row, c.id, value)
+	t.recalcRow(row)
+	t.Dispatch(OnChange, &TableEditEvent{Row: row, Col: c.id, OldValue: old, NewValue: value})
+}
+
+// endEdit closes the cell editor, if open, without changing the cell value
+// and moves the key focus from the editor to the table
+func (t *Table) endEdit() {
+
+	if t.editRow == nil {
+		return
+	}
+	focus := t.root.HasKeyFocus(t.editor)
+	t.closeEdit()
+	if focus {
+		t.root.SetKeyFocus(t)
+	}
+}
+
+// closeEdit closes the cell editor, if open, without
+// changing the cell value nor the key focus
+func (t *Table) closeEdit() {
+
+	if t.editRow == nil {
+		return
+	}
+	cell := t.editRow.cells[t.editCol.order]
+	t.editRow = nil
+	t.editCol = nil
+	cell.Remove(t.editor)
+	cell.label.SetVisible(true)
+}
+
+// tableEditValue converts the specified edited text to the type of
+// the specified previous cell value. Returns false if the text is not
+// a valid value of that type. Values of other types are set as strings.
+func tableEditValue(old interface{}, text string) (interface{}, bool) {
+
+	var err error
+	var i int64
+	var u uint64
+	var f float64
+	switch old.(type) {
+	case int:
+		i, err = strconv.ParseInt(text, 10, 0)
+		return int(i), err == nil
+	case int8:
+		i, err = strconv.ParseInt(text, 10, 8)
+		return int8(i), err == nil
+	case int16:
+		i, err = strconv.ParseInt(text, 10, 16)
+		return int16(i), err == nil
+	case int32:
+		i, err = strconv.ParseInt(text, 10, 32)
+		return int32(i), err == nil
+	case int64:
+		i, err = strconv.ParseInt(text, 10, 64)
+		return i, err == nil
+	case uint:
+		u, err = strconv.ParseUint(text, 10, 0)
+		return uint(u), err == nil
+	case uint8:
+		u, err = strconv.ParseUint(text, 10, 8)
+		return uint8(u), err == nil
+	case uint16:
+		u, err = strconv.ParseUint(text, 10, 16)
+		return uint16(u), err == nil
+	case uint32:
+		u, err = strconv.ParseUint(text, 10, 32)
+		return uint32(u), err == nil
+	case uint64:
+		u, err = strconv.ParseUint(text, 10, 64)
+		return u, err == nil
+	case float32:
+		f, err = strconv.ParseFloat(text, 32)
+		return float32(f), err == nil
+	case float64:
+		f, err = strconv.ParseFloat(text, 64)
+		return f, err == nil
+	case bool:
+		b, err := strconv.ParseBool(text)
+		return b, err == nil
+	default:
+		return text, true
+	}
+}
+
+// rowIndex returns the current index of the specified row or -1
+func (t *Table) rowIndex(trow *tableRow) int {
+
+	for ri := 0; ri < len(t.rows); ri++ {
+		if t.rows[ri] == trow {
+			return ri
+		}
+	}
+	return -1
+}
+
+// TableCompareString is the default comparison function for columns
+// sorted as strings. It compares the default string formats of the values.
+func TableCompareString(a, b interface{}) int {
+
+	sa := fmt.Sprint(a)
+	sb := fmt.Sprint(b)
+	if sa < sb {
+		return -1
+	}
+	if sa > sb {
+		return 1
+	}
+	return 0
+}
+
+// TableCompareNumber is the default comparison function for columns
+// sorted as numbers. Values which are not numbers are considered zero.
+func TableCompareNumber(a, b interface{}) int {
+
+	na := cv2f64(a)
+	nb := cv2f64(b)
+	if na < nb {
+		return -1
+	}
+	if na > nb {
+		return 1
+	}
+	return 0
+}
+
+// colSorter returns the comparison function for the specified column
+func (t *Table) colSorter(c *tableColHeader) TableSortFunc {
+
+	if c.sorter != nil {
+		return c.sorter
+	}
+	if c.sort == TableSortNumber {
+		return TableCompareNumber
+	}
+	// Compares the values as they are shown in the column
+	format := c.format
+	return func(a, b interface{}) int {
+		return TableCompareString(fmt.Sprintf(format, a), fmt.Sprintf(format, b))
+	}
+}
+
+// setSortDir sets the sort direction of the specified column, resets the
+// sort state of the other columns, sorts the rows and dispatches OnChange
+// with a TableSortEvent.
+func (t *Table) setSortDir(c *tableColHeader, dir TableSortDir) {
+
+	// Resets previous sorted column
+	if t.sortCol != nil && t.sortCol != c {
+		t.sortCol.sorted = TableSortDirNone
+		t.sortCol.ricon.SetText(string(rune(tableSortedNoneIcon)))
+	}
+	c.sorted = dir
+	switch dir {
+	case TableSortDirAsc:
+		t.sortCol = c
+		c.ricon.SetText(string(rune(tableSortedAscIcon)))
+		t.sortRows(c.order, t.colSorter(c), true)
+	case TableSortDirDesc:
+		t.sortCol = c
+		c.ricon.SetText(string(rune(tableSortedDescIcon)))
+		t.sortRows(c.order, t.colSorter(c), false)
+	default:
+		t.sortCol = nil
+		c.ricon.SetText(string(rune(tableSortedNoneIcon)))
+		t.sortRows(-1, nil, true)
+	}
+	t.Dispatch(OnChange, &TableSortEvent{Col: t.sortColId(), Dir: dir})
+}
+
+// sortColId returns the id of the column currently sorted or an empty string
+func (t *Table) sortColId() string {
+
+	if t.sortCol == nil {
+		return ""
+	}
+	return t.sortCol.id
+}
+
+// sortRows sorts the table rows by the values of the column with the specified
+// order using the specified comparison function keeping the row cursor.
+// If the comparison function is nil, the rows original order is restored.
+func (t *Table) sortRows(col int, cmp TableSortFunc, asc bool) {
+
+	// Saves current row cursor
+	var cursor *tableRow
+	if t.rowCursor >= 0 && t.rowCursor < len(t.rows) {
+		cursor = t.rows[t.rowCursor]
+	}
+	sort.Stable(tableSortCmp{rows: t.rows, col: col, cmp: cmp, asc: asc})
+
+	// Restores the row cursor
+	if cursor != nil {
+		for ri, trow := range t.rows {
+			if trow == cursor {
+				t.rowCursor = ri
+				break
+			}
+		}
 	}
 	t.recalc()
 }
@@ -735,6 +1169,18 @@ func (t *Table) insertRow(row int, values map[string]interface{}) {
 	t.rows[row] = trow
 	t.updateRowStyle(row)
 
+	// Sets the row sequence number used to restore the unsorted order.
+	// Rows inserted while the table is sorted are kept at the end.
+	if t.sortCol == nil {
+		for ri := 0; ri < len(t.rows); ri++ {
+			t.rows[ri].seq = ri
+		}
+		t.nextSeq = len(t.rows)
+	} else {
+		trow.seq = t.nextSeq
+		t.nextSeq++
+	}
+
 	// Sets the new row values from the specified map
 	if values != nil {
 		t.SetRow(row, values)
@@ -788,6 +1234,9 @@ func (t *Table) removeRow(row int) {
 
 	// Get row to be removed
 	trow := t.rows[row]
+	if trow == t.editRow {
+		t.endEdit()
+	}
 
 	// Remove row from table children
 	t.Panel.Remove(trow)
@@ -855,6 +1304,10 @@ func (t *Table) onCursorPos(evname string, ev interface{}) {
 func (t *Table) onMouse(evname string, ev interface{}) {
 
 	e := ev.(*window.MouseEvent)
+	// Ignores mouse events over the cell editor
+	if t.editRow != nil && t.editor.InsideBorders(e.Xpos, e.Ypos) {
+		return
+	}
 	t.root.SetKeyFocus(t)
 	switch evname {
 	case OnMouseDown:
@@ -876,6 +1329,13 @@ func (t *Table) onMouse(evname string, ev interface{}) {
 		var tce TableClickEvent
 		tce.MouseEvent = *e
 		t.findClick(&tce)
+		// If the header of a sortable column is clicked, cycles its sort direction
+		if tce.Header && e.Button == window.MouseButtonLeft {
+			c := t.header.cmap[tce.Col]
+			if c.sort != TableSortNone {
+				t.setSortDir(c, (c.sorted+1)%3)
+			}
+		}
 		// If row is clicked, selects it
 		if tce.Row >= 0 && e.Button == window.MouseButtonLeft {
 			t.rowCursor = tce.Row
@@ -884,6 +1344,17 @@ func (t *Table) onMouse(evname string, ev interface{}) {
 			}
 			t.recalc()
 			t.Dispatch(OnChange, nil)
+			// Checks for double click over an editable cell
+			trow := t.rows[tce.Row]
+			now := time.Now()
+			if trow == t.clickRow && tce.Col == t.clickCol && now.Sub(t.clickTime) < tableDblClickTime {
+				t.clickRow = nil
+				t.EditCell(tce.Row, tce.Col)
+			} else {
+				t.clickRow = trow
+				t.clickCol = tce.Col
+				t.clickTime = now
+			}
 		}
 		// Creates and dispatch TableClickEvent for user's context menu
 		t.Dispatch(OnTableClick, tce)
@@ -948,31 +1419,6 @@ func (t *Table) onScroll(evname string, ev interface{}) {
 	t.root.StopPropagation(Stop3D)
 }
 
-// onRicon receives subscribed events for column header right icon
-func (t *Table) onRicon(evname string, c *tableColHeader) {
-
-	icon := tableSortedNoneIcon
-	var asc bool
-	if c.sorted == tableSortedNone || c.sorted == tableSortedDesc {
-		c.sorted = tableSortedAsc
-		icon = tableSortedAscIcon
-		asc = false
-	} else {
-		c.sorted = tableSortedDesc
-		icon = tableSortedDescIcon
-		asc = true
-	}
-
-	var asString bool
-	if c.sort == TableSortString {
-		asString = true
-	} else {
-		asString = false
-	}
-	t.SortColumn(c.id, asString, asc)
-	c.ricon.SetText(string(icon))
-}
-
 // findClick finds where in the table the specified mouse click event
 // occurred updating the specified TableClickEvent with the click coordinates.
 func (t *Table) findClick(ev *TableClickEvent) {
@@ -1002,6 +1448,7 @@ func (t *Table) findClick(ev *TableClickEvent) {
 	// Checks if is in header
 	if t.header.Visible() && y < t.header.Height() {
 		ev.Header = true
+		return
 	}
 
 	// Find row clicked
@@ -1632,51 +2079,61 @@ func (t *Table) applyResizerStyle() {
 	t.resizerPanel.SetColor4(&s.BgColor)
 }
 
-// tableSortString is an internal type implementing the sort.Interface
-// and is used to sort a table column interpreting its values as strings
-type tableSortString struct {
-	rows   []*tableRow
-	col    int
-	asc    bool
-	format string
+// LostKeyFocus is called by the gui root panel when the
+// cell editor loses the key focus and commits the edited value
+func (te *tableEdit) LostKeyFocus() {
+
+	te.Edit.LostKeyFocus()
+	te.t.commitEdit(true)
 }
 
-func (ts tableSortString) Len() int      { return len(ts.rows) }
-func (ts tableSortString) Swap(i, j int) { ts.rows[i], ts.rows[j] = ts.rows[j], ts.rows[i] }
-func (ts tableSortString) Less(i, j int) bool {
+// onKey receives subscribed key events for the cell editor
+func (te *tableEdit) onKey(evname string, ev interface{}) {
 
-	vi := ts.rows[i].cells[ts.col].value
-	vj := ts.rows[j].cells[ts.col].value
-	si := fmt.Sprintf(ts.format, vi)
-	sj := fmt.Sprintf(ts.format, vj)
-	if ts.asc {
-		return si < sj
-	} else {
-		return sj < si
+	kev := ev.(*window.KeyEvent)
+	switch kev.Keycode {
+	case window.KeyEnter, window.KeyKPEnter:
+		te.t.commitEdit(false)
+	case window.KeyEscape:
+		te.t.endEdit()
+	default:
+		return
 	}
+	te.t.root.StopPropagation(Stop3D)
 }
 
-// tableSortNumber is an internal type implementing the sort.Interface
-// and is used to sort a table column interpreting its values as numbers
-type tableSortNumber struct {
+// onMouse receives subscribed mouse events for the cell editor
+// and keeps the key focus with this editor instead of the embedded edit
+func (te *tableEdit) onMouse(evname string, ev interface{}) {
+
+	te.t.root.SetKeyFocus(te)
+}
+
+// tableSortCmp is an internal type implementing the sort.Interface
+// and is used to sort the table rows using a comparison function for
+// the values of the specified column.
+// If the comparison function is nil, the rows are sorted by their
+// sequence numbers restoring their original order.
+type tableSortCmp struct {
 	rows []*tableRow
 	col  int
+	cmp  TableSortFunc
 	asc  bool
 }
 
-func (ts tableSortNumber) Len() int      { return len(ts.rows) }
-func (ts tableSortNumber) Swap(i, j int) { ts.rows[i], ts.rows[j] = ts.rows[j], ts.rows[i] }
-func (ts tableSortNumber) Less(i, j int) bool {
+func (ts tableSortCmp) Len() int      { return len(ts.rows) }
+func (ts tableSortCmp) Swap(i, j int) { ts.rows[i], ts.rows[j] = ts.rows[j], ts.rows[i] }
+func (ts tableSortCmp) Less(i, j int) bool {
 
+	if ts.cmp == nil {
+		return ts.rows[i].seq < ts.rows[j].seq
+	}
 	vi := ts.rows[i].cells[ts.col].value
 	vj := ts.rows[j].cells[ts.col].value
-	ni := cv2f64(vi)
-	nj := cv2f64(vj)
 	if ts.asc {
-		return ni < nj
-	} else {
-		return nj < ni
+		return ts.cmp(vi, vj) < 0
 	}
+	return ts.cmp(vj, vi) < 0
 }
 
 // Try to convert an interface value to a float64 number
@@ -1706,6 +2163,10 @@ func cv2f64(v interface{}) float64 {
 		return float64(n)
 	case int:
 		return float64(n)
+	case float32:
+		return float64(n)
+	case float64:
+		return n
 	case string:
 		sv, err := strconv.ParseFloat(n, 64)
 		if err == nil {
@@ -0,0 +1,40 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"testing"
+)
+
+func TestTableEditValue(t *testing.T) {
+
+	cases := []struct {
+		old   interface{}
+		text  string
+		value interface{}
+		ok    bool
+	}{
+		{"name", "other", "other", true},
+		{nil, "text", "text", true},
+		{12, "42", 42, true},
+		{12, "4.2", 0, false},
+		{int8(1), "300", int8(0), false},
+		{uint16(1), "65535", uint16(65535), true},
+		{uint(1), "-1", uint(0), false},
+		{float32(1), "2.5", float32(2.5), true},
+		{1.0, "x", 0.0, false},
+		{false, "true", true, true},
+	}
+	for _, c := range cases {
+		value, ok := tableEditValue(c.old, c.text)
+		if ok != c.ok {
+			t.Errorf("tableEditValue(%T, %q) ok = %v, want %v", c.old, c.text, ok, c.ok)
+			continue
+		}
+		if ok && value != c.value {
+			t.Errorf("tableEditValue(%T, %q) = %v (%T), want %v (%T)", c.old, c.text, value, value, c.value, c.value)
+		}
+	}
+}
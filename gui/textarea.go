@@ -0,0 +1,811 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/text"
+	"github.com/g3n/engine/texture"
+	"github.com/g3n/engine/window"
+)
+
+// TextArea is a multi line text edit widget with optional word wrap,
+// vertical scroll bar and text selection using the shift key.
+type TextArea struct {
+	Panel                           // Embedded panel
+	label          Label            // Label used to draw the visible lines
+	vscroll        *ScrollBar       // Vertical scroll bar
+	styles         *TextAreaStyles  // Pointer to current styles
+	lines          []string         // Text lines
+	dlines         []textAreaLine   // Display lines
+	wrap           bool             // Word wrap flag
+	row            int              // Caret line
+	col            int              // Caret column
+	anchorRow      int              // Selection anchor line
+	anchorCol      int              // Selection anchor column
+	selecting      bool             // Selection anchor is set
+	first          int              // First visible display line
+	focus          bool             // Key focus flag
+	cursorOver     bool             // Cursor over flag
+	caretOn        bool             // Caret blink state
+	blinkID        int              // Caret blink timer id
+	scrollBarEvent bool             // Do not update the scroll bar value if true
+	widths         map[rune]float32 // Cache of characters widths
+}
+
+// textAreaLine describes a display line of a TextArea which is
+// the whole text line or part of it if word wrap is enabled
+type textAreaLine struct {
+	row   int // Text line
+	start int // Start column in the text line
+	end   int // End column in the text line
+}
+
+// TextAreaStyle describes the style of a TextArea
+type TextAreaStyle struct {
+	Border      BorderSizes
+	Paddings    BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color
+	FgColor     math32.Color
+	SelColor    math32.Color4
+}
+
+// TextAreaStyles describes all the TextArea styles
+type TextAreaStyles struct {
+	Normal   TextAreaStyle
+	Over     TextAreaStyle
+	Focus    TextAreaStyle
+	Disabled TextAreaStyle
+}
+
+const (
+	textAreaMarginX     = 4
+	textAreaScrollWidth = 16
+)
+
+// NewTextArea creates and returns a pointer to a new empty TextArea
+// with the specified width and height
+func NewTextArea(width, height float32) *TextArea {
+
+	ta := new(TextArea)
+	ta.Panel.Initialize(width, height)
+	ta.styles = &StyleDefault.TextArea
+	ta.lines = []string{""}
+	ta.widths = make(map[rune]float32)
+
+	ta.label.initialize("", StyleDefault.Font)
+	ta.Panel.Add(&ta.label)
+
+	ta.Panel.Subscribe(OnKeyDown, ta.onKey)
+	ta.Panel.Subscribe(OnKeyRepeat, ta.onKey)
+	ta.Panel.Subscribe(OnChar, ta.onChar)
+	ta.Panel.Subscribe(OnMouseDown, ta.onMouse)
+	ta.Panel.Subscribe(OnScroll, ta.onScroll)
+	ta.Panel.Subscribe(OnCursorEnter, ta.onCursor)
+	ta.Panel.Subscribe(OnCursorLeave, ta.onCursor)
+	ta.Panel.Subscribe(OnResize, func(evname string, ev interface{}) { ta.recalc() })
+	ta.Panel.Subscribe(OnEnable, func(evname string, ev interface{}) { ta.update() })
+	ta.Panel.Subscribe(OnFocus, ta.onFocus)
+	ta.Panel.SetFocusable(true)
+
+	ta.update()
+	ta.recalc()
+	return ta
+}
+
+// SetText sets the text of this TextArea.
+// The text may contain line breaks (\n).
+func (ta *TextArea) SetText(s string) *TextArea {
+
+	ta.lines = strings.Split(strings.Replace(s, "\r", "", -1), "\n")
+	ta.row = 0
+	ta.col = 0
+	ta.first = 0
+	ta.selecting = false
+	ta.recalc()
+	return ta
+}
+
+// Text returns the current text of this TextArea
+// with the lines separated by line breaks (\n)
+func (ta *TextArea) Text() string {
+
+	return strings.Join(ta.lines, "\n")
+}
+
+// LineCount returns the current number of text lines
+func (ta *TextArea) LineCount() int {
+
+	return len(ta.lines)
+}
+
+// SetWrap sets the state of the word wrap of the text lines
+func (ta *TextArea) SetWrap(state bool) {
+
+	ta.wrap = state
+	ta.recalc()
+}
+
+// Wrap returns the state of the word wrap of the text lines
+func (ta *TextArea) Wrap() bool {
+
+	return ta.wrap
+}
+
+// SetStyles set the TextArea styles overriding the default style
+func (ta *TextArea) SetStyles(tas *TextAreaStyles) {
+
+	ta.styles = tas
+	ta.update()
+}
+
+// GetSelection returns the currently selected text
+// or an empty string if there is no selection
+func (ta *TextArea) GetSelection() string {
+
+	if !ta.hasSelection() {
+		return ""
+	}
+	r1, c1, r2, c2 := ta.selection()
+	if r1 == r2 {
+		return string([]rune(ta.lines[r1])[c1:c2])
+	}
+	parts := []string{string([]rune(ta.lines[r1])[c1:])}
+	parts = append(parts, ta.lines[r1+1:r2]...)
+	parts = append(parts, string([]rune(ta.lines[r2])[:c2]))
+	return strings.Join(parts, "\n")
+}
+
+// CursorInput inserts the specified text at the current caret position
+// replacing the current selection, if any.
+func (ta *TextArea) CursorInput(s string) {
+
+	ta.deleteSelection()
+	ins := strings.Split(strings.Replace(s, "\r", "", -1), "\n")
+	line := []rune(ta.lines[ta.row])
+	before := string(line[:ta.col])
+	after := string(line[ta.col:])
+	if len(ins) == 1 {
+		ta.lines[ta.row] = before + ins[0] + after
+		ta.col += text.StrCount(ins[0])
+	} else {
+		last := len(ins) - 1
+		newLines := make([]string, 0, len(ta.lines)+last)
+		newLines = append(newLines, ta.lines[:ta.row]...)
+		newLines = append(newLines, before+ins[0])
+		newLines = append(newLines, ins[1:last]...)
+		newLines = append(newLines, ins[last]+after)
+		newLines = append(newLines, ta.lines[ta.row+1:]...)
+		ta.lines = newLines
+		ta.row += last
+		ta.col = text.StrCount(ins[last])
+	}
+	ta.changed()
+}
+
+// LostKeyFocus satisfies the IPanel interface and is called by gui root
+// container when the panel loses the key focus
+func (ta *TextArea) LostKeyFocus() {
+
+	ta.focus = false
+	ta.root.ClearTimeout(ta.blinkID)
+	ta.update()
+}
+
+// onKey receives subscribed key events
+func (ta *TextArea) onKey(evname string, ev interface{}) {
+
+	kev := ev.(*window.KeyEvent)
+	shift := (kev.Mods & window.ModShift) != 0
+	switch kev.Keycode {
+	case window.KeyLeft:
+		ta.moveCaret(shift, func() {
+			if ta.col > 0 {
+				ta.col--
+			} else if ta.row > 0 {
+				ta.row--
+				ta.col = text.StrCount(ta.lines[ta.row])
+			}
+		})
+	case window.KeyRight:
+		ta.moveCaret(shift, func() {
+			if ta.col < text.StrCount(ta.lines[ta.row]) {
+				ta.col++
+			} else if ta.row < len(ta.lines)-1 {
+				ta.row++
+				ta.col = 0
+			}
+		})
+	case window.KeyUp:
+		ta.moveCaret(shift, func() { ta.moveLines(-1) })
+	case window.KeyDown:
+		ta.moveCaret(shift, func() { ta.moveLines(1) })
+	case window.KeyPageUp:
+		ta.moveCaret(shift, func() { ta.moveLines(-ta.visibleLines()) })
+	case window.KeyPageDown:
+		ta.moveCaret(shift, func() { ta.moveLines(ta.visibleLines()) })
+	case window.KeyHome:
+		ta.moveCaret(shift, func() {
+			if kev.Mods&window.ModControl != 0 {
+				ta.row = 0
+				ta.col = 0
+				return
+			}
+			ta.col = ta.dlines[ta.caretLine()].start
+		})
+	case window.KeyEnd:
+		ta.moveCaret(shift, func() {
+			if kev.Mods&window.ModControl != 0 {
+				ta.row = len(ta.lines) - 1
+				ta.col = text.StrCount(ta.lines[ta.row])
+				return
+			}
+			ta.col = ta.dlines[ta.caretLine()].end
+		})
+	case window.KeyEnter, window.KeyKPEnter:
+		ta.CursorInput("\n")
+	case window.KeyBackspace:
+		if ta.hasSelection() {
+			ta.deleteSelection()
+		} else if ta.col > 0 {
+			ta.deleteRange(ta.row, ta.col-1, ta.row, ta.col)
+		} else if ta.row > 0 {
+			ta.deleteRange(ta.row-1, text.StrCount(ta.lines[ta.row-1]), ta.row, 0)
+		} else {
+			break
+		}
+		ta.changed()
+	case window.KeyDelete:
+		if ta.hasSelection() {
+			ta.deleteSelection()
+		} else if ta.col < text.StrCount(ta.lines[ta.row]) {
+			ta.deleteRange(ta.row, ta.col, ta.row, ta.col+1)
+		} else if ta.row < len(ta.lines)-1 {
+			ta.deleteRange(ta.row, ta.col, ta.row+1, 0)
+		} else {
+			break
+		}
+		ta.changed()
+	case window.KeyC, window.KeyX:
+		if kev.Mods&window.ModControl == 0 {
+			return
+		}
+		if ta.hasSelection() {
+			ta.root.win.SetClipboardString(ta.GetSelection())
+			if kev.Keycode == window.KeyX {
+				ta.deleteSelection()
+				ta.changed()
+			}
+		}
+	case window.KeyV:
+		if kev.Mods&window.ModControl == 0 {
+			return
+		}
+		if s, err := ta.root.win.GetClipboardString(); err == nil && s != "" {
+			ta.CursorInput(s)
+		}
+	default:
+		return
+	}
+	ta.root.StopPropagation(Stop3D)
+}
+
+// onChar receives subscribed char events
+func (ta *TextArea) onChar(evname string, ev interface{}) {
+
+	cev := ev.(*window.CharEvent)
+	ta.CursorInput(string(cev.Char))
+}
+
+// onMouse receives subscribed mouse down events
+func (ta *TextArea) onMouse(evname string, ev interface{}) {
+
+	e := ev.(*window.MouseEvent)
+	if e.Button != window.MouseButtonLeft {
+		return
+	}
+	// Ignores clicks over the scroll bar
+	if ta.vscroll != nil && ta.vscroll.Visible() && ta.vscroll.InsideBorders(e.Xpos, e.Ypos) {
+		return
+	}
+	ta.root.SetKeyFocus(ta)
+	if !ta.focus {
+		ta.focus = true
+		ta.blinkID = ta.root.SetInterval(750*time.Millisecond, nil, ta.blink)
+		ta.update()
+	}
+
+	// Sets the caret at the clicked position
+	x, y := ta.ContentCoords(e.Xpos, e.Ypos)
+	di := ta.first + int(y/float32(ta.lineHeight()))
+	if di >= len(ta.dlines) {
+		di = len(ta.dlines) - 1
+	}
+	ta.moveCaret((e.Mods&window.ModShift) != 0, func() {
+		dl := ta.dlines[di]
+		ta.row = dl.row
+		ta.col = ta.colAtX(dl, x-textAreaMarginX)
+	})
+	ta.root.StopPropagation(Stop3D)
+}
+
+// onScroll receives subscribed mouse scroll events
+func (ta *TextArea) onScroll(evname string, ev interface{}) {
+
+	sev := ev.(*window.ScrollEvent)
+	if sev.Yoffset > 0 {
+		ta.setFirst(ta.first - 1)
+	} else if sev.Yoffset < 0 {
+		ta.setFirst(ta.first + 1)
+	}
+	ta.root.StopPropagation(Stop3D)
+}
+
+// onCursor receives subscribed cursor events
+func (ta *TextArea) onCursor(evname string, ev interface{}) {
+
+	switch evname {
+	case OnCursorEnter:
+		ta.root.SetScrollFocus(ta)
+		ta.cursorOver = true
+	case OnCursorLeave:
+		ta.root.SetScrollFocus(nil)
+		ta.cursorOver = false
+	}
+	ta.update()
+	ta.root.StopPropagation(Stop3D)
+}
+
+// onVScrollBar is called when the vertical scroll bar value changes
+func (ta *TextArea) onVScrollBar(evname string, ev interface{}) {
+
+	pos := ta.vscroll.Value()
+	first := int(math.Floor((float64(ta.maxFirst()) * pos) + 0.5))
+	if first == ta.first {
+		return
+	}
+	ta.scrollBarEvent = true
+	ta.first = first
+	ta.redraw()
+}
+
+// onFocus receives subscribed focus events and starts the caret blinking
+func (ta *TextArea) onFocus(evname string, ev interface{}) {
+
+	if ta.focus {
+		return
+	}
+	ta.focus = true
+	ta.blinkID = ta.root.SetInterval(750*time.Millisecond, nil, ta.blink)
+	ta.update()
+}
+
+// blink blinks the caret
+func (ta *TextArea) blink(arg interface{}) {
+
+	if !ta.focus {
+		return
+	}
+	ta.caretOn = !ta.caretOn
+	ta.redraw()
+}
+
+// moveCaret calls the specified function to move the caret and
+// starts, extends or clears the selection according to the shift state
+func (ta *TextArea) moveCaret(shift bool, move func()) {
+
+	if shift && !ta.selecting {
+		ta.selecting = true
+		ta.anchorRow = ta.row
+		ta.anchorCol = ta.col
+	} else if !shift {
+		ta.selecting = false
+	}
+	move()
+	ta.caretOn = true
+	ta.showCaret()
+	ta.redraw()
+}
+
+// moveLines moves the caret the specified number of display lines
+// keeping its horizontal position if possible
+func (ta *TextArea) moveLines(n int) {
+
+	ci := ta.caretLine()
+	dl := ta.dlines[ci]
+	x := ta.textWidth(ta.lines[dl.row], dl.start, ta.col)
+	ci += n
+	if ci < 0 {
+		ci = 0
+	} else if ci >= len(ta.dlines) {
+		ci = len(ta.dlines) - 1
+	}
+	dl = ta.dlines[ci]
+	ta.row = dl.row
+	ta.col = ta.colAtX(dl, x)
+}
+
+// changed is called after the text was changed
+func (ta *TextArea) changed() {
+
+	ta.selecting = false
+	ta.caretOn = true
+	ta.layout()
+	ta.showCaret()
+	ta.recalc()
+	ta.Dispatch(OnChange, nil)
+}
+
+// hasSelection returns if there is a non empty selection
+func (ta *TextArea) hasSelection() bool {
+
+	return ta.selecting && (ta.anchorRow != ta.row || ta.anchorCol != ta.col)
+}
+
+// selection returns the ordered start and end positions of the current selection
+func (ta *TextArea) selection() (r1, c1, r2, c2 int) {
+
+	r1, c1, r2, c2 = ta.anchorRow, ta.anchorCol, ta.row, ta.col
+	if r1 > r2 || (r1 == r2 && c1 > c2) {
+		r1, c1, r2, c2 = r2, c2, r1, c1
+	}
+	return
+}
+
+// deleteSelection deletes the selected text, if any
+func (ta *TextArea) deleteSelection() {
+
+	if ta.hasSelection() {
+		r1, c1, r2, c2 := ta.selection()
+		ta.deleteRange(r1, c1, r2, c2)
+	}
+	ta.selecting = false
+}
+
+// deleteRange deletes the text between the specified positions
+// and sets the caret at the start position
+func (ta *TextArea) deleteRange(r1, c1, r2, c2 int) {
+
+	before := string([]rune(ta.lines[r1])[:c1])
+	after := string([]rune(ta.lines[r2])[c2:])
+	ta.lines[r1] = before + after
+	ta.lines = append(ta.lines[:r1+1], ta.lines[r2+1:]...)
+	ta.row = r1
+	ta.col = c1
+}
+
+// caretLine returns the index of the display line which contains the caret
+func (ta *TextArea) caretLine() int {
+
+	for di, dl := range ta.dlines {
+		if dl.row != ta.row || ta.col < dl.start {
+			continue
+		}
+		// At the end of a wrapped line the caret is shown at the next line start
+		if ta.col < dl.end || di == len(ta.dlines)-1 || ta.dlines[di+1].row != dl.row {
+			return di
+		}
+	}
+	return 0
+}
+
+// colAtX returns the column of the specified display line nearest
+// to the specified x coordinate relative to the line start
+func (ta *TextArea) colAtX(dl textAreaLine, x float32) int {
+
+	line := []rune(ta.lines[dl.row])
+	var px float32
+	for col := dl.start; col < dl.end; col++ {
+		w := ta.runeWidth(line[col])
+		if x < px+w/2 {
+			return col
+		}
+		px += w
+	}
+	return dl.end
+}
+
+// textWidth returns the width in pixels of the specified columns of a text line
+func (ta *TextArea) textWidth(s string, start, end int) float32 {
+
+	line := []rune(s)
+	var width float32
+	for col := start; col < end && col < len(line); col++ {
+		width += ta.runeWidth(line[col])
+	}
+	return width
+}
+
+// runeWidth returns the width in pixels of the specified character
+func (ta *TextArea) runeWidth(r rune) float32 {
+
+	w, ok := ta.widths[r]
+	if !ok {
+		ta.setFont()
+		iw, _ := ta.label.font.MeasureText(string(r))
+		w = float32(iw)
+		ta.widths[r] = w
+	}
+	return w
+}
+
+// setFont sets the label font properties
+func (ta *TextArea) setFont() {
+
+	l := &ta.label
+	l.font.SetSize(l.fontSize)
+	l.font.SetDPI(l.fontDPI)
+	l.font.SetLineSpacing(l.lineSpacing)
+	l.font.SetBgColor4(&l.bgColor)
+	l.font.SetFgColor4(&l.fgColor)
+}
+
+// lineHeight returns the height in pixels of each text line
+func (ta *TextArea) lineHeight() int {
+
+	l := &ta.label
+	return int(math.Ceil(l.fontSize * l.lineSpacing * l.fontDPI / 72))
+}
+
+// visibleLines returns the number of display lines which fit in the text area
+func (ta *TextArea) visibleLines() int {
+
+	n := int(ta.ContentHeight()) / ta.lineHeight()
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// maxFirst returns the maximum index of the first visible display line
+func (ta *TextArea) maxFirst() int {
+
+	max := len(ta.dlines) - ta.visibleLines()
+	if max < 0 {
+		max = 0
+	}
+	return max
+}
+
+// setFirst sets the first visible display line
+func (ta *TextArea) setFirst(first int) {
+
+	if first > ta.maxFirst() {
+		first = ta.maxFirst()
+	}
+	if first < 0 {
+		first = 0
+	}
+	if first == ta.first {
+		return
+	}
+	ta.first = first
+	ta.redraw()
+}
+
+// showCaret scrolls the text area if necessary to show the caret
+func (ta *TextArea) showCaret() {
+
+	ci := ta.caretLine()
+	if ci < ta.first {
+		ta.first = ci
+	} else if ci >= ta.first+ta.visibleLines() {
+		ta.first = ci - ta.visibleLines() + 1
+	}
+}
+
+// textWidthAvail returns the available width in pixels for the text lines
+func (ta *TextArea) textWidthAvail() float32 {
+
+	width := ta.ContentWidth() - 2*textAreaMarginX
+	if ta.vscroll != nil && ta.vscroll.Visible() {
+		width -= ta.vscroll.Width()
+	}
+	return width
+}
+
+// layout builds the display lines from the text lines
+// breaking them at spaces if word wrap is enabled
+func (ta *TextArea) layout() {
+
+	ta.dlines = ta.dlines[:0]
+	width := ta.textWidthAvail()
+	for row, s := range ta.lines {
+		line := []rune(s)
+		if !ta.wrap || width <= 0 {
+			ta.dlines = append(ta.dlines, textAreaLine{row, 0, len(line)})
+			continue
+		}
+		start := 0
+		for {
+			var px float32
+			end := start
+			brk := -1
+			for end < len(line) {
+				w := ta.runeWidth(line[end])
+				if px+w > width && end > start {
+					break
+				}
+				if line[end] == ' ' {
+					brk = end + 1
+				}
+				px += w
+				end++
+			}
+			// Breaks after the last space of the line if possible
+			if end < len(line) && brk > start {
+				end = brk
+			}
+			ta.dlines = append(ta.dlines, textAreaLine{row, start, end})
+			if end >= len(line) {
+				break
+			}
+			start = end
+		}
+	}
+}
+
+// recalc recalculates the display lines, the scroll bar and redraws the text
+func (ta *TextArea) recalc() {
+
+	ta.layout()
+	// Shows the scroll bar if the display lines do not fit
+	scroll := len(ta.dlines) > ta.visibleLines()
+	visible := ta.vscroll != nil && ta.vscroll.Visible()
+	ta.setVScrollBar(scroll)
+	// The available width changed, so the display lines must be rebuilt
+	if ta.wrap && scroll != visible {
+		ta.layout()
+	}
+	if ta.first > ta.maxFirst() {
+		ta.first = ta.maxFirst()
+	}
+	ta.redraw()
+}
+
+// setVScrollBar sets the visibility state of the vertical scroll bar
+func (ta *TextArea) setVScrollBar(state bool) {
+
+	if !state {
+		if ta.vscroll != nil {
+			ta.vscroll.SetVisible(false)
+		}
+		return
+	}
+	// Creates scroll bar if necessary
+	if ta.vscroll == nil {
+		ta.vscroll = NewVScrollBar(0, 0)
+		ta.vscroll.SetBorders(0, 0, 0, 1)
+		ta.vscroll.Subscribe(OnChange, ta.onVScrollBar)
+		ta.Panel.Add(ta.vscroll)
+	}
+	ta.vscroll.SetSize(textAreaScrollWidth, ta.ContentHeight())
+	ta.vscroll.SetPositionX(ta.ContentWidth() - textAreaScrollWidth)
+	ta.vscroll.SetPositionY(0)
+	ta.vscroll.recalc()
+	ta.vscroll.SetVisible(true)
+}
+
+// redraw draws the visible display lines, the selection and the caret
+func (ta *TextArea) redraw() {
+
+	l := &ta.label
+	ta.setFont()
+	width := int(ta.textWidthAvail()) + 2*textAreaMarginX
+	height := int(ta.ContentHeight())
+	if width <= 0 || height <= 0 {
+		return
+	}
+	canvas := text.NewCanvas(width, height, &l.bgColor)
+
+	// Builds the text of the visible display lines and draws the selection
+	dy := ta.lineHeight()
+	top := int(math.Ceil(l.fontSize*l.fontDPI/72)) - int(l.fontSize) + 2
+	var r1, c1, r2, c2 int
+	sel := ta.hasSelection()
+	if sel {
+		r1, c1, r2, c2 = ta.selection()
+	}
+	selColor := image.NewUniform(text.Color4NRGBA(&ta.currentStyle().SelColor))
+	caretLine := ta.caretLine()
+	last := ta.first + ta.visibleLines() + 1
+	if last > len(ta.dlines) {
+		last = len(ta.dlines)
+	}
+	visible := make([]string, 0, last-ta.first)
+	for di := ta.first; di < last; di++ {
+		dl := ta.dlines[di]
+		s := ta.lines[dl.row]
+		visible = append(visible, string([]rune(s)[dl.start:dl.end]))
+		if !sel || dl.row < r1 || dl.row > r2 {
+			continue
+		}
+		start := dl.start
+		if dl.row == r1 && c1 > start {
+			start = c1
+		}
+		end := dl.end
+		if dl.row == r2 && c2 < end {
+			end = c2
+		}
+		if start > end {
+			continue
+		}
+		x1 := textAreaMarginX + int(ta.textWidth(s, dl.start, start))
+		x2 := textAreaMarginX + int(ta.textWidth(s, dl.start, end))
+		// Shows the selected line break
+		if dl.row < r2 && end == text.StrCount(s) {
+			x2 += int(ta.runeWidth(' '))
+		}
+		y := top + (di-ta.first)*dy
+		draw.Draw(canvas.RGBA, image.Rect(x1, y, x2, y+dy), selColor, image.ZP, draw.Over)
+	}
+	msg := strings.Join(visible, "\n")
+	if ta.focus && ta.caretOn && caretLine >= ta.first && caretLine < last {
+		canvas.DrawTextCaret(textAreaMarginX, 0, msg, l.font, caretLine-ta.first, ta.col-ta.dlines[caretLine].start)
+	} else {
+		canvas.DrawText(textAreaMarginX, 0, msg, l.font)
+	}
+
+	// Creates texture if if doesnt exist.
+	if l.tex == nil {
+		l.tex = texture.NewTexture2DFromRGBA(canvas.RGBA)
+		l.tex.SetMagFilter(gls.NEAREST)
+		l.tex.SetMinFilter(gls.NEAREST)
+		l.Panel.Material().AddTexture(l.tex)
+		// Otherwise update texture with new image
+	} else {
+		l.tex.SetFromRGBA(canvas.RGBA)
+	}
+	l.Panel.SetContentSize(float32(width), float32(height))
+	l.currentText = msg
+
+	// Set scroll bar value if redraw was not due by scroll event
+	if ta.vscroll != nil && ta.vscroll.Visible() && !ta.scrollBarEvent {
+		ta.vscroll.SetValue(float32(ta.first) / float32(ta.maxFirst()))
+	}
+	ta.scrollBarEvent = false
+}
+
+// currentStyle returns the style for the current state
+func (ta *TextArea) currentStyle() *TextAreaStyle {
+
+	if !ta.Enabled() {
+		return &ta.styles.Disabled
+	}
+	if ta.focus {
+		return &ta.styles.Focus
+	}
+	if ta.cursorOver {
+		return &ta.styles.Over
+	}
+	return &ta.styles.Normal
+}
+
+// update updates the visual state
+func (ta *TextArea) update() {
+
+	ta.applyStyle(ta.currentStyle())
+}
+
+// applyStyle applies the specified style
+func (ta *TextArea) applyStyle(s *TextAreaStyle) {
+
+	ta.SetBordersFrom(&s.Border)
+	ta.SetBordersColor4(&s.BorderColor)
+	ta.SetPaddingsFrom(&s.Paddings)
+	ta.SetColor(&s.BgColor)
+	ta.label.bgColor.FromColor(&s.BgColor, 1)
+	ta.label.fgColor.FromColor(&s.FgColor, 1)
+	ta.redraw()
+}
@@ -0,0 +1,213 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+// Tooltip is a small panel with a text label shown near the cursor
+// when the cursor hovers a panel with a tooltip text set.
+// A single tooltip is shared by all panels of a gui root.
+type Tooltip struct {
+	Panel                // Embedded panel
+	label  *Label        // tooltip text label
+	styles *TooltipStyle // pointer to current tooltip style
+}
+
+// Tooltip style
+type TooltipStyle struct {
+	Border      BorderSizes
+	Paddings    BorderSizes
+	BorderColor math32.Color4
+	BgColor     math32.Color4
+	FgColor     math32.Color
+	Delay       time.Duration // hover delay before the tooltip is shown
+	OffsetX     float32       // horizontal offset from the cursor position
+	OffsetY     float32       // vertical offset from the cursor position
+}
+
+// panelTooltip keeps the tooltip state of a panel
+type panelTooltip struct {
+	text    string        // tooltip text
+	styles  *TooltipStyle // pointer to the tooltip style of this panel
+	timerID int           // id of hover delay timer (0 = none)
+	shown   bool          // tooltip is being shown for this panel
+	x       float32       // last cursor x position over the panel
+	y       float32       // last cursor y position over the panel
+}
+
+// NewTooltip creates and returns a pointer to a new tooltip panel
+// with the specified text
+func NewTooltip(text string) *Tooltip {
+
+	t := new(Tooltip)
+	t.styles = &StyleDefault.Tooltip
+	t.Panel.Initialize(0, 0)
+	t.Panel.SetBounded(false)
+	// The tooltip should not receive mouse events
+	t.Panel.SetEnabled(false)
+
+	t.label = NewLabel(text)
+	t.Panel.Add(t.label)
+	t.update()
+	t.recalc()
+	return t
+}
+
+// SetText sets the text of this tooltip
+func (t *Tooltip) SetText(text string) {
+
+	t.label.SetText(text)
+	t.recalc()
+}
+
+// SetStyles sets the tooltip style overriding the default style
+func (t *Tooltip) SetStyles(ts *TooltipStyle) {
+
+	t.styles = ts
+	t.update()
+	t.recalc()
+}
+
+// update updates the tooltip visual state
+func (t *Tooltip) update() {
+
+	t.SetBordersFrom(&t.styles.Border)
+	t.SetBordersColor4(&t.styles.BorderColor)
+	t.SetPaddingsFrom(&t.styles.Paddings)
+	t.SetColor4(&t.styles.BgColor)
+	t.label.SetColor(&t.styles.FgColor)
+}
+
+// recalc recalculates the tooltip dimensions
+func (t *Tooltip) recalc() {
+
+	t.label.SetPosition(0, 0)
+	t.SetContentSize(t.label.Width(), t.label.Height())
+}
+
+// showAt shows this tooltip near the specified cursor position
+// keeping it inside the specified area.
+func (t *Tooltip) showAt(x, y, width, height float32) {
+
+	px := x + t.styles.OffsetX
+	py := y + t.styles.OffsetY
+	// Moves the tooltip to the left of the cursor if necessary
+	if px+t.Width() > width {
+		px = width - t.Width()
+	}
+	// Moves the tooltip above the cursor if necessary
+	if py+t.Height() > height {
+		py = y - t.Height()
+	}
+	if px < 0 {
+		px = 0
+	}
+	if py < 0 {
+		py = 0
+	}
+	t.SetPosition(px, py)
+	t.SetVisible(true)
+}
+
+// SetTooltip sets the text of the tooltip shown when the cursor
+// hovers this panel. Passing an empty text removes the tooltip.
+// The tooltip uses the current default tooltip style unless
+// it is overridden with SetTooltipStyles.
+func (p *Panel) SetTooltip(text string) {
+
+	if p.tooltip == nil {
+		p.tooltip = new(panelTooltip)
+		p.tooltip.styles = &StyleDefault.Tooltip
+		p.Subscribe(OnCursor, p.onTooltipCursor)
+		p.Subscribe(OnCursorLeave, p.onTooltipCursor)
+		p.Subscribe(OnMouseDown, p.onTooltipCursor)
+	}
+	p.hideTooltip()
+	p.tooltip.text = text
+}
+
+// Tooltip returns the current tooltip text of this panel
+func (p *Panel) Tooltip() string {
+
+	if p.tooltip == nil {
+		return ""
+	}
+	return p.tooltip.text
+}
+
+// SetTooltipStyles sets the style of the tooltip of this panel, which
+// specifies both the hover delay and the look of the tooltip, overriding
+// the default style. It must be called after SetTooltip.
+func (p *Panel) SetTooltipStyles(ts *TooltipStyle) {
+
+	p.hideTooltip()
+	p.tooltip.styles = ts
+}
+
+// onTooltipCursor process subscribed events for the panel tooltip
+func (p *Panel) onTooltipCursor(evname string, ev interface{}) {
+
+	if len(p.tooltip.text) == 0 || p.root == nil {
+		return
+	}
+	switch evname {
+	case OnCursor:
+		cev := ev.(*window.CursorEvent)
+		p.tooltip.x = cev.Xpos
+		p.tooltip.y = cev.Ypos
+		if p.tooltip.timerID != 0 || p.tooltip.shown {
+			return
+		}
+		p.tooltip.timerID = p.root.SetTimeout(p.tooltip.styles.Delay, nil, func(arg interface{}) {
+			p.tooltip.timerID = 0
+			p.root.showTooltip(p)
+		})
+	case OnCursorLeave, OnMouseDown:
+		p.hideTooltip()
+	}
+}
+
+// hideTooltip cancels the tooltip timer and hides the tooltip if shown
+func (p *Panel) hideTooltip() {
+
+	if p.tooltip.timerID != 0 {
+		p.root.ClearTimeout(p.tooltip.timerID)
+		p.tooltip.timerID = 0
+	}
+	if p.tooltip.shown {
+		p.root.tooltip.SetVisible(false)
+		p.tooltip.shown = false
+	}
+}
+
+// showTooltip shows the root tooltip for the specified panel
+func (r *Root) showTooltip(p *Panel) {
+
+	if r.tooltip == nil {
+		r.tooltip = NewTooltip(p.tooltip.text)
+		r.Add(r.tooltip)
+	} else {
+		r.tooltip.SetText(p.tooltip.text)
+		r.SetTopChild(r.tooltip)
+	}
+	// The shared tooltip has the style of the panel which set the delay
+	if r.tooltip.styles != p.tooltip.styles {
+		r.tooltip.SetStyles(p.tooltip.styles)
+	}
+	// Keeps the tooltip inside the root panel or the window if the root is not sized
+	width, height := r.Size()
+	if width == 0 || height == 0 {
+		w, h := r.win.GetSize()
+		width = float32(w)
+		height = float32(h)
+	}
+	r.tooltip.showAt(p.tooltip.x, p.tooltip.y, width, height)
+	p.tooltip.shown = true
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+func TestTooltipDelayFollowsStyle(t *testing.T) {
+
+	// A sized root panel without a window
+	r := new(Root)
+	r.root = r
+	r.Panel.Initialize(400, 300)
+	r.TimerManager.Initialize()
+
+	styles := StyleDefault.Tooltip
+	styles.Delay = 0
+	styles.BgColor = math32.Color4{0, 0, 1, 1}
+	cases := []struct {
+		name   string
+		styles *TooltipStyle
+		shown  bool
+		color  math32.Color4
+	}{
+		{"default", nil, false, StyleDefault.Tooltip.BgColor},
+		{"overridden", &styles, true, styles.BgColor},
+	}
+	for _, c := range cases {
+		p := NewPanel(100, 100)
+		p.SetTooltip(c.name)
+		if c.styles != nil {
+			p.SetTooltipStyles(c.styles)
+		}
+		r.Add(p)
+		p.Dispatch(OnCursor, &window.CursorEvent{Xpos: 10, Ypos: 10})
+		time.Sleep(time.Millisecond)
+		r.ProcessTimers()
+		if p.tooltip.shown != c.shown {
+			t.Errorf("%s: tooltip shown = %v, want %v", c.name, p.tooltip.shown, c.shown)
+		}
+		if c.shown && r.tooltip.Color4() != c.color {
+			t.Errorf("%s: tooltip color = %v, want %v", c.name, r.tooltip.Color4(), c.color)
+		}
+		p.Dispatch(OnCursorLeave, nil)
+		if p.tooltip.shown || p.tooltip.timerID != 0 {
+			t.Errorf("%s: tooltip not hidden when the cursor leaves", c.name)
+		}
+	}
+}
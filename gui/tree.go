@@ -35,13 +35,15 @@ type TreeNodeStyle struct {
 }
 
 type TreeNode struct {
-	Panel              // Embedded panel
-	label    Label     // Node label
-	icon     Label     // Node icon
-	tree     *Tree     // Parent tree
-	parNode  *TreeNode // Parent node
-	items    []IPanel  // List of node items
-	expanded bool      // Node expanded flag
+	Panel                                    // Embedded panel
+	label        Label                       // Node label
+	icon         Label                       // Node icon
+	tree         *Tree                       // Parent tree
+	parNode      *TreeNode                   // Parent node
+	items        []IPanel                    // List of node items
+	expanded     bool                        // Node expanded flag
+	childrenFunc func(*TreeNode) []*TreeNode // Optional lazy children loader
+	loaded       bool                        // Lazy children already loaded flag
 }
 
 // NewTree creates and returns a pointer to a new tree widget
@@ -189,15 +191,22 @@ func (t *Tree) onKey(evname string, ev interface{}) {
 		return
 	}
 	// Toggles the expansion state of the node
-	node.expanded = !node.expanded
-	node.update()
-	node.updateItems()
+	node.SetExpanded(!node.expanded)
 }
 
 //
 // TreeNode methods
 //
 
+// NewTreeNode creates and returns a pointer to a new tree node
+// with the specified text which is not yet inserted in a tree.
+// It is normally used to create the nodes returned by the children
+// function set with SetChildrenFunc().
+func NewTreeNode(text string) *TreeNode {
+
+	return newTreeNode(text, nil, nil)
+}
+
 // newTreeNode creates and returns a pointer to a new TreeNode with
 // the specified text, tree and parent node
 func newTreeNode(text string, tree *Tree, parNode *TreeNode) *TreeNode {
@@ -222,8 +231,10 @@ func newTreeNode(text string, tree *Tree, parNode *TreeNode) *TreeNode {
 	n.tree = tree
 	n.parNode = parNode
 
-	n.update()
-	n.recalc()
+	if tree != nil {
+		n.update()
+		n.recalc()
+	}
 	return n
 }
 
@@ -233,12 +244,80 @@ func (n *TreeNode) Len() int {
 	return len(n.items)
 }
 
-// SetExpanded sets the expanded state of this node
+// SetExpanded sets the expanded state of this node.
+// Dispatches OnExpand or OnCollapse to the tree with a pointer
+// to this node if its state changed. The state of a node which is not
+// in a tree yet is only saved and its children are loaded when it is added.
 func (n *TreeNode) SetExpanded(state bool) {
 
+	if state == n.expanded {
+		return
+	}
+	if n.tree == nil {
+		n.expanded = state
+		return
+	}
+	if state {
+		n.tree.Dispatch(OnExpand, n)
+		n.loadChildren()
+	}
 	n.expanded = state
 	n.update()
+	n.recalc()
 	n.updateItems()
+	if !state {
+		n.tree.Dispatch(OnCollapse, n)
+	}
+}
+
+// Expanded returns the expanded state of this node
+func (n *TreeNode) Expanded() bool {
+
+	return n.expanded
+}
+
+// SetChildrenFunc sets a function which is called to load the children
+// of this node the first time it is expanded. The returned nodes
+// are added to this node and the function is not called again.
+func (n *TreeNode) SetChildrenFunc(f func(node *TreeNode) []*TreeNode) {
+
+	n.childrenFunc = f
+	n.loaded = false
+}
+
+// loadChildren calls the children function, if set and not yet called,
+// and adds the returned nodes to this node.
+func (n *TreeNode) loadChildren() {
+
+	if n.childrenFunc == nil || n.loaded {
+		return
+	}
+	n.loaded = true
+	for _, child := range n.childrenFunc(n) {
+		if child == nil {
+			continue
+		}
+		child.setTree(n.tree, n)
+		n.items = append(n.items, child)
+	}
+}
+
+// setTree sets the tree and parent node of this node and its children nodes
+func (n *TreeNode) setTree(tree *Tree, parNode *TreeNode) {
+
+	n.tree = tree
+	n.parNode = parNode
+	if n.expanded && tree != nil {
+		n.loadChildren()
+	}
+	for _, item := range n.items {
+		node, ok := item.(*TreeNode)
+		if ok {
+			node.setTree(tree, n)
+		}
+	}
+	n.update()
+	n.recalc()
 }
 
 // FindChild searches for the specified child in this node and
@@ -338,10 +417,7 @@ func (n *TreeNode) onMouse(evname string, ev interface{}) {
 
 	switch evname {
 	case OnMouseDown:
-		n.expanded = !n.expanded
-		n.update()
-		n.recalc()
-		n.updateItems()
+		n.SetExpanded(!n.expanded)
 	default:
 		return
 	}
@@ -370,7 +446,7 @@ func (n *TreeNode) applyStyle(s *TreeNodeStyle) {
 	if n.expanded {
 		icode = 1
 	}
-	n.icon.SetText(string(s.Icons[icode]))
+	n.icon.SetText(string(rune(s.Icons[icode])))
 	n.icon.SetColor(&s.FgColor)
 	n.label.SetColor(&s.FgColor)
 }
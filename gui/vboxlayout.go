@@ -4,28 +4,38 @@
 
 package gui
 
+// VBoxLayout arranges the visible children of a panel in a column from top
+// to bottom. The extra height of the panel is shared among the children with
+// an expand factor. If no children expand, the whole block of children is
+// aligned vertically. The children are aligned horizontally by their own
+// parameters or by the default horizontal alignment of the layout.
 type VBoxLayout struct {
 	pan     IPanel
-	spacing float32 // vertical spacing between the children in pixels.
-	alignV  Align   // vertical alignment of the whole block of children
+	spacing float32            // vertical spacing between the children in pixels.
+	alignV  Align              // vertical alignment of the whole block of children
+	alignH  Align              // default horizontal alignment of the children
+	padding BorderSizes        // space around the block of children in pixels
+	sizes   map[*Panel]boxSize // sizes of the expanded children
 }
 
 // Parameters for individual children
 type VBoxLayoutParams struct {
 	Expand float32 // item expand vertically factor (0 - no expand)
-	AlignH Align   // item horizontal alignment
+	AlignH Align   // item horizontal alignment (AlignNone - use the layout alignment)
 }
 
-// NewVBoxLayout creates and returns a pointer to a new horizontal box layout
+// NewVBoxLayout creates and returns a pointer to a new vertical box layout
 func NewVBoxLayout() *VBoxLayout {
 
 	bl := new(VBoxLayout)
 	bl.spacing = 0
 	bl.alignV = AlignTop
+	bl.alignH = AlignLeft
+	bl.sizes = make(map[*Panel]boxSize)
 	return bl
 }
 
-// SetSpacing sets the horizontal spacing between the items in pixels
+// SetSpacing sets the vertical spacing between the items in pixels
 // and updates the layout if possible
 func (bl *VBoxLayout) SetSpacing(spacing float32) {
 
@@ -33,7 +43,13 @@ func (bl *VBoxLayout) SetSpacing(spacing float32) {
 	bl.Recalc(bl.pan)
 }
 
-// SetAlignH sets the horizontal alignment of the whole group of items
+// Spacing returns the vertical spacing between the items in pixels
+func (bl *VBoxLayout) Spacing() float32 {
+
+	return bl.spacing
+}
+
+// SetAlignV sets the vertical alignment of the whole group of items
 // inside the parent panel and updates the layout if possible.
 // This only has any effect if there are no expanded items.
 func (bl *VBoxLayout) SetAlignV(align Align) {
@@ -42,127 +58,157 @@ func (bl *VBoxLayout) SetAlignV(align Align) {
 	bl.Recalc(bl.pan)
 }
 
+// AlignV returns the vertical alignment of the whole group of items
+func (bl *VBoxLayout) AlignV() Align {
+
+	return bl.alignV
+}
+
+// SetAlignH sets the horizontal alignment of the items without
+// layout parameters or with AlignH set to AlignNone and updates the
+// layout if possible. The default is AlignLeft.
+func (bl *VBoxLayout) SetAlignH(align Align) {
+
+	bl.alignH = align
+	bl.Recalc(bl.pan)
+}
+
+// AlignH returns the default horizontal alignment of the items
+func (bl *VBoxLayout) AlignH() Align {
+
+	return bl.alignH
+}
+
+// SetPadding sets the space in pixels between the borders of the
+// parent panel content area and the items and updates the layout if possible
+func (bl *VBoxLayout) SetPadding(top, right, bottom, left float32) {
+
+	bl.padding = BorderSizes{top, right, bottom, left}
+	bl.Recalc(bl.pan)
+}
+
+// Padding returns the space in pixels around the items
+func (bl *VBoxLayout) Padding() BorderSizes {
+
+	return bl.padding
+}
+
 // Recalc recalculates and sets the position and sizes of all children
 func (bl *VBoxLayout) Recalc(ipan IPanel) {
 
+	type element struct {
+		panel  *Panel
+		params VBoxLayoutParams
+		height float32
+	}
+
 	// Saves the received panel
 	bl.pan = ipan
 	if bl.pan == nil {
 		return
 	}
 	parent := ipan.GetPanel()
-	if len(parent.Children()) == 0 {
-		return
-	}
 
-	// Calculates the total height, expanded height, fixed height and
-	// the sum of the expand factor for all items.
+	// Gets the visible items with their layout parameters and natural
+	// heights and calculates the total height, fixed height and the sum
+	// of the expand factor for all items.
 	var theight float32 = 0
-	var eheight float32 = 0
 	var fheight float32 = 0
 	var texpand float32 = 0
-	ecount := 0
-	paramsDef := VBoxLayoutParams{Expand: 0, AlignH: AlignLeft}
-	for pos, obj := range parent.Children() {
+	items := []element{}
+	for _, obj := range parent.Children() {
 		pan := obj.(IPanel).GetPanel()
+		if !pan.Visible() {
+			continue
+		}
 		// Get item layout parameters or use default
-		params := paramsDef
+		params := VBoxLayoutParams{}
 		if pan.layoutParams != nil {
 			params = *pan.layoutParams.(*VBoxLayoutParams)
 		}
-		// Calculate total height
-		theight += pan.Height()
-		if pos > 0 {
-			theight += bl.spacing
+		if params.AlignH == AlignNone {
+			params.AlignH = bl.alignH
 		}
-		// Calculate height of expanded items
+		height := pan.Height()
 		if params.Expand > 0 {
 			texpand += params.Expand
-			eheight += pan.Height()
-			if pos > 0 {
-				eheight += bl.spacing
+			if size, ok := bl.sizes[pan]; ok && size.assigned == height {
+				height = size.natural
 			}
-			ecount++
-			// Calculate width of fixed items
 		} else {
-			fheight += pan.Height()
-			if pos > 0 {
-				fheight += bl.spacing
-			}
+			fheight += height
 		}
+		theight += height
+		items = append(items, element{pan, params, height})
 	}
+	if len(items) == 0 {
+		return
+	}
+	spacings := bl.spacing * float32(len(items)-1)
+	theight += spacings
+
+	// Available area for the items
+	height := parent.ContentHeight() - bl.padding.Top - bl.padding.Bottom
+	width := parent.ContentWidth() - bl.padding.Left - bl.padding.Right
 
 	// If there is at least on expanded item, all free space will be occupied
 	spaceMiddle := bl.spacing
 	var posY float32 = 0
+	sizes := make(map[*Panel]boxSize)
 	if texpand > 0 {
-		// If there is free space, distribute space between expanded items
-		totalSpace := parent.ContentHeight() - theight
-		if totalSpace > 0 {
-			for _, obj := range parent.Children() {
-				pan := obj.(IPanel).GetPanel()
-				// Get item layout parameters or use default
-				params := paramsDef
-				if pan.layoutParams != nil {
-					params = *pan.layoutParams.(*VBoxLayoutParams)
-				}
-				if params.Expand > 0 {
-					iheight := totalSpace * params.Expand / texpand
-					pan.SetHeight(pan.Height() + iheight)
-				}
+		totalSpace := height - theight
+		for i := range items {
+			el := &items[i]
+			if el.params.Expand <= 0 {
+				continue
 			}
-			// No free space: distribute expanded items heights
-		} else {
-			for _, obj := range parent.Children() {
-				pan := obj.(IPanel).GetPanel()
-				// Get item layout parameters or use default
-				params := paramsDef
-				if pan.layoutParams != nil {
-					params = *pan.layoutParams.(*VBoxLayoutParams)
-				}
-				if params.Expand > 0 {
-					spacing := bl.spacing * float32(ecount-1)
-					iheight := (parent.ContentHeight() - spacing - fheight - bl.spacing) * params.Expand / texpand
-					pan.SetHeight(iheight)
+			// If there is free space, distribute space between expanded items
+			// otherwise distribute the height not used by the fixed items
+			var iheight float32
+			if totalSpace > 0 {
+				iheight = el.height + totalSpace*el.params.Expand/texpand
+			} else {
+				iheight = (height - spacings - fheight) * el.params.Expand / texpand
+				if iheight < 0 {
+					iheight = 0
 				}
 			}
+			if el.panel.Height() != iheight {
+				el.panel.SetHeight(iheight)
+			}
+			sizes[el.panel] = boxSize{el.height, el.panel.Height()}
 		}
 		// No expanded items: checks block vertical alignment
 	} else {
 		// Calculates initial y position which depends
-		// on the current horizontal alignment.
+		// on the current vertical alignment.
 		switch bl.alignV {
-		case AlignTop:
+		case AlignNone, AlignTop:
 			posY = 0
 		case AlignCenter:
-			posY = (parent.ContentHeight() - theight) / 2
+			posY = (height - theight) / 2
 		case AlignBottom:
-			posY = parent.ContentHeight() - theight
+			posY = height - theight
 		case AlignHeight:
-			space := parent.ContentHeight() - theight + bl.spacing*float32(len(parent.Children())-1)
+			space := height - theight + spacings
 			if space < 0 {
-				space = bl.spacing * float32(len(parent.Children())-1)
+				space = spacings
 			}
-			spaceMiddle = space / float32(len(parent.Children())+1)
+			spaceMiddle = space / float32(len(items)+1)
 			posY = spaceMiddle
 		default:
 			log.Fatal("VBoxLayout: invalid global vertical alignment")
 		}
 	}
+	bl.sizes = sizes
 
-	// Calculates the X position of each item considering
-	// it horizontal alignment
+	// Calculates the X position of each item considering its horizontal alignment
 	var posX float32
-	width := parent.ContentWidth()
-	for pos, obj := range parent.Children() {
-		pan := obj.(IPanel).GetPanel()
-		// Get item layout parameters or use default
-		params := paramsDef
-		if pan.layoutParams != nil {
-			params = *pan.layoutParams.(*VBoxLayoutParams)
-		}
+	posY += bl.padding.Top
+	for _, el := range items {
+		pan := el.panel
 		cwidth := pan.Width()
-		switch params.AlignH {
+		switch el.params.AlignH {
 		case AlignLeft:
 			posX = 0
 		case AlignCenter:
@@ -171,16 +217,14 @@ func (bl *VBoxLayout) Recalc(ipan IPanel) {
 			posX = width - cwidth
 		case AlignWidth:
 			posX = 0
-			pan.SetWidth(width)
+			if cwidth != width {
+				pan.SetWidth(width)
+			}
 		default:
 			log.Fatal("VBoxLayout: invalid item horizontal alignment")
 		}
-		// Sets the child position
-		pan.SetPosition(posX, posY)
-		// Calculates next position
-		posY += pan.Height()
-		if pos < len(parent.Children())-1 {
-			posY += spaceMiddle
-		}
+		// Sets the child position and calculates next position
+		pan.SetPosition(bl.padding.Left+posX, posY)
+		posY += pan.Height() + spaceMiddle
 	}
 }
@@ -11,16 +11,20 @@ import (
 )
 
 type Directional struct {
-	core.Node                 // Embedded node
-	color     math32.Color    // Light color
-	intensity float32         // Light intensity
-	uni       *gls.Uniform3fv // uniform with light color and direction
+	core.Node                  // Embedded node
+	color      math32.Color    // Light color
+	intensity  float32         // Light intensity
+	uni        *gls.Uniform3fv // uniform with light color and direction
+	castShadow bool            // casts shadows flag
+	shadow     *ShadowMap      // shadow map or nil
 }
 
 const (
-	dirColor    = 0 // index of color triplet in uniform
-	dirPosition = 1 // index of position vector in uniform
-	dirUniSize  = 2 // uniform count of 3 float32
+	dirColor      = 0 // index of color triplet in uniform
+	dirPosition   = 1 // index of position vector in uniform
+	dirShadow     = 6 // position of the index of the shadow map in uniform
+	dirShadowBias = 7 // position of the shadow map depth bias in uniform
+	dirUniSize    = 3 // uniform count of 3 float32
 )
 
 // NewDirectional creates and returns a pointer of a new directional light
@@ -34,6 +38,7 @@ func NewDirectional(color *math32.Color, intensity float32) *Directional {
 	ld.intensity = intensity
 	ld.uni = gls.NewUniform3fv("DirLight", dirUniSize)
 	ld.SetColor(color)
+	ld.uni.SetPos(dirShadow, -1)
 	return ld
 }
 
@@ -67,6 +72,77 @@ func (ld *Directional) Intensity() float32 {
 	return ld.intensity
 }
 
+// SetCastShadow sets if this light casts shadows of the graphics which
+// have cast shadow enabled. The shadow map is created when it is first
+// enabled and can be configured with ShadowMap(). The default is false.
+func (ld *Directional) SetCastShadow(state bool) {
+
+	ld.castShadow = state
+	if state && ld.shadow == nil {
+		ld.shadow = newShadowMap("Dir", -50, 50)
+	}
+	if !state {
+		ld.uni.SetPos(dirShadow, -1)
+	}
+}
+
+// CastShadow returns if this light casts shadows
+func (ld *Directional) CastShadow() bool {
+
+	return ld.castShadow
+}
+
+// ShadowMap returns the shadow map of this light, which is created
+// if necessary, to set its resolution and other parameters.
+func (ld *Directional) ShadowMap() *ShadowMap {
+
+	if ld.shadow == nil {
+		ld.shadow = newShadowMap("Dir", -50, 50)
+	}
+	return ld.shadow
+}
+
+// Dispose overrides the embedded Node Dispose method
+// and releases the OpenGL resources of the shadow map
+func (ld *Directional) Dispose() {
+
+	if ld.shadow != nil {
+		ld.shadow.Dispose()
+	}
+}
+
+// ShadowCamera satisfies the IShadowLight interface and sets the view and
+// projection matrices of the specified render info to the orthographic
+// projection of the shadow volume from the light direction.
+func (ld *Directional) ShadowCamera(rinfo *core.RenderInfo) {
+
+	var dir math32.Vector3
+	ld.WorldPosition(&dir)
+	dir.Normalize()
+	up := math32.Vector3{0, 1, 0}
+	if math32.Abs(dir.Y) > 0.999 {
+		up = math32.Vector3{0, 0, 1}
+	}
+	sm := ld.ShadowMap()
+	var target math32.Vector3
+	target.SubVectors(&sm.center, &dir)
+	var view, proj math32.Matrix4
+	view.LookAt(&sm.center, &target, &up)
+	half := sm.area / 2
+	proj.MakeOrthographic(-half, half, half, -half, sm.near, sm.far)
+	sm.setCamera(rinfo, &view, &proj)
+}
+
+// ShadowSetup is called by the engine before RenderSetup for lights which
+// cast shadows to bind the shadow map to the specified texture unit and
+// to set the shadow map uniforms with the specified index.
+func (ld *Directional) ShadowSetup(gs *gls.GLS, rinfo *core.RenderInfo, unit, idx int) {
+
+	ld.uni.SetPos(dirShadow, float32(idx))
+	ld.uni.SetPos(dirShadowBias, ld.shadow.bias)
+	ld.shadow.RenderSetup(gs, rinfo, unit, idx)
+}
+
 // RenderSetup is called by the engine before rendering the scene
 func (ld *Directional) RenderSetup(gs *gls.GLS, rinfo *core.RenderInfo, idx int) {
 
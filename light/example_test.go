@@ -0,0 +1,131 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package light_test
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
+	"github.com/g3n/engine/texture"
+	"github.com/g3n/engine/window"
+)
+
+// newExampleRoom creates the window, renderer and camera of the examples and
+// a scene with a floor, a back wall and a sphere lit by a dim ambient light
+func newExampleRoom(title string) (window.IWindow, *gls.GLS, *renderer.Renderer, *core.Node, *camera.Perspective) {
+
+	win, err := window.New("glfw", 800, 600, title, false)
+	if err != nil {
+		panic(err)
+	}
+	gs, err := gls.New()
+	if err != nil {
+		panic(err)
+	}
+	rend := renderer.NewRenderer(gs)
+	err = rend.AddDefaultShaders()
+	if err != nil {
+		panic(err)
+	}
+	scene := core.NewNode()
+	scene.Add(light.NewAmbient(math32.NewColor(1, 1, 1), 0.05))
+
+	walls := material.NewStandard(math32.NewColor(0.8, 0.8, 0.8))
+	floor := graphic.NewMesh(geometry.NewPlane(8, 8, 1, 1), walls)
+	floor.SetRotationX(-math32.Pi / 2)
+	scene.Add(floor)
+	back := graphic.NewMesh(geometry.NewPlane(8, 4, 1, 1), walls)
+	back.SetPosition(0, 2, -4)
+	scene.Add(back)
+	sphere := graphic.NewMesh(geometry.NewSphere(0.5, 32, 16, 0, 2*math32.Pi, 0, math32.Pi),
+		material.NewStandard(math32.NewColor(0.2, 0.4, 1)))
+	sphere.SetPosition(0, 0.5, 0)
+	scene.Add(sphere)
+
+	cam := camera.NewPerspective(60, 800.0/600.0, 0.1, 100)
+	cam.SetPosition(0, 2, 5)
+	cam.LookAt(&math32.Vector3{Y: 1})
+	return win, gs, rend, scene, cam
+}
+
+// run renders the scene until the window is closed
+func run(win window.IWindow, gs *gls.GLS, rend *renderer.Renderer, scene *core.Node, cam camera.ICamera) {
+
+	for !win.ShouldClose() {
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
+
+// This example lights a room through a window with a rect area light facing
+// the room from the back wall. Press the space key to replace it by a point
+// light at the same position, which produces hard shading and a small
+// specular highlight instead of the soft light and wide highlight of the window.
+func ExampleNewRectArea() {
+
+	win, gs, rend, scene, cam := newExampleRoom("Rect area light")
+
+	area := light.NewRectArea(math32.NewColor(1, 1, 1), 1, 2, 1.5)
+	area.SetPosition(0, 2, -3.99)
+	scene.Add(area)
+	bulb := light.NewPoint(math32.NewColor(1, 1, 1), 0)
+	bulb.SetPosition(0, 2, -3.99)
+	scene.Add(bulb)
+
+	win.Subscribe(window.OnKeyDown, func(evname string, ev interface{}) {
+		if ev.(*window.KeyEvent).Keycode != window.KeySpace {
+			return
+		}
+		if area.Intensity() > 0 {
+			area.SetIntensity(0)
+			bulb.SetIntensity(1)
+		} else {
+			area.SetIntensity(1)
+			bulb.SetIntensity(0)
+		}
+	})
+	run(win, gs, rend, scene, cam)
+}
+
+// This example projects the pattern of window blinds on the floor and the
+// sphere with a spot light with a soft cone edge.
+func ExampleSpot_SetCookie() {
+
+	win, gs, rend, scene, cam := newExampleRoom("Spot light cookie")
+
+	// Horizontal slats with 10 lit rows of every 16
+	blinds := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if y%16 < 10 {
+				blinds.Set(x, y, color.White)
+			} else {
+				blinds.Set(x, y, color.Black)
+			}
+		}
+	}
+	spot := light.NewSpot(math32.NewColor(1, 1, 0.9), 2)
+	spot.SetPosition(0, 3, -3)
+	spot.SetDirection(&math32.Vector3{Y: -0.7, Z: 0.7})
+	spot.SetCutoffAngle(35)
+	spot.SetPenumbra(0.2)
+	spot.SetCookie(texture.NewTexture2DFromRGBA(blinds))
+	scene.Add(spot)
+	run(win, gs, rend, scene, cam)
+}
@@ -0,0 +1,146 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package light
+
+import (
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// RectArea is a light emitted uniformly by a rectangle, such as a window
+// or a light panel, which produces soft diffuse lighting and wide specular
+// highlights. The rectangle lies on the XY plane of the light node, centered
+// at its position, and emits light only to its +Z side, so the light
+// node position and rotation orient the emitting rectangle.
+// The light color multiplied by the intensity is the radiance of the rectangle,
+// so surfaces very close to large lights receive the full light color
+// and there is no decay factor.
+//
+// The diffuse light is the exact irradiance from the rectangle, calculated by
+// integrating its edges as done by the linearly transformed cosines (LTC) method
+// for the cosine distribution, ignoring the parts below the surface horizon.
+// The specular light is approximated with the point of the rectangle closest
+// to the reflected camera direction, scaled by the solid angle of the rectangle.
+// Each rect area light adds 4 uniform vectors, 4 edge integrals and one ray
+// intersection per vertex for the standard material or per fragment for the
+// phong material, which is several times the cost of a point light, and it is
+// ignored by other materials. For example, to light a room through a window
+// with the size of 1.2 x 1.5 and compare with a point light at the same position:
+//
+//	window := light.NewRectArea(math32.NewColor(1, 1, 1), 1.0, 1.2, 1.5)
+//	window.SetPosition(0, 1.5, -3)
+//	scene.Add(window)
+//
+//	bulb := light.NewPoint(math32.NewColor(1, 1, 1), 1.0)
+//	bulb.SetPosition(0, 1.5, -3)
+//	scene.Add(bulb)
+type RectArea struct {
+	core.Node                 // Embedded node
+	color     math32.Color    // Light color
+	intensity float32         // Light intensity
+	width     float32         // Width of the rectangle along the X axis
+	height    float32         // Height of the rectangle along the Y axis
+	uni       *gls.Uniform3fv // Uniform with light properties
+}
+
+const (
+	rColor          = 0 // index of color vector in uniform (0,1,2)
+	rPosition       = 1 // index of position vector in uniform (3,4,5)
+	rHalfWidth      = 2 // index of half width vector in uniform (6,7,8)
+	rHalfHeight     = 3 // index of half height vector in uniform (9,10,11)
+	rectAreaUniSize = 4 // uniform count of 3 float32
+)
+
+// NewRectArea creates and returns a rect area light with the specified
+// color, intensity and size of the emitting rectangle
+func NewRectArea(color *math32.Color, intensity float32, width, height float32) *RectArea {
+
+	ra := new(RectArea)
+	ra.Node.Init()
+	ra.color = *color
+	ra.intensity = intensity
+	ra.width = width
+	ra.height = height
+
+	// Creates uniform and sets initial values
+	ra.uni = gls.NewUniform3fv("RectAreaLight", rectAreaUniSize)
+	ra.SetColor(color)
+	ra.SetIntensity(intensity)
+
+	return ra
+}
+
+// SetColor sets the color of this light
+func (ra *RectArea) SetColor(color *math32.Color) {
+
+	ra.color = *color
+	tmpColor := ra.color
+	tmpColor.MultiplyScalar(ra.intensity)
+	ra.uni.SetColor(rColor, &tmpColor)
+}
+
+// Color returns the current color of this light
+func (ra *RectArea) Color() math32.Color {
+
+	return ra.color
+}
+
+// SetIntensity sets the intensity of this light
+func (ra *RectArea) SetIntensity(intensity float32) {
+
+	ra.intensity = intensity
+	tmpColor := ra.color
+	tmpColor.MultiplyScalar(ra.intensity)
+	ra.uni.SetColor(rColor, &tmpColor)
+}
+
+// Intensity returns the current intensity of this light
+func (ra *RectArea) Intensity() float32 {
+
+	return ra.intensity
+}
+
+// SetSize sets the width and height of the emitting rectangle
+func (ra *RectArea) SetSize(width, height float32) {
+
+	ra.width = width
+	ra.height = height
+}
+
+// Size returns the current width and height of the emitting rectangle
+func (ra *RectArea) Size() (float32, float32) {
+
+	return ra.width, ra.height
+}
+
+// RenderSetup is called by the engine before rendering the scene
+func (ra *RectArea) RenderSetup(gs *gls.GLS, rinfo *core.RenderInfo, idx int) {
+
+	// Calculates and updates light position uniform in camera coordinates
+	var pos math32.Vector3
+	ra.WorldPosition(&pos)
+	var pos4 math32.Vector4
+	pos4.SetVector3(&pos, 1.0)
+	pos4.ApplyMatrix4(&rinfo.ViewMatrix)
+	ra.uni.SetVector3(rPosition, &math32.Vector3{pos4.X, pos4.Y, pos4.Z})
+
+	// Calculates and updates the half sides of the rectangle in camera coordinates
+	var quat math32.Quaternion
+	ra.WorldQuaternion(&quat)
+	halfWidth := math32.Vector3{ra.width / 2, 0, 0}
+	halfWidth.ApplyQuaternion(&quat)
+	pos4.SetVector3(&halfWidth, 0.0)
+	pos4.ApplyMatrix4(&rinfo.ViewMatrix)
+	ra.uni.SetVector3(rHalfWidth, &math32.Vector3{pos4.X, pos4.Y, pos4.Z})
+	halfHeight := math32.Vector3{0, ra.height / 2, 0}
+	halfHeight.ApplyQuaternion(&quat)
+	pos4.SetVector3(&halfHeight, 0.0)
+	pos4.ApplyMatrix4(&rinfo.ViewMatrix)
+	ra.uni.SetVector3(rHalfHeight, &math32.Vector3{pos4.X, pos4.Y, pos4.Z})
+
+	// Transfer uniform
+	ra.uni.TransferIdx(gs, idx*rectAreaUniSize)
+}
@@ -0,0 +1,248 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package light
+
+import (
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// ShadowMap is the depth texture where the renderer draws the graphics which
+// cast shadows from the point of view of a light. It is sampled with 3x3
+// percentage closer filtering by the standard, phong and physical materials
+// to darken the fragments which are not visible from the light.
+// The standard material is lit per fragment while there are shadow maps.
+// For example, to cast the shadow of a box on the ground from the sun
+// using a 2048 x 2048 shadow map:
+//
+//	sun := light.NewDirectional(math32.NewColor(1, 1, 1), 1.0)
+//	sun.SetPosition(1, 2, 1)
+//	sun.SetCastShadow(true)
+//	sun.ShadowMap().SetSize(2048)
+//	scene.Add(sun)
+//
+//	box.SetCastShadow(true)
+type ShadowMap struct {
+	gs       *gls.GLS            // Pointer to OpenGL state
+	size     int32               // width and height of the depth texture in pixels
+	fbo      uint32              // framebuffer handle
+	prevFbo  uint32              // framebuffer bound before rendering the shadow map
+	texname  uint32              // depth texture handle
+	bias     float32             // depth bias to avoid shadow acne
+	area     float32             // width and height of the shadow volume of directional lights
+	near     float32             // distance of the near plane of the shadow volume
+	far      float32             // distance of the far plane of the shadow volume
+	center   math32.Vector3      // center of the shadow volume of directional lights
+	update   bool                // depth texture needs to be created
+	viewProj math32.Matrix4      // light projection matrix multiplied by the light view matrix
+	uMap     gls.Uniform1i       // depth texture unit uniform
+	uMatrix  gls.UniformMatrix4f // transform from camera to shadow map coordinates uniform
+}
+
+// IShadowLight is the interface for the lights which can cast shadows
+type IShadowLight interface {
+	ILight
+	CastShadow() bool
+	ShadowMap() *ShadowMap
+	ShadowCamera(rinfo *core.RenderInfo)
+}
+
+// Default shadow map parameters
+const (
+	shadowDefaultSize = 1024
+	shadowDefaultBias = 0.005
+)
+
+// newShadowMap creates and returns a pointer to a new shadow map with the
+// specified uniforms prefix and near and far distances of the shadow volume.
+func newShadowMap(prefix string, near, far float32) *ShadowMap {
+
+	sm := new(ShadowMap)
+	sm.size = shadowDefaultSize
+	sm.bias = shadowDefaultBias
+	sm.area = 20
+	sm.near = near
+	sm.far = far
+	sm.update = true
+	sm.uMap.Init(prefix + "ShadowMap")
+	sm.uMatrix.Init(prefix + "ShadowMatrix")
+	return sm
+}
+
+// SetSize sets the width and height in pixels of the shadow map.
+// Larger shadow maps produce sharper shadows but use more memory.
+// The default is 1024.
+func (sm *ShadowMap) SetSize(size int) {
+
+	if int32(size) == sm.size {
+		return
+	}
+	sm.size = int32(size)
+	sm.update = true
+}
+
+// Size returns the width and height in pixels of the shadow map
+func (sm *ShadowMap) Size() int {
+
+	return int(sm.size)
+}
+
+// SetBias sets the depth bias subtracted from the fragments depths before
+// comparing them with the shadow map to avoid self shadowing artifacts (acne).
+// The bias is increased for surfaces at grazing angles from the light.
+// Too large values detach the shadows from the objects. The default is 0.005.
+func (sm *ShadowMap) SetBias(bias float32) {
+
+	sm.bias = bias
+}
+
+// Bias returns the current depth bias of the shadow map
+func (sm *ShadowMap) Bias() float32 {
+
+	return sm.bias
+}
+
+// SetRange sets the distances from the light of the near and far planes of
+// the volume where objects cast shadows. For directional lights the
+// distances are relative to the center of the shadow volume and the near
+// distance is normally negative. The defaults are -50 and 50 for directional
+// lights and 0.1 and 100 for spot lights.
+func (sm *ShadowMap) SetRange(near, far float32) {
+
+	sm.near = near
+	sm.far = far
+}
+
+// Range returns the current distances of the near and far planes of the shadow volume
+func (sm *ShadowMap) Range() (float32, float32) {
+
+	return sm.near, sm.far
+}
+
+// SetArea sets the width and height of the orthographic shadow volume of
+// directional lights, which should contain the objects which cast and
+// receive shadows. Smaller areas produce sharper shadows. The default is 20.
+func (sm *ShadowMap) SetArea(area float32) {
+
+	sm.area = area
+}
+
+// Area returns the current width and height of the shadow volume of directional lights
+func (sm *ShadowMap) Area() float32 {
+
+	return sm.area
+}
+
+// SetCenter sets the center in world coordinates of the shadow volume of
+// directional lights. The default is the origin.
+func (sm *ShadowMap) SetCenter(center *math32.Vector3) {
+
+	sm.center = *center
+}
+
+// Center returns the current center of the shadow volume of directional lights
+func (sm *ShadowMap) Center() math32.Vector3 {
+
+	return sm.center
+}
+
+// Begin is called by the renderer to bind the shadow map framebuffer before
+// rendering the depth of the graphics which cast shadows. It creates the
+// depth texture and framebuffer if necessary and sets the viewport to
+// the shadow map size.
+func (sm *ShadowMap) Begin(gs *gls.GLS) {
+
+	// One time initialization
+	if sm.gs == nil {
+		sm.texname = gs.GenTexture()
+		sm.fbo = gs.GenFramebuffer()
+		sm.gs = gs
+	}
+
+	// Creates the depth texture with comparison mode for PCF filtering
+	// and attaches it to the framebuffer
+	if sm.update {
+		prev := gs.Framebuffer()
+		gs.BindTexture(gls.TEXTURE_2D, sm.texname)
+		gs.TexImage2D(gls.TEXTURE_2D, 0, gls.DEPTH_COMPONENT24, sm.size, sm.size, 0, gls.DEPTH_COMPONENT, gls.FLOAT, nil)
+		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_MAG_FILTER, gls.LINEAR)
+		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_MIN_FILTER, gls.LINEAR)
+		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_WRAP_S, gls.CLAMP_TO_EDGE)
+		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_WRAP_T, gls.CLAMP_TO_EDGE)
+		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_COMPARE_MODE, gls.COMPARE_REF_TO_TEXTURE)
+		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_COMPARE_FUNC, gls.LEQUAL)
+		gs.BindFramebuffer(gls.FRAMEBUFFER, sm.fbo)
+		gs.FramebufferTexture2D(gls.FRAMEBUFFER, gls.DEPTH_ATTACHMENT, gls.TEXTURE_2D, sm.texname, 0)
+		gs.DrawBuffer(gls.NONE)
+		gs.ReadBuffer(gls.NONE)
+		if status := gs.CheckFramebufferStatus(gls.FRAMEBUFFER); status != gls.FRAMEBUFFER_COMPLETE {
+			log.Error("Shadow map framebuffer incomplete:%x", status)
+		}
+		gs.BindFramebuffer(gls.FRAMEBUFFER, prev)
+		sm.update = false
+	}
+
+	sm.prevFbo = gs.Framebuffer()
+	gs.BindFramebuffer(gls.FRAMEBUFFER, sm.fbo)
+	gs.Viewport(0, 0, sm.size, sm.size)
+	gs.DepthMask(true)
+	gs.Clear(gls.DEPTH_BUFFER_BIT)
+}
+
+// End is called by the renderer after rendering the depth of the graphics
+// to bind the previous framebuffer and restore the specified viewport.
+func (sm *ShadowMap) End(gs *gls.GLS, x, y, width, height int32) {
+
+	gs.BindFramebuffer(gls.FRAMEBUFFER, sm.prevFbo)
+	gs.Viewport(x, y, width, height)
+}
+
+// setCamera sets the view and projection matrices of the specified
+// render info from the specified light view and projection matrices
+// and saves them to calculate the shadow map coordinates.
+func (sm *ShadowMap) setCamera(rinfo *core.RenderInfo, view, proj *math32.Matrix4) {
+
+	rinfo.ViewMatrix = *view
+	rinfo.ProjMatrix = *proj
+	sm.viewProj.MultiplyMatrices(proj, view)
+}
+
+// RenderSetup is called by the light render setup to bind the depth texture
+// to the specified texture unit and to set the uniforms with the specified
+// index in the shadow maps uniforms.
+func (sm *ShadowMap) RenderSetup(gs *gls.GLS, rinfo *core.RenderInfo, unit, idx int) {
+
+	// Transforms from camera coordinates to the shadow map texture
+	// coordinates and depth in the range [0, 1]
+	var camWorld, mat math32.Matrix4
+	camWorld.GetInverse(&rinfo.ViewMatrix, false)
+	mat.Set(
+		0.5, 0, 0, 0.5,
+		0, 0.5, 0, 0.5,
+		0, 0, 0.5, 0.5,
+		0, 0, 0, 1,
+	)
+	mat.Multiply(&sm.viewProj).Multiply(&camWorld)
+	sm.uMatrix.SetMatrix4(&mat)
+
+	// Binds the depth texture and transfer uniforms
+	gs.ActiveTexture(uint32(gls.TEXTURE0 + unit))
+	gs.BindTexture(gls.TEXTURE_2D, sm.texname)
+	sm.uMap.Set(int32(unit))
+	sm.uMap.TransferIdx(gs, idx)
+	sm.uMatrix.TransferIdx(gs, idx)
+}
+
+// Dispose releases the OpenGL resources of this shadow map
+func (sm *ShadowMap) Dispose() {
+
+	if sm.gs != nil {
+		sm.gs.DeleteFramebuffers(sm.fbo)
+		sm.gs.DeleteTextures(sm.texname)
+		sm.gs = nil
+	}
+	sm.update = true
+}
@@ -8,14 +8,20 @@ import (
 	"github.com/g3n/engine/core"
 	"github.com/g3n/engine/gls"
 	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/texture"
 )
 
 type Spot struct {
-	core.Node                 // Embedded node
-	color     math32.Color    // Light color
-	intensity float32         // Light intensity
-	direction math32.Vector3  // Direction in world coordinates
-	uni       *gls.Uniform3fv // Uniform with spot light properties
+	core.Node                       // Embedded node
+	color       math32.Color        // Light color
+	intensity   float32             // Light intensity
+	direction   math32.Vector3      // Direction in world coordinates
+	uni         *gls.Uniform3fv     // Uniform with spot light properties
+	cookie      *texture.Texture2D  // Optional texture projected by the light
+	uCookie     gls.Uniform1i       // Cookie texture unit uniform
+	uCookieProj gls.UniformMatrix4f // Cookie projection from camera coordinates uniform
+	castShadow  bool                // casts shadows flag
+	shadow      *ShadowMap          // shadow map or nil
 }
 
 const (
@@ -26,7 +32,11 @@ const (
 	sCutoffAngle    = 10 // position of cutoff angle in uniform array
 	sLinearDecay    = 11 // position of scalar linear decay in uniform array
 	sQuadraticDecay = 12 // position of scalar quadratic decay in uniform array
-	spotUniSize     = 5  // uniform count of 5 float32
+	sPenumbra       = 13 // position of penumbra fraction in uniform array
+	sCookie         = 14 // position of the index of the cookie texture in uniform array
+	sShadow         = 15 // position of the index of the shadow map in uniform array
+	sShadowBias     = 16 // position of the shadow map depth bias in uniform array
+	spotUniSize     = 6  // uniform count of 6 float32
 )
 
 // NewSpot creates and returns a spot light with the specified color and intensity
@@ -45,6 +55,11 @@ func NewSpot(color *math32.Color, intensity float32) *Spot {
 	sl.SetCutoffAngle(45.0)
 	sl.SetLinearDecay(1.0)
 	sl.SetQuadraticDecay(1.0)
+	sl.SetPenumbra(0)
+	sl.uni.SetPos(sCookie, -1)
+	sl.uni.SetPos(sShadow, -1)
+	sl.uCookie.Init("SpotLightCookie")
+	sl.uCookieProj.Init("SpotLightCookieMatrix")
 
 	return sl
 }
@@ -103,6 +118,60 @@ func (sl *Spot) CutoffAngle() float32 {
 	return sl.uni.GetPos(sCutoffAngle)
 }
 
+// SetPenumbra sets the fraction of the cutoff angle from 0 to 1 where the
+// light intensity falls smoothly to zero at the edge of the cone.
+// The inner angle with full intensity is the cutoff angle multiplied by
+// 1 - penumbra. The default is 0, which produces a hard edge.
+func (sl *Spot) SetPenumbra(penumbra float32) {
+
+	sl.uni.SetPos(sPenumbra, math32.Clamp(penumbra, 0, 1))
+}
+
+// Penumbra returns the current fraction of the cutoff angle with smooth falloff
+func (sl *Spot) Penumbra() float32 {
+
+	return sl.uni.GetPos(sPenumbra)
+}
+
+// SetCookie sets the texture projected by this spot light, whose color
+// multiplies the light color, such as a gobo pattern or window blinds.
+// The texture covers the square which contains the cone of the cutoff angle,
+// with its top in the direction of the world +Y axis or of the +Z axis if the
+// light direction is vertical. Cookies are used by the standard, phong and
+// physical materials and each one uses one additional texture unit.
+// The texture is disposed with the light, so a texture shared with
+// other objects must have its reference count incremented with Incref.
+// A nil texture removes the current cookie. For example, to project blinds:
+//
+//	blinds := image.NewRGBA(image.Rect(0, 0, 64, 64))
+//	for y := 0; y < 64; y++ {
+//		for x := 0; x < 64; x++ {
+//			if y%16 < 10 {
+//				blinds.Set(x, y, color.White)
+//			} else {
+//				blinds.Set(x, y, color.Black)
+//			}
+//		}
+//	}
+//	spot := light.NewSpot(math32.NewColor(1, 1, 1), 1.0)
+//	spot.SetPosition(0, 3, 3)
+//	spot.SetDirection(&math32.Vector3{0, -0.7, -0.7})
+//	spot.SetPenumbra(0.2)
+//	spot.SetCookie(texture.NewTexture2DFromRGBA(blinds))
+func (sl *Spot) SetCookie(cookie *texture.Texture2D) {
+
+	sl.cookie = cookie
+	if cookie == nil {
+		sl.uni.SetPos(sCookie, -1)
+	}
+}
+
+// Cookie returns the current texture projected by this spot light or nil
+func (sl *Spot) Cookie() *texture.Texture2D {
+
+	return sl.cookie
+}
+
 // SetAngularDecay sets the angular decay exponent
 func (sl *Spot) SetAngularDecay(decay float32) {
 
@@ -159,3 +228,113 @@ func (sl *Spot) RenderSetup(gs *gls.GLS, rinfo *core.RenderInfo, idx int) {
 	// Transfer uniform
 	sl.uni.TransferIdx(gs, idx*spotUniSize)
 }
+
+// SetCastShadow sets if this light casts shadows of the graphics which
+// have cast shadow enabled. The shadow map is created when it is first
+// enabled and can be configured with ShadowMap(). The default is false.
+func (sl *Spot) SetCastShadow(state bool) {
+
+	sl.castShadow = state
+	if state && sl.shadow == nil {
+		sl.shadow = newShadowMap("Spot", 0.1, 100)
+	}
+	if !state {
+		sl.uni.SetPos(sShadow, -1)
+	}
+}
+
+// CastShadow returns if this light casts shadows
+func (sl *Spot) CastShadow() bool {
+
+	return sl.castShadow
+}
+
+// ShadowMap returns the shadow map of this light, which is created
+// if necessary, to set its resolution and other parameters.
+func (sl *Spot) ShadowMap() *ShadowMap {
+
+	if sl.shadow == nil {
+		sl.shadow = newShadowMap("Spot", 0.1, 100)
+	}
+	return sl.shadow
+}
+
+// Dispose overrides the embedded Node Dispose method and releases the
+// OpenGL resources of the shadow map and of the cookie texture if set
+func (sl *Spot) Dispose() {
+
+	if sl.shadow != nil {
+		sl.shadow.Dispose()
+	}
+	if sl.cookie != nil {
+		sl.cookie.Dispose()
+	}
+}
+
+// ShadowCamera satisfies the IShadowLight interface and sets the view and
+// projection matrices of the specified render info to the perspective
+// projection of the cone of the light.
+func (sl *Spot) ShadowCamera(rinfo *core.RenderInfo) {
+
+	var pos math32.Vector3
+	sl.WorldPosition(&pos)
+	var target math32.Vector3
+	target.AddVectors(&pos, &sl.direction)
+	up := math32.Vector3{0, 1, 0}
+	dir := sl.direction
+	dir.Normalize()
+	if math32.Abs(dir.Y) > 0.999 {
+		up = math32.Vector3{0, 0, 1}
+	}
+	sm := sl.ShadowMap()
+	var view, proj math32.Matrix4
+	view.LookAt(&pos, &target, &up)
+	proj.MakePerspective(2*math32.Clamp(sl.CutoffAngle(), 0.1, 89), 1, sm.near, sm.far)
+	sm.setCamera(rinfo, &view, &proj)
+}
+
+// ShadowSetup is called by the engine before RenderSetup for lights which
+// cast shadows to bind the shadow map to the specified texture unit and
+// to set the shadow map uniforms with the specified index.
+func (sl *Spot) ShadowSetup(gs *gls.GLS, rinfo *core.RenderInfo, unit, idx int) {
+
+	sl.uni.SetPos(sShadow, float32(idx))
+	sl.uni.SetPos(sShadowBias, sl.shadow.bias)
+	sl.shadow.RenderSetup(gs, rinfo, unit, idx)
+}
+
+// CookieSetup is called by the engine before RenderSetup for spot lights with
+// a cookie to bind the cookie texture to the specified texture unit and to
+// set the cookie uniforms with the specified index.
+func (sl *Spot) CookieSetup(gs *gls.GLS, rinfo *core.RenderInfo, unit, idx int) {
+
+	// Calculates the light view matrix
+	var pos math32.Vector3
+	sl.WorldPosition(&pos)
+	var target math32.Vector3
+	target.AddVectors(&pos, &sl.direction)
+	up := math32.Vector3{0, 1, 0}
+	dir := sl.direction
+	dir.Normalize()
+	if math32.Abs(dir.Y) > 0.999 {
+		up = math32.Vector3{0, 0, 1}
+	}
+	var lightView math32.Matrix4
+	lightView.LookAt(&pos, &target, &up)
+
+	// Calculates the projection of the cone into the texture
+	// from the camera coordinates
+	fov := 2 * math32.Clamp(sl.CutoffAngle(), 0.1, 89)
+	var proj, camWorld math32.Matrix4
+	proj.MakePerspective(fov, 1, 0.01, 1000)
+	camWorld.GetInverse(&rinfo.ViewMatrix, false)
+	proj.Multiply(&lightView).Multiply(&camWorld)
+	sl.uCookieProj.SetMatrix4(&proj)
+
+	// Binds the cookie and transfer its uniforms
+	sl.uni.SetPos(sCookie, float32(idx))
+	sl.cookie.Bind(gs, unit)
+	sl.uCookie.Set(int32(unit))
+	sl.uCookie.TransferIdx(gs, idx)
+	sl.uCookieProj.TransferIdx(gs, idx)
+}
@@ -21,13 +21,15 @@ type AnimationTarget struct {
 	maxInput float32        // maximum input value for all channels
 	loop     bool           // animation loop flag
 	rot      math32.Vector3 // rotation in XYZ Euler angles
+	rotated  bool           // target has rotation channels
 	channels []*ChannelInstance
 }
 
 // A ChannelInstance associates an animation parameter channel to an interpolation sampler
 type ChannelInstance struct {
-	sampler *SamplerInstance
-	action  ActionFunc
+	sampler   *SamplerInstance
+	action    ActionFunc
+	transform bool // channel animates the full transformation matrix
 }
 
 // SamplerInstance specifies the input key frames, output values for these key frames
@@ -61,6 +63,13 @@ func (at *AnimationTarget) SetStart(v float32) {
 	at.start = v
 }
 
+// Duration returns the difference between the last and
+// the first input values of all the animation target channels
+func (at *AnimationTarget) Duration() float32 {
+
+	return at.maxInput - at.minInput
+}
+
 // Update interpolates the specified input value for each animation target channel
 // and executes its corresponding action function. Returns true if the input value
 // is inside the key frames ranges or false otherwise.
@@ -83,6 +92,15 @@ func (at *AnimationTarget) Update(delta float32) bool {
 
 	for i := 0; i < len(at.channels); i++ {
 		ch := at.channels[i]
+		// Get interpolated transformation matrix and decomposes it
+		if ch.transform {
+			var m math32.Matrix4
+			if !ch.sampler.InterpolateMatrix(at.last, &m) {
+				return false
+			}
+			actionTransform(at, &m)
+			continue
+		}
 		// Get interpolated value
 		v, ok := ch.sampler.Interpolate(at.last)
 		if !ok {
@@ -91,7 +109,9 @@ func (at *AnimationTarget) Update(delta float32) bool {
 		// Call action func
 		ch.action(at, v)
 		// Sets final rotation
-		at.target.GetNode().SetRotation(at.rot.X, at.rot.Y, at.rot.Z)
+		if at.rotated {
+			at.target.GetNode().SetRotation(at.rot.X, at.rot.Y, at.rot.Z)
+		}
 	}
 	return true
 }
@@ -99,6 +119,8 @@ func (at *AnimationTarget) Update(delta float32) bool {
 // NewAnimationTargets creates and returns a map of all animation targets
 // contained in the decoded Collada document and for the previously decoded scene.
 // The map is indexed by the node loaderID.
+// Channels targeting the "transform" matrix of a node, as exported for the
+// joints of skinned meshes, set the node position, rotation and scale.
 func (d *Decoder) NewAnimationTargets(scene core.INode) (map[string]*AnimationTarget, error) {
 
 	if d.dom.LibraryAnimations == nil {
@@ -146,7 +168,13 @@ func (d *Decoder) NewAnimationTargets(scene core.INode) (map[string]*AnimationTa
 
 			// Sets the action function from the target action
 			var af ActionFunc
+			var transform bool
 			switch targetAction {
+			case "transform":
+				if len(si.Output) < 16*len(si.Input) {
+					return nil, fmt.Errorf("Channel:%s output is not a matrix", cc.Target)
+				}
+				transform = true
 			case "location.X":
 				af = actionPositionX
 			case "location.Y":
@@ -155,10 +183,13 @@ func (d *Decoder) NewAnimationTargets(scene core.INode) (map[string]*AnimationTa
 				af = actionPositionZ
 			case "rotationX.ANGLE":
 				af = actionRotationX
+				at.rotated = true
 			case "rotationY.ANGLE":
 				af = actionRotationY
+				at.rotated = true
 			case "rotationZ.ANGLE":
 				af = actionRotationZ
+				at.rotated = true
 			case "scale.X":
 				af = actionScaleX
 			case "scale.Y":
@@ -171,7 +202,7 @@ func (d *Decoder) NewAnimationTargets(scene core.INode) (map[string]*AnimationTa
 
 			// Creates the channel instance for this sampler and target action and adds it
 			// to the current AnimationTarget
-			ci := &ChannelInstance{si, af}
+			ci := &ChannelInstance{si, af, transform}
 			at.channels = append(at.channels, ci)
 		}
 	}
@@ -240,6 +271,18 @@ func actionScaleZ(at *AnimationTarget, v float32) {
 	at.target.GetNode().SetScaleZ(v)
 }
 
+func actionTransform(at *AnimationTarget, m *math32.Matrix4) {
+
+	var position math32.Vector3
+	var quaternion math32.Quaternion
+	var scale math32.Vector3
+	m.Decompose(&position, &quaternion, &scale)
+	n := at.target.GetNode()
+	n.SetPositionVec(&position)
+	n.SetQuaternionQuat(&quaternion)
+	n.SetScaleVec(&scale)
+}
+
 // NewSampler creates and returns a pointer to a new SamplerInstance built
 // with data from the specified Collada animation and URI
 func NewSamplerInstance(ca *Animation, uri string) (*SamplerInstance, error) {
@@ -350,6 +393,44 @@ func (si *SamplerInstance) Interpolate(inp float32) (float32, bool) {
 	return 0, false
 }
 
+// InterpolateMatrix sets the specified matrix with the interpolated output
+// of this sampler of transformation matrices for the specified input and
+// returns its validity. The matrices are interpolated element by element.
+func (si *SamplerInstance) InterpolateMatrix(inp float32, m *math32.Matrix4) bool {
+
+	// Test limits
+	if len(si.Input) < 2 || len(si.Output) < 16*len(si.Input) {
+		return false
+	}
+	if inp < si.Input[0] || inp > si.Input[len(si.Input)-1] {
+		return false
+	}
+
+	// Find key frame interval
+	var idx int
+	for idx = 0; idx < len(si.Input)-1; idx++ {
+		if inp >= si.Input[idx] && inp < si.Input[idx+1] {
+			break
+		}
+	}
+	if idx >= len(si.Input)-1 {
+		return false
+	}
+
+	// The output matrices are row major
+	var t float32
+	if idx >= len(si.Interp) || si.Interp[idx] != "STEP" {
+		t = (inp - si.Input[idx]) / (si.Input[idx+1] - si.Input[idx])
+	}
+	m0 := si.Output[16*idx : 16*(idx+1)]
+	m1 := si.Output[16*(idx+1) : 16*(idx+2)]
+	for i := 0; i < 16; i++ {
+		m[i] = m0[i] + (m1[i]-m0[i])*t
+	}
+	m.Transpose()
+	return true
+}
+
 func (si *SamplerInstance) linearInterp(inp float32, idx int) float32 {
 
 	k1 := si.Input[idx]
@@ -23,6 +23,7 @@ type Decoder struct {
 	geometries map[string]geomInstance       // Instanced geometries by id
 	materials  map[string]material.IMaterial // Instanced materials by id
 	tex2D      map[string]*texture.Texture2D // Instanced textures 2D by id
+	skins      map[string]*SkinInstance      // Skins of the last created scene by node id
 }
 
 type geomInstance struct {
@@ -76,6 +77,7 @@ type Collada struct {
 	LibraryEffects      *LibraryEffects
 	LibraryMaterials    *LibraryMaterials
 	LibraryGeometries   *LibraryGeometries
+	LibraryControllers  *LibraryControllers
 	LibraryVisualScenes *LibraryVisualScenes
 	Scene               *Scene
 }
@@ -94,6 +96,7 @@ func (d *Decoder) Dump(out io.Writer, indent int) {
 	d.dom.LibraryEffects.Dump(out, indent+step)
 	d.dom.LibraryMaterials.Dump(out, indent+step)
 	d.dom.LibraryGeometries.Dump(out, indent+step)
+	d.dom.LibraryControllers.Dump(out, indent+step)
 	d.dom.LibraryVisualScenes.Dump(out, indent+step)
 	d.dom.Scene.Dump(out, indent+step)
 }
@@ -202,6 +205,9 @@ func (d *Decoder) decCollada(dom *Collada) error {
 		if err == io.EOF {
 			return nil
 		}
+		if err != nil {
+			return err
+		}
 		// If not a start element ignore and continue
 		start, ok := tok.(xml.StartElement)
 		if !ok {
@@ -267,6 +273,13 @@ func (d *Decoder) decCollada(dom *Collada) error {
 			}
 			continue
 		}
+		if start.Name.Local == "library_controllers" {
+			err = d.decLibraryControllers(start, dom)
+			if err != nil {
+				break
+			}
+			continue
+		}
 		if start.Name.Local == "library_visual_scenes" {
 			err = d.decLibraryVisualScenes(start, dom)
 			if err != nil {
@@ -127,7 +127,7 @@ func (d *Decoder) decSource(start xml.StartElement) (*Source, error) {
 			}
 			continue
 		}
-		if child.Name.Local == "Name_array" {
+		if child.Name.Local == "Name_array" || child.Name.Local == "IDREF_array" {
 			err = d.decNameArray(child, data, source)
 			if err != nil {
 				return nil, err
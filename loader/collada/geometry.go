@@ -111,36 +111,49 @@ func newMesh(m *Mesh) (*geometry.Geometry, uint32, error) {
 // Only triangles are supported
 func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, error) {
 
+	geom, _, err := newPolylistGeometry(m, pels)
+	if err != nil {
+		return nil, 0, err
+	}
+	return geom, gls.TRIANGLES, nil
+}
+
+// newPolylistGeometry creates a geometry from a polylist and returns it
+// with the index of the mesh position of each vertex of the geometry
+func newPolylistGeometry(m *Mesh, pels []interface{}) (*geometry.Geometry, []int, error) {
+
 	// Get vertices positions
 	if len(m.Vertices.Input) != 1 {
-		return nil, 0, fmt.Errorf("Mesh.Vertices.Input length not supported")
+		return nil, nil, fmt.Errorf("Mesh.Vertices.Input length not supported")
 	}
 	vinp := m.Vertices.Input[0]
 	if vinp.Semantic != "POSITION" {
-		return nil, 0, fmt.Errorf("Mesh.Vertices.Input.Semantic:%s not supported", vinp.Semantic)
+		return nil, nil, fmt.Errorf("Mesh.Vertices.Input.Semantic:%s not supported", vinp.Semantic)
 	}
 
 	// Get vertices input source
 	inps := getMeshSource(m, vinp.Source)
 	if inps == nil {
-		return nil, 0, fmt.Errorf("Source:%s not found", vinp.Source)
+		return nil, nil, fmt.Errorf("Source:%s not found", vinp.Source)
 	}
 
 	// Get vertices input float array
 	// Ignore Accessor (??)
 	posArray, ok := inps.ArrayElement.(*FloatArray)
 	if !ok {
-		return nil, 0, fmt.Errorf("Mesh.Vertices.Input.Source not FloatArray")
+		return nil, nil, fmt.Errorf("Mesh.Vertices.Input.Source not FloatArray")
 	}
 
 	// Creates buffers
 	positions := math32.NewArrayF32(0, 0)
 	normals := math32.NewArrayF32(0, 0)
 	uvs := math32.NewArrayF32(0, 0)
+	uvs2 := math32.NewArrayF32(0, 0)
 	indices := math32.NewArrayU32(0, 0)
+	vpos := make([]int, 0)
 
 	// Creates vertices attributes map for reusing indices
-	mVindex := make(map[[8]float32]uint32)
+	mVindex := make(map[[10]float32]uint32)
 	var index uint32 = 0
 	geomGroups := make([]geometry.Group, 0)
 	groupMatindex := 0
@@ -149,7 +162,7 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 		// Checks if element is Polylist
 		pl, ok := pel.(*Polylist)
 		if !ok {
-			return nil, 0, fmt.Errorf("Element is not a Polylist")
+			return nil, nil, fmt.Errorf("Element is not a Polylist")
 		}
 		// If Polylist has not inputs, ignore
 		if pl.Input == nil || len(pl.Input) == 0 {
@@ -158,13 +171,13 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 		// Checks if all Vcount elements are triangles
 		for _, v := range pl.Vcount {
 			if v != 3 {
-				return nil, 0, fmt.Errorf("Only triangles are supported in Polylist")
+				return nil, nil, fmt.Errorf("Only triangles are supported in Polylist")
 			}
 		}
 		// Get VERTEX input
 		inpVertex := getInputSemantic(pl.Input, "VERTEX")
 		if inpVertex == nil {
-			return nil, 0, fmt.Errorf("VERTEX input not found")
+			return nil, nil, fmt.Errorf("VERTEX input not found")
 		}
 
 		// Get optional NORMAL input
@@ -174,12 +187,12 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 			// Get normals source
 			source := getMeshSource(m, inpNormal.Source)
 			if source == nil {
-				return nil, 0, fmt.Errorf("NORMAL source:%s not found", inpNormal.Source)
+				return nil, nil, fmt.Errorf("NORMAL source:%s not found", inpNormal.Source)
 			}
 			// Get normals source float array
 			normArray, ok = source.ArrayElement.(*FloatArray)
 			if !ok {
-				return nil, 0, fmt.Errorf("NORMAL source:%s not float array", inpNormal.Source)
+				return nil, nil, fmt.Errorf("NORMAL source:%s not float array", inpNormal.Source)
 			}
 		}
 
@@ -190,12 +203,26 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 			// Get texture coordinates source
 			source := getMeshSource(m, inpTexcoord.Source)
 			if source == nil {
-				return nil, 0, fmt.Errorf("TEXCOORD source:%s not found", inpTexcoord.Source)
+				return nil, nil, fmt.Errorf("TEXCOORD source:%s not found", inpTexcoord.Source)
 			}
 			// Get texture coordinates source float array
 			texArray, ok = source.ArrayElement.(*FloatArray)
 			if !ok {
-				return nil, 0, fmt.Errorf("TEXCOORD source:%s not float array", inpTexcoord.Source)
+				return nil, nil, fmt.Errorf("TEXCOORD source:%s not float array", inpTexcoord.Source)
+			}
+		}
+
+		// Get optional second TEXCOORD input, such as for lightmaps
+		inpTexcoord2 := getInputSemanticNext(pl.Input, inpTexcoord)
+		var texArray2 *FloatArray
+		if inpTexcoord2 != nil {
+			source := getMeshSource(m, inpTexcoord2.Source)
+			if source == nil {
+				return nil, nil, fmt.Errorf("TEXCOORD source:%s not found", inpTexcoord2.Source)
+			}
+			texArray2, ok = source.ArrayElement.(*FloatArray)
+			if !ok {
+				return nil, nil, fmt.Errorf("TEXCOORD source:%s not float array", inpTexcoord2.Source)
 			}
 		}
 
@@ -204,8 +231,8 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 		// For each primitive index
 		inputCount := len(pl.Input)
 		for i := 0; i < len(pl.P); i += inputCount {
-			// Vertex attributes: position(3) + normal(3) + uv(2)
-			var vx [8]float32
+			// Vertex attributes: position(3) + normal(3) + uv(2) + uv2(2)
+			var vx [10]float32
 
 			// Vertex position
 			posIndex := pl.P[i+inpVertex.Offset] * 3
@@ -233,6 +260,13 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 				vx[7] = texArray.Data[texIndex+1]
 			}
 
+			// Optional second vertex texture coordinate
+			if inpTexcoord2 != nil {
+				texIndex := pl.P[i+inpTexcoord2.Offset] * 2
+				vx[8] = texArray2.Data[texIndex]
+				vx[9] = texArray2.Data[texIndex+1]
+			}
+
 			// If this vertex and its attributes has already been appended,
 			// reuse it, adding its index to the index buffer
 			// to reuse its index
@@ -249,7 +283,11 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 			if inpTexcoord != nil {
 				uvs.Append(vx[6], vx[7])
 			}
+			if inpTexcoord2 != nil {
+				uvs2.Append(vx[8], vx[9])
+			}
 			indices.Append(index)
+			vpos = append(vpos, posIndex/3)
 			// Save the index to this vertex position and attributes for
 			// future reuse
 			mVindex[vx] = index
@@ -304,13 +342,20 @@ func newMeshPolylist(m *Mesh, pels []interface{}) (*geometry.Geometry, uint32, e
 		geom.AddVBO(vboUvs)
 	}
 
+	// Creates VBO with the second set of uv coordinates
+	if uvs2.Size() > 0 {
+		vboUvs2 := gls.NewVBO()
+		vboUvs2.AddAttrib("VertexTexcoord2", 2).SetBuffer(uvs2)
+		geom.AddVBO(vboUvs2)
+	}
+
 	// Sets the geometry indices buffer
 	geom.SetIndices(indices)
 
 	// Add material groups to the geometry
 	geom.AddGroupList(geomGroups)
 
-	return geom, gls.TRIANGLES, nil
+	return geom, vpos, nil
 }
 
 func newMeshTriangles(m *Mesh, tr *Triangles) (*geometry.Geometry, uint32, error) {
@@ -469,3 +514,22 @@ func getInputSemantic(inps []InputShared, semantic string) *InputShared {
 	}
 	return nil
 }
+
+// getInputSemanticNext returns the first input after the specified input
+// with the same semantic or nil if not found
+func getInputSemanticNext(inps []InputShared, prev *InputShared) *InputShared {
+
+	if prev == nil {
+		return nil
+	}
+	found := false
+	for i := 0; i < len(inps); i++ {
+		if found && inps[i].Semantic == prev.Semantic {
+			return &inps[i]
+		}
+		if &inps[i] == prev {
+			found = true
+		}
+	}
+	return nil
+}
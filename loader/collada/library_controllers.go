@@ -0,0 +1,242 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collada
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Library Controllers
+type LibraryControllers struct {
+	Id         string
+	Name       string
+	Asset      *Asset
+	Controller []*Controller
+}
+
+func (lc *LibraryControllers) Dump(out io.Writer, indent int) {
+
+	if lc == nil {
+		return
+	}
+	fmt.Fprintf(out, "%sLibraryControllers id:%s name:%s\n", sIndent(indent), lc.Id, lc.Name)
+	for _, c := range lc.Controller {
+		c.Dump(out, indent+step)
+	}
+}
+
+// Controller
+// Only skin controllers are supported
+type Controller struct {
+	Id   string
+	Name string
+	Skin *Skin
+}
+
+func (c *Controller) Dump(out io.Writer, indent int) {
+
+	fmt.Fprintf(out, "%sController id:%s name:%s\n", sIndent(indent), c.Id, c.Name)
+	if c.Skin != nil {
+		c.Skin.Dump(out, indent+step)
+	}
+}
+
+// Skin
+type Skin struct {
+	Source          string      // URL of the skinned geometry
+	BindShapeMatrix [16]float32 // Transform of the geometry in the bind pose (row major)
+	Sources         []*Source
+	Joints          Joints
+	VertexWeights   VertexWeights
+}
+
+func (s *Skin) Dump(out io.Writer, indent int) {
+
+	fmt.Fprintf(out, "%sSkin source:%s\n", sIndent(indent), s.Source)
+	ind := indent + step
+	fmt.Fprintf(out, "%sBindShapeMatrix:%v\n", sIndent(ind), s.BindShapeMatrix)
+	for _, source := range s.Sources {
+		source.Dump(out, ind)
+	}
+	s.Joints.Dump(out, ind)
+	s.VertexWeights.Dump(out, ind)
+}
+
+// Joints
+type Joints struct {
+	Input []Input // JOINT and INV_BIND_MATRIX inputs
+}
+
+func (j *Joints) Dump(out io.Writer, indent int) {
+
+	fmt.Fprintf(out, "%sJoints\n", sIndent(indent))
+	for _, inp := range j.Input {
+		inp.Dump(out, indent+step)
+	}
+}
+
+// VertexWeights
+type VertexWeights struct {
+	Count  int
+	Input  []InputShared // JOINT and WEIGHT inputs
+	Vcount []int         // Number of influences of each vertex
+	V      []int         // Indices of the joint and weight of each influence
+}
+
+func (vw *VertexWeights) Dump(out io.Writer, indent int) {
+
+	fmt.Fprintf(out, "%sVertexWeights count:%d\n", sIndent(indent), vw.Count)
+	ind := indent + step
+	for _, is := range vw.Input {
+		is.Dump(out, ind)
+	}
+	fmt.Fprintf(out, "%sVcount(%d):%v\n", sIndent(ind), len(vw.Vcount), intsToString(vw.Vcount, 20))
+	fmt.Fprintf(out, "%sV(%d):%v\n", sIndent(ind), len(vw.V), intsToString(vw.V, 20))
+}
+
+func (d *Decoder) decLibraryControllers(start xml.StartElement, dom *Collada) error {
+
+	lc := new(LibraryControllers)
+	dom.LibraryControllers = lc
+	lc.Id = findAttrib(start, "id").Value
+	lc.Name = findAttrib(start, "name").Value
+
+	for {
+		child, _, err := d.decNextChild(start)
+		if err != nil || child.Name.Local == "" {
+			return err
+		}
+		if child.Name.Local == "controller" {
+			err := d.decController(child, lc)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+	}
+}
+
+func (d *Decoder) decController(start xml.StartElement, lc *LibraryControllers) error {
+
+	c := new(Controller)
+	lc.Controller = append(lc.Controller, c)
+	c.Id = findAttrib(start, "id").Value
+	c.Name = findAttrib(start, "name").Value
+
+	for {
+		child, _, err := d.decNextChild(start)
+		if err != nil || child.Name.Local == "" {
+			return err
+		}
+		if child.Name.Local == "skin" {
+			err := d.decSkin(child, c)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+	}
+}
+
+func (d *Decoder) decSkin(start xml.StartElement, c *Controller) error {
+
+	s := new(Skin)
+	c.Skin = s
+	s.Source = findAttrib(start, "source").Value
+	// The default bind shape matrix is the identity
+	s.BindShapeMatrix = [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+
+	for {
+		child, data, err := d.decNextChild(start)
+		if err != nil || child.Name.Local == "" {
+			return err
+		}
+		if child.Name.Local == "bind_shape_matrix" {
+			err := decFloat32Sequence(data, s.BindShapeMatrix[0:16])
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if child.Name.Local == "source" {
+			source, err := d.decSource(child)
+			if err != nil {
+				return err
+			}
+			s.Sources = append(s.Sources, source)
+			continue
+		}
+		if child.Name.Local == "joints" {
+			err := d.decJoints(child, &s.Joints)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if child.Name.Local == "vertex_weights" {
+			err := d.decVertexWeights(child, &s.VertexWeights)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+	}
+}
+
+func (d *Decoder) decJoints(start xml.StartElement, j *Joints) error {
+
+	for {
+		child, _, err := d.decNextChild(start)
+		if err != nil || child.Name.Local == "" {
+			return err
+		}
+		if child.Name.Local == "input" {
+			inp, err := d.decInput(child)
+			if err != nil {
+				return err
+			}
+			j.Input = append(j.Input, inp)
+			continue
+		}
+	}
+}
+
+func (d *Decoder) decVertexWeights(start xml.StartElement, vw *VertexWeights) error {
+
+	vw.Count, _ = strconv.Atoi(findAttrib(start, "count").Value)
+	for {
+		child, data, err := d.decNextChild(start)
+		if err != nil || child.Name.Local == "" {
+			return err
+		}
+		if child.Name.Local == "input" {
+			inp, err := d.decInputShared(child)
+			if err != nil {
+				return err
+			}
+			vw.Input = append(vw.Input, inp)
+			continue
+		}
+		if child.Name.Local == "vcount" {
+			vc, err := d.decVcount(child, data, vw.Count)
+			if err != nil {
+				return err
+			}
+			vw.Vcount = vc
+			continue
+		}
+		if child.Name.Local == "v" {
+			v, err := d.decPrimitive(child, data)
+			if err != nil {
+				return err
+			}
+			vw.V = v
+			continue
+		}
+	}
+}
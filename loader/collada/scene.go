@@ -39,6 +39,7 @@ func (d *Decoder) NewScene() (core.INode, error) {
 	}
 
 	// Creates each node and adds it to the scene
+	d.skins = make(map[string]*SkinInstance)
 	for _, n := range vs.Node {
 		node, err := d.newNode(n)
 		if err != nil {
@@ -46,6 +47,14 @@ func (d *Decoder) NewScene() (core.INode, error) {
 		}
 		scene.Add(node)
 	}
+
+	// Binds the skins to the joints which may be anywhere in the scene
+	for _, skin := range d.skins {
+		err := skin.bindJoints(vs, scene)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return scene, nil
 }
 
@@ -68,21 +77,9 @@ func (d *Decoder) newNode(cnode *Node) (core.INode, error) {
 		switch gtype {
 		case gls.TRIANGLES:
 			mesh := graphic.NewMesh(geomi, nil)
-			geom := geomi.GetGeometry()
-			// Associates the material in <bind_material> with the geometry group material
-			for _, im := range nt.BindMaterial.TechniqueCommon.InstanceMaterial {
-				matid := strings.TrimPrefix(im.Target, "#")
-				for i := 0; i < geom.GroupCount(); i++ {
-					group := geom.GroupAt(i)
-					if group.Matid == matid {
-						mat, err := d.GetMaterial(im.Target)
-						if err != nil {
-							return nil, err
-						}
-						mesh.AddGroupMaterial(mat, i)
-						break
-					}
-				}
+			err := d.addGroupMaterials(mesh, nt.BindMaterial)
+			if err != nil {
+				return nil, err
 			}
 			node = mesh
 
@@ -102,6 +99,14 @@ func (d *Decoder) newNode(cnode *Node) (core.INode, error) {
 		default:
 			return nil, fmt.Errorf("primitive not supported")
 		}
+		// Skinned geometry
+	case *InstanceController:
+		skin, err := d.newSkinInstance(nt)
+		if err != nil {
+			return nil, err
+		}
+		d.skins[cnode.Id] = skin
+		node = skin.mesh
 	default:
 		return nil, fmt.Errorf("instance geometry type:%T not supported", nt)
 	}
@@ -156,6 +161,31 @@ func (d *Decoder) newNode(cnode *Node) (core.INode, error) {
 	return node, nil
 }
 
+// addGroupMaterials associates the materials in <bind_material>
+// with the geometry group materials of the specified mesh
+func (d *Decoder) addGroupMaterials(mesh *graphic.Mesh, bm *BindMaterial) error {
+
+	if bm == nil {
+		return nil
+	}
+	geom := mesh.GetGeometry()
+	for _, im := range bm.TechniqueCommon.InstanceMaterial {
+		matid := strings.TrimPrefix(im.Target, "#")
+		for i := 0; i < geom.GroupCount(); i++ {
+			group := geom.GroupAt(i)
+			if group.Matid == matid {
+				mat, err := d.GetMaterial(im.Target)
+				if err != nil {
+					return err
+				}
+				mesh.AddGroupMaterial(mat, i)
+				break
+			}
+		}
+	}
+	return nil
+}
+
 func findVisualScene(dom *Collada, uri string) *VisualScene {
 
 	id := strings.TrimPrefix(uri, "#")
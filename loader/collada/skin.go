@@ -0,0 +1,362 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collada
+
+import (
+	"fmt"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/math32"
+	"strings"
+)
+
+// Maximum number of joints which influence a vertex
+const maxInfluences = 4
+
+// SkinInstance deforms the geometry of a mesh created from a skin controller
+// by the current transforms of the joint nodes of its skeleton.
+// The vertices are transformed by the CPU when Update is called.
+type SkinInstance struct {
+	mesh       *graphic.Mesh
+	skeleton   []string         // URLs of the root nodes of the joints
+	names      []string         // Joint node names (sids or ids)
+	joints     []core.INode     // Joint nodes
+	invBind    []math32.Matrix4 // Inverse bind matrices multiplied by the bind shape matrix
+	matrices   []math32.Matrix4 // Current skinning matrix of each joint
+	influences []int            // Joint indices of each vertex (maxInfluences per vertex)
+	weights    []float32        // Normalized weights of each vertex (maxInfluences per vertex)
+	positions  math32.ArrayF32  // Vertex positions in the bind pose
+	normals    math32.ArrayF32  // Vertex normals in the bind pose (may be empty)
+}
+
+// Skins returns a map of the skin instances of the last scene created
+// by NewScene. The map is indexed by the loaderID of the skinned mesh nodes.
+// After the joints are animated, Update must be called for each skin instance
+// to deform its mesh:
+//
+//	scene, _ := dec.NewScene()
+//	targets, _ := dec.NewAnimationTargets(scene)
+//	skins := dec.Skins()
+//	...
+//	for _, at := range targets {
+//		at.Update(delta)
+//	}
+//	for _, skin := range skins {
+//		skin.Update()
+//	}
+func (d *Decoder) Skins() map[string]*SkinInstance {
+
+	return d.skins
+}
+
+// Mesh returns the skinned mesh
+func (s *SkinInstance) Mesh() *graphic.Mesh {
+
+	return s.mesh
+}
+
+// JointCount returns the number of joints of the skin
+func (s *SkinInstance) JointCount() int {
+
+	return len(s.joints)
+}
+
+// Joint returns the joint node at the specified index
+func (s *SkinInstance) Joint(idx int) core.INode {
+
+	return s.joints[idx]
+}
+
+// Update updates the world transforms of the scene of the mesh and
+// sets the vertices positions and normals from the current joints
+// transforms and the vertices weights.
+func (s *SkinInstance) Update() {
+
+	// Updates the world matrices from the root of the scene
+	var root core.INode = s.mesh
+	for root.GetNode().Parent() != nil {
+		root = root.GetNode().Parent()
+	}
+	root.GetNode().UpdateMatrixWorld()
+
+	// Calculates the skinning matrices in the mesh coordinates
+	mw := s.mesh.MatrixWorld()
+	var inv math32.Matrix4
+	inv.GetInverse(&mw, false)
+	for i, joint := range s.joints {
+		jw := joint.GetNode().MatrixWorld()
+		s.matrices[i].MultiplyMatrices(&inv, &jw)
+		s.matrices[i].Multiply(&s.invBind[i])
+	}
+
+	// Transforms the bind pose vertices by the weighted joints matrices
+	geom := s.mesh.GetGeometry()
+	vboPos := geom.VBO("VertexPosition")
+	positions := *vboPos.Buffer()
+	vboNormals := geom.VBO("VertexNormal")
+	var normals math32.ArrayF32
+	if vboNormals != nil && len(s.normals) > 0 {
+		normals = *vboNormals.Buffer()
+	}
+	var v math32.Vector3
+	for i := 0; i < len(s.positions)/3; i++ {
+		var m math32.Matrix4
+		if s.weights[i*maxInfluences] == 0 {
+			m.Identity()
+		}
+		for k := 0; k < maxInfluences; k++ {
+			w := s.weights[i*maxInfluences+k]
+			if w == 0 {
+				break
+			}
+			jm := &s.matrices[s.influences[i*maxInfluences+k]]
+			for e := range m {
+				m[e] += w * jm[e]
+			}
+		}
+		s.positions.GetVector3(i*3, &v)
+		positions.SetVector3(i*3, v.ApplyMatrix4(&m))
+		if normals != nil {
+			s.normals.GetVector3(i*3, &v)
+			normals.SetVector3(i*3, v.TransformDirection(&m))
+		}
+	}
+	vboPos.Update()
+	if normals != nil {
+		vboNormals.Update()
+	}
+	geom.InvalidateBounds()
+}
+
+// newSkinInstance creates and returns a skin instance with a new mesh
+// for the skin controller of the specified instance controller.
+// The joints are bound after the scene is created.
+func (d *Decoder) newSkinInstance(ic *InstanceController) (*SkinInstance, error) {
+
+	// Get the skin controller
+	id := strings.TrimPrefix(ic.Url, "#")
+	var skin *Skin
+	if d.dom.LibraryControllers != nil {
+		for _, c := range d.dom.LibraryControllers.Controller {
+			if c.Id == id {
+				skin = c.Skin
+				break
+			}
+		}
+	}
+	if skin == nil {
+		return nil, fmt.Errorf("Skin controller:%s not found", id)
+	}
+	s := new(SkinInstance)
+	s.skeleton = ic.Skeleton
+
+	// Creates the geometry which is not shared as its vertices are changed
+	gid := strings.TrimPrefix(skin.Source, "#")
+	var mesh *Mesh
+	for _, g := range d.dom.LibraryGeometries.Geometry {
+		if g.Id == gid {
+			mesh, _ = g.GeometricElement.(*Mesh)
+			break
+		}
+	}
+	if mesh == nil {
+		return nil, fmt.Errorf("Skin geometry:%s not found", gid)
+	}
+	geom, vpos, err := newPolylistGeometry(mesh, mesh.PrimitiveElements)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the joints names and inverse bind matrices
+	for _, inp := range skin.Joints.Input {
+		src := findSkinSource(skin, inp.Source)
+		if src == nil {
+			return nil, fmt.Errorf("Source:%s not found", inp.Source)
+		}
+		switch inp.Semantic {
+		case "JOINT":
+			na, ok := src.ArrayElement.(*NameArray)
+			if !ok {
+				return nil, fmt.Errorf("Source:%s is not NameArray", inp.Source)
+			}
+			s.names = na.Data
+		case "INV_BIND_MATRIX":
+			fa, ok := src.ArrayElement.(*FloatArray)
+			if !ok {
+				return nil, fmt.Errorf("Source:%s is not FloatArray", inp.Source)
+			}
+			var bindShape math32.Matrix4
+			bindShape.FromArray(skin.BindShapeMatrix)
+			bindShape.Transpose()
+			for i := 0; i+16 <= len(fa.Data); i += 16 {
+				var m math32.Matrix4
+				copy(m[:], fa.Data[i:i+16])
+				m.Transpose()
+				m.Multiply(&bindShape)
+				s.invBind = append(s.invBind, m)
+			}
+		}
+	}
+	if len(s.names) == 0 || len(s.names) != len(s.invBind) {
+		return nil, fmt.Errorf("Skin:%s with invalid joints", id)
+	}
+	s.matrices = make([]math32.Matrix4, len(s.names))
+
+	// Get the weights of each mesh position and sets the weights of the vertices
+	influences, weights, err := skinWeights(skin, len(s.names))
+	if err != nil {
+		return nil, err
+	}
+	s.influences = make([]int, len(vpos)*maxInfluences)
+	s.weights = make([]float32, len(vpos)*maxInfluences)
+	for i, pi := range vpos {
+		if pi*maxInfluences >= len(weights) {
+			return nil, fmt.Errorf("Skin:%s without weights for vertex:%d", id, pi)
+		}
+		copy(s.influences[i*maxInfluences:], influences[pi*maxInfluences:(pi+1)*maxInfluences])
+		copy(s.weights[i*maxInfluences:], weights[pi*maxInfluences:(pi+1)*maxInfluences])
+	}
+
+	// Saves the vertices of the bind pose
+	s.positions = append(s.positions, *geom.VBO("VertexPosition").Buffer()...)
+	if vbo := geom.VBO("VertexNormal"); vbo != nil {
+		s.normals = append(s.normals, *vbo.Buffer()...)
+	}
+
+	s.mesh = graphic.NewMesh(geom, nil)
+	err = d.addGroupMaterials(s.mesh, ic.BindMaterial)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// bindJoints finds the joint nodes of the skin in the specified scene
+// created from the specified visual scene and deforms the mesh by them.
+// The joints are searched by sid or id under the skeleton root nodes
+// or in all the visual scene if no skeleton was specified.
+func (s *SkinInstance) bindJoints(vs *VisualScene, scene core.INode) error {
+
+	roots := vs.Node
+	if len(s.skeleton) > 0 {
+		roots = nil
+		for _, url := range s.skeleton {
+			root := findJointNode(vs.Node, strings.TrimPrefix(url, "#"), true)
+			if root == nil {
+				return fmt.Errorf("Skeleton node:%s not found", url)
+			}
+			roots = append(roots, root)
+		}
+	}
+	s.joints = make([]core.INode, len(s.names))
+	for i, name := range s.names {
+		cnode := findJointNode(roots, name, false)
+		if cnode == nil {
+			return fmt.Errorf("Joint:%s not found", name)
+		}
+		joint := scene.GetNode().FindLoaderID(cnode.Id)
+		if cnode.Id == "" || joint == nil {
+			return fmt.Errorf("Joint:%s node not created", name)
+		}
+		s.joints[i] = joint
+	}
+	s.Update()
+	return nil
+}
+
+// skinWeights returns the joint indices and normalized weights of the
+// maxInfluences joints with the largest weights for each mesh position
+func skinWeights(skin *Skin, joints int) ([]int, []float32, error) {
+
+	vw := &skin.VertexWeights
+	inpJoint := getInputSemantic(vw.Input, "JOINT")
+	inpWeight := getInputSemantic(vw.Input, "WEIGHT")
+	if inpJoint == nil || inpWeight == nil {
+		return nil, nil, fmt.Errorf("Skin vertex weights without JOINT or WEIGHT input")
+	}
+	src := findSkinSource(skin, inpWeight.Source)
+	if src == nil {
+		return nil, nil, fmt.Errorf("Source:%s not found", inpWeight.Source)
+	}
+	weightArray, ok := src.ArrayElement.(*FloatArray)
+	if !ok {
+		return nil, nil, fmt.Errorf("Source:%s is not FloatArray", inpWeight.Source)
+	}
+
+	influences := make([]int, len(vw.Vcount)*maxInfluences)
+	weights := make([]float32, len(vw.Vcount)*maxInfluences)
+	inputCount := len(vw.Input)
+	pos := 0
+	for i, count := range vw.Vcount {
+		inf := influences[i*maxInfluences : (i+1)*maxInfluences]
+		ws := weights[i*maxInfluences : (i+1)*maxInfluences]
+		for c := 0; c < count; c, pos = c+1, pos+inputCount {
+			if pos+inputCount > len(vw.V) {
+				return nil, nil, fmt.Errorf("Skin vertex weights with invalid count")
+			}
+			joint := vw.V[pos+inpJoint.Offset]
+			wi := vw.V[pos+inpWeight.Offset]
+			if joint >= joints || wi < 0 || wi >= len(weightArray.Data) {
+				return nil, nil, fmt.Errorf("Skin vertex weights index out of range")
+			}
+			// Index -1 refers to the bind shape which is not animated
+			w := weightArray.Data[wi]
+			if joint < 0 || w <= 0 {
+				continue
+			}
+			// Inserts the influence keeping the weights in decreasing order
+			k := maxInfluences
+			for k > 0 && ws[k-1] < w {
+				k--
+			}
+			if k == maxInfluences {
+				continue
+			}
+			copy(inf[k+1:], inf[k:maxInfluences-1])
+			copy(ws[k+1:], ws[k:maxInfluences-1])
+			inf[k] = joint
+			ws[k] = w
+		}
+		// Normalizes the weights
+		var sum float32
+		for _, w := range ws {
+			sum += w
+		}
+		if sum > 0 {
+			for k := range ws {
+				ws[k] /= sum
+			}
+		}
+	}
+	return influences, weights, nil
+}
+
+// findJointNode returns the node with the specified sid or id, or only
+// with the specified id if byId is set, from the specified nodes or their
+// descendants. Returns nil if not found.
+func findJointNode(nodes []*Node, name string, byId bool) *Node {
+
+	for _, n := range nodes {
+		if n.Id == name || (!byId && n.Sid == name) {
+			return n
+		}
+		found := findJointNode(n.Node, name, byId)
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func findSkinSource(skin *Skin, uri string) *Source {
+
+	id := strings.TrimPrefix(uri, "#")
+	for _, src := range skin.Sources {
+		if src.Id == id {
+			return src
+		}
+	}
+	return nil
+}
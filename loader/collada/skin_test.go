@@ -0,0 +1,102 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collada
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestSkinnedBar(t *testing.T) {
+
+	dec, err := Decode("testdata/skinned_bar.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scene, err := dec.NewScene()
+	if err != nil {
+		t.Fatal(err)
+	}
+	skin := dec.Skins()["bar"]
+	if skin == nil {
+		t.Fatalf("skins %v, want the bar skin", dec.Skins())
+	}
+	if skin.JointCount() != 2 {
+		t.Fatalf("%d joints, want 2", skin.JointCount())
+	}
+	for i, id := range []string{"root", "tip"} {
+		if got := skin.Joint(i).GetNode().LoaderID(); got != id {
+			t.Errorf("joint %d = %s, want %s", i, got, id)
+		}
+	}
+
+	targets, err := dec.NewAnimationTargets(scene)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tip := targets["tip"]
+	if len(targets) != 1 || tip == nil {
+		t.Fatalf("animation targets %v, want the tip joint", targets)
+	}
+	if tip.Duration() != 2 {
+		t.Errorf("duration = %v, want 2", tip.Duration())
+	}
+
+	// After one second the tip joint moved half a unit along X, which moves
+	// the top vertices as much and the middle vertices half as much
+	if !tip.Update(1) {
+		t.Fatalf("animation ended after one second")
+	}
+	skin.Update()
+	positions := skin.Mesh().GetGeometry().VBO("VertexPosition").Buffer()
+	if positions.Size() != len(skin.positions) || positions.Size() != 6*3 {
+		t.Fatalf("%d positions, want %d", positions.Size()/3, 6)
+	}
+	for i := 0; i < positions.Size()/3; i++ {
+		var bind, got math32.Vector3
+		skin.positions.GetVector3(3*i, &bind)
+		positions.GetVector3(3*i, &got)
+		want := bind
+		want.X += 0.25 * bind.Y
+		if got.DistanceTo(&want) > 1e-5 {
+			t.Errorf("vertex %d at %v = %v, want %v", i, bind, got, want)
+		}
+	}
+}
+
+func TestSkinWeights(t *testing.T) {
+
+	// The first vertex has five influences and the second has one
+	skin := &Skin{
+		Sources: []*Source{{Id: "w", ArrayElement: &FloatArray{Data: []float32{0.2, 0.8, 0.4, 0.6, 0.1, 0.5}}}},
+		VertexWeights: VertexWeights{
+			Input: []InputShared{
+				{Offset: 0, Semantic: "JOINT"},
+				{Offset: 1, Semantic: "WEIGHT", Source: "#w"},
+			},
+			Vcount: []int{5, 1},
+			V:      []int{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 2, 5},
+		},
+	}
+	influences, weights, err := skinWeights(skin, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The four largest weights are kept in decreasing order and
+	// normalized, and the fifth weight of 0.1 is dropped
+	wantInfluences := []int{1, 3, 2, 0, 2, 0, 0, 0}
+	wantWeights := []float32{0.4, 0.3, 0.2, 0.1, 1, 0, 0, 0}
+	for i := range wantInfluences {
+		if influences[i] != wantInfluences[i] || math32.Abs(weights[i]-wantWeights[i]) > 1e-6 {
+			t.Errorf("influence %d = %d %v, want %d %v", i, influences[i], weights[i], wantInfluences[i], wantWeights[i])
+		}
+	}
+
+	if _, _, err := skinWeights(skin, 4); err == nil {
+		t.Errorf("joint index out of range accepted")
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/math32"
+)
+
+// Animator plays a glTF animation, setting the translation, rotation and
+// scale of the animated nodes from the keyframes interpolated at the current
+// animation time, which is advanced by Update.
+type Animator struct {
+	name     string        // Animation name
+	channels []animChannel // Animated node properties
+	time     float32       // Current time in seconds
+	duration float32       // Time of the last keyframe of all channels
+	loop     bool          // Animation loop flag
+}
+
+// animChannel contains the keyframes of one animated node property
+type animChannel struct {
+	node   *core.Node // Animated node
+	path   string     // Animated property
+	interp string     // Interpolation algorithm
+	size   int        // Number of components of the property
+	input  []float32  // Keyframe times
+	output []float32  // Keyframe values, with their tangents for cubic splines
+}
+
+// NewAnimator creates and returns an animator for the animation with the
+// specified index, which targets the last created instance of each animated
+// node, so the scene must be created before its animators. Morph target
+// weights are not animated. The nodes are set to the start of the animation.
+func (d *Decoder) NewAnimator(idx int) (*Animator, error) {
+
+	if idx < 0 || idx >= len(d.Animations) {
+		return nil, fmt.Errorf("Invalid animation index:%d", idx)
+	}
+	anim := &d.Animations[idx]
+	a := new(Animator)
+	a.name = anim.Name
+	for _, ch := range anim.Channels {
+		if ch.Target.Node == nil {
+			continue
+		}
+		var size int
+		switch ch.Target.Path {
+		case "translation", "scale":
+			size = 3
+		case "rotation":
+			size = 4
+		case "weights":
+			log.Warn("Animation:%d of morph target weights not supported", idx)
+			continue
+		default:
+			return nil, fmt.Errorf("Animation:%d with invalid target path:%s", idx, ch.Target.Path)
+		}
+		inode := d.nodes[*ch.Target.Node]
+		if inode == nil {
+			return nil, fmt.Errorf("Animation:%d target node:%d not created", idx, *ch.Target.Node)
+		}
+		if ch.Sampler < 0 || ch.Sampler >= len(anim.Samplers) {
+			return nil, fmt.Errorf("Animation:%d with invalid sampler index:%d", idx, ch.Sampler)
+		}
+		sampler := &anim.Samplers[ch.Sampler]
+
+		// Loads the keyframes
+		c := animChannel{node: inode.GetNode(), path: ch.Target.Path, size: size}
+		c.interp = sampler.Interpolation
+		if c.interp == "" {
+			c.interp = "LINEAR"
+		}
+		var err error
+		c.input, err = d.loadFloats(sampler.Input, "SCALAR")
+		if err != nil {
+			return nil, err
+		}
+		c.output, err = d.loadFloats(sampler.Output, "VEC3", "VEC4")
+		if err != nil {
+			return nil, err
+		}
+		values := len(c.input) * size
+		if c.interp == "CUBICSPLINE" {
+			values *= 3
+		}
+		if len(c.input) == 0 || len(c.output) != values {
+			return nil, fmt.Errorf("Animation:%d sampler:%d with invalid keyframes", idx, ch.Sampler)
+		}
+		if last := c.input[len(c.input)-1]; last > a.duration {
+			a.duration = last
+		}
+		a.channels = append(a.channels, c)
+	}
+	a.Reset()
+	return a, nil
+}
+
+// Name returns the name of the animation
+func (a *Animator) Name() string {
+
+	return a.name
+}
+
+// Duration returns the time in seconds of the last keyframe of the animation
+func (a *Animator) Duration() float32 {
+
+	return a.duration
+}
+
+// SetLoop sets if the animation restarts from the beginning when it ends
+func (a *Animator) SetLoop(loop bool) {
+
+	a.loop = loop
+}
+
+// Loop returns the state of the animation loop flag
+func (a *Animator) Loop() bool {
+
+	return a.loop
+}
+
+// Time returns the current time of the animation in seconds
+func (a *Animator) Time() float32 {
+
+	return a.time
+}
+
+// SetTime sets the current time of the animation in seconds and
+// updates the nodes with the values interpolated at this time
+func (a *Animator) SetTime(time float32) {
+
+	a.time = time
+	for i := range a.channels {
+		a.channels[i].update(time)
+	}
+}
+
+// Reset sets the nodes to the start of the animation
+func (a *Animator) Reset() {
+
+	a.SetTime(0)
+}
+
+// Update advances the animation by the specified time in seconds and updates
+// the nodes. Returns false if the animation has ended or true otherwise.
+func (a *Animator) Update(delta float32) bool {
+
+	time := a.time + delta
+	if time > a.duration {
+		if !a.loop || a.duration <= 0 {
+			a.SetTime(a.duration)
+			return false
+		}
+		time = math32.Mod(time, a.duration)
+	}
+	a.SetTime(time)
+	return true
+}
+
+// update sets the node property of this channel
+// with the value interpolated at the specified time
+func (c *animChannel) update(time float32) {
+
+	// Index of the last keyframe before the time, which
+	// is clamped to the interval of the keyframes
+	last := len(c.input) - 1
+	k := sort.Search(len(c.input), func(i int) bool { return c.input[i] > time }) - 1
+	var value [4]float32
+	switch {
+	case k < 0:
+		c.value(0, value[:])
+	case k >= last:
+		c.value(last, value[:])
+	case c.interp == "STEP":
+		c.value(k, value[:])
+	case c.interp == "CUBICSPLINE":
+		c.cubic(k, time, value[:])
+	default:
+		c.linear(k, time, value[:])
+	}
+
+	switch c.path {
+	case "translation":
+		c.node.SetPosition(value[0], value[1], value[2])
+	case "scale":
+		c.node.SetScale(value[0], value[1], value[2])
+	case "rotation":
+		q := math32.NewQuaternion(value[0], value[1], value[2], value[3])
+		c.node.SetQuaternionQuat(q.Normalize())
+	}
+}
+
+// value sets the specified value with the value of the specified keyframe
+func (c *animChannel) value(k int, value []float32) {
+
+	pos := k * c.size
+	if c.interp == "CUBICSPLINE" {
+		// In tangent, value and out tangent of each keyframe
+		pos = (3*k + 1) * c.size
+	}
+	copy(value, c.output[pos:pos+c.size])
+}
+
+// linear sets the specified value with the linear interpolation between the
+// specified keyframe and the next one, or the spherical linear interpolation
+// for rotations
+func (c *animChannel) linear(k int, time float32, value []float32) {
+
+	t := (time - c.input[k]) / (c.input[k+1] - c.input[k])
+	v0 := c.output[k*c.size : (k+1)*c.size]
+	v1 := c.output[(k+1)*c.size : (k+2)*c.size]
+	if c.path == "rotation" {
+		q0 := math32.NewQuaternion(v0[0], v0[1], v0[2], v0[3])
+		q1 := math32.NewQuaternion(v1[0], v1[1], v1[2], v1[3])
+		q0.Slerp(q1, t)
+		value[0], value[1], value[2], value[3] = q0.X(), q0.Y(), q0.Z(), q0.W()
+		return
+	}
+	for i := 0; i < c.size; i++ {
+		value[i] = v0[i] + (v1[i]-v0[i])*t
+	}
+}
+
+// cubic sets the specified value with the cubic Hermite spline
+// interpolation between the specified keyframe and the next one
+func (c *animChannel) cubic(k int, time float32, value []float32) {
+
+	dt := c.input[k+1] - c.input[k]
+	t := (time - c.input[k]) / dt
+	t2 := t * t
+	t3 := t2 * t
+	s := c.size
+	p0 := c.output[(3*k+1)*s:]
+	m0 := c.output[(3*k+2)*s:]
+	p1 := c.output[(3*(k+1)+1)*s:]
+	m1 := c.output[(3*(k+1))*s:]
+	for i := 0; i < s; i++ {
+		value[i] = (2*t3-3*t2+1)*p0[i] + (t3-2*t2+t)*dt*m0[i] + (-2*t3+3*t2)*p1[i] + (t3-t2)*dt*m1[i]
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/texture"
+)
+
+// Decoder contains the decoded glTF document with its buffers loaded
+// and builds the engine objects from its scenes, nodes, meshes,
+// materials, textures, cameras and animations.
+type Decoder struct {
+	GLTF                                     // Decoded glTF document
+	dir        string                        // Directory of the external buffers and images
+	nodes      map[int]core.INode            // Last created instance of each node
+	building   map[int]bool                  // Nodes being created, to detect cycles
+	geometries map[[2]int]*geometry.Geometry // Created geometries by mesh and primitive index
+	materials  map[int]material.IMaterial    // Created materials shared by the primitives
+	textures   map[[2]int]*texture.Texture2D // Created textures by texture index and texture coordinates set
+}
+
+// Binary glTF (.glb) header and chunk types
+const (
+	glbMagic     = 0x46546C67 // "glTF"
+	glbVersion   = 2
+	glbChunkJSON = 0x4E4F534A // "JSON"
+	glbChunkBIN  = 0x004E4942 // "BIN"
+)
+
+// componentSizes maps the accessor component types to their sizes in bytes
+var componentSizes = map[int]int{
+	BYTE:           1,
+	UNSIGNED_BYTE:  1,
+	SHORT:          2,
+	UNSIGNED_SHORT: 2,
+	UNSIGNED_INT:   4,
+	FLOAT:          4,
+}
+
+// Decode decodes the specified .gltf or .glb file returning a decoder object
+// and an error. External buffers and images are loaded relative to the
+// directory of the file.
+// For example, to load the default scene and play its first animation:
+//
+//	dec, err := gltf.Decode("models/robot.glb")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	model, err := dec.NewScene()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	scene.Add(model)
+//	anim, err := dec.NewAnimator(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	anim.SetLoop(true)
+//	...
+//	// In the render loop, with the elapsed time in seconds
+//	anim.Update(delta)
+func Decode(path string) (*Decoder, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeReader(bytes.NewReader(data), filepath.Dir(path))
+}
+
+// DecodeReader decodes the glTF JSON document or binary .glb file from the
+// specified reader returning a decoder object and an error. External buffers
+// and images are loaded relative to the specified directory.
+func DecodeReader(reader io.Reader, dir string) (*Decoder, error) {
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(Decoder)
+	d.dir = dir
+	d.nodes = make(map[int]core.INode)
+	d.building = make(map[int]bool)
+	d.geometries = make(map[[2]int]*geometry.Geometry)
+	d.materials = make(map[int]material.IMaterial)
+	d.textures = make(map[[2]int]*texture.Texture2D)
+
+	// Binary files contain the JSON document and an optional binary buffer
+	var bin []byte
+	if len(data) >= 4 && binary.LittleEndian.Uint32(data) == glbMagic {
+		data, bin, err = decodeGLB(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = json.Unmarshal(data, &d.GLTF)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(d.Asset.Version, "2.") {
+		return nil, fmt.Errorf("glTF version:%s not supported", d.Asset.Version)
+	}
+	if len(d.ExtensionsRequired) > 0 {
+		return nil, fmt.Errorf("glTF required extension:%s not supported", d.ExtensionsRequired[0])
+	}
+
+	// Loads the buffers
+	for i := range d.Buffers {
+		buf := &d.Buffers[i]
+		if buf.URI == "" {
+			if i != 0 || bin == nil {
+				return nil, fmt.Errorf("Buffer:%d has no data", i)
+			}
+			buf.data = bin
+		} else {
+			buf.data, err = d.loadURI(buf.URI)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(buf.data) < buf.ByteLength {
+			return nil, fmt.Errorf("Buffer:%d with %d bytes, expected %d", i, len(buf.data), buf.ByteLength)
+		}
+	}
+	return d, nil
+}
+
+// decodeGLB returns the JSON and binary chunks of the specified .glb file data
+func decodeGLB(data []byte) ([]byte, []byte, error) {
+
+	if len(data) < 12 {
+		return nil, nil, fmt.Errorf("GLB header too short")
+	}
+	version := binary.LittleEndian.Uint32(data[4:])
+	if version != glbVersion {
+		return nil, nil, fmt.Errorf("GLB version:%d not supported", version)
+	}
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if length > len(data) {
+		return nil, nil, fmt.Errorf("GLB length:%d greater than the file size", length)
+	}
+
+	// Reads the chunks, the first one must be the JSON document
+	var doc, bin []byte
+	for pos := 12; pos+8 <= length; {
+		clen := int(binary.LittleEndian.Uint32(data[pos:]))
+		ctype := binary.LittleEndian.Uint32(data[pos+4:])
+		pos += 8
+		if clen > length-pos {
+			return nil, nil, fmt.Errorf("GLB chunk length:%d out of bounds", clen)
+		}
+		chunk := data[pos : pos+clen]
+		pos += clen
+		switch {
+		case doc == nil && ctype == glbChunkJSON:
+			doc = chunk
+		case doc == nil:
+			return nil, nil, fmt.Errorf("GLB first chunk is not JSON")
+		case bin == nil && ctype == glbChunkBIN:
+			bin = chunk
+		}
+	}
+	if doc == nil {
+		return nil, nil, fmt.Errorf("GLB without JSON chunk")
+	}
+	return doc, bin, nil
+}
+
+// loadURI returns the data of the specified data URI
+// or of the file with the specified relative path
+func (d *Decoder) loadURI(uri string) ([]byte, error) {
+
+	// Data URI: data:[<mime type>][;base64],<data>
+	if strings.HasPrefix(uri, "data:") {
+		comma := strings.IndexByte(uri, ',')
+		if comma < 0 {
+			return nil, fmt.Errorf("Invalid data URI")
+		}
+		if !strings.HasSuffix(uri[:comma], ";base64") {
+			return nil, fmt.Errorf("Data URI without base64 encoding not supported")
+		}
+		return base64.StdEncoding.DecodeString(uri[comma+1:])
+	}
+
+	// Relative path with percent encoded characters
+	path, err := url.PathUnescape(uri)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.Join(d.dir, filepath.FromSlash(path)))
+}
+
+// viewData returns the data of the specified buffer view starting at the
+// specified offset and the distance in bytes between its elements, checking
+// that the specified number of elements of the specified size are inside the view
+func (d *Decoder) viewData(bvi, offset, count, size int) ([]byte, int, error) {
+
+	if bvi < 0 || bvi >= len(d.BufferViews) {
+		return nil, 0, fmt.Errorf("Invalid buffer view index:%d", bvi)
+	}
+	bv := &d.BufferViews[bvi]
+	if bv.Buffer < 0 || bv.Buffer >= len(d.Buffers) {
+		return nil, 0, fmt.Errorf("Invalid buffer index:%d", bv.Buffer)
+	}
+	buf := d.Buffers[bv.Buffer].data
+	if bv.ByteOffset < 0 || bv.ByteLength < 0 || bv.ByteOffset+bv.ByteLength > len(buf) {
+		return nil, 0, fmt.Errorf("Buffer view:%d out of the buffer bounds", bvi)
+	}
+	stride := size
+	if bv.ByteStride != 0 {
+		stride = bv.ByteStride
+	}
+	if offset < 0 || offset > bv.ByteLength {
+		return nil, 0, fmt.Errorf("Buffer view:%d offset:%d out of bounds", bvi, offset)
+	}
+	if count > 0 && offset+(count-1)*stride+size > bv.ByteLength {
+		return nil, 0, fmt.Errorf("Buffer view:%d too short for its accessor", bvi)
+	}
+	return buf[bv.ByteOffset+offset : bv.ByteOffset+bv.ByteLength], stride, nil
+}
+
+// decodeAccessor decodes the accessor with the specified index, which must
+// have one of the specified element types, calling the specified alloc function
+// with the total number of components and then the set function with the
+// position and data of each component, including the sparse replacements.
+func (d *Decoder) decodeAccessor(ai int, types []string, alloc func(size int), set func(pos int, data []byte, acc *Accessor)) error {
+
+	if ai < 0 || ai >= len(d.Accessors) {
+		return fmt.Errorf("Invalid accessor index:%d", ai)
+	}
+	acc := &d.Accessors[ai]
+	valid := false
+	for _, t := range types {
+		valid = valid || acc.Type == t
+	}
+	if !valid {
+		return fmt.Errorf("Accessor:%d with invalid type:%s", ai, acc.Type)
+	}
+	ncomps := TypeSizes[acc.Type]
+	csize := componentSizes[acc.ComponentType]
+	if csize == 0 {
+		return fmt.Errorf("Accessor:%d with invalid component type:%d", ai, acc.ComponentType)
+	}
+	if acc.Count < 0 {
+		return fmt.Errorf("Accessor:%d with invalid count:%d", ai, acc.Count)
+	}
+	alloc(acc.Count * ncomps)
+
+	// Elements in the buffer view. Without a buffer
+	// view all the elements are initialized to zero.
+	if acc.BufferView != nil {
+		data, stride, err := d.viewData(*acc.BufferView, acc.ByteOffset, acc.Count, ncomps*csize)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < acc.Count; i++ {
+			for c := 0; c < ncomps; c++ {
+				set(i*ncomps+c, data[i*stride+c*csize:], acc)
+			}
+		}
+	} else {
+		zero := make([]byte, csize)
+		for pos := 0; pos < acc.Count*ncomps; pos++ {
+			set(pos, zero, acc)
+		}
+	}
+
+	// Replaced elements of sparse accessors
+	if acc.Sparse != nil {
+		sp := acc.Sparse
+		isize := componentSizes[sp.Indices.ComponentType]
+		if isize == 0 || sp.Indices.ComponentType == BYTE || sp.Indices.ComponentType == SHORT || sp.Indices.ComponentType == FLOAT {
+			return fmt.Errorf("Accessor:%d with invalid sparse indices type:%d", ai, sp.Indices.ComponentType)
+		}
+		indices, _, err := d.viewData(sp.Indices.BufferView, sp.Indices.ByteOffset, sp.Count, isize)
+		if err != nil {
+			return err
+		}
+		values, _, err := d.viewData(sp.Values.BufferView, sp.Values.ByteOffset, sp.Count, ncomps*csize)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < sp.Count; i++ {
+			idx := int(decodeUint(indices[i*isize:], sp.Indices.ComponentType))
+			if idx >= acc.Count {
+				return fmt.Errorf("Accessor:%d with sparse index:%d out of range", ai, idx)
+			}
+			for c := 0; c < ncomps; c++ {
+				set(idx*ncomps+c, values[(i*ncomps+c)*csize:], acc)
+			}
+		}
+	}
+	return nil
+}
+
+// loadFloats returns the components of the elements of the accessor
+// with the specified index, which must have one of the specified element
+// types, converted to floats.
+func (d *Decoder) loadFloats(ai int, types ...string) ([]float32, error) {
+
+	var values []float32
+	err := d.decodeAccessor(ai, types, func(size int) {
+		values = make([]float32, size)
+	}, func(pos int, data []byte, acc *Accessor) {
+		values[pos] = decodeFloat(data, acc.ComponentType, acc.Normalized)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// loadIndices returns the vertex indices of the scalar accessor with the specified index
+func (d *Decoder) loadIndices(ai int) ([]uint32, error) {
+
+	var indices []uint32
+	err := d.decodeAccessor(ai, []string{"SCALAR"}, func(size int) {
+		indices = make([]uint32, size)
+	}, func(pos int, data []byte, acc *Accessor) {
+		indices[pos] = decodeUint(data, acc.ComponentType)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return indices, nil
+}
+
+// decodeFloat returns the component with the specified type at the start of the
+// specified data as a float, mapping normalized integers to [0, 1] or [-1, 1]
+func decodeFloat(data []byte, ctype int, normalized bool) float32 {
+
+	switch ctype {
+	case BYTE:
+		v := float32(int8(data[0]))
+		if normalized {
+			return float32(math.Max(float64(v)/127, -1))
+		}
+		return v
+	case UNSIGNED_BYTE:
+		v := float32(data[0])
+		if normalized {
+			return v / 255
+		}
+		return v
+	case SHORT:
+		v := float32(int16(binary.LittleEndian.Uint16(data)))
+		if normalized {
+			return float32(math.Max(float64(v)/32767, -1))
+		}
+		return v
+	case UNSIGNED_SHORT:
+		v := float32(binary.LittleEndian.Uint16(data))
+		if normalized {
+			return v / 65535
+		}
+		return v
+	case UNSIGNED_INT:
+		return float32(binary.LittleEndian.Uint32(data))
+	default:
+		return math.Float32frombits(binary.LittleEndian.Uint32(data))
+	}
+}
+
+// decodeUint returns the unsigned integer component with the
+// specified type at the start of the specified data
+func decodeUint(data []byte, ctype int) uint32 {
+
+	switch ctype {
+	case UNSIGNED_BYTE, BYTE:
+		return uint32(data[0])
+	case UNSIGNED_SHORT, SHORT:
+		return uint32(binary.LittleEndian.Uint16(data))
+	default:
+		return binary.LittleEndian.Uint32(data)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/g3n/engine/graphic"
+)
+
+// triangleData returns the buffer of the triangle fixture with the
+// positions of its three vertices followed by their uint16 indices
+func triangleData() []byte {
+
+	var buf bytes.Buffer
+	for _, v := range []float32{0, 0, 0, 1, 0, 0, 0, 1, 0} {
+		binary.Write(&buf, binary.LittleEndian, math.Float32bits(v))
+	}
+	binary.Write(&buf, binary.LittleEndian, []uint16{0, 1, 2, 0})
+	return buf.Bytes()
+}
+
+// triangleDoc returns the JSON document of the triangle fixture
+// with a buffer with the specified properties
+func triangleDoc(buffer string) string {
+
+	return `{
+	"asset": {"version": "2.0"},
+	"scene": 0,
+	"scenes": [{"nodes": [0]}],
+	"nodes": [{"mesh": 0}],
+	"meshes": [{"primitives": [{"attributes": {"POSITION": 0}, "indices": 1}]}],
+	"buffers": [{` + buffer + `"byteLength": 44}],
+	"bufferViews": [
+		{"buffer": 0, "byteOffset": 0, "byteLength": 36},
+		{"buffer": 0, "byteOffset": 36, "byteLength": 6}
+	],
+	"accessors": [
+		{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3"},
+		{"bufferView": 1, "componentType": 5123, "count": 3, "type": "SCALAR"}
+	]
+}`
+}
+
+// glb returns a binary glTF file with the specified JSON document
+// and binary buffer, without a binary chunk if the buffer is nil
+func glb(doc string, bin []byte) []byte {
+
+	for len(doc)%4 != 0 {
+		doc += " "
+	}
+	for len(bin)%4 != 0 {
+		bin = append(bin, 0)
+	}
+	length := 12 + 8 + len(doc)
+	if bin != nil {
+		length += 8 + len(bin)
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, []uint32{glbMagic, glbVersion, uint32(length)})
+	binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(doc)), glbChunkJSON})
+	buf.WriteString(doc)
+	if bin != nil {
+		binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(bin)), glbChunkBIN})
+		buf.Write(bin)
+	}
+	return buf.Bytes()
+}
+
+// checkTriangle checks that the default scene of the specified decoder has the triangle mesh
+func checkTriangle(t *testing.T, name string, d *Decoder) {
+
+	scene, err := d.NewScene()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	children := scene.GetNode().Children()
+	if len(children) != 1 {
+		t.Fatalf("%s: scene with %d nodes, want 1", name, len(children))
+	}
+	mesh, ok := children[0].(*graphic.Mesh)
+	if !ok {
+		t.Fatalf("%s: node %T, want *graphic.Mesh", name, children[0])
+	}
+	geom := mesh.GetGeometry()
+	positions := *geom.VBO("VertexPosition").Buffer()
+	if got, want := fmt.Sprint(positions), "[0 0 0 1 0 0 0 1 0]"; got != want {
+		t.Errorf("%s: positions %s, want %s", name, got, want)
+	}
+	if got, want := fmt.Sprint(geom.Indices()), "[0 1 2]"; got != want {
+		t.Errorf("%s: indices %s, want %s", name, got, want)
+	}
+}
+
+func TestDecodeDataURI(t *testing.T) {
+
+	uri := `"uri": "data:application/octet-stream;base64,` + base64.StdEncoding.EncodeToString(triangleData()) + `", `
+	d, err := DecodeReader(strings.NewReader(triangleDoc(uri)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTriangle(t, "data URI", d)
+
+	_, err = DecodeReader(strings.NewReader(triangleDoc(`"uri": "data:application/octet-stream,abc", `)), "")
+	if err == nil {
+		t.Errorf("data URI without base64 encoding accepted")
+	}
+}
+
+func TestDecodeGLB(t *testing.T) {
+
+	d, err := DecodeReader(bytes.NewReader(glb(triangleDoc(""), triangleData())), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTriangle(t, "glb", d)
+
+	// Malformed files
+	data := glb(triangleDoc(""), triangleData())
+	var binFirst bytes.Buffer
+	binary.Write(&binFirst, binary.LittleEndian, []uint32{glbMagic, glbVersion, 24, 4, glbChunkBIN, 0})
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"short header", data[:8]},
+		{"truncated", data[:len(data)-8]},
+		{"without BIN chunk", glb(triangleDoc(""), nil)},
+		{"BIN chunk first", binFirst.Bytes()},
+	}
+	for _, c := range cases {
+		if _, err := DecodeReader(bytes.NewReader(c.data), ""); err == nil {
+			t.Errorf("%s: no error", c.name)
+		}
+	}
+}
+
+// newAccessorDecoder returns a decoder with one buffer with the
+// specified data, one buffer view of all the buffer data and the
+// specified accessors
+func newAccessorDecoder(data []byte, accessors ...Accessor) *Decoder {
+
+	d := new(Decoder)
+	d.Buffers = []Buffer{{ByteLength: len(data), data: data}}
+	d.BufferViews = []BufferView{{ByteLength: len(data)}}
+	d.Accessors = accessors
+	return d
+}
+
+func TestDecodeSparseAccessor(t *testing.T) {
+
+	// Elements 1 and 3 of a zero initialized accessor are replaced
+	var data bytes.Buffer
+	data.Write([]byte{1, 3, 0, 0})
+	binary.Write(&data, binary.LittleEndian, []float32{1, 2, 3, 4, 5, 6})
+	d := newAccessorDecoder(data.Bytes(), Accessor{
+		ComponentType: FLOAT,
+		Count:         4,
+		Type:          "VEC3",
+		Sparse: &Sparse{
+			Count:   2,
+			Indices: SparseIndices{ComponentType: UNSIGNED_BYTE},
+			Values:  SparseValues{ByteOffset: 4},
+		},
+	})
+	values, err := d.loadFloats(0, "VEC3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(values), "[0 0 0 1 2 3 0 0 0 4 5 6]"; got != want {
+		t.Errorf("values %s, want %s", got, want)
+	}
+
+	// The sparse indices must be in range
+	d.Accessors[0].Count = 2
+	if _, err := d.loadFloats(0, "VEC3"); err == nil {
+		t.Errorf("sparse index out of range accepted")
+	}
+}
+
+func TestDecodeNormalized(t *testing.T) {
+
+	d := newAccessorDecoder([]byte{0, 51, 102, 255},
+		Accessor{BufferView: new(int), ComponentType: UNSIGNED_BYTE, Normalized: true, Count: 1, Type: "VEC4"},
+		Accessor{BufferView: new(int), ComponentType: UNSIGNED_BYTE, Count: 1, Type: "VEC4"},
+		Accessor{BufferView: new(int), ComponentType: BYTE, Normalized: true, Count: 1, Type: "VEC4"},
+	)
+	cases := []struct {
+		name string
+		want []float32
+	}{
+		{"normalized unsigned byte", []float32{0, 0.2, 0.4, 1}},
+		{"unsigned byte", []float32{0, 51, 102, 255}},
+		{"normalized byte", []float32{0, 51.0 / 127, 102.0 / 127, -1.0 / 127}},
+	}
+	for ai, c := range cases {
+		values, err := d.loadFloats(ai, "VEC4")
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		for i, v := range values {
+			if math.Abs(float64(v-c.want[i])) > 1e-6 {
+				t.Errorf("%s: value %d = %v, want %v", c.name, i, v, c.want[i])
+			}
+		}
+	}
+}
+
+func TestDecodeInvalidOffset(t *testing.T) {
+
+	cases := []struct {
+		name string
+		acc  Accessor
+	}{
+		{"negative offset", Accessor{BufferView: new(int), ByteOffset: -4, ComponentType: FLOAT, Count: 1, Type: "SCALAR"}},
+		{"offset after the view", Accessor{BufferView: new(int), ByteOffset: 12, ComponentType: FLOAT, Count: 1, Type: "SCALAR"}},
+		{"empty with negative offset", Accessor{BufferView: new(int), ByteOffset: -4, ComponentType: FLOAT, Type: "SCALAR"}},
+		{"empty with offset after the view", Accessor{BufferView: new(int), ByteOffset: 100, ComponentType: FLOAT, Type: "SCALAR"}},
+		{"sparse indices with negative offset", Accessor{ComponentType: FLOAT, Count: 1, Type: "SCALAR",
+			Sparse: &Sparse{Indices: SparseIndices{ByteOffset: -1, ComponentType: UNSIGNED_BYTE}}}},
+		{"sparse values with offset after the view", Accessor{ComponentType: FLOAT, Count: 1, Type: "SCALAR",
+			Sparse: &Sparse{Indices: SparseIndices{ComponentType: UNSIGNED_BYTE}, Values: SparseValues{ByteOffset: 100}}}},
+	}
+	for _, c := range cases {
+		d := newAccessorDecoder(make([]byte, 8), c.acc)
+		if _, err := d.loadFloats(0, "SCALAR"); err == nil {
+			t.Errorf("%s: no error", c.name)
+		}
+	}
+
+	// Images use the whole buffer view
+	d := newAccessorDecoder(make([]byte, 8))
+	if _, _, err := d.viewData(0, 0, 0, 0); err != nil {
+		t.Errorf("image view: %v", err)
+	}
+	if _, _, err := d.viewData(0, 9, 0, 0); err == nil {
+		t.Errorf("image view with offset after the view: no error")
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gltf implements a loader of glTF 2.0 files (.gltf and .glb)
+// which builds the node hierarchy with the meshes, physical materials,
+// textures and cameras of the decoded scenes and the animations of the
+// translation, rotation and scale of their nodes.
+// Skins and morph targets are decoded but not applied to the meshes.
+package gltf
+
+// GLTF is the root object of a glTF document.
+// The fields follow the glTF 2.0 JSON schema and the references
+// between objects are indices of the corresponding arrays.
+type GLTF struct {
+	ExtensionsUsed     []string     `json:"extensionsUsed,omitempty"`     // Names of the extensions used
+	ExtensionsRequired []string     `json:"extensionsRequired,omitempty"` // Names of the extensions required to load the document
+	Accessors          []Accessor   `json:"accessors,omitempty"`          // Typed views of the buffer views
+	Animations         []Animation  `json:"animations,omitempty"`         // Keyframe animations
+	Asset              Asset        `json:"asset"`                        // Metadata about the document
+	Buffers            []Buffer     `json:"buffers,omitempty"`            // Binary data buffers
+	BufferViews        []BufferView `json:"bufferViews,omitempty"`        // Views of subsets of the buffers
+	Cameras            []Camera     `json:"cameras,omitempty"`            // Cameras referenced by nodes
+	Images             []Image      `json:"images,omitempty"`             // Images used by the textures
+	Materials          []Material   `json:"materials,omitempty"`          // Materials of the mesh primitives
+	Meshes             []Mesh       `json:"meshes,omitempty"`             // Meshes referenced by nodes
+	Nodes              []Node       `json:"nodes,omitempty"`              // Nodes of the hierarchy
+	Samplers           []Sampler    `json:"samplers,omitempty"`           // Texture samplers
+	Scene              *int         `json:"scene,omitempty"`              // Index of the default scene
+	Scenes             []Scene      `json:"scenes,omitempty"`             // Scenes with their root nodes
+	Skins              []Skin       `json:"skins,omitempty"`              // Skins referenced by nodes
+	Textures           []Texture    `json:"textures,omitempty"`           // Textures with an image and a sampler
+}
+
+// Accessor is a typed view of a buffer view with elements of one of the
+// types SCALAR, VEC2, VEC3, VEC4, MAT2, MAT3 or MAT4 and components of
+// one of the BYTE, UNSIGNED_BYTE, SHORT, UNSIGNED_SHORT, UNSIGNED_INT
+// or FLOAT component types.
+type Accessor struct {
+	BufferView    *int      `json:"bufferView,omitempty"` // Index of the buffer view or nil if all the elements are zero
+	ByteOffset    int       `json:"byteOffset"`           // Offset relative to the start of the buffer view in bytes
+	ComponentType int       `json:"componentType"`        // Data type of the components
+	Normalized    bool      `json:"normalized"`           // Integer components are normalized to [0, 1] or [-1, 1]
+	Count         int       `json:"count"`                // Number of elements
+	Type          string    `json:"type"`                 // Element type
+	Max           []float32 `json:"max,omitempty"`        // Maximum value of each component
+	Min           []float32 `json:"min,omitempty"`        // Minimum value of each component
+	Sparse        *Sparse   `json:"sparse,omitempty"`     // Elements which deviate from their initialization value
+	Name          string    `json:"name,omitempty"`       // Name of the accessor
+}
+
+// Sparse contains the elements of an accessor which are replaced
+type Sparse struct {
+	Count   int           `json:"count"`   // Number of replaced elements
+	Indices SparseIndices `json:"indices"` // Indices of the replaced elements
+	Values  SparseValues  `json:"values"`  // Values of the replaced elements
+}
+
+// SparseIndices is the location of the indices of the replaced elements
+// of a sparse accessor, with one of the UNSIGNED_BYTE, UNSIGNED_SHORT
+// or UNSIGNED_INT component types
+type SparseIndices struct {
+	BufferView    int `json:"bufferView"`    // Index of the buffer view
+	ByteOffset    int `json:"byteOffset"`    // Offset relative to the start of the buffer view in bytes
+	ComponentType int `json:"componentType"` // Data type of the indices
+}
+
+// SparseValues is the location of the values of the replaced
+// elements of a sparse accessor, with the accessor component type
+type SparseValues struct {
+	BufferView int `json:"bufferView"` // Index of the buffer view
+	ByteOffset int `json:"byteOffset"` // Offset relative to the start of the buffer view in bytes
+}
+
+// Animation is a keyframe animation of the nodes
+type Animation struct {
+	Channels []Channel          `json:"channels"`       // Channels which connect the samplers to the animated properties
+	Samplers []AnimationSampler `json:"samplers"`       // Samplers with the keyframes
+	Name     string             `json:"name,omitempty"` // Name of the animation
+}
+
+// Channel connects an animation sampler to the animated property of a node
+type Channel struct {
+	Sampler int           `json:"sampler"` // Index of the sampler in the animation
+	Target  ChannelTarget `json:"target"`  // Animated node and property
+}
+
+// ChannelTarget is the node and property animated by a channel
+type ChannelTarget struct {
+	Node *int   `json:"node,omitempty"` // Index of the animated node
+	Path string `json:"path"`           // Animated property: translation, rotation, scale or weights
+}
+
+// AnimationSampler contains the keyframe times and values of an animation
+// and the interpolation between them: LINEAR, STEP or CUBICSPLINE
+type AnimationSampler struct {
+	Input         int    `json:"input"`                   // Index of the accessor with the keyframe times in seconds
+	Interpolation string `json:"interpolation,omitempty"` // Interpolation algorithm, LINEAR by default
+	Output        int    `json:"output"`                  // Index of the accessor with the keyframe values
+}
+
+// Asset contains metadata about the document
+type Asset struct {
+	Copyright  string `json:"copyright,omitempty"`  // Copyright message
+	Generator  string `json:"generator,omitempty"`  // Tool which generated the document
+	Version    string `json:"version"`              // glTF version
+	MinVersion string `json:"minVersion,omitempty"` // Minimum glTF version required to load the document
+}
+
+// Buffer is a binary data buffer which is stored in an external file,
+// in a data URI or in the binary chunk of a .glb file if it has no URI
+type Buffer struct {
+	URI        string `json:"uri,omitempty"`  // Relative path or data URI of the buffer data
+	ByteLength int    `json:"byteLength"`     // Length of the buffer in bytes
+	Name       string `json:"name,omitempty"` // Name of the buffer
+	data       []byte // Loaded buffer data
+}
+
+// BufferView is a subset of a buffer
+type BufferView struct {
+	Buffer     int    `json:"buffer"`               // Index of the buffer
+	ByteOffset int    `json:"byteOffset"`           // Offset in the buffer in bytes
+	ByteLength int    `json:"byteLength"`           // Length of the view in bytes
+	ByteStride int    `json:"byteStride,omitempty"` // Distance between the start of consecutive vertex elements in bytes
+	Target     int    `json:"target,omitempty"`     // Intended OpenGL buffer target
+	Name       string `json:"name,omitempty"`       // Name of the buffer view
+}
+
+// Camera is a perspective or orthographic camera which looks
+// along the negative Z axis of the nodes which reference it
+type Camera struct {
+	Orthographic *Orthographic `json:"orthographic,omitempty"` // Orthographic projection
+	Perspective  *Perspective  `json:"perspective,omitempty"`  // Perspective projection
+	Type         string        `json:"type"`                   // Projection type: perspective or orthographic
+	Name         string        `json:"name,omitempty"`         // Name of the camera
+}
+
+// Orthographic contains the properties of an orthographic projection
+type Orthographic struct {
+	Xmag  float32 `json:"xmag"`  // Horizontal magnification of the view
+	Ymag  float32 `json:"ymag"`  // Vertical magnification of the view
+	Zfar  float32 `json:"zfar"`  // Distance to the far clipping plane
+	Znear float32 `json:"znear"` // Distance to the near clipping plane
+}
+
+// Perspective contains the properties of a perspective projection
+type Perspective struct {
+	AspectRatio *float32 `json:"aspectRatio,omitempty"` // Aspect ratio of the field of view
+	Yfov        float32  `json:"yfov"`                  // Vertical field of view in radians
+	Zfar        *float32 `json:"zfar,omitempty"`        // Distance to the far clipping plane or nil for an infinite projection
+	Znear       float32  `json:"znear"`                 // Distance to the near clipping plane
+}
+
+// Image is an image used by textures which is stored in an external
+// file, in a data URI or in a buffer view
+type Image struct {
+	URI        string `json:"uri,omitempty"`        // Relative path or data URI of the image
+	MimeType   string `json:"mimeType,omitempty"`   // Image MIME type, required with a buffer view
+	BufferView *int   `json:"bufferView,omitempty"` // Index of the buffer view with the image
+	Name       string `json:"name,omitempty"`       // Name of the image
+}
+
+// Material is a metallic-roughness physically based material
+type Material struct {
+	Name                 string                `json:"name,omitempty"`                 // Name of the material
+	PbrMetallicRoughness *PbrMetallicRoughness `json:"pbrMetallicRoughness,omitempty"` // Metallic-roughness parameters
+	NormalTexture        *NormalTextureInfo    `json:"normalTexture,omitempty"`        // Normal map in tangent space
+	OcclusionTexture     *OcclusionTextureInfo `json:"occlusionTexture,omitempty"`     // Ambient occlusion map in the red channel
+	EmissiveTexture      *TextureInfo          `json:"emissiveTexture,omitempty"`      // Emissive color map
+	EmissiveFactor       *[3]float32           `json:"emissiveFactor,omitempty"`       // Emissive color, black by default
+	AlphaMode            string                `json:"alphaMode,omitempty"`            // OPAQUE (default), MASK or BLEND
+	AlphaCutoff          *float32              `json:"alphaCutoff,omitempty"`          // Alpha cutoff value of the MASK mode
+	DoubleSided          bool                  `json:"doubleSided"`                    // Back faces are visible
+}
+
+// PbrMetallicRoughness contains the parameters of the
+// metallic-roughness physically based rendering model
+type PbrMetallicRoughness struct {
+	BaseColorFactor          *[4]float32  `json:"baseColorFactor,omitempty"`          // Base color and alpha, opaque white by default
+	BaseColorTexture         *TextureInfo `json:"baseColorTexture,omitempty"`         // Base color map in sRGB
+	MetallicFactor           *float32     `json:"metallicFactor,omitempty"`           // Metalness, 1 by default
+	RoughnessFactor          *float32     `json:"roughnessFactor,omitempty"`          // Roughness, 1 by default
+	MetallicRoughnessTexture *TextureInfo `json:"metallicRoughnessTexture,omitempty"` // Roughness in green and metalness in blue map
+}
+
+// TextureInfo is a reference to a texture
+type TextureInfo struct {
+	Index    int `json:"index"`    // Index of the texture
+	TexCoord int `json:"texCoord"` // Set of texture coordinates used by the texture
+}
+
+// NormalTextureInfo is a reference to a normal map texture
+type NormalTextureInfo struct {
+	Index    int      `json:"index"`           // Index of the texture
+	TexCoord int      `json:"texCoord"`        // Set of texture coordinates used by the texture
+	Scale    *float32 `json:"scale,omitempty"` // Scale of the X and Y normal components, 1 by default
+}
+
+// OcclusionTextureInfo is a reference to an occlusion map texture
+type OcclusionTextureInfo struct {
+	Index    int      `json:"index"`              // Index of the texture
+	TexCoord int      `json:"texCoord"`           // Set of texture coordinates used by the texture
+	Strength *float32 `json:"strength,omitempty"` // Amount of occlusion applied, 1 by default
+}
+
+// Mesh is a set of primitives rendered together
+type Mesh struct {
+	Primitives []Primitive `json:"primitives"`        // Primitives with a geometry and a material
+	Weights    []float32   `json:"weights,omitempty"` // Weights of the morph targets
+	Name       string      `json:"name,omitempty"`    // Name of the mesh
+}
+
+// Primitive is a geometry rendered with a material. POSITION, NORMAL,
+// TEXCOORD_0 and TEXCOORD_1 are the vertex attributes used by the loader.
+type Primitive struct {
+	Attributes map[string]int   `json:"attributes"`         // Accessors of the vertex attributes by semantic name
+	Indices    *int             `json:"indices,omitempty"`  // Index of the accessor with the vertex indices
+	Material   *int             `json:"material,omitempty"` // Index of the material or nil for the default material
+	Mode       *int             `json:"mode,omitempty"`     // Topology type, TRIANGLES by default
+	Targets    []map[string]int `json:"targets,omitempty"`  // Morph targets attributes
+}
+
+// Node is a node of the hierarchy with an optional mesh or camera and
+// its local transform as a matrix or as translation, rotation and scale
+type Node struct {
+	Camera      *int         `json:"camera,omitempty"`      // Index of the camera
+	Children    []int        `json:"children,omitempty"`    // Indices of the children nodes
+	Skin        *int         `json:"skin,omitempty"`        // Index of the skin of the mesh
+	Matrix      *[16]float32 `json:"matrix,omitempty"`      // Local transform as a column major matrix
+	Mesh        *int         `json:"mesh,omitempty"`        // Index of the mesh
+	Rotation    *[4]float32  `json:"rotation,omitempty"`    // Local rotation as a unit quaternion (x, y, z, w)
+	Scale       *[3]float32  `json:"scale,omitempty"`       // Local scale
+	Translation *[3]float32  `json:"translation,omitempty"` // Local translation
+	Weights     []float32    `json:"weights,omitempty"`     // Weights of the morph targets of the mesh
+	Name        string       `json:"name,omitempty"`        // Name of the node
+}
+
+// Sampler contains the filters and wrap modes of a texture
+// with the OpenGL constants values
+type Sampler struct {
+	MagFilter int    `json:"magFilter,omitempty"` // Magnification filter
+	MinFilter int    `json:"minFilter,omitempty"` // Minification filter
+	WrapS     int    `json:"wrapS,omitempty"`     // S (U) wrap mode, REPEAT by default
+	WrapT     int    `json:"wrapT,omitempty"`     // T (V) wrap mode, REPEAT by default
+	Name      string `json:"name,omitempty"`      // Name of the sampler
+}
+
+// Scene contains the root nodes of a scene
+type Scene struct {
+	Nodes []int  `json:"nodes,omitempty"` // Indices of the root nodes
+	Name  string `json:"name,omitempty"`  // Name of the scene
+}
+
+// Skin contains the joints and inverse bind matrices of a skinned mesh
+type Skin struct {
+	InverseBindMatrices *int   `json:"inverseBindMatrices,omitempty"` // Index of the accessor with the inverse bind matrices
+	Skeleton            *int   `json:"skeleton,omitempty"`            // Index of the skeleton root node
+	Joints              []int  `json:"joints"`                        // Indices of the joint nodes
+	Name                string `json:"name,omitempty"`                // Name of the skin
+}
+
+// Texture is an image with a sampler
+type Texture struct {
+	Sampler *int   `json:"sampler,omitempty"` // Index of the sampler or nil for repeat wrapping and automatic filtering
+	Source  *int   `json:"source,omitempty"`  // Index of the image
+	Name    string `json:"name,omitempty"`    // Name of the texture
+}
+
+// Accessor component types
+const (
+	BYTE           = 5120
+	UNSIGNED_BYTE  = 5121
+	SHORT          = 5122
+	UNSIGNED_SHORT = 5123
+	UNSIGNED_INT   = 5125
+	FLOAT          = 5126
+)
+
+// Primitive topology types
+const (
+	POINTS         = 0
+	LINES          = 1
+	LINE_LOOP      = 2
+	LINE_STRIP     = 3
+	TRIANGLES      = 4
+	TRIANGLE_STRIP = 5
+	TRIANGLE_FAN   = 6
+)
+
+// TypeSizes maps the accessor element types to their number of components
+var TypeSizes = map[string]int{
+	"SCALAR": 1,
+	"VEC2":   2,
+	"VEC3":   3,
+	"VEC4":   4,
+	"MAT2":   4,
+	"MAT3":   9,
+	"MAT4":   16,
+}
@@ -0,0 +1,218 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/texture"
+)
+
+// NewMaterial creates and returns a physical material from the
+// metallic-roughness material with the specified index. Blended materials
+// are transparent and masked materials use alpha to coverage, which needs
+// a multisampled window. The textures are shared with the other materials
+// created by the decoder.
+func (d *Decoder) NewMaterial(idx int) (*material.Physical, error) {
+
+	if idx < 0 || idx >= len(d.Materials) {
+		return nil, fmt.Errorf("Invalid material index:%d", idx)
+	}
+	m := &d.Materials[idx]
+	pm := material.NewPhysical()
+
+	// Metallic-roughness parameters and maps
+	if pbr := m.PbrMetallicRoughness; pbr != nil {
+		if c := pbr.BaseColorFactor; c != nil {
+			pm.SetBaseColorFactor(&math32.Color4{c[0], c[1], c[2], c[3]})
+		}
+		if pbr.MetallicFactor != nil {
+			pm.SetMetallicFactor(*pbr.MetallicFactor)
+		}
+		if pbr.RoughnessFactor != nil {
+			pm.SetRoughnessFactor(*pbr.RoughnessFactor)
+		}
+		if info := pbr.BaseColorTexture; info != nil {
+			tex, err := d.texture(info.Index, info.TexCoord)
+			if err != nil {
+				return nil, err
+			}
+			pm.SetBaseColorMap(tex)
+		}
+		if info := pbr.MetallicRoughnessTexture; info != nil {
+			tex, err := d.texture(info.Index, info.TexCoord)
+			if err != nil {
+				return nil, err
+			}
+			pm.SetMetallicRoughnessMap(tex)
+		}
+	}
+
+	// Additional maps
+	if info := m.NormalTexture; info != nil {
+		tex, err := d.texture(info.Index, info.TexCoord)
+		if err != nil {
+			return nil, err
+		}
+		pm.SetNormalMap(tex)
+		if info.Scale != nil {
+			pm.SetNormalScale(*info.Scale)
+		}
+	}
+	if info := m.OcclusionTexture; info != nil {
+		tex, err := d.texture(info.Index, info.TexCoord)
+		if err != nil {
+			return nil, err
+		}
+		pm.SetOcclusionMap(tex)
+		if info.Strength != nil {
+			pm.SetOcclusionStrength(*info.Strength)
+		}
+	}
+	if info := m.EmissiveTexture; info != nil {
+		tex, err := d.texture(info.Index, info.TexCoord)
+		if err != nil {
+			return nil, err
+		}
+		pm.SetEmissiveMap(tex)
+	}
+	if c := m.EmissiveFactor; c != nil {
+		pm.SetEmissiveFactor(&math32.Color{c[0], c[1], c[2]})
+	}
+
+	// Alpha mode and sides
+	switch m.AlphaMode {
+	case "BLEND":
+		pm.SetTransparent(true)
+	case "MASK":
+		pm.SetBlending(material.BlendingNone)
+		pm.SetAlphaToCoverage(true)
+	default:
+		pm.SetBlending(material.BlendingNone)
+	}
+	if m.DoubleSided {
+		pm.SetSide(material.SideDouble)
+	}
+	return pm, nil
+}
+
+// material returns the material with the specified index
+// creating it when first used and sharing it afterwards
+func (d *Decoder) material(idx int) (material.IMaterial, error) {
+
+	if imat := d.materials[idx]; imat != nil {
+		imat.GetMaterial().Incref()
+		return imat, nil
+	}
+	pm, err := d.NewMaterial(idx)
+	if err != nil {
+		return nil, err
+	}
+	d.materials[idx] = pm
+	return pm, nil
+}
+
+// NewTexture creates and returns the texture with the specified index
+// with its image and sampler filters and wrap modes.
+func (d *Decoder) NewTexture(idx int) (*texture.Texture2D, error) {
+
+	if idx < 0 || idx >= len(d.Textures) {
+		return nil, fmt.Errorf("Invalid texture index:%d", idx)
+	}
+	t := &d.Textures[idx]
+	if t.Source == nil {
+		return nil, fmt.Errorf("Texture:%d without image", idx)
+	}
+	rgba, err := d.loadImage(*t.Source)
+	if err != nil {
+		return nil, err
+	}
+	tex := texture.NewTexture2DFromRGBA(rgba)
+	// The texture coordinates start at the top of the image
+	tex.SetFlipY(false)
+
+	// Sets the sampler parameters with the same values as OpenGL
+	wrapS, wrapT := gls.REPEAT, gls.REPEAT
+	if t.Sampler != nil {
+		if *t.Sampler < 0 || *t.Sampler >= len(d.Samplers) {
+			return nil, fmt.Errorf("Invalid sampler index:%d", *t.Sampler)
+		}
+		s := &d.Samplers[*t.Sampler]
+		if s.MagFilter != 0 {
+			tex.SetMagFilter(uint32(s.MagFilter))
+		}
+		if s.MinFilter != 0 {
+			tex.SetMinFilter(uint32(s.MinFilter))
+		}
+		if s.WrapS != 0 {
+			wrapS = s.WrapS
+		}
+		if s.WrapT != 0 {
+			wrapT = s.WrapT
+		}
+	}
+	tex.SetWrap(uint32(wrapS), uint32(wrapT))
+	return tex, nil
+}
+
+// texture returns the texture with the specified index using the specified
+// set of texture coordinates, creating it when first used and sharing it afterwards.
+// The set of texture coordinates is a property of the texture, so a texture
+// used with different sets by the materials is created once for each set.
+func (d *Decoder) texture(idx, texCoord int) (*texture.Texture2D, error) {
+
+	key := [2]int{idx, texCoord}
+	tex := d.textures[key]
+	if tex != nil {
+		tex.Incref()
+		return tex, nil
+	}
+	tex, err := d.NewTexture(idx)
+	if err != nil {
+		return nil, err
+	}
+	tex.SetUVChannel(texCoord)
+	d.textures[key] = tex
+	return tex, nil
+}
+
+// loadImage loads and decodes the image with the specified index into RGBA8
+func (d *Decoder) loadImage(idx int) (*image.RGBA, error) {
+
+	if idx < 0 || idx >= len(d.Images) {
+		return nil, fmt.Errorf("Invalid image index:%d", idx)
+	}
+	img := &d.Images[idx]
+	var data []byte
+	if img.BufferView != nil {
+		view, _, err := d.viewData(*img.BufferView, 0, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		data = view
+	} else {
+		var err error
+		data, err = d.loadURI(img.URI)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	rgba := image.NewRGBA(decoded.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+	return rgba, nil
+}
@@ -0,0 +1,358 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gltf
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+// Far plane distance of the perspective cameras with an infinite projection
+const infiniteFar = 1e5
+
+// NewScene creates and returns a node with the root nodes of the default
+// scene of the document, or of the first scene if there is no default one.
+func (d *Decoder) NewScene() (core.INode, error) {
+
+	if d.Scene != nil {
+		return d.NewSceneAt(*d.Scene)
+	}
+	return d.NewSceneAt(0)
+}
+
+// NewSceneAt creates and returns a node with the root nodes
+// of the scene with the specified index.
+// The cameras of the scene are pointed in the direction
+// of the negative Z axis of their nodes.
+func (d *Decoder) NewSceneAt(idx int) (core.INode, error) {
+
+	if idx < 0 || idx >= len(d.Scenes) {
+		return nil, fmt.Errorf("Invalid scene index:%d", idx)
+	}
+	sc := &d.Scenes[idx]
+	scene := core.NewNode()
+	scene.SetName(sc.Name)
+	for _, ni := range sc.Nodes {
+		node, err := d.NewNode(ni)
+		if err != nil {
+			return nil, err
+		}
+		scene.Add(node)
+	}
+
+	// The camera view matrix is calculated from its target
+	scene.UpdateMatrixWorld()
+	scene.Traverse(func(inode core.INode) bool {
+		if cam, ok := inode.(camera.ICamera); ok {
+			var pos, dir, up math32.Vector3
+			var quat math32.Quaternion
+			cam.GetCamera().WorldPosition(&pos)
+			cam.GetCamera().WorldQuaternion(&quat)
+			dir.Set(0, 0, -1).ApplyQuaternion(&quat)
+			up.Set(0, 1, 0).ApplyQuaternion(&quat)
+			cam.GetCamera().SetUp(&up)
+			cam.GetCamera().LookAt(dir.Add(&pos))
+		}
+		return true
+	})
+	return scene, nil
+}
+
+// NewNode creates and returns the node with the specified index with
+// its mesh or camera and all its descendants. The transform of the node
+// is set from its matrix or its translation, rotation and scale.
+// The animators target the last created instance of each node.
+func (d *Decoder) NewNode(idx int) (core.INode, error) {
+
+	if idx < 0 || idx >= len(d.Nodes) {
+		return nil, fmt.Errorf("Invalid node index:%d", idx)
+	}
+	if d.building[idx] {
+		return nil, fmt.Errorf("Node:%d is its own descendant", idx)
+	}
+	d.building[idx] = true
+	defer delete(d.building, idx)
+
+	// Creates the node with its mesh, camera or empty
+	n := &d.Nodes[idx]
+	var inode core.INode
+	var err error
+	switch {
+	case n.Mesh != nil:
+		inode, err = d.NewMesh(*n.Mesh)
+	case n.Camera != nil:
+		var icam camera.ICamera
+		icam, err = d.NewCamera(*n.Camera)
+		if err == nil {
+			inode = icam.(core.INode)
+		}
+	default:
+		inode = core.NewNode()
+	}
+	if err != nil {
+		return nil, err
+	}
+	node := inode.GetNode()
+	node.SetName(n.Name)
+	if n.Mesh != nil && n.Camera != nil {
+		icam, err := d.NewCamera(*n.Camera)
+		if err != nil {
+			return nil, err
+		}
+		node.Add(icam.(core.INode))
+	}
+	if n.Skin != nil {
+		log.Warn("Skin of node:%d not supported", idx)
+	}
+
+	// Sets the node transform
+	if n.Matrix != nil {
+		var m math32.Matrix4
+		var position, scale math32.Vector3
+		var quaternion math32.Quaternion
+		m.FromArray(*n.Matrix)
+		m.Decompose(&position, &quaternion, &scale)
+		node.SetPositionVec(&position)
+		node.SetQuaternionQuat(&quaternion)
+		node.SetScaleVec(&scale)
+	}
+	if n.Translation != nil {
+		node.SetPosition(n.Translation[0], n.Translation[1], n.Translation[2])
+	}
+	if n.Rotation != nil {
+		node.SetQuaternion(n.Rotation[0], n.Rotation[1], n.Rotation[2], n.Rotation[3])
+	}
+	if n.Scale != nil {
+		node.SetScale(n.Scale[0], n.Scale[1], n.Scale[2])
+	}
+
+	// Creates the children nodes
+	for _, ci := range n.Children {
+		child, err := d.NewNode(ci)
+		if err != nil {
+			return nil, err
+		}
+		node.Add(child)
+	}
+	d.nodes[idx] = inode
+	return inode, nil
+}
+
+// NewMesh creates and returns the mesh with the specified index. A mesh with
+// one primitive is returned as a graphic and a mesh with several primitives
+// as a node with a graphic for each primitive. Triangles are drawn as meshes
+// with physical materials, lines as lines and points as points.
+// The geometries and materials are shared by the instances of the mesh.
+func (d *Decoder) NewMesh(idx int) (core.INode, error) {
+
+	if idx < 0 || idx >= len(d.Meshes) {
+		return nil, fmt.Errorf("Invalid mesh index:%d", idx)
+	}
+	m := &d.Meshes[idx]
+	if len(m.Primitives) == 1 {
+		igr, err := d.newPrimitive(idx, 0)
+		if err != nil {
+			return nil, err
+		}
+		igr.GetNode().SetName(m.Name)
+		return igr, nil
+	}
+	group := core.NewNode()
+	group.SetName(m.Name)
+	for pi := range m.Primitives {
+		igr, err := d.newPrimitive(idx, pi)
+		if err != nil {
+			return nil, err
+		}
+		group.Add(igr)
+	}
+	return group, nil
+}
+
+// NewCamera creates and returns the camera with the specified index.
+// Perspective cameras without aspect ratio use 1 and should be updated
+// with the aspect ratio of the window.
+func (d *Decoder) NewCamera(idx int) (camera.ICamera, error) {
+
+	if idx < 0 || idx >= len(d.Cameras) {
+		return nil, fmt.Errorf("Invalid camera index:%d", idx)
+	}
+	c := &d.Cameras[idx]
+	switch {
+	case c.Type == "perspective" && c.Perspective != nil:
+		p := c.Perspective
+		aspect := float32(1)
+		if p.AspectRatio != nil {
+			aspect = *p.AspectRatio
+		}
+		far := float32(infiniteFar)
+		if p.Zfar != nil {
+			far = *p.Zfar
+		}
+		cam := camera.NewPerspective(math32.RadToDeg(p.Yfov), aspect, p.Znear, far)
+		cam.SetName(c.Name)
+		return cam, nil
+	case c.Type == "orthographic" && c.Orthographic != nil:
+		o := c.Orthographic
+		cam := camera.NewOrthographic(-o.Xmag, o.Xmag, o.Ymag, -o.Ymag, o.Znear, o.Zfar)
+		cam.SetName(c.Name)
+		return cam, nil
+	}
+	return nil, fmt.Errorf("Camera:%d with invalid type:%s", idx, c.Type)
+}
+
+// newPrimitive creates and returns a graphic for the
+// specified primitive of the mesh with the specified index
+func (d *Decoder) newPrimitive(mi, pi int) (graphic.IGraphic, error) {
+
+	prim := &d.Meshes[mi].Primitives[pi]
+	mode := TRIANGLES
+	if prim.Mode != nil {
+		mode = *prim.Mode
+	}
+	if mode < POINTS || mode > TRIANGLE_FAN {
+		return nil, fmt.Errorf("Primitive mode:%d not supported", mode)
+	}
+	if len(prim.Targets) > 0 {
+		log.Warn("Morph targets of mesh:%d not supported", mi)
+	}
+
+	// Geometries are shared by the instances of the mesh
+	key := [2]int{mi, pi}
+	geom := d.geometries[key]
+	if geom == nil {
+		var err error
+		geom, err = d.newGeometry(prim, mode)
+		if err != nil {
+			return nil, err
+		}
+		d.geometries[key] = geom
+	} else {
+		geom.Incref()
+	}
+
+	switch mode {
+	case POINTS:
+		return graphic.NewPoints(geom, material.NewPoint(math32.NewColor(1, 1, 1))), nil
+	case LINES:
+		return graphic.NewLines(geom, material.NewBasic()), nil
+	case LINE_LOOP, LINE_STRIP:
+		return graphic.NewLineStrip(geom, material.NewBasic()), nil
+	}
+	var imat material.IMaterial
+	if prim.Material != nil {
+		var err error
+		imat, err = d.material(*prim.Material)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		imat = material.NewPhysical()
+	}
+	return graphic.NewMesh(geom, imat), nil
+}
+
+// newGeometry creates and returns the geometry of the specified primitive
+// with the specified mode. Triangle strips and fans are converted to
+// triangles and line loops to line strips. Flat normals are calculated
+// for triangles without normals.
+func (d *Decoder) newGeometry(prim *Primitive, mode int) (*geometry.Geometry, error) {
+
+	geom := geometry.NewGeometry()
+	ai, ok := prim.Attributes["POSITION"]
+	if !ok {
+		return nil, fmt.Errorf("Primitive without POSITION attribute")
+	}
+	positions, err := d.loadFloats(ai, "VEC3")
+	if err != nil {
+		return nil, err
+	}
+	count := len(positions) / 3
+	geom.AddVBO(gls.NewVBO().AddAttrib("VertexPosition", 3).SetBuffer(positions))
+
+	// Optional attributes used by the materials
+	attribs := []struct {
+		semantic string
+		name     string
+		size     int
+		types    []string
+	}{
+		{"NORMAL", "VertexNormal", 3, []string{"VEC3"}},
+		{"TEXCOORD_0", "VertexTexcoord", 2, []string{"VEC2"}},
+		{"TEXCOORD_1", "VertexTexcoord2", 2, []string{"VEC2"}},
+	}
+	for _, attr := range attribs {
+		ai, ok := prim.Attributes[attr.semantic]
+		if !ok {
+			continue
+		}
+		values, err := d.loadFloats(ai, attr.types...)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != count*attr.size {
+			return nil, fmt.Errorf("Primitive attribute:%s with invalid count", attr.semantic)
+		}
+		geom.AddVBO(gls.NewVBO().AddAttrib(attr.name, int32(attr.size)).SetBuffer(values))
+	}
+
+	// Indices or sequential indices for the modes which need conversion
+	var indices math32.ArrayU32
+	if prim.Indices != nil {
+		indices, err = d.loadIndices(*prim.Indices)
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range indices {
+			if int(idx) >= count {
+				return nil, fmt.Errorf("Primitive index:%d out of range", idx)
+			}
+		}
+	} else if mode == TRIANGLE_STRIP || mode == TRIANGLE_FAN || mode == LINE_LOOP {
+		indices = math32.NewArrayU32(count, count)
+		for i := range indices {
+			indices[i] = uint32(i)
+		}
+	}
+
+	// Converts the modes without an equivalent graphic
+	switch mode {
+	case TRIANGLE_STRIP:
+		tris := math32.NewArrayU32(0, 3*len(indices))
+		for i := 0; i+2 < len(indices); i++ {
+			if i%2 == 0 {
+				tris.Append(indices[i], indices[i+1], indices[i+2])
+			} else {
+				tris.Append(indices[i], indices[i+2], indices[i+1])
+			}
+		}
+		indices = tris
+	case TRIANGLE_FAN:
+		tris := math32.NewArrayU32(0, 3*len(indices))
+		for i := 1; i+1 < len(indices); i++ {
+			tris.Append(indices[0], indices[i], indices[i+1])
+		}
+		indices = tris
+	case LINE_LOOP:
+		if len(indices) > 0 {
+			indices.Append(indices[0])
+		}
+	}
+	if indices != nil {
+		geom.SetIndices(indices)
+	}
+
+	if geom.VBO("VertexNormal") == nil && (mode == TRIANGLES || mode == TRIANGLE_STRIP || mode == TRIANGLE_FAN) {
+		geom.ComputeFlatNormals()
+	}
+	return geom, nil
+}
@@ -0,0 +1,346 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package loader loads models without blocking the window goroutine.
+// The model files are decoded and their nodes created by a worker goroutine.
+// The tasks are polled in the goroutine of the window, which owns the OpenGL
+// context, where the geometries and textures of the loaded nodes are
+// transferred to OpenGL before the nodes are delivered to be added to the scene.
+package loader
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/loader/collada"
+	"github.com/g3n/engine/loader/gltf"
+	"github.com/g3n/engine/loader/obj"
+	"github.com/g3n/engine/window"
+)
+
+// Task event names using for dispatch and subscribe
+const (
+	OnProgress = "loader.OnProgress" // More of the model file was read
+	OnLoad     = "loader.OnLoad"     // Task loaded the model, failed or was canceled
+)
+
+// ProgressEvent is dispatched as OnProgress when the
+// fraction of the model file read by the task changes
+type ProgressEvent struct {
+	Task     *Task
+	Progress float32 // Fraction of the model file read from 0 to 1
+}
+
+// LoadEvent is dispatched as OnLoad when the task ends
+type LoadEvent struct {
+	Task *Task
+	Node core.INode // Loaded node or nil if Err is not nil
+	Err  error      // Error of the task or ErrCanceled
+}
+
+// ErrCanceled is the error of the load event of canceled tasks
+var ErrCanceled = errors.New("loading canceled")
+
+// DecodeFunc decodes a model from the specified reader and creates its node.
+// It is called by the worker goroutine of a task, so it must not use OpenGL
+// nor nodes shared with the window goroutine. The reader fails with
+// ErrCanceled when the task is canceled.
+type DecodeFunc func(r io.Reader) (core.INode, error)
+
+// Task loads a model in a worker goroutine. It is polled and its events are
+// dispatched in the goroutine of the window on the window OnFrame event,
+// which the application must dispatch once per frame. For example:
+//
+//	task := loader.NewGltfTask(win, gs, "models/city.glb")
+//	task.Subscribe(loader.OnProgress, func(evname string, ev interface{}) {
+//		bar.SetValue(ev.(*loader.ProgressEvent).Progress)
+//	})
+//	task.Subscribe(loader.OnLoad, func(evname string, ev interface{}) {
+//		lev := ev.(*loader.LoadEvent)
+//		if lev.Err != nil {
+//			log.Error("%s", lev.Err)
+//			return
+//		}
+//		scene.Add(lev.Node)
+//	})
+//	task.Start()
+//	...
+//	for !win.ShouldClose() {
+//		win.Dispatch(window.OnFrame, nil)
+//		...
+//	}
+type Task struct {
+	core.Dispatcher                // Embedded event dispatcher
+	win             window.IWindow // Window which dispatches the task events
+	gs              *gls.GLS       // OpenGL state used to transfer the loaded node
+	path            string         // Path of the model file
+	decode          DecodeFunc     // Function which decodes the model
+	size            int64          // Size of the model file (atomic)
+	read            int64          // Number of bytes read of the model file (atomic)
+	canceled        int32          // Cancellation flag (atomic)
+	started         bool           // Task started flag
+	ended           bool           // Task ended flag
+	progress        float32        // Last dispatched progress
+	done            chan LoadEvent // Result of the worker goroutine
+	subsEvents      int            // Address used as window subscription id
+}
+
+// NewTask creates and returns a task which loads the model file with the
+// specified path using the specified decode function, which may save the
+// decoder to create other objects of the model, such as its animations.
+// The geometries and textures of the loaded node are transferred to the
+// OpenGL context of the specified OpenGL state, if not nil, before the
+// OnLoad event is dispatched, so the first frame which renders the node
+// does not stall:
+//
+//	var dec *collada.Decoder
+//	task := loader.NewTask(win, gs, path, func(r io.Reader) (core.INode, error) {
+//		var err error
+//		dec, err = collada.DecodeReader(r)
+//		if err != nil {
+//			return nil, err
+//		}
+//		dec.SetDirImages(filepath.Dir(path))
+//		return dec.NewScene()
+//	})
+//
+// The decoder should only be used in the window goroutine after the
+// OnLoad event is dispatched.
+func NewTask(win window.IWindow, gs *gls.GLS, path string, decode DecodeFunc) *Task {
+
+	t := new(Task)
+	t.Dispatcher.Initialize()
+	t.win = win
+	t.gs = gs
+	t.path = path
+	t.decode = decode
+	t.done = make(chan LoadEvent, 1)
+	return t
+}
+
+// NewObjTask creates and returns a task which loads the specified obj
+// and mtl files as a group with the meshes of the decoded objects.
+// If the path of the material file is empty, the path of the obj file
+// with the .mtl extension is used.
+func NewObjTask(win window.IWindow, gs *gls.GLS, objpath, mtlpath string) *Task {
+
+	if len(mtlpath) == 0 {
+		dir, objfile := filepath.Split(objpath)
+		ext := filepath.Ext(objfile)
+		mtlpath = dir + objfile[:len(objfile)-len(ext)] + ".mtl"
+	}
+	return NewTask(win, gs, objpath, func(r io.Reader) (core.INode, error) {
+		fmtl, err := os.Open(mtlpath)
+		if err != nil {
+			return nil, err
+		}
+		defer fmtl.Close()
+		dec, err := obj.DecodeReader(r, fmtl)
+		if err != nil {
+			return nil, err
+		}
+		dec.SetMtlDir(filepath.Dir(objpath))
+		group, err := dec.NewGroup()
+		if err != nil {
+			return nil, err
+		}
+		return group, nil
+	})
+}
+
+// NewColladaTask creates and returns a task which loads the
+// scene of the specified Collada file.
+func NewColladaTask(win window.IWindow, gs *gls.GLS, path string) *Task {
+
+	return NewTask(win, gs, path, func(r io.Reader) (core.INode, error) {
+		dec, err := collada.DecodeReader(r)
+		if err != nil {
+			return nil, err
+		}
+		dec.SetDirImages(filepath.Dir(path))
+		return dec.NewScene()
+	})
+}
+
+// NewGltfTask creates and returns a task which loads the default
+// scene of the specified glTF or binary glTF file.
+func NewGltfTask(win window.IWindow, gs *gls.GLS, path string) *Task {
+
+	return NewTask(win, gs, path, func(r io.Reader) (core.INode, error) {
+		dec, err := gltf.DecodeReader(r, filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		return dec.NewScene()
+	})
+}
+
+// Path returns the path of the model file of this task
+func (t *Task) Path() string {
+
+	return t.path
+}
+
+// Start starts loading the model in a worker goroutine.
+// It must be called in the window goroutine.
+func (t *Task) Start() {
+
+	if t.started {
+		return
+	}
+	t.started = true
+	t.win.SubscribeID(window.OnFrame, &t.subsEvents, t.onFrame)
+	go t.run()
+}
+
+// Cancel requests the cancellation of this task, which stops reading the
+// model file and dispatches the OnLoad event with ErrCanceled. Nodes
+// created before the cancellation are disposed with their resources
+// when the task is next polled. It may be called from any goroutine.
+func (t *Task) Cancel() {
+
+	atomic.StoreInt32(&t.canceled, 1)
+}
+
+// Canceled returns if the cancellation of this task was requested
+func (t *Task) Canceled() bool {
+
+	return atomic.LoadInt32(&t.canceled) != 0
+}
+
+// Progress returns the fraction of the model file read from 0 to 1.
+// The nodes are created after the file is read.
+func (t *Task) Progress() float32 {
+
+	size := atomic.LoadInt64(&t.size)
+	if size <= 0 {
+		return 0
+	}
+	p := float32(atomic.LoadInt64(&t.read)) / float32(size)
+	if p > 1 {
+		p = 1
+	}
+	return p
+}
+
+// run decodes the model and sends the result to the window goroutine
+func (t *Task) run() {
+
+	node, err := t.load()
+	t.done <- LoadEvent{Task: t, Node: node, Err: err}
+}
+
+// load opens and decodes the model file
+func (t *Task) load() (core.INode, error) {
+
+	f, err := os.Open(t.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	atomic.StoreInt64(&t.size, info.Size())
+	return t.decode(&taskReader{t, f})
+}
+
+// Poll dispatches the progress of the task and finishes it if the worker
+// goroutine is done. Returns true if the task ended. It is called on each window OnFrame
+// event after the task is started and must only be called in the window
+// goroutine, as finishing the task transfers the loaded node to OpenGL.
+func (t *Task) Poll() bool {
+
+	if !t.started || t.ended {
+		return t.ended
+	}
+	select {
+	case result := <-t.done:
+		t.finish(&result)
+		return true
+	default:
+		t.dispatchProgress(t.Progress())
+		return false
+	}
+}
+
+// onFrame is called on each window OnFrame event to poll the task
+func (t *Task) onFrame(evname string, ev interface{}) {
+
+	t.Poll()
+}
+
+// finish disposes the node of a canceled task or transfers the loaded node
+// to OpenGL and then dispatches the OnLoad event with the result of the task
+func (t *Task) finish(result *LoadEvent) {
+
+	t.ended = true
+	t.win.UnsubscribeID(window.OnFrame, &t.subsEvents)
+	if t.Canceled() {
+		if result.Node != nil {
+			core.DisposeTree(result.Node)
+		}
+		result.Node = nil
+		result.Err = ErrCanceled
+	} else if result.Err == nil {
+		if t.gs != nil {
+			transfer(t.gs, result.Node)
+		}
+		t.dispatchProgress(1)
+	}
+	t.Dispatch(OnLoad, result)
+}
+
+// transfer transfers the geometries and textures of the graphics
+// of the specified node and its descendants to OpenGL
+func transfer(gs *gls.GLS, inode core.INode) {
+
+	visit := func(inode core.INode) bool {
+		igr, ok := inode.(graphic.IGraphic)
+		if !ok {
+			return true
+		}
+		igr.GetGeometry().RenderSetup(gs)
+		for _, grmat := range igr.GetGraphic().Materials() {
+			grmat.GetMaterial().GetMaterial().Transfer(gs)
+		}
+		return true
+	}
+	visit(inode)
+	inode.GetNode().Traverse(visit)
+}
+
+// dispatchProgress dispatches the specified progress if it changed
+func (t *Task) dispatchProgress(p float32) {
+
+	if p == t.progress {
+		return
+	}
+	t.progress = p
+	t.Dispatch(OnProgress, &ProgressEvent{Task: t, Progress: p})
+}
+
+// taskReader counts the bytes read of the model file
+// and fails when the task is canceled
+type taskReader struct {
+	t *Task
+	r io.Reader
+}
+
+func (tr *taskReader) Read(p []byte) (int, error) {
+
+	if tr.t.Canceled() {
+		return 0, ErrCanceled
+	}
+	n, err := tr.r.Read(p)
+	atomic.AddInt64(&tr.t.read, int64(n))
+	return n, err
+}
@@ -0,0 +1,150 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/window"
+)
+
+// testWindow is a window which only records the OnFrame subscriptions
+type testWindow struct {
+	window.IWindow
+	subscribed int
+}
+
+func (w *testWindow) SubscribeID(evname string, id interface{}, cb core.Callback) {
+
+	w.subscribed++
+}
+
+func (w *testWindow) UnsubscribeID(evname string, id interface{}) int {
+
+	w.subscribed--
+	return 1
+}
+
+// disposeNode is a node which records if it was disposed
+type disposeNode struct {
+	core.Node
+	disposed bool
+}
+
+func (dn *disposeNode) Dispose() {
+
+	dn.disposed = true
+}
+
+func newDisposeNode() *disposeNode {
+
+	dn := new(disposeNode)
+	dn.Init()
+	return dn
+}
+
+// writeModel writes a model file with the specified size in a temporary
+// directory and returns its path
+func writeModel(t *testing.T, size int) string {
+
+	dir, err := ioutil.TempDir("", "loader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "model")
+	err = ioutil.WriteFile(path, make([]byte, size), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// poll polls the specified task until it ends or a timeout and returns
+// the load event and the dispatched progress values
+func poll(t *testing.T, task *Task) (*LoadEvent, []float32) {
+
+	var lev *LoadEvent
+	var progress []float32
+	task.Subscribe(OnLoad, func(evname string, ev interface{}) {
+		lev = ev.(*LoadEvent)
+	})
+	task.Subscribe(OnProgress, func(evname string, ev interface{}) {
+		progress = append(progress, ev.(*ProgressEvent).Progress)
+	})
+	task.Start()
+	timeout := time.Now().Add(5 * time.Second)
+	for !task.Poll() {
+		if time.Now().After(timeout) {
+			t.Fatalf("task not ended")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return lev, progress
+}
+
+func TestTaskLoad(t *testing.T) {
+
+	path := writeModel(t, 1000)
+	defer os.RemoveAll(filepath.Dir(path))
+	win := new(testWindow)
+	node := core.NewNode()
+	task := NewTask(win, nil, path, func(r io.Reader) (core.INode, error) {
+		_, err := ioutil.ReadAll(r)
+		return node, err
+	})
+	lev, progress := poll(t, task)
+	if lev == nil || lev.Task != task || lev.Node != node || lev.Err != nil {
+		t.Fatalf("load event %+v, want the node without error", lev)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != 1 {
+		t.Errorf("progress %v, want ending with 1", progress)
+	}
+	if win.subscribed != 0 {
+		t.Errorf("%d OnFrame subscriptions after the task ended, want 0", win.subscribed)
+	}
+	// Polling an ended task does nothing
+	lev = nil
+	if !task.Poll() || lev != nil {
+		t.Errorf("polling an ended task dispatched %+v", lev)
+	}
+}
+
+func TestTaskCancel(t *testing.T) {
+
+	path := writeModel(t, 1000)
+	defer os.RemoveAll(filepath.Dir(path))
+	root := newDisposeNode()
+	child := newDisposeNode()
+	root.Add(child)
+	task := NewTask(new(testWindow), nil, path, func(r io.Reader) (core.INode, error) {
+		return root, nil
+	})
+	task.Cancel()
+	lev, _ := poll(t, task)
+	if lev == nil || lev.Node != nil || lev.Err != ErrCanceled {
+		t.Fatalf("load event %+v, want ErrCanceled without node", lev)
+	}
+	// The nodes created before the cancellation are disposed
+	if !root.disposed || !child.disposed {
+		t.Errorf("root disposed:%v child disposed:%v, want both disposed", root.disposed, child.disposed)
+	}
+
+	// Canceled tasks fail reading the model file
+	task = NewTask(new(testWindow), nil, path, func(r io.Reader) (core.INode, error) {
+		_, err := ioutil.ReadAll(r)
+		return nil, err
+	})
+	task.Cancel()
+	lev, _ = poll(t, task)
+	if lev == nil || lev.Err != ErrCanceled {
+		t.Errorf("load event %+v, want ErrCanceled", lev)
+	}
+}
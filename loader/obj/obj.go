@@ -36,7 +36,7 @@ type Decoder struct {
 	line          uint                 // current line number
 	objCurrent    *Object              // current object
 	matCurrent    *Material            // current material
-	smoothCurrent bool                 // current smooth state
+	smoothCurrent int                  // current smoothing group
 	mtlDir        string               // Directory of material file
 }
 
@@ -44,16 +44,17 @@ type Decoder struct {
 type Object struct {
 	Name      string   // Object name
 	Faces     []Face   // Faces
-	materials []string // Materials used in this object
+	materials []string // Materials used by the faces in order of first use
 }
 
 // Face contains all information about an object face
 type Face struct {
-	Vertices []int  // Indices to the face vertices
-	Uvs      []int  // Indices to the face UV coordinates
-	Normals  []int  // Indices to the face normals
-	Material string // Material name
-	Smooth   bool   // Smooth face
+	Vertices    []int  // Indices to the face vertices
+	Uvs         []int  // Indices to the face UV coordinates
+	Normals     []int  // Indices to the face normals
+	Material    string // Material name
+	Smooth      bool   // Smooth face
+	SmoothGroup int    // Smoothing group number or 0 if the face is not smooth
 }
 
 // Material contains all information about an object material
@@ -138,6 +139,13 @@ func DecodeReader(objreader, mtlreader io.Reader) (*Decoder, error) {
 	return dec, nil
 }
 
+// SetMtlDir sets the directory used to load the texture files
+// of the materials decoded by DecodeReader.
+func (dec *Decoder) SetMtlDir(path string) {
+
+	dec.mtlDir = path
+}
+
 // NewGroup creates and returns a group containing as children meshes
 // with all the decoded objects.
 // A group is returned even if there is only one object decoded.
@@ -155,6 +163,41 @@ func (dec *Decoder) NewGroup() (*core.Node, error) {
 }
 
 // NewMesh creates and returns a mesh from an specified decoded object.
+// If the faces of the object use more than one material, the mesh
+// geometry has a group with the faces of each material and the mesh
+// has a material for each group.
+// For example, to load an object with a red and a blue face:
+//
+//	objData := `
+//	mtllib cube.mtl
+//	o quads
+//	v 0 0 0
+//	v 1 0 0
+//	v 1 1 0
+//	v 0 1 0
+//	v 2 0 0
+//	v 2 1 0
+//	usemtl red
+//	f 1 2 3 4
+//	usemtl blue
+//	f 2 5 6 3
+//	`
+//	mtlData := `
+//	newmtl red
+//	Kd 1 0 0
+//	newmtl blue
+//	Kd 0 0 1
+//	`
+//	dec, err := obj.DecodeReader(strings.NewReader(objData), strings.NewReader(mtlData))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	mesh, err := dec.NewMesh(&dec.Objects[0])
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	// mesh.Materials() has 2 materials with the red and blue diffuse colors
+//	scene.Add(mesh)
 func (dec *Decoder) NewMesh(obj *Object) (*graphic.Mesh, error) {
 
 	// Creates object geometry
@@ -165,16 +208,7 @@ func (dec *Decoder) NewMesh(obj *Object) (*graphic.Mesh, error) {
 
 	// Single material
 	if geom.GroupCount() == 1 {
-		matName := obj.materials[0]
-		matDesc := dec.Materials[matName]
-		// Creates material
-		mat := material.NewPhong(&matDesc.Diffuse)
-		ambientColor := mat.AmbientColor()
-		mat.SetAmbientColor(ambientColor.Multiply(&matDesc.Ambient))
-		mat.SetSpecularColor(&matDesc.Specular)
-		mat.SetShininess(matDesc.Shininess)
-		// Loads material textures if specified
-		err = dec.loadTex(&mat.Material, matDesc)
+		mat, err := dec.newMaterial(obj.materials[0])
 		if err != nil {
 			return nil, err
 		}
@@ -185,16 +219,7 @@ func (dec *Decoder) NewMesh(obj *Object) (*graphic.Mesh, error) {
 	mesh := graphic.NewMesh(geom, nil)
 	for idx := 0; idx < geom.GroupCount(); idx++ {
 		group := geom.GroupAt(idx)
-		matName := obj.materials[group.Matindex]
-		matDesc := dec.Materials[matName]
-		// Creates material
-		matGroup := material.NewPhong(&matDesc.Diffuse)
-		ambientColor := matGroup.AmbientColor()
-		matGroup.SetAmbientColor(ambientColor.Multiply(&matDesc.Ambient))
-		matGroup.SetSpecularColor(&matDesc.Specular)
-		matGroup.SetShininess(matDesc.Shininess)
-		// Loads material textures if specified
-		err = dec.loadTex(&matGroup.Material, matDesc)
+		matGroup, err := dec.newMaterial(obj.materials[group.Matindex])
 		if err != nil {
 			return nil, err
 		}
@@ -203,7 +228,37 @@ func (dec *Decoder) NewMesh(obj *Object) (*graphic.Mesh, error) {
 	return mesh, nil
 }
 
-// NewGeometry generates and returns a geometry from the specified object
+// newMaterial creates and returns a material from
+// the decoded material with the specified name
+func (dec *Decoder) newMaterial(matName string) (*material.Phong, error) {
+
+	matDesc := dec.Materials[matName]
+	mat := material.NewPhong(&matDesc.Diffuse)
+	ambientColor := mat.AmbientColor()
+	mat.SetAmbientColor(ambientColor.Multiply(&matDesc.Ambient))
+	mat.SetSpecularColor(&matDesc.Specular)
+	mat.SetShininess(matDesc.Shininess)
+	// Loads material textures if specified
+	err := dec.loadTex(&mat.Material, matDesc)
+	if err != nil {
+		return nil, err
+	}
+	return mat, nil
+}
+
+// smoothVertex identifies the vertices of the faces of a smoothing group
+// which share a vertex position and so have the same normal
+type smoothVertex struct {
+	position int // Index of the vertex position
+	group    int // Smoothing group number
+}
+
+// NewGeometry generates and returns a geometry from the specified object.
+// The geometry has a group with the faces of each material used by the
+// object. The normals of the faces which do not specify them are generated:
+// smooth faces use the average of the normals of the faces of the same
+// smoothing group which share the vertex and the other faces use their
+// own flat normal.
 func (dec *Decoder) NewGeometry(obj *Object) (*geometry.Geometry, error) {
 
 	geom := geometry.NewGeometry()
@@ -214,20 +269,47 @@ func (dec *Decoder) NewGeometry(obj *Object) (*geometry.Geometry, error) {
 	uvs := math32.NewArrayF32(0, 0)
 	indices := math32.NewArrayU32(0, 0)
 
+	// Computes the normals of the faces and sums them
+	// for the vertices of each smoothing group
+	faceNormals := make([]math32.Vector3, len(obj.Faces))
+	smoothNormals := make(map[smoothVertex]math32.Vector3)
+	for fi := range obj.Faces {
+		face := &obj.Faces[fi]
+		dec.faceNormal(face, &faceNormals[fi])
+		if face.SmoothGroup == 0 {
+			continue
+		}
+		for _, vi := range face.Vertices {
+			key := smoothVertex{vi, face.SmoothGroup}
+			normal := smoothNormals[key]
+			normal.Add(&faceNormals[fi])
+			smoothNormals[key] = normal
+		}
+	}
+	for fi := range faceNormals {
+		faceNormals[fi].Normalize()
+	}
+
 	// copy all vertex info from the decoded Object, face and index to the geometry
-	copyVertex := func(face *Face, idx int) {
+	copyVertex := func(fi int, idx int) {
 		var vec3 math32.Vector3
 		var vec2 math32.Vector2
 
+		face := &obj.Faces[fi]
 		pos := positions.Size() / 3
 		// Copy vertex position and append to geometry
 		dec.Vertices.GetVector3(3*face.Vertices[idx], &vec3)
 		positions.AppendVector3(&vec3)
-		// Copy vertex normal and append to geometry
+		// Copy vertex normal or use the generated one and append to geometry
 		if face.Normals[idx] != invINDEX {
 			dec.Normals.GetVector3(3*face.Normals[idx], &vec3)
-			normals.AppendVector3(&vec3)
+		} else if face.SmoothGroup != 0 {
+			vec3 = smoothNormals[smoothVertex{face.Vertices[idx], face.SmoothGroup}]
+			vec3.Normalize()
+		} else {
+			vec3 = faceNormals[fi]
 		}
+		normals.AppendVector3(&vec3)
 		// Copy vertex uv and append to geometry
 		if face.Uvs[idx] != invINDEX {
 			dec.Uvs.GetVector2(2*face.Uvs[idx], &vec2)
@@ -236,22 +318,22 @@ func (dec *Decoder) NewGeometry(obj *Object) (*geometry.Geometry, error) {
 		indices.Append(uint32(pos))
 	}
 
-	var group *geometry.Group
-	matName := ""
-	matIndex := 0
-	for _, face := range obj.Faces {
-		// If face material changed, starts a new group
-		if face.Material != matName {
-			group = geom.AddGroup(indices.Size(), 0, matIndex)
-			matName = face.Material
-			matIndex++
-		}
-		// Copy face vertices to geometry
-		for idx := 1; idx < len(face.Vertices)-1; idx++ {
-			copyVertex(&face, 0)
-			copyVertex(&face, idx)
-			copyVertex(&face, idx+1)
-			group.Count += 3
+	// Copy the faces of each material to a geometry group
+	for matIndex, matName := range obj.materials {
+		group := geom.AddGroup(indices.Size(), 0, matIndex)
+		group.Matid = matName
+		for fi := range obj.Faces {
+			face := &obj.Faces[fi]
+			if face.Material != matName {
+				continue
+			}
+			// Copy face vertices to geometry
+			for idx := 1; idx < len(face.Vertices)-1; idx++ {
+				copyVertex(fi, 0)
+				copyVertex(fi, idx)
+				copyVertex(fi, idx+1)
+				group.Count += 3
+			}
 		}
 	}
 
@@ -263,6 +345,23 @@ func (dec *Decoder) NewGeometry(obj *Object) (*geometry.Geometry, error) {
 	return geom, nil
 }
 
+// faceNormal sets the specified vector with the normal of the specified face
+// computed with the Newell method, which works for non planar polygons.
+// The normal is not normalized and its length is twice the face area,
+// so larger faces have more weight in the smooth normals.
+func (dec *Decoder) faceNormal(face *Face, normal *math32.Vector3) {
+
+	var curr, next math32.Vector3
+	normal.Set(0, 0, 0)
+	for i := range face.Vertices {
+		dec.Vertices.GetVector3(3*face.Vertices[i], &curr)
+		dec.Vertices.GetVector3(3*face.Vertices[(i+1)%len(face.Vertices)], &next)
+		normal.X += (curr.Y - next.Y) * (curr.Z + next.Z)
+		normal.Y += (curr.Z - next.Z) * (curr.X + next.X)
+		normal.Z += (curr.X - next.X) * (curr.Y + next.Y)
+	}
+}
+
 // loadTex loads textures described in the material descriptor into the
 // specified material
 func (dec *Decoder) loadTex(mat *material.Material, desc *Material) error {
@@ -448,23 +547,35 @@ func (dec *Decoder) parseTex(fields []string) error {
 // f v1[/vt1][/vn1] v2[/vt2][/vn2] v3[/vt3][/vn3] ...
 func (dec *Decoder) parseFace(fields []string) error {
 
-	// If current object has no material, appends last material if defined
-	if len(dec.objCurrent.materials) == 0 && dec.matCurrent != nil {
-		dec.objCurrent.materials = append(dec.objCurrent.materials, dec.matCurrent.Name)
-	}
-
 	if len(fields) < 3 {
 		return dec.formatError("Face line with less 3 fields")
 	}
 	if dec.matCurrent == nil {
 		return dec.formatError("No material defined")
 	}
+	if dec.objCurrent == nil {
+		return dec.formatError("No object defined")
+	}
+
+	// Appends the current material to the materials
+	// of the current object if not used before
+	used := false
+	for _, name := range dec.objCurrent.materials {
+		if name == dec.matCurrent.Name {
+			used = true
+			break
+		}
+	}
+	if !used {
+		dec.objCurrent.materials = append(dec.objCurrent.materials, dec.matCurrent.Name)
+	}
 	var face Face
 	face.Vertices = make([]int, len(fields))
 	face.Uvs = make([]int, len(fields))
 	face.Normals = make([]int, len(fields))
 	face.Material = dec.matCurrent.Name
-	face.Smooth = dec.smoothCurrent
+	face.Smooth = dec.smoothCurrent != 0
+	face.SmoothGroup = dec.smoothCurrent
 
 	for pos, f := range fields {
 
@@ -558,29 +669,34 @@ func (dec *Decoder) parseUsemtl(fields []string) error {
 		mat.Name = name
 		dec.Materials[name] = mat
 	}
-	dec.objCurrent.materials = append(dec.objCurrent.materials, name)
 	// Set this as the current material
 	dec.matCurrent = mat
 	return nil
 }
 
-// parseSmooth parses a "s" decription line:
-// s <0|1>
+// parseSmooth parses a "s" decription line with the smoothing group number
+// used by the next faces, where 0 or off disables smoothing:
+// s <group|on|off>
 func (dec *Decoder) parseSmooth(fields []string) error {
 
 	if len(fields) < 1 {
 		return dec.formatError("'s' with no fields")
 	}
 
-	if fields[0] == "0" || fields[0] == "off" {
-		dec.smoothCurrent = false
+	if fields[0] == "off" {
+		dec.smoothCurrent = 0
 		return nil
 	}
-	if fields[0] == "1" || fields[0] == "on" {
-		dec.smoothCurrent = true
+	if fields[0] == "on" {
+		dec.smoothCurrent = 1
 		return nil
 	}
-	return dec.formatError("'s' with invalid value")
+	val, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return dec.formatError("'s' with invalid value")
+	}
+	dec.smoothCurrent = int(val)
+	return nil
 }
 
 /******************************************************************************
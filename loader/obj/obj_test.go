@@ -0,0 +1,151 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package obj
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+// twoMaterials has a red face, a blue face and another red face
+const twoMaterials = `
+mtllib two.mtl
+o tiles
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 2 0 0
+v 2 1 0
+usemtl red
+f 1 2 3
+usemtl blue
+f 2 5 6 3
+usemtl red
+f 1 3 4
+`
+
+const twoMaterialsMtl = `
+newmtl red
+Ka 1 1 1
+Kd 1 0 0
+newmtl blue
+Ka 1 1 1
+Kd 0 0 1
+`
+
+func TestDecodeTwoMaterials(t *testing.T) {
+
+	dec, err := DecodeReader(strings.NewReader(twoMaterials), strings.NewReader(twoMaterialsMtl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dec.Objects) != 1 {
+		t.Fatalf("%d objects, want 1", len(dec.Objects))
+	}
+	mesh, err := dec.NewMesh(&dec.Objects[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The faces of each material are in one group
+	geom := mesh.GetGeometry()
+	if geom.GroupCount() != 2 {
+		t.Fatalf("%d geometry groups, want 2", geom.GroupCount())
+	}
+	cases := []struct {
+		matid string
+		start int
+		count int
+		color math32.Color
+	}{
+		{"red", 0, 6, math32.Color{1, 0, 0}},
+		{"blue", 6, 6, math32.Color{0, 0, 1}},
+	}
+	if len(mesh.Materials()) != len(cases) {
+		t.Fatalf("%d materials, want %d", len(mesh.Materials()), len(cases))
+	}
+	for i, c := range cases {
+		group := geom.GroupAt(i)
+		if group.Matid != c.matid || group.Start != c.start || group.Count != c.count {
+			t.Errorf("group %d = %s %d %d, want %s %d %d", i, group.Matid, group.Start, group.Count, c.matid, c.start, c.count)
+		}
+		// The ambient reflectivity is 1 so the ambient color is the diffuse color
+		for _, vpos := range []int{c.start, c.start + c.count - 1} {
+			mat, ok := mesh.GetMaterial(vpos).(*material.Phong)
+			if !ok {
+				t.Errorf("%s: material of vertex %d is %T, want *material.Phong", c.matid, vpos, mesh.GetMaterial(vpos))
+				continue
+			}
+			if got := mat.AmbientColor(); got != c.color {
+				t.Errorf("%s: color of vertex %d = %v, want %v", c.matid, vpos, got, c.color)
+			}
+		}
+	}
+}
+
+// folded has two faces folded along the edge between vertices 2 and 3,
+// first in a smoothing group and then flat
+const folded = `
+o folded
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 2 0 1
+usemtl grey
+s 1
+f 1 2 3
+f 2 4 3
+s off
+f 1 2 3
+f 2 4 3
+`
+
+func TestDecodeSmoothingGroups(t *testing.T) {
+
+	dec, err := DecodeReader(strings.NewReader(folded), strings.NewReader("newmtl grey\nKd 0.5 0.5 0.5\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	geom, err := dec.NewGeometry(&dec.Objects[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	normals := geom.VBO("VertexNormal").Buffer()
+	normal := func(vpos int) math32.Vector3 {
+		var n math32.Vector3
+		normals.GetVector3(3*vpos, &n)
+		return n
+	}
+
+	// The smooth faces share the normal of the shared vertices, weighted
+	// by the face areas, and the flat faces keep their own normals
+	flat1 := math32.Vector3{0, 0, 1}
+	flat2 := math32.Vector3{-1, 0, 1}
+	flat2.Normalize()
+	smooth := math32.Vector3{-1, 0, 2}
+	smooth.Normalize()
+	cases := []struct {
+		name string
+		vpos int
+		want math32.Vector3
+	}{
+		{"smooth shared vertex", 1, smooth},
+		{"smooth shared vertex of second face", 3, smooth},
+		{"smooth unshared vertex", 0, flat1},
+		{"smooth unshared vertex of second face", 4, flat2},
+		{"flat shared vertex", 7, flat1},
+		{"flat shared vertex of second face", 9, flat2},
+	}
+	for _, c := range cases {
+		n := normal(c.vpos)
+		if n.DistanceTo(&c.want) > 1e-5 {
+			t.Errorf("%s: normal = %v, want %v", c.name, n, c.want)
+		}
+	}
+}
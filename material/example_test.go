@@ -0,0 +1,180 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package material_test
+
+import (
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
+	"github.com/g3n/engine/texture"
+	"github.com/g3n/engine/window"
+)
+
+// newExampleScene creates the window, renderer, scene and camera of the examples
+func newExampleScene(title string) (window.IWindow, *gls.GLS, *renderer.Renderer, *core.Node, *camera.Perspective) {
+
+	win, err := window.New("glfw", 800, 600, title, false)
+	if err != nil {
+		panic(err)
+	}
+	gs, err := gls.New()
+	if err != nil {
+		panic(err)
+	}
+	rend := renderer.NewRenderer(gs)
+	err = rend.AddDefaultShaders()
+	if err != nil {
+		panic(err)
+	}
+	scene := core.NewNode()
+	scene.Add(light.NewAmbient(math32.NewColor(1, 1, 1), 0.5))
+	dir := light.NewDirectional(math32.NewColor(1, 1, 1), 1)
+	dir.SetPosition(1, 1, 1)
+	scene.Add(dir)
+	cam := camera.NewPerspective(60, 800.0/600.0, 0.1, 100)
+	cam.SetPosition(0, 0, 5)
+	return win, gs, rend, scene, cam
+}
+
+// This example renders a rotating torus only inside a circular portal.
+// The portal writes 1 to the stencil buffer and the torus is only rendered
+// where the stencil value is 1. The portal has a lower render order, so it
+// is rendered before the torus, and it does not write to the depth buffer,
+// so it does not hide the parts of the torus behind it.
+func ExampleMaterial_SetStencil() {
+
+	win, gs, rend, scene, cam := newExampleScene("Stencil mask")
+
+	portalMat := material.NewStandard(math32.NewColor(0.1, 0.1, 0.2))
+	portalMat.SetStencil(true)
+	portalMat.SetStencilFunc(gls.ALWAYS, 1, 0xFF)
+	portalMat.SetStencilOp(gls.KEEP, gls.KEEP, gls.REPLACE)
+	portalMat.SetDepthMask(false)
+	portalMat.SetRenderOrder(-1)
+	portal := graphic.NewMesh(geometry.NewCircle(1, 64, 0, 2*math32.Pi), portalMat)
+	scene.Add(portal)
+
+	torusMat := material.NewStandard(math32.NewColor(1, 0.5, 0))
+	torusMat.SetStencil(true)
+	torusMat.SetStencilFunc(gls.EQUAL, 1, 0xFF)
+	torusMat.SetStencilMask(0)
+	torus := graphic.NewMesh(geometry.NewTorus(1, 0.4, 16, 64, 2*math32.Pi), torusMat)
+	scene.Add(torus)
+
+	for !win.ShouldClose() {
+		torus.AddRotationY(0.01)
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT | gls.STENCIL_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
+
+// This example renders a tree made of many intersecting quads with a leaves
+// texture with transparent borders. With alpha to coverage the quads do not
+// need to be sorted, as they are rendered without blending and write to the
+// depth buffer, so the leaves do not flicker when the camera moves.
+// Press the space key to compare with alpha blending.
+func ExampleMaterial_SetAlphaToCoverage() {
+
+	win, gs, rend, scene, cam := newExampleScene("Alpha to coverage")
+
+	leavesTex, err := texture.NewTexture2DFromImage("leaves.png")
+	if err != nil {
+		panic(err)
+	}
+	leaves := material.NewStandard(math32.NewColor(1, 1, 1))
+	leaves.AddTexture(leavesTex)
+	leaves.SetSide(material.SideDouble)
+	leaves.SetBlending(material.BlendingNone)
+	leaves.SetAlphaToCoverage(true)
+
+	tree := core.NewNode()
+	quad := geometry.NewPlane(1, 1, 1, 1)
+	for i := 0; i < 200; i++ {
+		leaf := graphic.NewMesh(quad, leaves)
+		leaf.SetPosition(math32.Sin(float32(i)*0.7), math32.Cos(float32(i)*1.3), math32.Sin(float32(i)*2.1))
+		leaf.SetRotation(float32(i)*0.5, float32(i)*0.9, 0)
+		tree.Add(leaf)
+	}
+	scene.Add(tree)
+
+	win.Subscribe(window.OnKeyDown, func(evname string, ev interface{}) {
+		if ev.(*window.KeyEvent).Keycode != window.KeySpace {
+			return
+		}
+		if leaves.AlphaToCoverage() {
+			leaves.SetAlphaToCoverage(false)
+			leaves.SetBlending(material.BlendingNormal)
+			leaves.SetTransparent(true)
+		} else {
+			leaves.SetAlphaToCoverage(true)
+			leaves.SetBlending(material.BlendingNone)
+			leaves.SetTransparent(false)
+		}
+	})
+
+	for !win.ShouldClose() {
+		tree.AddRotationY(0.005)
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
+
+// This example renders two overlapping translucent quads in front of an
+// opaque box. The transparent quads are drawn after the box from back to
+// front by their distance to the camera, so each quad is blended over the
+// graphics behind it from any point of view as the camera orbits.
+func ExampleMaterial_SetTransparent() {
+
+	win, gs, rend, scene, cam := newExampleScene("Transparent quads")
+
+	box := graphic.NewMesh(geometry.NewBox(1, 1, 1, 1, 1, 1), material.NewStandard(math32.NewColor(0, 1, 0)))
+	box.SetPosition(0, 0, -2)
+	scene.Add(box)
+
+	quad := geometry.NewPlane(2, 2, 1, 1)
+	red := material.NewStandard(math32.NewColor(1, 0, 0))
+	red.SetOpacity(0.5)
+	red.SetTransparent(true)
+	red.SetSide(material.SideDouble)
+	scene.Add(graphic.NewMesh(quad, red))
+
+	blue := material.NewStandard(math32.NewColor(0, 0, 1))
+	blue.SetOpacity(0.5)
+	blue.SetTransparent(true)
+	blue.SetSide(material.SideDouble)
+	quad2 := graphic.NewMesh(quad, blue)
+	quad2.SetPosition(0.5, 0.5, -1)
+	scene.Add(quad2)
+
+	var angle float32
+	for !win.ShouldClose() {
+		angle += 0.005
+		cam.SetPosition(5*math32.Sin(angle), 1, 5*math32.Cos(angle))
+		cam.LookAt(&math32.Vector3{Z: -1})
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
@@ -41,6 +41,7 @@ const (
 	UseLightDirectional UseLights = 0x02
 	UseLightPoint       UseLights = 0x04
 	UseLightSpot        UseLights = 0x08
+	UseLightRectArea    UseLights = 0x10
 	UseLightAll         UseLights = 0xFF
 )
 
@@ -51,9 +52,7 @@ type IMaterial interface {
 	Dispose()
 }
 
-//
 // Base Material
-//
 type Material struct {
 	refcount         int                  // Current number of references
 	shader           string               // Shader name
@@ -74,7 +73,20 @@ type Material struct {
 	lineWidth        float32              // line width for lines and mesh wireframe
 	polyOffsetFactor float32              // polygon offset factor
 	polyOffsetUnits  float32              // polygon offset units
+	stencilTest      bool                 // Enable stencil buffer test
+	stencilFunc      uint32               // stencil test function
+	stencilRef       int32                // stencil test reference value
+	stencilFuncMask  uint32               // mask applied to the reference and stencil values by the test
+	stencilMask      uint32               // mask of the stencil bits which can be written
+	stencilFail      uint32               // stencil action when the stencil test fails
+	stencilZfail     uint32               // stencil action when the stencil test passes and the depth test fails
+	stencilZpass     uint32               // stencil action when both the stencil and depth tests pass
+	alphaToCoverage  bool                 // Enable alpha to coverage with multisampling
+	transparent      bool                 // Rendered after the opaque materials without depth writes
+	renderOrder      int                  // Order of rendering relative to other materials
 	textures         []*texture.Texture2D // List of textures
+	texArrays        []*texture.TexArray  // List of texture arrays
+	cubemaps         []*texture.Cubemap   // List of cube map textures
 }
 
 // NewMaterial returns a pointer to a new material
@@ -97,7 +109,18 @@ func (mat *Material) Init() *Material {
 	mat.lineWidth = 1.0
 	mat.polyOffsetFactor = 0
 	mat.polyOffsetUnits = 0
+	mat.stencilTest = false
+	mat.stencilFunc = gls.ALWAYS
+	mat.stencilRef = 0
+	mat.stencilFuncMask = 0xFF
+	mat.stencilMask = 0xFF
+	mat.stencilFail = gls.KEEP
+	mat.stencilZfail = gls.KEEP
+	mat.stencilZpass = gls.KEEP
+	mat.alphaToCoverage = false
 	mat.textures = make([]*texture.Texture2D, 0)
+	mat.texArrays = make([]*texture.TexArray, 0)
+	mat.cubemaps = make([]*texture.Cubemap, 0)
 
 	return mat
 }
@@ -130,6 +153,12 @@ func (mat *Material) Dispose() {
 	for i := 0; i < len(mat.textures); i++ {
 		mat.textures[i].Dispose()
 	}
+	for i := 0; i < len(mat.texArrays); i++ {
+		mat.texArrays[i].Dispose()
+	}
+	for i := 0; i < len(mat.cubemaps); i++ {
+		mat.cubemaps[i].Dispose()
+	}
 	mat.Init()
 }
 
@@ -216,6 +245,143 @@ func (mat *Material) SetPolygonOffset(factor, units float32) {
 	mat.polyOffsetUnits = units
 }
 
+// SetStencil sets if the stencil test is enabled when rendering with this material.
+// The window must have a stencil buffer, which should be cleared with
+// gls.STENCIL_BUFFER_BIT at the start of each frame. The default is disabled.
+// For example, to render an object only where a mask object was rendered,
+// the mask material could use:
+//
+//	mask.SetStencil(true)
+//	mask.SetStencilFunc(gls.ALWAYS, 1, 0xFF)
+//	mask.SetStencilOp(gls.KEEP, gls.KEEP, gls.REPLACE)
+//
+// and the masked object material:
+//
+//	masked.SetStencil(true)
+//	masked.SetStencilFunc(gls.EQUAL, 1, 0xFF)
+//	masked.SetStencilMask(0)
+//
+// The mask object must be rendered first, which can be ensured by
+// setting a lower render order to the mask material with SetRenderOrder.
+func (mat *Material) SetStencil(state bool) {
+
+	mat.stencilTest = state
+}
+
+// Stencil returns if the stencil test is enabled for this material
+func (mat *Material) Stencil() bool {
+
+	return mat.stencilTest
+}
+
+// SetStencilFunc sets the stencil test function (gls.NEVER, gls.LESS, gls.LEQUAL,
+// gls.GREATER, gls.GEQUAL, gls.EQUAL, gls.NOTEQUAL, gls.ALWAYS), the reference value
+// and the mask applied to both the reference and the stored stencil value.
+// The default is gls.ALWAYS with reference 0 and mask 0xFF.
+func (mat *Material) SetStencilFunc(fn uint32, ref int32, mask uint32) {
+
+	mat.stencilFunc = fn
+	mat.stencilRef = ref
+	mat.stencilFuncMask = mask
+}
+
+// SetStencilOp sets the actions (gls.KEEP, gls.ZERO, gls.REPLACE, gls.INCR, gls.INCR_WRAP,
+// gls.DECR, gls.DECR_WRAP, gls.INVERT) when the stencil test fails, when the stencil
+// test passes and the depth test fails and when both tests pass. The default is gls.KEEP.
+func (mat *Material) SetStencilOp(fail, zfail, zpass uint32) {
+
+	mat.stencilFail = fail
+	mat.stencilZfail = zfail
+	mat.stencilZpass = zpass
+}
+
+// SetStencilMask sets the mask of the stencil bits which can be written
+// when rendering with this material. The default is 0xFF.
+func (mat *Material) SetStencilMask(mask uint32) {
+
+	mat.stencilMask = mask
+}
+
+// SetAlphaToCoverage sets if the alpha of the fragments rendered with this
+// material is converted to a coverage mask of the multisampled pixels instead
+// of being used only for blending. The window must have been created with
+// multisampling, otherwise this setting has no effect. The default is disabled.
+// Alpha to coverage is normally used with blending disabled and depth writes
+// enabled, so overlapping transparent objects such as foliage do not need to be
+// sorted to be rendered correctly. If blending is also enabled, the alpha is
+// applied twice, which makes the edges softer and reintroduces sorting errors.
+// For example, for a material with a leaves texture with transparent borders:
+//
+//	leaves := material.NewStandard(math32.NewColor(1, 1, 1))
+//	leaves.AddTexture(leavesTex)
+//	leaves.SetSide(material.SideDouble)
+//	leaves.SetBlending(material.BlendingNone)
+//	leaves.SetAlphaToCoverage(true)
+func (mat *Material) SetAlphaToCoverage(state bool) {
+
+	mat.alphaToCoverage = state
+}
+
+// AlphaToCoverage returns if alpha to coverage is enabled for this material
+func (mat *Material) AlphaToCoverage() bool {
+
+	return mat.alphaToCoverage
+}
+
+// SetTransparent sets if this material is transparent (default = false).
+// The renderer draws the opaque graphics from front to back and after them
+// the transparent graphics from back to front by their distance to the camera,
+// so they are blended over the graphics behind them. Transparent materials
+// do not write into the depth buffer.
+// For example, for two overlapping translucent quads which are blended
+// correctly from any point of view:
+//
+//	red := material.NewStandard(math32.NewColor(1, 0, 0))
+//	red.SetOpacity(0.5)
+//	red.SetTransparent(true)
+//	red.SetSide(material.SideDouble)
+//	quad1 := graphic.NewMesh(geometry.NewPlane(2, 2, 1, 1), red)
+//	scene.Add(quad1)
+//
+//	blue := material.NewStandard(math32.NewColor(0, 0, 1))
+//	blue.SetOpacity(0.5)
+//	blue.SetTransparent(true)
+//	blue.SetSide(material.SideDouble)
+//	quad2 := graphic.NewMesh(geometry.NewPlane(2, 2, 1, 1), blue)
+//	quad2.SetPosition(0.5, 0.5, -1)
+//	scene.Add(quad2)
+func (mat *Material) SetTransparent(state bool) {
+
+	mat.transparent = state
+}
+
+// Transparent returns if this material is transparent
+func (mat *Material) Transparent() bool {
+
+	return mat.transparent
+}
+
+// SetRenderOrder sets the order of rendering of the graphics with this material
+// relative to the graphics with other materials (default = 0). Graphics with
+// lower orders are rendered first and the graphics with the same order are
+// sorted as opaque or transparent by their distance to the camera.
+// The GUI panels are always rendered after the other graphics in their
+// scene order and are not affected by the render order.
+// For example, to draw an outline over all the other graphics:
+//
+//	outline.SetDepthTest(false)
+//	outline.SetRenderOrder(1)
+func (mat *Material) SetRenderOrder(order int) {
+
+	mat.renderOrder = order
+}
+
+// RenderOrder returns the current render order of this material
+func (mat *Material) RenderOrder() int {
+
+	return mat.renderOrder
+}
+
 func (mat *Material) RenderSetup(gs *gls.GLS) {
 
 	// Sets triangle side view mode
@@ -236,7 +402,7 @@ func (mat *Material) RenderSetup(gs *gls.GLS) {
 	} else {
 		gs.Disable(gls.DEPTH_TEST)
 	}
-	gs.DepthMask(mat.depthMask)
+	gs.DepthMask(mat.depthMask && !mat.transparent)
 	gs.DepthFunc(mat.depthFunc)
 
 	if mat.wireframe {
@@ -245,6 +411,25 @@ func (mat *Material) RenderSetup(gs *gls.GLS) {
 		gs.PolygonMode(gls.FRONT_AND_BACK, gls.FILL)
 	}
 
+	// Sets the stencil test state. When disabled all the stencil bits can be
+	// written so the stencil buffer can be cleared.
+	if mat.stencilTest {
+		gs.Enable(gls.STENCIL_TEST)
+		gs.StencilFunc(mat.stencilFunc, mat.stencilRef, mat.stencilFuncMask)
+		gs.StencilOp(mat.stencilFail, mat.stencilZfail, mat.stencilZpass)
+		gs.StencilMask(mat.stencilMask)
+	} else {
+		gs.Disable(gls.STENCIL_TEST)
+		gs.StencilMask(0xFF)
+	}
+
+	// Sets the alpha to coverage state
+	if mat.alphaToCoverage {
+		gs.Enable(gls.SAMPLE_ALPHA_TO_COVERAGE)
+	} else {
+		gs.Disable(gls.SAMPLE_ALPHA_TO_COVERAGE)
+	}
+
 	// Set polygon offset if requested
 	gs.PolygonOffset(mat.polyOffsetFactor, mat.polyOffsetUnits)
 
@@ -285,6 +470,16 @@ func (mat *Material) RenderSetup(gs *gls.GLS) {
 	for idx, tex := range mat.textures {
 		tex.RenderSetup(gs, idx)
 	}
+
+	// Render texture arrays using the texture units after the textures
+	for idx, tex := range mat.texArrays {
+		tex.RenderSetup(gs, len(mat.textures)+idx, idx)
+	}
+
+	// Render cube maps using the texture units after the texture arrays
+	for idx, tex := range mat.cubemaps {
+		tex.RenderSetup(gs, len(mat.textures)+len(mat.texArrays)+idx, idx)
+	}
 }
 
 // AddTexture adds the specified Texture2d to the material
@@ -318,8 +513,90 @@ func (mat *Material) HasTexture(tex *texture.Texture2D) bool {
 	return false
 }
 
+// Transfer transfers the data of the textures of this material to OpenGL
+// if necessary, without setting the material state and uniforms, so the
+// textures of loaded models can be transferred before they are rendered.
+func (mat *Material) Transfer(gs *gls.GLS) {
+
+	for idx, tex := range mat.textures {
+		tex.Bind(gs, idx)
+	}
+}
+
 // TextureCount returns the current number of textures
 func (mat *Material) TextureCount() int {
 
 	return len(mat.textures)
 }
+
+// AddTexArray adds the specified texture array to the material
+func (mat *Material) AddTexArray(tex *texture.TexArray) {
+
+	mat.texArrays = append(mat.texArrays, tex)
+}
+
+// RemoveTexArray removes the specified texture array from the material
+func (mat *Material) RemoveTexArray(tex *texture.TexArray) {
+
+	for pos, curr := range mat.texArrays {
+		if curr == tex {
+			copy(mat.texArrays[pos:], mat.texArrays[pos+1:])
+			mat.texArrays[len(mat.texArrays)-1] = nil
+			mat.texArrays = mat.texArrays[:len(mat.texArrays)-1]
+			break
+		}
+	}
+}
+
+// HasTexArray checks if the material contains the specified texture array
+func (mat *Material) HasTexArray(tex *texture.TexArray) bool {
+
+	for _, curr := range mat.texArrays {
+		if curr == tex {
+			return true
+		}
+	}
+	return false
+}
+
+// TexArrayCount returns the current number of texture arrays
+func (mat *Material) TexArrayCount() int {
+
+	return len(mat.texArrays)
+}
+
+// AddCubemap adds the specified cube map texture to the material
+func (mat *Material) AddCubemap(tex *texture.Cubemap) {
+
+	mat.cubemaps = append(mat.cubemaps, tex)
+}
+
+// RemoveCubemap removes the specified cube map texture from the material
+func (mat *Material) RemoveCubemap(tex *texture.Cubemap) {
+
+	for pos, curr := range mat.cubemaps {
+		if curr == tex {
+			copy(mat.cubemaps[pos:], mat.cubemaps[pos+1:])
+			mat.cubemaps[len(mat.cubemaps)-1] = nil
+			mat.cubemaps = mat.cubemaps[:len(mat.cubemaps)-1]
+			break
+		}
+	}
+}
+
+// HasCubemap checks if the material contains the specified cube map texture
+func (mat *Material) HasCubemap(tex *texture.Cubemap) bool {
+
+	for _, curr := range mat.cubemaps {
+		if curr == tex {
+			return true
+		}
+	}
+	return false
+}
+
+// CubemapCount returns the current number of cube map textures
+func (mat *Material) CubemapCount() int {
+
+	return len(mat.cubemaps)
+}
@@ -0,0 +1,280 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package material
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/texture"
+)
+
+// Physical material implements the metallic-roughness physically based
+// rendering model used by glTF, with Cook-Torrance specular reflection
+// and optional image based lighting from an environment map.
+// The colors of the base color, emissive and environment maps are
+// considered to be in the sRGB color space and the lighting is
+// calculated in linear space.
+type Physical struct {
+	Material                              // Embedded material
+	uni      *gls.Uniform3fv              // Uniform array of 3 floats with material properties
+	uniEnv   gls.UniformMatrix3f          // Rotation from camera to world coordinates for the environment map
+	maps     [physMaps]*texture.Texture2D // Textures of each map slot
+	envCube  *texture.Cubemap             // Cube map environment texture
+}
+
+// IViewMaterial is the interface for materials which need the
+// current camera view matrix before being rendered
+type IViewMaterial interface {
+	IMaterial
+	SetViewMatrix(view *math32.Matrix4)
+}
+
+const (
+	vBaseColor        = 0                    // index for base color in uniform array
+	vPhysEmissive     = 1                    // index for emissive color in uniform array
+	pBaseAlpha        = 2 * 3                // position for base color alpha in uniform array
+	pMetallic         = pBaseAlpha + 1       // position for metallic factor in uniform array
+	pRoughness        = pMetallic + 1        // position for roughness factor in uniform array
+	pNormalScale      = pRoughness + 1       // position for normal map scale in uniform array
+	pOcclusion        = pNormalScale + 1     // position for occlusion strength in uniform array
+	pEnvIntensity     = pOcclusion + 1       // position for environment map intensity in uniform array
+	pMapIndex         = pEnvIntensity + 1    // position of the texture index of the first map
+	pEnvCubemap       = pMapIndex + physMaps // position of the index of the environment cube map
+	pEnvCubemapHDR    = pEnvCubemap + 1      // position of the environment cube map linear colors flag
+	physUniSize       = 7                    // total count of groups 3 floats in uniform
+	physMapBaseColor  = 0                    // base color map slot
+	physMapMetalRough = 1                    // metallic-roughness map slot
+	physMapNormal     = 2                    // normal map slot
+	physMapOcclusion  = 3                    // occlusion map slot
+	physMapEmissive   = 4                    // emissive map slot
+	physMapEnv        = 5                    // environment map slot
+	physMaps          = 6                    // number of map slots
+)
+
+// NewPhysical creates and returns a pointer to a new physical material
+// with white base color, full metallic and roughness factors and no maps,
+// which are the glTF defaults.
+func NewPhysical() *Physical {
+
+	pm := new(Physical)
+	pm.Material.Init()
+	pm.SetShader("shaderPhysical")
+
+	// Creates uniforms and set initial values
+	pm.uni = gls.NewUniform3fv("Physical", physUniSize)
+	pm.uni.Set(vBaseColor, 1, 1, 1)
+	pm.uni.Set(vPhysEmissive, 0, 0, 0)
+	pm.uni.SetPos(pBaseAlpha, 1)
+	pm.uni.SetPos(pMetallic, 1)
+	pm.uni.SetPos(pRoughness, 1)
+	pm.uni.SetPos(pNormalScale, 1)
+	pm.uni.SetPos(pOcclusion, 1)
+	pm.uni.SetPos(pEnvIntensity, 1)
+	pm.uniEnv.Init("PhysicalEnvRotation")
+	pm.uniEnv.SetMatrix3(math32.NewMatrix3())
+	return pm
+}
+
+// SetBaseColorFactor sets the base color and alpha of the material which
+// multiply the base color map. The default is opaque white.
+func (pm *Physical) SetBaseColorFactor(color *math32.Color4) {
+
+	pm.uni.Set(vBaseColor, color.R, color.G, color.B)
+	pm.uni.SetPos(pBaseAlpha, color.A)
+}
+
+// BaseColorFactor returns the material base color and alpha
+func (pm *Physical) BaseColorFactor() math32.Color4 {
+
+	c := pm.uni.GetColor(vBaseColor)
+	return math32.Color4{c.R, c.G, c.B, pm.uni.GetPos(pBaseAlpha)}
+}
+
+// SetMetallicFactor sets the metalness of the material from 0 (dielectric)
+// to 1 (metal) which multiplies the metallic map. The default is 1.
+func (pm *Physical) SetMetallicFactor(metallic float32) {
+
+	pm.uni.SetPos(pMetallic, math32.Clamp(metallic, 0, 1))
+}
+
+// MetallicFactor returns the material metalness
+func (pm *Physical) MetallicFactor() float32 {
+
+	return pm.uni.GetPos(pMetallic)
+}
+
+// SetRoughnessFactor sets the roughness of the material from 0 (smooth)
+// to 1 (rough) which multiplies the roughness map. The default is 1.
+func (pm *Physical) SetRoughnessFactor(roughness float32) {
+
+	pm.uni.SetPos(pRoughness, math32.Clamp(roughness, 0, 1))
+}
+
+// RoughnessFactor returns the material roughness
+func (pm *Physical) RoughnessFactor() float32 {
+
+	return pm.uni.GetPos(pRoughness)
+}
+
+// SetEmissiveFactor sets the emissive color of the material which
+// multiplies the emissive map. The default is {0,0,0}.
+func (pm *Physical) SetEmissiveFactor(color *math32.Color) {
+
+	pm.uni.SetColor(vPhysEmissive, color)
+}
+
+// EmissiveFactor returns the material emissive color
+func (pm *Physical) EmissiveFactor() math32.Color {
+
+	return pm.uni.GetColor(vPhysEmissive)
+}
+
+// SetNormalScale sets the scale of the X and Y components of the normals
+// sampled from the normal map. The default is 1.
+func (pm *Physical) SetNormalScale(scale float32) {
+
+	pm.uni.SetPos(pNormalScale, scale)
+}
+
+// SetOcclusionStrength sets how much of the occlusion map is applied
+// from 0 (none) to 1 (full). The default is 1.
+func (pm *Physical) SetOcclusionStrength(strength float32) {
+
+	pm.uni.SetPos(pOcclusion, math32.Clamp(strength, 0, 1))
+}
+
+// SetEnvIntensity sets the intensity of the light from the environment map.
+// The default is 1.
+func (pm *Physical) SetEnvIntensity(intensity float32) {
+
+	pm.uni.SetPos(pEnvIntensity, intensity)
+}
+
+// SetBaseColorMap sets the texture with the base color and alpha of the material.
+// A nil texture removes the current map.
+func (pm *Physical) SetBaseColorMap(tex *texture.Texture2D) {
+
+	pm.setMap(physMapBaseColor, tex)
+}
+
+// SetMetallicRoughnessMap sets the texture with the roughness in the green
+// channel and the metalness in the blue channel, as defined by glTF.
+// A nil texture removes the current map.
+func (pm *Physical) SetMetallicRoughnessMap(tex *texture.Texture2D) {
+
+	pm.setMap(physMapMetalRough, tex)
+}
+
+// SetNormalMap sets the texture with the normals in tangent space.
+// The tangent space is calculated from the texture coordinates,
+// so the geometry does not need to have tangents.
+// A nil texture removes the current map.
+func (pm *Physical) SetNormalMap(tex *texture.Texture2D) {
+
+	pm.setMap(physMapNormal, tex)
+}
+
+// SetOcclusionMap sets the texture with the ambient occlusion in the red channel.
+// The occlusion is applied to the ambient and environment lights.
+// A nil texture removes the current map.
+func (pm *Physical) SetOcclusionMap(tex *texture.Texture2D) {
+
+	pm.setMap(physMapOcclusion, tex)
+}
+
+// SetEmissiveMap sets the texture with the emissive color of the material.
+// A nil texture removes the current map.
+func (pm *Physical) SetEmissiveMap(tex *texture.Texture2D) {
+
+	pm.setMap(physMapEmissive, tex)
+}
+
+// SetEnvMap sets the equirectangular (latitude-longitude) texture with the
+// environment used for image based lighting. The specular reflections sample
+// the mipmap level corresponding to the roughness and the diffuse light samples
+// one of the smallest levels, so the texture min filter is set to trilinear
+// filtering and the S wrap mode to repeat.
+// A nil texture removes the current map.
+func (pm *Physical) SetEnvMap(tex *texture.Texture2D) {
+
+	if tex != nil {
+		tex.SetMinFilter(gls.LINEAR_MIPMAP_LINEAR)
+		tex.SetWrapS(gls.REPEAT)
+	}
+	pm.setMap(physMapEnv, tex)
+}
+
+// SetEnvCubemap sets the cube map texture with the environment used for image
+// based lighting instead of an equirectangular environment map. The colors of
+// cube maps with floating point data are considered to be linear.
+// A nil texture removes the current cube map.
+func (pm *Physical) SetEnvCubemap(tex *texture.Cubemap) {
+
+	if pm.envCube != nil {
+		pm.RemoveCubemap(pm.envCube)
+	}
+	pm.envCube = tex
+	if tex != nil {
+		pm.AddCubemap(tex)
+	}
+}
+
+// setMap sets the texture of the specified map slot
+// replacing the previous texture of the slot in the material
+func (pm *Physical) setMap(slot int, tex *texture.Texture2D) {
+
+	if pm.maps[slot] != nil {
+		pm.RemoveTexture(pm.maps[slot])
+	}
+	pm.maps[slot] = tex
+	if tex != nil {
+		pm.AddTexture(tex)
+	}
+}
+
+// SetViewMatrix satisfies the IViewMaterial interface.
+// It is called by the renderer to update the rotation of the environment map.
+func (pm *Physical) SetViewMatrix(view *math32.Matrix4) {
+
+	var rot math32.Matrix3
+	rot.GetInverse(view, false)
+	pm.uniEnv.SetMatrix3(&rot)
+}
+
+// RenderSetup is called by the engine before drawing the object
+// which uses this material
+func (pm *Physical) RenderSetup(gs *gls.GLS) {
+
+	// Sets the index of the texture of each map slot
+	// or -1 if the slot has no texture
+	for slot, tex := range pm.maps {
+		idx := -1
+		for i, curr := range pm.textures {
+			if tex != nil && curr == tex {
+				idx = i
+				break
+			}
+		}
+		pm.uni.SetPos(pMapIndex+slot, float32(idx))
+	}
+
+	idx := -1
+	hdr := float32(0)
+	for i, curr := range pm.cubemaps {
+		if pm.envCube != nil && curr == pm.envCube {
+			idx = i
+			if curr.HDR() {
+				hdr = 1
+			}
+			break
+		}
+	}
+	pm.uni.SetPos(pEnvCubemap, float32(idx))
+	pm.uni.SetPos(pEnvCubemapHDR, hdr)
+
+	pm.Material.RenderSetup(gs)
+	pm.uni.Transfer(gs)
+	pm.uniEnv.Transfer(gs)
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package material
+
+import (
+	"github.com/g3n/engine/texture"
+)
+
+// Skybox material renders a cube map on the inner faces of a cube centered
+// at the camera, sampled in the direction of each fragment. It is not affected
+// by lights, does not write to the depth buffer and its fragments are at the
+// maximum depth, so every other object is rendered in front of it.
+type Skybox struct {
+	Material                  // Embedded material
+	cubemap  *texture.Cubemap // Cube map texture
+}
+
+// NewSkybox creates and returns a pointer to a new skybox material
+// with the specified cube map texture
+func NewSkybox(cubemap *texture.Cubemap) *Skybox {
+
+	sm := new(Skybox)
+	sm.Material.Init()
+	sm.SetShader("shaderSkybox")
+	sm.SetShaderUnique(true)
+	sm.SetUseLights(UseLightNone)
+	sm.SetSide(SideBack)
+	sm.SetDepthMask(false)
+	sm.SetCubemap(cubemap)
+	return sm
+}
+
+// SetCubemap sets the cube map texture of this skybox material
+func (sm *Skybox) SetCubemap(cubemap *texture.Cubemap) {
+
+	if sm.cubemap != nil {
+		sm.RemoveCubemap(sm.cubemap)
+	}
+	sm.cubemap = cubemap
+	sm.AddCubemap(cubemap)
+}
+
+// Cubemap returns the cube map texture of this skybox material
+func (sm *Skybox) Cubemap() *texture.Cubemap {
+
+	return sm.cubemap
+}
@@ -18,15 +18,17 @@ type Standard struct {
 }
 
 const (
-	vAmbient   = 0              // index for Ambient color in uniform array
-	vDiffuse   = 1              // index for Diffuse color in uniform array
-	vSpecular  = 2              // index for Specular color in uniform array
-	vEmissive  = 3              // index for Emissive color in uniform array
-	pShininess = vEmissive * 4  // position for material shininess in uniform array
-	pOpacity   = pShininess + 1 // position for material opacity in uniform array
-	pSize      = pOpacity + 1   // position for material point size
-	pRotationZ = pSize + 1      // position for material point rotation
-	uniSize    = 6              // total count of groups 3 floats in uniform
+	vAmbient      = 0              // index for Ambient color in uniform array
+	vDiffuse      = 1              // index for Diffuse color in uniform array
+	vSpecular     = 2              // index for Specular color in uniform array
+	vEmissive     = 3              // index for Emissive color in uniform array
+	pShininess    = vEmissive * 4  // position for material shininess in uniform array
+	pOpacity      = pShininess + 1 // position for material opacity in uniform array
+	pSize         = pOpacity + 1   // position for material point size
+	pRotationZ    = pSize + 1      // position for material point rotation
+	pAlphaTest    = pRotationZ + 1 // position for unlit material alpha test threshold
+	pVertexColors = pAlphaTest + 1 // position for unlit material vertex colors flag
+	uniSize       = 6              // total count of groups 3 floats in uniform
 )
 
 // NewStandard creates and returns a pointer to a new standard material
@@ -102,6 +104,8 @@ func (ms *Standard) SetShininess(shininess float32) {
 }
 
 // SetOpacity sets the material opacity (alpha). Default is 1.0.
+// Translucent materials should also be set transparent with SetTransparent
+// so they are blended over the graphics behind them.
 func (ms *Standard) SetOpacity(opacity float32) {
 
 	ms.uni.SetPos(pOpacity, opacity)
@@ -0,0 +1,70 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package material
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// Unlit material is not affected by the scene lights.
+// The final color is the material color multiplied by the combined
+// colors of its textures and optionally by the vertex colors.
+// It uses the same uniform layout as the Standard material.
+type Unlit struct {
+	Standard // Embedded standard material
+}
+
+// NewUnlit creates and returns a pointer to a new unlit material
+func NewUnlit(color *math32.Color) *Unlit {
+
+	um := new(Unlit)
+	um.Standard.Init("shaderUnlit", color)
+	um.SetUseLights(UseLightNone)
+
+	// Sets uniform's initial values
+	um.uni.SetPos(pAlphaTest, 0)
+	um.uni.SetPos(pVertexColors, 0)
+	return um
+}
+
+// SetAlphaTest sets the alpha threshold below which the fragments are discarded.
+// The default is 0, which disables the alpha test.
+func (um *Unlit) SetAlphaTest(threshold float32) {
+
+	um.uni.SetPos(pAlphaTest, threshold)
+}
+
+// AlphaTest returns the current alpha test threshold
+func (um *Unlit) AlphaTest() float32 {
+
+	return um.uni.GetPos(pAlphaTest)
+}
+
+// SetVertexColors sets if the material color should be multiplied by the
+// geometry vertex colors. The geometry must have the VertexColor attribute.
+// The default is false.
+func (um *Unlit) SetVertexColors(state bool) {
+
+	if state {
+		um.uni.SetPos(pVertexColors, 1)
+	} else {
+		um.uni.SetPos(pVertexColors, 0)
+	}
+}
+
+// VertexColors returns if the material color is multiplied by the vertex colors
+func (um *Unlit) VertexColors() bool {
+
+	return um.uni.GetPos(pVertexColors) != 0
+}
+
+// RenderSetup is called by the engine before drawing the object
+// which uses this material
+func (um *Unlit) RenderSetup(gs *gls.GLS) {
+
+	um.Material.RenderSetup(gs)
+	um.uni.Transfer(gs)
+}
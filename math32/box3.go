@@ -90,6 +90,8 @@ func (this *Box3) Center(optionalTarget *Vector3) *Vector3 {
 	return result.AddVectors(&this.Min, &this.Max).MultiplyScalar(0.5)
 }
 
+// Size calculates the size of this box along each axis.
+// Returns a pointer to the size which is optionalTarget if it is not nil.
 func (this *Box3) Size(optionalTarget *Vector3) *Vector3 {
 
 	var result *Vector3
@@ -98,9 +100,10 @@ func (this *Box3) Size(optionalTarget *Vector3) *Vector3 {
 	} else {
 		result = optionalTarget
 	}
-	return result.SubVectors(&this.Min, &this.Max)
+	return result.SubVectors(&this.Max, &this.Min)
 }
 
+// ExpandByPoint expands this box, if necessary, to contain the specified point
 func (this *Box3) ExpandByPoint(point *Vector3) *Box3 {
 
 	this.Min.Min(point)
@@ -134,7 +137,7 @@ func (this *Box3) ContainsPoint(point *Vector3) bool {
 
 func (this *Box3) ContainsBox(box *Box3) bool {
 
-	if (this.Min.X <= box.Max.X) && (box.Max.X <= this.Max.X) &&
+	if (this.Min.X <= box.Min.X) && (box.Max.X <= this.Max.X) &&
 		(this.Min.Y <= box.Min.Y) && (box.Max.Y <= this.Max.Y) &&
 		(this.Min.Z <= box.Min.Z) && (box.Max.Z <= this.Max.Z) {
 		return true
@@ -212,6 +215,7 @@ func (this *Box3) Intersect(box *Box3) *Box3 {
 	return this
 }
 
+// Union expands this box, if necessary, to contain the specified box
 func (this *Box3) Union(box *Box3) *Box3 {
 
 	this.Min.Min(&box.Min)
@@ -219,19 +223,18 @@ func (this *Box3) Union(box *Box3) *Box3 {
 	return this
 }
 
+// ApplyMatrix4 transforms the eight corners of this box by the specified
+// matrix and sets this box to the axis aligned box which contains them.
+// The resulting box contains all the transformed points of the original box,
+// but may be larger than the transformed geometry it bounds.
+// An empty box is not changed.
 func (this *Box3) ApplyMatrix4(matrix *Matrix4) *Box3 {
 
-	points := []Vector3{
-		Vector3{},
-		Vector3{},
-		Vector3{},
-		Vector3{},
-		Vector3{},
-		Vector3{},
-		Vector3{},
-		Vector3{},
+	if this.Empty() {
+		return this
 	}
 
+	var points [8]Vector3
 	points[0].Set(this.Min.X, this.Min.Y, this.Min.Z).ApplyMatrix4(matrix) // 000
 	points[1].Set(this.Min.X, this.Min.Y, this.Max.Z).ApplyMatrix4(matrix) // 001
 	points[2].Set(this.Min.X, this.Max.Y, this.Min.Z).ApplyMatrix4(matrix) // 010
@@ -241,9 +244,7 @@ func (this *Box3) ApplyMatrix4(matrix *Matrix4) *Box3 {
 	points[6].Set(this.Max.X, this.Max.Y, this.Min.Z).ApplyMatrix4(matrix) // 110
 	points[7].Set(this.Max.X, this.Max.Y, this.Max.Z).ApplyMatrix4(matrix) // 111
 
-	this.MakeEmpty()
-	this.SetFromPoints(points)
-
+	this.SetFromPoints(points[:])
 	return this
 }
 
@@ -0,0 +1,94 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+func TestBox3ApplyMatrix4Rotated(t *testing.T) {
+
+	min := Vector3{-1, -2, -0.5}
+	max := Vector3{3, 1, 0.5}
+	rot := new(Quaternion).SetFromEuler(&Vector3{0.4, 0.9, -0.3})
+	var m Matrix4
+	m.Compose(&Vector3{5, 0, -2}, rot, &Vector3{1, 2, 1})
+
+	box := NewBox3(&min, &max)
+	box.ApplyMatrix4(&m)
+
+	// Every point of the transformed geometry is inside the refitted box
+	// and its corners touch the faces of the box
+	grown := box.Clone().ExpandByScalar(testEpsilon)
+	var fitted Box3
+	fitted.MakeEmpty()
+	for i := 0; i < 27; i++ {
+		p := Vector3{
+			min.X + (max.X-min.X)*float32(i%3)/2,
+			min.Y + (max.Y-min.Y)*float32(i/3%3)/2,
+			min.Z + (max.Z-min.Z)*float32(i/9)/2,
+		}
+		p.ApplyMatrix4(&m)
+		if !grown.ContainsPoint(&p) {
+			t.Errorf("transformed point %v is outside %v", p, *box)
+		}
+		fitted.ExpandByPoint(&p)
+	}
+	if !nearVector3(&fitted.Min, &box.Min) || !nearVector3(&fitted.Max, &box.Max) {
+		t.Errorf("ApplyMatrix4 = %v, want %v", *box, fitted)
+	}
+}
+
+func TestBox3ApplyMatrix4Empty(t *testing.T) {
+
+	var m Matrix4
+	m.MakeTranslation(1, 2, 3)
+	box := NewBox3(nil, nil)
+	box.ApplyMatrix4(&m)
+	if !box.Empty() {
+		t.Errorf("ApplyMatrix4 of an empty box = %v, want empty", *box)
+	}
+}
+
+func TestBox3UnionAndExpandByPoint(t *testing.T) {
+
+	box := NewBox3(nil, nil)
+	box.ExpandByPoint(&Vector3{1, 2, 3})
+	if box.Min != (Vector3{1, 2, 3}) || box.Max != (Vector3{1, 2, 3}) {
+		t.Errorf("ExpandByPoint of an empty box = %v", *box)
+	}
+	box.ExpandByPoint(&Vector3{-1, 5, 3})
+	if box.Min != (Vector3{-1, 2, 3}) || box.Max != (Vector3{1, 5, 3}) {
+		t.Errorf("ExpandByPoint = %v", *box)
+	}
+	box.Union(NewBox3(&Vector3{0, 0, 0}, &Vector3{0.5, 0.5, 10}))
+	if box.Min != (Vector3{-1, 0, 0}) || box.Max != (Vector3{1, 5, 10}) {
+		t.Errorf("Union = %v", *box)
+	}
+	box.Union(NewBox3(nil, nil))
+	if box.Min != (Vector3{-1, 0, 0}) || box.Max != (Vector3{1, 5, 10}) {
+		t.Errorf("Union with an empty box = %v", *box)
+	}
+}
+
+func TestBox3ContainsBox(t *testing.T) {
+
+	box := NewBox3(&Vector3{0, 0, 0}, &Vector3{4, 4, 4})
+	cases := []struct {
+		min, max Vector3
+		want     bool
+	}{
+		{Vector3{1, 1, 1}, Vector3{2, 2, 2}, true},
+		{Vector3{0, 0, 0}, Vector3{4, 4, 4}, true},
+		{Vector3{-1, 1, 1}, Vector3{2, 2, 2}, false},
+		{Vector3{1, 1, 1}, Vector3{2, 5, 2}, false},
+		{Vector3{5, 5, 5}, Vector3{6, 6, 6}, false},
+	}
+	for _, c := range cases {
+		if got := box.ContainsBox(NewBox3(&c.min, &c.max)); got != c.want {
+			t.Errorf("ContainsBox(%v, %v) = %v, want %v", c.min, c.max, got, c.want)
+		}
+	}
+}
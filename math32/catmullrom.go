@@ -0,0 +1,232 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+// CatmullRomType is the type of parameterization of a CatmullRom curve
+type CatmullRomType int
+
+// The types of parameterization of a CatmullRom curve
+const (
+	CatmullRomCentripetal = CatmullRomType(iota) // knots spaced by the square root of the distance between points
+	CatmullRomChordal                            // knots spaced by the distance between points
+	CatmullRomUniform                            // evenly spaced knots using the curve tension
+)
+
+// CatmullRom is a smooth curve which passes through all its control points.
+// The centripetal parameterization (the default) avoids cusps and
+// self intersections when the control points are unevenly spaced.
+// If the curve is closed, the last control point is joined to the first one.
+type CatmullRom struct {
+	points  []Vector3      // control points
+	closed  bool           // the curve is a closed loop
+	ctype   CatmullRomType // type of parameterization
+	tension float32        // tension of the uniform parameterization
+}
+
+// cubicPoly is a cubic polynomial for one component of a curve segment
+type cubicPoly struct {
+	c0, c1, c2, c3 float32
+}
+
+// NewCatmullRom creates and returns a pointer to a new Catmull-Rom curve
+// with a copy of the specified control points
+func NewCatmullRom(points []Vector3, closed bool) *CatmullRom {
+
+	c := new(CatmullRom)
+	c.SetPoints(points)
+	c.closed = closed
+	c.ctype = CatmullRomCentripetal
+	c.tension = 0.5
+	return c
+}
+
+// SetPoints sets the control points of this curve with a copy of the specified points
+func (c *CatmullRom) SetPoints(points []Vector3) *CatmullRom {
+
+	c.points = make([]Vector3, len(points))
+	copy(c.points, points)
+	return c
+}
+
+// Points returns the control points of this curve
+func (c *CatmullRom) Points() []Vector3 {
+
+	return c.points
+}
+
+// SetClosed sets if this curve is a closed loop
+func (c *CatmullRom) SetClosed(closed bool) *CatmullRom {
+
+	c.closed = closed
+	return c
+}
+
+// Closed returns if this curve is a closed loop
+func (c *CatmullRom) Closed() bool {
+
+	return c.closed
+}
+
+// SetType sets the type of parameterization of this curve
+func (c *CatmullRom) SetType(ctype CatmullRomType) *CatmullRom {
+
+	c.ctype = ctype
+	return c
+}
+
+// Type returns the type of parameterization of this curve
+func (c *CatmullRom) Type() CatmullRomType {
+
+	return c.ctype
+}
+
+// SetTension sets the tension of the uniform parameterization.
+// The default is 0.5
+func (c *CatmullRom) SetTension(tension float32) *CatmullRom {
+
+	c.tension = tension
+	return c
+}
+
+// Tension returns the tension of the uniform parameterization
+func (c *CatmullRom) Tension() float32 {
+
+	return c.tension
+}
+
+// Point calculates the point of this curve at the specified position t from
+// 0.0 (first control point) to 1.0 (last control point or, if the curve is
+// closed, the first control point again). The control points are evenly
+// distributed along t. Returns a pointer to the point which is
+// optionalTarget if it is not nil.
+func (c *CatmullRom) Point(t float32, optionalTarget *Vector3) *Vector3 {
+
+	var result *Vector3
+	if optionalTarget == nil {
+		result = NewVector3(0, 0, 0)
+	} else {
+		result = optionalTarget
+	}
+	l := len(c.points)
+	if l == 0 {
+		return result.Set(0, 0, 0)
+	}
+	if l == 1 {
+		return result.Copy(&c.points[0])
+	}
+
+	// Finds the curve segment and the position inside it
+	segments := l - 1
+	if c.closed {
+		segments = l
+	}
+	p := float32(segments) * t
+	ip := int(Floor(p))
+	weight := p - float32(ip)
+	if c.closed {
+		ip = ((ip % l) + l) % l
+	} else if ip >= l-1 {
+		weight += float32(ip - (l - 2))
+		ip = l - 2
+	} else if ip < 0 {
+		weight += float32(ip)
+		ip = 0
+	}
+
+	// Gets the four points of the segment, extrapolating
+	// the first and last points of an open curve
+	var p0, p3 Vector3
+	if c.closed || ip > 0 {
+		p0 = c.points[(ip-1+l)%l]
+	} else {
+		p0.SubVectors(&c.points[0], &c.points[1]).Add(&c.points[0])
+	}
+	p1 := &c.points[ip%l]
+	p2 := &c.points[(ip+1)%l]
+	if c.closed || ip+2 < l {
+		p3 = c.points[(ip+2)%l]
+	} else {
+		p3.SubVectors(&c.points[l-1], &c.points[l-2]).Add(&c.points[l-1])
+	}
+
+	var px, py, pz cubicPoly
+	if c.ctype == CatmullRomUniform {
+		px.initCatmullRom(p0.X, p1.X, p2.X, p3.X, c.tension)
+		py.initCatmullRom(p0.Y, p1.Y, p2.Y, p3.Y, c.tension)
+		pz.initCatmullRom(p0.Z, p1.Z, p2.Z, p3.Z, c.tension)
+	} else {
+		// Knot intervals from the distances between the points
+		var exp float32 = 0.25
+		if c.ctype == CatmullRomChordal {
+			exp = 0.5
+		}
+		dt0 := Pow(p0.DistanceToSquared(p1), exp)
+		dt1 := Pow(p1.DistanceToSquared(p2), exp)
+		dt2 := Pow(p2.DistanceToSquared(&p3), exp)
+		// Safety check for repeated points
+		if dt1 < 1e-4 {
+			dt1 = 1
+		}
+		if dt0 < 1e-4 {
+			dt0 = dt1
+		}
+		if dt2 < 1e-4 {
+			dt2 = dt1
+		}
+		px.initNonuniformCatmullRom(p0.X, p1.X, p2.X, p3.X, dt0, dt1, dt2)
+		py.initNonuniformCatmullRom(p0.Y, p1.Y, p2.Y, p3.Y, dt0, dt1, dt2)
+		pz.initNonuniformCatmullRom(p0.Z, p1.Z, p2.Z, p3.Z, dt0, dt1, dt2)
+	}
+	return result.Set(px.calc(weight), py.calc(weight), pz.calc(weight))
+}
+
+// GetPoints returns divisions+1 points of this curve evenly
+// distributed along t from 0.0 to 1.0
+func (c *CatmullRom) GetPoints(divisions int) []Vector3 {
+
+	if divisions < 1 {
+		divisions = 1
+	}
+	points := make([]Vector3, divisions+1)
+	for d := 0; d <= divisions; d++ {
+		c.Point(float32(d)/float32(divisions), &points[d])
+	}
+	return points
+}
+
+// init sets the coefficients of this polynomial from the values x0 and x1
+// at the start and end of the interval and their derivatives t0 and t1
+func (p *cubicPoly) init(x0, x1, t0, t1 float32) {
+
+	p.c0 = x0
+	p.c1 = t0
+	p.c2 = -3*x0 + 3*x1 - 2*t0 - t1
+	p.c3 = 2*x0 - 2*x1 + t0 + t1
+}
+
+// initCatmullRom sets this polynomial for the uniform
+// parameterization with the specified tension
+func (p *cubicPoly) initCatmullRom(x0, x1, x2, x3, tension float32) {
+
+	p.init(x1, x2, tension*(x2-x0), tension*(x3-x1))
+}
+
+// initNonuniformCatmullRom sets this polynomial for the
+// parameterization with the specified knot intervals
+func (p *cubicPoly) initNonuniformCatmullRom(x0, x1, x2, x3, dt0, dt1, dt2 float32) {
+
+	// Tangents at [x1,x2] in [t1,t2]
+	t1 := (x1-x0)/dt0 - (x2-x0)/(dt0+dt1) + (x2-x1)/dt1
+	t2 := (x2-x1)/dt1 - (x3-x1)/(dt1+dt2) + (x3-x2)/dt2
+	// Rescales tangents for the parameterization in [0,1]
+	p.init(x1, x2, t1*dt1, t2*dt1)
+}
+
+// calc returns the value of this polynomial at t
+func (p *cubicPoly) calc(t float32) float32 {
+
+	t2 := t * t
+	return p.c0 + p.c1*t + p.c2*t2 + p.c3*t2*t
+}
@@ -0,0 +1,78 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+// testWaypoints are unevenly spaced control points
+var testWaypoints = []Vector3{
+	{0, 0, 0}, {1, 2, 0}, {5, 2, -1}, {6, -3, 2}, {6.5, -3, 2},
+}
+
+func TestCatmullRomPassesThroughPoints(t *testing.T) {
+
+	types := []struct {
+		name  string
+		ctype CatmullRomType
+	}{
+		{"centripetal", CatmullRomCentripetal},
+		{"chordal", CatmullRomChordal},
+		{"uniform", CatmullRomUniform},
+	}
+	for _, ct := range types {
+		for _, closed := range []bool{false, true} {
+			c := NewCatmullRom(testWaypoints, closed).SetType(ct.ctype)
+			segments := len(testWaypoints) - 1
+			if closed {
+				segments = len(testWaypoints)
+			}
+			for i := range testWaypoints {
+				tp := float32(i) / float32(segments)
+				p := c.Point(tp, nil)
+				if !nearVector3(p, &testWaypoints[i]) {
+					t.Errorf("%s closed:%v Point(%v) = %v, want %v", ct.name, closed, tp, *p, testWaypoints[i])
+				}
+			}
+			if closed {
+				p := c.Point(1, nil)
+				if !nearVector3(p, &testWaypoints[0]) {
+					t.Errorf("%s closed Point(1) = %v, want %v", ct.name, *p, testWaypoints[0])
+				}
+			}
+		}
+	}
+}
+
+func TestCatmullRomGetPoints(t *testing.T) {
+
+	c := NewCatmullRom(testWaypoints, false)
+	divisions := 4 * (len(testWaypoints) - 1)
+	points := c.GetPoints(divisions)
+	if len(points) != divisions+1 {
+		t.Fatalf("GetPoints(%d) returned %d points, want %d", divisions, len(points), divisions+1)
+	}
+	for i := range testWaypoints {
+		if !nearVector3(&points[4*i], &testWaypoints[i]) {
+			t.Errorf("GetPoints(%d)[%d] = %v, want %v", divisions, 4*i, points[4*i], testWaypoints[i])
+		}
+	}
+}
+
+func TestCatmullRomFewPoints(t *testing.T) {
+
+	if p := NewCatmullRom(nil, false).Point(0.5, nil); *p != (Vector3{}) {
+		t.Errorf("Point of a curve without points = %v, want zero", *p)
+	}
+	one := []Vector3{{1, 2, 3}}
+	if p := NewCatmullRom(one, false).Point(0.5, nil); *p != one[0] {
+		t.Errorf("Point of a curve with one point = %v, want %v", *p, one[0])
+	}
+	two := []Vector3{{0, 0, 0}, {2, 0, 0}}
+	if p := NewCatmullRom(two, false).Point(0.5, nil); !nearVector3(p, &Vector3{1, 0, 0}) {
+		t.Errorf("Point(0.5) of a curve with two points = %v, want (1, 0, 0)", *p)
+	}
+}
@@ -59,6 +59,18 @@ func (c *Color) SetName(name string) *Color {
 	return c.SetHex(colorKeywords[name])
 }
 
+// IsColorName returns the color with the specified HTML color name
+// and if the name is valid
+func IsColorName(name string) (Color, bool) {
+
+	var c Color
+	hex, ok := colorKeywords[name]
+	if ok {
+		c.SetHex(hex)
+	}
+	return c, ok
+}
+
 func (c *Color) Add(other *Color) *Color {
 
 	c.R += other.R
@@ -99,6 +111,9 @@ func (c *Color) MultiplyScalar(v float32) *Color {
 	return c
 }
 
+// Lerp sets this color to the linear interpolation of its RGB components
+// with the specified color by the specified alpha factor from 0.0 (this color)
+// to 1.0 (the specified color).
 func (c *Color) Lerp(color *Color, alpha float32) *Color {
 
 	c.R += (color.R - c.R) * alpha
@@ -107,6 +122,84 @@ func (c *Color) Lerp(color *Color, alpha float32) *Color {
 	return c
 }
 
+// SetHSL sets this color RGB components from the specified hue, saturation
+// and lightness. All values are in the range 0.0 to 1.0 and the hue wraps
+// around so values outside this range are also accepted.
+func (c *Color) SetHSL(h, s, l float32) *Color {
+
+	h = h - Floor(h)
+	s = Clamp(s, 0, 1)
+	l = Clamp(l, 0, 1)
+	if s == 0 {
+		return c.Set(l, l, l)
+	}
+	var q float32
+	if l <= 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	c.R = hueToRGB(p, q, h+1.0/3)
+	c.G = hueToRGB(p, q, h)
+	c.B = hueToRGB(p, q, h-1.0/3)
+	return c
+}
+
+// GetHSL returns the hue, saturation and lightness of this color
+// in the range 0.0 to 1.0. For gray colors the hue and saturation are zero.
+func (c *Color) GetHSL() (h, s, l float32) {
+
+	max := Max(c.R, Max(c.G, c.B))
+	min := Min(c.R, Min(c.G, c.B))
+	l = (min + max) / 2
+	if min == max {
+		return 0, 0, l
+	}
+	delta := max - min
+	if l <= 0.5 {
+		s = delta / (max + min)
+	} else {
+		s = delta / (2 - max - min)
+	}
+	switch max {
+	case c.R:
+		h = (c.G - c.B) / delta
+		if c.G < c.B {
+			h += 6
+		}
+	case c.G:
+		h = (c.B-c.R)/delta + 2
+	default:
+		h = (c.R-c.G)/delta + 4
+	}
+	h /= 6
+	return h, s, l
+}
+
+// hueToRGB returns the value of a RGB component for the specified
+// hue offset and the auxiliary values calculated from the saturation
+// and the lightness of the color
+func hueToRGB(p, q, t float32) float32 {
+
+	if t < 0 {
+		t += 1
+	}
+	if t > 1 {
+		t -= 1
+	}
+	if t < 1.0/6 {
+		return p + (q-p)*6*t
+	}
+	if t < 0.5 {
+		return q
+	}
+	if t < 2.0/3 {
+		return p + (q-p)*6*(2.0/3-t)
+	}
+	return p
+}
+
 func (c *Color) Equals(other *Color) bool {
 
 	return (c.R == other.R) && (c.G == other.G) && (c.B == other.B)
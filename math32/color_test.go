@@ -0,0 +1,74 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+func TestColorHSL(t *testing.T) {
+
+	cases := []struct {
+		c       Color
+		h, s, l float32
+	}{
+		{Color{1, 0, 0}, 0, 1, 0.5},
+		{Color{0, 1, 0}, 1.0 / 3, 1, 0.5},
+		{Color{0, 0, 1}, 2.0 / 3, 1, 0.5},
+		{Color{1, 1, 0}, 1.0 / 6, 1, 0.5},
+		{Color{1, 0, 1}, 5.0 / 6, 1, 0.5},
+		{Color{0.5, 0.25, 0.25}, 0, 1.0 / 3, 0.375},
+		{Color{0.25, 0.5, 1}, 0.6111111, 1, 0.625},
+		{Color{0, 0, 0}, 0, 0, 0},
+		{Color{1, 1, 1}, 0, 0, 1},
+		{Color{0.3, 0.3, 0.3}, 0, 0, 0.3},
+	}
+	for _, c := range cases {
+		h, s, l := c.c.GetHSL()
+		if !near(h, c.h) || !near(s, c.s) || !near(l, c.l) {
+			t.Errorf("%v.GetHSL() = %v, %v, %v, want %v, %v, %v", c.c, h, s, l, c.h, c.s, c.l)
+		}
+		var rgb Color
+		rgb.SetHSL(h, s, l)
+		if !near(rgb.R, c.c.R) || !near(rgb.G, c.c.G) || !near(rgb.B, c.c.B) {
+			t.Errorf("SetHSL(%v, %v, %v) = %v, want %v", h, s, l, rgb, c.c)
+		}
+	}
+}
+
+func TestColorSetHSLWrapsHue(t *testing.T) {
+
+	var a, b Color
+	a.SetHSL(0.25, 0.8, 0.4)
+	b.SetHSL(1.25, 0.8, 0.4)
+	if !near(a.R, b.R) || !near(a.G, b.G) || !near(a.B, b.B) {
+		t.Errorf("SetHSL(1.25, ...) = %v, want %v", b, a)
+	}
+	b.SetHSL(-0.75, 0.8, 0.4)
+	if !near(a.R, b.R) || !near(a.G, b.G) || !near(a.B, b.B) {
+		t.Errorf("SetHSL(-0.75, ...) = %v, want %v", b, a)
+	}
+}
+
+func TestColorLerp(t *testing.T) {
+
+	from := Color{0, 0.5, 1}
+	to := Color{1, 0.5, 0}
+	cases := []struct {
+		alpha float32
+		want  Color
+	}{
+		{0, Color{0, 0.5, 1}},
+		{0.25, Color{0.25, 0.5, 0.75}},
+		{1, Color{1, 0.5, 0}},
+	}
+	for _, c := range cases {
+		color := from
+		color.Lerp(&to, c.alpha)
+		if !near(color.R, c.want.R) || !near(color.G, c.want.G) || !near(color.B, c.want.B) {
+			t.Errorf("Lerp(%v) = %v, want %v", c.alpha, color, c.want)
+		}
+	}
+}
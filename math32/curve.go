@@ -0,0 +1,33 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+// Curve is the interface for parametric curves in 3D space such as CatmullRom
+type Curve interface {
+	// Point calculates the point of the curve at the specified position t
+	// from 0.0 (start of the curve) to 1.0 (end of the curve). Returns a
+	// pointer to the point which is optionalTarget if it is not nil.
+	Point(t float32, optionalTarget *Vector3) *Vector3
+}
+
+// CurveTangent calculates the unit tangent of the specified curve at the position t
+// from 0.0 to 1.0 by finite differences. Returns a pointer to the tangent which
+// is optionalTarget if it is not nil.
+func CurveTangent(c Curve, t float32, optionalTarget *Vector3) *Vector3 {
+
+	var result *Vector3
+	if optionalTarget == nil {
+		result = NewVector3(0, 0, 0)
+	} else {
+		result = optionalTarget
+	}
+	const delta = 0.0001
+	t1 := Max(t-delta, 0)
+	t2 := Min(t+delta, 1)
+	var p1 Vector3
+	c.Point(t1, &p1)
+	c.Point(t2, result)
+	return result.Sub(&p1).Normalize()
+}
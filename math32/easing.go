@@ -0,0 +1,337 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+// EasingFunc is the type of the easing functions which map the normalized
+// time t of an animation from 0.0 to 1.0 to the animation progress.
+// All the easing functions return 0 for t = 0 and 1 for t = 1, but the
+// back and elastic functions return values outside this range in between.
+type EasingFunc func(t float32) float32
+
+// Constants of the back and elastic easing functions
+const (
+	easeBack1    = 1.70158
+	easeBack2    = easeBack1 * 1.525
+	easeBack3    = easeBack1 + 1
+	easeElastic1 = 2 * Pi / 3
+	easeElastic2 = 2 * Pi / 4.5
+)
+
+// easingFuncs maps the names of the easing functions to the functions
+var easingFuncs = map[string]EasingFunc{
+	"Linear":       EaseLinear,
+	"InQuad":       EaseInQuad,
+	"OutQuad":      EaseOutQuad,
+	"InOutQuad":    EaseInOutQuad,
+	"InCubic":      EaseInCubic,
+	"OutCubic":     EaseOutCubic,
+	"InOutCubic":   EaseInOutCubic,
+	"InQuart":      EaseInQuart,
+	"OutQuart":     EaseOutQuart,
+	"InOutQuart":   EaseInOutQuart,
+	"InQuint":      EaseInQuint,
+	"OutQuint":     EaseOutQuint,
+	"InOutQuint":   EaseInOutQuint,
+	"InSine":       EaseInSine,
+	"OutSine":      EaseOutSine,
+	"InOutSine":    EaseInOutSine,
+	"InExpo":       EaseInExpo,
+	"OutExpo":      EaseOutExpo,
+	"InOutExpo":    EaseInOutExpo,
+	"InCirc":       EaseInCirc,
+	"OutCirc":      EaseOutCirc,
+	"InOutCirc":    EaseInOutCirc,
+	"InBack":       EaseInBack,
+	"OutBack":      EaseOutBack,
+	"InOutBack":    EaseInOutBack,
+	"InElastic":    EaseInElastic,
+	"OutElastic":   EaseOutElastic,
+	"InOutElastic": EaseInOutElastic,
+	"InBounce":     EaseInBounce,
+	"OutBounce":    EaseOutBounce,
+	"InOutBounce":  EaseInOutBounce,
+}
+
+// Easing returns the easing function with the specified name, such as
+// "Linear", "InQuad", "OutCubic" or "InOutBounce", or nil if not found
+func Easing(name string) EasingFunc {
+
+	return easingFuncs[name]
+}
+
+// EasingNames returns the names of all the easing functions in no particular order
+func EasingNames() []string {
+
+	names := make([]string, 0, len(easingFuncs))
+	for name := range easingFuncs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// EaseLinear returns t without easing
+func EaseLinear(t float32) float32 {
+
+	return t
+}
+
+// EaseInQuad accelerates from zero velocity with a quadratic curve
+func EaseInQuad(t float32) float32 {
+
+	return t * t
+}
+
+// EaseOutQuad decelerates to zero velocity with a quadratic curve
+func EaseOutQuad(t float32) float32 {
+
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseInOutQuad accelerates until halfway and then decelerates with a quadratic curve
+func EaseInOutQuad(t float32) float32 {
+
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - 2*(1-t)*(1-t)
+}
+
+// EaseInCubic accelerates from zero velocity with a cubic curve
+func EaseInCubic(t float32) float32 {
+
+	return t * t * t
+}
+
+// EaseOutCubic decelerates to zero velocity with a cubic curve
+func EaseOutCubic(t float32) float32 {
+
+	u := 1 - t
+	return 1 - u*u*u
+}
+
+// EaseInOutCubic accelerates until halfway and then decelerates with a cubic curve
+func EaseInOutCubic(t float32) float32 {
+
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	u := 1 - t
+	return 1 - 4*u*u*u
+}
+
+// EaseInQuart accelerates from zero velocity with a quartic curve
+func EaseInQuart(t float32) float32 {
+
+	return t * t * t * t
+}
+
+// EaseOutQuart decelerates to zero velocity with a quartic curve
+func EaseOutQuart(t float32) float32 {
+
+	u := 1 - t
+	return 1 - u*u*u*u
+}
+
+// EaseInOutQuart accelerates until halfway and then decelerates with a quartic curve
+func EaseInOutQuart(t float32) float32 {
+
+	if t < 0.5 {
+		return 8 * t * t * t * t
+	}
+	u := 1 - t
+	return 1 - 8*u*u*u*u
+}
+
+// EaseInQuint accelerates from zero velocity with a quintic curve
+func EaseInQuint(t float32) float32 {
+
+	return t * t * t * t * t
+}
+
+// EaseOutQuint decelerates to zero velocity with a quintic curve
+func EaseOutQuint(t float32) float32 {
+
+	u := 1 - t
+	return 1 - u*u*u*u*u
+}
+
+// EaseInOutQuint accelerates until halfway and then decelerates with a quintic curve
+func EaseInOutQuint(t float32) float32 {
+
+	if t < 0.5 {
+		return 16 * t * t * t * t * t
+	}
+	u := 1 - t
+	return 1 - 16*u*u*u*u*u
+}
+
+// EaseInSine accelerates from zero velocity with a sinusoidal curve
+func EaseInSine(t float32) float32 {
+
+	if t == 1 {
+		return 1
+	}
+	return 1 - Cos(t*Pi/2)
+}
+
+// EaseOutSine decelerates to zero velocity with a sinusoidal curve
+func EaseOutSine(t float32) float32 {
+
+	return Sin(t * Pi / 2)
+}
+
+// EaseInOutSine accelerates until halfway and then decelerates with a sinusoidal curve
+func EaseInOutSine(t float32) float32 {
+
+	if t == 1 {
+		return 1
+	}
+	return (1 - Cos(t*Pi)) / 2
+}
+
+// EaseInExpo accelerates from zero velocity with an exponential curve
+func EaseInExpo(t float32) float32 {
+
+	if t == 0 {
+		return 0
+	}
+	return Pow(2, 10*t-10)
+}
+
+// EaseOutExpo decelerates to zero velocity with an exponential curve
+func EaseOutExpo(t float32) float32 {
+
+	if t == 1 {
+		return 1
+	}
+	return 1 - Pow(2, -10*t)
+}
+
+// EaseInOutExpo accelerates until halfway and then decelerates with an exponential curve
+func EaseInOutExpo(t float32) float32 {
+
+	switch {
+	case t == 0:
+		return 0
+	case t == 1:
+		return 1
+	case t < 0.5:
+		return Pow(2, 20*t-10) / 2
+	default:
+		return (2 - Pow(2, -20*t+10)) / 2
+	}
+}
+
+// EaseInCirc accelerates from zero velocity with a circular curve
+func EaseInCirc(t float32) float32 {
+
+	return 1 - Sqrt(1-t*t)
+}
+
+// EaseOutCirc decelerates to zero velocity with a circular curve
+func EaseOutCirc(t float32) float32 {
+
+	return Sqrt(1 - (t-1)*(t-1))
+}
+
+// EaseInOutCirc accelerates until halfway and then decelerates with a circular curve
+func EaseInOutCirc(t float32) float32 {
+
+	if t < 0.5 {
+		return (1 - Sqrt(1-4*t*t)) / 2
+	}
+	u := -2*t + 2
+	return (Sqrt(1-u*u) + 1) / 2
+}
+
+// EaseInBack moves slightly backwards before accelerating
+func EaseInBack(t float32) float32 {
+
+	return easeBack3*t*t*t - easeBack1*t*t
+}
+
+// EaseOutBack overshoots the end before settling
+func EaseOutBack(t float32) float32 {
+
+	u := t - 1
+	return 1 + easeBack3*u*u*u + easeBack1*u*u
+}
+
+// EaseInOutBack moves slightly backwards at the start and overshoots the end
+func EaseInOutBack(t float32) float32 {
+
+	if t < 0.5 {
+		u := 2 * t
+		return u * u * ((easeBack2+1)*u - easeBack2) / 2
+	}
+	u := 2*t - 2
+	return (u*u*((easeBack2+1)*u+easeBack2) + 2) / 2
+}
+
+// EaseInElastic oscillates with increasing amplitude before reaching the end
+func EaseInElastic(t float32) float32 {
+
+	if t == 0 || t == 1 {
+		return t
+	}
+	return -Pow(2, 10*t-10) * Sin((t*10-10.75)*easeElastic1)
+}
+
+// EaseOutElastic overshoots the end and oscillates with decreasing amplitude
+func EaseOutElastic(t float32) float32 {
+
+	if t == 0 || t == 1 {
+		return t
+	}
+	return Pow(2, -10*t)*Sin((t*10-0.75)*easeElastic1) + 1
+}
+
+// EaseInOutElastic oscillates at the start and at the end
+func EaseInOutElastic(t float32) float32 {
+
+	switch {
+	case t == 0 || t == 1:
+		return t
+	case t < 0.5:
+		return -(Pow(2, 20*t-10) * Sin((20*t-11.125)*easeElastic2)) / 2
+	default:
+		return Pow(2, -20*t+10)*Sin((20*t-11.125)*easeElastic2)/2 + 1
+	}
+}
+
+// EaseInBounce bounces with increasing amplitude before reaching the end
+func EaseInBounce(t float32) float32 {
+
+	return 1 - EaseOutBounce(1-t)
+}
+
+// EaseOutBounce bounces with decreasing amplitude after reaching the end
+func EaseOutBounce(t float32) float32 {
+
+	const n = 7.5625
+	const d = 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// EaseInOutBounce bounces at the start and at the end
+func EaseInOutBounce(t float32) float32 {
+
+	if t < 0.5 {
+		return (1 - EaseOutBounce(1-2*t)) / 2
+	}
+	return (1 + EaseOutBounce(2*t-1)) / 2
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEasingEndpoints(t *testing.T) {
+
+	for _, name := range EasingNames() {
+		f := Easing(name)
+		if v := f(0); v != 0 {
+			t.Errorf("%s(0) = %v, want 0", name, v)
+		}
+		if v := f(1); v != 1 {
+			t.Errorf("%s(1) = %v, want 1", name, v)
+		}
+	}
+}
+
+func TestEasingInOutMiddle(t *testing.T) {
+
+	for _, name := range EasingNames() {
+		if !strings.HasPrefix(name, "InOut") {
+			continue
+		}
+		if v := Easing(name)(0.5); !near(v, 0.5) {
+			t.Errorf("%s(0.5) = %v, want 0.5", name, v)
+		}
+	}
+}
+
+func TestEasingNames(t *testing.T) {
+
+	kinds := []string{"Quad", "Cubic", "Quart", "Quint", "Sine", "Expo", "Circ", "Back", "Elastic", "Bounce"}
+	for _, kind := range kinds {
+		for _, mode := range []string{"In", "Out", "InOut"} {
+			if Easing(mode+kind) == nil {
+				t.Errorf("Easing(%q) = nil", mode+kind)
+			}
+		}
+	}
+	if Easing("Linear") == nil {
+		t.Errorf("Easing(\"Linear\") = nil")
+	}
+	if Easing("Unknown") != nil {
+		t.Errorf("Easing(\"Unknown\") is not nil")
+	}
+	if n := len(EasingNames()); n != 3*len(kinds)+1 {
+		t.Errorf("EasingNames() returned %d names, want %d", n, 3*len(kinds)+1)
+	}
+}
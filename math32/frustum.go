@@ -4,14 +4,19 @@
 
 package math32
 
+// Frustum represents a volume bounded by six planes, usually the visible
+// volume of a camera. The normals of the planes point to the inside
+// of the volume. The planes of the zero value are zeroed, so it contains
+// every point until its planes are set, for example by SetFromMatrix.
 type Frustum struct {
-	planes []Plane
+	planes [6]Plane
 }
 
+// NewFrustum creates and returns a pointer to a new frustum
+// with the specified planes. Nil planes are left zeroed.
 func NewFrustum(p0, p1, p2, p3, p4, p5 *Plane) *Frustum {
 
 	this := new(Frustum)
-	this.planes = make([]Plane, 6)
 	if p0 != nil {
 		this.planes[0] = *p0
 	}
@@ -33,6 +38,7 @@ func NewFrustum(p0, p1, p2, p3, p4, p5 *Plane) *Frustum {
 	return this
 }
 
+// Set sets the planes of this frustum. Nil planes are not changed.
 func (this *Frustum) Set(p0, p1, p2, p3, p4, p5 *Plane) *Frustum {
 
 	if p0 != nil {
@@ -56,17 +62,26 @@ func (this *Frustum) Set(p0, p1, p2, p3, p4, p5 *Plane) *Frustum {
 	return this
 }
 
+// Copy copies the planes of the specified frustum into this one
 func (this *Frustum) Copy(frustum *Frustum) *Frustum {
 
-	for i := 0; i < 6; i++ {
-		this.planes[i] = frustum.planes[i]
-	}
+	this.planes = frustum.planes
 	return this
 }
 
+// Plane returns a pointer to the plane of this frustum with the specified
+// index: 0 (right), 1 (left), 2 (bottom), 3 (top), 4 (far) and 5 (near).
+func (this *Frustum) Plane(idx int) *Plane {
+
+	return &this.planes[idx]
+}
+
+// SetFromMatrix sets the planes of this frustum from the specified
+// projection or combined projection and view matrix. The planes are
+// normalized so distances to them are in world units.
 func (this *Frustum) SetFromMatrix(m *Matrix4) *Frustum {
 
-	planes := this.planes
+	planes := &this.planes
 	me0 := m[0]
 	me1 := m[1]
 	me2 := m[2]
@@ -103,14 +118,13 @@ func (this *Frustum) IntersectsObject(geometry *core.Geometry) bool {
 }
 */
 
+// IntersectsSphere checks if the specified sphere is inside
+// or intersects this frustum
 func (this *Frustum) IntersectsSphere(sphere *Sphere) bool {
 
-	planes := this.planes
 	negRadius := -sphere.Radius
-
 	for i := 0; i < 6; i++ {
-		distance := planes[i].DistanceToPoint(&sphere.Center)
-
+		distance := this.planes[i].DistanceToPoint(&sphere.Center)
 		if distance < negRadius {
 			return false
 		}
@@ -118,57 +132,39 @@ func (this *Frustum) IntersectsSphere(sphere *Sphere) bool {
 	return true
 }
 
+// IntersectsBox checks if the specified axis aligned box is inside or
+// intersects this frustum. The test is conservative: boxes close to a
+// corner of the frustum may be reported as intersecting it.
 func (this *Frustum) IntersectsBox(box *Box3) bool {
 
-	var p1 Vector3
-	var p2 Vector3
-
+	var p Vector3
 	for i := 0; i < 6; i++ {
 		plane := &this.planes[i]
+		// Uses the box corner farthest along the plane normal
 		if plane.normal.X > 0 {
-			p1.X = box.Min.X
-		} else {
-			p1.X = box.Max.X
-		}
-		if plane.normal.X > 0 {
-			p2.X = box.Max.X
+			p.X = box.Max.X
 		} else {
-			p2.X = box.Min.X
+			p.X = box.Min.X
 		}
 		if plane.normal.Y > 0 {
-			p2.Y = box.Min.Y
+			p.Y = box.Max.Y
 		} else {
-			p2.Y = box.Max.Y
-		}
-		if plane.normal.Y > 0 {
-			p2.Y = box.Max.Y
-		} else {
-			p2.Y = box.Min.Y
-		}
-		if plane.normal.Z > 0 {
-			p1.Z = box.Min.Z
-		} else {
-			p1.Z = box.Max.Z
+			p.Y = box.Min.Y
 		}
 		if plane.normal.Z > 0 {
-			p1.Z = box.Max.Z
+			p.Z = box.Max.Z
 		} else {
-			p2.Z = box.Min.Z
+			p.Z = box.Min.Z
 		}
-
-		d1 := plane.DistanceToPoint(&p1)
-		d2 := plane.DistanceToPoint(&p2)
-
-		// if both outside plane, no intersection
-
-		if d1 < 0 && d2 < 0 {
+		// If this corner is outside the plane, the whole box is outside
+		if plane.DistanceToPoint(&p) < 0 {
 			return false
 		}
-
 	}
 	return true
 }
 
+// ContainsPoint checks if the specified point is inside this frustum
 func (this *Frustum) ContainsPoint(point *Vector3) bool {
 
 	for i := 0; i < 6; i++ {
@@ -179,6 +175,7 @@ func (this *Frustum) ContainsPoint(point *Vector3) bool {
 	return true
 }
 
+// Clone returns a pointer to a new copy of this frustum
 func (this *Frustum) Clone() *Frustum {
 
 	return NewFrustum(nil, nil, nil, nil, nil, nil).Copy(this)
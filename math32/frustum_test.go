@@ -0,0 +1,122 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+// testFrustum returns the frustum of a camera at (0, 0, 10) looking to -Z
+// with a vertical field of view of 90 degrees, aspect 1, near 1 and far 100.
+// At z=0 its section is the square from -10 to 10.
+func testFrustum() *Frustum {
+
+	var proj, view, m Matrix4
+	proj.MakePerspective(90, 1, 1, 100)
+	view.MakeTranslation(0, 0, -10)
+	m.MultiplyMatrices(&proj, &view)
+	return new(Frustum).SetFromMatrix(&m)
+}
+
+func TestFrustumSetFromMatrixNormalizesPlanes(t *testing.T) {
+
+	f := testFrustum()
+	for i := 0; i < 6; i++ {
+		p := f.Plane(i)
+		if !near(p.normal.Length(), 1) {
+			t.Errorf("plane %d normal length = %v, want 1", i, p.normal.Length())
+		}
+	}
+	// Distances are in world units
+	if d := f.Plane(5).DistanceToPoint(&Vector3{0, 0, 0}); !near(d, 9) {
+		t.Errorf("distance to the near plane = %v, want 9", d)
+	}
+	if d := f.Plane(4).DistanceToPoint(&Vector3{0, 0, 0}); Abs(d-90) > 1e-3 {
+		t.Errorf("distance to the far plane = %v, want 90", d)
+	}
+}
+
+func TestFrustumContainsPoint(t *testing.T) {
+
+	f := testFrustum()
+	cases := []struct {
+		p    Vector3
+		want bool
+	}{
+		{Vector3{0, 0, 0}, true},
+		{Vector3{9, -9, 0}, true},
+		{Vector3{0, 0, 8.5}, true},
+		{Vector3{11, 0, 0}, false},
+		{Vector3{0, -11, 0}, false},
+		{Vector3{0, 0, 9.5}, false},
+		{Vector3{0, 0, 20}, false},
+		{Vector3{0, 0, -95}, false},
+	}
+	for _, c := range cases {
+		if got := f.ContainsPoint(&c.p); got != c.want {
+			t.Errorf("ContainsPoint(%v) = %v, want %v", c.p, got, c.want)
+		}
+	}
+}
+
+func TestFrustumIntersectsSphere(t *testing.T) {
+
+	f := testFrustum()
+	cases := []struct {
+		name   string
+		center Vector3
+		radius float32
+		want   bool
+	}{
+		{"inside", Vector3{0, 0, 0}, 1, true},
+		{"outside right", Vector3{15, 0, 0}, 1, false},
+		{"straddling right", Vector3{15, 0, 0}, 4, true},
+		{"behind the camera", Vector3{0, 0, 12}, 1, false},
+		{"straddling near", Vector3{0, 0, 9}, 0.5, true},
+		{"beyond far", Vector3{0, 0, -100}, 5, false},
+		{"straddling far", Vector3{0, 0, -90}, 5, true},
+		{"containing the frustum", Vector3{0, 0, -40}, 500, true},
+	}
+	for _, c := range cases {
+		s := NewSphere(&c.center, c.radius)
+		if got := f.IntersectsSphere(s); got != c.want {
+			t.Errorf("%s: IntersectsSphere = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestFrustumIntersectsBox(t *testing.T) {
+
+	f := testFrustum()
+	cases := []struct {
+		name     string
+		min, max Vector3
+		want     bool
+	}{
+		{"inside", Vector3{-1, -1, -1}, Vector3{1, 1, 1}, true},
+		{"outside right", Vector3{20, -1, -1}, Vector3{21, 1, 1}, false},
+		{"outside top", Vector3{-1, 20, -1}, Vector3{1, 21, 1}, false},
+		{"straddling right", Vector3{9, -1, -1}, Vector3{12, 1, 1}, true},
+		{"behind the camera", Vector3{-1, -1, 11}, Vector3{1, 1, 12}, false},
+		{"straddling near", Vector3{-1, -1, 8}, Vector3{1, 1, 12}, true},
+		{"beyond far", Vector3{-1, -1, -200}, Vector3{1, 1, -100}, false},
+		{"containing the frustum", Vector3{-1000, -1000, -1000}, Vector3{1000, 1000, 1000}, true},
+	}
+	for _, c := range cases {
+		box := NewBox3(&c.min, &c.max)
+		if got := f.IntersectsBox(box); got != c.want {
+			t.Errorf("%s: IntersectsBox = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestFrustumZeroValue(t *testing.T) {
+
+	var f Frustum
+	p := Vector3{1, 2, 3}
+	if !f.ContainsPoint(&p) {
+		t.Errorf("zero Frustum does not contain %v", p)
+	}
+}
@@ -332,6 +332,55 @@ func (m *Matrix4) ApplyToVector3Array(array []float32, offset int, length int) [
 	return array
 }
 
+// TransformPoints sets the first len(src) vectors of dst to the vectors of
+// src multiplied by this matrix, including its translation, and returns the
+// updated part of dst. The dst slice must be at least as long as src and
+// may be the same slice as src.
+func (m *Matrix4) TransformPoints(dst, src []Vector3) []Vector3 {
+
+	dst = dst[:len(src)]
+	m0, m1, m2 := m[0], m[1], m[2]
+	m4, m5, m6 := m[4], m[5], m[6]
+	m8, m9, m10 := m[8], m[9], m[10]
+	m12, m13, m14 := m[12], m[13], m[14]
+	for i := range src {
+		x, y, z := src[i].X, src[i].Y, src[i].Z
+		d := &dst[i]
+		d.X = m0*x + m4*y + m8*z + m12
+		d.Y = m1*x + m5*y + m9*z + m13
+		d.Z = m2*x + m6*y + m10*z + m14
+	}
+	return dst
+}
+
+// TransformDirections sets the first len(src) vectors of dst to the vectors
+// of src multiplied by the upper 3x3 part of this matrix, ignoring its
+// translation, and normalizes them as Vector3.TransformDirection does.
+// Returns the updated part of dst. The dst slice must be at least as long
+// as src and may be the same slice as src.
+func (m *Matrix4) TransformDirections(dst, src []Vector3) []Vector3 {
+
+	dst = dst[:len(src)]
+	m0, m1, m2 := m[0], m[1], m[2]
+	m4, m5, m6 := m[4], m[5], m[6]
+	m8, m9, m10 := m[8], m[9], m[10]
+	for i := range src {
+		x, y, z := src[i].X, src[i].Y, src[i].Z
+		tx := m0*x + m4*y + m8*z
+		ty := m1*x + m5*y + m9*z
+		tz := m2*x + m6*y + m10*z
+		l := tx*tx + ty*ty + tz*tz
+		if l != 0 {
+			l = 1 / Sqrt(l)
+		}
+		d := &dst[i]
+		d.X = tx * l
+		d.Y = ty * l
+		d.Z = tz * l
+	}
+	return dst
+}
+
 // Determinant calculates and returns the determinat of this matrix.
 func (m *Matrix4) Determinant() float32 {
 
@@ -557,6 +606,9 @@ func (m *Matrix4) MakeScale(x, y, z float32) *Matrix4 {
 	return m
 }
 
+// Compose sets this transformation matrix from the specified position,
+// rotation quaternion and scale.
+// Returns pointer to this updated matrix.
 func (m *Matrix4) Compose(position *Vector3, quaternion *Quaternion, scale *Vector3) *Matrix4 {
 
 	m.MakeRotationFromQuaternion(quaternion)
@@ -565,47 +617,69 @@ func (m *Matrix4) Compose(position *Vector3, quaternion *Quaternion, scale *Vect
 	return m
 }
 
+// Decompose extracts the position, rotation quaternion and scale of this
+// transformation matrix, so that composing them with Compose results in this
+// matrix. The scale is obtained from the lengths of the basis columns and,
+// if the matrix contains a reflection (negative determinant), the X scale
+// is negated. If one of the scales is zero, the rotation is obtained from
+// the other two basis columns. If more than one scale is zero, the rotation
+// is set to the identity.
+// Returns pointer to this (unchanged) matrix.
 func (m *Matrix4) Decompose(position *Vector3, quaternion *Quaternion, scale *Vector3) *Matrix4 {
 
-	var vector Vector3
 	var matrix Matrix4 = *m
-
-	sx := vector.Set(m[0], m[1], m[2]).Length()
-	sy := vector.Set(m[4], m[5], m[6]).Length()
-	sz := vector.Set(m[8], m[9], m[10]).Length()
+	var cols [3]Vector3
+	var scales [3]float32
+	zeros := 0
+	for i := 0; i < 3; i++ {
+		cols[i].Set(m[i*4], m[i*4+1], m[i*4+2])
+		scales[i] = cols[i].Length()
+		if scales[i] == 0 {
+			zeros++
+		}
+	}
 
 	// If determinant is negative, we need to invert one scale
 	det := m.Determinant()
 	if det < 0 {
-		sx = -sx
+		scales[0] = -scales[0]
 	}
 
 	position.X = m[12]
 	position.Y = m[13]
 	position.Z = m[14]
 
-	// Scale the rotation part
-	invSX := 1 / sx
-	invSY := 1 / sy
-	invSZ := 1 / sz
-
-	matrix[0] *= invSX
-	matrix[1] *= invSX
-	matrix[2] *= invSX
+	scale.X = scales[0]
+	scale.Y = scales[1]
+	scale.Z = scales[2]
 
-	matrix[4] *= invSY
-	matrix[5] *= invSY
-	matrix[6] *= invSY
+	if zeros > 1 {
+		quaternion.SetIdentity()
+		return m
+	}
 
-	matrix[8] *= invSZ
-	matrix[9] *= invSZ
-	matrix[10] *= invSZ
+	// Scale the rotation part
+	for i := 0; i < 3; i++ {
+		if scales[i] != 0 {
+			cols[i].MultiplyScalar(1 / scales[i])
+		}
+	}
+	// Rebuilds the degenerate basis column (if any) from the others
+	switch {
+	case scales[0] == 0:
+		cols[0].CrossVectors(&cols[1], &cols[2])
+	case scales[1] == 0:
+		cols[1].CrossVectors(&cols[2], &cols[0])
+	case scales[2] == 0:
+		cols[2].CrossVectors(&cols[0], &cols[1])
+	}
+	for i := 0; i < 3; i++ {
+		matrix[i*4] = cols[i].X
+		matrix[i*4+1] = cols[i].Y
+		matrix[i*4+2] = cols[i].Z
+	}
 
 	quaternion.SetFromRotationMatrix(&matrix)
-
-	scale.X = sx
-	scale.Y = sy
-	scale.Z = sz
 	return m
 }
 
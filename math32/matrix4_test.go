@@ -0,0 +1,183 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+// nearMatrix4 returns if the specified matrices are equal within testEpsilon
+func nearMatrix4(a, b *Matrix4) bool {
+
+	for i := range a {
+		if !near(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMatrix4Decompose(t *testing.T) {
+
+	rot := new(Quaternion).SetFromEuler(&Vector3{0.3, -1.2, 0.7})
+	cases := []struct {
+		name  string
+		pos   Vector3
+		quat  Quaternion
+		scale Vector3
+	}{
+		{"identity", Vector3{0, 0, 0}, Quaternion{0, 0, 0, 1}, Vector3{1, 1, 1}},
+		{"translation", Vector3{1, -2, 3}, Quaternion{0, 0, 0, 1}, Vector3{1, 1, 1}},
+		{"non-uniform scale", Vector3{1, 2, 3}, *rot, Vector3{2, 0.5, 3}},
+		{"reflection", Vector3{-4, 0, 1}, *rot, Vector3{-1, 2, 1}},
+		{"zero scale", Vector3{0, 1, 0}, *rot, Vector3{2, 0, 1}},
+	}
+	for _, c := range cases {
+		var m Matrix4
+		m.Compose(&c.pos, &c.quat, &c.scale)
+		var pos, scale Vector3
+		var quat Quaternion
+		m.Decompose(&pos, &quat, &scale)
+		if !nearVector3(&pos, &c.pos) {
+			t.Errorf("%s: position = %v, want %v", c.name, pos, c.pos)
+		}
+		// A reflection may be decomposed with a different negative scale
+		// and rotation, but must compose the same matrix
+		if c.scale.X*c.scale.Y*c.scale.Z >= 0 {
+			if !nearVector3(&scale, &c.scale) {
+				t.Errorf("%s: scale = %v, want %v", c.name, scale, c.scale)
+			}
+			if !sameRotation(&quat, &c.quat) {
+				t.Errorf("%s: rotation = %v, want %v", c.name, quat, c.quat)
+			}
+		} else if scale.X*scale.Y*scale.Z >= 0 {
+			t.Errorf("%s: scale = %v, want a reflection", c.name, scale)
+		}
+		var m2 Matrix4
+		m2.Compose(&pos, &quat, &scale)
+		if !nearMatrix4(&m2, &m) {
+			t.Errorf("%s: composed %v, want %v", c.name, m2, m)
+		}
+	}
+}
+
+// testTransform returns a matrix with translation, rotation and non-uniform scale
+func testTransform() *Matrix4 {
+
+	var m Matrix4
+	rot := new(Quaternion).SetFromEuler(&Vector3{0.3, -1.2, 0.7})
+	m.Compose(&Vector3{1, -2, 3}, rot, &Vector3{2, 0.5, 3})
+	return &m
+}
+
+// testPoints returns the specified number of points
+func testPoints(n int) []Vector3 {
+
+	points := make([]Vector3, n)
+	for i := range points {
+		f := float32(i)
+		points[i] = Vector3{f, -2 * f, 0.5*f + 1}
+	}
+	return points
+}
+
+func TestMatrix4TransformPoints(t *testing.T) {
+
+	m := testTransform()
+	src := testPoints(10)
+	dst := make([]Vector3, 12)
+	res := m.TransformPoints(dst, src)
+	if len(res) != len(src) {
+		t.Fatalf("TransformPoints returned %d points, want %d", len(res), len(src))
+	}
+	for i := range src {
+		want := src[i]
+		want.ApplyMatrix4(m)
+		if !nearVector3(&res[i], &want) {
+			t.Errorf("point %d = %v, want %v", i, res[i], want)
+		}
+	}
+	if dst[10] != (Vector3{}) || dst[11] != (Vector3{}) {
+		t.Errorf("TransformPoints changed the vectors after len(src)")
+	}
+
+	// In place
+	inplace := testPoints(10)
+	m.TransformPoints(inplace, inplace)
+	for i := range inplace {
+		if !nearVector3(&inplace[i], &res[i]) {
+			t.Errorf("in place point %d = %v, want %v", i, inplace[i], res[i])
+		}
+	}
+}
+
+func TestMatrix4TransformDirections(t *testing.T) {
+
+	m := testTransform()
+	src := append(testPoints(10), Vector3{})
+	dst := make([]Vector3, len(src))
+	m.TransformDirections(dst, src)
+	for i := range src {
+		want := src[i]
+		want.TransformDirection(m)
+		if !nearVector3(&dst[i], &want) {
+			t.Errorf("direction %d = %v, want %v", i, dst[i], want)
+		}
+	}
+}
+
+func BenchmarkMatrix4TransformPoints(b *testing.B) {
+
+	m := testTransform()
+	src := testPoints(10000)
+	dst := make([]Vector3, len(src))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		m.TransformPoints(dst, src)
+	}
+}
+
+// BenchmarkVector3ApplyMatrix4Loop is the per element loop
+// replaced by Matrix4.TransformPoints
+func BenchmarkVector3ApplyMatrix4Loop(b *testing.B) {
+
+	m := testTransform()
+	src := testPoints(10000)
+	dst := make([]Vector3, len(src))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range src {
+			dst[i] = src[i]
+			dst[i].ApplyMatrix4(m)
+		}
+	}
+}
+
+func BenchmarkMatrix4TransformDirections(b *testing.B) {
+
+	m := testTransform()
+	src := testPoints(10000)
+	dst := make([]Vector3, len(src))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		m.TransformDirections(dst, src)
+	}
+}
+
+// BenchmarkVector3TransformDirectionLoop is the per element loop
+// replaced by Matrix4.TransformDirections
+func BenchmarkVector3TransformDirectionLoop(b *testing.B) {
+
+	m := testTransform()
+	src := testPoints(10000)
+	dst := make([]Vector3, len(src))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range src {
+			dst[i] = src[i]
+			dst[i].TransformDirection(m)
+		}
+	}
+}
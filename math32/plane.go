@@ -103,16 +103,23 @@ func (this *Plane) OrthoPoint(point *Vector3, optionalTarget *Vector3) *Vector3
 	return result.Copy(&this.normal).MultiplyScalar(perpendicularMagnitude)
 }
 
+// IsIntersectionLine checks if the specified line segment crosses or touches
+// this plane, which includes segments which start or end exactly on the plane
+// and segments lying on the plane.
 func (this *Plane) IsIntersectionLine(line *Line3) bool {
 
-	// Note: this tests if a line intersects the plane, not whether it (or its end-points) are coplanar with it.
 	startSign := this.DistanceToPoint(&line.start)
 	endSign := this.DistanceToPoint(&line.end)
 
-	return (startSign < 0 && endSign > 0) || (endSign < 0 && startSign > 0)
-
+	return (startSign <= 0 && endSign >= 0) || (endSign <= 0 && startSign >= 0)
 }
 
+// IntersectLine calculates the point where the specified line segment
+// intersects this plane. If the segment starts or ends exactly on the plane,
+// that end point is returned. If the segment lies on the plane, its start
+// point is returned. Returns nil if the segment does not intersect the plane,
+// otherwise returns a pointer to the intersection point which is
+// optionalTarget if it is not nil.
 func (this *Plane) IntersectLine(line *Line3, optionalTarget *Vector3) *Vector3 {
 
 	var v1 Vector3
@@ -123,7 +130,7 @@ func (this *Plane) IntersectLine(line *Line3, optionalTarget *Vector3) *Vector3
 		result = optionalTarget
 	}
 
-	direction := line.Delta(&v1)
+	direction := v1.SubVectors(&line.end, &line.start)
 	denominator := this.normal.Dot(direction)
 	if denominator == 0 {
 		// line is coplanar, return origin
@@ -141,6 +148,29 @@ func (this *Plane) IntersectLine(line *Line3, optionalTarget *Vector3) *Vector3
 	return result.Copy(direction).MultiplyScalar(t).Add(&line.start)
 }
 
+// IntersectPlane calculates the line where this plane intersects the
+// specified plane and sets the specified line with a point of the
+// intersection line as its start and the start translated by the unit
+// direction of the intersection line as its end.
+// Returns false if the planes are parallel or coincident,
+// in which case the line is not changed.
+func (this *Plane) IntersectPlane(plane *Plane, line *Line3) bool {
+
+	var dir, v1, v2 Vector3
+	dir.CrossVectors(&this.normal, &plane.normal)
+	lenSq := dir.LengthSq()
+	if lenSq == 0 {
+		return false
+	}
+
+	// Point of the intersection line closest to the origin
+	v1.CrossVectors(&plane.normal, &dir).MultiplyScalar(-this.constant)
+	v2.CrossVectors(&dir, &this.normal).MultiplyScalar(-plane.constant)
+	line.start.AddVectors(&v1, &v2).DivideScalar(lenSq)
+	line.end.Copy(&dir).Normalize().Add(&line.start)
+	return true
+}
+
 func (this *Plane) CoplanarPoint(optionalTarget *Vector3) *Vector3 {
 
 	var result *Vector3
@@ -0,0 +1,84 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+func TestPlaneIntersectLine(t *testing.T) {
+
+	// Plane z = 1
+	plane := NewPlane(&Vector3{0, 0, 1}, -1)
+	cases := []struct {
+		name       string
+		start, end Vector3
+		want       Vector3
+		wantHit    bool
+	}{
+		{"crossing", Vector3{0, 0, 0}, Vector3{0, 0, 4}, Vector3{0, 0, 1}, true},
+		{"crossing near the end", Vector3{2, 0, -3}, Vector3{2, 0, 1.5}, Vector3{2, 0, 1}, true},
+		{"crossing oblique", Vector3{-1, -1, 0}, Vector3{1, 3, 2}, Vector3{0, 1, 1}, true},
+		{"start on plane", Vector3{1, 2, 1}, Vector3{1, 2, 5}, Vector3{1, 2, 1}, true},
+		{"end on plane", Vector3{1, 2, -5}, Vector3{1, 2, 1}, Vector3{1, 2, 1}, true},
+		{"short of the plane", Vector3{0, 0, -2}, Vector3{0, 0, 0.5}, Vector3{}, false},
+		{"beyond the plane", Vector3{0, 0, 2}, Vector3{0, 0, 3}, Vector3{}, false},
+		{"parallel", Vector3{0, 0, 2}, Vector3{5, 5, 2}, Vector3{}, false},
+		{"on the plane", Vector3{3, 0, 1}, Vector3{5, 5, 1}, Vector3{3, 0, 1}, true},
+	}
+	for _, c := range cases {
+		line := NewLine3(&c.start, &c.end)
+		if got := plane.IsIntersectionLine(line); got != c.wantHit {
+			t.Errorf("%s: IsIntersectionLine = %v, want %v", c.name, got, c.wantHit)
+		}
+		point := plane.IntersectLine(line, nil)
+		if (point != nil) != c.wantHit {
+			t.Errorf("%s: IntersectLine = %v, want hit %v", c.name, point, c.wantHit)
+			continue
+		}
+		if point != nil && !nearVector3(point, &c.want) {
+			t.Errorf("%s: IntersectLine = %v, want %v", c.name, *point, c.want)
+		}
+	}
+}
+
+func TestPlaneIntersectPlane(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		a, b    *Plane
+		wantHit bool
+	}{
+		{"perpendicular", NewPlane(&Vector3{0, 0, 1}, -1), NewPlane(&Vector3{1, 0, 0}, 2), true},
+		{"oblique", NewPlane(&Vector3{0, 1, 0}, 0), NewPlane(&Vector3{0, 0.6, 0.8}, -3), true},
+		{"parallel", NewPlane(&Vector3{0, 0, 1}, -1), NewPlane(&Vector3{0, 0, 1}, 2), false},
+		{"opposite", NewPlane(&Vector3{0, 0, 1}, -1), NewPlane(&Vector3{0, 0, -1}, 2), false},
+		{"coincident", NewPlane(&Vector3{0, 0, 1}, -1), NewPlane(&Vector3{0, 0, 1}, -1), false},
+	}
+	for _, c := range cases {
+		var line Line3
+		line.Set(&Vector3{7, 7, 7}, &Vector3{8, 8, 8})
+		hit := c.a.IntersectPlane(c.b, &line)
+		if hit != c.wantHit {
+			t.Errorf("%s: IntersectPlane = %v, want %v", c.name, hit, c.wantHit)
+			continue
+		}
+		if !hit {
+			if line.start != (Vector3{7, 7, 7}) || line.end != (Vector3{8, 8, 8}) {
+				t.Errorf("%s: IntersectPlane changed the line to %v", c.name, line)
+			}
+			continue
+		}
+		// Both points of the line lie on both planes
+		for _, p := range []*Vector3{&line.start, &line.end} {
+			if !near(c.a.DistanceToPoint(p), 0) || !near(c.b.DistanceToPoint(p), 0) {
+				t.Errorf("%s: point %v is not on both planes", c.name, *p)
+			}
+		}
+		if !near(line.Distance(), 1) {
+			t.Errorf("%s: line length = %v, want 1", c.name, line.Distance())
+		}
+	}
+}
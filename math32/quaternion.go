@@ -298,6 +298,14 @@ func (this *Quaternion) MultiplyQuaternions(a, b *Quaternion) *Quaternion {
 	return this
 }
 
+// Slerp sets this quaternion to the spherical linear interpolation between
+// this quaternion (t = 0) and the specified quaternion (t = 1) along the
+// shortest arc. If the quaternions are in opposite hemispheres, the
+// specified quaternion is negated, which represents the same rotation.
+// When the angle between them is very small, a normalized linear
+// interpolation is used instead. Values of t outside the 0 to 1 range
+// extrapolate the rotation (see SlerpClamped).
+// Returns pointer to this updated quaternion.
 func (this *Quaternion) Slerp(qb *Quaternion, t float32) *Quaternion {
 
 	if t == 0 {
@@ -312,57 +320,43 @@ func (this *Quaternion) Slerp(qb *Quaternion, t float32) *Quaternion {
 	z := this.z
 	w := this.w
 
+	// Negates the target quaternion if necessary to take the shortest arc
 	cosHalfTheta := w*qb.w + x*qb.x + y*qb.y + z*qb.z
-
+	bx, by, bz, bw := qb.x, qb.y, qb.z, qb.w
 	if cosHalfTheta < 0 {
-
-		this.w = -qb.w
-		this.x = -qb.x
-		this.y = -qb.y
-		this.z = -qb.z
-
+		bx, by, bz, bw = -bx, -by, -bz, -bw
 		cosHalfTheta = -cosHalfTheta
-
-	} else {
-
-		this.Copy(qb)
 	}
 
-	if cosHalfTheta >= 1.0 {
-
-		this.w = w
-		this.x = x
-		this.y = y
-		this.z = z
-
-		return this
-
+	// Quaternions are almost equal: uses normalized linear interpolation
+	// which avoids the division by a sine close to zero
+	if cosHalfTheta > 0.9995 {
+		this.x = x + t*(bx-x)
+		this.y = y + t*(by-y)
+		this.z = z + t*(bz-z)
+		this.w = w + t*(bw-w)
+		return this.Normalize()
 	}
 
 	halfTheta := Acos(cosHalfTheta)
-	sinHalfTheta := Sqrt(1.0 - cosHalfTheta + cosHalfTheta)
-
-	if Abs(sinHalfTheta) < 0.001 {
-
-		this.w = 0.5 * (w + this.w)
-		this.x = 0.5 * (x + this.x)
-		this.y = 0.5 * (y + this.y)
-		this.z = 0.5 * (z + this.z)
-
-		return this
-	}
-
+	sinHalfTheta := Sqrt(1.0 - cosHalfTheta*cosHalfTheta)
 	ratioA := Sin((1-t)*halfTheta) / sinHalfTheta
 	ratioB := Sin(t*halfTheta) / sinHalfTheta
 
-	this.w = (w*ratioA + this.w*ratioB)
-	this.x = (x*ratioA + this.x*ratioB)
-	this.y = (y*ratioA + this.y*ratioB)
-	this.z = (z*ratioA + this.z*ratioB)
-
+	this.x = x*ratioA + bx*ratioB
+	this.y = y*ratioA + by*ratioB
+	this.z = z*ratioA + bz*ratioB
+	this.w = w*ratioA + bw*ratioB
 	return this
 }
 
+// SlerpClamped is like Slerp but clamps t to the 0 to 1 range.
+// Returns pointer to this updated quaternion.
+func (this *Quaternion) SlerpClamped(qb *Quaternion, t float32) *Quaternion {
+
+	return this.Slerp(qb, Clamp(t, 0, 1))
+}
+
 func (this *Quaternion) Equals(quaternion *Quaternion) bool {
 
 	return (quaternion.x == this.x) && (quaternion.y == this.y) && (quaternion.z == this.z) && (quaternion.w == this.w)
@@ -0,0 +1,62 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+// sameRotation returns if the specified unit quaternions represent the same rotation
+func sameRotation(a, b *Quaternion) bool {
+
+	return near(Abs(a.Dot(b)), 1)
+}
+
+func TestQuaternionSlerp(t *testing.T) {
+
+	yAxis := &Vector3{0, 1, 0}
+	rotY := func(angle float32) *Quaternion {
+		return new(Quaternion).SetFromAxisAngle(yAxis, angle)
+	}
+	// The same rotation of 90 degrees in the opposite hemisphere
+	neg90 := rotY(Pi / 2)
+	neg90.Set(-neg90.x, -neg90.y, -neg90.z, -neg90.w)
+
+	cases := []struct {
+		name    string
+		a, b    *Quaternion
+		t       float32
+		want    *Quaternion
+		outside bool // t is outside the 0 to 1 range
+	}{
+		{"start", rotY(0), rotY(Pi / 2), 0, rotY(0), false},
+		{"middle", rotY(0), rotY(Pi / 2), 0.5, rotY(Pi / 4), false},
+		{"end", rotY(0), rotY(Pi / 2), 1, rotY(Pi / 2), false},
+		{"quarter", rotY(Pi / 2), rotY(Pi), 0.25, rotY(Pi * 5 / 8), false},
+		{"shortest arc", rotY(0), neg90, 0.5, rotY(Pi / 4), false},
+		{"shortest arc over 180", rotY(Pi * 3 / 4), rotY(-Pi * 3 / 4), 0.5, rotY(Pi), false},
+		{"tiny angle", rotY(0), rotY(1e-4), 0.5, rotY(0.5e-4), false},
+		{"extrapolate", rotY(0), rotY(Pi / 4), 2, rotY(Pi / 2), true},
+	}
+	for _, c := range cases {
+		q := *c.a
+		q.Slerp(c.b, c.t)
+		if !sameRotation(&q, c.want) {
+			t.Errorf("%s: Slerp = %v, want %v", c.name, q, *c.want)
+		}
+		if !near(q.Length(), 1) {
+			t.Errorf("%s: Slerp length = %v, want 1", c.name, q.Length())
+		}
+		// SlerpClamped only differs outside the 0 to 1 range
+		qc := *c.a
+		qc.SlerpClamped(c.b, c.t)
+		if !c.outside && !sameRotation(&qc, c.want) {
+			t.Errorf("%s: SlerpClamped = %v, want %v", c.name, qc, *c.want)
+		}
+		if c.outside && !sameRotation(&qc, c.b) {
+			t.Errorf("%s: SlerpClamped = %v, want %v", c.name, qc, *c.b)
+		}
+	}
+}
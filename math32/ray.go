@@ -320,159 +320,113 @@ func (ray *Ray) IntersectPlane(plane *Plane, optionalTarget *Vector3) *Vector3 {
 
 }
 
+// IsIntersectionBox checks if this ray intersects with the specified box
 func (ray *Ray) IsIntersectionBox(box *Box3) bool {
 
-	var v Vector3
-
-	if ray.IntersectBox(box, &v) != nil {
-		return true
-	}
-	return false
+	_, ok := ray.IntersectBox(box)
+	return ok
 }
 
-func (ray *Ray) IntersectBox(box *Box3, optionalTarget *Vector3) *Vector3 {
-
-	// http://www.scratchapixel.com/lessons/3d-basic-lessons/lesson-7-intersecting-simple-shapes/ray-box-intersection/
-
-	var tmin, tmax, tymin, tymax, tzmin, tzmax float32
-
-	invdirx := 1 / ray.direction.X
-	invdiry := 1 / ray.direction.Y
-	invdirz := 1 / ray.direction.Z
-
-	var origin = ray.origin
-
-	if invdirx >= 0 {
-		tmin = (box.Min.X - origin.X) * invdirx
-		tmax = (box.Max.X - origin.X) * invdirx
-	} else {
-		tmin = (box.Max.X - origin.X) * invdirx
-		tmax = (box.Min.X - origin.X) * invdirx
-	}
-
-	if invdiry >= 0 {
-		tymin = (box.Min.Y - origin.Y) * invdiry
-		tymax = (box.Max.Y - origin.Y) * invdiry
-	} else {
-		tymin = (box.Max.Y - origin.Y) * invdiry
-		tymax = (box.Min.Y - origin.Y) * invdiry
-	}
-
-	if (tmin > tymax) || (tymin > tmax) {
-		return nil
-	}
-
-	// These lines also handle the case where tmin or tmax is NaN
-	// (result of 0 * Infinity). x !== x returns true if x is NaN
-
-	if tymin > tmin || tmin != tmin {
-		tmin = tymin
-	}
-
-	if tymax < tmax || tmax != tmax {
-		tmax = tymax
-	}
-
-	if invdirz >= 0 {
-		tzmin = (box.Min.Z - origin.Z) * invdirz
-		tzmax = (box.Max.Z - origin.Z) * invdirz
-	} else {
-		tzmin = (box.Max.Z - origin.Z) * invdirz
-		tzmax = (box.Min.Z - origin.Z) * invdirz
-	}
-
-	if (tmin > tzmax) || (tzmin > tmax) {
-		return nil
-	}
-
-	if tzmin > tmin || tmin != tmin {
-		tmin = tzmin
-	}
-
-	if tzmax < tmax || tmax != tmax {
-		tmax = tzmax
+// IntersectBox calculates the point where this ray intersects the specified
+// axis aligned box using the slab method. If the ray origin is inside the box,
+// the point where the ray exits the box is returned. Rays parallel to one of
+// the box faces are supported. Returns the intersection point and true, or
+// false if the ray does not intersect the box or the box is empty.
+func (ray *Ray) IntersectBox(box *Box3) (Vector3, bool) {
+
+	if box.Empty() {
+		return Vector3{}, false
+	}
+
+	// Intersects the ray parameter ranges inside each pair of parallel planes
+	origin := [3]float32{ray.origin.X, ray.origin.Y, ray.origin.Z}
+	dir := [3]float32{ray.direction.X, ray.direction.Y, ray.direction.Z}
+	min := [3]float32{box.Min.X, box.Min.Y, box.Min.Z}
+	max := [3]float32{box.Max.X, box.Max.Y, box.Max.Z}
+	tmin := Inf(-1)
+	tmax := Inf(1)
+	for i := 0; i < 3; i++ {
+		// Parallel to the slab: misses it if the origin is outside
+		if dir[i] == 0 {
+			if origin[i] < min[i] || origin[i] > max[i] {
+				return Vector3{}, false
+			}
+			continue
+		}
+		inv := 1 / dir[i]
+		t1 := (min[i] - origin[i]) * inv
+		t2 := (max[i] - origin[i]) * inv
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tmin {
+			tmin = t1
+		}
+		if t2 < tmax {
+			tmax = t2
+		}
+		if tmin > tmax {
+			return Vector3{}, false
+		}
 	}
 
-	//return point closest to the ray (positive side)
-
+	// Returns the point closest to the ray origin in the positive side
 	if tmax < 0 {
-		return nil
+		return Vector3{}, false
 	}
-
+	var point Vector3
 	if tmin >= 0 {
-		return ray.At(tmin, optionalTarget)
+		ray.At(tmin, &point)
 	} else {
-		return ray.At(tmax, optionalTarget)
+		ray.At(tmax, &point)
 	}
-
+	return point, true
 }
 
 // IntersectTriangle checks if this ray intersects the triangle with the face
-// defined by points a, b, c. Returns true if it intersects and sets the point
-// parameter with the intersected point coordinates.
-// If backfaceCulling is false it ignores the intersection if the face is not oriented
-// in the ray direction.
-func (ray *Ray) IntersectTriangle(a, b, c *Vector3, backfaceCulling bool, point *Vector3) bool {
-
-	var diff Vector3
-	var edge1 Vector3
-	var edge2 Vector3
-	var normal Vector3
-
+// defined by points a, b, c using the Möller–Trumbore algorithm.
+// Returns the intersection point and true, or false if there is no intersection.
+// If backfaceCulling is true it ignores the intersection if the face is not
+// oriented towards the ray origin (with the points in counter clockwise order).
+// Degenerate triangles (with collinear points) and rays parallel to the
+// triangle plane never intersect.
+func (ray *Ray) IntersectTriangle(a, b, c *Vector3, backfaceCulling bool) (Vector3, bool) {
+
+	var edge1, edge2, pvec, tvec, qvec Vector3
 	edge1.SubVectors(b, a)
 	edge2.SubVectors(c, a)
-	normal.CrossVectors(&edge1, &edge2)
-
-	// Solve Q + t*D = b1*E1 + b2*E2 (Q = kDiff, D = ray direction,
-	// E1 = kEdge1, E2 = kEdge2, N = Cross(E1,E2)) by
-	//   |Dot(D,N)|*b1 = sign(Dot(D,N))*Dot(D,Cross(Q,E2))
-	//   |Dot(D,N)|*b2 = sign(Dot(D,N))*Dot(D,Cross(E1,Q))
-	//   |Dot(D,N)|*t = -sign(Dot(D,N))*Dot(Q,N)
-	DdN := ray.direction.Dot(&normal)
-	var sign float32
-
-	if DdN > 0 {
-		if backfaceCulling {
-			return false
-		}
-		sign = 1
-	} else if DdN < 0 {
-		sign = -1
-		DdN = -DdN
-	} else {
-		return false
-	}
-
-	diff.SubVectors(&ray.origin, a)
-	DdQxE2 := sign * ray.direction.Dot(edge2.CrossVectors(&diff, &edge2))
-
-	// b1 < 0, no intersection
-	if DdQxE2 < 0 {
-		return false
-	}
-
-	DdE1xQ := sign * ray.direction.Dot(edge1.Cross(&diff))
-	// b2 < 0, no intersection
-	if DdE1xQ < 0 {
-		return false
-	}
-
-	// b1+b2 > 1, no intersection
-	if DdQxE2+DdE1xQ > DdN {
-		return false
-	}
-
-	// Line intersects triangle, check if ray does.
-	QdN := -sign * diff.Dot(&normal)
-
-	// t < 0, no intersection
-	if QdN < 0 {
-		return false
-	}
-
-	// Ray intersects triangle.
-	ray.At(QdN/DdN, point)
-	return true
+	pvec.CrossVectors(&ray.direction, &edge2)
+
+	// The determinant is zero if the ray is parallel to the triangle plane
+	// or the triangle is degenerate and negative if the face is seen from its back.
+	// It is compared relative to the lengths of the edges and of the direction.
+	det := edge1.Dot(&pvec)
+	eps := 1e-6 * edge1.Length() * edge2.Length() * ray.direction.Length()
+	if det <= eps && (backfaceCulling || det >= -eps) {
+		return Vector3{}, false
+	}
+	invDet := 1 / det
+
+	// Barycentric coordinates of the intersection in the triangle plane
+	tvec.SubVectors(&ray.origin, a)
+	u := tvec.Dot(&pvec) * invDet
+	if u < 0 || u > 1 {
+		return Vector3{}, false
+	}
+	qvec.CrossVectors(&tvec, &edge1)
+	v := ray.direction.Dot(&qvec) * invDet
+	if v < 0 || u+v > 1 {
+		return Vector3{}, false
+	}
+
+	// Intersection behind the ray origin
+	t := edge2.Dot(&qvec) * invDet
+	if t < 0 {
+		return Vector3{}, false
+	}
+	var point Vector3
+	ray.At(t, &point)
+	return point, true
 }
 
 // ApplyMatrix4 multiplies this ray origin and direction
@@ -0,0 +1,88 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+func TestRayIntersectTriangle(t *testing.T) {
+
+	// Counter clockwise triangle in the z=0 plane facing +Z
+	a := Vector3{0, 0, 0}
+	b := Vector3{2, 0, 0}
+	c := Vector3{0, 2, 0}
+	cases := []struct {
+		name    string
+		origin  Vector3
+		dir     Vector3
+		a, b, c Vector3
+		culling bool
+		want    Vector3
+		wantHit bool
+	}{
+		{"front", Vector3{0.5, 0.5, 5}, Vector3{0, 0, -1}, a, b, c, true, Vector3{0.5, 0.5, 0}, true},
+		{"front not normalized", Vector3{0.5, 0.5, 5}, Vector3{0, 0, -10}, a, b, c, true, Vector3{0.5, 0.5, 0}, true},
+		{"oblique", Vector3{0, 0, 1}, Vector3{1, 1, -2}, a, b, c, false, Vector3{0.5, 0.5, 0}, true},
+		{"vertex", Vector3{2, 0, 1}, Vector3{0, 0, -1}, a, b, c, true, Vector3{2, 0, 0}, true},
+		{"outside", Vector3{1.5, 1.5, 5}, Vector3{0, 0, -1}, a, b, c, false, Vector3{}, false},
+		{"behind origin", Vector3{0.5, 0.5, -5}, Vector3{0, 0, -1}, a, b, c, false, Vector3{}, false},
+		{"back face culled", Vector3{0.5, 0.5, -5}, Vector3{0, 0, 1}, a, b, c, true, Vector3{}, false},
+		{"back face", Vector3{0.5, 0.5, -5}, Vector3{0, 0, 1}, a, b, c, false, Vector3{0.5, 0.5, 0}, true},
+		{"parallel", Vector3{0.5, 0.5, 0}, Vector3{1, 0, 0}, a, b, c, false, Vector3{}, false},
+		{"parallel above", Vector3{-1, 0.5, 1}, Vector3{1, 0, 0}, a, b, c, false, Vector3{}, false},
+		{"degenerate", Vector3{1, 0, 5}, Vector3{0, 0, -1}, a, b, Vector3{4, 0, 0}, false, Vector3{}, false},
+		{"degenerate point", Vector3{0, 0, 5}, Vector3{0, 0, -1}, a, a, a, false, Vector3{}, false},
+	}
+	for _, c := range cases {
+		ray := NewRay(&c.origin, &c.dir)
+		point, hit := ray.IntersectTriangle(&c.a, &c.b, &c.c, c.culling)
+		if hit != c.wantHit {
+			t.Errorf("%s: hit = %v, want %v", c.name, hit, c.wantHit)
+			continue
+		}
+		if hit && !nearVector3(&point, &c.want) {
+			t.Errorf("%s: point = %v, want %v", c.name, point, c.want)
+		}
+	}
+}
+
+func TestRayIntersectBox(t *testing.T) {
+
+	box := NewBox3(&Vector3{-1, -1, -1}, &Vector3{1, 1, 1})
+	cases := []struct {
+		name    string
+		origin  Vector3
+		dir     Vector3
+		box     *Box3
+		want    Vector3
+		wantHit bool
+	}{
+		{"front", Vector3{0, 0, 5}, Vector3{0, 0, -1}, box, Vector3{0, 0, 1}, true},
+		{"oblique", Vector3{-3, -3, 0}, Vector3{1, 1, 0}, box, Vector3{-1, -1, 0}, true},
+		{"origin inside", Vector3{0, 0, 0}, Vector3{1, 0, 0}, box, Vector3{1, 0, 0}, true},
+		{"origin on face", Vector3{1, 0, 0}, Vector3{-1, 0, 0}, box, Vector3{1, 0, 0}, true},
+		{"behind origin", Vector3{0, 0, 5}, Vector3{0, 0, 1}, box, Vector3{}, false},
+		{"miss", Vector3{2, 2, 5}, Vector3{0, 0, -1}, box, Vector3{}, false},
+		{"miss oblique", Vector3{0, 3, 5}, Vector3{0, 1, -1}, box, Vector3{}, false},
+		{"parallel to face inside", Vector3{0.5, 0.5, 5}, Vector3{0, 0, -1}, box, Vector3{0.5, 0.5, 1}, true},
+		{"parallel to face outside", Vector3{5, 0, 2}, Vector3{-1, 0, 0}, box, Vector3{}, false},
+		{"empty box", Vector3{0, 0, 5}, Vector3{0, 0, -1}, NewBox3(nil, nil).MakeEmpty(), Vector3{}, false},
+	}
+	for _, c := range cases {
+		ray := NewRay(&c.origin, &c.dir)
+		point, hit := ray.IntersectBox(c.box)
+		if hit != c.wantHit {
+			t.Errorf("%s: hit = %v, want %v", c.name, hit, c.wantHit)
+			continue
+		}
+		if hit && !nearVector3(&point, &c.want) {
+			t.Errorf("%s: point = %v, want %v", c.name, point, c.want)
+		}
+		if ray.IsIntersectionBox(c.box) != c.wantHit {
+			t.Errorf("%s: IsIntersectionBox = %v, want %v", c.name, !c.wantHit, c.wantHit)
+		}
+	}
+}
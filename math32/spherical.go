@@ -0,0 +1,87 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+// Spherical represents a point in spherical coordinates.
+// The polar angle phi is measured from the positive Y axis and the
+// azimuthal angle theta is measured around the Y axis from the positive Z axis.
+type Spherical struct {
+	Radius float32 // distance from the origin
+	Phi    float32 // polar angle from the positive Y axis in radians (0 to Pi)
+	Theta  float32 // azimuthal angle around the Y axis in radians
+}
+
+// sphericalEpsilon is the minimum distance of the polar angle from the poles
+// kept by MakeSafe
+const sphericalEpsilon = 0.000001
+
+// NewSpherical creates and returns a pointer to a new spherical
+// coordinates with the specified radius, polar and azimuthal angles
+func NewSpherical(radius, phi, theta float32) *Spherical {
+
+	return &Spherical{Radius: radius, Phi: phi, Theta: theta}
+}
+
+// Set sets the radius, polar and azimuthal angles of these spherical coordinates
+func (s *Spherical) Set(radius, phi, theta float32) *Spherical {
+
+	s.Radius = radius
+	s.Phi = phi
+	s.Theta = theta
+	return s
+}
+
+// Copy copies the specified spherical coordinates into these ones
+func (s *Spherical) Copy(other *Spherical) *Spherical {
+
+	*s = *other
+	return s
+}
+
+// MakeSafe clamps the polar angle to be slightly away from the poles
+// where the azimuthal angle is undefined
+func (s *Spherical) MakeSafe() *Spherical {
+
+	s.Phi = Clamp(s.Phi, sphericalEpsilon, Pi-sphericalEpsilon)
+	return s
+}
+
+// SetFromVec3 sets these spherical coordinates from the specified
+// cartesian coordinates. For the zero vector the angles are set to zero.
+func (s *Spherical) SetFromVec3(v *Vector3) *Spherical {
+
+	s.Radius = v.Length()
+	if s.Radius == 0 {
+		s.Phi = 0
+		s.Theta = 0
+		return s
+	}
+	s.Theta = Atan2(v.X, v.Z)
+	s.Phi = Acos(Clamp(v.Y/s.Radius, -1, 1))
+	return s
+}
+
+// Vector3 returns the cartesian coordinates of these spherical coordinates
+func (s *Spherical) Vector3() Vector3 {
+
+	sinPhiRadius := Sin(s.Phi) * s.Radius
+	return Vector3{
+		X: sinPhiRadius * Sin(s.Theta),
+		Y: Cos(s.Phi) * s.Radius,
+		Z: sinPhiRadius * Cos(s.Theta),
+	}
+}
+
+// Equals returns if these spherical coordinates are equal to the specified ones
+func (s *Spherical) Equals(other *Spherical) bool {
+
+	return *s == *other
+}
+
+// Clone returns a pointer to a copy of these spherical coordinates
+func (s *Spherical) Clone() *Spherical {
+
+	return NewSpherical(s.Radius, s.Phi, s.Theta)
+}
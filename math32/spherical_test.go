@@ -0,0 +1,71 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+func TestSphericalRoundTrip(t *testing.T) {
+
+	// One vector in each octant and some on the axes
+	cases := []Vector3{
+		{1, 2, 3}, {-1, 2, 3}, {1, -2, 3}, {1, 2, -3},
+		{-1, -2, 3}, {-1, 2, -3}, {1, -2, -3}, {-1, -2, -3},
+		{5, 0, 0}, {0, 0, -5}, {0, 0.5, 0}, {0, -0.5, 0},
+	}
+	for _, v := range cases {
+		var s Spherical
+		s.SetFromVec3(&v)
+		if !near(s.Radius, v.Length()) {
+			t.Errorf("SetFromVec3(%v).Radius = %v, want %v", v, s.Radius, v.Length())
+		}
+		if s.Phi < 0 || s.Phi > Pi {
+			t.Errorf("SetFromVec3(%v).Phi = %v, outside 0 to Pi", v, s.Phi)
+		}
+		got := s.Vector3()
+		if !nearVector3(&got, &v) {
+			t.Errorf("SetFromVec3(%v).Vector3() = %v", v, got)
+		}
+	}
+}
+
+func TestSphericalAngles(t *testing.T) {
+
+	cases := []struct {
+		v          Vector3
+		phi, theta float32
+	}{
+		{Vector3{0, 0, 1}, Pi / 2, 0},
+		{Vector3{1, 0, 0}, Pi / 2, Pi / 2},
+		{Vector3{0, 1, 0}, 0, 0},
+		{Vector3{0, -1, 0}, Pi, 0},
+		{Vector3{0, 0, 0}, 0, 0},
+	}
+	for _, c := range cases {
+		var s Spherical
+		s.SetFromVec3(&c.v)
+		if !near(s.Phi, c.phi) || !near(s.Theta, c.theta) {
+			t.Errorf("SetFromVec3(%v) = phi %v theta %v, want %v %v", c.v, s.Phi, s.Theta, c.phi, c.theta)
+		}
+	}
+}
+
+func TestSphericalMakeSafe(t *testing.T) {
+
+	cases := []float32{-1, 0, Pi, 4}
+	for _, phi := range cases {
+		s := NewSpherical(1, phi, 0)
+		s.MakeSafe()
+		if s.Phi <= 0 || s.Phi >= Pi {
+			t.Errorf("MakeSafe() with phi %v = %v, want inside 0 to Pi", phi, s.Phi)
+		}
+	}
+	s := NewSpherical(1, 1, 2)
+	s.MakeSafe()
+	if s.Phi != 1 || s.Theta != 2 {
+		t.Errorf("MakeSafe() changed safe coordinates to %v", *s)
+	}
+}
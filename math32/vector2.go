@@ -141,6 +141,7 @@ func (this *Vector2) DivideScalar(scalar float32) *Vector2 {
 	return this
 }
 
+// Min sets this vector with minimum components from itself and the other vector
 func (this *Vector2) Min(v *Vector2) *Vector2 {
 
 	if this.X > v.X {
@@ -152,6 +153,7 @@ func (this *Vector2) Min(v *Vector2) *Vector2 {
 	return this
 }
 
+// Max sets this vector with maximum components from itself and the other vector
 func (this *Vector2) Max(v *Vector2) *Vector2 {
 
 	if this.X < v.X {
@@ -163,6 +165,8 @@ func (this *Vector2) Max(v *Vector2) *Vector2 {
 	return this
 }
 
+// Clamp sets the components of this vector to be between the corresponding
+// components of the specified min and max vectors which must be min <= max
 func (this *Vector2) Clamp(min, max *Vector2) *Vector2 {
 
 	// This function assumes min < max, if this assumption isn't true it will not operate correctly
@@ -180,6 +184,7 @@ func (this *Vector2) Clamp(min, max *Vector2) *Vector2 {
 	return this
 }
 
+// ClampScalar sets the components of this vector to be between minVal and maxVal
 func (this *Vector2) ClampScalar(minVal, maxVal float32) *Vector2 {
 
 	min := NewVector2(0, 0)
@@ -189,6 +194,7 @@ func (this *Vector2) ClampScalar(minVal, maxVal float32) *Vector2 {
 	return this.Clamp(min, max)
 }
 
+// Floor rounds the components of this vector down
 func (this *Vector2) Floor() *Vector2 {
 
 	this.X = Floor(this.X)
@@ -196,6 +202,7 @@ func (this *Vector2) Floor() *Vector2 {
 	return this
 }
 
+// Ceil rounds the components of this vector up
 func (this *Vector2) Ceil() *Vector2 {
 
 	this.X = Ceil(this.X)
@@ -203,14 +210,15 @@ func (this *Vector2) Ceil() *Vector2 {
 	return this
 }
 
+// Round rounds the components of this vector to the nearest integer
 func (this *Vector2) Round() *Vector2 {
 
-	// TODO NEED CHECK
 	this.X = Floor(this.X + 0.5)
 	this.Y = Floor(this.Y + 0.5)
 	return this
 }
 
+// RoundToZero rounds the components of this vector towards zero
 func (this *Vector2) RoundToZero() *Vector2 {
 
 	if this.X < 0 {
@@ -226,6 +234,20 @@ func (this *Vector2) RoundToZero() *Vector2 {
 	return this
 }
 
+// SnapTo rounds the components of this vector to the nearest multiple
+// of the corresponding components of the specified grid vector.
+// Components with a zero grid size are not changed.
+func (this *Vector2) SnapTo(grid *Vector2) *Vector2 {
+
+	if grid.X != 0 {
+		this.X = Floor(this.X/grid.X+0.5) * grid.X
+	}
+	if grid.Y != 0 {
+		this.Y = Floor(this.Y/grid.Y+0.5) * grid.Y
+	}
+	return this
+}
+
 func (this *Vector2) Negate() *Vector2 {
 
 	this.X = -this.X
@@ -301,6 +301,7 @@ func (v *Vector3) DivideScalar(scalar float32) *Vector3 {
 	return v
 }
 
+// Min sets this vector with minimum components from itself and the other vector
 func (v *Vector3) Min(other *Vector3) *Vector3 {
 
 	if v.X > other.X {
@@ -330,6 +331,8 @@ func (v *Vector3) Max(other *Vector3) *Vector3 {
 	return v
 }
 
+// Clamp sets the components of this vector to be between the corresponding
+// components of the specified min and max vectors which must be min <= max
 func (this *Vector3) Clamp(min, max *Vector3) *Vector3 {
 
 	// This function assumes min < max, if this assumption isn't true it will not operate correctly
@@ -353,6 +356,7 @@ func (this *Vector3) Clamp(min, max *Vector3) *Vector3 {
 	return this
 }
 
+// ClampScalar sets the components of this vector to be between minVal and maxVal
 func (this *Vector3) ClampScalar(minVal, maxVal float32) *Vector3 {
 
 	min := NewVector3(minVal, minVal, minVal)
@@ -360,6 +364,7 @@ func (this *Vector3) ClampScalar(minVal, maxVal float32) *Vector3 {
 	return this.Clamp(min, max)
 }
 
+// Floor rounds the components of this vector down
 func (this *Vector3) Floor() *Vector3 {
 
 	this.X = Floor(this.X)
@@ -368,6 +373,7 @@ func (this *Vector3) Floor() *Vector3 {
 	return this
 }
 
+// Ceil rounds the components of this vector up
 func (this *Vector3) Ceil() *Vector3 {
 
 	this.X = Ceil(this.X)
@@ -376,6 +382,7 @@ func (this *Vector3) Ceil() *Vector3 {
 	return this
 }
 
+// Round rounds the components of this vector to the nearest integer
 func (this *Vector3) Round() *Vector3 {
 
 	this.X = Floor(this.X + 0.5)
@@ -384,6 +391,7 @@ func (this *Vector3) Round() *Vector3 {
 	return this
 }
 
+// RoundToZero rounds the components of this vector towards zero
 func (this *Vector3) RoundToZero() *Vector3 {
 
 	if this.X < 0 {
@@ -406,6 +414,23 @@ func (this *Vector3) RoundToZero() *Vector3 {
 	return this
 }
 
+// SnapTo rounds the components of this vector to the nearest multiple
+// of the corresponding components of the specified grid vector.
+// Components with a zero grid size are not changed.
+func (this *Vector3) SnapTo(grid *Vector3) *Vector3 {
+
+	if grid.X != 0 {
+		this.X = Floor(this.X/grid.X+0.5) * grid.X
+	}
+	if grid.Y != 0 {
+		this.Y = Floor(this.Y/grid.Y+0.5) * grid.Y
+	}
+	if grid.Z != 0 {
+		this.Z = Floor(this.Z/grid.Z+0.5) * grid.Z
+	}
+	return this
+}
+
 func (this *Vector3) Negate() *Vector3 {
 
 	this.X = -this.X
@@ -484,14 +509,25 @@ func (this *Vector3) CrossVectors(a, b *Vector3) *Vector3 {
 	return this
 }
 
+// ProjectOnVector sets this vector to its projection on the specified vector
+// which does not need to be normalized. If the specified vector has zero
+// length, this vector is set to zero.
+// Returns the pointer to this updated vector.
 func (this *Vector3) ProjectOnVector(vector *Vector3) *Vector3 {
 
-	var v1 Vector3
-	v1.Copy(vector).Normalize()
-	dot := this.Dot(&v1)
-	return this.Copy(&v1).MultiplyScalar(dot)
+	denominator := vector.LengthSq()
+	if denominator == 0 {
+		return this.Set(0, 0, 0)
+	}
+	scalar := vector.Dot(this) / denominator
+	return this.Copy(vector).MultiplyScalar(scalar)
 }
 
+// ProjectOnPlane sets this vector to its projection on the plane orthogonal
+// to the specified normal, subtracting its component along the normal.
+// The normal does not need to be normalized. If the normal has zero length,
+// this vector is not changed.
+// Returns the pointer to this updated vector.
 func (this *Vector3) ProjectOnPlane(planeNormal *Vector3) *Vector3 {
 
 	var v1 Vector3
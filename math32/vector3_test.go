@@ -0,0 +1,62 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package math32
+
+import (
+	"testing"
+)
+
+const testEpsilon = 1e-5
+
+// near returns if the specified values are equal within testEpsilon
+func near(a, b float32) bool {
+
+	return Abs(a-b) <= testEpsilon
+}
+
+// nearVector3 returns if the specified vectors are equal within testEpsilon
+func nearVector3(a, b *Vector3) bool {
+
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Z, b.Z)
+}
+
+func TestVector3ProjectOnVector(t *testing.T) {
+
+	cases := []struct {
+		v, onto, want Vector3
+	}{
+		{Vector3{1, 2, 3}, Vector3{1, 0, 0}, Vector3{1, 0, 0}},
+		{Vector3{1, 2, 3}, Vector3{0, 5, 0}, Vector3{0, 2, 0}},
+		{Vector3{2, 2, 0}, Vector3{2, 0, 2}, Vector3{1, 0, 1}},
+		{Vector3{1, 2, 3}, Vector3{0, 0, 0}, Vector3{0, 0, 0}},
+		{Vector3{0, 0, 0}, Vector3{1, 1, 1}, Vector3{0, 0, 0}},
+	}
+	for _, c := range cases {
+		v := c.v
+		v.ProjectOnVector(&c.onto)
+		if !nearVector3(&v, &c.want) {
+			t.Errorf("%v.ProjectOnVector(%v) = %v, want %v", c.v, c.onto, v, c.want)
+		}
+	}
+}
+
+func TestVector3ProjectOnPlane(t *testing.T) {
+
+	cases := []struct {
+		v, normal, want Vector3
+	}{
+		{Vector3{1, 2, 3}, Vector3{0, 1, 0}, Vector3{1, 0, 3}},
+		{Vector3{1, 2, 3}, Vector3{0, 0, 10}, Vector3{1, 2, 0}},
+		{Vector3{1, 1, 0}, Vector3{1, 1, 0}, Vector3{0, 0, 0}},
+		{Vector3{1, 2, 3}, Vector3{0, 0, 0}, Vector3{1, 2, 3}},
+	}
+	for _, c := range cases {
+		v := c.v
+		v.ProjectOnPlane(&c.normal)
+		if !nearVector3(&v, &c.want) {
+			t.Errorf("%v.ProjectOnPlane(%v) = %v, want %v", c.v, c.normal, v, c.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package renderer_test
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/camera/control"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
+	"github.com/g3n/engine/window"
+)
+
+// This example shows the statistics of the last rendered frame in a GUI
+// overlay over a grid of spheres. Orbiting the camera changes the number
+// of spheres culled outside the view and the time to draw the others.
+func ExampleRenderer_Stats() {
+
+	win, err := window.New("glfw", 800, 600, "Render statistics", false)
+	if err != nil {
+		panic(err)
+	}
+	gs, err := gls.New()
+	if err != nil {
+		panic(err)
+	}
+	rend := renderer.NewRenderer(gs)
+	err = rend.AddDefaultShaders()
+	if err != nil {
+		panic(err)
+	}
+
+	scene := core.NewNode()
+	scene.Add(light.NewAmbient(math32.NewColor(1, 1, 1), 0.5))
+	sun := light.NewDirectional(math32.NewColor(1, 1, 1), 1)
+	sun.SetPosition(1, 1, 1)
+	scene.Add(sun)
+	sphere := geometry.NewSphere(0.4, 16, 8, 0, 2*math32.Pi, 0, math32.Pi)
+	colors := []*material.Standard{
+		material.NewStandard(math32.NewColor(1, 0, 0)),
+		material.NewStandard(math32.NewColor(0, 1, 0)),
+		material.NewStandard(math32.NewColor(0, 0, 1)),
+	}
+	for x := -10; x <= 10; x++ {
+		for z := -10; z <= 10; z++ {
+			mesh := graphic.NewMesh(sphere, colors[(x+z+20)%3])
+			mesh.SetPosition(float32(x), 0, float32(z))
+			scene.Add(mesh)
+		}
+	}
+
+	cam := camera.NewPerspective(60, 800.0/600.0, 0.1, 100)
+	cam.SetPosition(0, 5, 15)
+	cam.LookAt(&math32.Vector3{})
+	control.NewOrbitControl(cam, win)
+
+	// The GUI panels are rendered over the scene
+	root := gui.NewRoot(gs, win)
+	root.SetSize(800, 600)
+	scene.Add(root)
+	label := gui.NewLabel("")
+	label.SetPosition(10, 10)
+	label.SetColor(math32.NewColor(1, 1, 1))
+	label.SetBgColor(math32.NewColor(0, 0, 0))
+	root.Add(label)
+
+	for !win.ShouldClose() {
+		win.Dispatch(window.OnFrame, nil)
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		// The label shows the statistics of the previous frame
+		stats := rend.Stats()
+		label.SetText(fmt.Sprintf("graphics:%d culled:%d draws:%d tris:%d programs:%d textures:%d\n"+
+			"cull:%v sort:%v shadows:%v draw:%v post:%v total:%v",
+			stats.Graphics, stats.Culled, stats.Drawcalls, stats.Triangles, stats.Programs, stats.Texbinds,
+			stats.CullTime, stats.SortTime, stats.ShadowTime, stats.DrawTime, stats.PostTime, stats.Time))
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package renderer
+
+import (
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// IPostPass is the interface for the full screen passes applied in order by
+// the renderer to the rendered scene. Each pass draws a triangle covering the
+// viewport with the program returned by Shader, which must use the vertex
+// shader "shaderPostVertex" and a fragment shader which includes the "post"
+// chunk with the PostTexture sampler with the result of the previous pass,
+// the PostTexelSize uniform and the FragTexcoord input.
+// RenderSetup is called after the program is set to transfer the uniforms
+// of the pass for the specified viewport size in pixels.
+type IPostPass interface {
+	Shader() string
+	RenderSetup(gs *gls.GLS, width, height int)
+}
+
+// PostPass is a post processing pass without uniforms of its own,
+// which can be embedded in other pass types.
+// For example, to add a pass which inverts the colors:
+//
+//	rend.AddShader("invertFrag", `
+//	#version {{.Version}}
+//	{{template "post" .}}
+//	void main() {
+//	    vec4 color = texture(PostTexture, FragTexcoord);
+//	    FragColor = vec4(1.0 - color.rgb, color.a);
+//	}
+//	`)
+//	rend.AddProgram("invert", "shaderPostVertex", "invertFrag")
+//	rend.AddPass(renderer.NewPostPass("invert"))
+type PostPass struct {
+	shader string // program name
+}
+
+// NewPostPass creates and returns a pointer to a new
+// post processing pass using the specified program
+func NewPostPass(shader string) *PostPass {
+
+	pp := new(PostPass)
+	pp.shader = shader
+	return pp
+}
+
+// Shader satisfies the IPostPass interface and
+// returns the program name of this pass
+func (pp *PostPass) Shader() string {
+
+	return pp.shader
+}
+
+// RenderSetup satisfies the IPostPass interface
+func (pp *PostPass) RenderSetup(gs *gls.GLS, width, height int) {
+}
+
+// FXAAPass is a post processing pass which smooths the edges of the
+// triangles using fast approximate anti-aliasing. It should be applied
+// to colors in the [0, 1] range, after any tone mapping pass.
+type FXAAPass struct {
+	PostPass // Embedded post pass
+}
+
+// NewFXAAPass creates and returns a pointer to a new FXAA pass
+func NewFXAAPass() *FXAAPass {
+
+	fp := new(FXAAPass)
+	fp.shader = "shaderPostFXAA"
+	return fp
+}
+
+// TonemapPass is a post processing pass which maps the high dynamic
+// range colors of the scene to the [0, 1] range with the ACES filmic
+// curve after multiplying them by an exposure.
+type TonemapPass struct {
+	PostPass                // Embedded post pass
+	uExposure gls.Uniform1f // Exposure uniform
+}
+
+// NewTonemapPass creates and returns a pointer to a new
+// tone mapping pass with the specified exposure
+func NewTonemapPass(exposure float32) *TonemapPass {
+
+	tp := new(TonemapPass)
+	tp.shader = "shaderPostTonemap"
+	tp.uExposure.Init("PostExposure")
+	tp.uExposure.Set(exposure)
+	return tp
+}
+
+// SetExposure sets the factor multiplying the colors before tone mapping
+func (tp *TonemapPass) SetExposure(exposure float32) {
+
+	tp.uExposure.Set(exposure)
+}
+
+// RenderSetup satisfies the IPostPass interface
+func (tp *TonemapPass) RenderSetup(gs *gls.GLS, width, height int) {
+
+	tp.uExposure.Transfer(gs)
+}
+
+// postProcess contains the post processing passes of the renderer
+// and the offscreen buffers used by them
+type postProcess struct {
+	passes   []IPostPass      // Ordered post processing passes
+	targets  [2]*RenderTarget // Ping pong buffers with high dynamic range colors
+	vao      uint32           // Empty vertex array object to draw the full screen triangle
+	specs    ShaderSpecs      // Shader specs of the current pass
+	uTexture gls.Uniform1i    // Previous pass texture uniform
+	uTexel   gls.Uniform2f    // Texel size uniform
+}
+
+// init initializes the post processing state
+func (pp *postProcess) init() {
+
+	pp.passes = make([]IPostPass, 0)
+	pp.specs.ShaderUnique = true
+	pp.uTexture.Init("PostTexture")
+	pp.uTexel.Init("PostTexelSize")
+}
+
+// AddPass appends the specified pass to the post processing passes. When there
+// are passes the scene is rendered into an offscreen buffer with floating point
+// colors and the passes are applied in order, each to the result of the previous
+// one, with the last pass drawing into the current framebuffer.
+// For example, to tone map and smooth the edges of the scene:
+//
+//	rend.AddPass(renderer.NewTonemapPass(1.0))
+//	rend.AddPass(renderer.NewFXAAPass())
+func (r *Renderer) AddPass(pass IPostPass) {
+
+	r.post.passes = append(r.post.passes, pass)
+}
+
+// RemovePass removes the specified pass from the post processing passes.
+// Returns true if found or false otherwise.
+func (r *Renderer) RemovePass(pass IPostPass) bool {
+
+	for pos, curr := range r.post.passes {
+		if curr == pass {
+			copy(r.post.passes[pos:], r.post.passes[pos+1:])
+			r.post.passes[len(r.post.passes)-1] = nil
+			r.post.passes = r.post.passes[:len(r.post.passes)-1]
+			return true
+		}
+	}
+	return false
+}
+
+// Passes returns the current post processing passes
+func (r *Renderer) Passes() []IPostPass {
+
+	return r.post.passes
+}
+
+// renderPost renders the specified scene into an offscreen buffer
+// and applies the post processing passes
+func (r *Renderer) renderPost(iscene core.INode, icam camera.ICamera) error {
+
+	pp := &r.post
+	_, _, width, height := r.gs.GetViewport()
+
+	// Creates or resizes the offscreen buffers with the viewport size
+	if pp.targets[0] == nil {
+		pp.targets[0] = NewRenderTarget(int(width), int(height), gls.RGBA16F, true)
+		pp.targets[1] = NewRenderTarget(int(width), int(height), gls.RGBA16F, false)
+		pp.vao = r.gs.GenVertexArray()
+	}
+	for _, target := range pp.targets {
+		target.SetSize(int(width), int(height))
+	}
+
+	// Renders the scene with the current clear color as background
+	var clear [4]float32
+	r.gs.GetFloatv(gls.COLOR_CLEAR_VALUE, &clear[0])
+	pp.targets[0].SetClearColor(&math32.Color4{clear[0], clear[1], clear[2], clear[3]})
+	err := r.RenderToTarget(pp.targets[0], iscene, icam)
+	if err != nil {
+		return err
+	}
+
+	// Applies the passes alternating the offscreen buffers
+	r.gs.Disable(gls.DEPTH_TEST)
+	r.gs.Disable(gls.BLEND)
+	r.gs.Disable(gls.CULL_FACE)
+	r.gs.PolygonMode(gls.FRONT_AND_BACK, gls.FILL)
+	r.gs.BindVertexArray(pp.vao)
+	src := 0
+	for i, pass := range pp.passes {
+		last := i == len(pp.passes)-1
+		dst := pp.targets[1-src]
+		if !last {
+			dst.begin(r.gs)
+		}
+		pp.specs.Name = pass.Shader()
+		_, err = r.shaman.SetProgram(&pp.specs)
+		if err == nil {
+			pp.targets[src].Texture().Bind(r.gs, 0)
+			pp.uTexture.Set(0)
+			pp.uTexture.Transfer(r.gs)
+			pp.uTexel.Set(1/float32(width), 1/float32(height))
+			pp.uTexel.Transfer(r.gs)
+			pass.RenderSetup(r.gs, int(width), int(height))
+			r.gs.DrawArrays(gls.TRIANGLES, 0, 3)
+		}
+		if !last {
+			dst.end(r.gs)
+		}
+		if err != nil {
+			return err
+		}
+		src = 1 - src
+	}
+	return nil
+}
@@ -9,7 +9,12 @@ import (
 	"github.com/g3n/engine/core"
 	"github.com/g3n/engine/gls"
 	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/gui"
 	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"sort"
+	"time"
 )
 
 type Renderer struct {
@@ -19,10 +24,27 @@ type Renderer struct {
 	dirLights   []*light.Directional       // Array of directional lights for last scene
 	pointLights []*light.Point             // Array of point
 	spotLights  []*light.Spot              // Array of spot lights for the scene
+	rectLights  []*light.RectArea          // Array of rect area lights for the scene
 	others      []core.INode               // Other nodes (audio, players, etc)
 	grmats      []*graphic.GraphicMaterial // Array of all graphic materials for scene
+	casters     []*graphic.GraphicMaterial // Array of graphic materials which cast shadows
+	panels      []*graphic.GraphicMaterial // Array of graphic materials of the GUI panels
+	items       []renderItem               // Preallocated array to sort the graphic materials
+	frustum     math32.Frustum             // View volume of the camera
+	stats       RenderStats                // Statistics of the last rendered scene
 	rinfo       core.RenderInfo            // Preallocated Render info
 	specs       ShaderSpecs                // Preallocated Shader specs
+	shadowInfo  core.RenderInfo            // Preallocated Render info for the shadow maps
+	shadowSpecs ShaderSpecs                // Shader specs for the shadow maps
+	post        postProcess                // Post processing passes and buffers
+}
+
+// renderItem contains a graphic material and its sort keys
+type renderItem struct {
+	grmat       *graphic.GraphicMaterial // Graphic material to render
+	order       int                      // Render order of the material
+	transparent bool                     // Material is transparent
+	depth       float32                  // Distance of the graphic in front of the camera
 }
 
 func NewRenderer(gs *gls.GLS) *Renderer {
@@ -35,8 +57,15 @@ func NewRenderer(gs *gls.GLS) *Renderer {
 	r.dirLights = make([]*light.Directional, 0)
 	r.pointLights = make([]*light.Point, 0)
 	r.spotLights = make([]*light.Spot, 0)
+	r.rectLights = make([]*light.RectArea, 0)
 	r.others = make([]core.INode, 0)
 	r.grmats = make([]*graphic.GraphicMaterial, 0)
+	r.casters = make([]*graphic.GraphicMaterial, 0)
+	r.panels = make([]*graphic.GraphicMaterial, 0)
+	r.items = make([]renderItem, 0)
+	r.shadowSpecs.Name = "shaderShadow"
+	r.shadowSpecs.ShaderUnique = true
+	r.post.init()
 
 	return r
 }
@@ -69,8 +98,33 @@ func (r *Renderer) SetProgramShader(pname string, stype int, sname string) error
 	return r.shaman.SetProgramShader(pname, stype, sname)
 }
 
+// Render renders the specified scene viewed by the specified camera into the
+// current framebuffer, applying the post processing passes if there are any.
+// Only the graphics which have a layer in common with the camera are drawn.
 func (r *Renderer) Render(iscene core.INode, icam camera.ICamera) error {
 
+	if len(r.post.passes) > 0 {
+		start := time.Now()
+		var glStart gls.Stats
+		r.gs.Stats(&glStart)
+		err := r.renderPost(iscene, icam)
+		// The scene statistics are extended with the post processing passes
+		r.stats.PostTime = time.Since(start) - r.stats.Time
+		r.stats.setCounters(r.gs, &glStart)
+		r.stats.Time = time.Since(start)
+		return err
+	}
+	return r.render(iscene, icam)
+}
+
+// render renders the specified scene viewed by the
+// specified camera without post processing
+func (r *Renderer) render(iscene core.INode, icam camera.ICamera) error {
+
+	start := time.Now()
+	var glStart gls.Stats
+	r.gs.Stats(&glStart)
+
 	// Updates world matrices of all scene nodes
 	iscene.UpdateMatrixWorld()
 	scene := iscene.GetNode()
@@ -78,14 +132,24 @@ func (r *Renderer) Render(iscene core.INode, icam camera.ICamera) error {
 	// Builds RenderInfo calls RenderSetup for all visible nodes
 	icam.ViewMatrix(&r.rinfo.ViewMatrix)
 	icam.ProjMatrix(&r.rinfo.ProjMatrix)
+	var viewProj math32.Matrix4
+	viewProj.MultiplyMatrices(&r.rinfo.ProjMatrix, &r.rinfo.ViewMatrix)
+	r.frustum.SetFromMatrix(&viewProj)
 
 	// Clear scene arrays
 	r.ambLights = r.ambLights[0:0]
 	r.dirLights = r.dirLights[0:0]
 	r.pointLights = r.pointLights[0:0]
 	r.spotLights = r.spotLights[0:0]
+	r.rectLights = r.rectLights[0:0]
 	r.others = r.others[0:0]
 	r.grmats = r.grmats[0:0]
+	r.casters = r.casters[0:0]
+	r.panels = r.panels[0:0]
+	r.stats = RenderStats{}
+
+	// Only the graphics in the layers of the camera are drawn
+	layers := icam.GetCamera().Layers()
 
 	// Internal function to classify a node and its children
 	var classifyNode func(inode core.INode)
@@ -100,12 +164,27 @@ func (r *Renderer) Render(iscene core.INode, icam camera.ICamera) error {
 		// Checks if node is a Graphic
 		igr, ok := inode.(graphic.IGraphic)
 		if ok {
-			if igr.Renderable() {
-				// Appends to list each graphic material for this graphic
+			if igr.Renderable() && node.Layers().Test(layers) {
+				// Appends to list each graphic material for this graphic if visible.
+				// Graphics outside the camera view may still cast visible shadows.
 				gr := igr.GetGraphic()
+				visible := !gr.FrustumCulled() || r.inFrustum(gr)
+				if visible {
+					r.stats.Graphics++
+				} else {
+					r.stats.Culled++
+				}
+				_, panel := igr.(gui.IPanel)
 				materials := gr.Materials()
 				for i := 0; i < len(materials); i++ {
-					r.grmats = append(r.grmats, &materials[i])
+					if panel {
+						r.panels = append(r.panels, &materials[i])
+					} else if visible {
+						r.grmats = append(r.grmats, &materials[i])
+					}
+					if gr.CastShadow() {
+						r.casters = append(r.casters, &materials[i])
+					}
 				}
 			}
 			// Node is not a Graphic
@@ -122,6 +201,8 @@ func (r *Renderer) Render(iscene core.INode, icam camera.ICamera) error {
 					r.pointLights = append(r.pointLights, l)
 				case *light.Spot:
 					r.spotLights = append(r.spotLights, l)
+				case *light.RectArea:
+					r.rectLights = append(r.rectLights, l)
 				default:
 					panic("Invalid light type")
 				}
@@ -139,12 +220,51 @@ func (r *Renderer) Render(iscene core.INode, icam camera.ICamera) error {
 
 	// Classify all scene nodes
 	classifyNode(scene)
+	cullEnd := time.Now()
+	r.stats.CullTime = cullEnd.Sub(start)
+
+	// Sorts the graphic materials in the render order
+	// and appends the GUI panels to be rendered last
+	r.sortGraphics()
+	r.grmats = append(r.grmats, r.panels...)
+	sortEnd := time.Now()
+	r.stats.SortTime = sortEnd.Sub(cullEnd)
 
 	// Sets lights count in shader specs
 	r.specs.AmbientLightsMax = len(r.ambLights)
 	r.specs.DirLightsMax = len(r.dirLights)
 	r.specs.PointLightsMax = len(r.pointLights)
 	r.specs.SpotLightsMax = len(r.spotLights)
+	r.specs.SpotCookiesMax = 0
+	for _, l := range r.spotLights {
+		if l.Cookie() != nil {
+			r.specs.SpotCookiesMax++
+		}
+	}
+	r.specs.RectAreaLightsMax = len(r.rectLights)
+
+	// Renders the shadow maps of the lights which cast shadows
+	r.specs.DirShadowsMax = 0
+	for _, l := range r.dirLights {
+		if l.CastShadow() {
+			r.specs.DirShadowsMax++
+			if err := r.renderShadowMap(l); err != nil {
+				return err
+			}
+		}
+	}
+	r.specs.SpotShadowsMax = 0
+	for _, l := range r.spotLights {
+		if l.CastShadow() {
+			r.specs.SpotShadowsMax++
+			if err := r.renderShadowMap(l); err != nil {
+				return err
+			}
+		}
+	}
+
+	shadowEnd := time.Now()
+	r.stats.ShadowTime = shadowEnd.Sub(sortEnd)
 
 	// Render other nodes (audio players, etc)
 	for i := 0; i < len(r.others); i++ {
@@ -165,6 +285,9 @@ func (r *Renderer) Render(iscene core.INode, icam camera.ICamera) error {
 		r.specs.ShaderUnique = mat.ShaderUnique()
 		r.specs.UseLights = mat.UseLights()
 		r.specs.MatTexturesMax = mat.TextureCount()
+		r.specs.MatTexArraysMax = mat.TexArrayCount()
+		r.specs.MatCubemapsMax = mat.CubemapCount()
+		r.specs.Instanced = grmat.GetGraphic().GetGraphic().Instanced()
 		_, err := r.shaman.SetProgram(&r.specs)
 		if err != nil {
 			return err
@@ -174,18 +297,136 @@ func (r *Renderer) Render(iscene core.INode, icam camera.ICamera) error {
 		for idx, l := range r.ambLights {
 			l.RenderSetup(r.gs, &r.rinfo, idx)
 		}
+		// The shadow maps and the cookies of the spot lights use
+		// the texture units after the material textures
+		unit := mat.TextureCount() + mat.TexArrayCount() + mat.CubemapCount()
+		shadows := 0
 		for idx, l := range r.dirLights {
+			if l.CastShadow() && (mat.UseLights()&material.UseLightDirectional) != 0 {
+				l.ShadowSetup(r.gs, &r.rinfo, unit, shadows)
+				unit++
+				shadows++
+			}
 			l.RenderSetup(r.gs, &r.rinfo, idx)
 		}
 		for idx, l := range r.pointLights {
 			l.RenderSetup(r.gs, &r.rinfo, idx)
 		}
+		cookies := 0
+		shadows = 0
 		for idx, l := range r.spotLights {
+			if (mat.UseLights() & material.UseLightSpot) != 0 {
+				if l.Cookie() != nil {
+					l.CookieSetup(r.gs, &r.rinfo, unit, cookies)
+					unit++
+					cookies++
+				}
+				if l.CastShadow() {
+					l.ShadowSetup(r.gs, &r.rinfo, unit, shadows)
+					unit++
+					shadows++
+				}
+			}
+			l.RenderSetup(r.gs, &r.rinfo, idx)
+		}
+		for idx, l := range r.rectLights {
 			l.RenderSetup(r.gs, &r.rinfo, idx)
 		}
 
+		// Transfers the camera view matrix to materials which need it
+		if vmat, ok := grmat.GetMaterial().(material.IViewMaterial); ok {
+			vmat.SetViewMatrix(&r.rinfo.ViewMatrix)
+		}
+
 		// Render this graphic material
 		grmat.Render(r.gs, &r.rinfo)
 	}
+	r.stats.DrawTime = time.Since(shadowEnd)
+	r.stats.setCounters(r.gs, &glStart)
+	r.stats.Time = time.Since(start)
+	return nil
+}
+
+// renderShadowMap renders the depth of the graphics which cast shadows
+// from the point of view of the specified light into its shadow map.
+func (r *Renderer) renderShadowMap(l light.IShadowLight) error {
+
+	x, y, width, height := r.gs.GetViewport()
+	l.ShadowCamera(&r.shadowInfo)
+	sm := l.ShadowMap()
+	sm.Begin(r.gs)
+	r.gs.Enable(gls.DEPTH_TEST)
+	r.gs.DepthFunc(gls.LEQUAL)
+	r.gs.Disable(gls.CULL_FACE)
+	r.gs.Disable(gls.BLEND)
+	r.gs.PolygonMode(gls.FRONT_AND_BACK, gls.FILL)
+	for _, grmat := range r.casters {
+		gr := grmat.GetGraphic().GetGraphic()
+		r.shadowSpecs.Instanced = gr.Instanced()
+		_, err := r.shaman.SetProgram(&r.shadowSpecs)
+		if err != nil {
+			sm.End(r.gs, x, y, width, height)
+			return err
+		}
+		grmat.RenderGeometry(r.gs, &r.shadowInfo)
+	}
+	sm.End(r.gs, x, y, width, height)
 	return nil
 }
+
+// inFrustum returns if the bounding sphere of the specified graphic is inside
+// or intersects the camera view volume. Graphics without vertex positions
+// have no bounds and are considered visible.
+func (r *Renderer) inFrustum(gr *graphic.Graphic) bool {
+
+	geom := gr.GetGeometry()
+	if geom.VBO("VertexPosition") == nil {
+		return true
+	}
+	sphere := geom.BoundingSphere()
+	matrixWorld := gr.MatrixWorld()
+	sphere.ApplyMatrix4(&matrixWorld)
+	return r.frustum.IntersectsSphere(&sphere)
+}
+
+// sortGraphics sorts the graphic materials to render by the render order of
+// their materials, drawing the opaque materials before the transparent ones
+// with the same order. The opaque graphics are sorted from front to back to
+// reduce the overdraw and the transparent graphics from back to front so they
+// are blended over the graphics behind them.
+func (r *Renderer) sortGraphics() {
+
+	r.items = r.items[0:0]
+	for _, grmat := range r.grmats {
+		gr := grmat.GetGraphic().GetGraphic()
+		mat := grmat.GetMaterial().GetMaterial()
+		var pos math32.Vector3
+		matrixWorld := gr.MatrixWorld()
+		pos.SetFromMatrixPosition(&matrixWorld)
+		pos.ApplyMatrix4(&r.rinfo.ViewMatrix)
+		r.items = append(r.items, renderItem{
+			grmat:       grmat,
+			order:       mat.RenderOrder(),
+			transparent: mat.Transparent(),
+			depth:       -pos.Z,
+		})
+	}
+	sort.SliceStable(r.items, func(i, j int) bool {
+		a := &r.items[i]
+		b := &r.items[j]
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		if a.transparent != b.transparent {
+			return b.transparent
+		}
+		if a.transparent {
+			return a.depth > b.depth
+		}
+		return a.depth < b.depth
+	})
+	for i := range r.items {
+		r.grmats[i] = r.items[i].grmat
+		r.items[i].grmat = nil
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package renderer_test
+
+import (
+	"flag"
+	"runtime"
+	"testing"
+
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
+	"github.com/g3n/engine/texture"
+	"github.com/g3n/engine/window"
+)
+
+// The tests which need a window and an OpenGL context only run with -gl
+var glTests = flag.Bool("gl", false, "run the tests which need an OpenGL context")
+
+// newGLRenderer creates a window with the specified size and a renderer for
+// its OpenGL context or skips the test if the -gl flag is not set. The calling
+// goroutine is locked to its thread, as OpenGL calls must be made from the
+// thread which created the context, and the returned function must be called
+// when the test ends to destroy the window and unlock the thread.
+func newGLRenderer(tb testing.TB, width, height int) (window.IWindow, *gls.GLS, *renderer.Renderer, func()) {
+
+	if !*glTests {
+		tb.Skip("needs an OpenGL context, run with -gl")
+	}
+	runtime.LockOSThread()
+	win, err := window.New("glfw", width, height, tb.Name(), false)
+	if err != nil {
+		runtime.UnlockOSThread()
+		tb.Skipf("OpenGL window not available: %v", err)
+	}
+	end := func() {
+		win.Destroy()
+		runtime.UnlockOSThread()
+	}
+	gs, err := gls.New()
+	if err != nil {
+		end()
+		tb.Skipf("OpenGL not available: %v", err)
+	}
+	rend := renderer.NewRenderer(gs)
+	err = rend.AddDefaultShaders()
+	if err != nil {
+		end()
+		tb.Fatal(err)
+	}
+	return win, gs, rend, end
+}
+
+func TestDisposeTreeReleasesResources(t *testing.T) {
+
+	_, gs, rend, end := newGLRenderer(t, 64, 64)
+	defer end()
+	cam := camera.NewPerspective(60, 1, 0.1, 100)
+	cam.SetPosition(0, 0, 5)
+	scene := core.NewNode()
+	scene.SetScene(true)
+
+	var before gls.Stats
+	gs.Stats(&before)
+
+	// A level with a geometry and a textured material shared by
+	// its meshes and a mesh with its own geometry and material
+	level := core.NewNode()
+	geom := geometry.NewBox(1, 1, 1, 1, 1, 1)
+	mat := material.NewStandard(math32.NewColor(1, 1, 1))
+	mat.AddTexture(texture.NewBoard(8, 8, math32.NewColor(1, 1, 1), math32.NewColor(0, 0, 0),
+		math32.NewColor(0, 0, 0), math32.NewColor(1, 1, 1), 1))
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			geom.Incref()
+			mat.Incref()
+		}
+		mesh := graphic.NewMesh(geom, mat)
+		mesh.SetPosition(float32(i)-1, 0, 0)
+		level.Add(mesh)
+	}
+	level.Add(graphic.NewMesh(geometry.NewSphere(0.5, 8, 8, 0, 2*math32.Pi, 0, math32.Pi),
+		material.NewStandard(math32.NewColor(1, 0, 0))))
+	scene.Add(level)
+	err := rend.Render(scene, cam)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rendered gls.Stats
+	gs.Stats(&rendered)
+	if rendered.Vaos <= before.Vaos || rendered.Buffers <= before.Buffers || rendered.Textures <= before.Textures {
+		t.Fatalf("rendering created vaos:%d buffers:%d textures:%d, want more than vaos:%d buffers:%d textures:%d",
+			rendered.Vaos, rendered.Buffers, rendered.Textures, before.Vaos, before.Buffers, before.Textures)
+	}
+
+	var removed bool
+	level.Subscribe(core.OnRemoved, func(evname string, ev interface{}) {
+		removed = true
+	})
+	core.DisposeTree(level)
+	if !removed {
+		t.Errorf("OnRemoved not dispatched to the disposed level")
+	}
+	var after gls.Stats
+	gs.Stats(&after)
+	if after.Vaos != before.Vaos || after.Buffers != before.Buffers || after.Textures != before.Textures {
+		t.Errorf("after DisposeTree vaos:%d buffers:%d textures:%d, want vaos:%d buffers:%d textures:%d",
+			after.Vaos, after.Buffers, after.Textures, before.Vaos, before.Buffers, before.Textures)
+	}
+}
+
+func TestGeometrySharedByWindows(t *testing.T) {
+
+	win, gs, rend, end := newGLRenderer(t, 64, 64)
+	defer end()
+	win2, err := window.New("glfw", 64, 64, t.Name()+" 2", false)
+	if err != nil {
+		t.Skipf("second OpenGL window not available: %v", err)
+	}
+	defer win2.Destroy()
+	gs2, err := gls.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rend2 := renderer.NewRenderer(gs2)
+	err = rend2.AddDefaultShaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cam := camera.NewPerspective(60, 1, 0.1, 100)
+	cam.SetPosition(0, 0, 5)
+	geom := geometry.NewBox(1, 1, 1, 1, 1, 1)
+	mat := material.NewStandard(math32.NewColor(1, 1, 1))
+
+	// The geometry has a vertex array object in each context
+	var before, before2 gls.Stats
+	gs.Stats(&before)
+	gs2.Stats(&before2)
+	scenes := make([]*core.Node, 2)
+	for i := range scenes {
+		scenes[i] = core.NewNode()
+		if i > 0 {
+			geom.Incref()
+			mat.Incref()
+		}
+		scenes[i].Add(graphic.NewMesh(geom, mat))
+	}
+	for _, w := range []struct {
+		win   window.IWindow
+		rend  *renderer.Renderer
+		scene *core.Node
+	}{{win, rend, scenes[0]}, {win2, rend2, scenes[1]}, {win, rend, scenes[0]}} {
+		w.win.MakeContextCurrent()
+		err := w.rend.Render(w.scene, cam)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var rendered, rendered2 gls.Stats
+	gs.Stats(&rendered)
+	gs2.Stats(&rendered2)
+	if rendered.Vaos != before.Vaos+1 || rendered2.Vaos != before2.Vaos+1 {
+		t.Errorf("rendering in both windows created vaos:%d and %d, want 1 and 1",
+			rendered.Vaos-before.Vaos, rendered2.Vaos-before2.Vaos)
+	}
+	if rendered2.Buffers != before2.Buffers {
+		t.Errorf("rendering in the second window created %d buffers, want 0", rendered2.Buffers-before2.Buffers)
+	}
+
+	// Disposing the geometry deletes the vertex arrays of both contexts
+	core.DisposeTree(scenes[1])
+	core.DisposeTree(scenes[0])
+	var after, after2 gls.Stats
+	gs.Stats(&after)
+	gs2.Stats(&after2)
+	if after.Vaos != before.Vaos || after2.Vaos != before2.Vaos {
+		t.Errorf("after DisposeTree vaos:%d and %d, want %d and %d", after.Vaos, after2.Vaos, before.Vaos, before2.Vaos)
+	}
+}
+
+// The benchmarks compare the time per frame of rendering 10000 quads as
+// individual meshes, with one draw call and one set of uniforms each, and
+// as the instances of one mesh, drawn with a single draw call. The frames are
+// swapped without waiting for the vertical sync, so the time includes the
+// GPU work once its command queue is full.
+const benchmarkObjects = 10000
+
+// benchmarkRender renders the specified scene once for each iteration
+// of the specified benchmark and reports the draw calls per frame
+func benchmarkRender(b *testing.B, win window.IWindow, gs *gls.GLS, rend *renderer.Renderer, scene *core.Node) {
+
+	cam := camera.NewPerspective(60, 1, 0.1, 1000)
+	cam.SetPosition(50, 50, 120)
+	cam.LookAt(&math32.Vector3{X: 50, Y: 50})
+	win.SwapInterval(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			b.Fatal(err)
+		}
+		win.SwapBuffers()
+	}
+	b.ReportMetric(float64(rend.Stats().Drawcalls), "draws/frame")
+}
+
+func BenchmarkRenderMeshes(b *testing.B) {
+
+	win, gs, rend, end := newGLRenderer(b, 512, 512)
+	defer end()
+	scene := core.NewNode()
+	geom := geometry.NewPlane(0.8, 0.8, 1, 1)
+	mat := material.NewBasic()
+	for i := 0; i < benchmarkObjects; i++ {
+		mesh := graphic.NewMesh(geom, mat)
+		mesh.SetPosition(float32(i%100), float32(i/100), 0)
+		scene.Add(mesh)
+	}
+	benchmarkRender(b, win, gs, rend, scene)
+}
+
+func BenchmarkRenderInstancedMesh(b *testing.B) {
+
+	win, gs, rend, end := newGLRenderer(b, 512, 512)
+	defer end()
+	scene := core.NewNode()
+	mesh := graphic.NewInstancedMesh(geometry.NewPlane(0.8, 0.8, 1, 1), material.NewBasic(), benchmarkObjects)
+	for i := 0; i < benchmarkObjects; i++ {
+		var m math32.Matrix4
+		m.MakeTranslation(float32(i%100), float32(i/100), 0)
+		mesh.SetInstanceMatrix(i, &m)
+	}
+	scene.Add(mesh)
+	benchmarkRender(b, win, gs, rend, scene)
+}
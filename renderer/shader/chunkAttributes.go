@@ -16,4 +16,10 @@ layout(location = 2) in vec3  VertexColor;
 layout(location = 3) in vec2  VertexTexcoord;
 layout(location = 4) in float VertexDistance;
 layout(location = 5) in vec4  VertexTexoffsets;
+layout(location = 6) in vec2  VertexTexcoord2;
+{{if .Instanced}}
+// Instance attributes
+layout(location = 7) in mat4  InstanceMatrix;
+layout(location = 11) in vec3 InstanceColor;
+{{end}}
 `
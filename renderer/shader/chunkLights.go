@@ -9,18 +9,63 @@ func init() {
 }
 
 const chunkLights = `
+{{if or .DirShadowsMax .SpotShadowsMax}}
+// Returns the fraction from 0 to 1 of the light which reaches the specified
+// position in camera coordinates using 3x3 percentage closer filtering of the
+// specified shadow map. The depth bias is increased for surfaces at grazing
+// angles from the light, which have the specified cosine with the light direction.
+float shadowPCF(sampler2DShadow shadowMap, mat4 shadowMatrix, vec3 position, float bias, float dotNormal) {
+
+    vec4 coord = shadowMatrix * vec4(position, 1.0);
+    vec3 proj = coord.xyz / coord.w;
+    if (coord.w <= 0.0 || proj.z > 1.0 || proj.x < 0.0 || proj.x > 1.0 || proj.y < 0.0 || proj.y > 1.0) {
+        return 1.0;
+    }
+    float depth = proj.z - bias * (2.0 - dotNormal);
+    vec2 texel = 1.0 / vec2(textureSize(shadowMap, 0));
+    float lit = 0.0;
+    for (int x = -1; x <= 1; x++) {
+        for (int y = -1; y <= 1; y++) {
+            lit += texture(shadowMap, vec3(proj.xy + vec2(x, y) * texel, depth));
+        }
+    }
+    return lit / 9.0;
+}
+{{end}}
+
 {{if .AmbientLightsMax}}
 // Ambient lights uniforms
 uniform vec3 AmbientLightColor[{{.AmbientLightsMax}}];
 {{end}}
 
 {{if .DirLightsMax}}
-// Directional lights uniform array. Each directional light uses 2 elements
-uniform vec3  DirLight[2*{{.DirLightsMax}}];
+// Directional lights uniform array. Each directional light uses 3 elements
+uniform vec3  DirLight[3*{{.DirLightsMax}}];
 
 // Macros to access elements inside the DirectionalLight uniform array
-#define DirLightColor(a)		DirLight[2*a]
-#define DirLightPosition(a)		DirLight[2*a+1]
+#define DirLightColor(a)		DirLight[3*a]
+#define DirLightPosition(a)		DirLight[3*a+1]
+#define DirLightShadowIndex(a)	int(DirLight[3*a+2].x)
+#define DirLightShadowBias(a)	DirLight[3*a+2].y
+
+{{if .DirShadowsMax}}
+// Directional lights shadow maps uniforms
+uniform sampler2DShadow	DirShadowMap[{{.DirShadowsMax}}];
+uniform mat4			DirShadowMatrix[{{.DirShadowsMax}}];
+{{end}}
+
+// Returns the fraction of the light which reaches the specified position from
+// the directional light with the specified shadow map index or 1 if the light
+// has no shadow map.
+float dirLightShadow(int idx, vec3 position, float bias, float dotNormal) {
+
+    {{ range loop .DirShadowsMax }}
+    if (idx == {{.}}) {
+        return shadowPCF(DirShadowMap[{{.}}], DirShadowMatrix[{{.}}], position, bias, dotNormal);
+    }
+    {{ end }}
+    return 1.0;
+}
 {{end}}
 
 {{if .PointLightsMax}}
@@ -35,16 +80,89 @@ uniform vec3  PointLight[3*{{.PointLightsMax}}];
 {{end}}
 
 {{if .SpotLightsMax}}
-// Spot lights uniforms. Each spot light uses 5 elements
-uniform vec3  SpotLight[5*{{.SpotLightsMax}}];
+// Spot lights uniforms. Each spot light uses 6 elements
+uniform vec3  SpotLight[6*{{.SpotLightsMax}}];
 
 // Macros to access elements inside the PointLight uniform array
-#define SpotLightColor(a)			SpotLight[5*a]
-#define SpotLightPosition(a)		SpotLight[5*a+1]
-#define SpotLightDirection(a)		SpotLight[5*a+2]
-#define SpotLightAngularDecay(a)	SpotLight[5*a+3].x
-#define SpotLightCutoffAngle(a)		SpotLight[5*a+3].y
-#define SpotLightLinearDecay(a)		SpotLight[5*a+3].z
-#define SpotLightQuadraticDecay(a)	SpotLight[5*a+4].x
+#define SpotLightColor(a)			SpotLight[6*a]
+#define SpotLightPosition(a)		SpotLight[6*a+1]
+#define SpotLightDirection(a)		SpotLight[6*a+2]
+#define SpotLightAngularDecay(a)	SpotLight[6*a+3].x
+#define SpotLightCutoffAngle(a)		SpotLight[6*a+3].y
+#define SpotLightLinearDecay(a)		SpotLight[6*a+3].z
+#define SpotLightQuadraticDecay(a)	SpotLight[6*a+4].x
+#define SpotLightPenumbra(a)		SpotLight[6*a+4].y
+#define SpotLightCookieIndex(a)		int(SpotLight[6*a+4].z)
+#define SpotLightShadowIndex(a)		int(SpotLight[6*a+5].x)
+#define SpotLightShadowBias(a)		SpotLight[6*a+5].y
+
+// Returns the intensity of a spot light between the specified cosine of the
+// cutoff angle of its cone and the cosine of the inner angle of the cone
+// calculated from its penumbra at the specified cosine of the angle from its direction.
+float spotLightFalloff(float cosAngle, float cutoff, float penumbra) {
+
+    float cosOuter = cos(cutoff);
+    float cosInner = cos(cutoff * (1.0 - penumbra));
+    if (cosInner <= cosOuter) {
+        return 1.0;
+    }
+    return smoothstep(cosOuter, cosInner, cosAngle);
+}
+
+{{if .SpotCookiesMax}}
+// Spot lights cookies uniforms
+uniform sampler2D	SpotLightCookie[{{.SpotCookiesMax}}];
+uniform mat4		SpotLightCookieMatrix[{{.SpotCookiesMax}}];
+{{end}}
+
+// Returns the color of the cookie texture with the specified index projected
+// on the specified position in camera coordinates or white if there is no cookie.
+// Use Go templates to unroll the loop because non-const
+// array indexes are not allowed until GLSL 4.00.
+vec3 spotLightCookie(int idx, vec3 position) {
+
+    {{ range loop .SpotCookiesMax }}
+    if (idx == {{.}}) {
+        vec4 proj = SpotLightCookieMatrix[{{.}}] * vec4(position, 1.0);
+        if (proj.w <= 0.0) {
+            return vec3(0.0);
+        }
+        vec2 uv = proj.xy / proj.w * 0.5 + 0.5;
+        uv.y = 1.0 - uv.y;
+        return textureLod(SpotLightCookie[{{.}}], uv, 0.0).rgb;
+    }
+    {{ end }}
+    return vec3(1.0);
+}
+
+{{if .SpotShadowsMax}}
+// Spot lights shadow maps uniforms
+uniform sampler2DShadow	SpotShadowMap[{{.SpotShadowsMax}}];
+uniform mat4			SpotShadowMatrix[{{.SpotShadowsMax}}];
+{{end}}
+
+// Returns the fraction of the light which reaches the specified position from
+// the spot light with the specified shadow map index or 1 if the light
+// has no shadow map.
+float spotLightShadow(int idx, vec3 position, float bias, float dotNormal) {
+
+    {{ range loop .SpotShadowsMax }}
+    if (idx == {{.}}) {
+        return shadowPCF(SpotShadowMap[{{.}}], SpotShadowMatrix[{{.}}], position, bias, dotNormal);
+    }
+    {{ end }}
+    return 1.0;
+}
+{{end}}
+
+{{if .RectAreaLightsMax}}
+// Rect area lights uniform array. Each rect area light uses 4 elements
+uniform vec3  RectAreaLight[4*{{.RectAreaLightsMax}}];
+
+// Macros to access elements inside the RectAreaLight uniform array
+#define RectAreaLightColor(a)		RectAreaLight[4*a]
+#define RectAreaLightPosition(a)	RectAreaLight[4*a+1]
+#define RectAreaLightHalfWidth(a)	RectAreaLight[4*a+2]
+#define RectAreaLightHalfHeight(a)	RectAreaLight[4*a+3]
 {{end}}
 `
@@ -21,6 +21,8 @@ uniform vec3	Material[6];
 #define MatOpacity			Material[4].y
 #define MatPointSize		Material[4].z
 #define MatPointRotationZ	Material[5].x
+#define MatAlphaTest		Material[5].y
+#define MatVertexColors		bool(Material[5].z)
 
 {{if .MatTexturesMax}}
 // Textures uniforms
@@ -32,5 +34,26 @@ uniform mat3		MatTexinfo[{{.MatTexturesMax}}];
 #define MatTexRepeat(a)		MatTexinfo[a][1].xy
 #define MatTexFlipY(a)		bool(MatTexinfo[a][2].x)
 #define MatTexVisible(a)	bool(MatTexinfo[a][2].y)
+#define MatTexUVChannel(a)	int(MatTexinfo[a][2].z)
+{{ end }}
+
+{{if .MatTexArraysMax}}
+// Texture arrays uniforms
+uniform sampler2DArray	MatTexArray[{{.MatTexArraysMax}}];
+uniform mat3			MatTexArrayInfo[{{.MatTexArraysMax}}];
+
+// Macros to access elements inside MatTexArrayInfo uniform
+#define MatTexArrayOffset(a)	MatTexArrayInfo[a][0].xy
+#define MatTexArrayRepeat(a)	MatTexArrayInfo[a][1].xy
+#define MatTexArrayFlipY(a)		bool(MatTexArrayInfo[a][2].x)
+#define MatTexArrayVisible(a)	bool(MatTexArrayInfo[a][2].y)
+
+// Macro to sample the layer with the specified index of a texture array
+#define MatTexArrayTexel(a, uv, layer) texture(MatTexArray[a], vec3(vec2((uv).x, MatTexArrayFlipY(a) ? 1 - (uv).y : (uv).y) * MatTexArrayRepeat(a) + MatTexArrayOffset(a), layer))
+{{ end }}
+
+{{if .MatCubemapsMax}}
+// Cube map textures uniforms
+uniform samplerCube	MatCubemap[{{.MatCubemapsMax}}];
 {{ end }}
 `
@@ -9,6 +9,25 @@ func init() {
 }
 
 const chunkPhongModel = `
+{{if .RectAreaLightsMax}}
+/***
+ Returns the vector form factor of the edge of a polygon light between
+ the specified unit directions to its vertices divided by 2*PI, using
+ the rational approximation of theta/sin(theta) of the linearly
+ transformed cosines method.
+*/
+vec3 rectEdgeFormFactor(vec3 v1, vec3 v2) {
+
+    float x = dot(v1, v2);
+    float y = abs(x);
+    float a = 0.8543985 + (0.4965155 + 0.0145206 * y) * y;
+    float b = 3.4175940 + (4.1616724 + y) * y;
+    float v = a / b;
+    float thetaSintheta = (x > 0.0) ? v : 0.5 * inversesqrt(max(1.0 - x * x, 1e-7)) - v;
+    return cross(v1, v2) * thetaSintheta;
+}
+{{end}}
+
 /***
  phong lighting model
  Parameters:
@@ -27,6 +46,7 @@ const chunkPhongModel = `
     PointLightPosition[];
     PointLightLinearDecay[];
     PointLightQuadraticDecay[];
+    RectAreaLight[]
     MatSpecularColor
     MatShininess
 */
@@ -47,13 +67,15 @@ void phongModel(vec4 position, vec3 normal, vec3 camDir, vec3 matAmbient, vec3 m
         vec3 lightDirection = normalize(DirLightPosition({{.}}));
         // Calculates the dot product between the light direction and this vertex normal.
         float dotNormal = max(dot(lightDirection, normal), 0.0);
-        diffuseTotal += DirLightColor({{.}}) * matDiffuse * dotNormal;
+        // Fraction of the light not blocked by the objects which cast shadows
+        float shadow = dirLightShadow(DirLightShadowIndex({{.}}), vec3(position), DirLightShadowBias({{.}}), dotNormal);
+        diffuseTotal += DirLightColor({{.}}) * matDiffuse * dotNormal * shadow;
 
         // Specular reflection
         // Calculates the light reflection vector 
         vec3 ref = reflect(-lightDirection, normal);
         if (dotNormal > 0.0) {
-            specularTotal += DirLightColor({{.}}) * MatSpecularColor * pow(max(dot(ref, camDir), 0.0), MatShininess);
+            specularTotal += DirLightColor({{.}}) * MatSpecularColor * pow(max(dot(ref, camDir), 0.0), MatShininess) * shadow;
         }
     }
     {{ end }}
@@ -101,16 +123,65 @@ void phongModel(vec4 position, vec3 normal, vec3 camDir, vec3 matAmbient, vec3 m
         float cutoff = radians(clamp(SpotLightCutoffAngle({{.}}), 0.0, 90.0));
 
         if (angle < cutoff) {
-            float spotFactor = pow(dot(-lightDirection, SpotLightDirection({{.}})), SpotLightAngularDecay({{.}}));
+            float cosAngle = dot(-lightDirection, SpotLightDirection({{.}}));
+            float spotFactor = pow(cosAngle, SpotLightAngularDecay({{.}})) *
+                spotLightFalloff(cosAngle, cutoff, SpotLightPenumbra({{.}}));
+            vec3 spotColor = SpotLightColor({{.}}) * spotLightCookie(SpotLightCookieIndex({{.}}), vec3(position));
 
             // Diffuse reflection
             float dotNormal = max(dot(lightDirection, normal), 0.0);
-            diffuseTotal += SpotLightColor({{.}}) * matDiffuse * dotNormal * attenuation * spotFactor;
+            spotFactor *= spotLightShadow(SpotLightShadowIndex({{.}}), vec3(position), SpotLightShadowBias({{.}}), dotNormal);
+            diffuseTotal += spotColor * matDiffuse * dotNormal * attenuation * spotFactor;
 
             // Specular reflection
             vec3 ref = reflect(-lightDirection, normal);
             if (dotNormal > 0.0) {
-                specularTotal += SpotLightColor({{.}}) * MatSpecularColor * pow(max(dot(ref, camDir), 0.0), MatShininess) * attenuation * spotFactor;
+                specularTotal += spotColor * MatSpecularColor * pow(max(dot(ref, camDir), 0.0), MatShininess) * attenuation * spotFactor;
+            }
+        }
+    }
+    {{ end }}
+
+    {{ range loop .RectAreaLightsMax }}
+    {
+        vec3 center = RectAreaLightPosition({{.}});
+        vec3 halfWidth = RectAreaLightHalfWidth({{.}});
+        vec3 halfHeight = RectAreaLightHalfHeight({{.}});
+        vec3 lightNormal = normalize(cross(halfWidth, halfHeight));
+        vec3 pos = vec3(position);
+
+        // The rectangle only emits light to the side of its normal
+        if (dot(pos - center, lightNormal) > 0.0) {
+            // Diffuse reflection from the form factor of the rectangle
+            // calculated from the directions to its corners
+            vec3 c0 = normalize(center - halfWidth - halfHeight - pos);
+            vec3 c1 = normalize(center - halfWidth + halfHeight - pos);
+            vec3 c2 = normalize(center + halfWidth + halfHeight - pos);
+            vec3 c3 = normalize(center + halfWidth - halfHeight - pos);
+            vec3 formFactor = rectEdgeFormFactor(c0, c1) + rectEdgeFormFactor(c1, c2) +
+                rectEdgeFormFactor(c2, c3) + rectEdgeFormFactor(c3, c0);
+            float dotNormal = max(dot(formFactor, normal), 0.0);
+            diffuseTotal += RectAreaLightColor({{.}}) * matDiffuse * dotNormal;
+
+            // Specular reflection from the point of the rectangle closest to
+            // the reflected camera direction, scaled by the solid angle of the
+            // rectangle relative to the width of the specular lobe.
+            vec3 ref = reflect(-camDir, normal);
+            float t = dot(center - pos, lightNormal) / min(dot(ref, lightNormal), -1e-3);
+            vec3 local = pos + ref * t - center;
+            vec2 halfSize = vec2(length(halfWidth), length(halfHeight));
+            vec3 point = center +
+                clamp(dot(local, halfWidth) / halfSize.x, -halfSize.x, halfSize.x) * halfWidth / halfSize.x +
+                clamp(dot(local, halfHeight) / halfSize.y, -halfSize.y, halfSize.y) * halfHeight / halfSize.y;
+            vec3 lightDirection = point - pos;
+            float lightDistance = length(lightDirection);
+            lightDirection = lightDirection / lightDistance;
+            float solidAngle = 4.0 * halfSize.x * halfSize.y * max(dot(-lightDirection, lightNormal), 0.0) /
+                (lightDistance * lightDistance);
+            float lobe = min(solidAngle * (MatShininess + 2.0) / (2.0 * 3.14159265), 1.0);
+            if (dot(lightDirection, normal) > 0.0) {
+                specularTotal += RectAreaLightColor({{.}}) * MatSpecularColor *
+                    pow(max(dot(reflect(-lightDirection, normal), camDir), 0.0), MatShininess) * lobe;
             }
         }
     }
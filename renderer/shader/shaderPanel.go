@@ -59,7 +59,7 @@ uniform mat3		MatTexinfo[1];
 in vec2 FragTexcoord;
 
 // Input uniform
-uniform vec4 Panel[8];
+uniform vec4 Panel[11];
 #define Bounds			Panel[0]		  // panel bounds in texture coordinates
 #define Border			Panel[1]		  // panel border in texture coordinates
 #define Padding			Panel[2]		  // panel padding in texture coordinates
@@ -68,6 +68,9 @@ uniform vec4 Panel[8];
 #define PaddingColor	Panel[5]		  // panel padding color
 #define ContentColor	Panel[6]		  // panel content color
 #define TextureValid	bool(Panel[7].x)  // texture valid flag
+#define Radius			Panel[8]		  // corner radii in pixels (top left, top right, bottom right, bottom left)
+#define Size			Panel[9].xy		  // panel size in pixels
+#define TextureTint		Panel[10]		  // color multiplied by the texture color
 
 // Output
 out vec4 FragColor;
@@ -99,6 +102,56 @@ bool checkRect(vec4 rect) {
 }
 
 
+/***
+* Checks if current fragment texture coordinate is inside the
+* supplied rectangle in texture coordinates with rounded corners
+* with the specified radii in pixels.
+*/
+bool checkRounded(vec4 rect, vec4 radius) {
+
+    if (!checkRect(rect)) {
+        return false;
+    }
+    vec2 p = FragTexcoord * Size;
+    vec2 rmin = rect.xy * Size;
+    vec2 rmax = (rect.xy + rect.zw) * Size;
+    float rlim = min(rmax.x - rmin.x, rmax.y - rmin.y) / 2.0;
+    vec4 r = min(radius, vec4(rlim));
+    vec2 c;
+    c = vec2(rmin.x + r[0], rmin.y + r[0]);
+    if (p.x < c.x && p.y < c.y) {
+        return distance(p, c) <= r[0];
+    }
+    c = vec2(rmax.x - r[1], rmin.y + r[1]);
+    if (p.x > c.x && p.y < c.y) {
+        return distance(p, c) <= r[1];
+    }
+    c = vec2(rmax.x - r[2], rmax.y - r[2]);
+    if (p.x > c.x && p.y > c.y) {
+        return distance(p, c) <= r[2];
+    }
+    c = vec2(rmin.x + r[3], rmax.y - r[3]);
+    if (p.x < c.x && p.y > c.y) {
+        return distance(p, c) <= r[3];
+    }
+    return true;
+}
+
+/***
+* Returns the corner radii for the inner rectangle reduced by
+* the distances in pixels from the outer rectangle.
+*/
+vec4 innerRadius(vec4 outer, vec4 inner, vec4 radius) {
+
+    float left = (inner[0] - outer[0]) * Size.x;
+    float top = (inner[1] - outer[1]) * Size.y;
+    float right = ((outer[0] + outer[2]) - (inner[0] + inner[2])) * Size.x;
+    float bottom = ((outer[1] + outer[3]) - (inner[1] + inner[3])) * Size.y;
+    vec4 d = vec4(max(left, top), max(right, top), max(right, bottom), max(left, bottom));
+    return max(radius - d, vec4(0));
+}
+
+
 void main() {
 
     // Discard fragment outside of received bounds
@@ -113,8 +166,19 @@ void main() {
         discard;
     }
 
+    // Radii of the rounded corners of each area
+    bool rounded = Radius != vec4(0);
+    vec4 paddingRadius = innerRadius(Border, Padding, Radius);
+    vec4 contentRadius = innerRadius(Padding, Content, paddingRadius);
+
+    // Fragment outside of the rounded borders is in the margins area
+    if (rounded && !checkRounded(Border, Radius)) {
+        FragColor = vec4(1,1,1,0);
+        return;
+    }
+
     // Check if fragment is inside content area
-    if (checkRect(Content)) {
+    if (checkRect(Content) && (!rounded || checkRounded(Content, contentRadius))) {
         // If no texture, the color will be the material color.
         vec4 color = ContentColor;
 		if (TextureValid) {
@@ -122,7 +186,7 @@ void main() {
             vec2 offset = vec2(-Content[0], -Content[1]);
             vec2 factor = vec2(1/Content[2], 1/Content[3]);
             vec2 texcoord = (FragTexcoord + offset) * factor;
-            color = texture(MatTexture[0], texcoord * MatTexRepeat(0) + MatTexOffset(0));
+            color = texture(MatTexture[0], texcoord * MatTexRepeat(0) + MatTexOffset(0)) * TextureTint;
 		}
         if (color.a == 0) {
             discard;
@@ -132,7 +196,7 @@ void main() {
     }
 
     // Checks if fragment is inside paddings area
-    if (checkRect(Padding)) {
+    if (checkRect(Padding) && (!rounded || checkRounded(Padding, paddingRadius))) {
         FragColor = PaddingColor;
         return;
     }
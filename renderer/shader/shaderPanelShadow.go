@@ -0,0 +1,65 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shader
+
+func init() {
+	AddShader("shaderPanelShadowFrag", shaderPanelShadowFrag)
+	AddProgram("shaderPanelShadow", "shaderPanelVertex", "shaderPanelShadowFrag")
+}
+
+//
+// Fragment Shader template
+//
+const shaderPanelShadowFrag = `
+#version {{.Version}}
+
+// Inputs from vertex shader
+in vec2 FragTexcoord;
+
+// Input uniform
+uniform vec4 Shadow[5];
+#define Rect			Shadow[0]		  // shadow rectangle in window coordinates (x, y, width, height)
+#define Bounds			Shadow[1]		  // clipping bounds in window coordinates (xmin, ymin, xmax, ymax)
+#define Color			Shadow[2]		  // shadow color
+#define Radius			Shadow[3]		  // corner radii in pixels (top left, top right, bottom right, bottom left)
+#define Blur			Shadow[4].x		  // blur size in pixels
+
+// Output
+out vec4 FragColor;
+
+
+void main() {
+
+    // Discard fragment outside of the clipping bounds
+    vec2 p = gl_FragCoord.xy;
+    if (p.x < Bounds[0] || p.y < Bounds[1] || p.x > Bounds[2] || p.y > Bounds[3]) {
+        discard;
+    }
+
+    // Radius of the corner of the quadrant of the fragment
+    // Window coordinates have the y axis pointing up.
+    vec2 hsize = Rect.zw / 2.0;
+    vec2 q = p - (Rect.xy + hsize);
+    float r;
+    if (q.x < 0) {
+        r = q.y > 0 ? Radius[0] : Radius[3];
+    } else {
+        r = q.y > 0 ? Radius[1] : Radius[2];
+    }
+    r = min(r, min(hsize.x, hsize.y));
+
+    // Signed distance from the fragment to the rounded rectangle
+    vec2 d = abs(q) - hsize + r;
+    float dist = length(max(d, 0.0)) + min(max(d.x, d.y), 0.0) - r;
+
+    // Fades the shadow across the blur size around the rectangle edges
+    float blur = max(Blur, 0.5);
+    float alpha = Color.a * (1.0 - smoothstep(-blur, blur, dist));
+    if (alpha <= 0) {
+        discard;
+    }
+    FragColor = vec4(Color.rgb, alpha);
+}
+`
@@ -30,6 +30,7 @@ out vec4 Position;
 out vec3 Normal;
 out vec3 CamDir;
 out vec2 FragTexcoord;
+out vec2 FragTexcoord2;
 
 void main() {
 
@@ -45,12 +46,15 @@ void main() {
 
     // Flips texture coordinate Y if requested.
     vec2 texcoord = VertexTexcoord;
+    vec2 texcoord2 = VertexTexcoord2;
     {{ if .MatTexturesMax }}
     if (MatTexFlipY(0)) {
         texcoord.y = 1 - texcoord.y;
+        texcoord2.y = 1 - texcoord2.y;
     }
     {{ end }}
     FragTexcoord = texcoord;
+    FragTexcoord2 = texcoord2;
 
     gl_Position = MVP * vec4(VertexPosition, 1.0);
 }
@@ -67,6 +71,7 @@ in vec4 Position;       // Vertex position in camera coordinates.
 in vec3 Normal;         // Vertex normal in camera coordinates.
 in vec3 CamDir;         // Direction from vertex to camera
 in vec2 FragTexcoord;
+in vec2 FragTexcoord2;
 
 {{template "lights" .}}
 {{template "material" .}}
@@ -81,7 +86,7 @@ void main() {
     vec4 texCombined = vec4(1);
     {{ range loop .MatTexturesMax }}
     if (MatTexVisible({{.}})) {
-        vec4 texcolor = texture(MatTexture[{{.}}], FragTexcoord * MatTexRepeat({{.}}) + MatTexOffset({{.}}));
+        vec4 texcolor = texture(MatTexture[{{.}}], (MatTexUVChannel({{.}}) == 1 ? FragTexcoord2 : FragTexcoord) * MatTexRepeat({{.}}) + MatTexOffset({{.}}));
         if ({{.}} == 0) {
             texCombined = texcolor;
         } else {
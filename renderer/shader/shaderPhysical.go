@@ -0,0 +1,328 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shader
+
+func init() {
+	AddShader("shaderPhysicalVertex", shaderPhysicalVertex)
+	AddShader("shaderPhysicalFrag", shaderPhysicalFrag)
+	AddProgram("shaderPhysical", "shaderPhysicalVertex", "shaderPhysicalFrag")
+}
+
+//
+// Vertex Shader template
+//
+const shaderPhysicalVertex = `
+#version {{.Version}}
+
+{{template "attributes" .}}
+
+// Model uniforms
+uniform mat4 ModelViewMatrix;
+uniform mat3 NormalMatrix;
+uniform mat4 MVP;
+
+// Output variables for Fragment shader
+out vec4 Position;
+out vec3 Normal;
+out vec2 FragTexcoord;
+out vec2 FragTexcoord2;
+
+void main() {
+
+    // Transform this vertex position to camera coordinates.
+    Position = ModelViewMatrix * vec4(VertexPosition, 1.0);
+
+    // Transform this vertex normal to camera coordinates.
+    Normal = normalize(NormalMatrix * VertexNormal);
+
+    // The texture coordinates are flipped in the fragment shader
+    // because each map has its own texture info.
+    FragTexcoord = VertexTexcoord;
+    FragTexcoord2 = VertexTexcoord2;
+
+    gl_Position = MVP * vec4(VertexPosition, 1.0);
+}
+`
+
+//
+// Fragment Shader template
+//
+const shaderPhysicalFrag = `
+#version {{.Version}}
+
+// Inputs from vertex shader
+in vec4 Position;       // Vertex position in camera coordinates.
+in vec3 Normal;         // Vertex normal in camera coordinates.
+in vec2 FragTexcoord;
+in vec2 FragTexcoord2;
+
+{{template "lights" .}}
+{{template "material" .}}
+
+// Physical material uniforms
+uniform vec3 Physical[7];
+uniform mat3 PhysicalEnvRotation;
+
+// Macros to access elements inside the Physical uniform array
+#define PhysBaseColor			Physical[0]
+#define PhysEmissiveColor		Physical[1]
+#define PhysBaseAlpha			Physical[2].x
+#define PhysMetallic			Physical[2].y
+#define PhysRoughness			Physical[2].z
+#define PhysNormalScale			Physical[3].x
+#define PhysOcclusionStrength	Physical[3].y
+#define PhysEnvIntensity		Physical[3].z
+#define PhysBaseColorMap		int(Physical[4].x)
+#define PhysMetalRoughMap		int(Physical[4].y)
+#define PhysNormalMap			int(Physical[4].z)
+#define PhysOcclusionMap		int(Physical[5].x)
+#define PhysEmissiveMap			int(Physical[5].y)
+#define PhysEnvMap				int(Physical[5].z)
+#define PhysEnvCubemap			int(Physical[6].x)
+#define PhysEnvCubemapHDR		bool(Physical[6].y)
+
+// Final fragment color
+out vec4 FragColor;
+
+const float PI = 3.14159265359;
+
+// Returns the texture coordinates of the set used by the material
+// texture with the specified index.
+vec2 mapTexcoord(int idx) {
+
+    {{ range loop .MatTexturesMax }}
+    if (idx == {{.}} && MatTexUVChannel({{.}}) == 1) {
+        return FragTexcoord2;
+    }
+    {{ end }}
+    return FragTexcoord;
+}
+
+// Returns the color of the material texture with the specified index or
+// the specified default color if there is no visible texture with this index.
+// Use Go templates to unroll the loop because non-const
+// array indexes are not allowed until GLSL 4.00.
+vec4 sampleMap(int idx, vec2 texcoord, vec4 def) {
+
+    {{ range loop .MatTexturesMax }}
+    if (idx == {{.}} && MatTexVisible({{.}})) {
+        vec2 uv = texcoord;
+        if (MatTexFlipY({{.}})) {
+            uv.y = 1 - uv.y;
+        }
+        return texture(MatTexture[{{.}}], uv * MatTexRepeat({{.}}) + MatTexOffset({{.}}));
+    }
+    {{ end }}
+    return def;
+}
+
+// Converts a color from sRGB to linear space
+vec3 sRGBToLinear(vec3 color) {
+
+    return pow(color, vec3(2.2));
+}
+
+// Returns the linear color of the environment cube map or equirectangular
+// environment map in the specified world direction sampling the mipmap level
+// at the specified fraction of the levels or black if there is no environment map.
+vec3 sampleEnv(vec3 dir, float level) {
+
+    {{ range loop .MatCubemapsMax }}
+    if (PhysEnvCubemap == {{.}}) {
+        float maxLevel = log2(float(textureSize(MatCubemap[{{.}}], 0).x));
+        vec3 color = textureLod(MatCubemap[{{.}}], dir, level * maxLevel).rgb;
+        if (PhysEnvCubemapHDR) {
+            return color;
+        }
+        return sRGBToLinear(color);
+    }
+    {{ end }}
+
+    {{ range loop .MatTexturesMax }}
+    if (PhysEnvMap == {{.}} && MatTexVisible({{.}})) {
+        vec2 uv = vec2(atan(dir.x, -dir.z) / (2 * PI) + 0.5, asin(clamp(dir.y, -1.0, 1.0)) / PI + 0.5);
+        if (MatTexFlipY({{.}})) {
+            uv.y = 1 - uv.y;
+        }
+        ivec2 size = textureSize(MatTexture[{{.}}], 0);
+        float maxLevel = log2(float(max(size.x, size.y)));
+        return sRGBToLinear(textureLod(MatTexture[{{.}}], uv, level * maxLevel).rgb);
+    }
+    {{ end }}
+    return vec3(0);
+}
+
+// Returns the normal perturbed by the normal map using the tangent space
+// calculated from the derivatives of the position and texture coordinates.
+vec3 perturbNormal(vec3 normal, vec3 position, vec2 texcoord) {
+
+    vec3 mapNormal = sampleMap(PhysNormalMap, texcoord, vec4(0.5, 0.5, 1, 1)).xyz * 2 - 1;
+    mapNormal.xy *= PhysNormalScale;
+
+    vec3 dp1 = dFdx(position);
+    vec3 dp2 = dFdy(position);
+    vec2 duv1 = dFdx(texcoord);
+    vec2 duv2 = dFdy(texcoord);
+    vec3 dp2perp = cross(dp2, normal);
+    vec3 dp1perp = cross(normal, dp1);
+    vec3 tangent = dp2perp * duv1.x + dp1perp * duv2.x;
+    vec3 bitangent = dp2perp * duv1.y + dp1perp * duv2.y;
+    float invmax = inversesqrt(max(max(dot(tangent, tangent), dot(bitangent, bitangent)), 1e-12));
+    return normalize(mat3(tangent * invmax, bitangent * invmax, normal) * mapNormal);
+}
+
+// Returns the radiance reflected to the camera from a light with the specified
+// direction and color using the Cook-Torrance model with the GGX distribution,
+// the Smith-Schlick geometric attenuation and the Schlick Fresnel approximation.
+// The light color is multiplied by PI so its intensity is similar to the intensity
+// of the same light in the Phong model.
+vec3 cookTorrance(vec3 normal, vec3 camDir, vec3 lightDir, vec3 lightColor, vec3 diffuseColor, vec3 f0, float alpha) {
+
+    float dotNL = dot(normal, lightDir);
+    if (dotNL <= 0.0) {
+        return vec3(0);
+    }
+    vec3 halfDir = normalize(lightDir + camDir);
+    float dotNV = max(dot(normal, camDir), 1e-4);
+    float dotNH = max(dot(normal, halfDir), 0.0);
+    float dotVH = max(dot(camDir, halfDir), 0.0);
+
+    // Normal distribution
+    float alpha2 = alpha * alpha;
+    float d = dotNH * dotNH * (alpha2 - 1) + 1;
+    float distribution = alpha2 / (PI * d * d);
+
+    // Geometric attenuation divided by the 4*dotNL*dotNV term of the BRDF
+    float k = alpha / 2;
+    float visibility = 0.25 / ((dotNL * (1 - k) + k) * (dotNV * (1 - k) + k));
+
+    // Fresnel reflectance
+    vec3 fresnel = f0 + (1 - f0) * pow(1 - dotVH, 5.0);
+
+    vec3 diffuse = (1 - fresnel) * diffuseColor / PI;
+    vec3 specular = fresnel * distribution * visibility;
+    return (diffuse + specular) * lightColor * PI * dotNL;
+}
+
+// Returns the environment specular reflectance integrated over the
+// hemisphere using the analytic approximation by Karis.
+vec3 envBRDF(vec3 f0, float roughness, float dotNV) {
+
+    const vec4 c0 = vec4(-1, -0.0275, -0.572, 0.022);
+    const vec4 c1 = vec4(1, 0.0425, 1.04, -0.04);
+    vec4 r = roughness * c0 + c1;
+    float a004 = min(r.x * r.x, exp2(-9.28 * dotNV)) * r.x + r.y;
+    vec2 ab = vec2(-1.04, 1.04) * a004 + r.zw;
+    return f0 * ab.x + ab.y;
+}
+
+void main() {
+
+    // Base color and opacity
+    vec4 baseColor = vec4(PhysBaseColor, PhysBaseAlpha);
+    vec4 baseTex = sampleMap(PhysBaseColorMap, mapTexcoord(PhysBaseColorMap), vec4(1));
+    baseColor *= vec4(sRGBToLinear(baseTex.rgb), baseTex.a);
+
+    // Metalness and roughness from the blue and green channels of the map
+    vec4 metalRough = sampleMap(PhysMetalRoughMap, mapTexcoord(PhysMetalRoughMap), vec4(1));
+    float metallic = clamp(PhysMetallic * metalRough.b, 0.0, 1.0);
+    float roughness = clamp(PhysRoughness * metalRough.g, 0.04, 1.0);
+    float alpha = roughness * roughness;
+
+    // Dielectrics reflect 4% of the light and metals reflect their base color
+    vec3 f0 = mix(vec3(0.04), baseColor.rgb, metallic);
+    vec3 diffuseColor = baseColor.rgb * (1 - metallic);
+
+    // Inverts the fragment normal if not FrontFacing
+    vec3 normal = normalize(Normal);
+    if (!gl_FrontFacing) {
+        normal = -normal;
+    }
+    if (PhysNormalMap >= 0) {
+        normal = perturbNormal(normal, Position.xyz, mapTexcoord(PhysNormalMap));
+    }
+
+    // The camera is at 0,0,0
+    vec3 camDir = normalize(-Position.xyz);
+    float dotNV = max(dot(normal, camDir), 1e-4);
+
+    // Ambient occlusion from the red channel of the map
+    float occlusion = 1 + PhysOcclusionStrength * (sampleMap(PhysOcclusionMap, mapTexcoord(PhysOcclusionMap), vec4(1)).r - 1);
+
+    vec3 color = vec3(0);
+    vec3 specularEnv = envBRDF(f0, roughness, dotNV);
+
+    {{ range loop .AmbientLightsMax }}
+    color += AmbientLightColor[{{.}}] * (diffuseColor + specularEnv) * occlusion;
+    {{ end }}
+
+    {{ range loop .DirLightsMax }}
+    {
+        // DirLightPosition is the direction of the current light
+        vec3 lightDirection = normalize(DirLightPosition({{.}}));
+        float shadow = dirLightShadow(DirLightShadowIndex({{.}}), vec3(Position), DirLightShadowBias({{.}}),
+            max(dot(lightDirection, normal), 0.0));
+        color += cookTorrance(normal, camDir, lightDirection, DirLightColor({{.}}) * shadow, diffuseColor, f0, alpha);
+    }
+    {{ end }}
+
+    {{ range loop .PointLightsMax }}
+    {
+        // Calculates the direction and distance from the current fragment to this point light.
+        vec3 lightDirection = PointLightPosition({{.}}) - vec3(Position);
+        float lightDistance = length(lightDirection);
+        lightDirection = lightDirection / lightDistance;
+        // Calculates the attenuation due to the distance of the light
+        float attenuation = 1.0 / (1.0 + PointLightLinearDecay({{.}}) * lightDistance +
+            PointLightQuadraticDecay({{.}}) * lightDistance * lightDistance);
+        color += cookTorrance(normal, camDir, lightDirection, PointLightColor({{.}}) * attenuation, diffuseColor, f0, alpha);
+    }
+    {{ end }}
+
+    {{ range loop .SpotLightsMax }}
+    {
+        // Calculates the direction and distance from the current fragment to this spot light.
+        vec3 lightDirection = SpotLightPosition({{.}}) - vec3(Position);
+        float lightDistance = length(lightDirection);
+        lightDirection = lightDirection / lightDistance;
+        // Calculates the attenuation due to the distance of the light
+        float attenuation = 1.0 / (1.0 + SpotLightLinearDecay({{.}}) * lightDistance +
+            SpotLightQuadraticDecay({{.}}) * lightDistance * lightDistance);
+        // The spot light only contributes inside its cutoff angle
+        float angle = acos(dot(-lightDirection, SpotLightDirection({{.}})));
+        float cutoff = radians(clamp(SpotLightCutoffAngle({{.}}), 0.0, 90.0));
+        if (angle < cutoff) {
+            float cosAngle = dot(-lightDirection, SpotLightDirection({{.}}));
+            float spotFactor = pow(cosAngle, SpotLightAngularDecay({{.}})) *
+                spotLightFalloff(cosAngle, cutoff, SpotLightPenumbra({{.}})) *
+                spotLightShadow(SpotLightShadowIndex({{.}}), vec3(Position), SpotLightShadowBias({{.}}),
+                    max(dot(lightDirection, normal), 0.0));
+            vec3 spotColor = SpotLightColor({{.}}) * spotLightCookie(SpotLightCookieIndex({{.}}), vec3(Position));
+            color += cookTorrance(normal, camDir, lightDirection, spotColor * attenuation * spotFactor,
+                diffuseColor, f0, alpha);
+        }
+    }
+    {{ end }}
+
+    // Image based lighting from the environment map in world coordinates.
+    // The diffuse light is approximated by one of the smallest mipmap levels
+    // and the specular reflection by the level corresponding to the roughness.
+    if (PhysEnvMap >= 0 || PhysEnvCubemap >= 0) {
+        vec3 worldNormal = normalize(PhysicalEnvRotation * normal);
+        vec3 worldReflect = normalize(PhysicalEnvRotation * reflect(-camDir, normal));
+        vec3 envDiffuse = diffuseColor * sampleEnv(worldNormal, 0.8);
+        vec3 envSpecular = specularEnv * sampleEnv(worldReflect, roughness);
+        color += (envDiffuse + envSpecular) * PhysEnvIntensity * occlusion;
+    }
+
+    // Emissive color
+    vec3 emissiveTex = sampleMap(PhysEmissiveMap, mapTexcoord(PhysEmissiveMap), vec4(1)).rgb;
+    color += PhysEmissiveColor * sRGBToLinear(emissiveTex);
+
+    // Final fragment color converted to sRGB
+    FragColor = vec4(pow(clamp(color, 0.0, 1.0), vec3(1 / 2.2)), baseColor.a);
+}
+
+`
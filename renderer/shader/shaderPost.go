@@ -0,0 +1,116 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shader
+
+func init() {
+	AddChunk("post", chunkPost)
+	AddShader("shaderPostVertex", shaderPostVertex)
+	AddShader("shaderPostFXAAFrag", shaderPostFXAAFrag)
+	AddShader("shaderPostTonemapFrag", shaderPostTonemapFrag)
+	AddProgram("shaderPostFXAA", "shaderPostVertex", "shaderPostFXAAFrag")
+	AddProgram("shaderPostTonemap", "shaderPostVertex", "shaderPostTonemapFrag")
+}
+
+//
+// Fragment shaders inputs and output of the post processing passes
+//
+const chunkPost = `
+// Texture with the result of the previous pass
+uniform sampler2D PostTexture;
+// Size of one texel of the previous pass texture
+uniform vec2 PostTexelSize;
+
+// Texture coordinates of the fragment
+in vec2 FragTexcoord;
+
+// Output
+out vec4 FragColor;
+`
+
+//
+// Vertex Shader template
+//
+const shaderPostVertex = `
+#version {{.Version}}
+
+out vec2 FragTexcoord;
+
+void main() {
+
+    // Draws a triangle which covers the whole viewport
+    // using the vertex index without vertex attributes
+    vec2 pos = vec2(float((gl_VertexID & 1) << 2) - 1.0, float((gl_VertexID & 2) << 1) - 1.0);
+    FragTexcoord = pos * 0.5 + 0.5;
+    gl_Position = vec4(pos, 0.0, 1.0);
+}
+`
+
+//
+// Fragment Shader template
+//
+const shaderPostFXAAFrag = `
+#version {{.Version}}
+
+{{template "post" .}}
+
+const float FXAA_SPAN_MAX = 8.0;
+const float FXAA_REDUCE_MUL = 1.0 / 8.0;
+const float FXAA_REDUCE_MIN = 1.0 / 128.0;
+
+void main() {
+
+    // Luminances of the fragment and its diagonal neighbours
+    vec3 luma = vec3(0.299, 0.587, 0.114);
+    vec4 colorM = texture(PostTexture, FragTexcoord);
+    float lumaM = dot(colorM.rgb, luma);
+    float lumaNW = dot(texture(PostTexture, FragTexcoord + vec2(-1.0, -1.0) * PostTexelSize).rgb, luma);
+    float lumaNE = dot(texture(PostTexture, FragTexcoord + vec2(1.0, -1.0) * PostTexelSize).rgb, luma);
+    float lumaSW = dot(texture(PostTexture, FragTexcoord + vec2(-1.0, 1.0) * PostTexelSize).rgb, luma);
+    float lumaSE = dot(texture(PostTexture, FragTexcoord + vec2(1.0, 1.0) * PostTexelSize).rgb, luma);
+    float lumaMin = min(lumaM, min(min(lumaNW, lumaNE), min(lumaSW, lumaSE)));
+    float lumaMax = max(lumaM, max(max(lumaNW, lumaNE), max(lumaSW, lumaSE)));
+
+    // Direction of the edge perpendicular to the luminance gradient
+    vec2 dir = vec2(-((lumaNW + lumaNE) - (lumaSW + lumaSE)), (lumaNW + lumaSW) - (lumaNE + lumaSE));
+    float dirReduce = max((lumaNW + lumaNE + lumaSW + lumaSE) * (0.25 * FXAA_REDUCE_MUL), FXAA_REDUCE_MIN);
+    float rcpDirMin = 1.0 / (min(abs(dir.x), abs(dir.y)) + dirReduce);
+    dir = clamp(dir * rcpDirMin, vec2(-FXAA_SPAN_MAX), vec2(FXAA_SPAN_MAX)) * PostTexelSize;
+
+    // Blends the samples along the edge
+    vec3 colorA = 0.5 * (
+        texture(PostTexture, FragTexcoord + dir * (1.0 / 3.0 - 0.5)).rgb +
+        texture(PostTexture, FragTexcoord + dir * (2.0 / 3.0 - 0.5)).rgb);
+    vec3 colorB = colorA * 0.5 + 0.25 * (
+        texture(PostTexture, FragTexcoord + dir * -0.5).rgb +
+        texture(PostTexture, FragTexcoord + dir * 0.5).rgb);
+    float lumaB = dot(colorB, luma);
+    if (lumaB < lumaMin || lumaB > lumaMax) {
+        FragColor = vec4(colorA, colorM.a);
+    } else {
+        FragColor = vec4(colorB, colorM.a);
+    }
+}
+`
+
+//
+// Fragment Shader template
+//
+const shaderPostTonemapFrag = `
+#version {{.Version}}
+
+{{template "post" .}}
+
+// Exposure multiplying the colors before tone mapping
+uniform float PostExposure;
+
+void main() {
+
+    // ACES filmic tone mapping curve approximation
+    vec4 color = texture(PostTexture, FragTexcoord);
+    vec3 x = color.rgb * PostExposure;
+    vec3 mapped = clamp((x * (2.51 * x + 0.03)) / (x * (2.43 * x + 0.59) + 0.14), 0.0, 1.0);
+    FragColor = vec4(mapped, color.a);
+}
+`
@@ -0,0 +1,45 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shader
+
+func init() {
+	AddShader("shaderShadowVertex", shaderShadowVertex)
+	AddShader("shaderShadowFrag", shaderShadowFrag)
+	AddProgram("shaderShadow", "shaderShadowVertex", "shaderShadowFrag")
+}
+
+//
+// Vertex Shader template
+//
+const shaderShadowVertex = `
+#version {{.Version}}
+
+{{template "attributes" .}}
+
+// Model uniforms
+uniform mat4 MVP;
+
+void main() {
+
+    // The MVP matrix uses the light view and projection matrices
+    {{if .Instanced}}
+    gl_Position = MVP * InstanceMatrix * vec4(VertexPosition, 1.0);
+    {{else}}
+    gl_Position = MVP * vec4(VertexPosition, 1.0);
+    {{end}}
+}
+`
+
+//
+// Fragment Shader template
+//
+const shaderShadowFrag = `
+#version {{.Version}}
+
+// Only the depth is written to the shadow map
+void main() {
+
+}
+`
@@ -0,0 +1,57 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shader
+
+func init() {
+	AddShader("shaderSkyboxVertex", shaderSkyboxVertex)
+	AddShader("shaderSkyboxFrag", shaderSkyboxFrag)
+	AddProgram("shaderSkybox", "shaderSkyboxVertex", "shaderSkyboxFrag")
+}
+
+//
+// Vertex Shader template
+//
+const shaderSkyboxVertex = `
+#version {{.Version}}
+
+{{template "attributes" .}}
+
+// Model uniforms
+uniform mat4 MVP;
+
+// Output for the fragment shader
+out vec3 Direction;
+
+void main() {
+
+    // The cube is centered at the origin so the vertex position
+    // is the direction to sample the cube map
+    Direction = VertexPosition;
+
+    // Sets the depth to the maximum value
+    vec4 position = MVP * vec4(VertexPosition, 1.0);
+    gl_Position = position.xyww;
+}
+`
+
+//
+// Fragment Shader template
+//
+const shaderSkyboxFrag = `
+#version {{.Version}}
+
+{{template "material" .}}
+
+// Input from vertex shader
+in vec3 Direction;
+
+// Output
+out vec4 FragColor;
+
+void main() {
+
+    FragColor = vec4(texture(MatCubemap[0], Direction).rgb, 1);
+}
+`
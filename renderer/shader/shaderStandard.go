@@ -23,45 +23,84 @@ uniform mat4 ModelViewMatrix;
 uniform mat3 NormalMatrix;
 uniform mat4 MVP;
 
+{{if or .DirShadowsMax .SpotShadowsMax}}
+{{template "material" .}}
+
+// The lighting is calculated per fragment when there are shadow maps
+// because the shadows are sampled at each fragment position.
+out vec4 Position;
+out vec3 Normal;
+out vec3 CamDir;
+{{if .Instanced}}
+out vec3 FragInstanceColor;
+{{end}}
+{{else}}
 {{template "lights" .}}
 {{template "material" .}}
 {{template "phong_model" .}}
 
-
 // Outputs for the fragment shader.
 out vec3 ColorFrontAmbdiff;
 out vec3 ColorFrontSpec;
 out vec3 ColorBackAmbdiff;
 out vec3 ColorBackSpec;
+{{end}}
 out vec2 FragTexcoord;
+out vec2 FragTexcoord2;
 
 void main() {
 
+    {{if .Instanced}}
+    // Applies the instance transform in model coordinates
+    // and multiplies the material colors by the instance color
+    vec4 vertexPosition = InstanceMatrix * vec4(VertexPosition, 1.0);
+    vec3 vertexNormal = transpose(inverse(mat3(InstanceMatrix))) * VertexNormal;
+    vec3 matAmbient = MatAmbientColor * InstanceColor;
+    vec3 matDiffuse = MatDiffuseColor * InstanceColor;
+    {{else}}
+    vec4 vertexPosition = vec4(VertexPosition, 1.0);
+    vec3 vertexNormal = VertexNormal;
+    vec3 matAmbient = MatAmbientColor;
+    vec3 matDiffuse = MatDiffuseColor;
+    {{end}}
+
     // Transform this vertex normal to camera coordinates.
-    vec3 normal = normalize(NormalMatrix * VertexNormal);
+    vec3 normal = normalize(NormalMatrix * vertexNormal);
 
     // Calculate this vertex position in camera coordinates
-    vec4 position = ModelViewMatrix * vec4(VertexPosition, 1.0);
+    vec4 position = ModelViewMatrix * vertexPosition;
 
     // Calculate the direction vector from the vertex to the camera
     // The camera is at 0,0,0
     vec3 camDir = normalize(-position.xyz);
 
+    {{if or .DirShadowsMax .SpotShadowsMax}}
+    Position = position;
+    Normal = normal;
+    CamDir = camDir;
+    {{if .Instanced}}
+    FragInstanceColor = InstanceColor;
+    {{end}}
+    {{else}}
     // Calculates the vertex Ambient+Diffuse and Specular colors using the Phong model
     // for the front and back
-    phongModel(position,  normal, camDir, MatAmbientColor, MatDiffuseColor, ColorFrontAmbdiff, ColorFrontSpec);
-    phongModel(position, -normal, camDir, MatAmbientColor, MatDiffuseColor, ColorBackAmbdiff, ColorBackSpec);
+    phongModel(position,  normal, camDir, matAmbient, matDiffuse, ColorFrontAmbdiff, ColorFrontSpec);
+    phongModel(position, -normal, camDir, matAmbient, matDiffuse, ColorBackAmbdiff, ColorBackSpec);
+    {{end}}
 
     vec2 texcoord = VertexTexcoord;
+    vec2 texcoord2 = VertexTexcoord2;
     {{if .MatTexturesMax }}
     // Flips texture coordinate Y if requested.
     if (MatTexFlipY(0)) {
         texcoord.y = 1 - texcoord.y;
+        texcoord2.y = 1 - texcoord2.y;
     }
     {{ end }}
     FragTexcoord = texcoord;
+    FragTexcoord2 = texcoord2;
 
-    gl_Position = MVP * vec4(VertexPosition, 1.0);
+    gl_Position = MVP * vertexPosition;
 }
 `
 
@@ -71,14 +110,28 @@ void main() {
 const shaderStandardFrag = `
 #version {{.Version}}
 
-{{template "material" .}}
-
 // Inputs from Vertex shader
+{{if or .DirShadowsMax .SpotShadowsMax}}
+in vec4 Position;
+in vec3 Normal;
+in vec3 CamDir;
+{{if .Instanced}}
+in vec3 FragInstanceColor;
+{{end}}
+
+{{template "lights" .}}
+{{template "material" .}}
+{{template "phong_model" .}}
+{{else}}
 in vec3 ColorFrontAmbdiff;
 in vec3 ColorFrontSpec;
 in vec3 ColorBackAmbdiff;
 in vec3 ColorBackSpec;
+
+{{template "material" .}}
+{{end}}
 in vec2 FragTexcoord;
+in vec2 FragTexcoord2;
 
 // Output
 out vec4 FragColor;
@@ -93,7 +146,7 @@ void main() {
     // array indexes are not allowed until GLSL 4.00.
     {{ range loop .MatTexturesMax }}
     if (MatTexVisible({{.}})) {
-        vec4 texcolor = texture(MatTexture[{{.}}], FragTexcoord * MatTexRepeat({{.}}) + MatTexOffset({{.}}));
+        vec4 texcolor = texture(MatTexture[{{.}}], (MatTexUVChannel({{.}}) == 1 ? FragTexcoord2 : FragTexcoord) * MatTexRepeat({{.}}) + MatTexOffset({{.}}));
         if ({{.}} == 0) {
             texCombined = texcolor;
         } else {
@@ -104,6 +157,24 @@ void main() {
 
     vec4 colorAmbDiff;
     vec4 colorSpec;
+    {{if or .DirShadowsMax .SpotShadowsMax}}
+    // Calculates the Ambient+Diffuse and Specular colors for this fragment
+    // using the Phong model with the normal facing the camera.
+    vec3 fragNormal = Normal;
+    if (!gl_FrontFacing) {
+        fragNormal = -fragNormal;
+    }
+    vec3 matAmbient = MatAmbientColor;
+    vec3 matDiffuse = MatDiffuseColor;
+    {{if .Instanced}}
+    matAmbient *= FragInstanceColor;
+    matDiffuse *= FragInstanceColor;
+    {{end}}
+    vec3 ambdiff, spec;
+    phongModel(Position, fragNormal, CamDir, matAmbient, matDiffuse, ambdiff, spec);
+    colorAmbDiff = vec4(ambdiff, MatOpacity);
+    colorSpec = vec4(spec, 0);
+    {{else}}
     if (gl_FrontFacing) {
         colorAmbDiff = vec4(ColorFrontAmbdiff, MatOpacity);
         colorSpec = vec4(ColorFrontSpec, 0);
@@ -111,6 +182,7 @@ void main() {
         colorAmbDiff = vec4(ColorBackAmbdiff, MatOpacity);
         colorSpec = vec4(ColorBackSpec, 0);
     }
+    {{end}}
     FragColor = min(colorAmbDiff * texCombined + colorSpec, vec4(1));
 }
 
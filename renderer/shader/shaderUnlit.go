@@ -0,0 +1,98 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shader
+
+func init() {
+	AddShader("shaderUnlitVertex", shaderUnlitVertex)
+	AddShader("shaderUnlitFrag", shaderUnlitFrag)
+	AddProgram("shaderUnlit", "shaderUnlitVertex", "shaderUnlitFrag")
+}
+
+//
+// Vertex Shader template
+//
+const shaderUnlitVertex = `
+#version {{.Version}}
+
+{{template "attributes" .}}
+{{template "material" .}}
+
+// Model uniforms
+uniform mat4 MVP;
+
+// Outputs for the fragment shader.
+out vec3 Color;
+out vec2 FragTexcoord;
+out vec2 FragTexcoord2;
+
+void main() {
+
+    // Multiplies the material color by the vertex color if requested
+    Color = MatDiffuseColor;
+    if (MatVertexColors) {
+        Color *= VertexColor;
+    }
+
+    vec2 texcoord = VertexTexcoord;
+    vec2 texcoord2 = VertexTexcoord2;
+    {{if .MatTexturesMax }}
+    // Flips texture coordinate Y if requested.
+    if (MatTexFlipY(0)) {
+        texcoord.y = 1 - texcoord.y;
+        texcoord2.y = 1 - texcoord2.y;
+    }
+    {{ end }}
+    FragTexcoord = texcoord;
+    FragTexcoord2 = texcoord2;
+
+    gl_Position = MVP * vec4(VertexPosition, 1.0);
+}
+`
+
+//
+// Fragment Shader template
+//
+const shaderUnlitFrag = `
+#version {{.Version}}
+
+{{template "material" .}}
+
+// Inputs from Vertex shader
+in vec3 Color;
+in vec2 FragTexcoord;
+in vec2 FragTexcoord2;
+
+// Output
+out vec4 FragColor;
+
+
+void main() {
+
+    vec4 texCombined = vec4(1);
+
+    // Combine all texture colors and opacity
+    // Use Go templates to unroll the loop because non-const
+    // array indexes are not allowed until GLSL 4.00.
+    {{ range loop .MatTexturesMax }}
+    if (MatTexVisible({{.}})) {
+        vec4 texcolor = texture(MatTexture[{{.}}], (MatTexUVChannel({{.}}) == 1 ? FragTexcoord2 : FragTexcoord) * MatTexRepeat({{.}}) + MatTexOffset({{.}}));
+        if ({{.}} == 0) {
+            texCombined = texcolor;
+        } else {
+            texCombined = mix(texCombined, texcolor, texcolor.a);
+        }
+    }
+    {{ end }}
+
+    vec4 color = vec4(Color, MatOpacity) * texCombined;
+
+    // Discards the fragments below the alpha test threshold
+    if (color.a < MatAlphaTest) {
+        discard;
+    }
+    FragColor = color;
+}
+
+`
@@ -15,15 +15,22 @@ import (
 )
 
 type ShaderSpecs struct {
-	Name             string             // Shader name
-	Version          string             // GLSL version
-	ShaderUnique     bool               // indicates if shader is independent of lights and textures
-	UseLights        material.UseLights // Bitmask indicating which lights to consider
-	AmbientLightsMax int                // Current number of ambient lights
-	DirLightsMax     int                // Current Number of directional lights
-	PointLightsMax   int                // Current Number of point lights
-	SpotLightsMax    int                // Current Number of spot lights
-	MatTexturesMax   int                // Current Number of material textures
+	Name              string             // Shader name
+	Version           string             // GLSL version
+	ShaderUnique      bool               // indicates if shader is independent of lights and textures
+	Instanced         bool               // indicates if the graphic is drawn with instancing
+	UseLights         material.UseLights // Bitmask indicating which lights to consider
+	AmbientLightsMax  int                // Current number of ambient lights
+	DirLightsMax      int                // Current Number of directional lights
+	PointLightsMax    int                // Current Number of point lights
+	SpotLightsMax     int                // Current Number of spot lights
+	SpotCookiesMax    int                // Current Number of spot lights with cookies
+	DirShadowsMax     int                // Current Number of directional lights with shadows
+	SpotShadowsMax    int                // Current Number of spot lights with shadows
+	RectAreaLightsMax int                // Current Number of rect area lights
+	MatTexturesMax    int                // Current Number of material textures
+	MatTexArraysMax   int                // Current Number of material texture arrays
+	MatCubemapsMax    int                // Current Number of material cube maps
 }
 
 type ProgSpecs struct {
@@ -178,12 +185,18 @@ func (sm *Shaman) SetProgram(s *ShaderSpecs) (bool, error) {
 	}
 	if (specs.UseLights & material.UseLightDirectional) == 0 {
 		specs.DirLightsMax = 0
+		specs.DirShadowsMax = 0
 	}
 	if (specs.UseLights & material.UseLightPoint) == 0 {
 		specs.PointLightsMax = 0
 	}
 	if (specs.UseLights & material.UseLightSpot) == 0 {
 		specs.SpotLightsMax = 0
+		specs.SpotCookiesMax = 0
+		specs.SpotShadowsMax = 0
+	}
+	if (specs.UseLights & material.UseLightRectArea) == 0 {
+		specs.RectAreaLightsMax = 0
 	}
 
 	// If current shader specs are the same as the specified specs, nothing to do.
@@ -218,10 +231,33 @@ func (sm *Shaman) SetProgram(s *ShaderSpecs) (bool, error) {
 // Generates shader program from the specified specs
 func (sm *Shaman) GenProgram(specs *ShaderSpecs) (*gls.Program, error) {
 
+	sourceVertex, sourceFrag, sourceGeom, err := sm.genSources(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Creates shader program
+	prog := sm.gs.NewProgram()
+	prog.AddShader(gls.VERTEX_SHADER, sourceVertex, nil)
+	prog.AddShader(gls.FRAGMENT_SHADER, sourceFrag, nil)
+	if sourceGeom != "" {
+		prog.AddShader(gls.GEOMETRY_SHADER, sourceGeom, nil)
+	}
+	err = prog.Build()
+	if err != nil {
+		return nil, err
+	}
+	return prog, nil
+}
+
+// genSources generates the vertex, fragment and optional geometry
+// shader sources of the program with the specified specs
+func (sm *Shaman) genSources(specs *ShaderSpecs) (string, string, string, error) {
+
 	// Get info for the specified shader program
 	progInfo, ok := sm.proginfo[specs.Name]
 	if !ok {
-		return nil, fmt.Errorf("Program:%s not found", specs.Name)
+		return "", "", "", fmt.Errorf("Program:%s not found", specs.Name)
 	}
 
 	// Sets the GLSL version string
@@ -230,25 +266,25 @@ func (sm *Shaman) GenProgram(specs *ShaderSpecs) (*gls.Program, error) {
 	// Get vertex shader compiled template
 	vtempl, ok := sm.shaders[progInfo.Vertex]
 	if !ok {
-		return nil, fmt.Errorf("Vertex shader:%s template not found", progInfo.Vertex)
+		return "", "", "", fmt.Errorf("Vertex shader:%s template not found", progInfo.Vertex)
 	}
 	// Generates vertex shader source from template
 	var sourceVertex bytes.Buffer
 	err := vtempl.Execute(&sourceVertex, specs)
 	if err != nil {
-		return nil, err
+		return "", "", "", err
 	}
 
 	// Get fragment shader compiled template
 	fragTempl, ok := sm.shaders[progInfo.Frag]
 	if !ok {
-		return nil, fmt.Errorf("Fragment shader:%s template not found", progInfo.Frag)
+		return "", "", "", fmt.Errorf("Fragment shader:%s template not found", progInfo.Frag)
 	}
 	// Generates fragment shader source from template
 	var sourceFrag bytes.Buffer
 	err = fragTempl.Execute(&sourceFrag, specs)
 	if err != nil {
-		return nil, err
+		return "", "", "", err
 	}
 
 	// Checks for optional geometry shader compiled template
@@ -257,27 +293,15 @@ func (sm *Shaman) GenProgram(specs *ShaderSpecs) (*gls.Program, error) {
 		// Get geometry shader compiled template
 		geomTempl, ok := sm.shaders[progInfo.Geometry]
 		if !ok {
-			return nil, fmt.Errorf("Geometry shader:%s template not found", progInfo.Geometry)
+			return "", "", "", fmt.Errorf("Geometry shader:%s template not found", progInfo.Geometry)
 		}
 		// Generates geometry shader source from template
 		err = geomTempl.Execute(&sourceGeom, specs)
 		if err != nil {
-			return nil, err
+			return "", "", "", err
 		}
 	}
-
-	// Creates shader program
-	prog := sm.gs.NewProgram()
-	prog.AddShader(gls.VERTEX_SHADER, sourceVertex.String(), nil)
-	prog.AddShader(gls.FRAGMENT_SHADER, sourceFrag.String(), nil)
-	if progInfo.Geometry != "" {
-		prog.AddShader(gls.GEOMETRY_SHADER, sourceGeom.String(), nil)
-	}
-	err = prog.Build()
-	if err != nil {
-		return nil, err
-	}
-	return prog, nil
+	return sourceVertex.String(), sourceFrag.String(), sourceGeom.String(), nil
 }
 
 // Compare compares two shaders specifications structures
@@ -286,6 +310,9 @@ func (ss *ShaderSpecs) Compare(other *ShaderSpecs) bool {
 	if ss.Name != other.Name {
 		return false
 	}
+	if ss.Instanced != other.Instanced {
+		return false
+	}
 	if other.ShaderUnique {
 		return true
 	}
@@ -293,7 +320,13 @@ func (ss *ShaderSpecs) Compare(other *ShaderSpecs) bool {
 		ss.DirLightsMax == other.DirLightsMax &&
 		ss.PointLightsMax == other.PointLightsMax &&
 		ss.SpotLightsMax == other.SpotLightsMax &&
-		ss.MatTexturesMax == other.MatTexturesMax {
+		ss.SpotCookiesMax == other.SpotCookiesMax &&
+		ss.DirShadowsMax == other.DirShadowsMax &&
+		ss.SpotShadowsMax == other.SpotShadowsMax &&
+		ss.RectAreaLightsMax == other.RectAreaLightsMax &&
+		ss.MatTexturesMax == other.MatTexturesMax &&
+		ss.MatTexArraysMax == other.MatTexArraysMax &&
+		ss.MatCubemapsMax == other.MatCubemapsMax {
 		return true
 	}
 	return false
@@ -0,0 +1,45 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package renderer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShamanSecondTexcoords(t *testing.T) {
+
+	var sm Shaman
+	sm.Init(nil)
+	err := sm.AddDefaultShaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The shaders of the materials with textures read the second set of
+	// texture coordinates and select the set of each texture by its info
+	for _, name := range []string{"shaderStandard", "shaderPhong", "shaderUnlit", "shaderPhysical"} {
+		specs := ShaderSpecs{Name: name, MatTexturesMax: 2}
+		vertex, frag, _, err := sm.genSources(&specs)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, want := range []string{"in vec2  VertexTexcoord2;", "FragTexcoord2 = "} {
+			if !strings.Contains(vertex, want) {
+				t.Errorf("%s: vertex shader without %q", name, want)
+			}
+		}
+		for _, want := range []string{
+			"#define MatTexUVChannel(a)\tint(MatTexinfo[a][2].z)",
+			"in vec2 FragTexcoord2;",
+			"MatTexUVChannel(0) == 1",
+			"MatTexUVChannel(1) == 1",
+		} {
+			if !strings.Contains(frag, want) {
+				t.Errorf("%s: fragment shader without %q", name, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package renderer
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+func TestSortGraphics(t *testing.T) {
+
+	// The camera is at the origin looking along -Z
+	r := new(Renderer)
+	r.rinfo.ViewMatrix.Identity()
+	quad := geometry.NewPlane(1, 1, 1, 1)
+	meshes := make(map[*graphic.GraphicMaterial]string)
+	add := func(name string, z float32, transparent bool, order int) {
+		mat := material.NewStandard(math32.NewColor(1, 1, 1))
+		if transparent {
+			mat.SetOpacity(0.5)
+			mat.SetTransparent(true)
+		}
+		mat.SetRenderOrder(order)
+		// The overlapping quads are moved sideways, which does not change their depth
+		mesh := graphic.NewMesh(quad, mat)
+		mesh.SetPosition(z*0.1, 0, z)
+		mesh.UpdateMatrixWorld()
+		grmat := &mesh.Materials()[0]
+		meshes[grmat] = name
+		r.grmats = append(r.grmats, grmat)
+	}
+	add("opaque far", -5, false, 0)
+	add("opaque near", -2, false, 0)
+	add("transparent near", -3, true, 0)
+	add("transparent far", -6, true, 0)
+	add("transparent first", -1, true, -1)
+	add("opaque last", -10, false, 1)
+	add("opaque first", -10, false, -1)
+	r.sortGraphics()
+
+	// The render order sorts first, then the opaque graphics are sorted front
+	// to back and the transparent graphics with the same order back to front
+	want := []struct {
+		name        string
+		order       int
+		transparent bool
+		depth       float32
+	}{
+		{"opaque first", -1, false, 10},
+		{"transparent first", -1, true, 1},
+		{"opaque near", 0, false, 2},
+		{"opaque far", 0, false, 5},
+		{"transparent far", 0, true, 6},
+		{"transparent near", 0, true, 3},
+		{"opaque last", 1, false, 10},
+	}
+	if len(r.items) != len(want) || len(r.grmats) != len(want) {
+		t.Fatalf("%d items and %d graphic materials, want %d", len(r.items), len(r.grmats), len(want))
+	}
+	for i, w := range want {
+		item := r.items[i]
+		if item.order != w.order || item.transparent != w.transparent || math32.Abs(item.depth-w.depth) > 1e-5 {
+			t.Errorf("item %d = order %d transparent %v depth %v, want %d %v %v",
+				i, item.order, item.transparent, item.depth, w.order, w.transparent, w.depth)
+		}
+		if got := meshes[r.grmats[i]]; got != w.name {
+			t.Errorf("graphic %d = %s, want %s", i, got, w.name)
+		}
+	}
+}
+
+func TestOpacityNotTransparent(t *testing.T) {
+
+	// Transparency is only enabled by SetTransparent
+	mat := material.NewStandard(math32.NewColor(1, 1, 1))
+	mat.SetOpacity(0.5)
+	if mat.Transparent() {
+		t.Errorf("SetOpacity made the material transparent")
+	}
+}
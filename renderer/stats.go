@@ -0,0 +1,58 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package renderer
+
+import (
+	"github.com/g3n/engine/gls"
+	"time"
+)
+
+// RenderStats contains the statistics of the last rendered scene.
+// The counters are the differences of the OpenGL state statistics before and
+// after rendering, including the shadow maps and the post processing passes.
+// The times are measured in the CPU and include the time to submit the
+// OpenGL commands, but not the time the GPU takes to execute them.
+type RenderStats struct {
+	Graphics   int           // Number of graphics drawn
+	Culled     int           // Number of graphics outside the camera view volume
+	Drawcalls  int           // Number of draw calls
+	Triangles  int           // Number of triangles drawn
+	Programs   int           // Number of shader program switches
+	Texbinds   int           // Number of texture binds
+	CullTime   time.Duration // Time to update the scene matrices and classify and cull its nodes
+	SortTime   time.Duration // Time to sort the graphics by render order and distance to the camera
+	ShadowTime time.Duration // Time to render the shadow maps
+	DrawTime   time.Duration // Time to draw the graphics
+	PostTime   time.Duration // Time to apply the post processing passes
+	Time       time.Duration // Total render time
+}
+
+// Stats returns the statistics of the last rendered scene.
+// For example, to show them in a label updated on each frame:
+//
+//	label := gui.NewLabel("")
+//	label.SetPosition(10, 10)
+//	root.Add(label)
+//	...
+//	rend.Render(scene, cam)
+//	stats := rend.Stats()
+//	label.SetText(fmt.Sprintf("draws:%d tris:%d culled:%d programs:%d textures:%d time:%v",
+//		stats.Drawcalls, stats.Triangles, stats.Culled, stats.Programs, stats.Texbinds, stats.Time))
+func (r *Renderer) Stats() RenderStats {
+
+	return r.stats
+}
+
+// setCounters sets the OpenGL counters of these statistics from
+// the current OpenGL statistics and the specified previous ones
+func (rs *RenderStats) setCounters(gs *gls.GLS, prev *gls.Stats) {
+
+	var curr gls.Stats
+	gs.Stats(&curr)
+	rs.Drawcalls = int(curr.Drawcalls - prev.Drawcalls)
+	rs.Triangles = int(curr.Triangles - prev.Triangles)
+	rs.Programs = int(curr.Programs - prev.Programs)
+	rs.Texbinds = int(curr.Texbinds - prev.Texbinds)
+}
@@ -0,0 +1,192 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package renderer
+
+import (
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/texture"
+)
+
+// RenderTarget is an offscreen framebuffer where the renderer can draw
+// a scene instead of the window, with a color texture which can be used
+// as a material map or shown in a GUI image, and an optional depth buffer
+// for rendering scenes with depth test.
+// For example, to show a minimap rendered by a top view camera:
+//
+//	target := renderer.NewRenderTarget(256, 256, gls.RGBA8, true)
+//	minimap := gui.NewImageFromTex(target.Texture())
+//	root.Add(minimap)
+//	...
+//	// In the render loop, before rendering the main scene
+//	rend.RenderToTarget(target, scene, topCamera)
+type RenderTarget struct {
+	gs         *gls.GLS           // Pointer to OpenGL state
+	width      int32              // width of the framebuffer in pixels
+	height     int32              // height of the framebuffer in pixels
+	iformat    int                // internal format of the color texture
+	depth      bool               // has depth buffer
+	fbo        uint32             // framebuffer handle
+	depthTex   uint32             // depth texture handle
+	tex        *texture.Texture2D // color texture
+	clearColor math32.Color4      // color used to clear the color texture
+	update     bool               // attachments need to be recreated
+	prevFbo    uint32             // framebuffer bound before rendering into this target
+	prevClear  [4]float32         // clear color before rendering into this target
+	prevX      int32              // viewport before rendering into this target
+	prevY      int32              // viewport before rendering into this target
+	prevWidth  int32              // viewport before rendering into this target
+	prevHeight int32              // viewport before rendering into this target
+}
+
+// NewRenderTarget creates and returns a pointer to a new render target with
+// the specified size in pixels, internal format of the color texture, such as
+// gls.RGBA8 or gls.RGBA16F for high dynamic range colors, and which has a
+// depth buffer if depth is true.
+func NewRenderTarget(width, height int, iformat int, depth bool) *RenderTarget {
+
+	rt := new(RenderTarget)
+	rt.iformat = iformat
+	rt.depth = depth
+	rt.tex = texture.NewTexture2DFromData(1, 1, gls.RGBA, rt.formatType(), iformat, nil)
+	// The framebuffer rows start at the bottom
+	rt.tex.SetFlipY(false)
+	rt.SetSize(width, height)
+	return rt
+}
+
+// SetSize sets the size in pixels of this render target. The texture
+// storage is reallocated before the next render, so it can be called
+// when the window is resized.
+func (rt *RenderTarget) SetSize(width, height int) {
+
+	if int32(width) == rt.width && int32(height) == rt.height {
+		return
+	}
+	rt.width = int32(width)
+	rt.height = int32(height)
+	rt.tex.SetData(width, height, gls.RGBA, rt.formatType(), rt.iformat, nil)
+	rt.update = true
+}
+
+// Size returns the size in pixels of this render target
+func (rt *RenderTarget) Size() (width, height int) {
+
+	return int(rt.width), int(rt.height)
+}
+
+// SetClearColor sets the color used to clear the texture before
+// rendering into it. The default is transparent black.
+func (rt *RenderTarget) SetClearColor(color *math32.Color4) {
+
+	rt.clearColor = *color
+}
+
+// Texture returns the color texture of this render target.
+// The texture is owned by the render target and is released by its Dispose.
+func (rt *RenderTarget) Texture() *texture.Texture2D {
+
+	return rt.tex
+}
+
+// Dispose releases the OpenGL resources of this render target.
+// It can be called when the OpenGL context is lost or destroyed
+// and the render target is recreated when it is used again.
+func (rt *RenderTarget) Dispose() {
+
+	if rt.gs != nil {
+		rt.gs.DeleteFramebuffers(rt.fbo)
+		if rt.depth {
+			rt.gs.DeleteTextures(rt.depthTex)
+		}
+		rt.gs = nil
+	}
+	rt.tex.Dispose()
+	rt.tex.SetData(int(rt.width), int(rt.height), gls.RGBA, rt.formatType(), rt.iformat, nil)
+	rt.update = true
+}
+
+// begin binds the framebuffer of this render target, creating it if
+// necessary, saves the current framebuffer, viewport and clear color,
+// and clears the render target.
+func (rt *RenderTarget) begin(gs *gls.GLS) {
+
+	rt.prevFbo = gs.Framebuffer()
+	rt.prevX, rt.prevY, rt.prevWidth, rt.prevHeight = gs.GetViewport()
+	gs.GetFloatv(gls.COLOR_CLEAR_VALUE, &rt.prevClear[0])
+
+	// One time initialization
+	if rt.gs == nil {
+		rt.fbo = gs.GenFramebuffer()
+		if rt.depth {
+			rt.depthTex = gs.GenTexture()
+		}
+		rt.gs = gs
+	}
+
+	// Allocates the textures with the current size and attaches them
+	if rt.update {
+		rt.tex.Bind(gs, 0)
+		if rt.depth {
+			gs.BindTexture(gls.TEXTURE_2D, rt.depthTex)
+			gs.TexImage2D(gls.TEXTURE_2D, 0, gls.DEPTH_COMPONENT24, rt.width, rt.height, 0, gls.DEPTH_COMPONENT, gls.FLOAT, nil)
+			gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_MAG_FILTER, gls.NEAREST)
+			gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_MIN_FILTER, gls.NEAREST)
+		}
+		gs.BindFramebuffer(gls.FRAMEBUFFER, rt.fbo)
+		gs.FramebufferTexture2D(gls.FRAMEBUFFER, gls.COLOR_ATTACHMENT0, gls.TEXTURE_2D, rt.tex.TexName(), 0)
+		if rt.depth {
+			gs.FramebufferTexture2D(gls.FRAMEBUFFER, gls.DEPTH_ATTACHMENT, gls.TEXTURE_2D, rt.depthTex, 0)
+		}
+		if status := gs.CheckFramebufferStatus(gls.FRAMEBUFFER); status != gls.FRAMEBUFFER_COMPLETE {
+			log.Error("Render target framebuffer incomplete:%x", status)
+		}
+		rt.update = false
+	}
+
+	gs.BindFramebuffer(gls.FRAMEBUFFER, rt.fbo)
+	gs.Viewport(0, 0, rt.width, rt.height)
+	gs.ClearColor(rt.clearColor.R, rt.clearColor.G, rt.clearColor.B, rt.clearColor.A)
+	mask := uint(gls.COLOR_BUFFER_BIT)
+	if rt.depth {
+		gs.DepthMask(true)
+		mask |= gls.DEPTH_BUFFER_BIT
+	}
+	gs.Clear(mask)
+}
+
+// end restores the framebuffer, viewport and clear color
+// saved before rendering into this render target.
+func (rt *RenderTarget) end(gs *gls.GLS) {
+
+	gs.BindFramebuffer(gls.FRAMEBUFFER, rt.prevFbo)
+	gs.Viewport(rt.prevX, rt.prevY, rt.prevWidth, rt.prevHeight)
+	gs.ClearColor(rt.prevClear[0], rt.prevClear[1], rt.prevClear[2], rt.prevClear[3])
+}
+
+// formatType returns the type of the color texture components
+// for the internal format of this render target.
+func (rt *RenderTarget) formatType() int {
+
+	switch rt.iformat {
+	case gls.RGBA16F, gls.RGBA32F, gls.RGB16F, gls.R11F_G11F_B10F:
+		return gls.FLOAT
+	}
+	return gls.UNSIGNED_BYTE
+}
+
+// RenderToTarget renders the specified scene viewed by the specified camera
+// into the specified render target instead of the current framebuffer
+// without applying the post processing passes.
+// The previous framebuffer, viewport and clear color are restored after rendering.
+func (r *Renderer) RenderToTarget(target *RenderTarget, iscene core.INode, icam camera.ICamera) error {
+
+	target.begin(r.gs)
+	err := r.render(iscene, icam)
+	target.end(r.gs)
+	return err
+}
@@ -0,0 +1,324 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package texture
+
+import (
+	"fmt"
+	"github.com/g3n/engine/gls"
+	"image"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// Cubemap is a texture with six square faces backed by an OpenGL TEXTURE_CUBE_MAP
+// which is sampled by a direction instead of texture coordinates.
+// The faces follow the OpenGL cube map convention, with the first pixel row
+// of each face image at the top, as produced by most OpenGL tools.
+// Sampling across the faces is seamless. The cube maps of a material are
+// available in the shaders as the MatCubemap[] uniform.
+type Cubemap struct {
+	gs           *gls.GLS       // Pointer to OpenGL state
+	refcount     int            // Current number of references
+	texname      uint32         // Texture handle
+	magFilter    uint32         // magnification filter
+	minFilter    uint32         // minification filter
+	iformat      int32          // internal format
+	size         int32          // width and height of each face in pixels
+	format       uint32         // format of the pixel data
+	formatType   uint32         // type of the pixel data
+	faces        [6]interface{} // array with the data of each face
+	updateData   bool           // texture data needs to be sent
+	updateParams bool           // texture parameters needs to be sent
+	genMipmap    bool           // generate mipmaps flag
+	uTexture     gls.Uniform1i  // Texture unit uniform
+}
+
+// Cube map faces in the order of the OpenGL face targets
+const (
+	CubemapPosX = iota
+	CubemapNegX
+	CubemapPosY
+	CubemapNegY
+	CubemapPosZ
+	CubemapNegZ
+)
+
+func newCubemap() *Cubemap {
+
+	t := new(Cubemap)
+	t.refcount = 1
+	t.magFilter = gls.LINEAR
+	t.minFilter = gls.LINEAR_MIPMAP_LINEAR
+	t.updateParams = true
+	t.genMipmap = true
+	t.uTexture.Init("MatCubemap")
+	return t
+}
+
+// NewCubemap creates and returns a pointer to a new cube map using
+// the specified image files as the faces in the +X, -X, +Y, -Y, +Z, -Z
+// directions. All the images must be square and have the same size.
+// Supported image formats are: PNG, JPEG and GIF.
+func NewCubemap(posX, negX, posY, negY, posZ, negZ string) (*Cubemap, error) {
+
+	var faces [6]*image.RGBA
+	for i, imgfile := range []string{posX, negX, posY, negY, posZ, negZ} {
+		rgba, err := DecodeImage(imgfile)
+		if err != nil {
+			return nil, err
+		}
+		faces[i] = rgba
+	}
+	return NewCubemapFromRGBA(faces)
+}
+
+// NewCubemapFromRGBA creates and returns a pointer to a new cube map using
+// the specified images as the faces in the +X, -X, +Y, -Y, +Z, -Z directions.
+// All the images must be square and have the same size.
+func NewCubemapFromRGBA(faces [6]*image.RGBA) (*Cubemap, error) {
+
+	t := newCubemap()
+	for i, rgba := range faces {
+		size := rgba.Rect.Size()
+		if size.X != size.Y {
+			return nil, fmt.Errorf("cube map face:%d is not square", i)
+		}
+		if i > 0 && int32(size.X) != t.size {
+			return nil, fmt.Errorf("cube map face:%d size different from the first face", i)
+		}
+		if rgba.Stride != size.X*4 {
+			return nil, fmt.Errorf("unsupported stride")
+		}
+		t.size = int32(size.X)
+		t.faces[i] = rgba.Pix
+	}
+	t.iformat = gls.RGBA8
+	t.format = gls.RGBA
+	t.formatType = gls.UNSIGNED_BYTE
+	t.updateData = true
+	return t, nil
+}
+
+// NewCubemapFromEquirect creates and returns a pointer to a new cube map with
+// faces of the specified size in pixels converted from the specified
+// equirectangular (latitude-longitude) image file, with the -Z direction
+// at the center of the image and the +Y direction at the top.
+// Radiance HDR (.hdr) files generate a cube map with linear floating
+// point colors; other images (PNG, JPEG and GIF) are kept in sRGB.
+func NewCubemapFromEquirect(imgfile string, size int) (*Cubemap, error) {
+
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid cube map size:%d", size)
+	}
+
+	// Decodes the image into RGB floats
+	hdr := strings.ToLower(filepath.Ext(imgfile)) == ".hdr"
+	var width, height int
+	var pix []float32
+	if hdr {
+		img, err := DecodeHDR(imgfile)
+		if err != nil {
+			return nil, err
+		}
+		width, height, pix = img.Width, img.Height, img.Pix
+	} else {
+		rgba, err := DecodeImage(imgfile)
+		if err != nil {
+			return nil, err
+		}
+		width, height = rgba.Rect.Size().X, rgba.Rect.Size().Y
+		pix = make([]float32, 0, width*height*3)
+		for i := 0; i < len(rgba.Pix); i += 4 {
+			pix = append(pix, float32(rgba.Pix[i]), float32(rgba.Pix[i+1]), float32(rgba.Pix[i+2]))
+		}
+	}
+
+	// Bilinear sample of the equirectangular image in the specified direction
+	// wrapping around horizontally and clamping at the poles
+	texel := func(x, y int) []float32 {
+		x = ((x % width) + width) % width
+		if y < 0 {
+			y = 0
+		} else if y >= height {
+			y = height - 1
+		}
+		return pix[(y*width+x)*3 : (y*width+x)*3+3]
+	}
+	sample := func(dx, dy, dz float64, out []float32) {
+		u := math.Atan2(dx, -dz)/(2*math.Pi) + 0.5
+		v := 0.5 - math.Asin(dy/math.Sqrt(dx*dx+dy*dy+dz*dz))/math.Pi
+		fx := u*float64(width) - 0.5
+		fy := v*float64(height) - 0.5
+		x0 := int(math.Floor(fx))
+		y0 := int(math.Floor(fy))
+		tx := float32(fx - float64(x0))
+		ty := float32(fy - float64(y0))
+		t00, t10 := texel(x0, y0), texel(x0+1, y0)
+		t01, t11 := texel(x0, y0+1), texel(x0+1, y0+1)
+		for c := 0; c < 3; c++ {
+			top := t00[c]*(1-tx) + t10[c]*tx
+			bottom := t01[c]*(1-tx) + t11[c]*tx
+			out[c] = top*(1-ty) + bottom*ty
+		}
+	}
+
+	// Generates each face using the OpenGL cube map face coordinates
+	t := newCubemap()
+	t.size = int32(size)
+	var rgb [3]float32
+	for face := 0; face < 6; face++ {
+		floats := make([]float32, 0, size*size*3)
+		ldr := make([]byte, 0, size*size*4)
+		for y := 0; y < size; y++ {
+			tc := 2*(float64(y)+0.5)/float64(size) - 1
+			for x := 0; x < size; x++ {
+				sc := 2*(float64(x)+0.5)/float64(size) - 1
+				switch face {
+				case CubemapPosX:
+					sample(1, -tc, -sc, rgb[:])
+				case CubemapNegX:
+					sample(-1, -tc, sc, rgb[:])
+				case CubemapPosY:
+					sample(sc, 1, tc, rgb[:])
+				case CubemapNegY:
+					sample(sc, -1, -tc, rgb[:])
+				case CubemapPosZ:
+					sample(sc, -tc, 1, rgb[:])
+				case CubemapNegZ:
+					sample(-sc, -tc, -1, rgb[:])
+				}
+				if hdr {
+					floats = append(floats, rgb[0], rgb[1], rgb[2])
+				} else {
+					ldr = append(ldr, uint8(rgb[0]+0.5), uint8(rgb[1]+0.5), uint8(rgb[2]+0.5), 255)
+				}
+			}
+		}
+		if hdr {
+			t.faces[face] = floats
+		} else {
+			t.faces[face] = ldr
+		}
+	}
+	if hdr {
+		t.iformat = gls.RGB16F
+		t.format = gls.RGB
+		t.formatType = gls.FLOAT
+	} else {
+		t.iformat = gls.RGBA8
+		t.format = gls.RGBA
+		t.formatType = gls.UNSIGNED_BYTE
+	}
+	t.updateData = true
+	return t, nil
+}
+
+// Incref increments the reference count for this texture
+// and returns a pointer to the texture.
+// It should be used when this texture is shared by another
+// material.
+func (t *Cubemap) Incref() *Cubemap {
+
+	t.refcount++
+	return t
+}
+
+// Dispose decrements this texture reference count and
+// if necessary releases OpenGL resources and C memory
+// associated with this texture.
+func (t *Cubemap) Dispose() {
+
+	if t.refcount > 1 {
+		t.refcount--
+		return
+	}
+	if t.gs != nil {
+		t.gs.DeleteTextures(t.texname)
+		t.gs = nil
+	}
+}
+
+// SetMagFilter sets the filter to be applied when the texture element
+// covers more than on pixel. The default value is gls.Linear.
+func (t *Cubemap) SetMagFilter(magFilter uint32) {
+
+	t.magFilter = magFilter
+	t.updateParams = true
+}
+
+// SetMinFilter sets the filter to be applied when the texture element
+// covers less than on pixel. The default value is gls.LINEAR_MIPMAP_LINEAR.
+func (t *Cubemap) SetMinFilter(minFilter uint32) {
+
+	t.minFilter = minFilter
+	t.updateParams = true
+}
+
+// Size returns the width and height of each face in pixels
+func (t *Cubemap) Size() int {
+
+	return int(t.size)
+}
+
+// HDR returns if this cube map has linear floating point colors
+func (t *Cubemap) HDR() bool {
+
+	return t.formatType == gls.FLOAT
+}
+
+// RenderSetup is called by the material render setup to bind this cube map
+// to the specified texture unit and to set the uniform with the specified
+// index in the cube maps uniform.
+func (t *Cubemap) RenderSetup(gs *gls.GLS, unit, idx int) {
+
+	// One time initialization
+	if t.gs == nil {
+		t.texname = gs.GenTexture()
+		t.gs = gs
+		gs.Enable(gls.TEXTURE_CUBE_MAP_SEAMLESS)
+	}
+
+	// Sets the texture unit for this texture
+	gs.ActiveTexture(uint32(gls.TEXTURE0 + unit))
+	gs.BindTexture(gls.TEXTURE_CUBE_MAP, t.texname)
+
+	// Transfer the data of all the faces to OpenGL if necessary
+	if t.updateData {
+		for i, data := range t.faces {
+			gs.TexImage2D(
+				uint32(gls.TEXTURE_CUBE_MAP_POSITIVE_X+i), // face target
+				0,            // level of detail
+				t.iformat,    // internal format
+				t.size,       // width in texels
+				t.size,       // height in texels
+				0,            // border must be 0
+				t.format,     // format of supplied texture data
+				t.formatType, // type of external format color component
+				data,         // face data
+			)
+		}
+		// Generates mipmaps if requested
+		if t.genMipmap {
+			gs.GenerateMipmap(gls.TEXTURE_CUBE_MAP)
+		}
+		// No data to send
+		t.updateData = false
+	}
+
+	// Sets texture parameters if needed
+	if t.updateParams {
+		gs.TexParameteri(gls.TEXTURE_CUBE_MAP, gls.TEXTURE_MAG_FILTER, int32(t.magFilter))
+		gs.TexParameteri(gls.TEXTURE_CUBE_MAP, gls.TEXTURE_MIN_FILTER, int32(t.minFilter))
+		gs.TexParameteri(gls.TEXTURE_CUBE_MAP, gls.TEXTURE_WRAP_S, gls.CLAMP_TO_EDGE)
+		gs.TexParameteri(gls.TEXTURE_CUBE_MAP, gls.TEXTURE_WRAP_T, gls.CLAMP_TO_EDGE)
+		gs.TexParameteri(gls.TEXTURE_CUBE_MAP, gls.TEXTURE_WRAP_R, gls.CLAMP_TO_EDGE)
+		t.updateParams = false
+	}
+
+	// Transfer uniforms
+	t.uTexture.Set(int32(unit))
+	t.uTexture.TransferIdx(gs, idx)
+}
@@ -0,0 +1,95 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package texture_test
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/g3n/engine/camera"
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
+	"github.com/g3n/engine/texture"
+	"github.com/g3n/engine/window"
+)
+
+// This example renders a floor with a tiled base texture on the first set of
+// texture coordinates and a baked lightmap on the second set. The base texture
+// repeats 8 times over the floor while the lightmap, which darkens the floor
+// away from a spot at its center, covers it once. The textures of a material
+// are mixed by their alpha, so the lightmap is black and its alpha is the
+// amount of shadow. The second set of texture
+// coordinates is the VertexTexcoord2 attribute, as decoded from the TEXCOORD_1
+// attribute of glTF meshes or the second texture coordinates of Collada meshes.
+func ExampleTexture2D_SetUVChannel() {
+
+	win, err := window.New("glfw", 800, 600, "Lightmap", false)
+	if err != nil {
+		panic(err)
+	}
+	gs, err := gls.New()
+	if err != nil {
+		panic(err)
+	}
+	rend := renderer.NewRenderer(gs)
+	err = rend.AddDefaultShaders()
+	if err != nil {
+		panic(err)
+	}
+	scene := core.NewNode()
+	scene.Add(light.NewAmbient(math32.NewColor(1, 1, 1), 1))
+	cam := camera.NewPerspective(60, 800.0/600.0, 0.1, 100)
+
+	// The lightmap texture coordinates are a copy of the base ones
+	floor := geometry.NewPlane(10, 10, 1, 1)
+	uvs := floor.VBO("VertexTexcoord").Buffer()
+	floor.AddVBO(gls.NewVBO().AddAttrib("VertexTexcoord2", 2).SetBuffer(append(math32.NewArrayF32(0, len(*uvs)), (*uvs)...)))
+
+	base := texture.NewBoard(16, 16, math32.NewColor(0.9, 0.9, 0.9), math32.NewColor(0.6, 0.5, 0.4),
+		math32.NewColor(0.6, 0.5, 0.4), math32.NewColor(0.9, 0.9, 0.9), 1)
+	base.SetWrapS(gls.REPEAT)
+	base.SetWrapT(gls.REPEAT)
+	base.SetRepeat(8, 8)
+
+	const size = 64
+	rgba := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx := float32(x-size/2) / (size / 2)
+			dy := float32(y-size/2) / (size / 2)
+			a := uint8(255 * math32.Clamp(math32.Sqrt(dx*dx+dy*dy), 0, 0.9))
+			rgba.Set(x, y, color.RGBA{0, 0, 0, a})
+		}
+	}
+	lightmap := texture.NewTexture2DFromRGBA(rgba)
+	lightmap.SetUVChannel(1)
+
+	mat := material.NewStandard(math32.NewColor(1, 1, 1))
+	mat.AddTexture(base)
+	mat.AddTexture(lightmap)
+	mesh := graphic.NewMesh(floor, mat)
+	mesh.SetRotationX(-math32.Pi / 2)
+	scene.Add(mesh)
+
+	var angle float32
+	for !win.ShouldClose() {
+		angle += 0.005
+		cam.SetPosition(8*math32.Sin(angle), 5, 8*math32.Cos(angle))
+		cam.LookAt(&math32.Vector3{})
+		gs.Clear(gls.COLOR_BUFFER_BIT | gls.DEPTH_BUFFER_BIT)
+		err := rend.Render(scene, cam)
+		if err != nil {
+			panic(err)
+		}
+		win.SwapBuffers()
+		win.PollEvents()
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package texture
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// HDRImage is a high dynamic range image with linear RGB float values
+type HDRImage struct {
+	Width  int       // image width in pixels
+	Height int       // image height in pixels
+	Pix    []float32 // RGB values of the pixels from the top row to the bottom row
+}
+
+// DecodeHDR reads and decodes the specified Radiance HDR (.hdr) image file
+// with pixels in the RGBE format and flat or run length encoded scanlines.
+// Only the standard orientation (-Y height +X width) is supported.
+func DecodeHDR(imgfile string) (*HDRImage, error) {
+
+	file, err := os.Open(imgfile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	r := bufio.NewReader(file)
+
+	// Reads the header until the empty line
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "#?") {
+		return nil, fmt.Errorf("invalid HDR file signature")
+	}
+	for {
+		line, err = r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return nil, fmt.Errorf("unsupported HDR format:%s", line[7:])
+		}
+	}
+
+	// Reads the resolution line
+	line, err = r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	img := new(HDRImage)
+	_, err = fmt.Sscanf(line, "-Y %d +X %d", &img.Height, &img.Width)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported HDR resolution:%s", strings.TrimSpace(line))
+	}
+	if img.Width <= 0 || img.Height <= 0 {
+		return nil, fmt.Errorf("invalid HDR resolution:%dx%d", img.Width, img.Height)
+	}
+
+	// Decodes the scanlines
+	img.Pix = make([]float32, 0, img.Width*img.Height*3)
+	scanline := make([]byte, img.Width*4)
+	for y := 0; y < img.Height; y++ {
+		err = readHDRScanline(r, scanline)
+		if err != nil {
+			return nil, err
+		}
+		for x := 0; x < img.Width; x++ {
+			rgbe := scanline[x*4 : x*4+4]
+			if rgbe[3] == 0 {
+				img.Pix = append(img.Pix, 0, 0, 0)
+				continue
+			}
+			f := float32(math.Ldexp(1, int(rgbe[3])-(128+8)))
+			img.Pix = append(img.Pix, float32(rgbe[0])*f, float32(rgbe[1])*f, float32(rgbe[2])*f)
+		}
+	}
+	return img, nil
+}
+
+// readHDRScanline reads one scanline of RGBE pixels into the specified buffer
+func readHDRScanline(r *bufio.Reader, scanline []byte) error {
+
+	width := len(scanline) / 4
+	var head [4]byte
+	_, err := io.ReadFull(r, head[:])
+	if err != nil {
+		return err
+	}
+
+	// Flat or old run length encoded scanline
+	if width < 8 || width > 0x7fff || head[0] != 2 || head[1] != 2 || head[2]&0x80 != 0 {
+		shift := uint(0)
+		for x := 0; x < width; {
+			if x > 0 || shift > 0 {
+				_, err = io.ReadFull(r, head[:])
+				if err != nil {
+					return err
+				}
+			}
+			// Repeats the previous pixel
+			if head[0] == 1 && head[1] == 1 && head[2] == 1 {
+				if x == 0 {
+					return fmt.Errorf("invalid HDR run length")
+				}
+				count := int(head[3]) << shift
+				for ; count > 0 && x < width; count-- {
+					copy(scanline[x*4:x*4+4], scanline[x*4-4:x*4])
+					x++
+				}
+				shift += 8
+				continue
+			}
+			copy(scanline[x*4:x*4+4], head[:])
+			x++
+			shift = 0
+		}
+		return nil
+	}
+
+	// New run length encoded scanline with each component encoded separately
+	if int(head[2])<<8|int(head[3]) != width {
+		return fmt.Errorf("invalid HDR scanline width")
+	}
+	for c := 0; c < 4; c++ {
+		for x := 0; x < width; {
+			count, err := r.ReadByte()
+			if err != nil {
+				return err
+			}
+			if count > 128 {
+				// Run of the same value
+				n := int(count) - 128
+				if x+n > width {
+					return fmt.Errorf("invalid HDR run length")
+				}
+				v, err := r.ReadByte()
+				if err != nil {
+					return err
+				}
+				for ; n > 0; n-- {
+					scanline[x*4+c] = v
+					x++
+				}
+			} else {
+				// Sequence of different values
+				n := int(count)
+				if n == 0 || x+n > width {
+					return fmt.Errorf("invalid HDR run length")
+				}
+				for ; n > 0; n-- {
+					v, err := r.ReadByte()
+					if err != nil {
+						return err
+					}
+					scanline[x*4+c] = v
+					x++
+				}
+			}
+		}
+	}
+	return nil
+}
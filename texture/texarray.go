@@ -0,0 +1,300 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package texture
+
+import (
+	"fmt"
+	"github.com/g3n/engine/gls"
+	"image"
+)
+
+// TexArray is a texture with several layers of images with the same size
+// backed by an OpenGL TEXTURE_2D_ARRAY, so all the layers use a single
+// texture unit. The texture arrays of a material are available in the shaders
+// as the MatTexArray[] uniform and a layer can be sampled by its index with
+// the MatTexArrayTexel(a, uv, layer) macro of the "material" shader chunk.
+type TexArray struct {
+	gs           *gls.GLS            // Pointer to OpenGL state
+	refcount     int                 // Current number of references
+	texname      uint32              // Texture handle
+	magFilter    uint32              // magnification filter
+	minFilter    uint32              // minification filter
+	wrapS        uint32              // wrap mode for s coordinate
+	wrapT        uint32              // wrap mode for t coordinate
+	width        int32               // texture width in pixels
+	height       int32               // texture height in pixels
+	layers       [][]byte            // RGBA8 pixel data of each layer
+	updateData   bool                // texture data needs to be sent
+	updateParams bool                // texture parameters needs to be sent
+	genMipmap    bool                // generate mipmaps flag
+	uTexture     gls.Uniform1i       // Texture unit uniform
+	uTexinfo     gls.UniformMatrix3f // uniform 3x3 array with texture info
+}
+
+// NewTexArray creates and returns a pointer to a new empty texture array.
+// The minification filter is gls.LINEAR_MIPMAP_LINEAR to use the mipmaps
+// generated for all the layers.
+func NewTexArray() *TexArray {
+
+	t := new(TexArray)
+	t.refcount = 1
+	t.magFilter = gls.LINEAR
+	t.minFilter = gls.LINEAR_MIPMAP_LINEAR
+	t.wrapS = gls.REPEAT
+	t.wrapT = gls.REPEAT
+	t.updateParams = true
+	t.genMipmap = true
+
+	// Initialize Uniform elements
+	t.uTexture.Init("MatTexArray")
+	t.uTexinfo.Init("MatTexArrayInfo")
+	t.uTexinfo.Set(iOffsetX, 0)
+	t.uTexinfo.Set(iOffsetY, 0)
+	t.uTexinfo.Set(iRepeatX, 1)
+	t.uTexinfo.Set(iRepeatY, 1)
+	t.uTexinfo.Set(iFlipY, 1)
+	t.uTexinfo.Set(iVisible, 1)
+
+	return t
+}
+
+// NewTexArrayFromImages creates and returns a pointer to a new texture array
+// with one layer for each of the specified image files, in the same order.
+// All the images must have the same size.
+// Supported image formats are: PNG, JPEG and GIF.
+func NewTexArrayFromImages(imgfiles []string) (*TexArray, error) {
+
+	t := NewTexArray()
+	for _, imgfile := range imgfiles {
+		rgba, err := DecodeImage(imgfile)
+		if err != nil {
+			return nil, err
+		}
+		err = t.AddLayer(rgba)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", imgfile, err)
+		}
+	}
+	return t, nil
+}
+
+// Incref increments the reference count for this texture
+// and returns a pointer to the texture.
+// It should be used when this texture is shared by another
+// material.
+func (t *TexArray) Incref() *TexArray {
+
+	t.refcount++
+	return t
+}
+
+// Dispose decrements this texture reference count and
+// if necessary releases OpenGL resources and C memory
+// associated with this texture.
+func (t *TexArray) Dispose() {
+
+	if t.refcount > 1 {
+		t.refcount--
+		return
+	}
+	if t.gs != nil {
+		t.gs.DeleteTextures(t.texname)
+		t.gs = nil
+	}
+}
+
+// AddLayer appends a layer with the specified image to this texture array.
+// The image must have the same size as the previous layers.
+func (t *TexArray) AddLayer(rgba *image.RGBA) error {
+
+	size := rgba.Rect.Size()
+	if len(t.layers) > 0 && (int32(size.X) != t.width || int32(size.Y) != t.height) {
+		return fmt.Errorf("layer size %dx%d different from texture array size %dx%d", size.X, size.Y, t.width, t.height)
+	}
+	if rgba.Stride != size.X*4 {
+		return fmt.Errorf("unsupported stride")
+	}
+	t.width = int32(size.X)
+	t.height = int32(size.Y)
+	t.layers = append(t.layers, rgba.Pix)
+	t.updateData = true
+	return nil
+}
+
+// SetLayer sets the image of the layer with the specified index.
+// The image must have the same size as the other layers.
+func (t *TexArray) SetLayer(idx int, rgba *image.RGBA) error {
+
+	if idx < 0 || idx >= len(t.layers) {
+		return fmt.Errorf("invalid layer index:%d", idx)
+	}
+	size := rgba.Rect.Size()
+	if int32(size.X) != t.width || int32(size.Y) != t.height {
+		return fmt.Errorf("layer size %dx%d different from texture array size %dx%d", size.X, size.Y, t.width, t.height)
+	}
+	if rgba.Stride != size.X*4 {
+		return fmt.Errorf("unsupported stride")
+	}
+	t.layers[idx] = rgba.Pix
+	t.updateData = true
+	return nil
+}
+
+// LayerCount returns the current number of layers of this texture array
+func (t *TexArray) LayerCount() int {
+
+	return len(t.layers)
+}
+
+// SetVisible sets the visibility state of the texture
+func (t *TexArray) SetVisible(state bool) {
+
+	if state {
+		t.uTexinfo.Set(iVisible, 1)
+	} else {
+		t.uTexinfo.Set(iVisible, 0)
+	}
+}
+
+// Visible returns the current visibility state of the texture
+func (t *TexArray) Visible() bool {
+
+	return t.uTexinfo.Get(iVisible) != 0
+}
+
+// SetMagFilter sets the filter to be applied when the texture element
+// covers more than on pixel. The default value is gls.Linear.
+func (t *TexArray) SetMagFilter(magFilter uint32) {
+
+	t.magFilter = magFilter
+	t.updateParams = true
+}
+
+// SetMinFilter sets the filter to be applied when the texture element
+// covers less than on pixel. The default value is gls.LINEAR_MIPMAP_LINEAR.
+func (t *TexArray) SetMinFilter(minFilter uint32) {
+
+	t.minFilter = minFilter
+	t.updateParams = true
+}
+
+// SetWrapS set the wrapping mode for texture S coordinate
+// The default value is gls.REPEAT
+func (t *TexArray) SetWrapS(wrapS uint32) {
+
+	t.wrapS = wrapS
+	t.updateParams = true
+}
+
+// SetWrapT set the wrapping mode for texture T coordinate
+// The default value is gls.REPEAT
+func (t *TexArray) SetWrapT(wrapT uint32) {
+
+	t.wrapT = wrapT
+	t.updateParams = true
+}
+
+// SetRepeat set the repeat factor
+func (t *TexArray) SetRepeat(x, y float32) {
+
+	t.uTexinfo.Set(iRepeatX, x)
+	t.uTexinfo.Set(iRepeatY, y)
+}
+
+// Repeat returns the current X and Y repeat factors
+func (t *TexArray) Repeat() (float32, float32) {
+
+	return t.uTexinfo.Get(iRepeatX), t.uTexinfo.Get(iRepeatY)
+}
+
+// SetOffset sets the offset factor
+func (t *TexArray) SetOffset(x, y float32) {
+
+	t.uTexinfo.Set(iOffsetX, x)
+	t.uTexinfo.Set(iOffsetY, y)
+}
+
+// Offset returns the current X and Y offset factors
+func (t *TexArray) Offset() (float32, float32) {
+
+	return t.uTexinfo.Get(iOffsetX), t.uTexinfo.Get(iOffsetY)
+}
+
+// SetFlipY set the state for flipping the Y coordinate
+func (t *TexArray) SetFlipY(state bool) {
+
+	if state {
+		t.uTexinfo.Set(iFlipY, 1)
+	} else {
+		t.uTexinfo.Set(iFlipY, 0)
+	}
+}
+
+// Width returns the texture width in pixels
+func (t *TexArray) Width() int {
+
+	return int(t.width)
+}
+
+// Height returns the texture height in pixels
+func (t *TexArray) Height() int {
+
+	return int(t.height)
+}
+
+// RenderSetup is called by the material render setup to bind this texture
+// array to the specified texture unit and to set the uniforms with the
+// specified index in the texture arrays uniforms.
+func (t *TexArray) RenderSetup(gs *gls.GLS, unit, idx int) {
+
+	// One time initialization
+	if t.gs == nil {
+		t.texname = gs.GenTexture()
+		t.gs = gs
+	}
+
+	// Sets the texture unit for this texture
+	gs.ActiveTexture(uint32(gls.TEXTURE0 + unit))
+	gs.BindTexture(gls.TEXTURE_2D_ARRAY, t.texname)
+
+	// Transfer texture data of all the layers to OpenGL if necessary
+	if t.updateData {
+		gs.TexImage3D(
+			gls.TEXTURE_2D_ARRAY, // texture type
+			0,                    // level of detail
+			gls.RGBA8,            // internal format
+			t.width,              // width in texels
+			t.height,             // height in texels
+			int32(len(t.layers)), // number of layers
+			0,                    // border must be 0
+			gls.RGBA,             // format of supplied texture data
+			gls.UNSIGNED_BYTE,    // type of external format color component
+			nil,                  // the layers are set below
+		)
+		for i, layer := range t.layers {
+			gs.TexSubImage3D(gls.TEXTURE_2D_ARRAY, 0, 0, 0, int32(i), t.width, t.height, 1, gls.RGBA, gls.UNSIGNED_BYTE, layer)
+		}
+		// Generates mipmaps of all the layers if requested
+		if t.genMipmap && len(t.layers) > 0 {
+			gs.GenerateMipmap(gls.TEXTURE_2D_ARRAY)
+		}
+		// No data to send
+		t.updateData = false
+	}
+
+	// Sets texture parameters if needed
+	if t.updateParams {
+		gs.TexParameteri(gls.TEXTURE_2D_ARRAY, gls.TEXTURE_MAG_FILTER, int32(t.magFilter))
+		gs.TexParameteri(gls.TEXTURE_2D_ARRAY, gls.TEXTURE_MIN_FILTER, int32(t.minFilter))
+		gs.TexParameteri(gls.TEXTURE_2D_ARRAY, gls.TEXTURE_WRAP_S, int32(t.wrapS))
+		gs.TexParameteri(gls.TEXTURE_2D_ARRAY, gls.TEXTURE_WRAP_T, int32(t.wrapT))
+		t.updateParams = false
+	}
+
+	// Transfer uniforms
+	t.uTexture.Set(int32(unit))
+	t.uTexture.TransferIdx(gs, idx)
+	t.uTexinfo.TransferIdx(gs, idx)
+}
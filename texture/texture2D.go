@@ -7,6 +7,7 @@ package texture
 import (
 	"fmt"
 	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
 	"image"
 	"image/draw"
 	_ "image/gif"
@@ -23,6 +24,7 @@ type Texture2D struct {
 	minFilter    uint32              // minification filter
 	wrapS        uint32              // wrap mode for s coordinate
 	wrapT        uint32              // wrap mode for t coordinate
+	anisotropy   float32             // maximum anisotropy of the filter
 	iformat      int32               // internal format
 	width        int32               // texture width in pixels
 	height       int32               // texture height in pixels
@@ -32,17 +34,27 @@ type Texture2D struct {
 	updateParams bool                // texture parameters needs to be sent
 	genMipmap    bool                // generate mipmaps flag
 	data         interface{}         // array with texture data
+	allocated    bool                // texture storage allocated with the current size and formats
+	subImages    []subImage          // pending updates of regions of the texture
 	uTexture     gls.Uniform1i       // Texture unit uniform
 	uTexinfo     gls.UniformMatrix3f // uniform 3x3 array with texture info
 }
 
+// subImage is an update of a region of the texture
+type subImage struct {
+	x, y          int32
+	width, height int32
+	pix           []byte
+}
+
 const (
-	iOffsetX = 0
-	iOffsetY = 1
-	iRepeatX = 3
-	iRepeatY = 4
-	iFlipY   = 6
-	iVisible = 7
+	iOffsetX   = 0
+	iOffsetY   = 1
+	iRepeatX   = 3
+	iRepeatY   = 4
+	iFlipY     = 6
+	iVisible   = 7
+	iUVChannel = 8
 )
 
 func newTexture2D() *Texture2D {
@@ -55,6 +67,7 @@ func newTexture2D() *Texture2D {
 	t.minFilter = gls.LINEAR
 	t.wrapS = gls.CLAMP_TO_EDGE
 	t.wrapT = gls.CLAMP_TO_EDGE
+	t.anisotropy = 1
 	t.updateData = false
 	t.updateParams = true
 	t.genMipmap = true
@@ -68,6 +81,7 @@ func newTexture2D() *Texture2D {
 	t.uTexinfo.Set(iRepeatY, 1)
 	t.uTexinfo.Set(iFlipY, 1)
 	t.uTexinfo.Set(iVisible, 1)
+	t.uTexinfo.Set(iUVChannel, 0)
 
 	return t
 }
@@ -126,6 +140,7 @@ func (t *Texture2D) Dispose() {
 	if t.gs != nil {
 		t.gs.DeleteTextures(t.texname)
 		t.gs = nil
+		t.allocated = false
 	}
 }
 
@@ -141,7 +156,10 @@ func (t *Texture2D) SetImage(imgfile string) error {
 	return nil
 }
 
-// SetFromRGBA sets the texture data from the speficied image.RGBA object
+// SetFromRGBA sets the texture data from the speficied image.RGBA object.
+// If the image has the same size as the current texture data, the texture
+// storage already allocated in the GPU is reused, so it can be called every
+// frame to stream new images to the texture.
 func (t *Texture2D) SetFromRGBA(rgba *image.RGBA) {
 
 	t.SetData(
@@ -150,13 +168,16 @@ func (t *Texture2D) SetFromRGBA(rgba *image.RGBA) {
 		gls.RGBA,
 		gls.UNSIGNED_BYTE,
 		gls.RGBA8,
-		rgba.Pix,
+		rgbaPix(rgba),
 	)
 }
 
 // SetData sets the texture data
 func (t *Texture2D) SetData(width, height int, format int, formatType, iformat int, data interface{}) {
 
+	if int32(width) != t.width || int32(height) != t.height || int32(iformat) != t.iformat {
+		t.allocated = false
+	}
 	t.width = int32(width)
 	t.height = int32(height)
 	t.format = uint32(format)
@@ -164,6 +185,56 @@ func (t *Texture2D) SetData(width, height int, format int, formatType, iformat i
 	t.iformat = int32(iformat)
 	t.data = data
 	t.updateData = true
+	t.subImages = t.subImages[:0]
+}
+
+// UpdateSubImage updates the region of the texture with the top left corner
+// at the specified pixel with the specified image, without reallocating the
+// texture in the GPU. The texture must have RGBA data, set from an image
+// file or image.RGBA object, and the region must be inside the texture.
+func (t *Texture2D) UpdateSubImage(x, y int, rgba *image.RGBA) error {
+
+	if t.format != gls.RGBA || t.formatType != gls.UNSIGNED_BYTE {
+		return fmt.Errorf("texture data is not RGBA")
+	}
+	size := rgba.Rect.Size()
+	if x < 0 || y < 0 || x+size.X > int(t.width) || y+size.Y > int(t.height) {
+		return fmt.Errorf("region %dx%d at (%d,%d) outside of texture with size %dx%d",
+			size.X, size.Y, x, y, t.width, t.height)
+	}
+	if size.X == 0 || size.Y == 0 {
+		return nil
+	}
+	pix := rgbaPix(rgba)
+
+	// Updates the texture data so it is consistent if it is sent again
+	if data, ok := t.data.([]byte); ok && len(data) >= int(t.width*t.height)*4 {
+		for row := 0; row < size.Y; row++ {
+			start := ((y+row)*int(t.width) + x) * 4
+			copy(data[start:start+size.X*4], pix[row*size.X*4:(row+1)*size.X*4])
+		}
+	}
+
+	// The whole texture will be sent anyway
+	if t.updateData {
+		return nil
+	}
+	t.subImages = append(t.subImages, subImage{int32(x), int32(y), int32(size.X), int32(size.Y), pix})
+	return nil
+}
+
+// rgbaPix returns the pixels of the specified image without padding between rows
+func rgbaPix(rgba *image.RGBA) []byte {
+
+	size := rgba.Rect.Size()
+	if rgba.Stride == size.X*4 {
+		return rgba.Pix[:size.X*size.Y*4]
+	}
+	pix := make([]byte, size.X*size.Y*4)
+	for row := 0; row < size.Y; row++ {
+		copy(pix[row*size.X*4:(row+1)*size.X*4], rgba.Pix[row*rgba.Stride:])
+	}
+	return pix
 }
 
 // SetVisible sets the visibility state of the texture
@@ -218,6 +289,31 @@ func (t *Texture2D) SetWrapT(wrapT uint32) {
 	t.updateParams = true
 }
 
+// SetWrap sets the wrapping modes for the texture S and T coordinates
+func (t *Texture2D) SetWrap(wrapS, wrapT uint32) {
+
+	t.wrapS = wrapS
+	t.wrapT = wrapT
+	t.updateParams = true
+}
+
+// SetAnisotropy sets the maximum anisotropy of the texture filter, which
+// improves the sharpness of textures seen at grazing angles when mipmaps are used.
+// The level is clamped to the maximum supported by the OpenGL context when the
+// texture is rendered and is ignored if anisotropic filtering is not supported.
+// The default value is 1, which disables anisotropic filtering.
+func (t *Texture2D) SetAnisotropy(level float32) {
+
+	t.anisotropy = math32.Max(level, 1)
+	t.updateParams = true
+}
+
+// Anisotropy returns the current maximum anisotropy of the texture filter
+func (t *Texture2D) Anisotropy() float32 {
+
+	return t.anisotropy
+}
+
 // SetRepeat set the repeat factor
 func (t *Texture2D) SetRepeat(x, y float32) {
 
@@ -254,6 +350,32 @@ func (t *Texture2D) SetFlipY(state bool) {
 	}
 }
 
+// SetUVChannel sets the set of texture coordinates used to sample this texture:
+// 0 for the geometry "VertexTexcoord" attribute (the default) or 1 for the
+// secondary "VertexTexcoord2" attribute, as used by lightmaps. The glTF and
+// Collada decoders set this attribute from the second texture coordinates of
+// their meshes. Wavefront OBJ files have a single set of texture coordinates,
+// so the attribute must be added to their geometries. For example:
+//
+//	mat := material.NewStandard(math32.NewColor(1, 1, 1))
+//	mat.AddTexture(baseTex)
+//	lightmap.SetUVChannel(1)
+//	mat.AddTexture(lightmap)
+func (t *Texture2D) SetUVChannel(channel int) {
+
+	if channel == 1 {
+		t.uTexinfo.Set(iUVChannel, 1)
+	} else {
+		t.uTexinfo.Set(iUVChannel, 0)
+	}
+}
+
+// UVChannel returns the set of texture coordinates used to sample this texture
+func (t *Texture2D) UVChannel() int {
+
+	return int(t.uTexinfo.Get(iUVChannel))
+}
+
 // Width returns the texture width in pixels
 func (t *Texture2D) Width() int {
 
@@ -292,9 +414,31 @@ func DecodeImage(imgfile string) (*image.RGBA, error) {
 	return rgba, nil
 }
 
+// TexName returns the OpenGL handle of this texture, which is
+// created when the texture is first bound, or 0 before.
+// It is used to attach the texture to framebuffers.
+func (t *Texture2D) TexName() uint32 {
+
+	return t.texname
+}
+
 // Called by material render setup
 func (t *Texture2D) RenderSetup(gs *gls.GLS, idx int) {
 
+	t.Bind(gs, idx)
+
+	// Transfer uniforms
+	t.uTexture.Set(int32(idx))
+	t.uTexture.TransferIdx(gs, idx)
+	t.uTexinfo.TransferIdx(gs, idx)
+}
+
+// Bind transfers the texture data and parameters to OpenGL if necessary and
+// binds this texture to the specified texture unit without setting the material
+// texture uniforms. It is used to bind textures sampled by other uniforms, such
+// as the cookie textures of spot lights.
+func (t *Texture2D) Bind(gs *gls.GLS, unit int) {
+
 	// One time initialization
 	if t.gs == nil {
 		t.texname = gs.GenTexture()
@@ -304,19 +448,25 @@ func (t *Texture2D) RenderSetup(gs *gls.GLS, idx int) {
 	// Transfer texture data to OpenGL if necessary
 	if t.updateData {
 		// Sets the texture unit for this texture
-		gs.ActiveTexture(uint32(gls.TEXTURE0 + idx))
+		gs.ActiveTexture(uint32(gls.TEXTURE0 + unit))
 		gs.BindTexture(gls.TEXTURE_2D, t.texname)
-		gs.TexImage2D(
-			gls.TEXTURE_2D, // texture type
-			0,              // level of detail
-			t.iformat,      // internal format
-			t.width,        // width in texels
-			t.height,       // height in texels
-			0,              // border must be 0
-			t.format,       // format of supplied texture data
-			t.formatType,   // type of external format color component
-			t.data,         // image data
-		)
+		// Reuses the texture storage if it has the same size and format
+		if t.allocated {
+			gs.TexSubImage2D(gls.TEXTURE_2D, 0, 0, 0, t.width, t.height, t.format, t.formatType, t.data)
+		} else {
+			gs.TexImage2D(
+				gls.TEXTURE_2D, // texture type
+				0,              // level of detail
+				t.iformat,      // internal format
+				t.width,        // width in texels
+				t.height,       // height in texels
+				0,              // border must be 0
+				t.format,       // format of supplied texture data
+				t.formatType,   // type of external format color component
+				t.data,         // image data
+			)
+			t.allocated = true
+		}
 		// Generates mipmaps if requested
 		if t.genMipmap {
 			gs.GenerateMipmap(gls.TEXTURE_2D)
@@ -325,8 +475,21 @@ func (t *Texture2D) RenderSetup(gs *gls.GLS, idx int) {
 		t.updateData = false
 	}
 
+	// Transfer the updated regions of the texture
+	if len(t.subImages) > 0 {
+		gs.ActiveTexture(uint32(gls.TEXTURE0 + unit))
+		gs.BindTexture(gls.TEXTURE_2D, t.texname)
+		for _, sub := range t.subImages {
+			gs.TexSubImage2D(gls.TEXTURE_2D, 0, sub.x, sub.y, sub.width, sub.height, gls.RGBA, gls.UNSIGNED_BYTE, sub.pix)
+		}
+		if t.genMipmap {
+			gs.GenerateMipmap(gls.TEXTURE_2D)
+		}
+		t.subImages = t.subImages[:0]
+	}
+
 	// Sets the texture unit for this texture
-	gs.ActiveTexture(uint32(gls.TEXTURE0 + idx))
+	gs.ActiveTexture(uint32(gls.TEXTURE0 + unit))
 	gs.BindTexture(gls.TEXTURE_2D, t.texname)
 
 	// Sets texture parameters if needed
@@ -335,11 +498,9 @@ func (t *Texture2D) RenderSetup(gs *gls.GLS, idx int) {
 		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_MIN_FILTER, int32(t.minFilter))
 		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_WRAP_S, int32(t.wrapS))
 		gs.TexParameteri(gls.TEXTURE_2D, gls.TEXTURE_WRAP_T, int32(t.wrapT))
+		if max := gs.MaxTextureAnisotropy(); max > 1 {
+			gs.TexParameterf(gls.TEXTURE_2D, gls.TEXTURE_MAX_ANISOTROPY, math32.Min(t.anisotropy, max))
+		}
 		t.updateParams = false
 	}
-
-	// Transfer uniforms
-	t.uTexture.Set(int32(idx))
-	t.uTexture.TransferIdx(gs, idx)
-	t.uTexinfo.TransferIdx(gs, idx)
 }
@@ -0,0 +1,186 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package texture
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/g3n/engine/gls"
+)
+
+// filledRGBA returns an image with the specified size filled with the specified color
+func filledRGBA(width, height int, c color.RGBA) *image.RGBA {
+
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			rgba.SetRGBA(x, y, c)
+		}
+	}
+	return rgba
+}
+
+// uploaded simulates the transfer of the texture data by the renderer
+func uploaded(t *Texture2D) {
+
+	t.updateData = false
+	t.allocated = true
+	t.subImages = t.subImages[:0]
+}
+
+func TestTexture2DSetFromRGBA(t *testing.T) {
+
+	tex := NewTexture2DFromRGBA(filledRGBA(4, 4, color.RGBA{255, 0, 0, 255}))
+	uploaded(tex)
+
+	// Same size keeps the allocated storage
+	tex.SetFromRGBA(filledRGBA(4, 4, color.RGBA{0, 255, 0, 255}))
+	if !tex.updateData || !tex.allocated {
+		t.Errorf("same size: updateData = %v, allocated = %v, want true, true", tex.updateData, tex.allocated)
+	}
+	uploaded(tex)
+
+	// Different size reallocates the storage
+	tex.SetFromRGBA(filledRGBA(8, 4, color.RGBA{0, 0, 255, 255}))
+	if !tex.updateData || tex.allocated {
+		t.Errorf("new size: updateData = %v, allocated = %v, want true, false", tex.updateData, tex.allocated)
+	}
+	if w, h := tex.Width(), tex.Height(); w != 8 || h != 4 {
+		t.Errorf("new size: size = %dx%d, want 8x4", w, h)
+	}
+}
+
+func TestTexture2DUpdateSubImage(t *testing.T) {
+
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	cases := []struct {
+		name    string
+		x, y    int
+		sub     *image.RGBA
+		wantErr bool
+	}{
+		{"whole", 0, 0, filledRGBA(4, 4, blue), false},
+		{"corner", 2, 2, filledRGBA(2, 2, blue), false},
+		{"padded stride", 1, 0, filledRGBA(4, 4, blue).SubImage(image.Rect(0, 0, 2, 3)).(*image.RGBA), false},
+		{"empty", 1, 1, filledRGBA(0, 0, blue), false},
+		{"too wide", 3, 0, filledRGBA(2, 1, blue), true},
+		{"too high", 0, 0, filledRGBA(1, 5, blue), true},
+		{"negative", -1, 0, filledRGBA(1, 1, blue), true},
+	}
+	for _, c := range cases {
+		tex := NewTexture2DFromRGBA(filledRGBA(4, 4, red))
+		uploaded(tex)
+		err := tex.UpdateSubImage(c.x, c.y, c.sub)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: error = %v, want error %v", c.name, err, c.wantErr)
+			continue
+		}
+		if c.wantErr {
+			if len(tex.subImages) != 0 {
+				t.Errorf("%s: %d pending updates after error, want 0", c.name, len(tex.subImages))
+			}
+			continue
+		}
+		size := c.sub.Rect.Size()
+		if tex.updateData {
+			t.Errorf("%s: updateData = true, want false", c.name)
+		}
+		wantSubs := 1
+		if size.X == 0 {
+			wantSubs = 0
+		}
+		if len(tex.subImages) != wantSubs {
+			t.Errorf("%s: %d pending updates, want %d", c.name, len(tex.subImages), wantSubs)
+		}
+
+		// The texture data must have the region replaced
+		data := tex.data.([]byte)
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				want := red
+				if x >= c.x && x < c.x+size.X && y >= c.y && y < c.y+size.Y {
+					want = blue
+				}
+				pix := data[(y*4+x)*4 : (y*4+x)*4+4]
+				if !bytes.Equal(pix, []byte{want.R, want.G, want.B, want.A}) {
+					t.Errorf("%s: pixel (%d,%d) = %v, want %v", c.name, x, y, pix, want)
+				}
+			}
+		}
+	}
+}
+
+func TestTexture2DUpdateSubImageNotRGBA(t *testing.T) {
+
+	tex := NewTexture2DFromData(4, 4, gls.RGB, gls.FLOAT, gls.RGB16F, make([]float32, 4*4*3))
+	err := tex.UpdateSubImage(0, 0, filledRGBA(1, 1, color.RGBA{}))
+	if err == nil {
+		t.Errorf("float texture: error = nil, want error")
+	}
+}
+
+func TestTexture2DUpdateSubImagePending(t *testing.T) {
+
+	// The whole texture is sent if the data was not transferred yet
+	tex := NewTexture2DFromRGBA(filledRGBA(4, 4, color.RGBA{}))
+	err := tex.UpdateSubImage(0, 0, filledRGBA(2, 2, color.RGBA{255, 255, 255, 255}))
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if !tex.updateData || len(tex.subImages) != 0 {
+		t.Errorf("updateData = %v, pending updates = %d, want true, 0", tex.updateData, len(tex.subImages))
+	}
+}
+
+// benchmarkFrames updates a 1280x720 texture once per frame with the specified function
+func benchmarkFrames(b *testing.B, update func(tex *Texture2D, frame *image.RGBA)) {
+
+	frames := []*image.RGBA{
+		filledRGBA(1280, 720, color.RGBA{255, 0, 0, 255}),
+		filledRGBA(1280, 720, color.RGBA{0, 0, 255, 255}),
+	}
+	tex := NewTexture2DFromRGBA(frames[0])
+	uploaded(tex)
+	b.SetBytes(int64(len(frames[0].Pix)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		update(tex, frames[i%2])
+		uploaded(tex)
+	}
+}
+
+func BenchmarkTexture2DSetFromRGBA(b *testing.B) {
+
+	benchmarkFrames(b, func(tex *Texture2D, frame *image.RGBA) {
+		tex.SetFromRGBA(frame)
+	})
+}
+
+func BenchmarkTexture2DUpdateSubImage(b *testing.B) {
+
+	benchmarkFrames(b, func(tex *Texture2D, frame *image.RGBA) {
+		err := tex.UpdateSubImage(0, 0, frame)
+		if err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+func TestTexture2DSetUVChannel(t *testing.T) {
+
+	// The channel is the third element of the last column of the
+	// MatTexinfo mat3 uniform read by the MatTexUVChannel shader macro
+	tex := NewTexture2DFromRGBA(filledRGBA(4, 4, color.RGBA{}))
+	for _, c := range []struct{ set, want int }{{0, 0}, {1, 1}, {2, 0}, {-1, 0}} {
+		tex.SetUVChannel(c.set)
+		if tex.UVChannel() != c.want || int(tex.uTexinfo.Get(2*3+2)) != c.want {
+			t.Errorf("SetUVChannel(%d): channel %d uniform %v, want %d", c.set, tex.UVChannel(), tex.uTexinfo.Get(2*3+2), c.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/g3n/engine/window"
+)
+
+// This example prints the models dropped onto the window, which may
+// be several files at once with paths containing any unicode characters.
+func ExampleDropEvent() {
+
+	win, err := window.New("glfw", 800, 600, "Drop models here", false)
+	if err != nil {
+		panic(err)
+	}
+	win.Subscribe(window.OnFileDrop, func(evname string, ev interface{}) {
+		dev := ev.(*window.DropEvent)
+		for _, path := range dev.Paths {
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".obj", ".dae", ".gltf", ".glb":
+				fmt.Printf("load %s dropped at %v,%v\n", path, dev.Xpos, dev.Ypos)
+			default:
+				fmt.Printf("ignored %s\n", path)
+			}
+		}
+	})
+	for !win.ShouldClose() {
+		win.PollEvents()
+		win.SwapBuffers()
+	}
+}
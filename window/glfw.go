@@ -5,7 +5,9 @@
 package window
 
 import (
+	"errors"
 	"runtime"
+	"unicode/utf8"
 
 	"github.com/g3n/engine/core"
 	"github.com/go-gl/glfw/v3.2/glfw"
@@ -21,6 +23,9 @@ type GLFW struct {
 	sizeEv          SizeEvent
 	cursorEv        CursorEvent
 	scrollEv        ScrollEvent
+	dropEv          DropEvent
+	joystickEv      JoystickEvent
+	gamepadMaps     map[Joystick]*GamepadMapping
 	arrowCursor     *glfw.Cursor
 	ibeamCursor     *glfw.Cursor
 	crosshairCursor *glfw.Cursor
@@ -28,16 +33,30 @@ type GLFW struct {
 	hresizeCursor   *glfw.Cursor
 	vresizeCursor   *glfw.Cursor
 	fullScreen      bool
+	cursorMode      int
+	cursorReset     bool
+	cursorX         float64
+	cursorY         float64
+	savedCursorX    float64
+	savedCursorY    float64
 	lastX           int
 	lastY           int
 	lastWidth       int
 	lastHeight      int
 }
 
+// ErrClipboardNotText is returned by GetClipboardString when
+// the clipboard contents are not valid UTF-8 text
+var ErrClipboardNotText = errors.New("clipboard contents are not UTF-8 text")
+
 // Global GLFW initialization flag
 // is initialized when the first window is created
 var initialized bool = false
 
+// List of the currently open windows in creation order.
+// New windows share the OpenGL buffers, textures and shaders of the first open window.
+var windows []*GLFW
+
 func newGLFW(width, height int, title string, full bool) (*GLFW, error) {
 
 	// Initialize GLFW once before the first window is created
@@ -58,14 +77,24 @@ func newGLFW(width, height int, title string, full bool) (*GLFW, error) {
 		if runtime.GOOS == "darwin" {
 			glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 		}
+		// Set joystick connection callback to dispatch event to all windows.
+		// GLFW supports a single joystick callback for the application.
+		glfw.SetJoystickCallback(onJoystick)
 		initialized = true
 	}
 
+	// Shares the buffers, textures and shaders with the first open window.
+	// Container objects such as vertex arrays are not shared by OpenGL.
+	var share *glfw.Window
+	if len(windows) > 0 {
+		share = windows[0].win
+	}
+
 	// Creates window and sets it as the current context.
 	// The window is created always as not full screen because if it is
 	// created as full screen it not possible to revert it to windowed mode.
 	// At the end of this function, the window will be set to full screen if requested.
-	win, err := glfw.CreateWindow(width, height, title, nil, nil)
+	win, err := glfw.CreateWindow(width, height, title, nil, share)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +103,8 @@ func newGLFW(width, height int, title string, full bool) (*GLFW, error) {
 	// Create wrapper window with dispacher
 	w := new(GLFW)
 	w.win = win
+	w.cursorMode = CursorNormal
+	w.cursorReset = true
 	w.Dispatcher.Initialize()
 
 	// Set key callback to dispatch event
@@ -148,9 +179,20 @@ func newGLFW(width, height int, title string, full bool) (*GLFW, error) {
 	// Set window cursor position event callback to dispatch event
 	win.SetCursorPosCallback(func(x *glfw.Window, xpos float64, ypos float64) {
 
+		// The first event after a cursor mode change has no movement
+		if w.cursorReset {
+			w.cursorX = xpos
+			w.cursorY = ypos
+			w.cursorReset = false
+		}
 		w.cursorEv.W = w
 		w.cursorEv.Xpos = float32(xpos)
 		w.cursorEv.Ypos = float32(ypos)
+		w.cursorEv.Xdelta = float32(xpos - w.cursorX)
+		w.cursorEv.Ydelta = float32(ypos - w.cursorY)
+		w.cursorX = xpos
+		w.cursorY = ypos
+		w.cursorEv.Mods = w.modifiers()
 		w.Dispatch(OnCursor, &w.cursorEv)
 	})
 
@@ -160,9 +202,17 @@ func newGLFW(width, height int, title string, full bool) (*GLFW, error) {
 		w.scrollEv.W = w
 		w.scrollEv.Xoffset = float32(xoff)
 		w.scrollEv.Yoffset = float32(yoff)
+		w.scrollEv.Mods = w.modifiers()
 		w.Dispatch(OnScroll, &w.scrollEv)
 	})
 
+	// Set file drop callback to dispatch event
+	win.SetDropCallback(func(x *glfw.Window, names []string) {
+
+		xpos, ypos := x.GetCursorPos()
+		w.dropFiles(names, xpos, ypos)
+	})
+
 	// Preallocate standard cursors
 	w.arrowCursor = glfw.CreateStandardCursor(glfw.ArrowCursor)
 	w.ibeamCursor = glfw.CreateStandardCursor(glfw.IBeamCursor)
@@ -175,9 +225,29 @@ func newGLFW(width, height int, title string, full bool) (*GLFW, error) {
 	if full {
 		w.SetFullScreen(true)
 	}
+	windows = append(windows, w)
 	return w, nil
 }
 
+// onJoystick is the GLFW joystick callback which dispatches
+// the joystick connection events to all the open windows
+func onJoystick(joy, event int) {
+
+	for _, w := range windows {
+		w.joystickEv.W = w
+		w.joystickEv.Joystick = Joystick(joy)
+		if event == int(glfw.Connected) {
+			w.Dispatch(OnJoystickConnect, &w.joystickEv)
+			continue
+		}
+		if event == int(glfw.Disconnected) {
+			// The slot may be reused by a different device
+			delete(w.gamepadMaps, Joystick(joy))
+			w.Dispatch(OnJoystickDisconnect, &w.joystickEv)
+		}
+	}
+}
+
 // GetScreenResolution returns the resolution of the primary screen in pixels.
 // The parameter is currently ignored
 func (w *GLFW) GetScreenResolution(p interface{}) (width, height int) {
@@ -242,6 +312,35 @@ func (w *GLFW) SetStandardCursor(cursor StandardCursor) {
 	}
 }
 
+// SetCursorMode sets the cursor mode of this window to CursorNormal,
+// CursorHidden (hidden while over the window) or CursorDisabled (hidden and
+// unbounded for relative movement). When switching back from CursorDisabled
+// the cursor is restored to its position before it was disabled.
+// Raw mouse motion is not enabled in CursorDisabled mode as GLFW 3.2,
+// used by this package, does not support it, so the cursor event deltas
+// are the differences between the (unbounded) cursor positions.
+func (w *GLFW) SetCursorMode(mode int) {
+
+	if mode == w.cursorMode {
+		return
+	}
+	if mode == CursorDisabled {
+		w.savedCursorX, w.savedCursorY = w.win.GetCursorPos()
+	}
+	w.win.SetInputMode(glfw.CursorMode, mode)
+	if w.cursorMode == CursorDisabled {
+		w.win.SetCursorPos(w.savedCursorX, w.savedCursorY)
+	}
+	w.cursorMode = mode
+	w.cursorReset = true
+}
+
+// CursorMode returns the current cursor mode of this window
+func (w *GLFW) CursorMode() int {
+
+	return w.cursorMode
+}
+
 // FullScreen returns this window full screen state for the primary monitor
 func (w *GLFW) FullScreen() bool {
 
@@ -249,30 +348,88 @@ func (w *GLFW) FullScreen() bool {
 }
 
 // SetFullScreen sets this window full screen state for the primary monitor
+// using its current video mode
 func (w *GLFW) SetFullScreen(full bool) {
 
 	// If already in the desired state, nothing to do
 	if w.fullScreen == full {
 		return
 	}
-	// Sets this window full screen for the primary monitor
 	if full {
-		// Get primary monitor
-		mon := glfw.GetPrimaryMonitor()
-		vmode := mon.GetVideoMode()
-		width := vmode.Width
-		height := vmode.Height
-		// Saves current position and size of the window
+		w.SetFullScreenMode(nil, nil)
+		return
+	}
+	// Restore window to previous position and size
+	w.win.SetMonitor(nil, w.lastX, w.lastY, w.lastWidth, w.lastHeight, glfw.DontCare)
+	w.fullScreen = false
+	w.dispatchSize()
+}
+
+// SetFullScreenMode sets this window full screen on the specified monitor
+// with the specified video mode. If the monitor is nil the primary monitor
+// is used. If the video mode is nil the current video mode of the monitor is
+// kept, which makes the window borderless full screen without a mode change.
+// The previous window position and size are restored by SetFullScreen(false).
+func (w *GLFW) SetFullScreenMode(mon *Monitor, mode *VideoMode) {
+
+	var gmon *glfw.Monitor
+	if mon != nil {
+		gmon = mon.mon
+	} else {
+		gmon = glfw.GetPrimaryMonitor()
+	}
+	vmode := newVideoMode(gmon.GetVideoMode())
+	if mode != nil {
+		vmode = *mode
+	}
+	// Saves current position and size of the window
+	if !w.fullScreen {
 		w.lastX, w.lastY = w.win.GetPos()
 		w.lastWidth, w.lastHeight = w.win.GetSize()
-		// Sets monitor for full screen
-		w.win.SetMonitor(mon, 0, 0, width, height, vmode.RefreshRate)
-		w.fullScreen = true
+	}
+	w.win.SetMonitor(gmon, 0, 0, vmode.Width, vmode.Height, vmode.RefreshRate)
+	w.fullScreen = true
+	w.dispatchSize()
+}
+
+// GetMonitors returns the currently connected monitors.
+// The first monitor is the primary monitor.
+func (w *GLFW) GetMonitors() []*Monitor {
+
+	gmons := glfw.GetMonitors()
+	mons := make([]*Monitor, len(gmons))
+	for i := 0; i < len(gmons); i++ {
+		mons[i] = &Monitor{gmons[i]}
+	}
+	return mons
+}
+
+// GetVideoModes returns the video modes supported by the specified monitor
+// sorted in ascending order by color depth, resolution and refresh rate.
+// If the monitor is nil the primary monitor is used.
+func (w *GLFW) GetVideoModes(mon *Monitor) []VideoMode {
+
+	var gmon *glfw.Monitor
+	if mon != nil {
+		gmon = mon.mon
 	} else {
-		// Restore window to previous position and size
-		w.win.SetMonitor(nil, w.lastX, w.lastY, w.lastWidth, w.lastHeight, glfw.DontCare)
-		w.fullScreen = false
+		gmon = glfw.GetPrimaryMonitor()
+	}
+	vmodes := gmon.GetVideoModes()
+	modes := make([]VideoMode, len(vmodes))
+	for i := 0; i < len(vmodes); i++ {
+		modes[i] = newVideoMode(vmodes[i])
 	}
+	return modes
+}
+
+// dispatchSize dispatches the current window size after a video mode change
+// because GLFW does not report it if the size in screen coordinates is unchanged
+func (w *GLFW) dispatchSize() {
+
+	w.sizeEv.W = w
+	w.sizeEv.Width, w.sizeEv.Height = w.win.GetSize()
+	w.Dispatch(OnWindowSize, &w.sizeEv)
 }
 
 // ShouldClose returns the current state of this window  should close flag
@@ -292,8 +449,16 @@ func (w *GLFW) SwapBuffers() {
 	w.win.SwapBuffers()
 }
 
+// Destroy destroys this window and its context.
+// The context objects shared with other open windows remain available to them.
 func (w *GLFW) Destroy() {
 
+	for i, ow := range windows {
+		if ow == w {
+			windows = append(windows[:i], windows[i+1:]...)
+			break
+		}
+	}
 	w.win.Destroy()
 	w.win = nil
 }
@@ -307,3 +472,131 @@ func (w *GLFW) GetTime() float64 {
 
 	return glfw.GetTime()
 }
+
+// GetClipboardString returns the text contents of the system clipboard.
+// Returns an empty string without error if the clipboard is empty.
+func (w *GLFW) GetClipboardString() (string, error) {
+
+	s, err := w.win.GetClipboardString()
+	if err != nil {
+		// GLFW reports an empty clipboard or one without text as an error
+		return "", nil
+	}
+	if !utf8.ValidString(s) {
+		return "", ErrClipboardNotText
+	}
+	return s, nil
+}
+
+// SetClipboardString sets the system clipboard to the specified text
+func (w *GLFW) SetClipboardString(s string) {
+
+	w.win.SetClipboardString(s)
+}
+
+// Joysticks returns the list of currently connected joysticks
+func (w *GLFW) Joysticks() []Joystick {
+
+	joys := make([]Joystick, 0)
+	for joy := Joystick1; joy <= JoystickLast; joy++ {
+		if glfw.JoystickPresent(glfw.Joystick(joy)) {
+			joys = append(joys, joy)
+		}
+	}
+	return joys
+}
+
+// JoystickPresent returns if the specified joystick is connected
+func (w *GLFW) JoystickPresent(joy Joystick) bool {
+
+	return glfw.JoystickPresent(glfw.Joystick(joy))
+}
+
+// JoystickName returns the name of the specified joystick
+// or an empty string if it is not connected
+func (w *GLFW) JoystickName(joy Joystick) string {
+
+	return glfw.GetJoystickName(glfw.Joystick(joy))
+}
+
+// JoystickAxes returns the current values of the axes of the specified
+// joystick from -1.0 to 1.0 or nil if it is not connected
+func (w *GLFW) JoystickAxes(joy Joystick) []float32 {
+
+	return glfw.GetJoystickAxes(glfw.Joystick(joy))
+}
+
+// JoystickButtons returns the current states (Press or Release) of the
+// buttons of the specified joystick or nil if it is not connected
+func (w *GLFW) JoystickButtons(joy Joystick) []byte {
+
+	return glfw.GetJoystickButtons(glfw.Joystick(joy))
+}
+
+// SetGamepadMapping sets the mapping used by GamepadState for the specified
+// joystick until it is disconnected. If m is nil the default mapping is used.
+func (w *GLFW) SetGamepadMapping(joy Joystick, m *GamepadMapping) {
+
+	if m == nil {
+		delete(w.gamepadMaps, joy)
+		return
+	}
+	if w.gamepadMaps == nil {
+		w.gamepadMaps = make(map[Joystick]*GamepadMapping)
+	}
+	w.gamepadMaps[joy] = m
+}
+
+// GamepadState sets the specified state with the current buttons and axes
+// of the specified joystick as a gamepad and returns true, or returns
+// false if the joystick is not connected. It should be called every frame
+// after the events are polled.
+func (w *GLFW) GamepadState(joy Joystick, state *GamepadState) bool {
+
+	if !glfw.JoystickPresent(glfw.Joystick(joy)) {
+		*state = GamepadState{}
+		return false
+	}
+	m, ok := w.gamepadMaps[joy]
+	if !ok {
+		m = &DefaultGamepadMapping
+	}
+	m.Apply(glfw.GetJoystickButtons(glfw.Joystick(joy)), glfw.GetJoystickAxes(glfw.Joystick(joy)), state)
+	return true
+}
+
+// dropFiles dispatches the file drop event with a copy of the specified
+// paths, as GLFW only keeps them valid during the callback, and the
+// specified cursor position
+func (w *GLFW) dropFiles(names []string, xpos, ypos float64) {
+
+	w.dropEv.W = w
+	w.dropEv.Paths = make([]string, len(names))
+	copy(w.dropEv.Paths, names)
+	w.dropEv.Xpos = float32(xpos)
+	w.dropEv.Ypos = float32(ypos)
+	w.Dispatch(OnFileDrop, &w.dropEv)
+}
+
+// modifiers returns the state of the modifier keys for events
+// whose callbacks do not receive it from GLFW
+func (w *GLFW) modifiers() ModifierKey {
+
+	var mods ModifierKey
+	pressed := func(k1, k2 glfw.Key) bool {
+		return w.win.GetKey(k1) == glfw.Press || w.win.GetKey(k2) == glfw.Press
+	}
+	if pressed(glfw.KeyLeftShift, glfw.KeyRightShift) {
+		mods |= ModShift
+	}
+	if pressed(glfw.KeyLeftControl, glfw.KeyRightControl) {
+		mods |= ModControl
+	}
+	if pressed(glfw.KeyLeftAlt, glfw.KeyRightAlt) {
+		mods |= ModAlt
+	}
+	if pressed(glfw.KeyLeftSuper, glfw.KeyRightSuper) {
+		mods |= ModSuper
+	}
+	return mods
+}
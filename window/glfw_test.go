@@ -0,0 +1,47 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDropFiles(t *testing.T) {
+
+	cases := [][]string{
+		{"/home/user/models/cube.obj"},
+		{"/home/user/models/cube.obj", "/home/user/models/cube.mtl", "/home/user/textures"},
+		{"/home/usuário/modelos/maçã.glb", "/tmp/模型/城市.dae", "C:\\Users\\Jürgen\\Ω.obj"},
+		{},
+	}
+	for _, names := range cases {
+		w := new(GLFW)
+		w.Initialize()
+		var got *DropEvent
+		w.Subscribe(OnFileDrop, func(evname string, ev interface{}) {
+			got = ev.(*DropEvent)
+		})
+		paths := append([]string(nil), names...)
+		w.dropFiles(paths, 10.5, 20)
+		if got == nil {
+			t.Errorf("%v: OnFileDrop not dispatched", names)
+			continue
+		}
+		if got.W != w || got.Xpos != 10.5 || got.Ypos != 20 {
+			t.Errorf("%v: event window %v position %v, %v", names, got.W, got.Xpos, got.Ypos)
+		}
+		if len(got.Paths) != len(names) || (len(names) > 0 && !reflect.DeepEqual(got.Paths, names)) {
+			t.Errorf("%v: event paths = %q", names, got.Paths)
+		}
+		// The event keeps its own copy of the paths
+		for i := range paths {
+			paths[i] = ""
+		}
+		if len(names) > 0 && !reflect.DeepEqual(got.Paths, names) {
+			t.Errorf("%v: event paths changed with the callback paths to %q", names, got.Paths)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Joystick corresponds to a joystick or gamepad slot.
+type Joystick int
+
+// Joysticks
+const (
+	Joystick1    = Joystick(glfw.Joystick1)
+	Joystick2    = Joystick(glfw.Joystick2)
+	Joystick3    = Joystick(glfw.Joystick3)
+	Joystick4    = Joystick(glfw.Joystick4)
+	Joystick5    = Joystick(glfw.Joystick5)
+	Joystick6    = Joystick(glfw.Joystick6)
+	Joystick7    = Joystick(glfw.Joystick7)
+	Joystick8    = Joystick(glfw.Joystick8)
+	Joystick9    = Joystick(glfw.Joystick9)
+	Joystick10   = Joystick(glfw.Joystick10)
+	Joystick11   = Joystick(glfw.Joystick11)
+	Joystick12   = Joystick(glfw.Joystick12)
+	Joystick13   = Joystick(glfw.Joystick13)
+	Joystick14   = Joystick(glfw.Joystick14)
+	Joystick15   = Joystick(glfw.Joystick15)
+	Joystick16   = Joystick(glfw.Joystick16)
+	JoystickLast = Joystick(glfw.JoystickLast)
+)
+
+// Joystick event names using for dispatch and subscribe
+const (
+	OnJoystickConnect    = "win.OnJoystickConnect"
+	OnJoystickDisconnect = "win.OnJoystickDisconnect"
+)
+
+// JoystickEvent is dispatched when a joystick is connected or disconnected
+type JoystickEvent struct {
+	W        IWindow
+	Joystick Joystick
+}
+
+// GamepadButton corresponds to a button of a standard gamepad.
+type GamepadButton int
+
+// Gamepad buttons
+const (
+	GamepadA GamepadButton = iota
+	GamepadB
+	GamepadX
+	GamepadY
+	GamepadLeftBumper
+	GamepadRightBumper
+	GamepadBack
+	GamepadStart
+	GamepadGuide
+	GamepadLeftThumb
+	GamepadRightThumb
+	GamepadDpadUp
+	GamepadDpadRight
+	GamepadDpadDown
+	GamepadDpadLeft
+	GamepadButtonLast = GamepadDpadLeft
+)
+
+// GamepadAxis corresponds to an axis of a standard gamepad.
+type GamepadAxis int
+
+// Gamepad axes
+const (
+	GamepadAxisLeftX GamepadAxis = iota
+	GamepadAxisLeftY
+	GamepadAxisRightX
+	GamepadAxisRightY
+	GamepadAxisLeftTrigger
+	GamepadAxisRightTrigger
+	GamepadAxisLast = GamepadAxisRightTrigger
+)
+
+// GamepadState is a snapshot of the buttons and axes of a gamepad.
+// Stick axes range from -1.0 to 1.0 and trigger axes from -1.0 (released)
+// to 1.0 (fully pressed), as reported by the device.
+type GamepadState struct {
+	Buttons [GamepadButtonLast + 1]bool
+	Axes    [GamepadAxisLast + 1]float32
+}
+
+// GamepadMapping maps the raw button and axis indices of a joystick
+// to the standard gamepad buttons and axes. An index of -1 indicates
+// that the gamepad button or axis is not available.
+type GamepadMapping struct {
+	Buttons [GamepadButtonLast + 1]int
+	Axes    [GamepadAxisLast + 1]int
+}
+
+// DefaultGamepadMapping is the mapping of the XInput (Xbox) controller
+// layout as reported by GLFW, used by GamepadState for joysticks without
+// a mapping set by SetGamepadMapping.
+var DefaultGamepadMapping = GamepadMapping{
+	Buttons: [GamepadButtonLast + 1]int{
+		GamepadA:           0,
+		GamepadB:           1,
+		GamepadX:           2,
+		GamepadY:           3,
+		GamepadLeftBumper:  4,
+		GamepadRightBumper: 5,
+		GamepadBack:        6,
+		GamepadStart:       7,
+		GamepadGuide:       -1,
+		GamepadLeftThumb:   8,
+		GamepadRightThumb:  9,
+		GamepadDpadUp:      10,
+		GamepadDpadRight:   11,
+		GamepadDpadDown:    12,
+		GamepadDpadLeft:    13,
+	},
+	Axes: [GamepadAxisLast + 1]int{
+		GamepadAxisLeftX:        0,
+		GamepadAxisLeftY:        1,
+		GamepadAxisRightX:       2,
+		GamepadAxisRightY:       3,
+		GamepadAxisLeftTrigger:  4,
+		GamepadAxisRightTrigger: 5,
+	},
+}
+
+// Apply sets the specified gamepad state from the raw buttons and axes
+// of a joystick using this mapping. Unavailable buttons are released
+// and unavailable axes are set to zero.
+func (m *GamepadMapping) Apply(buttons []byte, axes []float32, state *GamepadState) {
+
+	for b, idx := range m.Buttons {
+		state.Buttons[b] = idx >= 0 && idx < len(buttons) && Action(buttons[idx]) == Press
+	}
+	for a, idx := range m.Axes {
+		if idx >= 0 && idx < len(axes) {
+			state.Axes[a] = axes[idx]
+		} else {
+			state.Axes[a] = 0
+		}
+	}
+}
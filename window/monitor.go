@@ -0,0 +1,56 @@
+// Copyright 2016 The G3N Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Monitor represents a monitor connected to the system
+type Monitor struct {
+	mon *glfw.Monitor
+}
+
+// VideoMode describes a video mode of a monitor
+type VideoMode struct {
+	Width       int // width in screen coordinates
+	Height      int // height in screen coordinates
+	RedBits     int // bit depth of the red channel
+	GreenBits   int // bit depth of the green channel
+	BlueBits    int // bit depth of the blue channel
+	RefreshRate int // refresh rate in Hz
+}
+
+// Name returns the human readable name of this monitor
+func (m *Monitor) Name() string {
+
+	return m.mon.GetName()
+}
+
+// Pos returns the position in screen coordinates of the
+// upper left corner of this monitor on the virtual screen
+func (m *Monitor) Pos() (xpos, ypos int) {
+
+	return m.mon.GetPos()
+}
+
+// VideoMode returns the current video mode of this monitor
+func (m *Monitor) VideoMode() VideoMode {
+
+	return newVideoMode(m.mon.GetVideoMode())
+}
+
+// newVideoMode returns the video mode from the specified GLFW video mode
+func newVideoMode(vm *glfw.VidMode) VideoMode {
+
+	return VideoMode{
+		Width:       vm.Width,
+		Height:      vm.Height,
+		RedBits:     vm.RedBits,
+		GreenBits:   vm.GreenBits,
+		BlueBits:    vm.BlueBits,
+		RefreshRate: vm.RefreshRate,
+	}
+}
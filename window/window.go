@@ -27,14 +27,28 @@ type IWindow interface {
 	SetPos(xpos, ypos int)
 	SetTitle(title string)
 	SetStandardCursor(cursor StandardCursor)
+	SetCursorMode(mode int)
+	CursorMode() int
 	SwapBuffers()
 	ShouldClose() bool
 	SetShouldClose(bool)
 	FullScreen() bool
 	SetFullScreen(bool)
+	SetFullScreenMode(mon *Monitor, mode *VideoMode)
+	GetMonitors() []*Monitor
+	GetVideoModes(mon *Monitor) []VideoMode
 	Destroy()
 	PollEvents()
 	GetTime() float64
+	GetClipboardString() (string, error)
+	SetClipboardString(string)
+	Joysticks() []Joystick
+	JoystickPresent(joy Joystick) bool
+	JoystickName(joy Joystick) string
+	JoystickAxes(joy Joystick) []float32
+	JoystickButtons(joy Joystick) []byte
+	SetGamepadMapping(joy Joystick, m *GamepadMapping)
+	GamepadState(joy Joystick, state *GamepadState) bool
 }
 
 // Key corresponds to a keyboard key.
@@ -251,6 +265,7 @@ const (
 	OnMouseUp    = "win.OnMouseUp"
 	OnMouseDown  = "win.OnMouseDown"
 	OnScroll     = "win.OnScroll"
+	OnFileDrop   = "win.OnFileDrop"
 	OnFrame      = "win.OnFrame"
 )
 
@@ -269,12 +284,16 @@ type SizeEvent struct {
 }
 
 // Key pressed in window
+// The event is dispatched as OnKeyDown when the key is pressed, as
+// OnKeyRepeat while the key is held down and as OnKeyUp when it is released.
+// Action is respectively Press, Repeat or Release, so subscribers of several
+// of these events can distinguish held down keys from new key presses.
 type KeyEvent struct {
 	W        IWindow
-	Keycode  Key
-	Scancode int
-	Action   Action
-	Mods     ModifierKey
+	Keycode  Key         // key code
+	Scancode int         // system specific scan code of the key
+	Action   Action      // Press, Repeat or Release
+	Mods     ModifierKey // bitmask of the modifier keys pressed with the key
 }
 
 // Char pressed in window
@@ -291,27 +310,56 @@ type MouseEvent struct {
 	Ypos   float32
 	Button MouseButton
 	Action Action
-	Mods   ModifierKey
+	Mods   ModifierKey // bitmask of the modifier keys pressed with the button
 }
 
 // Cursor position changed
+// When the cursor mode is CursorDisabled the cursor is hidden and its
+// position is not limited by the window, so the deltas can be used for
+// unbounded relative movement such as first person camera controls.
+// The deltas are not raw mouse motion, which GLFW 3.2 does not support.
 type CursorEvent struct {
-	W    IWindow
-	Xpos float32
-	Ypos float32
+	W      IWindow
+	Xpos   float32 // cursor horizontal position
+	Ypos   float32 // cursor vertical position
+	Xdelta float32 // horizontal movement since the previous cursor event
+	Ydelta float32 // vertical movement since the previous cursor event
+	Mods   ModifierKey // bitmask of the modifier keys pressed during the movement
 }
 
 // Scroll event
+// The offsets are the raw deltas reported by the platform for the mouse
+// wheel or the trackpad. A positive Yoffset scrolls up (wheel rotated away
+// from the user) and a positive Xoffset scrolls left. Mice with a single
+// wheel only report Yoffset, even with the Shift key pressed, so horizontal
+// scrolling with Shift+wheel must be detected using Mods.
 type ScrollEvent struct {
 	W       IWindow
-	Xoffset float32
-	Yoffset float32
+	Xoffset float32     // horizontal scroll offset
+	Yoffset float32     // vertical scroll offset
+	Mods    ModifierKey // modifier keys pressed when the event was generated
+}
+
+// File drop event
+// Dispatched when one or more files or directories are dragged from
+// the system and dropped onto the window. The paths are UTF-8 encoded.
+type DropEvent struct {
+	W     IWindow
+	Paths []string // absolute paths of the dropped files in the order reported by the system
+	Xpos  float32  // cursor horizontal position when the files were dropped
+	Ypos  float32  // cursor vertical position when the files were dropped
 }
 
 // New creates and returns a new window of the specified type, width, height and title.
 // If full is true, the window will be opened in full screen and the width and height
 // parameters will be ignored.
 // Currently only "glfw" type is supported.
+// Several windows may be created and each one has its own events and buffers.
+// All windows share the OpenGL buffers, textures and shaders of the first
+// window still open. Vertex array objects are not shared, so each geometry
+// creates one for each window it is rendered in.
+// The context of the new window becomes current, so MakeContextCurrent
+// must be called before rendering to each window.
 func New(wtype string, width, height int, title string, full bool) (IWindow, error) {
 
 	if wtype != "glfw" {